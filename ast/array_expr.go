@@ -1,6 +1,7 @@
 package ast
 
 type ArrayExpr struct {
+	Node
 	Elements []Expr
 }
 
@@ -10,11 +11,11 @@ func NewArrayExpr(elements []Expr) *ArrayExpr {
 	}
 }
 
-func (a *ArrayExpr) Eval() *Value {
+func (a *ArrayExpr) Eval(rt *Runtime) *Value {
 	var values []any
 	
 	for _, element := range a.Elements {
-		values = append(values, element.Eval().Any())
+		values = append(values, element.Eval(rt).Any())
 	}
 	
 	return NewValue(values)
@@ -0,0 +1,311 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+	"sort"
+	"strings"
+)
+
+// arrayMethods - встроенные методы массивов. Методы не изменяют массив:
+// push, sort, reverse и остальные возвращают новый массив
+var arrayMethods = []*BuiltinMethod{
+	{
+		Name: "length", Returns: "int",
+		Doc: "Количество элементов",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(int64(len(this.([]any))))
+		},
+	},
+	{
+		Name: "push", Params: []string{"value: any"}, Returns: "array",
+		Doc: "Новый массив с элементом в конце",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			arr := this.([]any)
+			// Всегда копируем: иначе два push к одному массиву делили бы его запас емкости
+			return NewValue(append(arr[:len(arr):len(arr)], args[0].Any()))
+		},
+	},
+	{
+		Name: "pop", Returns: "any",
+		Doc: "Последний элемент массива",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			arr := this.([]any)
+			if len(arr) == 0 {
+				panic("array.pop() called on empty array")
+			}
+			return NewValue(arr[len(arr)-1])
+		},
+	},
+	{
+		Name: "slice", Params: []string{"start: int", "end: int"}, Returns: "array",
+		Doc: "Элементы с start до end (не включая end)",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			arr := this.([]any)
+			start := intArg("array", "slice", args[0])
+			end := intArg("array", "slice", args[1])
+			if start < 0 || end > len(arr) || start > end {
+				panic("array.slice() index out of bounds")
+			}
+			return NewValue(arr[start:end])
+		},
+	},
+	{
+		Name: "map", Params: []string{"fn: (item) -> any"}, Returns: "array",
+		Doc: "Новый массив из результатов fn для каждого элемента",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			fn := callbackArg("array", "map", args[0])
+			arr := this.([]any)
+			result := make([]any, len(arr))
+			for i, item := range arr {
+				result[i] = CallIn(rt, fn, []*Value{NewValue(item)}).Any()
+			}
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "filter", Params: []string{"fn: (item) -> bool"}, Returns: "array",
+		Doc: "Элементы, для которых fn вернула true",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			fn := callbackArg("array", "filter", args[0])
+			result := []any{}
+			for _, item := range this.([]any) {
+				if CallIn(rt, fn, []*Value{NewValue(item)}).Bool() {
+					result = append(result, item)
+				}
+			}
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "reduce", Params: []string{"initial: any", "fn: (acc, item) -> any"}, Returns: "any",
+		Doc: "Сворачивает массив: acc = fn(acc, item), начиная с initial",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			fn := callbackArg("array", "reduce", args[1])
+			accumulator := args[0]
+			for _, item := range this.([]any) {
+				accumulator = CallIn(rt, fn, []*Value{accumulator, NewValue(item)})
+			}
+			return accumulator
+		},
+	},
+	{
+		Name: "sort", Params: []string{"compare?: (a, b) -> int"}, Returns: "array",
+		Doc: "Отсортированная копия. Без компаратора сортирует числа, строки и структуры с impl Ord по возрастанию; " +
+			"компаратор возвращает отрицательное число (или true), если a идет раньше b",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			result := append([]any(nil), this.([]any)...)
+			var less func(a, b any) bool
+			if len(args) == 1 {
+				fn := callbackArg("array", "sort", args[0])
+				less = func(a, b any) bool {
+					order := CallIn(rt, fn, []*Value{NewValue(a), NewValue(b)})
+					if before, ok := order.Any().(bool); ok {
+						return before
+					}
+					return order.Float64() < 0
+				}
+			} else {
+				less = func(a, b any) bool {
+					order, err := Compare(rt, NewValue(a), NewValue(b))
+					if err != nil {
+						panic(fmt.Sprintf("array.sort() without comparator expects numbers, strings or structs with impl Ord, got %s and %s",
+							value.GetValueTypeName(NewValue(a)), value.GetValueTypeName(NewValue(b))))
+					}
+					return order < 0
+				}
+			}
+			sort.SliceStable(result, func(i, j int) bool { return less(result[i], result[j]) })
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "find", Params: []string{"fn: (item) -> bool"}, Returns: "any",
+		Doc: "Первый элемент, для которого fn вернула true, или null",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			arr := this.([]any)
+			if i := findIndex(rt, "find", arr, args[0]); i >= 0 {
+				return NewValue(arr[i])
+			}
+			return NewValue(nil)
+		},
+	},
+	{
+		Name: "findIndex", Params: []string{"fn: (item) -> bool"}, Returns: "int",
+		Doc: "Индекс первого элемента, для которого fn вернула true, или -1",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(int64(findIndex(rt, "findIndex", this.([]any), args[0])))
+		},
+	},
+	{
+		Name: "some", Params: []string{"fn: (item) -> bool"}, Returns: "bool",
+		Doc: "true, если fn вернула true хотя бы для одного элемента",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(findIndex(rt, "some", this.([]any), args[0]) >= 0)
+		},
+	},
+	{
+		Name: "every", Params: []string{"fn: (item) -> bool"}, Returns: "bool",
+		Doc: "true, если fn вернула true для всех элементов",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			fn := callbackArg("array", "every", args[0])
+			for _, item := range this.([]any) {
+				if !CallIn(rt, fn, []*Value{NewValue(item)}).Bool() {
+					return NewValue(false)
+				}
+			}
+			return NewValue(true)
+		},
+	},
+	{
+		Name: "includes", Params: []string{"value: any"}, Returns: "bool",
+		Doc: "true, если массив содержит значение (сравнение как в ==)",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(indexOf(rt, this.([]any), args[0]) >= 0)
+		},
+	},
+	{
+		Name: "indexOf", Params: []string{"value: any"}, Returns: "int",
+		Doc: "Индекс первого элемента, равного значению, или -1",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(int64(indexOf(rt, this.([]any), args[0])))
+		},
+	},
+	{
+		Name: "join", Params: []string{"sep?: string"}, Returns: "string",
+		Doc: "Строка из элементов через разделитель (по умолчанию \",\")",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			sep := ","
+			if len(args) == 1 {
+				sep = stringArg("array", "join", args[0])
+			}
+			arr := this.([]any)
+			parts := make([]string, len(arr))
+			for i, item := range arr {
+				parts[i] = FormatValue(item)
+			}
+			return NewValue(strings.Join(parts, sep))
+		},
+	},
+	{
+		Name: "reverse", Returns: "array",
+		Doc: "Элементы в обратном порядке",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			arr := this.([]any)
+			result := make([]any, len(arr))
+			for i, item := range arr {
+				result[len(arr)-1-i] = item
+			}
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "flatMap", Params: []string{"fn: (item) -> array"}, Returns: "array",
+		Doc: "Как map, но массивы из fn склеиваются в один",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			fn := callbackArg("array", "flatMap", args[0])
+			result := []any{}
+			for _, item := range this.([]any) {
+				mapped := CallIn(rt, fn, []*Value{NewValue(item)}).Any()
+				if items, ok := mapped.([]any); ok {
+					result = append(result, items...)
+				} else {
+					result = append(result, mapped)
+				}
+			}
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "zip", Params: []string{"other: array"}, Returns: "array",
+		Doc: "Массив кортежей (a, b) из элементов с одинаковым индексом; длина - по короткому массиву",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			other, ok := args[0].Any().([]any)
+			if !ok {
+				panic(fmt.Sprintf("array.zip() expects an array, got %s", value.GetValueTypeName(args[0])))
+			}
+			arr := this.([]any)
+			result := make([]any, min(len(arr), len(other)))
+			for i := range result {
+				result[i] = value.NewTuple(arr[i], other[i])
+			}
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "groupBy", Params: []string{"fn: (item) -> any"}, Returns: "object",
+		Doc: "Объект: ключ из fn -> массив элементов с этим ключом",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			fn := callbackArg("array", "groupBy", args[0])
+			groups := value.NewObject()
+			for _, item := range this.([]any) {
+				key := FormatValue(CallIn(rt, fn, []*Value{NewValue(item)}).Any())
+				group, _ := groups.Get(key)
+				if group == nil {
+					groups.Set(key, NewValue([]any{item}))
+					continue
+				}
+				items := group.Any().([]any)
+				groups.Set(key, NewValue(append(items, item)))
+			}
+			return NewValue(groups)
+		},
+	},
+	{
+		Name: "chunk", Params: []string{"size: int"}, Returns: "array",
+		Doc: "Массив частей длиной size (последняя может быть короче)",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			size := intArg("array", "chunk", args[0])
+			if size <= 0 {
+				panic(fmt.Sprintf("array.chunk() size must be positive, got %d", size))
+			}
+			arr := this.([]any)
+			result := []any{}
+			for start := 0; start < len(arr); start += size {
+				end := min(start+size, len(arr))
+				result = append(result, append([]any(nil), arr[start:end]...))
+			}
+			return NewValue(result)
+		},
+	},
+	{
+		Name: "unique", Returns: "array",
+		Doc: "Элементы без повторов (сравнение как в ==), в порядке первого вхождения",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			result := []any{}
+			seen := value.NewSet()
+			for _, item := range this.([]any) {
+				if seen.Has(item) {
+					continue
+				}
+				if seen.Add(item) != nil && indexOf(rt, result, NewValue(item)) >= 0 {
+					// Объекты, массивы и структуры не хешируются, сравниваем их перебором
+					continue
+				}
+				result = append(result, item)
+			}
+			return NewValue(result)
+		},
+	},
+}
+
+// findIndex возвращает индекс первого элемента, для которого fn вернула true
+func findIndex(rt *Runtime, method string, arr []any, fnArg *Value) int {
+	fn := callbackArg("array", method, fnArg)
+	for i, item := range arr {
+		if CallIn(rt, fn, []*Value{NewValue(item)}).Bool() {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexOf возвращает индекс первого элемента, равного target (как в ==), или -1
+func indexOf(rt *Runtime, arr []any, target *Value) int {
+	for i, item := range arr {
+		if Equal(rt, NewValue(item), target) {
+			return i
+		}
+	}
+	return -1
+}
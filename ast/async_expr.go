@@ -3,41 +3,25 @@ package ast
 import (
 	"foo_lang/scope"
 	"foo_lang/value"
-	"sync"
 	"time"
 )
 
 // AsyncExpr представляет async выражение для запуска горутины
 type AsyncExpr struct {
+	Node
 	Expr Expr
 }
 
-// Карта scope для каждой горутины - нужно для избежания race conditions
-var goroutineScopes = make(map[int64]*scope.ScopeStack)
-var goroutineCounter int64
-var scopeMutex sync.RWMutex
-
-func (a *AsyncExpr) Eval() *value.Value {
+func (a *AsyncExpr) Eval(rt *Runtime) *value.Value {
 	promise := value.NewPromise()
 	
 	// Захватываем текущее состояние scope (все переменные)
-	capturedVars := scope.GlobalScope.GetAll()
-	
-	// Увеличиваем счетчик горутин
-	scopeMutex.Lock()
-	goroutineCounter++
-	goroutineID := goroutineCounter
-	scopeMutex.Unlock()
+	capturedVars := rt.Scope().GetAll()
 	
 	// Запускаем выполнение в отдельной горутине
 	go func() {
 		// Восстанавливаемся от паник
 		defer func() {
-			// Очищаем scope для этой горутины
-			scopeMutex.Lock()
-			delete(goroutineScopes, goroutineID)
-			scopeMutex.Unlock()
-			
 			if r := recover(); r != nil {
 				// Преобразуем панику в ошибку
 				var errMsg string
@@ -61,25 +45,11 @@ func (a *AsyncExpr) Eval() *value.Value {
 			isolatedScope.Set(name, val)
 		}
 		
-		// Сохраняем scope для этой горутины
-		scopeMutex.Lock()
-		goroutineScopes[goroutineID] = isolatedScope
-		originalScope := scope.GlobalScope
-		scope.GlobalScope = isolatedScope
-		scopeMutex.Unlock()
-		
-		// Гарантируем восстановление scope в defer
-		defer func() {
-			scopeMutex.Lock()
-			scope.GlobalScope = originalScope
-			scopeMutex.Unlock()
-		}()
-		
-		// Выполняем выражение в изолированном scope
-		result := a.Expr.Eval()
+		// Выполняем выражение в изолированном scope, не затрагивая стек вызывающей горутины
+		result := a.Expr.Eval(rt.WithScope(isolatedScope).Detached("<async>"))
 		
 		// Проверяем специальные флаги
-		if result != nil && (result.IsReturn() || result.IsBreak()) {
+		if result != nil && (result.IsReturn() || result.IsBreak() || result.IsContinue()) {
 			// Эти флаги не должны распространяться через async границы
 			// Создаем новое значение без флагов
 			cleanResult := value.NewValue(result.Any())
@@ -96,12 +66,13 @@ func (a *AsyncExpr) Eval() *value.Value {
 
 // AwaitExpr представляет await выражение для ожидания промиса
 type AwaitExpr struct {
+	Node
 	Expr Expr
 }
 
-func (a *AwaitExpr) Eval() *value.Value {
+func (a *AwaitExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем выражение
-	result := a.Expr.Eval()
+	result := a.Expr.Eval(rt)
 	
 	// Проверяем, является ли результат промисом
 	if promise, ok := result.Any().(*value.Promise); ok {
@@ -127,15 +98,16 @@ func (a *AwaitExpr) Eval() *value.Value {
 
 // PromiseAllExpr представляет Promise.all() выражение
 type PromiseAllExpr struct {
+	Node
 	Args []Expr
 }
 
-func (p *PromiseAllExpr) Eval() *value.Value {
+func (p *PromiseAllExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем все аргументы
 	promises := make([]*value.Promise, 0)
 	
 	for _, arg := range p.Args {
-		result := arg.Eval()
+		result := arg.Eval(rt)
 		if promise, ok := result.Any().(*value.Promise); ok {
 			promises = append(promises, promise)
 		} else {
@@ -153,15 +125,16 @@ func (p *PromiseAllExpr) Eval() *value.Value {
 
 // PromiseAnyExpr представляет Promise.any() выражение
 type PromiseAnyExpr struct {
+	Node
 	Args []Expr
 }
 
-func (p *PromiseAnyExpr) Eval() *value.Value {
+func (p *PromiseAnyExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем все аргументы
 	promises := make([]*value.Promise, 0)
 	
 	for _, arg := range p.Args {
-		result := arg.Eval()
+		result := arg.Eval(rt)
 		if promise, ok := result.Any().(*value.Promise); ok {
 			promises = append(promises, promise)
 		} else {
@@ -179,12 +152,13 @@ func (p *PromiseAnyExpr) Eval() *value.Value {
 
 // SleepExpr представляет функцию sleep для задержки
 type SleepExpr struct {
+	Node
 	Duration Expr
 }
 
-func (s *SleepExpr) Eval() *value.Value {
+func (s *SleepExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем длительность
-	durationValue := s.Duration.Eval()
+	durationValue := s.Duration.Eval(rt)
 	
 	// Преобразуем в миллисекунды
 	var ms int64
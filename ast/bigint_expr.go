@@ -0,0 +1,17 @@
+package ast
+
+import "math/big"
+
+// BigIntExpr - литерал BigInt: 123n
+type BigIntExpr struct {
+	Node
+	Value *Value
+}
+
+func NewBigIntExpr(value *big.Int) *BigIntExpr {
+	return &BigIntExpr{Value: NewValue(value)}
+}
+
+func (n *BigIntExpr) Eval(rt *Runtime) *Value {
+	return n.Value
+}
@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"foo_lang/value"
+	"math/big"
+)
+
+// bigintMethods - встроенные методы BigInt
+var bigintMethods = []*BuiltinMethod{
+	{
+		Name: "toString", Returns: "string",
+		Doc: "Десятичная запись числа",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(this.(*big.Int).String())
+		},
+	},
+	{
+		Name: "abs", Returns: "bigint",
+		Doc: "Модуль числа",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(new(big.Int).Abs(this.(*big.Int)))
+		},
+	},
+	{
+		Name: "toInt", Returns: "int",
+		Doc: "Число как int; ошибка, если оно не помещается в int",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			n := this.(*big.Int)
+			if !n.IsInt64() {
+				panic("bigint.toInt() value " + n.String() + " is out of int range")
+			}
+			return NewValue(n.Int64())
+		},
+	},
+	{
+		Name: "toFloat", Returns: "float",
+		Doc: "Ближайшее float значение",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(NewValue(this).Float64())
+		},
+	},
+}
+
+// decimalMethods - встроенные методы Decimal
+var decimalMethods = []*BuiltinMethod{
+	{
+		Name: "toString", Returns: "string",
+		Doc: "Десятичная запись числа",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(this.(*value.Decimal).String())
+		},
+	},
+	{
+		Name: "abs", Returns: "decimal",
+		Doc: "Модуль числа",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(this.(*value.Decimal).Abs())
+		},
+	},
+	{
+		Name: "round", Params: []string{"places?: int"}, Returns: "decimal",
+		Doc: "Число, округленное до places знаков после точки (по умолчанию 0); половина округляется от нуля",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			places := 0
+			if len(args) == 1 {
+				places = intArg("decimal", "round", args[0])
+			}
+			return NewValue(this.(*value.Decimal).Round(places))
+		},
+	},
+	{
+		Name: "toFloat", Returns: "float",
+		Doc: "Ближайшее float значение",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(this.(*value.Decimal).Float64())
+		},
+	},
+	{
+		Name: "toBigInt", Returns: "bigint",
+		Doc: "Целая часть числа",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(this.(*value.Decimal).BigInt())
+		},
+	},
+}
@@ -3,9 +3,11 @@ package ast
 import (
 	"fmt"
 	"foo_lang/token"
+	"foo_lang/value"
 )
 
 type BinaryExpr struct {
+	Node
 	Left  Expr
 	Op    token.Token
 	Right Expr
@@ -15,19 +17,32 @@ func NewBinaryExpr(left Expr, op token.Token, right Expr) *BinaryExpr {
 	return &BinaryExpr{Left: left, Op: op, Right: right}
 }
 
-func (b *BinaryExpr) Eval() *Value {
+func (b *BinaryExpr) Eval(rt *Runtime) *Value {
 
-	left := b.Left.Eval()
-	right := b.Right.Eval()
+	left := b.Left.Eval(rt)
+	right := b.Right.Eval(rt)
+
+	// Структуры с impl Add, Eq, Ord ... (см. operatorInterfaces)
+	if result, ok := OverloadedBinary(rt, b.Op, left, right); ok {
+		return result
+	}
+
+	// BigInt и Decimal сравниваются точно, без приведения к float
+	if value.IsBigNumber(left) || value.IsBigNumber(right) {
+		switch b.Op {
+		case token.GT, token.LT, token.GT_EQ, token.LT_EQ:
+			return b.compareBig(left, right)
+		}
+	}
 
 	switch b.Op {
 
 	case token.ADD:
-		return b.add(left, right)
+		return b.add(rt, left, right)
 	case token.SUB:
-		return b.subtract(left, right)
+		return b.subtract(rt, left, right)
 	case token.MUL:
-		return b.multiply(left, right)
+		return b.multiply(rt, left, right)
 	case token.QUO:
 		return b.divide(left, right)
 	case token.REM:
@@ -38,13 +53,14 @@ func (b *BinaryExpr) Eval() *Value {
 	case token.LT:
 		return NewValue(left.Float64() < right.Float64())
 	case token.NOT_EQ:
-		return NewValue(left.Float64() != right.Float64())
+		return value.NotEqual(left, right)
 	case token.LT_EQ:
 		return NewValue(left.Float64() <= right.Float64())
 	case token.GT_EQ:
 		return NewValue(left.Float64() >= right.Float64())
 	case token.EQ_EQ:
-		return NewValue(left.Float64() == right.Float64())
+		// Как в bytecode VM: строки, bool, null, кортежи и множества сравниваются по значению
+		return value.Equal(left, right)
 
 	case token.AND:
 		return NewValue(left.Int64() & right.Int64())
@@ -68,17 +84,21 @@ func (b *BinaryExpr) Eval() *Value {
 	panic(fmt.Sprintf("unknown token: %s", b.Op))
 }
 
-func (b *BinaryExpr) add(left, right *Value) *Value {
+func (b *BinaryExpr) add(rt *Runtime, left, right *Value) *Value {
 
 	if left.IsNumber() && right.IsNumber() {
 		// Сохраняем типы: int + int = int, иначе float
 		if left.IsInt64() && right.IsInt64() {
-			return NewValue(left.Int64() + right.Int64())
+			return NewValue(value.AddInt(left.Int64(), right.Int64(), rt.OverflowCheck()))
 		} else {
 			return NewValue(left.Float64() + right.Float64())
 		}
 	}
 
+	if !left.IsString() && !right.IsString() && (value.IsBigNumber(left) || value.IsBigNumber(right)) {
+		return bigArith("+", left, right)
+	}
+
 	if left.IsString() && right.IsString() {
 		return NewValue(left.String() + right.String())
 	}
@@ -111,13 +131,13 @@ func (b *BinaryExpr) add(left, right *Value) *Value {
 		return NewValue(left.String() + right.String())
 	}
 
-	// Конкатенация строки с любым другим типом (включая массивы)
+	// Конкатенация строки с любым другим типом (включая массивы и структуры с impl Display)
 	if left.IsString() {
-		return NewValue(left.String() + FormatValue(right.Any()))
+		return NewValue(left.String() + Display(rt, right))
 	}
 
 	if right.IsString() {
-		return NewValue(FormatValue(left.Any()) + right.String())
+		return NewValue(Display(rt, left) + right.String())
 	}
 
 	return nil
@@ -139,11 +159,14 @@ func (b *BinaryExpr) AndAnd(left, right *Value) *Value {
 
 // Арифметические операции с сохранением типов
 
-func (b *BinaryExpr) subtract(left, right *Value) *Value {
+func (b *BinaryExpr) subtract(rt *Runtime, left, right *Value) *Value {
+	if value.IsBigNumber(left) || value.IsBigNumber(right) {
+		return bigArith("-", left, right)
+	}
 	if left.IsNumber() && right.IsNumber() {
 		// int - int = int, иначе float
 		if left.IsInt64() && right.IsInt64() {
-			return NewValue(left.Int64() - right.Int64())
+			return NewValue(value.SubInt(left.Int64(), right.Int64(), rt.OverflowCheck()))
 		} else {
 			return NewValue(left.Float64() - right.Float64())
 		}
@@ -151,11 +174,14 @@ func (b *BinaryExpr) subtract(left, right *Value) *Value {
 	panic("subtract operation requires numeric operands")
 }
 
-func (b *BinaryExpr) multiply(left, right *Value) *Value {
+func (b *BinaryExpr) multiply(rt *Runtime, left, right *Value) *Value {
+	if value.IsBigNumber(left) || value.IsBigNumber(right) {
+		return bigArith("*", left, right)
+	}
 	if left.IsNumber() && right.IsNumber() {
 		// int * int = int, иначе float
 		if left.IsInt64() && right.IsInt64() {
-			return NewValue(left.Int64() * right.Int64())
+			return NewValue(value.MulInt(left.Int64(), right.Int64(), rt.OverflowCheck()))
 		} else {
 			return NewValue(left.Float64() * right.Float64())
 		}
@@ -164,6 +190,9 @@ func (b *BinaryExpr) multiply(left, right *Value) *Value {
 }
 
 func (b *BinaryExpr) divide(left, right *Value) *Value {
+	if value.IsBigNumber(left) || value.IsBigNumber(right) {
+		return bigArith("/", left, right)
+	}
 	if left.IsNumber() && right.IsNumber() {
 		// Деление всегда возвращает float для точности
 		return NewValue(left.Float64() / right.Float64())
@@ -172,6 +201,9 @@ func (b *BinaryExpr) divide(left, right *Value) *Value {
 }
 
 func (b *BinaryExpr) modulo(left, right *Value) *Value {
+	if value.IsBigNumber(left) || value.IsBigNumber(right) {
+		return bigArith("%", left, right)
+	}
 	if left.IsNumber() && right.IsNumber() {
 		// int % int = int, float % float = float
 		if left.IsInt64() && right.IsInt64() {
@@ -183,3 +215,29 @@ func (b *BinaryExpr) modulo(left, right *Value) *Value {
 	}
 	panic("modulo operation requires numeric operands")
 }
+
+// bigArith выполняет арифметику, в которой участвует BigInt или Decimal
+func bigArith(op string, left, right *Value) *Value {
+	result, err := value.BigArith(op, left, right)
+	if err != nil {
+		panic(err.Error())
+	}
+	return result
+}
+
+// compareBig сравнивает числа, из которых хотя бы одно BigInt или Decimal
+func (b *BinaryExpr) compareBig(left, right *Value) *Value {
+	order, err := value.Compare(left, right)
+	if err != nil {
+		panic(fmt.Sprintf("cannot compare %s and %s", value.GetValueTypeName(left), value.GetValueTypeName(right)))
+	}
+	switch b.Op {
+	case token.GT:
+		return NewValue(order > 0)
+	case token.LT:
+		return NewValue(order < 0)
+	case token.GT_EQ:
+		return NewValue(order >= 0)
+	}
+	return NewValue(order <= 0)
+}
@@ -1,6 +1,7 @@
 package ast
 
 type BodyExpr struct {
+	Node
 	Statments []Expr
 }
 
@@ -8,11 +9,12 @@ func NewBodyStatment(stratments []Expr) *BodyExpr {
 	return &BodyExpr{Statments: stratments}
 }
 
-func (b *BodyExpr) Eval() *Value {
+func (b *BodyExpr) Eval(rt *Runtime) *Value {
 	var result *Value
 
 	for _, stm := range b.Statments {
-		result = stm.Eval()
+		rt.calls.at(stm)
+		result = stm.Eval(rt)
 	}
 
 	return result
@@ -5,6 +5,7 @@ import (
 )
 
 type BoolExpr struct {
+	Node
 	Value *Value
 }
 
@@ -14,7 +15,7 @@ func NewBoolExpr(value bool) *BoolExpr {
 	}
 }
 
-func (n *BoolExpr) Eval() *Value {
+func (n *BoolExpr) Eval(rt *Runtime) *Value {
 	return n.Value
 }
 
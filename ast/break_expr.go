@@ -1,16 +1,36 @@
 package ast
 
 type BreakExpr struct {
-	Expr Expr
+	Node
+	Expr  Expr
+	Label string // break outer: метка цикла, пустая - ближайший цикл
 }
 
-func NewBreakExpr(expr Expr) *BreakExpr {
-	return &BreakExpr{Expr: expr}
+func NewBreakExpr(expr Expr, label string) *BreakExpr {
+	return &BreakExpr{Expr: expr, Label: label}
 }
 
-func (r *BreakExpr) Eval() *Value {
+func (r *BreakExpr) Eval(rt *Runtime) *Value {
 	// Break always returns nil but marks the value as a break
 	result := NewValue(nil)
 	result.SetBreak(true)
+	result.SetLabel(r.Label)
+	return result
+}
+
+// ContinueExpr переходит к следующей итерации цикла (или цикла с меткой: continue outer)
+type ContinueExpr struct {
+	Node
+	Label string
+}
+
+func NewContinueExpr(label string) *ContinueExpr {
+	return &ContinueExpr{Label: label}
+}
+
+func (c *ContinueExpr) Eval(rt *Runtime) *Value {
+	result := NewValue(nil)
+	result.SetContinue(true)
+	result.SetLabel(c.Label)
 	return result
 }
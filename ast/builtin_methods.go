@@ -0,0 +1,120 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+	"sort"
+	"strings"
+)
+
+// BuiltinMethod - встроенный метод массива, строки, BigInt или Decimal. Методы
+// собраны в одну таблицу: по ней вызывает методы CallMethod (интерпретатор и
+// bytecode VM) и строит подсказки LSP
+type BuiltinMethod struct {
+	Name    string
+	Params  []string // "sep: string"; необязательный параметр - "sep?", переменное число - "args..."
+	Returns string
+	Doc     string
+	call    func(rt *Runtime, this any, args []*Value) *Value
+}
+
+// builtinMethods - таблица встроенных методов: [typeName][methodName]
+var builtinMethods = map[string]map[string]*BuiltinMethod{}
+
+func init() {
+	registerBuiltinMethods("array", arrayMethods)
+	registerBuiltinMethods("string", stringMethods)
+	registerBuiltinMethods("bigint", bigintMethods)
+	registerBuiltinMethods("decimal", decimalMethods)
+}
+
+func registerBuiltinMethods(typeName string, methods []*BuiltinMethod) {
+	table := make(map[string]*BuiltinMethod, len(methods))
+	for _, method := range methods {
+		table[method.Name] = method
+	}
+	builtinMethods[typeName] = table
+}
+
+// LookupBuiltinMethod ищет встроенный метод по имени типа значения
+// (value.GetValueTypeName): "array", "string", "bigint" или "decimal"
+func LookupBuiltinMethod(typeName, name string) (*BuiltinMethod, bool) {
+	method, ok := builtinMethods[typeName][name]
+	return method, ok
+}
+
+// BuiltinMethods возвращает встроенные методы типа, отсортированные по имени
+func BuiltinMethods(typeName string) []*BuiltinMethod {
+	methods := make([]*BuiltinMethod, 0, len(builtinMethods[typeName]))
+	for _, method := range builtinMethods[typeName] {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+// Signature возвращает сигнатуру метода: fn join(sep?: string) -> string
+func (m *BuiltinMethod) Signature() string {
+	return fmt.Sprintf("fn %s(%s) -> %s", m.Name, strings.Join(m.Params, ", "), m.Returns)
+}
+
+// arity возвращает минимальное и максимальное число аргументов; max = -1 без ограничения
+func (m *BuiltinMethod) arity() (min, max int) {
+	for _, param := range m.Params {
+		name, _, _ := strings.Cut(param, ":")
+		switch {
+		case strings.HasSuffix(name, "..."):
+			return min, -1
+		case !strings.HasSuffix(name, "?"):
+			min++
+		}
+	}
+	return min, len(m.Params)
+}
+
+// Call проверяет число аргументов и вызывает метод у значения this
+func (m *BuiltinMethod) Call(rt *Runtime, typeName string, this *Value, args []*Value) *Value {
+	min, max := m.arity()
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		expected := fmt.Sprint(min)
+		switch {
+		case max < 0:
+			expected = "at least " + expected
+		case max > min:
+			expected = fmt.Sprintf("%d to %d", min, max)
+		}
+		panic(fmt.Sprintf("%s.%s() expects %s argument(s), got %d", typeName, m.Name, expected, len(args)))
+	}
+	return m.call(rt, this.Any(), args)
+}
+
+// callbackArg возвращает аргумент-функцию метода
+func callbackArg(receiver, method string, arg *Value) Callable {
+	fn, ok := arg.Any().(Callable)
+	if !ok {
+		panic(fmt.Sprintf("%s.%s() expects a function, got %s", receiver, method, value.GetValueTypeName(arg)))
+	}
+	return fn
+}
+
+// intArg возвращает целочисленный аргумент метода
+func intArg(receiver, method string, arg *Value) int {
+	switch n := arg.Any().(type) {
+	case int64:
+		return int(n)
+	case float64:
+		if n == float64(int(n)) {
+			return int(n)
+		}
+	}
+	panic(fmt.Sprintf("%s.%s() expects an integer, got %s", receiver, method, FormatValue(arg.Any())))
+}
+
+// stringArg возвращает строковый аргумент метода
+func stringArg(receiver, method string, arg *Value) string {
+	str, ok := arg.Any().(string)
+	if !ok {
+		panic(fmt.Sprintf("%s.%s() expects a string, got %s", receiver, method, value.GetValueTypeName(arg)))
+	}
+	return str
+}
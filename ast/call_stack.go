@@ -0,0 +1,185 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/modules"
+	"strings"
+)
+
+// Frame - кадр стека вызовов foo: функция и выполняемая в ней позиция
+type Frame struct {
+	Function string
+	Position Position
+
+	module bool // верхний уровень импортируемого модуля: defer в нем запрещен
+}
+
+// String форматирует кадр как "at fib (math.foo:12:5)"
+func (f Frame) String() string {
+	if !f.Position.IsValid() {
+		return "at " + f.Function
+	}
+	return fmt.Sprintf("at %s (%s)", f.Function, f.Position)
+}
+
+// callStack - стек вызовов функций foo одной горутины.
+// Позиция верхнего кадра обновляется при выполнении каждого оператора,
+// поэтому в момент ошибки стек указывает на строки скрипта, а не на кадры Go
+type callStack struct {
+	frames []Frame
+
+	// deferred - выражения defer каждого кадра (индексы совпадают с frames)
+	deferred [][]deferredCall
+
+	// trace - снимок стека в момент последней ошибки. Сбрасывается,
+	// когда выполнение продолжается (ошибка была перехвачена)
+	trace []Frame
+
+	// imports - цепочка модулей, верхний уровень которых выполняет эта
+	// горутина: по ней modules.Cache находит циклы импорта
+	imports *modules.Importer
+}
+
+func newCallStack(root string) *callStack {
+	return &callStack{
+		frames:   []Frame{{Function: root}},
+		deferred: [][]deferredCall{nil},
+		imports:  &modules.Importer{},
+	}
+}
+
+// at запоминает позицию выполняемого узла в верхнем кадре
+func (cs *callStack) at(expr Expr) {
+	cs.atPosition(PositionOf(expr))
+}
+
+func (cs *callStack) atPosition(pos Position) {
+	cs.trace = nil
+
+	if pos.IsValid() {
+		cs.frames[len(cs.frames)-1].Position = pos
+	}
+}
+
+// push добавляет кадр вызываемой функции. Снимается через
+//
+//	defer func() { rt.calls.leave(recover()) }()
+func (cs *callStack) push(function string, body Expr) {
+	cs.pushAt(function, PositionOf(body))
+}
+
+func (cs *callStack) pushAt(function string, pos Position) {
+	if function == "" {
+		function = "<anonymous>"
+	}
+	cs.frames = append(cs.frames, Frame{Function: function, Position: pos})
+	cs.deferred = append(cs.deferred, nil)
+}
+
+// pushModule добавляет кадр верхнего уровня импортируемого модуля, чтобы стек
+// ошибки показывал цепочку импортов. Снимается так же, как кадр функции
+func (cs *callStack) pushModule() {
+	cs.frames = append(cs.frames, Frame{Function: "<module>", module: true})
+	cs.deferred = append(cs.deferred, nil)
+}
+
+// leave снимает кадр функции. Если функция завершилась паникой, сохраняет снимок
+// стека (первым его делает самый глубокий кадр) и продолжает панику
+func (cs *callStack) leave(recovered interface{}) {
+	if recovered != nil && cs.trace == nil {
+		cs.trace = cs.snapshot()
+	}
+
+	cs.frames = cs.frames[:len(cs.frames)-1]
+	cs.deferred = cs.deferred[:len(cs.deferred)-1]
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
+// canDefer сообщает, является ли верхний кадр функцией (defer на верхнем
+// уровне скрипта или модуля не допускается)
+func (cs *callStack) canDefer() bool {
+	top := len(cs.frames) - 1
+	return top > 0 && !cs.frames[top].module
+}
+
+// addDeferred добавляет отложенный вызов в верхний кадр (проверяется canDefer)
+func (cs *callStack) addDeferred(call deferredCall) {
+	top := len(cs.deferred) - 1
+	cs.deferred[top] = append(cs.deferred[top], call)
+}
+
+// takeDeferred забирает выражения defer верхнего кадра
+func (cs *callStack) takeDeferred() []deferredCall {
+	top := len(cs.deferred) - 1
+	pending := cs.deferred[top]
+	cs.deferred[top] = nil
+	return pending
+}
+
+// snapshot возвращает кадры от самого глубокого к верхнему уровню
+func (cs *callStack) snapshot() []Frame {
+	trace := make([]Frame, len(cs.frames))
+	for i, frame := range cs.frames {
+		trace[len(cs.frames)-1-i] = frame
+	}
+	return trace
+}
+
+// CallFrame выполняет run в новом кадре function стека вызовов rt - для функций,
+// тело которых выполняет не tree-walking интерпретатор (bytecode VM). pos -
+// начало тела функции; дальше позицию кадра обновляет At. Как у замыканий, при
+// выходе из run выполняются отложенные выражения defer (и при панике), а Err,
+// поднятый оператором ?, становится результатом
+func (rt *Runtime) CallFrame(function string, pos Position, run func() *Value) (result *Value) {
+	rt.calls.pushAt(function, pos)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
+	defer runDeferred(rt)
+
+	return run()
+}
+
+// At запоминает выполняемую позицию в верхнем кадре стека вызовов rt
+// (bytecode VM вызывает его для инструкций, у которых позиция известна)
+func (rt *Runtime) At(pos Position) {
+	rt.calls.atPosition(pos)
+}
+
+// StackTrace возвращает стек вызовов foo в момент последней ошибки
+// (или текущий стек, если ошибки не было), начиная с самого глубокого кадра
+func (rt *Runtime) StackTrace() []Frame {
+	if rt.calls.trace != nil {
+		return rt.calls.trace
+	}
+	return rt.calls.snapshot()
+}
+
+// ScriptError - ошибка выполнения скрипта со стеком вызовов foo
+type ScriptError struct {
+	Message string
+	Trace   []Frame
+}
+
+// NewScriptError создает ошибку из значения паники и стека вызовов rt
+func NewScriptError(rt *Runtime, recovered interface{}) *ScriptError {
+	return &ScriptError{
+		Message: fmt.Sprintf("%v", recovered),
+		Trace:   rt.StackTrace(),
+	}
+}
+
+// Error форматирует сообщение и цепочку "at ..." кадров
+func (e *ScriptError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Message)
+
+	for _, frame := range e.Trace {
+		sb.WriteString("\n    ")
+		sb.WriteString(frame.String())
+	}
+
+	return sb.String()
+}
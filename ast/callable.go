@@ -7,4 +7,52 @@ type Callable interface {
 	
 	// Name возвращает имя функции (для отладки)
 	Name() string
-}
\ No newline at end of file
+}
+
+// runtimeCallable - функция, которая выполняется в Runtime вызывающего кода:
+// в его стеке вызовов, а замыкания tree-walking интерпретатора - и в его стеке областей
+type runtimeCallable interface {
+	CallIn(rt *Runtime, args []*Value) *Value
+}
+
+// Builtin - встроенная Go-функция, которой нужен Runtime вызывающего кода:
+// например, trace() печатает его стек вызовов foo
+type Builtin struct {
+	name string
+	fn   func(rt *Runtime, args []*Value) *Value
+}
+
+func NewBuiltin(name string, fn func(rt *Runtime, args []*Value) *Value) *Builtin {
+	return &Builtin{name: name, fn: fn}
+}
+
+func (b *Builtin) Name() string {
+	return b.name
+}
+
+// Call вызывает функцию в GlobalRuntime - для кода, у которого нет своего Runtime
+func (b *Builtin) Call(args []*Value) *Value {
+	return b.fn(GlobalRuntime, args)
+}
+
+func (b *Builtin) CallIn(rt *Runtime, args []*Value) *Value {
+	return b.fn(rt, args)
+}
+
+// CallIn вызывает fn в Runtime вызывающего кода, если fn это поддерживает
+func CallIn(rt *Runtime, fn Callable, args []*Value) *Value {
+	if rc, ok := fn.(runtimeCallable); ok {
+		return rc.CallIn(rt, args)
+	}
+	return fn.Call(args)
+}
+
+// callResult снимает с результата вызова флаг return: он останавливает
+// выполнение тела вызванной функции, а в вызывающем коде цикл принял бы
+// оператор-вызов за return и завершил бы объемлющую функцию
+func callResult(result *Value) *Value {
+	if result != nil && result.IsReturn() {
+		result.SetReturn(false)
+	}
+	return result
+}
@@ -2,12 +2,12 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 )
 
 // Closure представляет замыкание - функцию с захваченными переменными
 type Closure struct {
+	rt           *Runtime // Runtime, в котором создано замыкание
 	funcName     string
 	args         []map[string]Expr
 	body         Expr
@@ -17,6 +17,7 @@ type Closure struct {
 
 // TypedClosure представляет типизированное замыкание
 type TypedClosure struct {
+	rt           *Runtime // Runtime, в котором создано замыкание
 	funcName     string
 	params       []FuncParam
 	body         Expr
@@ -25,22 +26,23 @@ type TypedClosure struct {
 }
 
 // NewClosure создает новое замыкание с захватом переменных из текущей области
-func NewClosure(funcName string, args []map[string]Expr, body Expr, isMacro bool) *Closure {
+func NewClosure(rt *Runtime, funcName string, args []map[string]Expr, body Expr, isMacro bool) *Closure {
 	// Захватываем все переменные из текущей области видимости
 	capturedVars := make(map[string]*value.Value)
 
 	// Анализируем тело функции для поиска свободных переменных
-	freeVars := findFreeVariables(body, args)
+	freeVars := findFreeVariables(rt, body, args)
 
 	// Захватываем значения свободных переменных
 	for varName := range freeVars {
-		if val, exists := scope.GlobalScope.Get(varName); exists {
+		if val, exists := rt.Scope().Get(varName); exists {
 			// Создаем копию значения для захвата
 			capturedVars[varName] = value.NewValue(val.Any())
 		}
 	}
 
 	return &Closure{
+		rt:           rt,
 		funcName:     funcName,
 		args:         args,
 		body:         body,
@@ -50,12 +52,12 @@ func NewClosure(funcName string, args []map[string]Expr, body Expr, isMacro bool
 }
 
 // findFreeVariables анализирует AST и находит свободные переменные
-func findFreeVariables(expr Expr, funcArgs []map[string]Expr) map[string]bool {
+func findFreeVariables(rt *Runtime, expr Expr, funcArgs []map[string]Expr) map[string]bool {
 	freeVars := make(map[string]bool)
 
 	// Пока что упростим: захватываем все переменные из текущей области видимости
 	// TODO: Улучшить анализ AST для более точного определения свободных переменных
-	allVars := scope.GlobalScope.GetAll()
+	allVars := rt.Scope().GetAll()
 
 	// Исключаем параметры функции из захваченных переменных
 	localVars := make(map[string]bool)
@@ -80,8 +82,17 @@ func (c *Closure) Name() string {
 	return c.funcName
 }
 
-// Call выполняет замыкание с захваченными переменными
+// Call выполняет замыкание в Runtime, в котором оно было создано
 func (c *Closure) Call(args []*Value) *Value {
+	return c.CallIn(c.rt.Detached("<go>"), args)
+}
+
+// CallIn выполняет замыкание с захваченными переменными в стеке областей rt
+func (c *Closure) CallIn(rt *Runtime, args []*Value) (result *Value) {
+	rt = rt.homeOf(c.rt)
+	rt.calls.push(c.funcName, c.body)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
 
 	expected := len(c.args)
 	passed := len(args)
@@ -91,25 +102,26 @@ func (c *Closure) Call(args []*Value) *Value {
 	}
 
 	// Создаем новую область видимости для функции
-	err := scope.GlobalScope.PushFunction()
+	err := rt.Scope().PushFunction()
 	if err != nil {
 		panic(err.Error())
 	}
-	defer scope.GlobalScope.PopFunction()
+	defer rt.Scope().PopFunction()
+	defer runDeferred(rt)
 
 	// Восстанавливаем захваченные переменные в новой области
 	for name, val := range c.capturedVars {
-		scope.GlobalScope.Set(name, val)
+		rt.Scope().Set(name, val)
 	}
 
 	// Устанавливаем параметры функции
 	for i, arg := range c.args {
 		for name, expr := range arg {
 			if i < len(args) {
-				scope.GlobalScope.Set(name, args[i])
+				rt.Scope().Set(name, args[i])
 			} else if expr != nil {
-				defaultValue := expr.Eval()
-				scope.GlobalScope.Set(name, defaultValue)
+				defaultValue := expr.Eval(rt)
+				rt.Scope().Set(name, defaultValue)
 			} else {
 				panic(fmt.Sprintf("missing required argument: %s", name))
 			}
@@ -124,7 +136,8 @@ func (c *Closure) Call(args []*Value) *Value {
 				continue
 			}
 
-			result := stm.Eval()
+			rt.calls.at(stm)
+			result := stm.Eval(rt)
 
 			// Проверяем на return
 			if result != nil && result.IsReturn() {
@@ -133,7 +146,7 @@ func (c *Closure) Call(args []*Value) *Value {
 		}
 	} else {
 		// Если тело - одно выражение (для стрелочных функций)
-		result := c.body.Eval()
+		result := c.body.Eval(rt)
 		if result != nil {
 			// Для одиночных выражений автоматически возвращаем результат
 			retResult := NewValue(result.Any())
@@ -161,7 +174,7 @@ func (c *Closure) GetCapturedVars() map[string]*value.Value {
 }
 
 // NewTypedClosure создает новое типизированное замыкание
-func NewTypedClosure(funcName string, params []FuncParam, body Expr, returnType string) *TypedClosure {
+func NewTypedClosure(rt *Runtime, funcName string, params []FuncParam, body Expr, returnType string) *TypedClosure {
 	// Захватываем все переменные из текущей области видимости
 	capturedVars := make(map[string]*value.Value)
 
@@ -169,6 +182,7 @@ func NewTypedClosure(funcName string, params []FuncParam, body Expr, returnType
 	// В будущем можно добавить анализ свободных переменных
 
 	return &TypedClosure{
+		rt:           rt,
 		funcName:     funcName,
 		params:       params,
 		body:         body,
@@ -182,8 +196,18 @@ func (tc *TypedClosure) Name() string {
 	return tc.funcName
 }
 
-// Call вызывает типизированное замыкание
+// Call вызывает типизированное замыкание в Runtime, в котором оно было создано
 func (tc *TypedClosure) Call(args []*Value) *Value {
+	return tc.CallIn(tc.rt.Detached("<go>"), args)
+}
+
+// CallIn вызывает типизированное замыкание в стеке областей rt
+func (tc *TypedClosure) CallIn(rt *Runtime, args []*Value) (result *Value) {
+	rt = rt.homeOf(tc.rt)
+	rt.calls.push(tc.funcName, tc.body)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
+
 	// Проверяем количество аргументов
 	requiredArgs := 0
 	for _, param := range tc.params {
@@ -201,12 +225,13 @@ func (tc *TypedClosure) Call(args []*Value) *Value {
 	}
 
 	// Создаем новую область видимости
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+	defer runDeferred(rt)
 
 	// Восстанавливаем захваченные переменные
 	for name, val := range tc.capturedVars {
-		scope.GlobalScope.Set(name, val)
+		rt.Scope().Set(name, val)
 	}
 
 	// Устанавливаем параметры функции с проверкой типов
@@ -218,25 +243,25 @@ func (tc *TypedClosure) Call(args []*Value) *Value {
 
 			// Проверяем тип параметра, если указан
 			if param.TypeName != "" {
-				if err := validateFunctionParameterType(argValue, param.TypeName); err != nil {
+				if err := validateFunctionParameterType(rt, argValue, param.TypeName); err != nil {
 					panic(fmt.Sprintf("function '%s' parameter '%s': %s", tc.funcName, param.Name, err.Error()))
 				}
 			}
 		} else if param.Default != nil {
 			// Используем значение по умолчанию
-			argValue = param.Default.Eval()
+			argValue = param.Default.Eval(rt)
 		} else {
 			panic(fmt.Sprintf("missing required argument: %s", param.Name))
 		}
 
-		scope.GlobalScope.Set(param.Name, argValue)
+		rt.Scope().Set(param.Name, argValue)
 	}
 
 	// Выполняем тело функции
-	var result *Value
 	if bodyStm, ok := tc.body.(*BodyExpr); ok {
 		for _, stmt := range bodyStm.Statments {
-			result = stmt.Eval()
+			rt.calls.at(stmt)
+			result = stmt.Eval(rt)
 			if result != nil && result.IsReturn() {
 				break
 			}
@@ -246,12 +271,12 @@ func (tc *TypedClosure) Call(args []*Value) *Value {
 			result = NewValue(nil)
 		}
 	} else {
-		result = tc.body.Eval()
+		result = tc.body.Eval(rt)
 	}
 
 	// Проверяем тип возвращаемого значения, если он указан
 	if tc.returnType != "" && result != nil {
-		if err := tc.validateReturnType(result); err != nil {
+		if err := tc.validateReturnType(rt, result); err != nil {
 			panic(fmt.Sprintf("function '%s' return type error: %s", tc.funcName, err.Error()))
 		}
 	}
@@ -260,18 +285,18 @@ func (tc *TypedClosure) Call(args []*Value) *Value {
 }
 
 // validateReturnType проверяет соответствие типа возвращаемого значения ожидаемому
-func (tc *TypedClosure) validateReturnType(returnValue *Value) error {
+func (tc *TypedClosure) validateReturnType(rt *Runtime, returnValue *Value) error {
 	// Извлекаем значение из return-обертки, если оно есть
 	actualValue := returnValue
 	if returnValue.IsReturn() {
 		actualValue = NewValue(returnValue.Any())
 	}
 
-	return validateFunctionParameterType(actualValue, tc.returnType)
+	return validateFunctionParameterType(rt, actualValue, tc.returnType)
 }
 
 // validateFunctionParameterType проверяет соответствие типа аргумента ожидаемому типу (включая Union типы)
-func validateFunctionParameterType(argValue *Value, expectedTypeName string) error {
+func validateFunctionParameterType(rt *Runtime, argValue *Value, expectedTypeName string) error {
 	// Используем универсальную функцию валидации, которая поддерживает Union типы
-	return validateVariableType(argValue, expectedTypeName)
+	return validateVariableType(rt, argValue, expectedTypeName)
 }
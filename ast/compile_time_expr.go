@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"foo_lang/scope"
 	"foo_lang/value"
 )
 
 // CompileTimeIfExpr представляет compile-time условие $if
 // Выполняется во время макроса, а не в runtime
 type CompileTimeIfExpr struct {
+	Node
 	Condition Expr // Условие, проверяемое в compile-time
 	ThenBody  Expr // Код, генерируемый если условие истинно
 	ElseBody  Expr // Код, генерируемый если условие ложно (опционально)
@@ -24,17 +24,17 @@ func NewCompileTimeIfExpr(condition, thenBody, elseBody Expr) *CompileTimeIfExpr
 	}
 }
 
-func (c *CompileTimeIfExpr) Eval() *value.Value {
+func (c *CompileTimeIfExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем условие в compile-time
-	condition := c.Condition.Eval()
+	condition := c.Condition.Eval(rt)
 	
 	// Проверяем результат условия
 	if condition.IsTruthy() {
 		// Если условие истинно, возвращаем тело then
-		return c.ThenBody.Eval()
+		return c.ThenBody.Eval(rt)
 	} else if c.ElseBody != nil {
 		// Если условие ложно и есть else, возвращаем тело else
-		return c.ElseBody.Eval()
+		return c.ElseBody.Eval(rt)
 	}
 	
 	// Если условие ложно и нет else, возвращаем пустую строку
@@ -44,6 +44,7 @@ func (c *CompileTimeIfExpr) Eval() *value.Value {
 // CompileTimeForExpr представляет compile-time цикл $for
 // Выполняется во время макроса для генерации повторяющегося кода
 type CompileTimeForExpr struct {
+	Node
 	Iterator   string // Имя переменной итератора
 	Collection Expr   // Коллекция для итерации
 	Body       Expr   // Тело цикла, генерируемое для каждого элемента
@@ -57,15 +58,15 @@ func NewCompileTimeForExpr(iterator string, collection, body Expr) *CompileTimeF
 	}
 }
 
-func (c *CompileTimeForExpr) Eval() *value.Value {
+func (c *CompileTimeForExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем коллекцию
-	collection := c.Collection.Eval()
+	collection := c.Collection.Eval(rt)
 	
 	var generatedCode []string
 	
 	// Создаем новую область видимости для цикла
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
 	
 	// Итерируемся по коллекции
 	// Пока упростим - будем работать только со строками и числами
@@ -74,11 +75,11 @@ func (c *CompileTimeForExpr) Eval() *value.Value {
 		str := collection.String()
 		for i, char := range str {
 			// Устанавливаем переменную итератора
-			scope.GlobalScope.Set(c.Iterator, value.NewValue(string(char)))
-			scope.GlobalScope.Set("index", value.NewValue(int64(i)))
+			rt.Scope().Set(c.Iterator, value.NewValue(string(char)))
+			rt.Scope().Set("index", value.NewValue(int64(i)))
 			
 			// Генерируем код для текущего элемента
-			result := c.Body.Eval()
+			result := c.Body.Eval(rt)
 			if result.IsString() {
 				// Обрабатываем интерполяцию ${переменная_итератора} вручную
 				processedString := c.processStringInterpolation(result.String(), c.Iterator, string(char))
@@ -90,11 +91,11 @@ func (c *CompileTimeForExpr) Eval() *value.Value {
 		count := collection.Int64()
 		for i := int64(0); i < count; i++ {
 			// Устанавливаем переменную итератора ПЕРЕД выполнением Body
-			scope.GlobalScope.Set(c.Iterator, value.NewValue(i))
+			rt.Scope().Set(c.Iterator, value.NewValue(i))
 			
 			
 			// Специальная обработка для compile-time интерполяций
-			processedCode := c.processBodyWithInterpolation()
+			processedCode := c.processBodyWithInterpolation(rt)
 			if processedCode != "" {
 				generatedCode = append(generatedCode, processedCode)
 			}
@@ -131,10 +132,10 @@ func (c *CompileTimeForExpr) processStringInterpolation(text string, varName str
 
 // getBodyCode возвращает исходный код тела цикла как строку без выполнения
 // Пока используем упрощенный подход - получаем результат как строку
-func (c *CompileTimeForExpr) getBodyCode() string {
+func (c *CompileTimeForExpr) getBodyCode(rt *Runtime) string {
 	// Упрощенное решение: выполняем Body и получаем результат
 	// В будущем можно улучшить для получения исходного кода без выполнения
-	result := c.Body.Eval()
+	result := c.Body.Eval(rt)
 	if result.IsString() {
 		return result.String()
 	}
@@ -142,7 +143,7 @@ func (c *CompileTimeForExpr) getBodyCode() string {
 }
 
 // processBodyWithInterpolation обрабатывает Body с учетом compile-time интерполяций
-func (c *CompileTimeForExpr) processBodyWithInterpolation() string {
+func (c *CompileTimeForExpr) processBodyWithInterpolation(rt *Runtime) string {
 	// Проверяем, является ли Body оберткой BodyExpr
 	if bodyExpr, ok := c.Body.(*BodyExpr); ok {
 		// Обрабатываем все statements в BodyExpr
@@ -152,7 +153,7 @@ func (c *CompileTimeForExpr) processBodyWithInterpolation() string {
 			if printExpr, isPrintExpr := stmt.(*PrintExpr); isPrintExpr {
 				if printExpr.Expr != nil {
 					// Обрабатываем выражение специально для compile-time
-					processedArg := c.processArgumentWithInterpolation(printExpr.Expr)
+					processedArg := c.processArgumentWithInterpolation(rt, printExpr.Expr)
 					
 					// Добавляем сгенерированный код
 					results = append(results, fmt.Sprintf("println(\"%s\")", processedArg))
@@ -166,7 +167,7 @@ func (c *CompileTimeForExpr) processBodyWithInterpolation() string {
 					arg := funcCall.args[0]
 					
 					// Обрабатываем аргумент специально для compile-time
-					processedArg := c.processArgumentWithInterpolation(arg)
+					processedArg := c.processArgumentWithInterpolation(rt, arg)
 					
 					// Добавляем сгенерированный код
 					results = append(results, fmt.Sprintf("println(\"%s\")", processedArg))
@@ -185,7 +186,7 @@ func (c *CompileTimeForExpr) processBodyWithInterpolation() string {
 			arg := funcCall.args[0]
 			
 			// Обрабатываем аргумент специально для compile-time
-			processedArg := c.processArgumentWithInterpolation(arg)
+			processedArg := c.processArgumentWithInterpolation(rt, arg)
 			
 			// Возвращаем сгенерированный код
 			return fmt.Sprintf("println(\"%s\")", processedArg)
@@ -193,7 +194,7 @@ func (c *CompileTimeForExpr) processBodyWithInterpolation() string {
 	}
 	
 	// Для других случаев используем стандартную обработку
-	result := c.Body.Eval()
+	result := c.Body.Eval(rt)
 	if result.IsString() {
 		return result.String()
 	}
@@ -201,10 +202,10 @@ func (c *CompileTimeForExpr) processBodyWithInterpolation() string {
 }
 
 // processArgumentWithInterpolation обрабатывает аргументы функций с интерполяциями
-func (c *CompileTimeForExpr) processArgumentWithInterpolation(arg Expr) string {
+func (c *CompileTimeForExpr) processArgumentWithInterpolation(rt *Runtime, arg Expr) string {
 	// Если это StringFormatExpr, обрабатываем его части
 	if stringFormat, ok := arg.(*StringFormatExpr); ok {
-		return c.processStringFormatWithInterpolation(stringFormat)
+		return c.processStringFormatWithInterpolation(rt, stringFormat)
 	}
 	
 	// Если это простая строка, проверяем на возможные необработанные интерполяции
@@ -214,13 +215,13 @@ func (c *CompileTimeForExpr) processArgumentWithInterpolation(arg Expr) string {
 		if strings.Contains(text, c.Iterator) {
 			// Пытаемся восстановить интерполяцию и обработать её
 			restoredText := c.restoreInterpolation(text)
-			return c.processManualInterpolation(restoredText)
+			return c.processManualInterpolation(rt, restoredText)
 		}
 		return text
 	}
 	
 	// Для других типов выполняем стандартную обработку
-	result := arg.Eval()
+	result := arg.Eval(rt)
 	if result.IsString() {
 		return result.String()
 	}
@@ -238,7 +239,7 @@ func (c *CompileTimeForExpr) restoreInterpolation(text string) string {
 }
 
 // processManualInterpolation обрабатывает восстановленную интерполяцию
-func (c *CompileTimeForExpr) processManualInterpolation(text string) string {
+func (c *CompileTimeForExpr) processManualInterpolation(rt *Runtime, text string) string {
 	// Регулярное выражение для поиска ${...}
 	re := regexp.MustCompile(`\$\{([^}]+)\}`)
 	
@@ -248,7 +249,7 @@ func (c *CompileTimeForExpr) processManualInterpolation(text string) string {
 		varName := strings.TrimSpace(match[2 : len(match)-1])
 		
 		// Пытаемся получить значение из scope
-		if val, found := scope.GlobalScope.Get(varName); found {
+		if val, found := rt.Scope().Get(varName); found {
 			if val != nil {
 				return c.valueToString(val)
 			}
@@ -262,7 +263,7 @@ func (c *CompileTimeForExpr) processManualInterpolation(text string) string {
 }
 
 // processStringFormatWithInterpolation обрабатывает StringFormatExpr с compile-time интерполяциями
-func (c *CompileTimeForExpr) processStringFormatWithInterpolation(format *StringFormatExpr) string {
+func (c *CompileTimeForExpr) processStringFormatWithInterpolation(rt *Runtime, format *StringFormatExpr) string {
 	var result strings.Builder
 	
 	// Обрабатываем каждую часть StringFormatExpr
@@ -272,7 +273,7 @@ func (c *CompileTimeForExpr) processStringFormatWithInterpolation(format *String
 			result.WriteString(p.Value)
 		case *VarExpr:
 			// Для переменных ищем значение в текущем scope
-			if val, found := scope.GlobalScope.Get(p.Name); found {
+			if val, found := rt.Scope().Get(p.Name); found {
 				result.WriteString(c.valueToString(val))
 			} else {
 				// Если переменная не найдена, возвращаем её имя
@@ -280,7 +281,7 @@ func (c *CompileTimeForExpr) processStringFormatWithInterpolation(format *String
 			}
 		default:
 			// Для других выражений пытаемся выполнить их
-			res := part.Eval()
+			res := part.Eval(rt)
 			if res != nil {
 				result.WriteString(c.valueToString(res))
 			}
@@ -318,6 +319,7 @@ func (c *CompileTimeForExpr) valueToString(val *value.Value) string {
 // CompileTimeLetExpr представляет compile-time переменную $let
 // Переменная существует только во время выполнения макроса
 type CompileTimeLetExpr struct {
+	Node
 	Name string // Имя переменной
 	Expr Expr   // Выражение для вычисления значения
 }
@@ -329,12 +331,12 @@ func NewCompileTimeLetExpr(name string, expr Expr) *CompileTimeLetExpr {
 	}
 }
 
-func (c *CompileTimeLetExpr) Eval() *value.Value {
+func (c *CompileTimeLetExpr) Eval(rt *Runtime) *value.Value {
 	// Вычисляем значение выражения
-	val := c.Expr.Eval()
+	val := c.Expr.Eval(rt)
 	
 	// Сохраняем переменную в текущей области видимости
-	scope.GlobalScope.Set(c.Name, val)
+	rt.Scope().Set(c.Name, val)
 	
 	// Compile-time переменные не генерируют код, возвращаем пустую строку
 	return value.NewValue("")
@@ -343,6 +345,7 @@ func (c *CompileTimeLetExpr) Eval() *value.Value {
 // CompileTimeWhileExpr представляет compile-time цикл $while
 // Выполняется во время макроса, пока условие истинно
 type CompileTimeWhileExpr struct {
+	Node
 	Condition Expr // Условие цикла
 	Body      Expr // Тело цикла
 }
@@ -354,23 +357,23 @@ func NewCompileTimeWhileExpr(condition, body Expr) *CompileTimeWhileExpr {
 	}
 }
 
-func (c *CompileTimeWhileExpr) Eval() *value.Value {
+func (c *CompileTimeWhileExpr) Eval(rt *Runtime) *value.Value {
 	var generatedCode []string
 	
 	// Создаем новую область видимости для цикла
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
 	
 	// Выполняем цикл пока условие истинно
 	for {
 		// Проверяем условие
-		condition := c.Condition.Eval()
+		condition := c.Condition.Eval(rt)
 		if !condition.IsTruthy() {
 			break
 		}
 		
 		// Выполняем тело цикла
-		result := c.Body.Eval()
+		result := c.Body.Eval(rt)
 		if result.IsString() {
 			generatedCode = append(generatedCode, result.String())
 		}
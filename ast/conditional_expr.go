@@ -1,6 +1,7 @@
 package ast
 
 type ConditionalExpr struct {
+	Node
 	Condition  Expr
 	ThenBranch Expr
 	ElseBranch Expr
@@ -14,10 +15,10 @@ func NewConditionalExpr(cond, thenExpr, elseExpr Expr) *ConditionalExpr {
 	}
 }
 
-func (c *ConditionalExpr) Eval() *Value {
-	condVal := c.Condition.Eval()
+func (c *ConditionalExpr) Eval(rt *Runtime) *Value {
+	condVal := c.Condition.Eval(rt)
 	if condVal.Bool() {
-		return c.ThenBranch.Eval()
+		return c.ThenBranch.Eval(rt)
 	}
-	return c.ElseBranch.Eval()
+	return c.ElseBranch.Eval(rt)
 }
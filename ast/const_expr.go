@@ -1,28 +1,43 @@
 package ast
 
-import "foo_lang/scope"
 
 type ConstExpr struct {
+	Node
 	name string
 	expr Expr
 }
 
-func NewConstExpr(name string, expr Expr) *ConstExpr {
-	c := &ConstExpr{
+func NewConstExpr(rt *Runtime, name string, expr Expr) *ConstExpr {
+	c := NewConstDecl(name, expr)
+	c.define(rt)
+	return c
+}
+
+// NewConstDecl создает объявление константы без вычисления значения
+// (разбор для foo check: инициализатор может иметь побочные эффекты)
+func NewConstDecl(name string, expr Expr) *ConstExpr {
+	return &ConstExpr{
 		name: name,
 		expr: expr,
 	}
-
-	c.define()
-	return c
 }
 
-func (n *ConstExpr) Eval() *Value {
+func (n *ConstExpr) Eval(rt *Runtime) *Value {
 	return nil
 }
 
-func (n *ConstExpr) define() {
-	if scope.GlobalScope.Has(n.name) {
+// GetName возвращает имя константы
+func (n *ConstExpr) GetName() string {
+	return n.name
+}
+
+// GetExpr возвращает выражение значения константы
+func (n *ConstExpr) GetExpr() Expr {
+	return n.expr
+}
+
+func (n *ConstExpr) define(rt *Runtime) {
+	if rt.Scope().Has(n.name) {
 		panic("constant " + n.name + " is already defined")
 	}
 
@@ -30,7 +45,8 @@ func (n *ConstExpr) define() {
 		return
 	}
 
-	val := n.expr.Eval()
+	// const защищает и привязку, и само значение: объекты и массивы замораживаются
+	val := Freeze(n.expr.Eval(rt))
 	val.SetConst(true)
-	scope.GlobalScope.Set(n.name, val)
+	rt.Scope().Set(n.name, val)
 }
@@ -1,24 +1,16 @@
 package ast
 
-import "foo_lang/modules"
-
-// Global parse function registry to avoid circular imports
-var GlobalParseFunc modules.ParseFunc
-
-// Global current file context for imports
-var CurrentFileContext string
-
-// SetGlobalParseFunc sets the parse function for import statements
-func SetGlobalParseFunc(parseFunc modules.ParseFunc) {
-	GlobalParseFunc = parseFunc
+// SetGlobalParseFunc устанавливает функцию разбора кода для GlobalRuntime
+func SetGlobalParseFunc(parseFunc ParseFunc) {
+	GlobalRuntime.SetParseFunc(parseFunc)
 }
 
-// SetCurrentFileContext устанавливает контекст текущего файла для импортов
+// SetCurrentFileContext устанавливает контекст текущего файла для импортов GlobalRuntime
 func SetCurrentFileContext(filePath string) {
-	CurrentFileContext = filePath
+	GlobalRuntime.SetCurrentFile(filePath)
 }
 
-// GetCurrentFileContext возвращает контекст текущего файла
+// GetCurrentFileContext возвращает контекст текущего файла GlobalRuntime
 func GetCurrentFileContext() string {
-	return CurrentFileContext
-}
\ No newline at end of file
+	return GlobalRuntime.CurrentFile()
+}
@@ -0,0 +1,117 @@
+package ast
+
+import "foo_lang/scope"
+
+// DeferExpr - defer expr или defer { ... }: выражение выполняется при выходе
+// из функции (конец тела, return, оператор ? или паника), отложенные выражения
+// одной функции - в обратном порядке. Как в Go, у defer f(x), defer obj.m(x)
+// и defer println(x) объект и аргументы вычисляются в момент defer. Остальные выражения и блоки
+// вычисляются при выходе, но в области видимости, где стоял defer: переменные
+// цикла или вложенного блока доступны и после выхода из него
+type DeferExpr struct {
+	Node
+	Expr Expr
+}
+
+func NewDeferExpr(expr Expr) *DeferExpr {
+	return &DeferExpr{Expr: expr}
+}
+
+// deferredCall - отложенное выражение вместе с областью видимости, в которой
+// оно объявлено
+type deferredCall struct {
+	expr  Expr
+	scope *scope.Scope
+	run   func(rt *Runtime) *Value
+}
+
+func (d *DeferExpr) Eval(rt *Runtime) *Value {
+	if !rt.calls.canDefer() {
+		panic("defer outside of function")
+	}
+	rt.calls.addDeferred(deferredCall{
+		expr:  d.Expr,
+		scope: rt.Scope().CurrentScope(),
+		run:   d.capture(rt),
+	})
+	return nil
+}
+
+// capture вычисляет то, что defer фиксирует сразу, и возвращает функцию,
+// выполняющую остальное при выходе
+func (d *DeferExpr) capture(rt *Runtime) func(rt *Runtime) *Value {
+	switch expr := d.Expr.(type) {
+	case *FuncCallExpr:
+		args := evalAll(rt, expr.args)
+		return func(rt *Runtime) *Value {
+			return CallFunction(rt, expr.funcName, args)
+		}
+	case *MethodCallExpr:
+		obj := expr.Object.Eval(rt)
+		args := evalAll(rt, expr.Args)
+		return func(rt *Runtime) *Value {
+			return CallMethod(rt, obj, expr.MethodName, args)
+		}
+	case *PrintExpr:
+		args := evalAll(rt, expr.args())
+		return func(rt *Runtime) *Value {
+			return expr.write(rt, args)
+		}
+	case *BodyExpr:
+		return func(rt *Runtime) *Value {
+			return evalBlock(rt, expr, nil)
+		}
+	default:
+		return expr.Eval
+	}
+}
+
+func evalAll(rt *Runtime, exprs []Expr) []*Value {
+	values := make([]*Value, len(exprs))
+	for i, expr := range exprs {
+		values[i] = expr.Eval(rt)
+	}
+	return values
+}
+
+// runDeferred выполняет отложенные выражения верхнего кадра. Вызывается
+// функциями foo, пока их область видимости еще на стеке:
+//
+//	defer runDeferred(rt)
+//
+// Как в Go, паника отложенного выражения не мешает выполнить остальные
+// и заменяет панику, с которой функция завершалась
+func runDeferred(rt *Runtime) {
+	pending := rt.calls.takeDeferred()
+	if len(pending) == 0 {
+		return
+	}
+
+	// Стек и позиция ошибки, с которой завершается функция, не должны
+	// указывать на отложенный код
+	cs := rt.calls
+	trace, position := cs.trace, cs.frames[len(cs.frames)-1].Position
+
+	var failure interface{}
+	for i := len(pending) - 1; i >= 0; i-- {
+		call := pending[i]
+		_, thrown := protect(rt, func() *Value {
+			current := rt.Scope().CurrentScope()
+			rt.Scope().RestoreScope(call.scope)
+			defer rt.Scope().RestoreScope(current)
+
+			cs.at(call.expr)
+			return call.run(rt)
+		})
+		if thrown != nil {
+			failure = thrown
+		}
+	}
+
+	if failure != nil {
+		panic(failure)
+	}
+
+	cs.trace = trace
+	cs.frames[len(cs.frames)-1].Position = position
+}
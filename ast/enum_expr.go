@@ -1,45 +1,166 @@
 package ast
 
 import (
-	"foo_lang/scope"
+	"fmt"
 	"foo_lang/value"
+	"strings"
 )
 
+// EnumField - поле данных варианта enum: r в Circle(r: float)
+type EnumField struct {
+	Name string
+	Type string // "any", если тип не указан
+}
+
 // EnumExpr представляет определение enum
 type EnumExpr struct {
+	Node
 	Name   string
 	Values []string
+	Fields [][]EnumField // данные вариантов (nil для варианта без скобок), параллельно Values
 }
 
-func NewEnumExpr(name string, values []string) *EnumExpr {
+//	enum Color { RED, GREEN, BLUE }
+//	enum Shape { Circle(r: float), Rect(w: float, h: float), Empty }
+func NewEnumExpr(name string, values []string, fields [][]EnumField) *EnumExpr {
 	return &EnumExpr{
 		Name:   name,
 		Values: values,
+		Fields: fields,
 	}
 }
 
-func (e *EnumExpr) Eval() *Value {
+func (e *EnumExpr) Eval(rt *Runtime) *Value {
 	// Создаём объект с enum значениями для обратной совместимости
-	enumObj := make(map[string]*Value)
-	
-	for i, value := range e.Values {
-		enumObj[value] = NewValue(int64(i))
-	}
+	enumObj := value.NewObject()
 	
 	// Создаем TypeInfo для enum
 	enumTypeInfo := NewEnumTypeInfo(e.Name, e.Values)
+
+	if e.hasPayloads() {
+		// Алгебраический тип: варианты с данными - конструкторы, остальные - готовые значения
+		enumTypeInfo.Variants = make(map[string][]*FieldInfo)
+		for i, variant := range e.Values {
+			fields := e.Fields[i]
+			enumTypeInfo.Variants[variant] = variantFieldInfo(fields)
+
+			if fields == nil {
+				enumObj.Set(variant, NewValue(&EnumVariant{Enum: e.Name, Variant: variant}))
+			} else {
+				enumObj.Set(variant, NewValue(e.constructor(rt, variant, fields)))
+			}
+		}
+	} else {
+		for i, variant := range e.Values {
+			enumObj.Set(variant, NewValue(int64(i)))
+		}
+	}
 	
 	// Сохраняем enum как объект для обычного использования
-	scope.GlobalScope.Set(e.Name, NewValue(enumObj))
+	rt.Scope().Set(e.Name, NewValue(enumObj))
 	
 	// ДОПОЛНИТЕЛЬНО: Сохраняем TypeInfo для использования в type() и макросах
-	scope.GlobalScope.Set(e.Name+"__TypeInfo", value.NewValue(enumTypeInfo))
+	rt.Scope().Set(e.Name+"__TypeInfo", value.NewValue(enumTypeInfo))
 	
 	return value.NewValue(enumTypeInfo)
 }
 
+// hasPayloads сообщает, есть ли у enum варианты с данными.
+// Enum без данных остается набором int64 порядковых номеров
+func (e *EnumExpr) hasPayloads() bool {
+	for _, fields := range e.Fields {
+		if fields != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// constructor создает функцию Shape.Circle(2.0), проверяющую количество и типы данных
+func (e *EnumExpr) constructor(rt *Runtime, variant string, fields []EnumField) func([]*value.Value) *value.Value {
+	name := e.Name + "." + variant
+
+	return func(args []*value.Value) *value.Value {
+		if len(args) != len(fields) {
+			panic(fmt.Sprintf("%s() expects %d argument(s), got %d", name, len(fields), len(args)))
+		}
+
+		for i, field := range fields {
+			if field.Type == "any" {
+				continue
+			}
+			if err := validateVariableType(rt, args[i], field.Type); err != nil {
+				panic(fmt.Sprintf("%s() field '%s': %v", name, field.Name, err))
+			}
+		}
+
+		values := make([]*Value, len(args))
+		copy(values, args)
+		return NewValue(&EnumVariant{Enum: e.Name, Variant: variant, Fields: fields, Values: values})
+	}
+}
+
+// variantFieldInfo описывает данные варианта для TypeInfo. Тип поля хранится по имени
+func variantFieldInfo(fields []EnumField) []*FieldInfo {
+	if fields == nil {
+		return nil
+	}
+
+	info := make([]*FieldInfo, len(fields))
+	for i, field := range fields {
+		info[i] = &FieldInfo{Name: field.Name, Type: NewPrimitiveTypeInfo(field.Type)}
+	}
+	return info
+}
+
+// EnumVariant - значение варианта алгебраического enum: Shape.Circle(2.0) или Shape.Empty
+type EnumVariant struct {
+	Enum    string
+	Variant string
+	Fields  []EnumField
+	Values  []*Value
+}
+
+// Get возвращает данные варианта по имени поля
+func (v *EnumVariant) Get(name string) (*Value, bool) {
+	for i, field := range v.Fields {
+		if field.Name == name {
+			return v.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// Equals сравнивает варианты структурно: Shape.Circle(1) == Shape.Circle(1)
+func (v *EnumVariant) Equals(other any) bool {
+	o, ok := other.(*EnumVariant)
+	if !ok || v.Enum != o.Enum || v.Variant != o.Variant || len(v.Values) != len(o.Values) {
+		return false
+	}
+	for i := range v.Values {
+		if !value.Equal(v.Values[i], o.Values[i]).Bool() {
+			return false
+		}
+	}
+	return true
+}
+
+// String форматирует вариант как Shape.Circle(2) или Shape.Empty
+func (v *EnumVariant) String() string {
+	if v.Fields == nil {
+		return v.Enum + "." + v.Variant
+	}
+
+	values := make([]string, len(v.Values))
+	for i, val := range v.Values {
+		values[i] = FormatValue(val.Any())
+	}
+	return fmt.Sprintf("%s.%s(%s)", v.Enum, v.Variant, strings.Join(values, ", "))
+}
+
 // EnumValueExpr представляет доступ к значению enum (Color.RED)
 type EnumValueExpr struct {
+	Node
 	EnumName string
 	Value    string
 }
@@ -51,17 +172,17 @@ func NewEnumValueExpr(enumName, value string) *EnumValueExpr {
 	}
 }
 
-func (e *EnumValueExpr) Eval() *Value {
+func (e *EnumValueExpr) Eval(rt *Runtime) *Value {
 	// Получаем enum из scope
-	enumVal, ok := scope.GlobalScope.Get(e.EnumName)
+	enumVal, ok := rt.Scope().Get(e.EnumName)
 	if !ok {
 		panic("enum '" + e.EnumName + "' is not defined")
 	}
 	
 	// Проверяем, что это объект
-	if enumObj, ok := enumVal.Any().(map[string]*Value); ok {
-		if value, exists := enumObj[e.Value]; exists {
-			return value
+	if enumObj, ok := enumVal.Any().(*value.Object); ok {
+		if variant, exists := enumObj.Get(e.Value); exists {
+			return variant
 		}
 		panic("enum value '" + e.Value + "' does not exist in " + e.EnumName)
 	}
@@ -2,7 +2,6 @@ package ast
 
 import (
 	"fmt"
-	"runtime"
 	"strings"
 )
 
@@ -11,7 +10,7 @@ type ErrorInfo struct {
 	Type        string   // Тип ошибки (RuntimeError, TypeError, etc.)
 	Message     string   // Сообщение об ошибке
 	Code        string   // Код ошибки (E001, E002, etc.)
-	StackTrace  []string // Стек вызовов
+	StackTrace  []string // Стек вызовов foo ("at fib (math.foo:12:5)"), см. WithStackTrace
 	Context     string   // Контекст (функция, файл и т.д.)
 	Suggestions []string // Предложения по исправлению
 }
@@ -22,18 +21,32 @@ func NewErrorInfo(errorType, message, code string) *ErrorInfo {
 		Type:        errorType,
 		Message:     message,
 		Code:        code,
-		StackTrace:  getCaller(),
+		StackTrace:  []string{},
 		Context:     "",
 		Suggestions: []string{},
 	}
 }
 
+// String возвращает "Type: message" - так ошибка выглядит внутри Err(...)
+func (e *ErrorInfo) String() string {
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
 // WithContext добавляет контекст к ошибке
 func (e *ErrorInfo) WithContext(context string) *ErrorInfo {
 	e.Context = context
 	return e
 }
 
+// WithStackTrace заполняет стек вызовов foo (функция, файл, строка) из rt
+func (e *ErrorInfo) WithStackTrace(rt *Runtime) *ErrorInfo {
+	e.StackTrace = e.StackTrace[:0]
+	for _, frame := range rt.StackTrace() {
+		e.StackTrace = append(e.StackTrace, frame.String())
+	}
+	return e
+}
+
 // WithSuggestion добавляет предложение по исправлению
 func (e *ErrorInfo) WithSuggestion(suggestion string) *ErrorInfo {
 	e.Suggestions = append(e.Suggestions, suggestion)
@@ -74,31 +87,27 @@ func (e *ErrorInfo) ToString() string {
 	return sb.String()
 }
 
-// getCaller получает стек вызовов
-func getCaller() []string {
-	var frames []string
-	for i := 2; i < 10; i++ { // Пропускаем getCaller() и вызывающую функцию
-		pc, file, line, ok := runtime.Caller(i)
-		if !ok {
-			break
-		}
-		
-		// Получаем имя функции
-		funcName := "unknown"
-		if fn := runtime.FuncForPC(pc); fn != nil {
-			funcName = fn.Name()
-		}
-		
-		// Упрощаем путь к файлу
-		parts := strings.Split(file, "/")
-		if len(parts) > 2 {
-			file = ".../" + strings.Join(parts[len(parts)-2:], "/")
-		}
-		
-		frame := fmt.Sprintf("%s:%d in %s", file, line, funcName)
-		frames = append(frames, frame)
+// ToObject представляет ошибку объектом foo: {type, message, code, context, suggestions, stack}.
+// Так ошибка видна в блоке catch
+func (e *ErrorInfo) ToObject() *Value {
+	suggestions := make([]any, len(e.Suggestions))
+	for i, suggestion := range e.Suggestions {
+		suggestions[i] = suggestion
+	}
+
+	stack := make([]any, len(e.StackTrace))
+	for i, frame := range e.StackTrace {
+		stack[i] = frame
 	}
-	return frames
+
+	return NewValue(map[string]*Value{
+		"type":        NewValue(e.Type),
+		"message":     NewValue(e.Message),
+		"code":        NewValue(e.Code),
+		"context":     NewValue(e.Context),
+		"suggestions": NewValue(suggestions),
+		"stack":       NewValue(stack),
+	})
 }
 
 // Предопределенные типы ошибок
@@ -111,6 +120,10 @@ const (
 	AttributeError  = "AttributeError"
 	ValueError      = "ValueError"
 	OverflowError   = "OverflowError"
+	IOError         = "IOError"
+	NetworkError    = "NetworkError"
+	ParseError      = "ParseError"
+	UserError       = "Error" // значение, выброшенное оператором throw
 )
 
 // Коды ошибок
@@ -138,6 +151,16 @@ const (
 	E401_INVALID_VALUE        = "E401"
 	E402_EMPTY_CONTAINER      = "E402"
 	E403_READONLY_VARIABLE    = "E403"
+	
+	// Ошибки окружения (восстанавливаемые ошибки встроенных функций)
+	E501_IO_FAILURE           = "E501"
+	E502_NETWORK_FAILURE      = "E502"
+	E503_PARSE_FAILURE        = "E503"
+	E504_INVALID_PATTERN      = "E504"
+	
+	// Ошибки выполнения
+	E901_THROWN               = "E901"
+	E999_RUNTIME_PANIC        = "E999"
 )
 
 // Функции-хелперы для создания типичных ошибок
@@ -219,7 +242,7 @@ func NewEmptyContainerError(operation, container string) *Value {
 }
 
 // SafeEval безопасно выполняет выражение и возвращает Result
-func SafeEval(expr Expr, context string) *Value {
+func SafeEval(rt *Runtime, expr Expr, context string) *Value {
 	// Используем recover для перехвата panic
 	defer func() {
 		if r := recover(); r != nil {
@@ -235,7 +258,7 @@ func SafeEval(expr Expr, context string) *Value {
 	}()
 	
 	// Выполняем выражение
-	value := expr.Eval()
+	value := expr.Eval(rt)
 	
 	// Оборачиваем в Ok если это не Result
 	if _, isResult := value.Any().(*ResultValue); !isResult {
@@ -1,14 +1,21 @@
 package ast
 
-import "foo_lang/scope"
+import "foo_lang/modules"
 
 // ExportExpr represents export statements:
 // export fn add(a, b) { return a + b }
 // export let PI = 3.14159
 // export enum Color { RED, GREEN, BLUE }
+// export struct Point { x: int, y: int }
+// export interface Shape { fn area() -> float }
+// export type UserId = int
+// export default fn main() { }
+// export default 42
 type ExportExpr struct {
-	Declaration Expr // The declaration being exported (FuncExpr, LetExpr, EnumExpr, etc.)
-	Name        string // Name of the exported item
+	Node
+	Declaration Expr   // The declaration being exported (FuncExpr, LetExpr, EnumExpr, etc.) or the default value
+	Name        string // Name of the exported item, empty for export default <expr>
+	Default     bool   // export default: the item is exported as "default"
 }
 
 func NewExportExpr(declaration Expr, name string) *ExportExpr {
@@ -18,19 +25,97 @@ func NewExportExpr(declaration Expr, name string) *ExportExpr {
 	}
 }
 
-func (e *ExportExpr) Eval() *Value {
+// NewDefaultExportExpr creates export default; name is set when the default
+// export is a declaration: export default fn handler() { }
+func NewDefaultExportExpr(declaration Expr, name string) *ExportExpr {
+	return &ExportExpr{
+		Declaration: declaration,
+		Name:        name,
+		Default:     true,
+	}
+}
+
+func (e *ExportExpr) Eval(rt *Runtime) *Value {
 	// Execute the declaration first
-	e.Declaration.Eval()
-	
-	// Mark this item as exported in the module's export table
-	// For now, store in a special scope with "__export_" prefix
+	result := e.Declaration.Eval(rt)
+
+	var export *modules.Export
 	if e.Name != "" {
-		// Get the actual value from global scope and mark it as exported
-		if val, exists := scope.GlobalScope.Get(e.Name); exists {
-			scope.GlobalScope.Set("__export_"+e.Name, val)
+		// Get the declared value from the module scope
+		export = exportOf(rt, e.Name)
+		if export == nil {
+			return nil
 		}
+	} else {
+		export = &modules.Export{Kind: modules.ValueExport, Value: result}
+	}
+	if e.Default {
+		export.Name = modules.DefaultExport
 	}
-	
+
+	rt.Exports().Set(export)
+
 	// Export statements always return nil
 	return nil
-}
\ No newline at end of file
+}
+
+// exportOf builds an export entry for a name declared in the current scope.
+// Structs, interfaces, enums and type aliases are type exports; enums and
+// aliases carry their Name__TypeInfo entry along
+func exportOf(rt *Runtime, name string) *modules.Export {
+	val, exists := rt.Scope().Get(name)
+	if !exists {
+		return nil
+	}
+
+	export := &modules.Export{Name: name, Kind: modules.ValueExport, Value: val}
+	if typeInfo, found := rt.Scope().Get(name + modules.TypeInfoSuffix); found {
+		export.Kind = modules.TypeExport
+		export.Type = typeInfo
+	}
+	switch v := val.Any().(type) {
+	case *InterfaceDefinition:
+		export.Kind = modules.TypeExport
+	case *TypeInfo:
+		if v.Kind == "struct" {
+			export.Kind = modules.TypeExport
+		}
+	}
+	return export
+}
+
+// ExportListExpr represents export lists and re-exports:
+// export { a, b as c }
+// export { a, b as c } from "./other"
+// export * from "./other"
+type ExportListExpr struct {
+	Node
+	Items []modules.Binding // Exported names with their export names; empty for export *
+	Path  string            // Module to re-export from, empty for local names
+}
+
+func NewExportListExpr(items []modules.Binding, path string) *ExportListExpr {
+	return &ExportListExpr{
+		Items: items,
+		Path:  path,
+	}
+}
+
+func (e *ExportListExpr) Eval(rt *Runtime) *Value {
+	if e.Path != "" {
+		if err := rt.ReExport(e.Path, e.Items); err != nil {
+			panic(err.Error())
+		}
+		return nil
+	}
+
+	for _, item := range e.Items {
+		export := exportOf(rt, item.Name)
+		if export == nil {
+			panic("cannot export undefined '" + item.Name + "'")
+		}
+		export.Name = item.Local
+		rt.Exports().Set(export)
+	}
+	return nil
+}
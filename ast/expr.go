@@ -7,5 +7,5 @@ type Value = value.Value
 var NewValue = value.NewValue
 
 type Expr interface {
-	Eval() *Value
+	Eval(rt *Runtime) *Value
 }
@@ -2,7 +2,6 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 )
 
@@ -21,22 +20,38 @@ type ExtensionMethodInfo struct {
 // extension string {
 //     fn isPalindrome() -> bool { ... }
 // }
+//
+// Расширение коллекции может объявить параметр типа элементов:
+// extension Array<T> { fn first() -> T { ... } }
 type ExtensionExpr struct {
-	TypeName string                 // Имя типа для расширения (string, int, etc)
-	Methods  []*ExtensionMethodInfo // Методы расширения
+	Node
+	TypeName   string                 // Имя типа для расширения (string, int, etc)
+	TypeParams []string               // Параметр типа элементов: Array<T>
+	Methods    []*ExtensionMethodInfo // Методы расширения
 }
 
-func (e *ExtensionExpr) Eval() *value.Value {
+// extensionTypeName приводит имя generic коллекции к имени типа значения
+func extensionTypeName(typeName string) string {
+	if typeName == "Array" {
+		return "array"
+	}
+	return typeName
+}
+
+func (e *ExtensionExpr) Eval(rt *Runtime) *value.Value {
+	typeName := extensionTypeName(e.TypeName)
+
 	// Регистрируем методы расширения для типа
 	for _, method := range e.Methods {
 		// Создаем функцию-обертку, которая будет принимать this как первый параметр
 		extensionMethod := &ExtensionMethodWrapper{
-			Method: method,
-			TypeName: e.TypeName,
+			Method:     method,
+			TypeName:   e.TypeName,
+			TypeParams: e.TypeParams,
 		}
 		
 		// Регистрируем метод в системе типов
-		value.RegisterExtensionMethod(e.TypeName, method.Name, extensionMethod)
+		rt.Extensions.Register(typeName, method.Name, extensionMethod)
 	}
 	
 	// Extension не возвращает значение, просто регистрирует методы
@@ -49,38 +64,97 @@ func (e *ExtensionExpr) String() string {
 
 // ExtensionMethodWrapper оборачивает метод расширения для вызова с правильным контекстом
 type ExtensionMethodWrapper struct {
-	Method   *ExtensionMethodInfo
-	TypeName string
+	Method     *ExtensionMethodInfo
+	TypeName   string
+	TypeParams []string // параметр типа элементов, связывается по получателю
+}
+
+// elementTypeArg выводит тип элементов массива: общий тип всех элементов,
+// float для смеси int и float, any для разнородных или пустых массивов
+func elementTypeArg(rt *Runtime, receiver *Value) string {
+	items, ok := receiver.Any().([]any)
+	if !ok || len(items) == 0 {
+		return "any"
+	}
+
+	bound := bindTypeArg(rt, NewValue(items[0]))
+	for _, item := range items[1:] {
+		next := bindTypeArg(rt, NewValue(item))
+		switch {
+		case next == bound:
+		case bound == "int" && next == "float", bound == "float" && next == "int":
+			bound = "float"
+		default:
+			return "any"
+		}
+	}
+	return bound
 }
 
-func (w *ExtensionMethodWrapper) Call(receiver *value.Value, args []*value.Value) *value.Value {
+func (w *ExtensionMethodWrapper) Call(rt *Runtime, receiver *value.Value, args []*value.Value) (result *value.Value) {
+	rt.calls.push(w.TypeName+"."+w.Method.Name, w.Method.Body)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
+
 	// Сохраняем текущую область видимости и создаем новую
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+	defer runDeferred(rt)
 	
 	// Добавляем this в область видимости
-	scope.GlobalScope.Set("this", receiver)
+	rt.Scope().Set("this", receiver)
+
+	// Связываем параметр типа с типом элементов получателя: Array<int>
+	var typeArgs []string
+	if len(w.TypeParams) > 0 {
+		typeArgs = []string{elementTypeArg(rt, receiver)}
+		bindTypeArgs(rt, w.TypeParams, typeArgs)
+	}
 	
 	// Добавляем параметры метода
 	for i, param := range w.Method.Params {
 		if i < len(args) {
-			scope.GlobalScope.Set(param, args[i])
+			if i < len(w.Method.ParamTypes) && MentionsTypeParam(w.Method.ParamTypes[i], w.TypeParams) {
+				expected := SubstituteTypeParams(w.Method.ParamTypes[i], w.TypeParams, typeArgs)
+				if err := validateVariableType(rt, args[i], expected); err != nil {
+					panic(fmt.Sprintf("method '%s.%s' parameter '%s': %v",
+						FormatGenericType(w.TypeName, typeArgs), w.Method.Name, param, err))
+				}
+			}
+			rt.Scope().Set(param, args[i])
 		} else if w.Method.Defaults != nil && i < len(w.Method.Defaults) && w.Method.Defaults[i] != nil {
 			// Используем значение по умолчанию
-			defaultValue := w.Method.Defaults[i].Eval()
-			scope.GlobalScope.Set(param, defaultValue)
+			defaultValue := w.Method.Defaults[i].Eval(rt)
+			rt.Scope().Set(param, defaultValue)
 		} else {
-			scope.GlobalScope.Set(param, value.NewValue(nil))
+			rt.Scope().Set(param, value.NewValue(nil))
 		}
 	}
 	
-	// Выполняем тело метода
-	result := w.Method.Body.Eval()
+	// Выполняем тело метода: return внутри if или цикла завершает метод
+	if body, ok := w.Method.Body.(*BodyExpr); ok {
+		for _, stmt := range body.Statments {
+			rt.calls.at(stmt)
+			if result = stmt.Eval(rt); result != nil && result.IsReturn() {
+				break
+			}
+		}
+	} else {
+		result = w.Method.Body.Eval(rt)
+	}
 	
 	// Убираем флаг возврата, если он установлен
 	if result != nil && result.IsReturn() {
 		result.SetReturn(false)
 	}
+
+	if MentionsTypeParam(w.Method.ReturnType, w.TypeParams) {
+		expected := SubstituteTypeParams(w.Method.ReturnType, w.TypeParams, typeArgs)
+		if err := validateVariableType(rt, orNull(result), expected); err != nil {
+			panic(fmt.Sprintf("method '%s.%s' return type error: %v",
+				FormatGenericType(w.TypeName, typeArgs), w.Method.Name, err))
+		}
+	}
 	
 	return result
 }
\ No newline at end of file
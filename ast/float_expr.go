@@ -1,6 +1,7 @@
 package ast
 
 type FloatExpr struct {
+	Node
 	Value *Value
 }
 
@@ -8,6 +9,6 @@ func NewFloat64Expr(value float64) *FloatExpr {
 	return &FloatExpr{Value: NewValue(value)}
 }
 
-func (n *FloatExpr) Eval() *Value {
+func (n *FloatExpr) Eval(rt *Runtime) *Value {
 	return n.Value
 }
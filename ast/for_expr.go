@@ -1,13 +1,14 @@
 package ast
 
-import "foo_lang/scope"
 
 
 type ForExpr struct {
+	Node
 	InitExpr      Expr
 	ConditionExpr Expr
 	StepExpr      Expr
 	BodyExpr      Expr
+	Label         string
 }
 
 func NewForExpr(init, condition, step, body Expr) *ForExpr {
@@ -19,77 +20,97 @@ func NewForExpr(init, condition, step, body Expr) *ForExpr {
 	}
 }
 
-func (f *ForExpr) Eval() *Value {
+func (f *ForExpr) SetLabel(label string) {
+	f.Label = label
+}
+
+func (f *ForExpr) Eval(rt *Runtime) *Value {
 	// Создаём локальную область видимости для цикла
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
 
 	statments := f.BodyExpr.(*BodyExpr).Statments
 	var yield []any
 
-	f.InitExpr.Eval()
+	f.InitExpr.Eval(rt)
 	for {
-		if !f.ConditionExpr.Eval().Bool() {
+		if !f.ConditionExpr.Eval(rt).Bool() {
 			break
 		}
 		
-		// 🔥 ИСПРАВЛЕНИЕ: Создаем новую область видимости для каждой итерации
-		// Это изолирует переменные, объявленные с let внутри цикла
-		scope.GlobalScope.Push()
-		
-		iterationBreak := false
-		for _, statment := range statments {
-			switch stm := statment.(type) {
-			case *ReturnExpr:
-				val := stm.Eval()
-				val.SetReturn(true)
-				scope.GlobalScope.Pop() // Очищаем область итерации перед return
-				return val
-			case *BreakExpr:
-				// Break out of the loop immediately
-				iterationBreak = true
-				break
-			case *YieldExpr:
-				// Проверяем, если уже был break, не выполняем yield
-				if !iterationBreak {
-					yield = append(yield, stm.Eval().Any())
-				}
-			default:
-				val := stm.Eval()
-				if val != nil {
-					if val.IsReturn() {
-						scope.GlobalScope.Pop() // Очищаем область итерации перед return
-						return val
-					}
-					if val.IsBreak() {
-						iterationBreak = true
-						break
-					}
-					if val.IsYield() {
-						// Проверяем, если уже был break, не выполняем yield
-						if !iterationBreak {
-							yield = append(yield, val.Any())
-						}
-					}
-				}
-			}
-			
-			// Если был break, прекращаем обработку остальных statement в этой итерации
-			if iterationBreak {
-				break
-			}
+		exit, brk := loopIteration(rt, f.Label, statments, nil, &yield)
+		if exit != nil {
+			return exit
 		}
 		
-		// Очищаем область видимости итерации
-		scope.GlobalScope.Pop()
-		
 		// Проверяем, нужно ли выйти из цикла
-		if iterationBreak {
+		if brk {
 			break
 		}
 		
-		f.StepExpr.Eval()
+		f.StepExpr.Eval(rt)
 	}
 
 	return NewValue(yield)
 }
+
+// LabeledLoop - цикл, который может быть целью break label / continue label
+type LabeledLoop interface {
+	Expr
+	SetLabel(label string)
+}
+
+// loopIteration выполняет одну итерацию тела цикла label. bind (если задан) объявляет
+// переменные цикла в области итерации. Значения yield добавляются в yield.
+// exit != nil - цикл завершается с этим значением: return или break/continue,
+// адресованные внешнему циклу; brk - break этого цикла. continue этого цикла
+// просто заканчивает итерацию
+func loopIteration(rt *Runtime, label string, statments []Expr, bind func(), yield *[]any) (exit *Value, brk bool) {
+	// 🔥 ИСПРАВЛЕНИЕ: Создаем новую область видимости для каждой итерации
+	// Это изолирует переменные, объявленные с let внутри цикла
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+
+	if bind != nil {
+		bind()
+	}
+
+	for _, statment := range statments {
+		rt.calls.at(statment)
+
+		if ret, ok := statment.(*ReturnExpr); ok {
+			val := ret.Eval(rt)
+			val.SetReturn(true)
+			return val, false
+		}
+
+		val := statment.Eval(rt)
+		if val == nil {
+			continue
+		}
+
+		switch {
+		case val.IsReturn():
+			return val, false
+		case val.IsBreak():
+			if targets(val, label) {
+				return nil, true
+			}
+			return val, false
+		case val.IsContinue():
+			if targets(val, label) {
+				return nil, false
+			}
+			return val, false
+		case val.IsYield():
+			*yield = append(*yield, val.Any())
+		}
+	}
+
+	return nil, false
+}
+
+// targets сообщает, что break/continue относится к циклу с меткой label
+func targets(control *Value, label string) bool {
+	return control.Label() == "" || control.Label() == label
+}
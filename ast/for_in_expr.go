@@ -0,0 +1,151 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+)
+
+// ForInExpr - цикл по коллекции через протокол итераторов value.Iterator
+//
+//	for item in [1, 2, 3] { ... }
+//	for i, item in items { ... }
+//	for key, val in obj { ... }
+//	for i in 0..10 { ... }
+type ForInExpr struct {
+	Node
+	KeyName  string // индекс или ключ; пустой для формы с одной переменной
+	ItemName string
+	Iterable Expr
+	BodyExpr Expr
+	Label    string
+}
+
+func NewForInExpr(keyName, itemName string, iterable, body Expr) *ForInExpr {
+	return &ForInExpr{
+		KeyName:  keyName,
+		ItemName: itemName,
+		Iterable: iterable,
+		BodyExpr: body,
+	}
+}
+
+func (f *ForInExpr) SetLabel(label string) {
+	f.Label = label
+}
+
+func (f *ForInExpr) Eval(rt *Runtime) *Value {
+	collection := f.Iterable.Eval(rt)
+	iter := NewIterator(rt, collection)
+
+	// Для объекта единственная переменная получает ключ, как в for key in obj
+	_, isObject := collection.Any().(*value.Object)
+	single := f.KeyName == ""
+
+	statments := f.BodyExpr.(*BodyExpr).Statments
+	var yield []any
+
+	for {
+		key, item, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		bind := func() {
+			switch {
+			case single && isObject:
+				rt.Scope().Set(f.ItemName, key)
+			case single:
+				rt.Scope().Set(f.ItemName, orNull(item))
+			default:
+				rt.Scope().Set(f.KeyName, key)
+				rt.Scope().Set(f.ItemName, orNull(item))
+			}
+		}
+
+		exit, brk := loopIteration(rt, f.Label, statments, bind, &yield)
+		if exit != nil {
+			return exit
+		}
+		if brk {
+			break
+		}
+	}
+
+	return NewValue(yield)
+}
+
+// NewIterator возвращает итератор значения: встроенные коллекции обходятся
+// через value.NewIterator, структуры - через методы iter()/next() из impl
+func NewIterator(rt *Runtime, v *Value) value.Iterator {
+	if iter, ok := value.NewIterator(v); ok {
+		return iter
+	}
+
+	if structObj, ok := v.Any().(*StructObject); ok {
+		if findStructMethod(rt, structObj, "iter") != nil {
+			v = CallMethod(rt, v, "iter", nil)
+			if iter, ok := value.NewIterator(v); ok {
+				return iter
+			}
+		}
+
+		if iterObj, ok := v.Any().(*StructObject); ok && findStructMethod(rt, iterObj, "next") != nil {
+			return &structIterator{rt: rt, obj: v}
+		}
+	}
+
+	panic(fmt.Sprintf("value of type %s is not iterable", value.GetValueTypeName(v)))
+}
+
+// structIterator - итератор пользовательской структуры: next() возвращает
+// {value: x, done: false} для очередного элемента и {done: true} в конце
+type structIterator struct {
+	rt    *Runtime
+	obj   *Value
+	count int64
+}
+
+func (it *structIterator) Next() (*Value, *Value, bool) {
+	result := CallMethod(it.rt, it.obj, "next", nil)
+
+	step, ok := result.Any().(*value.Object)
+	if !ok {
+		panic(fmt.Sprintf("next() must return {value, done}, got %s", FormatValue(result.Any())))
+	}
+
+	if done, exists := step.Get("done"); exists && done.Bool() {
+		return nil, nil, false
+	}
+
+	it.count++
+	item, _ := step.Get("value")
+	return NewValue(it.count - 1), orNull(item), true
+}
+
+// RangeExpr - диапазон start..end (end не включается) или start..=end (end включается)
+type RangeExpr struct {
+	Node
+	Start     Expr
+	End       Expr
+	Inclusive bool
+}
+
+func NewRangeExpr(start, end Expr, inclusive bool) *RangeExpr {
+	return &RangeExpr{Start: start, End: end, Inclusive: inclusive}
+}
+
+func (r *RangeExpr) Eval(rt *Runtime) *Value {
+	start, end := r.Start.Eval(rt), r.End.Eval(rt)
+	if !start.IsInt64() || !end.IsInt64() {
+		panic(fmt.Sprintf("range bounds must be int, got %s%s%s",
+			value.GetValueTypeName(start), r.operator(), value.GetValueTypeName(end)))
+	}
+	return NewValue(value.NewRange(start.Int64(), end.Int64(), r.Inclusive))
+}
+
+func (r *RangeExpr) operator() string {
+	if r.Inclusive {
+		return "..="
+	}
+	return ".."
+}
@@ -0,0 +1,96 @@
+package ast
+
+import "foo_lang/value"
+
+// Freeze глубоко замораживает значение и возвращает его же: поля объектов и
+// структур больше нельзя присваивать, а вложенные объекты, массивы и структуры
+// замораживаются рекурсивно. Элементы замороженного массива при чтении
+// (arr[i], for-in) получают флаг заморозки от массива
+func Freeze(v *Value) *Value {
+	if v == nil || v.IsFrozen() {
+		return v
+	}
+	v.SetFrozen(true)
+	freezeData(v.Any())
+	return v
+}
+
+func freezeData(data any) {
+	switch d := data.(type) {
+	case *value.Object:
+		for _, field := range d.Fields() {
+			Freeze(field)
+		}
+	case []any:
+		for _, item := range d {
+			freezeData(item)
+		}
+	case *StructObject:
+		if !d.Frozen {
+			d.Frozen = true
+			for _, field := range d.Fields {
+				Freeze(field)
+			}
+		}
+	}
+}
+
+// IsFrozen сообщает, можно ли изменять значение: персистентные List и Map
+// неизменяемы всегда
+func IsFrozen(v *Value) bool {
+	switch data := v.Any().(type) {
+	case *value.List, *value.Map:
+		return true
+	case *StructObject:
+		return data.Frozen || v.IsFrozen()
+	}
+	return v.IsFrozen()
+}
+
+// frozenElement оборачивает элемент коллекции; элемент замороженной
+// коллекции тоже заморожен
+func frozenElement(collection *Value, item any) *Value {
+	elem := NewValue(item)
+	if IsFrozen(collection) {
+		elem.SetFrozen(true)
+	}
+	return elem
+}
+
+// Clone возвращает изменяемую глубокую копию значения, в том числе замороженного
+func Clone(v *Value) *Value {
+	return NewValue(cloneData(v.Any()))
+}
+
+// cloneData глубоко копирует объекты, массивы и структуры: копия не
+// заморожена и не разделяет изменяемых данных с оригиналом
+func cloneData(data any) any {
+	switch d := data.(type) {
+	case *value.Object:
+		result := value.NewObject()
+		for _, key := range d.Keys() {
+			field, _ := d.Get(key)
+			result.Set(key, NewValue(cloneData(field.Any())))
+		}
+		return result
+	case []any:
+		result := make([]any, len(d))
+		for i, item := range d {
+			result[i] = cloneData(item)
+		}
+		return result
+	case *StructObject:
+		fields := make(map[string]*Value, len(d.Fields))
+		for key, field := range d.Fields {
+			fields[key] = NewValue(cloneData(field.Any()))
+		}
+		clone := NewStructObject(d.TypeInfo, fields)
+		clone.TypeArgs = d.TypeArgs
+		return clone
+	case *value.Set:
+		return value.NewSet().Union(d)
+	case *value.Tuple:
+		return value.NewTuple(cloneData(d.Items()).([]any)...)
+	}
+	return data
+}
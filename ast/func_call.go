@@ -1,11 +1,9 @@
 package ast
 
-import (
-	"fmt"
-	"foo_lang/scope"
-)
+import "fmt"
 
 type FuncCallExpr struct {
+	Node
 	funcName string
 	args     []Expr
 }
@@ -20,46 +18,63 @@ func NewFuncCallExpr(funcName string, args []Expr) *FuncCallExpr {
 	return f
 }
 
-func (f *FuncCallExpr) Eval() *Value {
+// FuncName возвращает имя вызываемой функции
+func (f *FuncCallExpr) FuncName() string {
+	return f.funcName
+}
+
+// Args возвращает выражения аргументов вызова
+func (f *FuncCallExpr) Args() []Expr {
+	return f.args
+}
+
+func (f *FuncCallExpr) Eval(rt *Runtime) *Value {
 	// Сначала вычисляем аргументы
 	evalArgs := make([]*Value, len(f.args))
 	for i, arg := range f.args {
-		evalArgs[i] = arg.Eval()
+		evalArgs[i] = arg.Eval(rt)
 	}
-	
+
+	rt.calls.at(f)
+	return callResult(CallFunction(rt, f.funcName, evalArgs))
+}
+
+// CallFunction вызывает функцию по имени с уже вычисленными аргументами,
+// учитывая перегрузки, типизированные замыкания и встроенные Go-функции
+func CallFunction(rt *Runtime, funcName string, evalArgs []*Value) *Value {
 	// Если функция зарегистрирована как перегружаемая, используем систему перегрузки
-	if IsOverloadedMethod(f.funcName) {
+	if rt.IsOverloadedMethod(funcName) {
 		argTypes := GetArgTypesFromValues(evalArgs)
 		
 		// Пытаемся разрешить перегрузку
-		overloadedFunc, err := ResolveMethodOverload(f.funcName, argTypes)
+		overloadedFunc, err := rt.ResolveMethodOverload(funcName, argTypes)
 		if err != nil {
-			panic(fmt.Sprintf("Overload resolution failed for '%s': %v", f.funcName, err))
+			panic(fmt.Sprintf("Overload resolution failed for '%s': %v", funcName, err))
 		}
 		
 		// Вызываем найденную перегрузку
-		return overloadedFunc.Call(evalArgs)
+		return CallIn(rt, overloadedFunc, evalArgs)
 	}
 	
 	// Иначе проверяем обычную функцию (это поддерживает параметры по умолчанию)
-	val, ok := scope.GlobalScope.Get(f.funcName)
+	val, ok := rt.Scope().Get(funcName)
 	if ok {
 		// Проверяем на TypedClosure (поддерживает параметры по умолчанию)
 		if typedClosure, ok := val.Any().(*TypedClosure); ok {
 			// Вызываем типизированное замыкание
-			return typedClosure.Call(evalArgs)
+			return typedClosure.CallIn(rt, evalArgs)
 		}
 	}
 	
 	// Если перегрузок нет, используем обычную логику
 	if !ok {
-		panic("function '" + f.funcName + "' is not defined")
+		panic("function '" + funcName + "' is not defined")
 	}
 	
 	// Пробуем найти Callable объект (может быть FuncStatment или встроенная функция)
 	if callable, ok := val.Any().(Callable); ok {
 		// Вызываем функцию
-		return callable.Call(evalArgs)
+		return CallIn(rt, callable, evalArgs)
 	}
 
 	// Проверяем на Go-функцию (встроенные функции)
@@ -70,10 +85,10 @@ func (f *FuncCallExpr) Eval() *Value {
 	// Старый код для совместимости с FuncStatment
 	fnStatment, ok := val.Any().(*FuncStatment)
 	if !ok {
-		panic("'" + f.funcName + "' is not a function")
+		panic("'" + funcName + "' is not a function")
 	}
 
-	return fnStatment.Call(evalArgs)
+	return fnStatment.Call(rt, evalArgs)
 }
 
 func (f *FuncCallExpr) String() string {
@@ -2,7 +2,6 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 )
 
@@ -14,6 +13,7 @@ type FuncParam struct {
 }
 
 type FuncStatment struct {
+	Node
 	funcName string
 	args     []map[string]Expr
 	body     Expr
@@ -22,6 +22,7 @@ type FuncStatment struct {
 
 // TypedFuncStatement представляет функцию с типизированными параметрами
 type TypedFuncStatement struct {
+	Node
 	FuncName   string
 	Params     []FuncParam
 	Body       Expr
@@ -36,11 +37,13 @@ type TypeConstraint struct {
 
 // GenericFuncStatement представляет generic функцию с параметрами типов
 type GenericFuncStatement struct {
+	Node
 	FuncName      string
 	TypeParams    []TypeConstraint // Параметры типов с ограничениями, например [{TypeName: "T", Constraints: ["Drawable"]}]
 	Params        []FuncParam      // Обычные параметры функции
 	ReturnType    string           // Тип возвращаемого значения
 	Body          Expr
+	rt            *Runtime         // Runtime, в котором зарегистрирована функция
 }
 
 func NewGenericFuncStatement(funcName string, typeParams []TypeConstraint, params []FuncParam, returnType string, body Expr) *GenericFuncStatement {
@@ -78,9 +81,9 @@ func NewTypedFuncStatement(funcName string, params []FuncParam, body Expr, retur
 	}
 }
 
-func (f *TypedFuncStatement) Eval() *Value {
+func (f *TypedFuncStatement) Eval(rt *Runtime) *Value {
 	// Создаем замыкание с типизированными параметрами
-	closure := NewTypedClosure(f.FuncName, f.Params, f.Body, f.ReturnType)
+	closure := NewTypedClosure(rt, f.FuncName, f.Params, f.Body, f.ReturnType)
 	
 	// Создаем сигнатуру для перегрузки
 	signature := CreateSignatureFromFunction(f.FuncName, f.Params, "")
@@ -96,11 +99,11 @@ func (f *TypedFuncStatement) Eval() *Value {
 	
 	// Если нет параметров по умолчанию, регистрируем в системе перегрузки
 	if !hasDefaultParams {
-		err := RegisterOverloadedMethod(signature, closure)
+		err := rt.RegisterOverloadedMethod(signature, closure)
 		if err != nil {
 			// Если это метод интерфейса, не выдаем ошибку
 			// Методы интерфейсов могут иметь одинаковые имена и сигнатуры
-			if !isInterfaceMethod(f.FuncName) {
+			if !isInterfaceMethod(rt, f.FuncName) {
 				panic(fmt.Sprintf("Function overload error: %v", err))
 			}
 		}
@@ -108,12 +111,12 @@ func (f *TypedFuncStatement) Eval() *Value {
 	
 	// Также регистрируем в обычном scope для обратной совместимости
 	// Всегда регистрируем как основную функцию (перегрузка будет работать через систему overloading)
-	scope.GlobalScope.Set(f.FuncName, NewValue(closure))
+	rt.Scope().Set(f.FuncName, NewValue(closure))
 	
 	return NewValue(nil)
 }
 
-func NewFuncStatment(funcName string, args []map[string]Expr, body Expr, isMacro bool) *FuncStatment {
+func NewFuncStatment(rt *Runtime, funcName string, args []map[string]Expr, body Expr, isMacro bool) *FuncStatment {
 	f := &FuncStatment{
 		funcName: funcName,
 		args:     args,
@@ -122,10 +125,10 @@ func NewFuncStatment(funcName string, args []map[string]Expr, body Expr, isMacro
 	}
 
 	// Создаем замыкание для захвата переменных из текущей области видимости
-	closure := NewClosure(funcName, args, body, isMacro)
+	closure := NewClosure(rt, funcName, args, body, isMacro)
 	
 	// Регистрируем замыкание в области видимости
-	scope.GlobalScope.Set(funcName, NewValue(closure))
+	rt.Scope().Set(funcName, NewValue(closure))
 
 	return f
 }
@@ -134,6 +137,16 @@ func (f *FuncStatment) Name() string {
 	return f.funcName
 }
 
+// Args возвращает параметры функции вместе со значениями по умолчанию
+func (f *FuncStatment) Args() []map[string]Expr {
+	return f.args
+}
+
+// Body возвращает тело функции
+func (f *FuncStatment) Body() Expr {
+	return f.body
+}
+
 func (f *FuncStatment) Params() []string {
 	var args []string
 	{
@@ -146,7 +159,7 @@ func (f *FuncStatment) Params() []string {
 	return args
 }
 
-func (f *FuncStatment) Eval() *Value {
+func (f *FuncStatment) Eval(rt *Runtime) *Value {
 	// Function definitions don't return values, they register the function in scope
 	// The function is already registered in NewFuncStatment constructor
 	return nil
@@ -161,9 +174,13 @@ func (f *FuncStatment) String() string {
 }
 
 // Реализация интерфейса Callable для FuncStatment
-func (f *FuncStatment) Call(args []*Value) *Value {
+func (f *FuncStatment) Call(rt *Runtime, args []*Value) (result *Value) {
 	bodyStm := f.body.(*BodyExpr)
 
+	rt.calls.push(f.funcName, f.body)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
+
 	expected := len(f.args)
 	passed := len(args)
 
@@ -172,20 +189,21 @@ func (f *FuncStatment) Call(args []*Value) *Value {
 	}
 
 	// Создаем новую область видимости для функции с проверкой рекурсии
-	err := scope.GlobalScope.PushFunction()
+	err := rt.Scope().PushFunction()
 	if err != nil {
 		panic(err.Error())
 	}
-	defer scope.GlobalScope.PopFunction()
+	defer rt.Scope().PopFunction()
+	defer runDeferred(rt)
 
 	// Устанавливаем параметры функции в локальной области
 	for i, arg := range f.args {
 		for name, expr := range arg {
 			if i < len(args) {
-				scope.GlobalScope.Set(name, args[i])
+				rt.Scope().Set(name, args[i])
 			} else if expr != nil {
-				defaultValue := expr.Eval()
-				scope.GlobalScope.Set(name, defaultValue)
+				defaultValue := expr.Eval(rt)
+				rt.Scope().Set(name, defaultValue)
 			} else {
 				panic(fmt.Sprintf("missing required argument: %s", name))
 			}
@@ -198,7 +216,8 @@ func (f *FuncStatment) Call(args []*Value) *Value {
 			continue
 		}
 
-		result := stm.Eval()
+		rt.calls.at(stm)
+		result := stm.Eval(rt)
 		
 		// Проверяем на return
 		if result != nil && result.IsReturn() {
@@ -210,17 +229,29 @@ func (f *FuncStatment) Call(args []*Value) *Value {
 }
 
 // Eval для GenericFuncStatement - регистрирует generic функцию
-func (g *GenericFuncStatement) Eval() *Value {
-	// Сохраняем generic функцию в глобальной области видимости
-	scope.GlobalScope.Set(g.FuncName, NewValue(g))
-	return NewValue(g)
+func (g *GenericFuncStatement) Eval(rt *Runtime) *Value {
+	// Сохраняем копию generic функции, привязанную к текущему Runtime
+	bound := *g
+	bound.rt = rt
+	rt.Scope().Set(g.FuncName, NewValue(&bound))
+	return NewValue(&bound)
 }
 
 // Call для GenericFuncStatement с поддержкой типов
 func (g *GenericFuncStatement) Call(args []*Value) *Value {
+	return g.CallIn(g.rt, args)
+}
+
+// CallIn выполняет generic функцию в стеке областей rt
+func (g *GenericFuncStatement) CallIn(rt *Runtime, args []*Value) (result *Value) {
+	rt.calls.push(g.FuncName, g.Body)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
+
 	// Создаем новую область видимости для функции
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+	defer runDeferred(rt)
 
 	// Проверяем количество аргументов
 	if len(args) != len(g.Params) {
@@ -228,19 +259,36 @@ func (g *GenericFuncStatement) Call(args []*Value) *Value {
 			g.FuncName, len(g.Params), len(args)))
 	}
 
+	// Параметр типа связывается с типом первого аргумента вида x: T,
+	// остальные аргументы того же параметра должны иметь этот тип
+	names := TypeParamNames(g.TypeParams)
+	bound := make([]string, len(names))
+
 	// Устанавливаем параметры в области видимости
 	for i, param := range g.Params {
 		argValue := args[i]
 		
 		// Проверяем ограничения типов, если они есть
 		if param.TypeName != "" {
-			if err := g.validateTypeConstraints(param.TypeName, argValue); err != nil {
+			if err := g.validateTypeConstraints(rt, param.TypeName, argValue); err != nil {
 				panic(fmt.Sprintf("Type constraint violation in function '%s': %v", g.FuncName, err))
 			}
 		}
+
+		if k := indexOfName(names, param.TypeName); k >= 0 && argValue.Any() != nil {
+			next := bindTypeArg(rt, argValue)
+			switch {
+			case bound[k] == "", bound[k] == "int" && next == "float":
+				bound[k] = next
+			case validateVariableType(rt, argValue, bound[k]) != nil:
+				panic(fmt.Sprintf("function '%s' parameter '%s': expected %s (%s), got %s",
+					g.FuncName, param.Name, bound[k], param.TypeName, next))
+			}
+		}
 		
-		scope.GlobalScope.Set(param.Name, argValue)
+		rt.Scope().Set(param.Name, argValue)
 	}
+	bindTypeArgs(rt, names, bound)
 
 	// Выполняем тело функции
 	if bodyStm, ok := g.Body.(*BodyExpr); ok {
@@ -249,7 +297,8 @@ func (g *GenericFuncStatement) Call(args []*Value) *Value {
 				continue
 			}
 
-			result := stm.Eval()
+			rt.calls.at(stm)
+			result := stm.Eval(rt)
 			
 			// Проверяем на return
 			if result != nil && result.IsReturn() {
@@ -267,7 +316,7 @@ func (g *GenericFuncStatement) Name() string {
 }
 
 // validateTypeConstraints проверяет, соответствует ли значение ограничениям типа
-func (g *GenericFuncStatement) validateTypeConstraints(paramType string, val *Value) error {
+func (g *GenericFuncStatement) validateTypeConstraints(rt *Runtime, paramType string, val *Value) error {
 	// Находим ограничения для данного типа
 	var constraints []string
 	for _, typeParam := range g.TypeParams {
@@ -284,7 +333,7 @@ func (g *GenericFuncStatement) validateTypeConstraints(paramType string, val *Va
 	
 	// Проверяем каждое ограничение
 	for _, constraint := range constraints {
-		if !g.checkInterfaceConstraint(constraint, val) {
+		if !g.checkInterfaceConstraint(rt, constraint, val) {
 			return fmt.Errorf("value does not satisfy interface constraint '%s'", constraint)
 		}
 	}
@@ -293,9 +342,9 @@ func (g *GenericFuncStatement) validateTypeConstraints(paramType string, val *Va
 }
 
 // checkInterfaceConstraint проверяет, реализует ли тип данный интерфейс
-func (g *GenericFuncStatement) checkInterfaceConstraint(interfaceName string, val *Value) bool {
+func (g *GenericFuncStatement) checkInterfaceConstraint(rt *Runtime, interfaceName string, val *Value) bool {
 	// Получаем определение интерфейса
-	interfaceDef := GetInterface(interfaceName)
+	interfaceDef := rt.GetInterface(interfaceName)
 	if interfaceDef == nil {
 		// Если интерфейс не найден, считаем ограничение не выполненным
 		return false
@@ -317,14 +366,14 @@ func (g *GenericFuncStatement) checkInterfaceConstraint(interfaceName string, va
 	}
 	
 	// Проверяем, есть ли реализация интерфейса для данного типа
-	return TypeImplementsInterface(typeName, interfaceName)
+	return rt.TypeImplementsInterface(typeName, interfaceName)
 }
 
 // isInterfaceMethod проверяет, является ли функция методом интерфейса
 // Методы интерфейсов имеют общие имена и могут дублироваться для разных типов
-func isInterfaceMethod(methodName string) bool {
+func isInterfaceMethod(rt *Runtime, methodName string) bool {
 	// Проверяем все зарегистрированные интерфейсы
-	for _, interfaceDef := range registeredInterfaces {
+	for _, interfaceDef := range rt.Interfaces() {
 		if interfaceDef.HasMethod(methodName) {
 			return true
 		}
@@ -2,17 +2,14 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 	"regexp"
 	"strings"
 )
 
-// ParserFunc - функция для парсинга кода, устанавливается извне чтобы избежать циклических зависимостей
-var ParserFunc func(string) []Expr
-
 // GenerateExpr представляет блок generate {} для шаблонной генерации кода в макросах
 type GenerateExpr struct {
+	Node
 	Template string // Шаблон с ${} интерполяциями
 }
 
@@ -20,12 +17,12 @@ func NewGenerateExpr(template string) *GenerateExpr {
 	return &GenerateExpr{Template: template}
 }
 
-func (g *GenerateExpr) Eval() *value.Value {
+func (g *GenerateExpr) Eval(rt *Runtime) *value.Value {
 	// Создаем TemplateExpr для обработки шаблона
 	templateExpr := NewTemplateExpr(g.Template)
 
 	// Обрабатываем шаблон и получаем сгенерированный код
-	result := templateExpr.Eval()
+	result := templateExpr.Eval(rt)
 
 	if result != nil && result.Any() != nil {
 		generatedCode := fmt.Sprintf("%v", result.Any())
@@ -36,11 +33,8 @@ func (g *GenerateExpr) Eval() *value.Value {
 		// fmt.Println("======================")
 
 		// Парсим и выполняем сгенерированный код
-		if len(generatedCode) > 0 && ParserFunc != nil {
-			exprs := ParserFunc(generatedCode)
-			for _, expr := range exprs {
-				expr.Eval()
-			}
+		if len(generatedCode) > 0 && rt.parse != nil {
+			rt.Run(rt.Parse(generatedCode))
 		}
 
 		return result
@@ -50,7 +44,7 @@ func (g *GenerateExpr) Eval() *value.Value {
 }
 
 // processTemplate обрабатывает шаблон и заменяет ${...} интерполяции
-func (g *GenerateExpr) processTemplate() string {
+func (g *GenerateExpr) processTemplate(rt *Runtime) string {
 	template := g.Template
 
 	// Регулярное выражение для поиска ${...}
@@ -68,7 +62,7 @@ func (g *GenerateExpr) processTemplate() string {
 			return g.processIfStatement(expr)
 		} else {
 			// Обычная интерполяция переменной или выражения
-			return g.evaluateExpression(expr)
+			return g.evaluateExpression(rt, expr)
 		}
 	})
 
@@ -76,9 +70,9 @@ func (g *GenerateExpr) processTemplate() string {
 }
 
 // evaluateExpression вычисляет выражение и возвращает его строковое представление
-func (g *GenerateExpr) evaluateExpression(expr string) string {
+func (g *GenerateExpr) evaluateExpression(rt *Runtime, expr string) string {
 	// Пытаемся получить значение из scope
-	if val, found := scope.GlobalScope.Get(expr); found {
+	if val, found := rt.Scope().Get(expr); found {
 		if val != nil {
 			return fmt.Sprintf("%v", val.Any())
 		}
@@ -89,7 +83,7 @@ func (g *GenerateExpr) evaluateExpression(expr string) string {
 		parts := strings.Split(expr, ".")
 		if len(parts) == 2 {
 			// Получаем объект из scope
-			if obj, found := scope.GlobalScope.Get(parts[0]); found && obj != nil {
+			if obj, found := rt.Scope().Get(parts[0]); found && obj != nil {
 				// Если это TypeInfo, получаем его свойство
 				if typeInfo, ok := obj.Any().(*TypeInfo); ok {
 					if parts[1] == "Name" {
@@ -0,0 +1,162 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+	"strings"
+)
+
+// SplitGenericType разбирает тип вида Box<int> или Pair<string,Box<int>>
+// на имя и аргументы типа. Для обычного типа аргументов нет
+func SplitGenericType(typ string) (string, []string) {
+	open := strings.Index(typ, "<")
+	if open < 0 || !strings.HasSuffix(typ, ">") {
+		return typ, nil
+	}
+
+	var args []string
+	depth, from := 0, open+1
+	for i := open + 1; i < len(typ)-1; i++ {
+		switch typ[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(typ[from:i]))
+				from = i + 1
+			}
+		}
+	}
+	args = append(args, strings.TrimSpace(typ[from:len(typ)-1]))
+
+	return typ[:open], args
+}
+
+// FormatGenericType собирает имя generic типа: Box + [int] -> Box<int>
+func FormatGenericType(name string, args []string) string {
+	if len(args) == 0 {
+		return name
+	}
+	return name + "<" + strings.Join(args, ",") + ">"
+}
+
+// TypeParamNames возвращает имена параметров типа: <T: Drawable, U> -> [T, U]
+func TypeParamNames(params []TypeConstraint) []string {
+	names := make([]string, len(params))
+	for i, param := range params {
+		names[i] = param.TypeName
+	}
+	return names
+}
+
+// SubstituteTypeParams заменяет в типе имена параметров типа на аргументы:
+// Box<T>|null с T = int дает Box<int>|null
+func SubstituteTypeParams(typ string, names, args []string) string {
+	if len(names) == 0 || typ == "" {
+		return typ
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(typ); {
+		if !isTypeNameChar(typ[i]) {
+			result.WriteByte(typ[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(typ) && isTypeNameChar(typ[j]) {
+			j++
+		}
+		word := typ[i:j]
+		for k, name := range names {
+			if word == name && k < len(args) {
+				word = args[k]
+				break
+			}
+		}
+		result.WriteString(word)
+		i = j
+	}
+	return result.String()
+}
+
+func indexOfName(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MentionsTypeParam сообщает, упоминает ли тип один из параметров типа
+func MentionsTypeParam(typ string, names []string) bool {
+	return SubstituteTypeParams(typ, names, make([]string, len(names))) != typ
+}
+
+func isTypeNameChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// ValueTypeName возвращает имя типа значения для связывания параметров типа:
+// структуры называются своим именем, остальные значения - как в validateVariableType
+func ValueTypeName(v *Value) string {
+	if v == nil || v.Any() == nil {
+		return "null"
+	}
+	if structObj, ok := v.Any().(*StructObject); ok {
+		return structObj.TypeName()
+	}
+	return value.GetValueTypeName(v)
+}
+
+// bindTypeArgs делает параметры типа видимыми в текущей области: аннотации
+// вида let x: T внутри generic кода проверяются по связанному аргументу
+func bindTypeArgs(rt *Runtime, names, args []string) {
+	for i, name := range names {
+		bound := "any"
+		if i < len(args) && args[i] != "" {
+			bound = args[i]
+		}
+		rt.Scope().Set(name+"__TypeArg", NewValue(bound))
+	}
+}
+
+// bindTypeArg связывает параметр типа с типом значения; неподходящее для
+// аннотаций значение (функция, канал) связывает параметр с any
+func bindTypeArg(rt *Runtime, val *Value) string {
+	name := ValueTypeName(val)
+	if name == "null" || validateVariableType(rt, val, name) != nil {
+		return "any"
+	}
+	return name
+}
+
+// unifyTypeArg уточняет выведенный аргумент типа очередным значением:
+// первое значение связывает параметр, int расширяется до float
+func unifyTypeArg(bound, next string) string {
+	if bound == "any" || (bound == "int" && next == "float") {
+		return next
+	}
+	return bound
+}
+
+// checkTypeArgs проверяет количество аргументов типа и ограничения параметров
+func checkTypeArgs(rt *Runtime, owner string, params []TypeConstraint, args []string) {
+	if len(args) != len(params) {
+		panic(fmt.Sprintf("%s expects %d type argument(s), got %d", owner, len(params), len(args)))
+	}
+
+	for i, param := range params {
+		for _, constraint := range param.Constraints {
+			base, _ := SplitGenericType(args[i])
+			if args[i] != "any" && !rt.TypeImplementsInterface(base, constraint) {
+				panic(fmt.Sprintf("Type constraint violation in %s: type %s does not satisfy interface constraint '%s'",
+					owner, args[i], constraint))
+			}
+		}
+	}
+}
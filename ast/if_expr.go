@@ -2,6 +2,7 @@ package ast
 
 
 type IfExpr struct {
+	Node
 	Condition []Expr
 	Then      []Expr
 	Else      Expr
@@ -11,30 +12,11 @@ func NewIfExpr(conditions []Expr, then []Expr, elseExpr Expr) *IfExpr {
 	return &IfExpr{Condition: conditions, Then: then, Else: elseExpr}
 }
 
-func (i *IfExpr) Eval() *Value {
+func (i *IfExpr) Eval(rt *Runtime) *Value {
 
 	for index, cond := range i.Condition {
-		if cond.Eval().Bool() {
-			body := i.Then[index].(*BodyExpr)
-			{
-				var result *Value
-				for _, statment := range body.Statments {
-					switch stm := statment.(type) {
-					case *ReturnExpr:
-						val := stm.Eval()
-						val.SetReturn(true)
-						return val
-					case *BreakExpr:
-						return stm.Eval()
-					default:
-						result = stm.Eval()
-						if result != nil && (result.IsReturn() || result.IsYield() || result.IsBreak()) {
-							return result
-						}
-					}
-				}
-				return result
-			}
+		if cond.Eval(rt).Bool() {
+			return evalBranch(rt, i.Then[index].(*BodyExpr))
 		}
 	}
 
@@ -42,5 +24,32 @@ func (i *IfExpr) Eval() *Value {
 		return nil
 	}
 
-	return i.Else.Eval()
+	// else { ... } прерывается так же, как ветка then; else if - вложенный IfExpr
+	if body, ok := i.Else.(*BodyExpr); ok {
+		return evalBranch(rt, body)
+	}
+
+	return i.Else.Eval(rt)
+}
+
+// evalBranch выполняет ветку if: return, break, continue и yield прерывают ее и передаются выше
+func evalBranch(rt *Runtime, body *BodyExpr) *Value {
+	var result *Value
+	for _, statment := range body.Statments {
+		rt.calls.at(statment)
+		switch stm := statment.(type) {
+		case *ReturnExpr:
+			val := stm.Eval(rt)
+			val.SetReturn(true)
+			return val
+		case *BreakExpr:
+			return stm.Eval(rt)
+		default:
+			result = stm.Eval(rt)
+			if result != nil && (result.IsReturn() || result.IsYield() || result.IsBreak() || result.IsContinue()) {
+				return result
+			}
+		}
+	}
+	return result
 }
@@ -1,18 +1,21 @@
 package ast
 
-import (
-	"foo_lang/modules"
-)
+import "foo_lang/modules"
 
 // ImportExpr represents different types of import statements:
 // import "./module.foo"
-// import { func1, var1 } from "./module.foo"
+// import { func1, var1 as v } from "./module.foo"
 // import * as ModuleName from "./module.foo"
+// import name from "./module.foo"
+// import name, { func1 } from "./module.foo"
 type ImportExpr struct {
-	Path         string   // Path to the module file
-	ImportedAll  bool     // true for import * as Name
-	AliasName    string   // Name for import * as Name, or empty
-	ImportedItems []string // List of specific items to import, empty for import all
+	Node
+	Path          string            // Path to the module file
+	ImportedAll   bool              // true for import * as Name
+	AliasName     string            // Name for import * as Name, or empty
+	ImportedItems []string          // List of specific items to import, empty for import all
+	Renames       map[string]string // Local names of items imported with 'as'
+	DefaultName   string            // Local name of the default export, or empty
 }
 
 func NewImportExpr(path string) *ImportExpr {
@@ -38,24 +41,45 @@ func NewAliasImportExpr(path string, alias string) *ImportExpr {
 	}
 }
 
-func (i *ImportExpr) Eval() *Value {
+// NewDefaultImportExpr creates import name from "./module.foo"; items are
+// imported along with the default export: import name, { a } from "./module.foo"
+func NewDefaultImportExpr(path string, name string, items []string) *ImportExpr {
+	return &ImportExpr{
+		Path:          path,
+		ImportedAll:   false,
+		ImportedItems: items,
+		DefaultName:   name,
+	}
+}
+
+// LocalName returns the name an imported item gets in the importing scope
+func (i *ImportExpr) LocalName(item string) string {
+	if local, ok := i.Renames[item]; ok {
+		return local
+	}
+	return item
+}
+
+// Bindings returns imported items with their local names
+func (i *ImportExpr) Bindings() []modules.Binding {
+	var bindings []modules.Binding
+	if i.DefaultName != "" {
+		bindings = append(bindings, modules.Binding{Name: modules.DefaultExport, Local: i.DefaultName})
+	}
+	for _, item := range i.ImportedItems {
+		bindings = append(bindings, modules.Binding{Name: item, Local: i.LocalName(item)})
+	}
+	return bindings
+}
+
+func (i *ImportExpr) Eval(rt *Runtime) *Value {
 	// Import statements don't return values, they modify the current scope
 	// Use the modules system to load and import
-	
-	// Используем контекст текущего файла, установленный парсером
-	currentFile := GetCurrentFileContext()
-	if currentFile == "" {
-		currentFile = "./" // Fallback для обратной совместимости
-	}
-	
-	if GlobalParseFunc == nil {
-		panic("GlobalParseFunc not set - cannot import modules")
-	}
-	
-	err := modules.ImportModule(i.Path, currentFile, i.ImportedItems, i.AliasName, GlobalParseFunc)
+
+	err := rt.ImportBindings(i.Path, i.Bindings(), i.AliasName)
 	if err != nil {
 		panic(err.Error())
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
@@ -4,6 +4,7 @@ import "foo_lang/value"
 
 // IndexExpr представляет индексацию массива или объекта (arr[index])
 type IndexExpr struct {
+	Node
 	Object Expr // Объект или массив
 	Index  Expr // Индекс
 }
@@ -15,10 +16,16 @@ func NewIndexExpr(object, index Expr) *IndexExpr {
 	}
 }
 
-func (i *IndexExpr) Eval() *Value {
-	obj := i.Object.Eval()
-	idx := i.Index.Eval()
-	
+func (i *IndexExpr) Eval(rt *Runtime) *Value {
+	obj, idx := i.Object.Eval(rt), i.Index.Eval(rt)
+	if result, ok := OverloadedIndex(rt, obj, idx); ok {
+		return result
+	}
+	return GetIndex(obj, idx)
+}
+
+// GetIndex возвращает элемент массива по числовому индексу или значение объекта по ключу
+func GetIndex(obj, idx *Value) *Value {
 	// Для массивов
 	if arr, ok := obj.Any().([]any); ok {
 		// Индекс должен быть числом
@@ -33,17 +40,50 @@ func (i *IndexExpr) Eval() *Value {
 			panic("array index out of bounds")
 		}
 		
-		return value.NewValue(arr[index])
+		return frozenElement(obj, arr[index])
+	}
+
+	// Персистентный список List
+	if list, ok := obj.Any().(*value.List); ok {
+		if !idx.IsNumber() {
+			panic("list index must be an integer")
+		}
+		item, exists := list.Get(idx.Int())
+		if !exists {
+			panic("list index out of bounds")
+		}
+		return frozenElement(obj, item)
+	}
+
+	// Кортеж
+	if tuple, ok := obj.Any().(*value.Tuple); ok {
+		if !idx.IsNumber() {
+			panic("tuple index must be an integer")
+		}
+		item, exists := tuple.Get(idx.Int())
+		if !exists {
+			panic("tuple index out of bounds")
+		}
+		return NewValue(item)
+	}
+
+	// Персистентный словарь Map
+	if m, ok := obj.Any().(*value.Map); ok {
+		if !idx.IsString() {
+			panic("map key must be a string")
+		}
+		item, _ := m.Get(idx.String())
+		return frozenElement(obj, item)
 	}
 	
 	// Для объектов (строковый индекс)
-	if objMap, ok := obj.Any().(map[string]*Value); ok {
+	if objMap, ok := obj.Any().(*value.Object); ok {
 		if !idx.IsString() {
 			panic("object property name must be a string")
 		}
 		
 		key := idx.String()
-		if val, exists := objMap[key]; exists {
+		if val, exists := objMap.Get(key); exists {
 			return val
 		} else {
 			return value.NewValue(nil) // undefined
@@ -1,6 +1,7 @@
 package ast
 
 type IntExpr struct {
+	Node
 	Value *Value
 }
 
@@ -8,6 +9,6 @@ func NewInt64Expr(value int64) *IntExpr {
 	return &IntExpr{Value: NewValue(value)}
 }
 
-func (n *IntExpr) Eval() *Value {
+func (n *IntExpr) Eval(rt *Runtime) *Value {
 	return n.Value
 }
@@ -1,9 +1,6 @@
 package ast
 
-import (
-	"fmt"
-	"foo_lang/scope"
-)
+import "fmt"
 
 // InterfaceMethod представляет сигнатуру метода в интерфейсе
 type InterfaceMethod struct {
@@ -14,8 +11,10 @@ type InterfaceMethod struct {
 
 // InterfaceDefinition представляет определение интерфейса
 type InterfaceDefinition struct {
-	Name    string            // Имя интерфейса
-	Methods []InterfaceMethod // Методы интерфейса
+	Node
+	Name       string            // Имя интерфейса
+	TypeParams []TypeConstraint  // Параметры типа: interface Container<T>
+	Methods    []InterfaceMethod // Методы интерфейса
 }
 
 // NewInterfaceDefinition создает новое определение интерфейса
@@ -26,11 +25,48 @@ func NewInterfaceDefinition(name string, methods []InterfaceMethod) *InterfaceDe
 	}
 }
 
+// NewGenericInterfaceDefinition создает определение generic интерфейса
+func NewGenericInterfaceDefinition(name string, typeParams []TypeConstraint, methods []InterfaceMethod) *InterfaceDefinition {
+	return &InterfaceDefinition{
+		Name:       name,
+		TypeParams: typeParams,
+		Methods:    methods,
+	}
+}
+
+// Instantiate подставляет аргументы типа в сигнатуры методов:
+// Container<T> с [int] требует fn get() -> int
+func (i *InterfaceDefinition) Instantiate(args []string) (*InterfaceDefinition, error) {
+	if len(args) != len(i.TypeParams) {
+		return nil, fmt.Errorf("interface '%s' expects %d type argument(s), got %d", i.Name, len(i.TypeParams), len(args))
+	}
+	if len(args) == 0 {
+		return i, nil
+	}
+
+	names := TypeParamNames(i.TypeParams)
+	methods := make([]InterfaceMethod, len(i.Methods))
+	for m, method := range i.Methods {
+		params := make([]FuncParam, len(method.Params))
+		for p, param := range method.Params {
+			param.TypeName = SubstituteTypeParams(param.TypeName, names, args)
+			params[p] = param
+		}
+		methods[m] = InterfaceMethod{
+			Name:       method.Name,
+			Params:     params,
+			ReturnType: SubstituteTypeParams(method.ReturnType, names, args),
+		}
+	}
+
+	return &InterfaceDefinition{Name: FormatGenericType(i.Name, args), Methods: methods}, nil
+}
+
 // Eval регистрирует интерфейс в области видимости
-func (i *InterfaceDefinition) Eval() *Value {
+func (i *InterfaceDefinition) Eval(rt *Runtime) *Value {
 	// Регистрируем интерфейс в глобальной области видимости
-	RegisterInterface(i.Name, i)
-	scope.GlobalScope.Set(i.Name, NewValue(i))
+	rt.RegisterInterface(i.Name, i)
+	rt.Scope().Set(i.Name, NewValue(i))
 	
 	return NewValue(i)
 }
@@ -55,7 +91,8 @@ func (i *InterfaceDefinition) HasMethod(methodName string) bool {
 	return i.GetMethodByName(methodName) != nil
 }
 
-// MethodsMatch проверяет, совпадает ли сигнатура метода с требованиями интерфейса
+// MethodsMatch проверяет, совпадает ли сигнатура метода с требованиями интерфейса.
+// Нетипизированный параметр или результат интерфейса допускает любой тип
 func (i *InterfaceDefinition) MethodMatches(methodName string, params []FuncParam, returnType string) bool {
 	interfaceMethod := i.GetMethodByName(methodName)
 	if interfaceMethod == nil {
@@ -69,23 +106,27 @@ func (i *InterfaceDefinition) MethodMatches(methodName string, params []FuncPara
 	
 	// Проверяем типы параметров
 	for j, interfaceParam := range interfaceMethod.Params {
-		if interfaceParam.TypeName != params[j].TypeName {
+		if interfaceParam.TypeName != "" && interfaceParam.TypeName != params[j].TypeName {
 			return false
 		}
 	}
 	
 	// Проверяем тип возвращаемого значения
-	if interfaceMethod.ReturnType != returnType {
+	if interfaceMethod.ReturnType != "" && interfaceMethod.ReturnType != returnType {
 		return false
 	}
 	
 	return true
 }
 
-// ImplBlock представляет блок реализации интерфейса для типа
+// ImplBlock представляет блок реализации интерфейса для типа:
+// impl Container<T> for Box<T> { ... }
 type ImplBlock struct {
+	Node
 	InterfaceName string            // Имя реализуемого интерфейса
+	InterfaceArgs []string          // Аргументы типа интерфейса: Container<int>
 	TypeName      string            // Имя типа, для которого реализуется интерфейс
+	TypeParams    []string          // Параметры типа generic структуры: for Box<T>
 	Methods       []*TypedFuncStatement // Методы реализации
 }
 
@@ -99,25 +140,28 @@ func NewImplBlock(interfaceName, typeName string, methods []*TypedFuncStatement)
 }
 
 // Eval выполняет блок реализации интерфейса
-func (impl *ImplBlock) Eval() *Value {
+func (impl *ImplBlock) Eval(rt *Runtime) *Value {
 	// Получаем интерфейс
-	interfaceDef := GetInterface(impl.InterfaceName)
+	interfaceDef := rt.GetInterface(impl.InterfaceName)
 	if interfaceDef == nil {
 		panic(fmt.Sprintf("Interface '%s' is not defined", impl.InterfaceName))
 	}
 	
 	// Проверяем, что все методы интерфейса реализованы
-	err := impl.ValidateImplementation(interfaceDef)
+	interfaceDef, err := interfaceDef.Instantiate(impl.InterfaceArgs)
+	if err == nil {
+		err = impl.ValidateImplementation(interfaceDef)
+	}
 	if err != nil {
 		panic(fmt.Sprintf("Implementation error: %v", err))
 	}
 	
 	// Регистрируем реализацию
-	RegisterImplementation(impl.TypeName, impl.InterfaceName, impl)
+	rt.RegisterImplementation(impl.TypeName, impl.InterfaceName, impl)
 	
 	// Регистрируем методы в области видимости
 	for _, method := range impl.Methods {
-		method.Eval()
+		method.Eval(rt)
 	}
 	
 	return NewValue(impl)
@@ -153,45 +197,79 @@ func (impl *ImplBlock) String() string {
 	return fmt.Sprintf("impl %s for %s", impl.InterfaceName, impl.TypeName)
 }
 
-// Глобальный реестр интерфейсов
-var registeredInterfaces = make(map[string]*InterfaceDefinition)
-
-// Глобальный реестр реализаций интерфейсов
-var interfaceImplementations = make(map[string]map[string]*ImplBlock) // [typeName][interfaceName] = implBlock
-
 // RegisterInterface регистрирует новый интерфейс
-func RegisterInterface(name string, interfaceDef *InterfaceDefinition) {
-	registeredInterfaces[name] = interfaceDef
+func (rt *Runtime) RegisterInterface(name string, interfaceDef *InterfaceDefinition) {
+	rt.registry.mu.Lock()
+	defer rt.registry.mu.Unlock()
+	
+	rt.registry.interfaces[name] = interfaceDef
 }
 
 // GetInterface получает интерфейс по имени
-func GetInterface(name string) *InterfaceDefinition {
-	return registeredInterfaces[name]
+func (rt *Runtime) GetInterface(name string) *InterfaceDefinition {
+	rt.registry.mu.RLock()
+	defer rt.registry.mu.RUnlock()
+	
+	return rt.registry.interfaces[name]
+}
+
+// Interfaces возвращает все зарегистрированные интерфейсы
+func (rt *Runtime) Interfaces() []*InterfaceDefinition {
+	rt.registry.mu.RLock()
+	defer rt.registry.mu.RUnlock()
+	
+	interfaces := make([]*InterfaceDefinition, 0, len(rt.registry.interfaces))
+	for _, interfaceDef := range rt.registry.interfaces {
+		interfaces = append(interfaces, interfaceDef)
+	}
+	return interfaces
 }
 
 // RegisterImplementation регистрирует реализацию интерфейса для типа
-func RegisterImplementation(typeName, interfaceName string, impl *ImplBlock) {
-	if interfaceImplementations[typeName] == nil {
-		interfaceImplementations[typeName] = make(map[string]*ImplBlock)
+func (rt *Runtime) RegisterImplementation(typeName, interfaceName string, impl *ImplBlock) {
+	rt.registry.mu.Lock()
+	defer rt.registry.mu.Unlock()
+	
+	if rt.registry.implementations[typeName] == nil {
+		rt.registry.implementations[typeName] = make(map[string]*ImplBlock)
 	}
-	interfaceImplementations[typeName][interfaceName] = impl
+	rt.registry.implementations[typeName][interfaceName] = impl
 }
 
 // GetImplementation получает реализацию интерфейса для типа
-func GetImplementation(typeName, interfaceName string) *ImplBlock {
-	if typeImpls, exists := interfaceImplementations[typeName]; exists {
+func (rt *Runtime) GetImplementation(typeName, interfaceName string) *ImplBlock {
+	rt.registry.mu.RLock()
+	defer rt.registry.mu.RUnlock()
+	
+	if typeImpls, exists := rt.registry.implementations[typeName]; exists {
 		return typeImpls[interfaceName]
 	}
 	return nil
 }
 
+// ImplementedInterfaces возвращает список интерфейсов, реализованных типом
+func (rt *Runtime) ImplementedInterfaces(typeName string) []string {
+	rt.registry.mu.RLock()
+	defer rt.registry.mu.RUnlock()
+	
+	var interfaces []string
+	for interfaceName := range rt.registry.implementations[typeName] {
+		interfaces = append(interfaces, interfaceName)
+	}
+	return interfaces
+}
+
 // TypeImplementsInterface проверяет, реализует ли тип интерфейс
-func TypeImplementsInterface(typeName, interfaceName string) bool {
-	return GetImplementation(typeName, interfaceName) != nil
+func (rt *Runtime) TypeImplementsInterface(typeName, interfaceName string) bool {
+	return rt.GetImplementation(typeName, interfaceName) != nil
 }
 
-// ClearInterfaces очищает реестр интерфейсов (для тестов)
+// ClearInterfaces очищает реестр интерфейсов GlobalRuntime (для тестов)
 func ClearInterfaces() {
-	registeredInterfaces = make(map[string]*InterfaceDefinition)
-	interfaceImplementations = make(map[string]map[string]*ImplBlock)
-}
\ No newline at end of file
+	GlobalRuntime.registry.mu.Lock()
+	defer GlobalRuntime.registry.mu.Unlock()
+	
+	clear(GlobalRuntime.registry.interfaces)
+	clear(GlobalRuntime.registry.implementations)
+	GlobalRuntime.registry.registerOperatorInterfaces()
+}
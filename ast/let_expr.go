@@ -1,8 +1,8 @@
 package ast
 
-import "foo_lang/scope"
 
 type LetExpr struct {
+	Node
 	name string
 	expr Expr
 }
@@ -14,13 +14,13 @@ func NewLetExpr(name string, expr Expr) *LetExpr {
 	}
 }
 
-func (n *LetExpr) Eval() *Value {
-	if scope.GlobalScope.Has(n.name) {
+func (n *LetExpr) Eval(rt *Runtime) *Value {
+	if rt.Scope().Has(n.name) {
 		panic("variable " + n.name + " is already defined")
 	}
 
-	val := n.expr.Eval()
-	scope.GlobalScope.Set(n.name, val)
+	val := n.expr.Eval(rt)
+	rt.Scope().Set(n.name, val)
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package ast
 
 type LiteralAny struct {
+	Node
 	Value any
 }
 
@@ -8,6 +9,6 @@ func NewLiteralAny(value any) *LiteralAny {
 	return &LiteralAny{Value: value}
 }
 
-func (l *LiteralAny) Eval() *Value {
+func (l *LiteralAny) Eval(rt *Runtime) *Value {
 	return NewValue(l.Value)
 }
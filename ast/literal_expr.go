@@ -3,6 +3,7 @@ package ast
 import "strings"
 
 type LiteralString struct {
+	Node
 	Value string
 }
 
@@ -16,6 +17,6 @@ func NewLiteralString(value string) *LiteralString {
 	return &LiteralString{Value: value}
 }
 
-func (l *LiteralString) Eval() *Value {
+func (l *LiteralString) Eval(rt *Runtime) *Value {
 	return NewValue(l.Value)
 }
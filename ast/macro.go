@@ -2,7 +2,6 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 )
 
@@ -22,6 +21,7 @@ type Macro struct {
 
 // MacroDefExpr представляет определение макроса с поддержкой macro-time и code-gen
 type MacroDefExpr struct {
+	Node
 	Name        string
 	Params      []MacroParam // Типизированные параметры
 	MacroTime   []Expr       // Выполняется во время компиляции макроса
@@ -56,7 +56,7 @@ func NewLegacyMacroDefExpr(name string, paramNames []string, body Expr) *MacroDe
 	return NewSimpleMacroDefExpr(name, params, body)
 }
 
-func (m *MacroDefExpr) Eval() *value.Value {
+func (m *MacroDefExpr) Eval(rt *Runtime) *value.Value {
 	// Сохраняем макрос в текущей области видимости
 	macro := &Macro{
 		Name:        m.Name,
@@ -66,7 +66,7 @@ func (m *MacroDefExpr) Eval() *value.Value {
 	}
 	
 	// Сохраняем макрос как специальное значение в scope
-	scope.GlobalScope.Set(m.Name, value.NewValue(macro))
+	rt.Scope().Set(m.Name, value.NewValue(macro))
 	
 	// Возвращаем nil, так как определение макроса не производит значения
 	return value.NewValue(nil)
@@ -74,6 +74,7 @@ func (m *MacroDefExpr) Eval() *value.Value {
 
 // MacroCallExpr представляет вызов макроса
 type MacroCallExpr struct {
+	Node
 	Name string
 	Args []Expr
 }
@@ -85,9 +86,9 @@ func NewMacroCallExpr(name string, args []Expr) *MacroCallExpr {
 	}
 }
 
-func (m *MacroCallExpr) Eval() *value.Value {
+func (m *MacroCallExpr) Eval(rt *Runtime) *value.Value {
 	// Получаем макрос из scope
-	macroValue, found := scope.GlobalScope.Get(m.Name)
+	macroValue, found := rt.Scope().Get(m.Name)
 	if !found || macroValue == nil {
 		panic(fmt.Sprintf("macro '%s' not found", m.Name))
 	}
@@ -104,34 +105,41 @@ func (m *MacroCallExpr) Eval() *value.Value {
 	}
 	
 	// Создаем новую область видимости для макроса
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
 	
 	// Связываем параметры с аргументами с проверкой типов
 	for i, param := range macro.Params {
 		// Вычисляем аргументы перед передачей в макрос
-		argValue := m.Args[i].Eval()
+		argValue := m.Args[i].Eval(rt)
 		
 		// Проверяем тип параметра, если указан
 		if param.TypeName != "" {
+			// Имя enum вычисляется в объект значений - макросу нужен его TypeInfo
+			if v, isVar := m.Args[i].(*VarExpr); isVar && v.GetExpr() == nil {
+				if typeInfo, found := rt.Scope().Get(v.Name + "__TypeInfo"); found {
+					argValue = typeInfo
+				}
+			}
+
 			if err := validateMacroParameterType(argValue, param.TypeName); err != nil {
 				panic(fmt.Sprintf("macro '%s' parameter '%s': %s", m.Name, param.Name, err.Error()))
 			}
 		}
 		
-		scope.GlobalScope.Set(param.Name, argValue)
+		rt.Scope().Set(param.Name, argValue)
 	}
 	
 	// ФАЗА 1: Выполняем macro-time код
 	if macro.MacroTime != nil {
 		for _, stmt := range macro.MacroTime {
-			stmt.Eval()
+			stmt.Eval(rt)
 		}
 	}
 	
 	// ФАЗА 2: Генерируем и выполняем код из Expr блока
 	if macro.CodeGenBody != nil {
-		result := macro.CodeGenBody.Eval()
+		result := macro.CodeGenBody.Eval(rt)
 		return result
 	}
 	
@@ -173,6 +181,7 @@ func validateMacroParameterType(argValue *value.Value, expectedTypeName string)
 
 // QuoteExpr представляет оператор quote для создания AST
 type QuoteExpr struct {
+	Node
 	Expr Expr
 }
 
@@ -180,13 +189,14 @@ func NewQuoteExpr(expr Expr) *QuoteExpr {
 	return &QuoteExpr{Expr: expr}
 }
 
-func (q *QuoteExpr) Eval() *value.Value {
+func (q *QuoteExpr) Eval(rt *Runtime) *value.Value {
 	// Quote возвращает AST как значение, не выполняя его
 	return value.NewValue(q.Expr)
 }
 
 // UnquoteExpr представляет оператор unquote для вставки значений в quoted код
 type UnquoteExpr struct {
+	Node
 	Expr Expr
 }
 
@@ -194,13 +204,14 @@ func NewUnquoteExpr(expr Expr) *UnquoteExpr {
 	return &UnquoteExpr{Expr: expr}
 }
 
-func (u *UnquoteExpr) Eval() *value.Value {
+func (u *UnquoteExpr) Eval(rt *Runtime) *value.Value {
 	// Unquote выполняет выражение и возвращает результат
-	return u.Expr.Eval()
+	return u.Expr.Eval(rt)
 }
 
 // ExpandExpr представляет оператор для раскрытия макроса в AST
 type ExpandExpr struct {
+	Node
 	Expr Expr
 }
 
@@ -208,13 +219,13 @@ func NewExpandExpr(expr Expr) *ExpandExpr {
 	return &ExpandExpr{Expr: expr}
 }
 
-func (e *ExpandExpr) Eval() *value.Value {
+func (e *ExpandExpr) Eval(rt *Runtime) *value.Value {
 	// Раскрываем макрос и возвращаем результирующий AST
-	result := e.Expr.Eval()
+	result := e.Expr.Eval(rt)
 	
 	// Если результат - это AST, выполняем его
 	if expr, ok := result.Any().(Expr); ok {
-		return expr.Eval()
+		return expr.Eval(rt)
 	}
 	
 	return result
@@ -222,6 +233,7 @@ func (e *ExpandExpr) Eval() *value.Value {
 
 // ExprBlockExpr представляет блок Expr {} для генерации кода в макросах
 type ExprBlockExpr struct {
+	Node
 	Statements []Expr
 }
 
@@ -229,15 +241,15 @@ func NewExprBlockExpr(statements []Expr) *ExprBlockExpr {
 	return &ExprBlockExpr{Statements: statements}
 }
 
-func (e *ExprBlockExpr) Eval() *value.Value {
+func (e *ExprBlockExpr) Eval(rt *Runtime) *value.Value {
 	// Выполняем все выражения в блоке для генерации кода
 	var result *value.Value = value.NewValue(nil) // Инициализируем значением по умолчанию
 	
 	for _, stmt := range e.Statements {
-		result = stmt.Eval()
+		result = stmt.Eval(rt)
 		
 		// Проверяем специальные флаги (если result не nil)
-		if result != nil && (result.IsReturn() || result.IsBreak()) {
+		if result != nil && (result.IsReturn() || result.IsBreak() || result.IsContinue()) {
 			break
 		}
 	}
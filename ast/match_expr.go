@@ -1,50 +1,85 @@
 package ast
 
 type MatchExpr struct {
+	Node
 	Value Expr
 	Arms  []MatchArm
+
+	checked bool // полнота match над enum уже проверена
 }
 
+// MatchArm - ветка match: паттерн, необязательный guard (x if x > 0) и тело
 type MatchArm struct {
-	Pattern Expr
+	Pattern Pattern
+	Guard   Expr
 	Body    Expr
 }
 
-//	let q =	match 1 > 2 && 1 || false {
-//		1 => 1+22,
-//		2 => ("b"),
-//		true => true + 2,
-//		false => false + 1,
+//	let q =	match value {
+//		1 | 2 => "small",
+//		3..10 => "medium",
+//		Ok(v) => v,
+//		[first, ...rest] => first,
+//		{name, age} if age > 18 => name,
+//		Color.RED => "red",
+//		n => n,
 //		_ => ("a"),
 //	}
-func NewMatchArm(pattern Expr, body Expr) MatchArm {
-	return MatchArm{Pattern: pattern, Body: body}
+func NewMatchArm(pattern Pattern, guard Expr, body Expr) MatchArm {
+	return MatchArm{Pattern: pattern, Guard: guard, Body: body}
 }
 
 func NewMatchExpr(value Expr, arms []MatchArm) *MatchExpr {
 	return &MatchExpr{Value: value, Arms: arms}
 }
 
-func (m *MatchExpr) Eval() *Value {
-
-	for _, arm := range m.Arms {
-		value := m.Value.Eval()
-		pattern := arm.Pattern.Eval()
+func (m *MatchExpr) Eval(rt *Runtime) *Value {
+	m.checkExhaustive(rt)
 
-		if pattern.IsString() && pattern.String() == "_" {
-			return arm.Body.Eval()
-		}
+	subject := orNull(m.Value.Eval(rt))
 
-		if value.IsString() && pattern.IsString() && value.String() == pattern.String() {
-			return arm.Body.Eval()
-		} else if value.IsInt64() && pattern.IsInt64() && value.Int64() == pattern.Int64() {
-			return arm.Body.Eval()
-		} else if value.IsFloat64() && pattern.IsFloat64() && value.Float64() == pattern.Float64() {
-			return arm.Body.Eval()
-		} else if value.IsBool() && pattern.IsBool() && value.Bool() == pattern.Bool() {
-			return arm.Body.Eval()
+	for i := range m.Arms {
+		if result, matched := m.Arms[i].eval(rt, subject); matched {
+			return result
 		}
 	}
 
 	return nil
 }
+
+// eval выполняет ветку, если значение подходит под паттерн и guard.
+// Имена из паттерна живут в собственной области ветки
+func (a *MatchArm) eval(rt *Runtime, subject *Value) (*Value, bool) {
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+
+	if !a.Pattern.Match(rt, subject) {
+		return nil, false
+	}
+
+	if a.Guard != nil && !a.Guard.Eval(rt).Bool() {
+		return nil, false
+	}
+
+	if _, ok := a.Body.(*BodyExpr); ok {
+		return evalBlock(rt, a.Body, nil), true
+	}
+
+	return a.Body.Eval(rt), true
+}
+
+// MatchPattern проверяет совпадение значения с литеральным паттерном ветки match
+func MatchPattern(value, pattern *Value) bool {
+	if value.IsString() && pattern.IsString() {
+		return value.String() == pattern.String()
+	} else if value.IsInt64() && pattern.IsInt64() {
+		return value.Int64() == pattern.Int64()
+	} else if value.IsFloat64() && pattern.IsFloat64() {
+		return value.Float64() == pattern.Float64()
+	} else if value.IsBool() && pattern.IsBool() {
+		return value.Bool() == pattern.Bool()
+	} else if value.Any() == nil && pattern.Any() == nil {
+		return true
+	}
+	return false
+}
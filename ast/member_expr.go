@@ -1,7 +1,13 @@
 package ast
 
+import (
+	"fmt"
+	"foo_lang/value"
+)
+
 // MemberExpr представляет доступ к полю объекта (object.property)
 type MemberExpr struct {
+	Node
 	Object   Expr
 	Property string
 }
@@ -13,33 +19,101 @@ func NewMemberExpr(object Expr, property string) *MemberExpr {
 	}
 }
 
-func (m *MemberExpr) Eval() *Value {
-	obj := m.Object.Eval()
-	
+func (m *MemberExpr) Eval(rt *Runtime) *Value {
+	return GetMember(m.Object.Eval(rt), m.Property)
+}
+
+// GetMember возвращает поле структуры, свойство TypeInfo или значение объекта по имени
+func GetMember(obj *Value, property string) *Value {
 	// Проверяем, является ли объект экземпляром структуры
 	if structObj, ok := obj.Any().(*StructObject); ok {
-		if value, exists := structObj.Fields[m.Property]; exists {
+		if value, exists := structObj.Fields[property]; exists {
 			return value
 		}
-		panic("field '" + m.Property + "' does not exist in struct " + structObj.TypeInfo.Name)
+		panic("field '" + property + "' does not exist in struct " + structObj.TypeInfo.Name)
 	}
 	
 	// Проверяем, является ли объект TypeInfo
 	if typeInfo, ok := obj.Any().(*TypeInfo); ok {
-		property := typeInfo.GetProperty(m.Property)
-		if property != nil {
-			return property
+		propValue := typeInfo.GetProperty(property)
+		if propValue != nil {
+			return propValue
 		}
-		panic("property '" + m.Property + "' does not exist on type")
+		panic("property '" + property + "' does not exist on type")
 	}
 	
-	// Проверяем, что объект - это словарь
-	if objMap, ok := obj.Any().(map[string]*Value); ok {
-		if value, exists := objMap[m.Property]; exists {
+	// Данные варианта enum: Shape.Circle(2.0).r
+	if variant, ok := obj.Any().(*EnumVariant); ok {
+		if value, exists := variant.Get(property); exists {
 			return value
 		}
-		panic("property '" + m.Property + "' does not exist")
+		panic("field '" + property + "' does not exist in " + variant.Enum + "." + variant.Variant)
+	}
+
+	// Ошибка из Err встроенной функции читается как объект ошибки catch (см. ErrorInfo.ToObject)
+	if info, ok := obj.Any().(*ErrorInfo); ok {
+		obj = info.ToObject()
+	}
+
+	// Персистентный словарь Map: элементы неизменяемы
+	if m, ok := obj.Any().(*value.Map); ok {
+		if item, exists := m.Get(property); exists {
+			return frozenElement(obj, item)
+		}
+		panic("property '" + property + "' does not exist")
+	}
+
+	// Проверяем, что объект - это словарь
+	if objMap, ok := obj.Any().(*value.Object); ok {
+		if field, exists := objMap.Get(property); exists {
+			return field
+		}
+		panic("property '" + property + "' does not exist")
 	}
 	
 	panic("cannot access property of non-object")
-}
\ No newline at end of file
+}
+// MemberAssignExpr представляет присваивание полю: this.count = this.count + 1
+type MemberAssignExpr struct {
+	Node
+	Object   Expr
+	Property string
+	Value    Expr
+}
+
+func NewMemberAssignExpr(object Expr, property string, value Expr) *MemberAssignExpr {
+	return &MemberAssignExpr{
+		Object:   object,
+		Property: property,
+		Value:    value,
+	}
+}
+
+func (m *MemberAssignExpr) Eval(rt *Runtime) *Value {
+	obj := m.Object.Eval(rt)
+	val := orNull(m.Value.Eval(rt))
+
+	if IsFrozen(obj) {
+		kind := "object"
+		if structObj, ok := obj.Any().(*StructObject); ok {
+			kind = "struct " + structObj.TypeName()
+		}
+		panic(fmt.Sprintf("cannot assign property '%s' of frozen %s", m.Property, kind))
+	}
+
+	switch target := obj.Any().(type) {
+	case *StructObject:
+		// Структура не получает новых полей
+		if _, exists := target.Fields[m.Property]; !exists {
+			panic("field '" + m.Property + "' does not exist in struct " + target.TypeInfo.Name)
+		}
+		target.checkField(rt, m.Property, val)
+		target.Fields[m.Property] = val
+	case *value.Object:
+		target.Set(m.Property, val)
+	default:
+		panic("cannot assign property of non-object")
+	}
+
+	return val
+}
@@ -3,15 +3,15 @@ package ast
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"foo_lang/value"
-	"foo_lang/scope"
 )
 
 // StructObject представляет экземпляр структуры
 type StructObject struct {
 	TypeInfo *TypeInfo
 	Fields   map[string]*Value
+	TypeArgs []string // аргументы типа generic структуры, параллельно TypeInfo.TypeParams
+	Frozen   bool     // поля нельзя присваивать, см. Freeze
 }
 
 // NewStructObject создает новый экземпляр структуры
@@ -22,8 +22,32 @@ func NewStructObject(typeInfo *TypeInfo, fields map[string]*Value) *StructObject
 	}
 }
 
+// TypeName возвращает имя типа экземпляра с аргументами типа: Box<int>
+func (s *StructObject) TypeName() string {
+	return FormatGenericType(s.TypeInfo.Name, s.TypeArgs)
+}
+
+// checkField проверяет значение поля generic структуры по аргументам типа
+func (s *StructObject) checkField(rt *Runtime, name string, val *Value) {
+	if len(s.TypeInfo.TypeParams) == 0 || val.Any() == nil {
+		return
+	}
+
+	declared := s.TypeInfo.FieldTypes[name]
+	names := TypeParamNames(s.TypeInfo.TypeParams)
+	if !MentionsTypeParam(declared, names) {
+		return
+	}
+
+	expected := SubstituteTypeParams(declared, names, s.TypeArgs)
+	if err := validateVariableType(rt, val, expected); err != nil {
+		panic(fmt.Sprintf("%s field '%s': %v", s.TypeName(), name, err))
+	}
+}
+
 // MethodCallExpr представляет вызов метода объекта (object.method(args))
 type MethodCallExpr struct {
+	Node
 	Object     Expr
 	MethodName string
 	Args       []Expr
@@ -37,44 +61,42 @@ func NewMethodCallExpr(object Expr, methodName string, args []Expr) *MethodCallE
 	}
 }
 
-func (m *MethodCallExpr) Eval() *Value {
-	obj := m.Object.Eval()
-	
+func (m *MethodCallExpr) Eval(rt *Runtime) *Value {
+	obj := m.Object.Eval(rt)
+
+	args := make([]*Value, len(m.Args))
+	for i, arg := range m.Args {
+		args[i] = arg.Eval(rt)
+	}
+
+	rt.calls.at(m)
+	return callResult(CallMethod(rt, obj, m.MethodName, args))
+}
+
+// CallMethod вызывает метод methodName у уже вычисленного объекта с вычисленными аргументами.
+// Используется как tree-walking интерпретатором, так и bytecode VM
+func CallMethod(rt *Runtime, obj *Value, methodName string, args []*Value) *Value {
 	// Методы для экземпляров структур
 	if structObj, ok := obj.Any().(*StructObject); ok {
-		// Проверяем все зарегистрированные интерфейсы для этого типа
-		for _, interfaceName := range getImplementedInterfaces(structObj.TypeInfo.Name) {
-			if impl := GetImplementation(structObj.TypeInfo.Name, interfaceName); impl != nil {
-				// Ищем метод в реализации
-				for _, method := range impl.Methods {
-					if method.FuncName == m.MethodName {
-						// Вычисляем аргументы
-						args := make([]*Value, len(m.Args))
-						for i, arg := range m.Args {
-							args[i] = arg.Eval()
-						}
-						
-						// Вызываем метод с 'this' контекстом
-						return callMethodWithContext(method, obj, args)
-					}
-				}
-			}
+		if method := findStructMethod(rt, structObj, methodName); method != nil {
+			// Вызываем метод с 'this' контекстом
+			return callMethodWithContext(rt, method, obj, args)
 		}
 		
 		// Если метод не найден в интерфейсах, возможно это доступ к полю
-		panic("method '" + m.MethodName + "' not found for struct " + structObj.TypeInfo.Name)
+		panic("method '" + methodName + "' not found for struct " + structObj.TypeInfo.Name)
 	}
 	
 	// Методы для TypeInfo
 	if typeInfo, ok := obj.Any().(*TypeInfo); ok {
-		switch m.MethodName {
+		switch methodName {
 		case "String":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("String() expects no arguments")
 			}
 			return NewValue(typeInfo.String())
 		case "GetFieldNames":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("GetFieldNames() expects no arguments")
 			}
 			names := typeInfo.GetFieldNames()
@@ -84,45 +106,56 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(values)
 		case "GetFieldType":
-			if len(m.Args) != 1 {
+			if len(args) != 1 {
 				panic("GetFieldType() expects exactly 1 argument")
 			}
-			fieldName := m.Args[0].Eval().String()
+			fieldName := args[0].String()
 			fieldType := typeInfo.GetFieldType(fieldName)
 			if fieldType == nil {
 				return NewValue(nil)
 			}
 			return NewValue(fieldType)
 		case "HasField":
-			if len(m.Args) != 1 {
+			if len(args) != 1 {
 				panic("HasField() expects exactly 1 argument")
 			}
-			fieldName := m.Args[0].Eval().String()
+			fieldName := args[0].String()
 			return NewValue(typeInfo.HasField(fieldName))
+		// Варианты enum с данными - для генерации кода по вариантам в макросах
+		case "GetVariantNames":
+			if len(args) != 0 {
+				panic("GetVariantNames() expects no arguments")
+			}
+			return NewValue(typeInfo.GetVariantNames())
+		case "GetVariantFields":
+			if len(args) != 1 {
+				panic("GetVariantFields() expects exactly 1 argument")
+			}
+			return NewValue(typeInfo.GetVariantFields(args[0].String()))
 		// Полиморфные методы проверки типов
 		case "isStruct":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("isStruct() expects no arguments")
 			}
 			return NewValue(typeInfo.Kind == "struct")
 		case "isFunction":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("isFunction() expects no arguments")
 			}
 			return NewValue(typeInfo.Kind == "function")
 		case "isEnum":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("isEnum() expects no arguments")
 			}
 			return NewValue(typeInfo.Kind == "enum")
 		case "isPrimitive":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("isPrimitive() expects no arguments")
 			}
 			return NewValue(typeInfo.Kind == "primitive")
 		// Методы преобразования
 		case "toStruct":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("toStruct() expects no arguments")
 			}
 			if typeInfo.Kind != "struct" {
@@ -130,7 +163,7 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(typeInfo)
 		case "toFunction":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("toFunction() expects no arguments")
 			}
 			if typeInfo.Kind != "function" {
@@ -138,7 +171,7 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(typeInfo)
 		case "toEnum":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("toEnum() expects no arguments")
 			}
 			if typeInfo.Kind != "enum" {
@@ -150,199 +183,106 @@ func (m *MethodCallExpr) Eval() *Value {
 	
 	// Методы для Result типа
 	if result, ok := obj.Any().(*ResultValue); ok {
-		switch m.MethodName {
+		switch methodName {
 		case "isOk":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("isOk() expects no arguments")
 			}
 			return NewValue(result.IsOk())
 		case "isErr":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("isErr() expects no arguments")
 			}
 			return NewValue(result.IsErr())
 		case "unwrap":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("unwrap() expects no arguments")
 			}
 			return result.Unwrap()
+		case "unwrapErr":
+			if len(args) != 0 {
+				panic("unwrapErr() expects no arguments")
+			}
+			return result.UnwrapErr()
 		case "unwrapOr":
-			if len(m.Args) != 1 {
+			if len(args) != 1 {
 				panic("unwrapOr() expects exactly 1 argument")
 			}
-			defaultValue := m.Args[0].Eval()
+			defaultValue := args[0]
 			return result.UnwrapOr(defaultValue)
 		}
 	}
 	
-	// Методы для массивов
-	if arr, ok := obj.Any().([]any); ok {
-		switch m.MethodName {
-		case "push":
-			if len(m.Args) != 1 {
-				panic("push() expects exactly 1 argument")
-			}
-			newItem := m.Args[0].Eval().Any()
-			newArr := append(arr, newItem)
-			return NewValue(newArr)
+	// Методы персистентных List и Map
+	if result, ok := callPersistentMethod(rt, obj, methodName, args); ok {
+		return result
+	}
+
+	// Методы множеств
+	if set, ok := obj.Any().(*value.Set); ok {
+		switch methodName {
 		case "length":
-			if len(m.Args) != 0 {
-				panic("length() expects no arguments")
-			}
-			return NewValue(int64(len(arr)))
-		case "pop":
-			if len(m.Args) != 0 {
-				panic("pop() expects no arguments")
-			}
-			if len(arr) == 0 {
-				panic("pop() called on empty array")
-			}
-			return NewValue(arr[len(arr)-1])
-		case "slice":
-			if len(m.Args) != 2 {
-				panic("slice() expects exactly 2 arguments")
-			}
-			start := int(m.Args[0].Eval().Int64())
-			end := int(m.Args[1].Eval().Int64())
-			if start < 0 || end > len(arr) || start > end {
-				panic("slice() index out of bounds")
-			}
-			return NewValue(arr[start:end])
-		case "map":
-			if len(m.Args) != 1 {
-				panic("map() expects exactly 1 argument (function)")
-			}
-			
-			// Получаем функцию из аргумента
-			fnArg := m.Args[0].Eval()
-			
-			// Проверяем что это вызываемый объект
-			if callable, ok := fnArg.Any().(Callable); ok {
-				result := make([]any, len(arr))
-				
-				// Применяем функцию к каждому элементу
-				for i, item := range arr {
-					// Вызываем функцию с текущим элементом
-					argValue := NewValue(item)
-					mappedValue := callable.Call([]*Value{argValue})
-					result[i] = mappedValue.Any()
-				}
-				
-				return NewValue(result)
-			}
-			
-			panic("map() argument must be a function")
-		case "filter":
-			if len(m.Args) != 1 {
-				panic("filter() expects exactly 1 argument (predicate function)")
-			}
-			
-			// Получаем функцию из аргумента
-			fnArg := m.Args[0].Eval()
-			
-			// Проверяем что это вызываемый объект
-			if callable, ok := fnArg.Any().(Callable); ok {
-				var result []any
-				
-				// Фильтруем элементы через переданную функцию
-				for _, item := range arr {
-					// Вызываем функцию с текущим элементом
-					argValue := NewValue(item)
-					shouldInclude := callable.Call([]*Value{argValue})
-					
-					// Если функция вернула true, добавляем элемент
-					if shouldInclude.Bool() {
-						result = append(result, item)
-					}
-				}
-				
-				return NewValue(result)
-			}
-			
-			panic("filter() argument must be a function")
-		case "reduce":
-			if len(m.Args) != 2 {
-				panic("reduce() expects 2 arguments (initial value, reducer function)")
-			}
-			
-			initialValue := m.Args[0].Eval()
-			fnArg := m.Args[1].Eval()
-			
-			// Проверяем что второй аргумент - функция
-			if callable, ok := fnArg.Any().(Callable); ok {
-				accumulator := initialValue
-				
-				// Применяем reducer функцию к каждому элементу
-				for _, item := range arr {
-					// Вызываем функцию с аккумулятором и текущим элементом
-					accValue := accumulator
-					itemValue := NewValue(item)
-					accumulator = callable.Call([]*Value{accValue, itemValue})
-				}
-				
-				return accumulator
-			}
-			
-			panic("reduce() second argument must be a function")
+			expectArgs("Set", methodName, args, 0)
+			return NewValue(int64(set.Len()))
+		case "has":
+			expectArgs("Set", methodName, args, 1)
+			return NewValue(set.Has(args[0].Any()))
+		case "add":
+			expectArgs("Set", methodName, args, 1)
+			if IsFrozen(obj) {
+				panic("cannot add to frozen set")
+			}
+			if err := set.Add(args[0].Any()); err != nil {
+				panic(err.Error())
+			}
+			return obj
+		case "remove":
+			expectArgs("Set", methodName, args, 1)
+			if IsFrozen(obj) {
+				panic("cannot remove from frozen set")
+			}
+			return NewValue(set.Remove(args[0].Any()))
+		case "union", "intersection", "difference":
+			expectArgs("Set", methodName, args, 1)
+			other, ok := args[0].Any().(*value.Set)
+			if !ok {
+				panic(fmt.Sprintf("Set.%s() expects a set, got %s", methodName, value.GetValueTypeName(args[0])))
+			}
+			switch methodName {
+			case "union":
+				return NewValue(set.Union(other))
+			case "intersection":
+				return NewValue(set.Intersection(other))
+			}
+			return NewValue(set.Difference(other))
+		case "toArray":
+			expectArgs("Set", methodName, args, 0)
+			return NewValue(set.Items())
 		}
 	}
-	
-	// Методы для строк
-	if str, ok := obj.Any().(string); ok {
-		switch m.MethodName {
+
+	// Методы кортежей
+	if tuple, ok := obj.Any().(*value.Tuple); ok {
+		switch methodName {
 		case "length":
-			if len(m.Args) != 0 {
-				panic("string.length() expects no arguments")
-			}
-			return NewValue(int64(len(str)))
-		case "charAt":
-			if len(m.Args) != 1 {
-				panic("string.charAt() expects exactly 1 argument")
-			}
-			index := int(m.Args[0].Eval().Int64())
-			if index < 0 || index >= len(str) {
-				panic("string.charAt() index out of bounds")
-			}
-			return NewValue(string(str[index]))
-		case "substring":
-			if len(m.Args) != 2 {
-				panic("string.substring() expects exactly 2 arguments")
-			}
-			start := int(m.Args[0].Eval().Int64())
-			end := int(m.Args[1].Eval().Int64())
-			if start < 0 {
-				start = 0
-			}
-			if end > len(str) {
-				end = len(str)
-			}
-			if start > end {
-				start = end
-			}
-			return NewValue(str[start:end])
-		case "toUpper":
-			if len(m.Args) != 0 {
-				panic("string.toUpper() expects no arguments")
-			}
-			return NewValue(strings.ToUpper(str))
-		case "toLower":
-			if len(m.Args) != 0 {
-				panic("string.toLower() expects no arguments")
-			}
-			return NewValue(strings.ToLower(str))
+			expectArgs("Tuple", methodName, args, 0)
+			return NewValue(int64(tuple.Len()))
+		case "toArray":
+			expectArgs("Tuple", methodName, args, 0)
+			return NewValue(tuple.Items())
 		}
 	}
-	
+
 	// Методы для int64
 	if num, ok := obj.Any().(int64); ok {
-		switch m.MethodName {
+		switch methodName {
 		case "toString":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("int.toString() expects no arguments")
 			}
 			return NewValue(strconv.FormatInt(num, 10))
 		case "abs":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("int.abs() expects no arguments")
 			}
 			if num < 0 {
@@ -350,7 +290,7 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(num)
 		case "toFloat":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("int.toFloat() expects no arguments")
 			}
 			return NewValue(float64(num))
@@ -359,14 +299,14 @@ func (m *MethodCallExpr) Eval() *Value {
 	
 	// Методы для int (32-bit)
 	if num, ok := obj.Any().(int); ok {
-		switch m.MethodName {
+		switch methodName {
 		case "toString":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("int.toString() expects no arguments")
 			}
 			return NewValue(strconv.Itoa(num))
 		case "abs":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("int.abs() expects no arguments")
 			}
 			if num < 0 {
@@ -374,7 +314,7 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(num)
 		case "toFloat":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("int.toFloat() expects no arguments")
 			}
 			return NewValue(float64(num))
@@ -383,24 +323,24 @@ func (m *MethodCallExpr) Eval() *Value {
 	
 	// Методы для float64 (все числа в foo_lang)
 	if num, ok := obj.Any().(float64); ok {
-		switch m.MethodName {
+		switch methodName {
 		case "toString":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.toString() expects no arguments")
 			}
 			return NewValue(strconv.FormatFloat(num, 'f', -1, 64))
 		case "round":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.round() expects no arguments")
 			}
 			return NewValue(float64(int(num + 0.5)))
 		case "floor":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.floor() expects no arguments")
 			}
 			return NewValue(float64(int(num)))
 		case "ceil":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.ceil() expects no arguments")
 			}
 			if num == float64(int(num)) {
@@ -408,13 +348,13 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(float64(int(num) + 1))
 		case "toInt":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.toInt() expects no arguments")
 			}
 			return NewValue(int64(num))
 		// Добавляем методы которые были для int
 		case "abs":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.abs() expects no arguments")
 			}
 			if num < 0 {
@@ -422,12 +362,12 @@ func (m *MethodCallExpr) Eval() *Value {
 			}
 			return NewValue(num)
 		case "toFloat":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.toFloat() expects no arguments")
 			}
 			return NewValue(num)  // уже float64
 		case "isInteger":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("number.isInteger() expects no arguments")
 			}
 			return NewValue(num == float64(int64(num)))
@@ -436,73 +376,68 @@ func (m *MethodCallExpr) Eval() *Value {
 	
 	// Методы для bool
 	if b, ok := obj.Any().(bool); ok {
-		switch m.MethodName {
+		switch methodName {
 		case "toString":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("bool.toString() expects no arguments")
 			}
 			return NewValue(strconv.FormatBool(b))
 		case "not":
-			if len(m.Args) != 0 {
+			if len(args) != 0 {
 				panic("bool.not() expects no arguments")
 			}
 			return NewValue(!b)
 		}
 	}
 	
-	// Методы для объектов (map[string]*value.Value) - глобальные объекты типа IO, System и т.д.
-	if objectMap, ok := obj.Any().(map[string]*value.Value); ok {
-		if method, exists := objectMap[m.MethodName]; exists {
+	// Методы для объектов (value.Object) - глобальные объекты типа IO, System и т.д.
+	if objectMap, ok := obj.Any().(*value.Object); ok {
+		if method, exists := objectMap.Get(methodName); exists {
 			// Проверяем что это функция
 			if fn, isFn := method.Any().(func([]*value.Value) *value.Value); isFn {
-				// Вычисляем аргументы
-				args := make([]*value.Value, len(m.Args))
-				for i, arg := range m.Args {
-					// Конвертируем AST Value в value.Value
-					astValue := arg.Eval()
-					args[i] = value.NewValue(astValue.Any())
+				// Конвертируем AST Value в value.Value
+				callArgs := make([]*value.Value, len(args))
+				for i, arg := range args {
+					callArgs[i] = value.NewValue(arg.Any())
 				}
 				// Вызываем функцию и конвертируем результат обратно
-				result := fn(args)
+				result := fn(callArgs)
 				return NewValue(result.Any())
 			}
+			// Функции foo в объекте модуля: import * as fs from "std:fs"; fs.readFile(p)
+			if fn, isCallable := method.Any().(Callable); isCallable {
+				return CallIn(rt, fn, args)
+			}
 		}
 		// Если метод не найден, продолжаем поиск
 	}
 	
-	// Проверяем extension методы
+	// Проверяем extension методы: они могут переопределить встроенные методы
+	// массивов и строк из таблицы builtinMethods
 	typeName := value.GetValueTypeName(obj)
-	if extensionMethod, ok := value.GetExtensionMethod(typeName, m.MethodName); ok {
+	if extensionMethod, ok := rt.Extensions.Get(typeName, methodName); ok {
 		if wrapper, ok := extensionMethod.(*ExtensionMethodWrapper); ok {
-			// Вычисляем аргументы
-			args := make([]*Value, len(m.Args))
-			for i, arg := range m.Args {
-				args[i] = arg.Eval()
-			}
 			// Вызываем extension метод с receiver (this) как первым аргументом
-			return wrapper.Call(obj, args)
+			return wrapper.Call(rt, obj, args)
 		}
 	}
+	if method, ok := LookupBuiltinMethod(typeName, methodName); ok {
+		return method.Call(rt, typeName, obj, args)
+	}
 	
 	// Проверяем interface методы
 	if typeInfo, ok := obj.Any().(*TypeInfo); ok {
 		// Для объекта структуры, ищем методы интерфейса
-		if impl := GetImplementation(typeInfo.Name, ""); impl != nil {
+		if impl := rt.GetImplementation(typeInfo.Name, ""); impl != nil {
 			// Ищем метод в реализации
 			for _, method := range impl.Methods {
-				if method.FuncName == m.MethodName {
-					// Вычисляем аргументы
-					args := make([]*Value, len(m.Args))
-					for i, arg := range m.Args {
-						args[i] = arg.Eval()
-					}
-					
+				if method.FuncName == methodName {
 					// Создаем временную область видимости для метода
 					// TODO: Нужно установить 'this' как текущий объект
 					
 					// Пока что вызываем метод как обычную функцию  
-					closure := NewTypedClosure(method.FuncName, method.Params, method.Body, method.ReturnType)
-					return closure.Call(args)
+					closure := NewTypedClosure(rt, method.FuncName, method.Params, method.Body, method.ReturnType)
+					return closure.CallIn(rt, args)
 				}
 			}
 		}
@@ -511,19 +446,13 @@ func (m *MethodCallExpr) Eval() *Value {
 	// Если объект - это экземпляр структуры (не TypeInfo), попробуем найти его тип
 	if typeInfo := getObjectTypeInfo(obj); typeInfo != nil {
 		// Проверяем все зарегистрированные интерфейсы для этого типа
-		for _, interfaceName := range getImplementedInterfaces(typeInfo.Name) {
-			if impl := GetImplementation(typeInfo.Name, interfaceName); impl != nil {
+		for _, interfaceName := range rt.ImplementedInterfaces(typeInfo.Name) {
+			if impl := rt.GetImplementation(typeInfo.Name, interfaceName); impl != nil {
 				// Ищем метод в реализации
 				for _, method := range impl.Methods {
-					if method.FuncName == m.MethodName {
-						// Вычисляем аргументы
-						args := make([]*Value, len(m.Args))
-						for i, arg := range m.Args {
-							args[i] = arg.Eval()
-						}
-						
+					if method.FuncName == methodName {
 						// Вызываем метод с 'this' контекстом
-						return callMethodWithContext(method, obj, args)
+						return callMethodWithContext(rt, method, obj, args)
 					}
 				}
 			}
@@ -532,7 +461,38 @@ func (m *MethodCallExpr) Eval() *Value {
 	
 	// Отладка: покажем тип объекта
 	objType := fmt.Sprintf("%T", obj.Any())
-	panic("method '" + m.MethodName + "' not supported on type: " + objType)
+	panic("method '" + methodName + "' not supported on type: " + objType)
+}
+
+// findStructMethod ищет метод структуры во всех реализованных ею интерфейсах
+func findStructMethod(rt *Runtime, structObj *StructObject, methodName string) *TypedFuncStatement {
+	for _, interfaceName := range rt.ImplementedInterfaces(structObj.TypeInfo.Name) {
+		if impl := rt.GetImplementation(structObj.TypeInfo.Name, interfaceName); impl != nil {
+			for _, method := range impl.Methods {
+				if method.FuncName == methodName {
+					return method
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// implTypeParams возвращает имена параметров типа из impl блока метода
+// (impl Container<T> for Box<T>), а без них - параметры самой структуры
+func implTypeParams(rt *Runtime, structObj *StructObject, method *TypedFuncStatement) []string {
+	for _, interfaceName := range rt.ImplementedInterfaces(structObj.TypeInfo.Name) {
+		impl := rt.GetImplementation(structObj.TypeInfo.Name, interfaceName)
+		if impl == nil || len(impl.TypeParams) == 0 {
+			continue
+		}
+		for _, m := range impl.Methods {
+			if m == method {
+				return impl.TypeParams
+			}
+		}
+	}
+	return TypeParamNames(structObj.TypeInfo.TypeParams)
 }
 
 // getObjectTypeInfo пытается определить TypeInfo для объекта
@@ -544,30 +504,40 @@ func getObjectTypeInfo(obj *Value) *TypeInfo {
 	return nil
 }
 
-// getImplementedInterfaces возвращает список интерфейсов, реализованных типом
-func getImplementedInterfaces(typeName string) []string {
-	var interfaces []string
-	for interfaceName := range interfaceImplementations[typeName] {
-		interfaces = append(interfaces, interfaceName)
-	}
-	return interfaces
-}
-
 // callMethodWithContext вызывает метод интерфейса с установленным 'this' контекстом
-func callMethodWithContext(method *TypedFuncStatement, thisObj *Value, args []*Value) *Value {
+func callMethodWithContext(rt *Runtime, method *TypedFuncStatement, thisObj *Value, args []*Value) (result *Value) {
+	rt.calls.push(value.GetValueTypeName(thisObj)+"."+method.FuncName, method.Body)
+	defer func() { rt.calls.leave(recover()) }()
+	defer ReturnPropagated(&result)
+
 	// Создаем временную область видимости
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+	defer runDeferred(rt)
 	
 	// Устанавливаем 'this' в области видимости
-	scope.GlobalScope.Set("this", thisObj)
+	rt.Scope().Set("this", thisObj)
+
+	// Параметры типа generic структуры связываются с аргументами типа экземпляра
+	var typeParams []string
+	structObj, isStruct := thisObj.Any().(*StructObject)
+	if isStruct && len(structObj.TypeArgs) > 0 {
+		typeParams = implTypeParams(rt, structObj, method)
+		bindTypeArgs(rt, typeParams, structObj.TypeArgs)
+	}
 	
 	// Устанавливаем параметры метода
 	for i, param := range method.Params {
 		if i < len(args) {
-			scope.GlobalScope.Set(param.Name, args[i])
+			if MentionsTypeParam(param.TypeName, typeParams) {
+				expected := SubstituteTypeParams(param.TypeName, typeParams, structObj.TypeArgs)
+				if err := validateVariableType(rt, args[i], expected); err != nil {
+					panic(fmt.Sprintf("method '%s.%s' parameter '%s': %v", structObj.TypeName(), method.FuncName, param.Name, err))
+				}
+			}
+			rt.Scope().Set(param.Name, args[i])
 		} else if param.Default != nil {
-			scope.GlobalScope.Set(param.Name, param.Default.Eval())
+			rt.Scope().Set(param.Name, param.Default.Eval(rt))
 		} else {
 			panic(fmt.Sprintf("missing required argument: %s", param.Name))
 		}
@@ -576,13 +546,14 @@ func callMethodWithContext(method *TypedFuncStatement, thisObj *Value, args []*V
 	// Выполняем тело метода
 	if bodyStm, ok := method.Body.(*BodyExpr); ok {
 		for _, stmt := range bodyStm.Statments {
-			result := stmt.Eval()
+			rt.calls.at(stmt)
+			result := stmt.Eval(rt)
 			if result != nil && result.IsReturn() {
 				return result
 			}
 		}
 		return NewValue(nil)
 	} else {
-		return method.Body.Eval()
+		return method.Body.Eval(rt)
 	}
 }
\ No newline at end of file
@@ -61,6 +61,11 @@ func (s *MethodSignature) IsCompatible(argTypes []string) bool {
 		if (paramType == "float" && argType == "int") {
 			continue
 		}
+
+		// Тип кортежа (int,string) принимает кортеж или массив
+		if strings.HasPrefix(paramType, "(") && (argType == "tuple" || argType == "array") {
+			continue
+		}
 		
 		// Несовместимые типы
 		return false
@@ -185,18 +190,18 @@ func CreateSignatureFromFunction(name string, params []FuncParam, returnType str
 	}
 }
 
-// Глобальный реестр перегруженных методов
-var overloadedMethods = make(map[string]*OverloadedMethod)
-
 // RegisterOverloadedMethod регистрирует перегруженный метод
-func RegisterOverloadedMethod(signature *MethodSignature, function Callable) error {
+func (rt *Runtime) RegisterOverloadedMethod(signature *MethodSignature, function Callable) error {
 	methodName := signature.Name
 	
+	rt.registry.mu.Lock()
+	defer rt.registry.mu.Unlock()
+	
 	// Получаем или создаем перегруженный метод
-	overloadedMethod, exists := overloadedMethods[methodName]
+	overloadedMethod, exists := rt.registry.overloads[methodName]
 	if !exists {
 		overloadedMethod = NewOverloadedMethod(methodName)
-		overloadedMethods[methodName] = overloadedMethod
+		rt.registry.overloads[methodName] = overloadedMethod
 	}
 	
 	// Добавляем перегрузку
@@ -204,8 +209,11 @@ func RegisterOverloadedMethod(signature *MethodSignature, function Callable) err
 }
 
 // ResolveMethodOverload разрешает вызов перегруженного метода
-func ResolveMethodOverload(methodName string, argTypes []string) (Callable, error) {
-	overloadedMethod, exists := overloadedMethods[methodName]
+func (rt *Runtime) ResolveMethodOverload(methodName string, argTypes []string) (Callable, error) {
+	rt.registry.mu.RLock()
+	overloadedMethod, exists := rt.registry.overloads[methodName]
+	rt.registry.mu.RUnlock()
+	
 	if !exists {
 		return nil, fmt.Errorf("method '%s' is not overloaded", methodName)
 	}
@@ -219,12 +227,18 @@ func ResolveMethodOverload(methodName string, argTypes []string) (Callable, erro
 }
 
 // IsOverloadedMethod проверяет, является ли метод перегруженным
-func IsOverloadedMethod(methodName string) bool {
-	_, exists := overloadedMethods[methodName]
+func (rt *Runtime) IsOverloadedMethod(methodName string) bool {
+	rt.registry.mu.RLock()
+	defer rt.registry.mu.RUnlock()
+	
+	_, exists := rt.registry.overloads[methodName]
 	return exists
 }
 
-// ClearOverloadedMethods очищает реестр перегруженных методов (для тестов)
+// ClearOverloadedMethods очищает реестр перегруженных методов GlobalRuntime (для тестов)
 func ClearOverloadedMethods() {
-	overloadedMethods = make(map[string]*OverloadedMethod)
-}
\ No newline at end of file
+	GlobalRuntime.registry.mu.Lock()
+	defer GlobalRuntime.registry.mu.Unlock()
+	
+	clear(GlobalRuntime.registry.overloads)
+}
@@ -1,9 +1,10 @@
 package ast
 
-import "foo_lang/scope"
+import "foo_lang/value"
 
 // MultiAssignExpr represents multiple assignment: let a, b = func()
 type MultiAssignExpr struct {
+	Node
 	Names []string // Variable names to assign to
 	Expr  Expr     // Expression that returns multiple values
 }
@@ -15,9 +16,9 @@ func NewMultiAssignExpr(names []string, expr Expr) *MultiAssignExpr {
 	}
 }
 
-func (m *MultiAssignExpr) Eval() *Value {
+func (m *MultiAssignExpr) Eval(rt *Runtime) *Value {
 	// Evaluate the expression that should return multiple values
-	result := m.Expr.Eval()
+	result := m.Expr.Eval(rt)
 	
 	if result == nil {
 		panic("expression returned nil, expected multiple values")
@@ -28,17 +29,23 @@ func (m *MultiAssignExpr) Eval() *Value {
 		// Assign each value to corresponding variable
 		for i, name := range m.Names {
 			if i < len(values) {
-				scope.GlobalScope.Set(name, values[i])
+				rt.Scope().Set(name, values[i])
 			} else {
 				// If fewer values than names, assign nil
-				scope.GlobalScope.Set(name, NewValue(nil))
+				rt.Scope().Set(name, NewValue(nil))
 			}
 		}
+	} else if tuple, ok := result.Any().(*value.Tuple); ok {
+		// Tuple destructuring: let a, b = (1, "x")
+		for i, name := range m.Names {
+			item, _ := tuple.Get(i)
+			rt.Scope().Set(name, NewValue(item))
+		}
 	} else {
 		// Single value case - assign to first variable, rest get nil
-		scope.GlobalScope.Set(m.Names[0], result)
+		rt.Scope().Set(m.Names[0], result)
 		for i := 1; i < len(m.Names); i++ {
-			scope.GlobalScope.Set(m.Names[i], NewValue(nil))
+			rt.Scope().Set(m.Names[i], NewValue(nil))
 		}
 	}
 	
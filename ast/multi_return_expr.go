@@ -2,6 +2,7 @@ package ast
 
 // MultiReturnExpr represents multiple return values: return a, b, c
 type MultiReturnExpr struct {
+	Node
 	Values []Expr // Multiple expressions to return
 }
 
@@ -9,11 +10,11 @@ func NewMultiReturnExpr(values []Expr) *MultiReturnExpr {
 	return &MultiReturnExpr{Values: values}
 }
 
-func (m *MultiReturnExpr) Eval() *Value {
+func (m *MultiReturnExpr) Eval(rt *Runtime) *Value {
 	// Evaluate all return values
 	var results []*Value
 	for _, expr := range m.Values {
-		results = append(results, expr.Eval())
+		results = append(results, expr.Eval(rt))
 	}
 	
 	// Create a special return value that contains multiple values
@@ -1,13 +1,15 @@
 package ast
 
 // NullExpr представляет null литерал
-type NullExpr struct {}
+type NullExpr struct {
+	Node
+}
 
 func NewNullExpr() *NullExpr {
 	return &NullExpr{}
 }
 
-func (n *NullExpr) Eval() *Value {
+func (n *NullExpr) Eval(rt *Runtime) *Value {
 	// Null возвращает специальное значение с nil
 	return NewValue(nil)
 }
\ No newline at end of file
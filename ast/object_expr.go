@@ -1,18 +1,22 @@
 package ast
 
+import "foo_lang/value"
+
 // ObjectExpr представляет объект как словарь ключ-значение
 type ObjectExpr struct {
+	Node
+	Keys   []string // ключи в порядке записи в литерале
 	Fields map[string]Expr
 }
 
-func NewObjectExpr(fields map[string]Expr) *ObjectExpr {
-	return &ObjectExpr{Fields: fields}
+func NewObjectExpr(keys []string, fields map[string]Expr) *ObjectExpr {
+	return &ObjectExpr{Keys: keys, Fields: fields}
 }
 
-func (o *ObjectExpr) Eval() *Value {
-	result := make(map[string]*Value)
-	for key, expr := range o.Fields {
-		result[key] = expr.Eval()
+func (o *ObjectExpr) Eval(rt *Runtime) *Value {
+	result := value.NewObject()
+	for _, key := range o.Keys {
+		result.Set(key, o.Fields[key].Eval(rt))
 	}
 	return NewValue(result)
-}
\ No newline at end of file
+}
@@ -0,0 +1,236 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/token"
+	"foo_lang/value"
+)
+
+// Встроенные интерфейсы операторов. Структура, реализующая такой интерфейс
+// через impl, участвует в соответствующем операторе:
+//
+//	impl Add for Vec { fn add(other: Vec) -> Vec { ... } }
+//
+// делает a + b вызовом a.add(b). Бинарный оператор выбирается по левому операнду.
+// Параметры и результат методов не типизированы - impl может указать свои типы
+var operatorInterfaces = []*InterfaceDefinition{
+	operatorInterface("Add", "add", "other"),
+	operatorInterface("Sub", "sub", "other"),
+	operatorInterface("Mul", "mul", "other"),
+	operatorInterface("Div", "div", "other"),
+	operatorInterface("Rem", "rem", "other"),
+	operatorInterface("Neg", "neg"),
+	operatorInterface("Eq", "eq", "other"),   // == и !=, результат bool
+	operatorInterface("Ord", "cmp", "other"), // < <= > >=, результат int: <0, 0, >0
+	operatorInterface("Index", "index", "key"),
+	operatorInterface("Display", "toString"), // интерполяция, print, конкатенация со строкой
+}
+
+func operatorInterface(name, method string, params ...string) *InterfaceDefinition {
+	funcParams := make([]FuncParam, len(params))
+	for i, param := range params {
+		funcParams[i] = FuncParam{Name: param}
+	}
+	return NewInterfaceDefinition(name, []InterfaceMethod{{Name: method, Params: funcParams}})
+}
+
+// OperatorInterfaces возвращает встроенные интерфейсы операторов
+func OperatorInterfaces() []*InterfaceDefinition {
+	return append([]*InterfaceDefinition(nil), operatorInterfaces...)
+}
+
+// operatorMethodName возвращает имя метода встроенного интерфейса оператора
+func operatorMethodName(iface string) string {
+	for _, def := range operatorInterfaces {
+		if def.Name == iface {
+			return def.Methods[0].Name
+		}
+	}
+	return ""
+}
+
+// binaryOperators - интерфейсы арифметических операторов
+var binaryOperators = map[token.Token]string{
+	token.ADD: "Add",
+	token.SUB: "Sub",
+	token.MUL: "Mul",
+	token.QUO: "Div",
+	token.REM: "Rem",
+}
+
+// operatorMethod возвращает метод встроенного интерфейса iface, если значение -
+// экземпляр структуры с impl этого интерфейса
+func operatorMethod(rt *Runtime, v *Value, iface string) *TypedFuncStatement {
+	if v == nil {
+		return nil
+	}
+	structObj, ok := v.Any().(*StructObject)
+	if !ok {
+		return nil
+	}
+
+	impl := rt.GetImplementation(structObj.TypeInfo.Name, iface)
+	if impl == nil {
+		return nil
+	}
+
+	name := operatorMethodName(iface)
+	for _, method := range impl.Methods {
+		if method.FuncName == name {
+			return method
+		}
+	}
+	return nil
+}
+
+// operandType возвращает имя типа структуры для сообщений об ошибках: Vec, Box<int>
+func operandType(v *Value) string {
+	return v.Any().(*StructObject).TypeName()
+}
+
+// callOperator вызывает метод оператора и снимает с результата флаг return
+func callOperator(rt *Runtime, method *TypedFuncStatement, receiver *Value, args ...*Value) *Value {
+	result := callMethodWithContext(rt, method, receiver, args)
+	if result == nil {
+		return NewValue(nil)
+	}
+	if result.IsReturn() {
+		result = NewValue(result.Any())
+	}
+	return result
+}
+
+// OverloadedBinary вычисляет бинарный оператор через интерфейс левого операнда.
+// false - левый операнд не перегружает оператор op
+func OverloadedBinary(rt *Runtime, op token.Token, left, right *Value) (*Value, bool) {
+	if left == nil || right == nil {
+		return nil, false
+	}
+
+	// Массивы сравниваются поэлементно тем же правилом: [a] == [b] вызывает a.eq(b)
+	if op == token.EQ_EQ || op == token.NOT_EQ {
+		if leftItems, ok := left.Any().([]any); ok {
+			if rightItems, ok := right.Any().([]any); ok {
+				return NewValue(equalItems(rt, leftItems, rightItems) == (op == token.EQ_EQ)), true
+			}
+		}
+	}
+
+	if _, ok := left.Any().(*StructObject); !ok {
+		return nil, false
+	}
+
+	if iface, ok := binaryOperators[op]; ok {
+		if method := operatorMethod(rt, left, iface); method != nil {
+			return callOperator(rt, method, left, right), true
+		}
+		return nil, false
+	}
+
+	switch op {
+	case token.EQ_EQ, token.NOT_EQ:
+		method := operatorMethod(rt, left, "Eq")
+		if method == nil {
+			return nil, false
+		}
+		result := callOperator(rt, method, left, right)
+		if !result.IsBool() {
+			panic(fmt.Sprintf("%s.eq() must return bool, got %s", operandType(left), value.GetValueTypeName(result)))
+		}
+		return NewValue(result.Bool() == (op == token.EQ_EQ)), true
+
+	case token.LT, token.GT, token.LT_EQ, token.GT_EQ:
+		method := operatorMethod(rt, left, "Ord")
+		if method == nil {
+			return nil, false
+		}
+		order := callCmp(rt, method, left, right)
+		switch op {
+		case token.LT:
+			return NewValue(order < 0), true
+		case token.GT:
+			return NewValue(order > 0), true
+		case token.LT_EQ:
+			return NewValue(order <= 0), true
+		}
+		return NewValue(order >= 0), true
+	}
+
+	return nil, false
+}
+
+// callCmp вызывает cmp() интерфейса Ord и проверяет, что результат - int
+func callCmp(rt *Runtime, method *TypedFuncStatement, left, right *Value) int64 {
+	result := callOperator(rt, method, left, right)
+	if !result.IsInt64() {
+		panic(fmt.Sprintf("%s.cmp() must return int, got %s", operandType(left), value.GetValueTypeName(result)))
+	}
+	return result.Int64()
+}
+
+// Equal сравнивает значения как ==: структуры с impl Eq - через eq(),
+// массивы - поэлементно, остальные значения - через value.Equal
+func Equal(rt *Runtime, a, b *Value) bool {
+	if result, ok := OverloadedBinary(rt, token.EQ_EQ, a, b); ok {
+		return result.Bool()
+	}
+	return value.Equal(a, b).Bool()
+}
+
+// equalItems сравнивает элементы массивов через Equal
+func equalItems(rt *Runtime, a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(rt, NewValue(a[i]), NewValue(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare упорядочивает значения как <: структуры с impl Ord - через cmp(),
+// остальные значения - через value.Compare
+func Compare(rt *Runtime, a, b *Value) (int, error) {
+	if method := operatorMethod(rt, a, "Ord"); method != nil {
+		order := callCmp(rt, method, a, b)
+		switch {
+		case order < 0:
+			return -1, nil
+		case order > 0:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return value.Compare(a, b)
+}
+
+// OverloadedNegate вычисляет унарный минус через интерфейс Neg
+func OverloadedNegate(rt *Runtime, operand *Value) (*Value, bool) {
+	if method := operatorMethod(rt, operand, "Neg"); method != nil {
+		return callOperator(rt, method, operand), true
+	}
+	return nil, false
+}
+
+// OverloadedIndex вычисляет obj[key] через интерфейс Index
+func OverloadedIndex(rt *Runtime, obj, key *Value) (*Value, bool) {
+	if method := operatorMethod(rt, obj, "Index"); method != nil {
+		return callOperator(rt, method, obj, key), true
+	}
+	return nil, false
+}
+
+// Display форматирует значение для вывода: структуры с impl Display
+// выводятся через toString(), остальные значения - через FormatValue
+func Display(rt *Runtime, v *Value) string {
+	if method := operatorMethod(rt, v, "Display"); method != nil {
+		result := callOperator(rt, method, v)
+		if !result.IsString() {
+			panic(fmt.Sprintf("%s.toString() must return string, got %s", operandType(v), value.GetValueTypeName(result)))
+		}
+		return result.String()
+	}
+	return FormatValue(v.Any())
+}
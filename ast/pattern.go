@@ -0,0 +1,340 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+	"strings"
+)
+
+// Pattern - паттерн ветки match. Match проверяет значение и связывает имена
+// в текущей области видимости (match создает ее для каждой ветки)
+type Pattern interface {
+	Match(rt *Runtime, v *Value) bool
+}
+
+// WildcardPattern - _: подходит любое значение, ничего не связывает
+type WildcardPattern struct{}
+
+func NewWildcardPattern() *WildcardPattern {
+	return &WildcardPattern{}
+}
+
+func (w *WildcardPattern) Match(rt *Runtime, v *Value) bool {
+	return true
+}
+
+// BindingPattern - имя: подходит любое значение, которое становится переменной ветки
+type BindingPattern struct {
+	Name string
+}
+
+func NewBindingPattern(name string) *BindingPattern {
+	return &BindingPattern{Name: name}
+}
+
+func (b *BindingPattern) Match(rt *Runtime, v *Value) bool {
+	rt.Scope().Set(b.Name, orNull(v))
+	return true
+}
+
+// ValuePattern - литерал или выражение, с которым значение сравнивается через MatchPattern
+type ValuePattern struct {
+	Expr Expr
+}
+
+func NewValuePattern(expr Expr) *ValuePattern {
+	return &ValuePattern{Expr: expr}
+}
+
+func (p *ValuePattern) Match(rt *Runtime, v *Value) bool {
+	return MatchPattern(v, p.Expr.Eval(rt))
+}
+
+// RangePattern - 1..10 (верхняя граница не включается) или 1..=10 (включается),
+// как и у диапазонов в for-in
+type RangePattern struct {
+	Low       Expr
+	High      Expr
+	Inclusive bool
+}
+
+func NewRangePattern(low, high Expr, inclusive bool) *RangePattern {
+	return &RangePattern{Low: low, High: high, Inclusive: inclusive}
+}
+
+func (r *RangePattern) Match(rt *Runtime, v *Value) bool {
+	low, high := r.Low.Eval(rt), r.High.Eval(rt)
+
+	if v.IsNumber() && low.IsNumber() && high.IsNumber() {
+		n := v.Float64()
+		return n >= low.Float64() && (n < high.Float64() || r.Inclusive && n == high.Float64())
+	}
+
+	if v.IsString() && low.IsString() && high.IsString() {
+		s := v.String()
+		return s >= low.String() && (s < high.String() || r.Inclusive && s == high.String())
+	}
+
+	return false
+}
+
+// OrPattern - 1 | 2: подходит, если подходит любая альтернатива
+type OrPattern struct {
+	Alternatives []Pattern
+}
+
+func NewOrPattern(alternatives []Pattern) *OrPattern {
+	return &OrPattern{Alternatives: alternatives}
+}
+
+func (o *OrPattern) Match(rt *Runtime, v *Value) bool {
+	for _, alt := range o.Alternatives {
+		if alt.Match(rt, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultPattern - Ok(p) или Err(p): проверяет вариант Result и сопоставляет его содержимое
+type ResultPattern struct {
+	Ok    bool
+	Inner Pattern
+}
+
+func NewResultPattern(ok bool, inner Pattern) *ResultPattern {
+	return &ResultPattern{Ok: ok, Inner: inner}
+}
+
+func (r *ResultPattern) Match(rt *Runtime, v *Value) bool {
+	result, ok := v.Any().(*ResultValue)
+	if !ok || result.IsOk() != r.Ok {
+		return false
+	}
+	return r.Inner.Match(rt, orNull(result.GetValue()))
+}
+
+// EnumPattern - Color.RED: значение варианта enum (или поля любого объекта).
+// Shape.Circle(r) дополнительно сопоставляет данные варианта по позиции;
+// Shape.Circle без скобок подходит для любых данных
+type EnumPattern struct {
+	Enum       string
+	Variant    string
+	Payload    []Pattern
+	HasPayload bool
+}
+
+func NewEnumPattern(enum, variant string) *EnumPattern {
+	return &EnumPattern{Enum: enum, Variant: variant}
+}
+
+func NewEnumPayloadPattern(enum, variant string, payload []Pattern) *EnumPattern {
+	return &EnumPattern{Enum: enum, Variant: variant, Payload: payload, HasPayload: true}
+}
+
+func (e *EnumPattern) Match(rt *Runtime, v *Value) bool {
+	enumVal, ok := rt.Scope().Get(e.Enum)
+	if !ok {
+		panic("enum '" + e.Enum + "' is not defined")
+	}
+	expected := GetMember(enumVal, e.Variant)
+
+	variant, isVariant := v.Any().(*EnumVariant)
+	if !isVariant {
+		return !e.HasPayload && MatchPattern(v, expected)
+	}
+
+	if variant.Enum != e.Enum || variant.Variant != e.Variant {
+		return false
+	}
+	if !e.HasPayload {
+		return true
+	}
+
+	if len(e.Payload) != len(variant.Values) {
+		panic(fmt.Sprintf("pattern %s.%s expects %d field(s), got %d",
+			e.Enum, e.Variant, len(variant.Values), len(e.Payload)))
+	}
+	for i, p := range e.Payload {
+		if !p.Match(rt, orNull(variant.Values[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// ArrayPattern - [first, second, ...rest]: массив нужной длины, поэлементно.
+// Без ...rest длина должна совпасть точно; Rest "_" принимает хвост без связывания
+type ArrayPattern struct {
+	Elements []Pattern
+	Rest     string // имя для оставшихся элементов
+	HasRest  bool
+}
+
+func NewArrayPattern(elements []Pattern, rest string, hasRest bool) *ArrayPattern {
+	return &ArrayPattern{Elements: elements, Rest: rest, HasRest: hasRest}
+}
+
+func (a *ArrayPattern) Match(rt *Runtime, v *Value) bool {
+	items, ok := v.Any().([]any)
+	if !ok {
+		return false
+	}
+
+	if len(items) < len(a.Elements) || (!a.HasRest && len(items) != len(a.Elements)) {
+		return false
+	}
+
+	for i, elem := range a.Elements {
+		if !elem.Match(rt, NewValue(items[i])) {
+			return false
+		}
+	}
+
+	if a.HasRest && a.Rest != "_" {
+		rest := make([]any, len(items)-len(a.Elements))
+		copy(rest, items[len(a.Elements):])
+		rt.Scope().Set(a.Rest, NewValue(rest))
+	}
+
+	return true
+}
+
+// FieldPattern - поле объектного паттерна: {name} или {name: pattern}
+type FieldPattern struct {
+	Name    string
+	Pattern Pattern
+}
+
+// ObjectPattern - {name, age: 30} для объектов и User{name, age} для структур.
+// Поля, не упомянутые в паттерне, не проверяются
+type ObjectPattern struct {
+	TypeName string // имя структуры, пустое для любого объекта
+	Fields   []FieldPattern
+}
+
+func NewObjectPattern(typeName string, fields []FieldPattern) *ObjectPattern {
+	return &ObjectPattern{TypeName: typeName, Fields: fields}
+}
+
+func (o *ObjectPattern) Match(rt *Runtime, v *Value) bool {
+	var fields map[string]*Value
+
+	// Ошибка из Err встроенной функции сопоставляется как объект ошибки catch
+	if info, ok := v.Any().(*ErrorInfo); ok {
+		v = info.ToObject()
+	}
+
+	switch obj := v.Any().(type) {
+	case *StructObject:
+		if o.TypeName != "" && obj.TypeInfo.Name != o.TypeName {
+			return false
+		}
+		fields = obj.Fields
+	case *value.Object:
+		if o.TypeName != "" {
+			return false
+		}
+		fields = obj.Fields()
+	default:
+		return false
+	}
+
+	for _, field := range o.Fields {
+		fieldVal, exists := fields[field.Name]
+		if !exists || !field.Pattern.Match(rt, orNull(fieldVal)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// orNull заменяет отсутствующее значение на null
+func orNull(v *Value) *Value {
+	if v == nil {
+		return NewValue(nil)
+	}
+	return v
+}
+
+// missingVariants возвращает варианты enum, которые не покрывают ветки match без guard.
+// ok = false, если match не над одним enum, известным через TypeInfo, или в нем есть ветка "все остальное"
+func (m *MatchExpr) missingVariants(rt *Runtime) (enum string, missing []string, ok bool) {
+	covered := map[string]bool{}
+
+	var collect func(p Pattern) bool
+	collect = func(p Pattern) bool {
+		switch pat := p.(type) {
+		case *EnumPattern:
+			if enum != "" && enum != pat.Enum {
+				return false
+			}
+			enum = pat.Enum
+			covered[pat.Variant] = true
+			return true
+		case *OrPattern:
+			for _, alt := range pat.Alternatives {
+				if !collect(alt) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	}
+
+	for _, arm := range m.Arms {
+		if arm.Guard != nil {
+			if _, isEnum := arm.Pattern.(*EnumPattern); isEnum {
+				continue
+			}
+		}
+		if !collect(arm.Pattern) {
+			return "", nil, false
+		}
+	}
+
+	if enum == "" {
+		return "", nil, false
+	}
+
+	typeVal, found := rt.Scope().Get(enum + "__TypeInfo")
+	if !found {
+		return "", nil, false
+	}
+	info, isType := typeVal.Any().(*TypeInfo)
+	if !isType || info.Kind != "enum" {
+		return "", nil, false
+	}
+
+	for _, variant := range info.Values {
+		if !covered[variant] {
+			missing = append(missing, variant)
+		}
+	}
+	return enum, missing, true
+}
+
+// checkExhaustive предупреждает (один раз для выражения), что match над enum
+// не покрывает все варианты и не имеет ветки _
+func (m *MatchExpr) checkExhaustive(rt *Runtime) {
+	if m.checked {
+		return
+	}
+	m.checked = true
+
+	if enum, missing, ok := m.missingVariants(rt); ok && len(missing) > 0 {
+		rt.Warn(PositionOf(m), "non-exhaustive match on enum %s: missing %s",
+			enum, strings.Join(prefixed(enum, missing), ", "))
+	}
+}
+
+// prefixed добавляет к вариантам имя enum: RED -> Color.RED
+func prefixed(enum string, variants []string) []string {
+	result := make([]string, len(variants))
+	for i, variant := range variants {
+		result[i] = fmt.Sprintf("%s.%s", enum, variant)
+	}
+	return result
+}
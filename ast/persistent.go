@@ -0,0 +1,152 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+)
+
+// NewPersistentList создает List из значений. Список хранит замороженные копии
+// элементов, поэтому его можно безопасно разделять между горутинами, а
+// исходные объекты остаются изменяемыми
+func NewPersistentList(items []*Value) *value.List {
+	list := value.NewList()
+	for _, item := range items {
+		list = list.Push(frozenCopy(item))
+	}
+	return list
+}
+
+// NewPersistentMap создает Map из замороженных копий полей объекта
+func NewPersistentMap(fields map[string]*Value) *value.Map {
+	m := value.NewMap()
+	for key, field := range fields {
+		m = m.Set(key, frozenCopy(field))
+	}
+	return m
+}
+
+// callPersistentMethod вызывает метод List или Map. Изменяющие методы
+// возвращают новую коллекцию, разделяющую неизмененную часть со старой
+func callPersistentMethod(rt *Runtime, obj *Value, methodName string, args []*Value) (*Value, bool) {
+	switch collection := obj.Any().(type) {
+	case *value.List:
+		return callListMethod(rt, obj, collection, methodName, args), true
+	case *value.Map:
+		return callMapMethod(obj, collection, methodName, args), true
+	}
+	return nil, false
+}
+
+// frozenCopy возвращает глубоко замороженную копию значения для хранения в List или Map
+func frozenCopy(v *Value) any {
+	data := cloneData(v.Any())
+	freezeData(data)
+	return data
+}
+
+func expectArgs(receiver, methodName string, args []*Value, count int) {
+	if len(args) != count {
+		panic(fmt.Sprintf("%s.%s() expects %d argument(s), got %d", receiver, methodName, count, len(args)))
+	}
+}
+
+func callListMethod(rt *Runtime, obj *Value, list *value.List, methodName string, args []*Value) *Value {
+	switch methodName {
+	case "length":
+		expectArgs("List", methodName, args, 0)
+		return NewValue(int64(list.Len()))
+	case "get":
+		expectArgs("List", methodName, args, 1)
+		item, _ := list.Get(args[0].Int())
+		return frozenElement(obj, item)
+	case "last":
+		expectArgs("List", methodName, args, 0)
+		item, _ := list.Get(list.Len() - 1)
+		return frozenElement(obj, item)
+	case "set":
+		expectArgs("List", methodName, args, 2)
+		updated, ok := list.Set(args[0].Int(), frozenCopy(args[1]))
+		if !ok {
+			panic("List.set() index out of bounds")
+		}
+		return NewValue(updated)
+	case "push":
+		expectArgs("List", methodName, args, 1)
+		return NewValue(list.Push(frozenCopy(args[0])))
+	case "pop":
+		expectArgs("List", methodName, args, 0)
+		updated, ok := list.Pop()
+		if !ok {
+			panic("List.pop() called on empty list")
+		}
+		return NewValue(updated)
+	case "toArray":
+		expectArgs("List", methodName, args, 0)
+		return NewValue(cloneData(list.Items()))
+	case "map", "filter":
+		expectArgs("List", methodName, args, 1)
+		fn, ok := args[0].Any().(Callable)
+		if !ok {
+			panic(fmt.Sprintf("List.%s() argument must be a function", methodName))
+		}
+		result := value.NewList()
+		for _, item := range list.Items() {
+			elem := frozenElement(obj, item)
+			out := CallIn(rt, fn, []*Value{elem})
+			switch {
+			case methodName == "map":
+				result = result.Push(frozenCopy(orNull(out)))
+			case out.Bool():
+				result = result.Push(item)
+			}
+		}
+		return NewValue(result)
+	}
+	panic(fmt.Sprintf("method '%s' not found for List", methodName))
+}
+
+func callMapMethod(obj *Value, m *value.Map, methodName string, args []*Value) *Value {
+	switch methodName {
+	case "length":
+		expectArgs("Map", methodName, args, 0)
+		return NewValue(int64(m.Len()))
+	case "get":
+		expectArgs("Map", methodName, args, 1)
+		item, _ := m.Get(args[0].String())
+		return frozenElement(obj, item)
+	case "has":
+		expectArgs("Map", methodName, args, 1)
+		_, exists := m.Get(args[0].String())
+		return NewValue(exists)
+	case "set":
+		expectArgs("Map", methodName, args, 2)
+		return NewValue(m.Set(args[0].String(), frozenCopy(args[1])))
+	case "delete":
+		expectArgs("Map", methodName, args, 1)
+		return NewValue(m.Delete(args[0].String()))
+	case "keys":
+		expectArgs("Map", methodName, args, 0)
+		keys := make([]any, 0, m.Len())
+		for _, key := range m.Keys() {
+			keys = append(keys, key)
+		}
+		return NewValue(keys)
+	case "values":
+		expectArgs("Map", methodName, args, 0)
+		values := make([]any, 0, m.Len())
+		for _, key := range m.Keys() {
+			item, _ := m.Get(key)
+			values = append(values, cloneData(item))
+		}
+		return NewValue(values)
+	case "toObject":
+		expectArgs("Map", methodName, args, 0)
+		fields := make(map[string]*Value, m.Len())
+		for _, key := range m.Keys() {
+			item, _ := m.Get(key)
+			fields[key] = NewValue(cloneData(item))
+		}
+		return NewValue(fields)
+	}
+	panic(fmt.Sprintf("method '%s' not found for Map", methodName))
+}
@@ -0,0 +1,59 @@
+package ast
+
+import "fmt"
+
+// Position - позиция в исходном коде (строки и колонки считаются с 1)
+type Position struct {
+	File string
+	Line int
+	Col  int
+}
+
+// IsValid сообщает, что позиция была записана парсером
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String форматирует позицию как file:line:col
+func (p Position) String() string {
+	file := p.File
+	if file == "" {
+		file = "<input>"
+	}
+	return fmt.Sprintf("%s:%d:%d", file, p.Line, p.Col)
+}
+
+// Span - участок исходного кода, из которого разобран узел
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// Node встраивается во все узлы AST и хранит их участок исходного кода
+type Node struct {
+	span Span
+}
+
+// Span возвращает участок исходного кода узла
+func (n *Node) Span() Span {
+	return n.span
+}
+
+// SetSpan устанавливает участок исходного кода узла
+func (n *Node) SetSpan(span Span) {
+	n.span = span
+}
+
+// Positioned реализуют все узлы, встраивающие Node
+type Positioned interface {
+	Span() Span
+	SetSpan(span Span)
+}
+
+// PositionOf возвращает начало узла или пустую позицию, если она неизвестна
+func PositionOf(expr Expr) Position {
+	if node, ok := expr.(Positioned); ok {
+		return node.Span().Start
+	}
+	return Position{}
+}
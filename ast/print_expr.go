@@ -2,10 +2,13 @@ package ast
 
 import (
 	"fmt"
+	"strings"
 )
 
 type PrintExpr struct {
+	Node
 	Expr    Expr
+	Args    []Expr // println() и println(a, b): значения печатаются через пробел
 	isPrint bool
 }
 
@@ -13,14 +16,39 @@ func NewPrintExpr(expr Expr, isPrint bool) *PrintExpr {
 	return &PrintExpr{Expr: expr, isPrint: isPrint}
 }
 
-func (n *PrintExpr) Eval() *Value {
+// NewPrintArgsExpr создает print/println с несколькими аргументами или без них
+func NewPrintArgsExpr(args []Expr, isPrint bool) *PrintExpr {
+	return &PrintExpr{Args: append([]Expr{}, args...), isPrint: isPrint}
+}
+
+// IsPrint возвращает true для print (без перевода строки) и false для println
+func (n *PrintExpr) IsPrint() bool {
+	return n.isPrint
+}
+
+func (n *PrintExpr) Eval(rt *Runtime) *Value {
+	return n.write(rt, evalAll(rt, n.args()))
+}
 
-	if n.Expr == nil {
+// args возвращает выражения печатаемых значений
+func (n *PrintExpr) args() []Expr {
+	if n.Args != nil || n.Expr == nil {
+		return n.Args
+	}
+	return []Expr{n.Expr}
+}
+
+// write печатает уже вычисленные значения через пробел
+func (n *PrintExpr) write(rt *Runtime, values []*Value) *Value {
+	if n.Args == nil && n.Expr == nil {
 		return nil
 	}
 
-	val := n.Expr.Eval()
-	output := FormatValue(val.Any())
+	parts := make([]string, len(values))
+	for i, val := range values {
+		parts[i] = Display(rt, val)
+	}
+	output := strings.Join(parts, " ")
 
 	if !n.isPrint {
 		fmt.Println(output)
@@ -0,0 +1,61 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+)
+
+// PropagateExpr - постфиксный оператор ?: Ok(v)? дает v, а Err(e)? немедленно
+// возвращает Err(e) из объемлющей функции, как return
+type PropagateExpr struct {
+	Node
+	Expr Expr
+}
+
+func NewPropagateExpr(expr Expr) *PropagateExpr {
+	return &PropagateExpr{Expr: expr}
+}
+
+func (p *PropagateExpr) Eval(rt *Runtime) *Value {
+	return Propagate(p.Expr.Eval(rt))
+}
+
+// Propagate применяет оператор ? к значению (используется и VM байткода)
+func Propagate(val *Value) *Value {
+	result, ok := val.Any().(*ResultValue)
+	if !ok {
+		panic(fmt.Sprintf("operator ? expects Result, got %s", value.GetValueTypeName(val)))
+	}
+
+	if result.IsOk() {
+		return result.GetValue()
+	}
+
+	// Err может стоять глубоко в выражении (let x = f()?), поэтому до границы
+	// функции он поднимается паникой и там становится обычным значением return
+	errValue := NewValue(result)
+	errValue.SetReturn(true)
+	panic(&propagation{err: errValue})
+}
+
+// propagation - Err, поднятый оператором ? до границы функции
+type propagation struct {
+	err *Value
+}
+
+// Error описывает ? вне функции: Err некуда вернуть, и он останавливает программу
+func (p *propagation) Error() string {
+	return fmt.Sprintf("%s propagated by ? outside of a function", FormatValue(p.err.Any()))
+}
+
+// ReturnPropagated - defer в вызовах функций foo (и функций байткода): Err,
+// поднятый оператором ?, становится результатом функции. Остальные паники идут дальше
+func ReturnPropagated(result **Value) {
+	if r := recover(); r != nil {
+		if p, ok := r.(*propagation); ok {
+			*result = p.err
+			return
+		}
+		panic(r)
+	}
+}
@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"foo_lang/scope"
 	"foo_lang/value"
 )
 
 // RawStringExpr представляет строку с необработанными интерполяциями ${...}
 // Используется в compile-time контексте, где интерполяции должны обрабатываться позже
 type RawStringExpr struct {
+	Node
 	RawText string // Исходный текст с ${...} интерполяциями
 }
 
@@ -18,14 +18,14 @@ func NewRawStringExpr(rawText string) *RawStringExpr {
 	return &RawStringExpr{RawText: rawText}
 }
 
-func (r *RawStringExpr) Eval() *value.Value {
+func (r *RawStringExpr) Eval(rt *Runtime) *value.Value {
 	// Обрабатываем интерполяции на этапе выполнения
-	result := r.processInterpolations()
+	result := r.processInterpolations(rt)
 	return value.NewValue(result)
 }
 
 // processInterpolations обрабатывает ${...} интерполяции с текущим scope
-func (r *RawStringExpr) processInterpolations() string {
+func (r *RawStringExpr) processInterpolations(rt *Runtime) string {
 	text := r.RawText
 	
 	// Регулярное выражение для поиска ${...}
@@ -37,7 +37,7 @@ func (r *RawStringExpr) processInterpolations() string {
 		varName := strings.TrimSpace(match[2 : len(match)-1])
 		
 		// Пытаемся получить значение из scope
-		if val, found := scope.GlobalScope.Get(varName); found {
+		if val, found := rt.Scope().Get(varName); found {
 			if val != nil {
 				return r.valueToString(val)
 			}
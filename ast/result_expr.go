@@ -43,6 +43,14 @@ func (r *ResultValue) Unwrap() *Value {
 	panic("called unwrap on Err value: " + FormatValue(r.error.Any()))
 }
 
+// UnwrapErr возвращает ошибку или panic, если Result содержит Ok
+func (r *ResultValue) UnwrapErr() *Value {
+	if !r.isOk {
+		return r.error
+	}
+	panic("called unwrapErr on Ok value: " + FormatValue(r.value.Any()))
+}
+
 // UnwrapOr возвращает значение или default
 func (r *ResultValue) UnwrapOr(defaultValue *Value) *Value {
 	if r.isOk {
@@ -61,6 +69,7 @@ func (r *ResultValue) GetValue() *Value {
 
 // OkExpr представляет Ok(value)
 type OkExpr struct {
+	Node
 	Value Expr
 }
 
@@ -68,14 +77,15 @@ func NewOkExpr(value Expr) *OkExpr {
 	return &OkExpr{Value: value}
 }
 
-func (o *OkExpr) Eval() *Value {
-	val := o.Value.Eval()
+func (o *OkExpr) Eval(rt *Runtime) *Value {
+	val := o.Value.Eval(rt)
 	result := NewResultOk(val)
 	return NewValue(result)
 }
 
 // ErrExpr представляет Err(error)
 type ErrExpr struct {
+	Node
 	Error Expr
 }
 
@@ -83,8 +93,8 @@ func NewErrExpr(error Expr) *ErrExpr {
 	return &ErrExpr{Error: error}
 }
 
-func (e *ErrExpr) Eval() *Value {
-	err := e.Error.Eval()
+func (e *ErrExpr) Eval(rt *Runtime) *Value {
+	err := e.Error.Eval(rt)
 	result := NewResultErr(err)
 	return NewValue(result)
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package ast
 
 type ReturnExpr struct {
+	Node
 	Expr Expr
 }
 
@@ -8,13 +9,13 @@ func NewReturnExpr(expr Expr) *ReturnExpr {
 	return &ReturnExpr{Expr: expr}
 }
 
-func (r *ReturnExpr) Eval() *Value {
+func (r *ReturnExpr) Eval(rt *Runtime) *Value {
 	if r.Expr == nil {
 		result := NewValue(nil)
 		result.SetReturn(true)
 		return result
 	}
-	result := r.Expr.Eval()
+	result := r.Expr.Eval(rt)
 	if result == nil {
 		result = NewValue(nil)
 	}
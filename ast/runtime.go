@@ -0,0 +1,263 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/modules"
+	"foo_lang/scope"
+	"foo_lang/value"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// ParseFunc разбирает исходный код в контексте указанного Runtime.
+// Устанавливается извне (пакетом parser), чтобы избежать циклических зависимостей
+type ParseFunc func(rt *Runtime, code string) []Expr
+
+// Runtime - состояние одного экземпляра интерпретатора: стек областей видимости,
+// кэш модулей, реестры методов расширения, перегрузок и интерфейсов.
+// Runtime передается в Eval каждого узла, поэтому несколько интерпретаторов
+// в одном процессе не видят состояние друг друга. Вне Runtime (общими для
+// процесса) остаются только встроенные методы расширения пакета value
+type Runtime struct {
+	scope       *scope.ScopeStack // nil только у GlobalRuntime - тогда используется scope.GlobalScope
+	currentFile string
+	module      bool             // код импортированного модуля (execModule)
+	exports     *modules.Exports // таблица экспорта выполняемого файла (модуля или основного)
+
+	Modules    *modules.Cache
+	Extensions *value.ExtensionRegistry
+
+	registry *registry
+	parse    ParseFunc
+	calls    *callStack // стек вызовов foo; у каждой горутины свой (см. Detached)
+	warnings io.Writer  // nil - предупреждения пишутся в stderr
+}
+
+// registry - реестры, общие для Runtime и всех его производных (WithScope)
+type registry struct {
+	mu              sync.RWMutex
+	overloads       map[string]*OverloadedMethod
+	interfaces      map[string]*InterfaceDefinition
+	implementations map[string]map[string]*ImplBlock // [typeName][interfaceName] = implBlock
+	locals          map[any]any                      // состояние пакетов поверх ast (см. Local)
+	overflowCheck   atomic.Bool                      // --check-overflow (см. SetOverflowCheck)
+}
+
+func newRegistry() *registry {
+	r := &registry{
+		overloads:       make(map[string]*OverloadedMethod),
+		interfaces:      make(map[string]*InterfaceDefinition),
+		implementations: make(map[string]map[string]*ImplBlock),
+		locals:          make(map[any]any),
+	}
+	r.registerOperatorInterfaces()
+	return r
+}
+
+// registerOperatorInterfaces добавляет встроенные интерфейсы операторов (Add, Eq, Display ...)
+func (r *registry) registerOperatorInterfaces() {
+	for _, iface := range operatorInterfaces {
+		r.interfaces[iface.Name] = iface
+	}
+}
+
+// NewRuntime создает изолированный Runtime над переданным стеком областей видимости
+func NewRuntime(scopeStack *scope.ScopeStack, parse ParseFunc) *Runtime {
+	return &Runtime{
+		scope:      scopeStack,
+		Modules:    modules.NewCache(),
+		Extensions: value.NewExtensionRegistry(),
+		registry:   newRegistry(),
+		parse:      parse,
+		calls:      newCallStack("<main>"),
+		exports:    modules.NewExports(),
+	}
+}
+
+// GlobalRuntime - Runtime по умолчанию, работающий поверх scope.GlobalScope.
+// Используется кодом, который создает парсер без явного Runtime (parser.NewParser)
+var GlobalRuntime = &Runtime{
+	Modules:    modules.NewCache(),
+	Extensions: value.NewExtensionRegistry(),
+	registry:   newRegistry(),
+	calls:      newCallStack("<main>"),
+	exports:    modules.NewExports(),
+}
+
+// Local возвращает значение, которое пакет поверх ast (например, builtin) хранит
+// в Runtime под ключом key; при первом обращении оно создается функцией init.
+// Значение общее для Runtime и всех его производных (WithScope, Detached)
+func (rt *Runtime) Local(key any, init func() any) any {
+	rt.registry.mu.Lock()
+	defer rt.registry.mu.Unlock()
+
+	local, ok := rt.registry.locals[key]
+	if !ok {
+		local = init()
+		rt.registry.locals[key] = local
+	}
+	return local
+}
+
+// SetOverflowCheck включает или выключает проверку переполнения int: при
+// включенной проверке +, -, * и унарный минус над int паникуют вместо
+// молчаливого переполнения. Действует только на этот интерпретатор
+func (rt *Runtime) SetOverflowCheck(enabled bool) {
+	rt.registry.overflowCheck.Store(enabled)
+}
+
+// OverflowCheck сообщает, включена ли проверка переполнения int
+func (rt *Runtime) OverflowCheck() bool {
+	return rt.registry.overflowCheck.Load()
+}
+
+// Scope возвращает текущий стек областей видимости
+func (rt *Runtime) Scope() *scope.ScopeStack {
+	if rt.scope == nil {
+		return scope.GlobalScope
+	}
+	return rt.scope
+}
+
+// WithScope возвращает Runtime, который разделяет с rt модули и реестры,
+// но вычисляет выражения в другом стеке областей (модули, горутины async)
+func (rt *Runtime) WithScope(scopeStack *scope.ScopeStack) *Runtime {
+	derived := *rt
+	derived.scope = scopeStack
+	return &derived
+}
+
+// homeOf возвращает Runtime для вызова функции, созданной в Runtime home.
+// Функция модуля выполняется в области своего модуля: видит его переменные
+// верхнего уровня, а не переменные вызывающего кода. Стек вызовов остается общим
+func (rt *Runtime) homeOf(home *Runtime) *Runtime {
+	if !home.module || home.scope == rt.scope {
+		return rt
+	}
+	derived := *rt
+	derived.scope = home.scope
+	derived.currentFile = home.currentFile
+	return &derived
+}
+
+// Detached возвращает Runtime с собственным стеком вызовов - для кода,
+// который выполняется в другой горутине (async, вызовы функций из Go)
+func (rt *Runtime) Detached(root string) *Runtime {
+	derived := *rt
+	derived.calls = newCallStack(root)
+	return &derived
+}
+
+// CurrentFile возвращает путь к файлу, относительно которого разрешаются импорты
+func (rt *Runtime) CurrentFile() string {
+	return rt.currentFile
+}
+
+// SetCurrentFile устанавливает путь к текущему файлу
+func (rt *Runtime) SetCurrentFile(filePath string) {
+	rt.currentFile = filePath
+}
+
+// SetWarningOutput направляет предупреждения (неполный match и т.п.) в w
+func (rt *Runtime) SetWarningOutput(w io.Writer) {
+	rt.warnings = w
+}
+
+// Warn выводит предупреждение с позицией в исходном коде. Выполнение продолжается
+func (rt *Runtime) Warn(pos Position, format string, args ...interface{}) {
+	out := rt.warnings
+	if out == nil {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "warning: %s: %s\n", pos, fmt.Sprintf(format, args...))
+}
+
+// SetParseFunc устанавливает функцию разбора кода для импортов и generate
+func (rt *Runtime) SetParseFunc(parse ParseFunc) {
+	rt.parse = parse
+}
+
+// Parse разбирает код в контексте rt: объявления функций и констант
+// регистрируются в стеке областей rt
+func (rt *Runtime) Parse(code string) []Expr {
+	if rt.parse == nil {
+		panic("parse function not set - cannot parse code at runtime")
+	}
+	return rt.parse(rt, code)
+}
+
+// Run выполняет выражения по порядку и возвращает значение последнего
+func (rt *Runtime) Run(exprs []Expr) *Value {
+	var result *Value
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		rt.calls.at(expr)
+		result = expr.Eval(rt)
+	}
+	return result
+}
+
+// importingFile возвращает файл, относительно которого разрешаются импорты
+func (rt *Runtime) importingFile() string {
+	if rt.currentFile == "" {
+		return "./" // Fallback для обратной совместимости
+	}
+	return rt.currentFile
+}
+
+// ImportModule загружает модуль и импортирует его элементы в текущую область
+// под их собственными именами
+func (rt *Runtime) ImportModule(modulePath string, importedItems []string, alias string) error {
+	items := make([]modules.Binding, len(importedItems))
+	for i, name := range importedItems {
+		items[i] = modules.Binding{Name: name, Local: name}
+	}
+	return rt.ImportBindings(modulePath, items, alias)
+}
+
+// ImportBindings импортирует элементы модуля под локальными именами:
+// import { a as b }, import name from
+func (rt *Runtime) ImportBindings(modulePath string, items []modules.Binding, alias string) error {
+	return rt.Modules.Import(rt.Scope(), modulePath, rt.importingFile(), items, alias, rt.calls.imports, rt.execModule)
+}
+
+// Exports возвращает таблицу экспорта выполняемого файла
+func (rt *Runtime) Exports() *modules.Exports {
+	return rt.exports
+}
+
+// ReExport экспортирует элементы другого модуля от имени текущего:
+// export { a, b as c } from "./x", export * from "./x"
+func (rt *Runtime) ReExport(modulePath string, items []modules.Binding) error {
+	return rt.Modules.ReExport(rt.exports, modulePath, rt.importingFile(), items, rt.calls.imports, rt.execModule)
+}
+
+// RerunModule заново выполняет измененный модуль в новой области, не трогая
+// кэш (горячая перезагрузка, см. modules.Cache.Replace). Ошибка кода модуля
+// возвращается как *ScriptError
+func (rt *Runtime) RerunModule(modulePath string) (module *modules.Module, err error) {
+	reload := rt.Detached("<reload>")
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewScriptError(reload, r)
+		}
+	}()
+	return rt.Modules.Rerun(modulePath, reload.execModule)
+}
+
+// execModule разбирает и выполняет код модуля в собственной области модуля.
+// Импорты внутри модуля разрешаются относительно его файла
+func (rt *Runtime) execModule(module *modules.Module, code string) {
+	moduleRuntime := rt.WithScope(module.Scope)
+	moduleRuntime.SetCurrentFile(module.Path)
+	moduleRuntime.module = true
+	moduleRuntime.exports = module.Exports
+
+	rt.calls.pushModule()
+	defer func() { rt.calls.leave(recover()) }()
+	moduleRuntime.Run(moduleRuntime.Parse(code))
+}
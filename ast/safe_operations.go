@@ -1,25 +1,26 @@
 package ast
 
-import (
-	"fmt"
-	"foo_lang/scope"
-)
+import "fmt"
 
 // SafeVarAccess безопасно получает переменную из scope
-func SafeVarAccess(name string) *Value {
-	if value, exists := scope.GlobalScope.Get(name); exists {
+func SafeVarAccess(rt *Runtime, name string) *Value {
+	if value, exists := rt.Scope().Get(name); exists {
 		return value
 	}
 	
-	return NewReferenceError(name, "variable")
+	result := NewReferenceError(name, "variable")
+	ExtractError(result).WithStackTrace(rt)
+	return result
 }
 
 // SafeFunctionCall безопасно вызывает функцию
-func SafeFunctionCall(funcName string, args []*Value) *Value {
+func SafeFunctionCall(rt *Runtime, funcName string, args []*Value) *Value {
 	// Проверяем существование функции
-	funcValue, exists := scope.GlobalScope.Get(funcName)
+	funcValue, exists := rt.Scope().Get(funcName)
 	if !exists {
-		return NewReferenceError(funcName, "function")
+		result := NewReferenceError(funcName, "function")
+		ExtractError(result).WithStackTrace(rt)
+		return result
 	}
 	
 	// Проверяем что это функция
@@ -37,14 +38,15 @@ func SafeFunctionCall(funcName string, args []*Value) *Value {
 			return NewArgumentError(expected, got, funcName)
 		}
 		
-		return fn.Call(args)
+		return CallIn(rt, fn, args)
 		
 	default:
 		err := NewErrorInfo(TypeError,
 			fmt.Sprintf("'%s' is not a function", funcName),
 			E102_UNDEFINED_FUNCTION).
 			WithContext(funcName).
-			WithSuggestion("Check that the name refers to a function")
+			WithSuggestion("Check that the name refers to a function").
+			WithStackTrace(rt)
 		
 		result := NewResultErr(NewValue(err))
 		return NewValue(result)
@@ -3,6 +3,7 @@ package ast
 import "strings"
 
 type StringFormatExpr struct {
+	Node
 	exprs []Expr
 }
 
@@ -12,7 +13,12 @@ func NewStringFormatExpr(exprs []Expr) *StringFormatExpr {
 	}
 }
 
-func (s *StringFormatExpr) Eval() *Value {
+// Parts возвращает части интерполированной строки
+func (s *StringFormatExpr) Parts() []Expr {
+	return s.exprs
+}
+
+func (s *StringFormatExpr) Eval(rt *Runtime) *Value {
 	var result strings.Builder
 
 	for _, part := range s.exprs {
@@ -21,13 +27,13 @@ func (s *StringFormatExpr) Eval() *Value {
 		}
 		
 		
-		res := part.Eval()
+		res := part.Eval(rt)
 		if res == nil {
 			continue
 		}
 
-		// Используем FormatValue для правильного форматирования массивов и объектов
-		result.WriteString(FormatValue(res.Any()))
+		// Display форматирует массивы, объекты и структуры с impl Display
+		result.WriteString(Display(rt, res))
 	}
 
 	return NewValue(result.String())
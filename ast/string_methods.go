@@ -0,0 +1,184 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringMethods - встроенные методы строк. Индексы и length считаются в байтах,
+// как в substring и charAt; padStart считает длину в символах
+var stringMethods = []*BuiltinMethod{
+	{
+		Name: "length", Returns: "int",
+		Doc: "Длина строки в байтах",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(int64(len(this.(string))))
+		},
+	},
+	{
+		Name: "charAt", Params: []string{"index: int"}, Returns: "string",
+		Doc: "Символ (байт) по индексу",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			str := this.(string)
+			index := intArg("string", "charAt", args[0])
+			if index < 0 || index >= len(str) {
+				panic("string.charAt() index out of bounds")
+			}
+			return NewValue(string(str[index]))
+		},
+	},
+	{
+		Name: "substring", Params: []string{"start: int", "end: int"}, Returns: "string",
+		Doc: "Часть строки с start до end; границы обрезаются по длине строки",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			str := this.(string)
+			start := max(intArg("string", "substring", args[0]), 0)
+			end := min(intArg("string", "substring", args[1]), len(str))
+			start = min(start, end)
+			return NewValue(str[start:end])
+		},
+	},
+	{
+		Name: "toUpper", Returns: "string",
+		Doc: "Строка в верхнем регистре",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(strings.ToUpper(this.(string)))
+		},
+	},
+	{
+		Name: "toLower", Returns: "string",
+		Doc: "Строка в нижнем регистре",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(strings.ToLower(this.(string)))
+		},
+	},
+	{
+		Name: "split", Params: []string{"sep: string"}, Returns: "array",
+		Doc: "Части строки между разделителями; пустой разделитель делит на символы",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			parts := strings.Split(this.(string), stringArg("string", "split", args[0]))
+			return NewValue(stringsToArray(parts))
+		},
+	},
+	{
+		Name: "trim", Returns: "string",
+		Doc: "Строка без пробельных символов в начале и конце",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(strings.TrimSpace(this.(string)))
+		},
+	},
+	{
+		Name: "replace", Params: []string{"old: string", "new: string"}, Returns: "string",
+		Doc: "Строка, в которой все вхождения old заменены на new",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			old := stringArg("string", "replace", args[0])
+			replacement := stringArg("string", "replace", args[1])
+			return NewValue(strings.ReplaceAll(this.(string), old, replacement))
+		},
+	},
+	{
+		Name: "repeat", Params: []string{"count: int"}, Returns: "string",
+		Doc: "Строка, повторенная count раз",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			count := intArg("string", "repeat", args[0])
+			if count < 0 {
+				panic(fmt.Sprintf("string.repeat() count must not be negative, got %d", count))
+			}
+			return NewValue(strings.Repeat(this.(string), count))
+		},
+	},
+	{
+		Name: "padStart", Params: []string{"length: int", "pad?: string"}, Returns: "string",
+		Doc: "Строка, дополненная слева символами pad (по умолчанию пробел) до length символов",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			str := this.(string)
+			length := intArg("string", "padStart", args[0])
+			pad := " "
+			if len(args) == 2 {
+				pad = stringArg("string", "padStart", args[1])
+			}
+			missing := length - utf8.RuneCountInString(str)
+			if missing <= 0 || pad == "" {
+				return NewValue(str)
+			}
+			padding := []rune(strings.Repeat(pad, missing))
+			return NewValue(string(padding[:missing]) + str)
+		},
+	},
+	{
+		Name: "contains", Params: []string{"substr: string"}, Returns: "bool",
+		Doc: "true, если строка содержит substr",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(strings.Contains(this.(string), stringArg("string", "contains", args[0])))
+		},
+	},
+	{
+		Name: "lines", Returns: "array",
+		Doc: "Строки текста без символов перевода строки (\\n и \\r\\n)",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			text := strings.TrimSuffix(this.(string), "\n")
+			if text == "" {
+				return NewValue([]any{})
+			}
+			lines := strings.Split(text, "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimSuffix(line, "\r")
+			}
+			return NewValue(stringsToArray(lines))
+		},
+	},
+	{
+		Name: "format", Params: []string{"args...: any"}, Returns: "string",
+		Doc: "Подставляет аргументы вместо {} по порядку или {0}, {1} по номеру; {{ и }} - фигурные скобки",
+		call: func(rt *Runtime, this any, args []*Value) *Value {
+			return NewValue(formatString(this.(string), args))
+		},
+	},
+}
+
+func stringsToArray(parts []string) []any {
+	result := make([]any, len(parts))
+	for i, part := range parts {
+		result[i] = part
+	}
+	return result
+}
+
+// formatString подставляет аргументы в шаблон string.format()
+func formatString(template string, args []*Value) string {
+	var sb strings.Builder
+	next := 0
+	for i := 0; i < len(template); i++ {
+		ch := template[i]
+		switch {
+		case ch == '{' && strings.HasPrefix(template[i:], "{{"), ch == '}' && strings.HasPrefix(template[i:], "}}"):
+			sb.WriteByte(ch)
+			i++
+		case ch == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				panic("string.format() unclosed '{' in template")
+			}
+			index := next
+			if spec := template[i+1 : i+end]; spec != "" {
+				n, err := strconv.Atoi(spec)
+				if err != nil {
+					panic(fmt.Sprintf("string.format() invalid placeholder {%s}", spec))
+				}
+				index = n
+			} else {
+				next++
+			}
+			if index < 0 || index >= len(args) {
+				panic(fmt.Sprintf("string.format() placeholder %d out of range: got %d argument(s)", index, len(args)))
+			}
+			sb.WriteString(FormatValue(args[index].Any()))
+			i += end
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String()
+}
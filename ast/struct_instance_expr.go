@@ -1,12 +1,16 @@
 package ast
 
 import (
-	"foo_lang/scope"
+	"fmt"
+	"sort"
 )
 
 // StructInstanceExpr представляет создание экземпляра структуры: TypeName{field: value, ...}
+// или Box<int>{value: 1} для generic структуры
 type StructInstanceExpr struct {
+	Node
 	TypeName string
+	TypeArgs []string // явные аргументы типа; без них они выводятся по значениям полей
 	Fields   map[string]Expr
 }
 
@@ -17,9 +21,9 @@ func NewStructInstanceExpr(typeName string, fields map[string]Expr) *StructInsta
 	}
 }
 
-func (s *StructInstanceExpr) Eval() *Value {
+func (s *StructInstanceExpr) Eval(rt *Runtime) *Value {
 	// Получаем информацию о типе структуры из глобального scope
-	typeValue, found := scope.GlobalScope.Get(s.TypeName)
+	typeValue, found := rt.Scope().Get(s.TypeName)
 	if !found {
 		panic("unknown struct type: " + s.TypeName)
 	}
@@ -41,7 +45,7 @@ func (s *StructInstanceExpr) Eval() *Value {
 		}
 		
 		// Вычисляем значение поля
-		fields[fieldName] = fieldExpr.Eval()
+		fields[fieldName] = fieldExpr.Eval(rt)
 	}
 	
 	// Устанавливаем значения по умолчанию для незаданных полей
@@ -54,6 +58,40 @@ func (s *StructInstanceExpr) Eval() *Value {
 	
 	// Создаем объект структуры
 	structObj := NewStructObject(typeInfo, fields)
-	
+
+	if len(typeInfo.TypeParams) > 0 {
+		structObj.TypeArgs = s.typeArgs(rt, typeInfo, fields)
+		checkTypeArgs(rt, FormatGenericType(s.TypeName, TypeParamNames(typeInfo.TypeParams)), typeInfo.TypeParams, structObj.TypeArgs)
+		for fieldName, val := range fields {
+			structObj.checkField(rt, fieldName, val)
+		}
+	} else if len(s.TypeArgs) > 0 {
+		panic(fmt.Sprintf("%s is not a generic struct", s.TypeName))
+	}
+
 	return NewValue(structObj)
+}
+
+// typeArgs возвращает явные аргументы типа или выводит их по полям вида value: T
+func (s *StructInstanceExpr) typeArgs(rt *Runtime, typeInfo *TypeInfo, fields map[string]*Value) []string {
+	if len(s.TypeArgs) > 0 {
+		return s.TypeArgs
+	}
+
+	names := make([]string, 0, len(fields))
+	for fieldName := range fields {
+		names = append(names, fieldName)
+	}
+	sort.Strings(names)
+
+	args := make([]string, len(typeInfo.TypeParams))
+	for i, param := range typeInfo.TypeParams {
+		args[i] = "any"
+		for _, fieldName := range names {
+			if typeInfo.FieldTypes[fieldName] == param.TypeName && fields[fieldName].Any() != nil {
+				args[i] = unifyTypeArg(args[i], bindTypeArg(rt, fields[fieldName]))
+			}
+		}
+	}
+	return args
 }
\ No newline at end of file
@@ -2,7 +2,6 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 	"regexp"
 	"strings"
@@ -10,6 +9,7 @@ import (
 
 // TemplateExpr представляет шаблон с интерполяцией ${expr}
 type TemplateExpr struct {
+	Node
 	Template string // Шаблон с ${} интерполяциями
 }
 
@@ -17,16 +17,16 @@ func NewTemplateExpr(template string) *TemplateExpr {
 	return &TemplateExpr{Template: template}
 }
 
-func (t *TemplateExpr) Eval() *value.Value {
+func (t *TemplateExpr) Eval(rt *Runtime) *value.Value {
 	// Обрабатываем шаблон и заменяем ${...} на вычисленные значения
-	result := t.processTemplate()
+	result := t.processTemplate(rt)
 	
 	// Возвращаем обработанную строку
 	return value.NewValue(result)
 }
 
 // processTemplate обрабатывает шаблон и заменяет ${...} интерполяции
-func (t *TemplateExpr) processTemplate() string {
+func (t *TemplateExpr) processTemplate(rt *Runtime) string {
 	template := t.Template
 	
 	// Регулярное выражение для поиска ${...}
@@ -39,12 +39,12 @@ func (t *TemplateExpr) processTemplate() string {
 		
 		// Обрабатываем специальные конструкции
 		if strings.HasPrefix(expr, "for ") {
-			return t.processForLoop(expr)
+			return t.processForLoop(rt, expr)
 		} else if strings.HasPrefix(expr, "if ") {
-			return t.processIfStatement(expr)
+			return t.processIfStatement(rt, expr)
 		} else {
 			// Обычная интерполяция переменной или выражения
-			return t.evaluateExpression(expr)
+			return t.evaluateExpression(rt, expr)
 		}
 	})
 	
@@ -52,11 +52,11 @@ func (t *TemplateExpr) processTemplate() string {
 }
 
 // evaluateExpression вычисляет выражение и возвращает его строковое представление
-func (t *TemplateExpr) evaluateExpression(expr string) string {
+func (t *TemplateExpr) evaluateExpression(rt *Runtime, expr string) string {
 	expr = strings.TrimSpace(expr)
 	
 	// Пытаемся получить значение из scope
-	if val, found := scope.GlobalScope.Get(expr); found {
+	if val, found := rt.Scope().Get(expr); found {
 		if val != nil {
 			return t.valueToString(val)
 		}
@@ -64,7 +64,7 @@ func (t *TemplateExpr) evaluateExpression(expr string) string {
 	
 	// Обрабатываем доступ к свойствам (например, structParam.Name)
 	if strings.Contains(expr, ".") {
-		return t.evaluatePropertyAccess(expr)
+		return t.evaluatePropertyAccess(rt, expr)
 	}
 	
 	// Обрабатываем вызовы методов (например, structParam.getName())
@@ -77,7 +77,7 @@ func (t *TemplateExpr) evaluateExpression(expr string) string {
 }
 
 // evaluatePropertyAccess обрабатывает доступ к свойствам объектов
-func (t *TemplateExpr) evaluatePropertyAccess(expr string) string {
+func (t *TemplateExpr) evaluatePropertyAccess(rt *Runtime, expr string) string {
 	parts := strings.Split(expr, ".")
 	if len(parts) < 2 {
 		return expr
@@ -87,7 +87,7 @@ func (t *TemplateExpr) evaluatePropertyAccess(expr string) string {
 	objName := parts[0]
 	property := parts[1]
 	
-	if obj, found := scope.GlobalScope.Get(objName); found && obj != nil {
+	if obj, found := rt.Scope().Get(objName); found && obj != nil {
 		// Если это TypeInfo, получаем его свойство
 		if typeInfo, ok := obj.Any().(*TypeInfo); ok {
 			switch property {
@@ -113,8 +113,8 @@ func (t *TemplateExpr) evaluatePropertyAccess(expr string) string {
 		}
 		
 		// Если это обычный объект/map
-		if objValue, ok := obj.Any().(map[string]*value.Value); ok {
-			if propValue, exists := objValue[property]; exists {
+		if objValue, ok := obj.Any().(*value.Object); ok {
+			if propValue, exists := objValue.Get(property); exists {
 				return t.valueToString(propValue)
 			}
 		}
@@ -161,7 +161,7 @@ func (t *TemplateExpr) valueToString(val *value.Value) string {
 
 // processForLoop обрабатывает цикл for внутри шаблона
 // Формат: for field in structParam.Fields { template }
-func (t *TemplateExpr) processForLoop(expr string) string {
+func (t *TemplateExpr) processForLoop(rt *Runtime, expr string) string {
 	// Парсим for цикл
 	// Упрощенная версия: for varName in collection { body }
 	
@@ -185,24 +185,24 @@ func (t *TemplateExpr) processForLoop(expr string) string {
 	body := strings.TrimSpace(expr[bodyStart+1 : bodyEnd])
 	
 	// Получаем коллекцию для итерации
-	collection := t.getCollection(collectionExpr)
+	collection := t.getCollection(rt, collectionExpr)
 	if collection == nil {
 		return fmt.Sprintf("/* collection not found: %s */", collectionExpr)
 	}
 	
 	// Сохраняем текущее состояние scope
-	scope.GlobalScope.Push()
-	defer scope.GlobalScope.Pop()
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
 	
 	var results []string
 	
 	// Итерируемся по коллекции
 	for _, item := range collection {
 		// Устанавливаем переменную цикла
-		scope.GlobalScope.Set(varName, item)
+		rt.Scope().Set(varName, item)
 		
 		// Обрабатываем тело цикла
-		processedBody := t.processTemplateString(body)
+		processedBody := t.processTemplateString(rt, body)
 		results = append(results, processedBody)
 	}
 	
@@ -210,7 +210,7 @@ func (t *TemplateExpr) processForLoop(expr string) string {
 }
 
 // getCollection получает коллекцию для итерации из выражения
-func (t *TemplateExpr) getCollection(expr string) []*value.Value {
+func (t *TemplateExpr) getCollection(rt *Runtime, expr string) []*value.Value {
 	// Обрабатываем доступ к свойствам (например, structParam.Fields)
 	if strings.Contains(expr, ".") {
 		parts := strings.Split(expr, ".")
@@ -218,7 +218,7 @@ func (t *TemplateExpr) getCollection(expr string) []*value.Value {
 			objName := parts[0]
 			property := parts[1]
 			
-			if obj, found := scope.GlobalScope.Get(objName); found && obj != nil {
+			if obj, found := rt.Scope().Get(objName); found && obj != nil {
 				if typeInfo, ok := obj.Any().(*TypeInfo); ok {
 					switch property {
 					case "Fields":
@@ -240,7 +240,7 @@ func (t *TemplateExpr) getCollection(expr string) []*value.Value {
 	}
 	
 	// Пытаемся получить массив из scope
-	if val, found := scope.GlobalScope.Get(expr); found && val != nil {
+	if val, found := rt.Scope().Get(expr); found && val != nil {
 		if arr, ok := val.Any().([]*value.Value); ok {
 			return arr
 		}
@@ -250,10 +250,10 @@ func (t *TemplateExpr) getCollection(expr string) []*value.Value {
 }
 
 // processTemplateString обрабатывает строку шаблона (используется в циклах)
-func (t *TemplateExpr) processTemplateString(template string) string {
+func (t *TemplateExpr) processTemplateString(rt *Runtime, template string) string {
 	// Создаем новый TemplateExpr для обработки вложенного шаблона
 	nestedTemplate := NewTemplateExpr(template)
-	result := nestedTemplate.Eval()
+	result := nestedTemplate.Eval(rt)
 	
 	if result != nil && result.Any() != nil {
 		return fmt.Sprintf("%v", result.Any())
@@ -264,7 +264,7 @@ func (t *TemplateExpr) processTemplateString(template string) string {
 
 // processIfStatement обрабатывает условные операторы в шаблоне
 // Формат: if condition { trueTemplate } else { falseTemplate }
-func (t *TemplateExpr) processIfStatement(expr string) string {
+func (t *TemplateExpr) processIfStatement(rt *Runtime, expr string) string {
 	// Упрощенная обработка if операторов
 	// В будущем можно добавить полный парсер условий
 	
@@ -289,8 +289,8 @@ func (t *TemplateExpr) processIfStatement(expr string) string {
 	body := strings.TrimSpace(expr[bodyStart+1 : bodyEnd])
 	
 	// Вычисляем условие
-	if t.evaluateCondition(condition) {
-		return t.processTemplateString(body)
+	if t.evaluateCondition(rt, condition) {
+		return t.processTemplateString(rt, body)
 	}
 	
 	// Ищем else часть
@@ -301,7 +301,7 @@ func (t *TemplateExpr) processIfStatement(expr string) string {
 		
 		if elseBodyStart != -1 && elseBodyEnd != -1 {
 			elseBody := strings.TrimSpace(expr[bodyEnd+elseStart+elseBodyStart+1 : elseBodyEnd])
-			return t.processTemplateString(elseBody)
+			return t.processTemplateString(rt, elseBody)
 		}
 	}
 	
@@ -309,7 +309,7 @@ func (t *TemplateExpr) processIfStatement(expr string) string {
 }
 
 // evaluateCondition вычисляет логическое условие
-func (t *TemplateExpr) evaluateCondition(condition string) bool {
+func (t *TemplateExpr) evaluateCondition(rt *Runtime, condition string) bool {
 	condition = strings.TrimSpace(condition)
 	
 	// Простые логические значения
@@ -321,7 +321,7 @@ func (t *TemplateExpr) evaluateCondition(condition string) bool {
 	}
 	
 	// Проверка переменных из scope
-	if val, found := scope.GlobalScope.Get(condition); found && val != nil {
+	if val, found := rt.Scope().Get(condition); found && val != nil {
 		if b, ok := val.Any().(bool); ok {
 			return b
 		}
@@ -335,7 +335,7 @@ func (t *TemplateExpr) evaluateCondition(condition string) bool {
 		if len(parts) == 2 {
 			left := strings.TrimSpace(parts[0])
 			right := strings.TrimSpace(parts[1])
-			return t.evaluateExpression(left) == t.evaluateExpression(right)
+			return t.evaluateExpression(rt, left) == t.evaluateExpression(rt, right)
 		}
 	}
 	
@@ -344,9 +344,9 @@ func (t *TemplateExpr) evaluateCondition(condition string) bool {
 }
 
 // processCodeBlock обрабатывает блок кода - генерирует код, не выполняет его
-func (t *TemplateExpr) processCodeBlock(codeBlock string) string {
+func (t *TemplateExpr) processCodeBlock(rt *Runtime, codeBlock string) string {
 	// Обрабатываем интерполяции внутри блока кода
-	processedCode := t.processTemplateString(codeBlock)
+	processedCode := t.processTemplateString(rt, codeBlock)
 	
 	// Возвращаем обработанный код как часть сгенерированного кода
 	return processedCode
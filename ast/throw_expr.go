@@ -0,0 +1,112 @@
+package ast
+
+import (
+	"fmt"
+	"foo_lang/value"
+	"strings"
+)
+
+// ThrownError - ошибка, летящая через panic до ближайшего try/catch.
+// Ее создает оператор throw; паники интерпретатора превращаются в нее при перехвате
+type ThrownError struct {
+	Info  *ErrorInfo
+	Value *Value // значение, переданное в throw (nil для ошибок выполнения)
+}
+
+// Error форматирует ошибку как "Type: message" для неперехваченного throw
+func (t *ThrownError) Error() string {
+	return t.Info.String()
+}
+
+type ThrowExpr struct {
+	Node
+	Expr Expr
+}
+
+func NewThrowExpr(expr Expr) *ThrowExpr {
+	return &ThrowExpr{Expr: expr}
+}
+
+func (t *ThrowExpr) Eval(rt *Runtime) *Value {
+	thrown := t.Expr.Eval(rt)
+	if thrown == nil {
+		thrown = NewValue(nil)
+	}
+
+	info := errorInfoFromValue(thrown)
+	if len(info.StackTrace) == 0 {
+		info.WithStackTrace(rt)
+	}
+
+	panic(&ThrownError{Info: info, Value: thrown})
+}
+
+// errorInfoFromValue строит ErrorInfo из значения throw: Err(...), объекта ошибки
+// из catch (повторный throw e) или любого другого значения (становится сообщением)
+func errorInfoFromValue(v *Value) *ErrorInfo {
+	switch val := v.Any().(type) {
+	case *ErrorInfo:
+		return val
+	case *ResultValue:
+		if val.IsErr() && val.error != nil {
+			return errorInfoFromValue(val.error)
+		}
+	case *value.Object:
+		fields := val.Fields()
+		if message, ok := fields["message"]; ok {
+			info := NewErrorInfo(UserError, message.String(), E901_THROWN)
+			if errType, ok := fields["type"]; ok {
+				info.Type = errType.String()
+			}
+			if code, ok := fields["code"]; ok {
+				info.Code = code.String()
+			}
+			if context, ok := fields["context"]; ok {
+				info.Context = context.String()
+			}
+			if suggestions, ok := fields["suggestions"]; ok {
+				info.Suggestions = stringsOf(suggestions)
+			}
+			if stack, ok := fields["stack"]; ok {
+				info.StackTrace = stringsOf(stack)
+			}
+			return info
+		}
+	}
+
+	return NewErrorInfo(UserError, v.String(), E901_THROWN)
+}
+
+// errorInfoFromPanic строит ErrorInfo из значения паники. Стек берется из rt:
+// в момент перехвата он еще указывает на место ошибки
+func errorInfoFromPanic(rt *Runtime, recovered interface{}) *ErrorInfo {
+	if thrown, ok := recovered.(*ThrownError); ok {
+		return thrown.Info
+	}
+
+	message := fmt.Sprintf("%v", recovered)
+
+	errType, code := RuntimeError, E999_RUNTIME_PANIC
+	switch {
+	case strings.Contains(message, "is not defined"):
+		errType, code = ReferenceError, E101_UNDEFINED_VARIABLE
+	case strings.Contains(message, "index out of"):
+		errType, code = IndexError, E201_INDEX_OUT_OF_BOUNDS
+	}
+
+	return NewErrorInfo(errType, message, code).WithStackTrace(rt)
+}
+
+// stringsOf превращает массив foo в []string
+func stringsOf(v *Value) []string {
+	items, ok := v.Any().([]any)
+	if !ok {
+		return []string{}
+	}
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = fmt.Sprintf("%v", item)
+	}
+	return result
+}
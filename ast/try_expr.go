@@ -0,0 +1,98 @@
+package ast
+
+import "foo_lang/value"
+
+// TryExpr - try { } catch (e) { } finally { }.
+// Catch перехватывает throw и любые паники выполнения (unwrap на Err, неизвестный тип
+// структуры ...); ошибка доступна в catch как объект ErrorInfo (см. ErrorInfo.ToObject)
+type TryExpr struct {
+	Node
+	Body      Expr
+	CatchName string // имя переменной ошибки, пустое для catch { }
+	Catch     Expr   // nil, если блока catch нет
+	Finally   Expr   // nil, если блока finally нет
+}
+
+func NewTryExpr(body Expr, catchName string, catch Expr, finally Expr) *TryExpr {
+	return &TryExpr{Body: body, CatchName: catchName, Catch: catch, Finally: finally}
+}
+
+func (t *TryExpr) Eval(rt *Runtime) *Value {
+	result, thrown := protect(rt, func() *Value {
+		return evalBlock(rt, t.Body, nil)
+	})
+
+	// Err, поднятый оператором ?, - это return, а не ошибка: catch его не видит
+	if _, returning := thrown.(*propagation); thrown != nil && !returning && t.Catch != nil {
+		caught := thrown
+		info := errorInfoFromPanic(rt, caught)
+
+		result, thrown = protect(rt, func() *Value {
+			return evalBlock(rt, t.Catch, func() {
+				if t.CatchName != "" {
+					rt.Scope().Set(t.CatchName, t.errorObject(info, caught))
+				}
+			})
+		})
+	}
+
+	if t.Finally != nil {
+		// return/break/continue из finally отменяют и результат, и необработанную ошибку
+		if final := evalBlock(rt, t.Finally, nil); final != nil && (final.IsReturn() || final.IsBreak() || final.IsContinue()) {
+			return final
+		}
+	}
+
+	if thrown != nil {
+		panic(thrown)
+	}
+
+	return result
+}
+
+// errorObject создает объект ошибки для catch. Для throw значение доступно в поле value
+func (t *TryExpr) errorObject(info *ErrorInfo, recovered interface{}) *Value {
+	obj := info.ToObject()
+	if thrown, ok := recovered.(*ThrownError); ok && thrown.Value != nil {
+		obj.Any().(*value.Object).Set("value", thrown.Value)
+	}
+	return obj
+}
+
+// protect выполняет run и перехватывает панику. Области видимости и глубина рекурсии,
+// оставленные прерванным кодом (циклы снимают свою область без defer), откатываются
+func protect(rt *Runtime, run func() *Value) (result *Value, thrown interface{}) {
+	scopes := rt.Scope()
+	current, depth := scopes.CurrentScope(), scopes.GetRecursionDepth()
+
+	defer func() {
+		if r := recover(); r != nil {
+			scopes.Unwind(current, depth)
+			thrown = r
+		}
+	}()
+
+	return run(), nil
+}
+
+// evalBlock выполняет блок в собственной области видимости, как тело if:
+// return, break, continue и yield прерывают блок и передаются выше. init заполняет область перед блоком
+func evalBlock(rt *Runtime, block Expr, init func()) *Value {
+	rt.Scope().Push()
+	defer rt.Scope().Pop()
+
+	if init != nil {
+		init()
+	}
+
+	var result *Value
+	for _, stm := range block.(*BodyExpr).Statments {
+		rt.calls.at(stm)
+		result = stm.Eval(rt)
+		if result != nil && (result.IsReturn() || result.IsYield() || result.IsBreak() || result.IsContinue()) {
+			return result
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,41 @@
+package ast
+
+import "foo_lang/value"
+
+// TupleExpr - литерал кортежа: (1, "a"), (x,) для одного элемента, () для пустого
+type TupleExpr struct {
+	Node
+	Elements []Expr
+}
+
+func NewTupleExpr(elements []Expr) *TupleExpr {
+	return &TupleExpr{Elements: elements}
+}
+
+func (t *TupleExpr) Eval(rt *Runtime) *Value {
+	items := make([]any, len(t.Elements))
+	for i, element := range t.Elements {
+		items[i] = element.Eval(rt).Any()
+	}
+	return NewValue(value.NewTuple(items...))
+}
+
+// SetExpr - литерал множества: #{1, 2, 3}, #{} для пустого
+type SetExpr struct {
+	Node
+	Elements []Expr
+}
+
+func NewSetExpr(elements []Expr) *SetExpr {
+	return &SetExpr{Elements: elements}
+}
+
+func (s *SetExpr) Eval(rt *Runtime) *Value {
+	set := value.NewSet()
+	for _, element := range s.Elements {
+		if err := set.Add(element.Eval(rt).Any()); err != nil {
+			panic(err.Error())
+		}
+	}
+	return NewValue(set)
+}
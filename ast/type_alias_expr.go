@@ -1,12 +1,10 @@
 package ast
 
-import (
-	"foo_lang/scope"
-	"foo_lang/value"
-)
+import "foo_lang/value"
 
 // TypeAliasExpr представляет определение псевдонима типа: type UserId = int
 type TypeAliasExpr struct {
+	Node
 	AliasName string // Имя нового типа (например, "UserId")
 	BaseType  string // Базовый тип (например, "int")
 }
@@ -18,7 +16,7 @@ func NewTypeAliasExpr(aliasName, baseType string) *TypeAliasExpr {
 	}
 }
 
-func (t *TypeAliasExpr) Eval() *value.Value {
+func (t *TypeAliasExpr) Eval(rt *Runtime) *value.Value {
 	// Создаем псевдоним типа в глобальной области видимости
 	
 	// Получаем информацию о базовом типе
@@ -40,7 +38,7 @@ func (t *TypeAliasExpr) Eval() *value.Value {
 		baseTypeInfo = NewPrimitiveTypeInfo("object")
 	default:
 		// Проверяем другие псевдонимы (цепочка псевдонимов)
-		if aliasValue, exists := scope.GlobalScope.Get(t.BaseType); exists {
+		if aliasValue, exists := rt.Scope().Get(t.BaseType); exists {
 			if existingAlias, ok := aliasValue.Any().(*TypeAliasInfo); ok {
 				// Ссылка на псевдоним - используем его базовый тип
 				baseTypeInfo = existingAlias.BaseTypeInfo
@@ -49,7 +47,7 @@ func (t *TypeAliasExpr) Eval() *value.Value {
 			} else {
 				panic("Invalid alias value for type: " + t.BaseType)
 			}
-		} else if typeInfoValue, exists := scope.GlobalScope.Get(t.BaseType + "__TypeInfo"); exists {
+		} else if typeInfoValue, exists := rt.Scope().Get(t.BaseType + "__TypeInfo"); exists {
 			if baseInfo, ok := typeInfoValue.Any().(*TypeInfo); ok {
 				baseTypeInfo = baseInfo
 			} else {
@@ -64,8 +62,8 @@ func (t *TypeAliasExpr) Eval() *value.Value {
 	aliasInfo := NewTypeAliasInfo(t.AliasName, baseTypeInfo)
 	
 	// Сохраняем псевдоним в scope
-	scope.GlobalScope.Set(t.AliasName, value.NewValue(aliasInfo))
-	scope.GlobalScope.Set(t.AliasName+"__TypeInfo", value.NewValue(aliasInfo))
+	rt.Scope().Set(t.AliasName, value.NewValue(aliasInfo))
+	rt.Scope().Set(t.AliasName+"__TypeInfo", value.NewValue(aliasInfo))
 	
 	return value.NewString("type alias '" + t.AliasName + "' = '" + t.BaseType + "' defined")
 }
@@ -1,9 +1,9 @@
 package ast
 
 import (
-	"foo_lang/scope"
 	"foo_lang/value"
 	"fmt"
+	"math/big"
 	"strings"
 )
 
@@ -21,6 +21,9 @@ type TypeInfo struct {
 	Params []*TypeInfo            // параметры для fn
 	Return *TypeInfo              // возвращаемый тип для fn
 	Values []string               // значения для enum
+	Variants map[string][]*FieldInfo // данные вариантов enum (nil для enum без данных)
+	TypeParams []TypeConstraint   // параметры типа generic структуры: struct Box<T>
+	FieldTypes map[string]string  // объявленные типы полей struct, как в аннотациях
 	Data   interface{}            // дополнительная информация
 }
 
@@ -39,6 +42,10 @@ func (ti *TypeInfo) GetProperty(name string) *value.Value {
 		return value.NewValue(&GetFieldTypeMethod{typeInfo: ti})
 	case "HasField":
 		return value.NewValue(&HasFieldMethod{typeInfo: ti})
+	case "GetVariantNames":
+		return value.NewValue(&GetVariantNamesMethod{typeInfo: ti})
+	case "GetVariantFields":
+		return value.NewValue(&GetVariantFieldsMethod{typeInfo: ti})
 	// Полиморфные методы проверки типа
 	case "isStruct":
 		return value.NewValue(&IsStructMethod{typeInfo: ti})
@@ -113,6 +120,30 @@ func (m *HasFieldMethod) Call(args []*value.Value) *value.Value {
 	return value.NewValue(m.typeInfo.HasField(fieldName))
 }
 
+// GetVariantNamesMethod представляет метод GetVariantNames для TypeInfo
+type GetVariantNamesMethod struct {
+	typeInfo *TypeInfo
+}
+
+func (m *GetVariantNamesMethod) Call(args []*value.Value) *value.Value {
+	if len(args) != 0 {
+		panic("GetVariantNames() expects no arguments")
+	}
+	return value.NewValue(m.typeInfo.GetVariantNames())
+}
+
+// GetVariantFieldsMethod представляет метод GetVariantFields для TypeInfo
+type GetVariantFieldsMethod struct {
+	typeInfo *TypeInfo
+}
+
+func (m *GetVariantFieldsMethod) Call(args []*value.Value) *value.Value {
+	if len(args) != 1 {
+		panic("GetVariantFields() expects exactly 1 argument")
+	}
+	return value.NewValue(m.typeInfo.GetVariantFields(args[0].String()))
+}
+
 // Полиморфные методы проверки типов
 type IsStructMethod struct {
 	typeInfo *TypeInfo
@@ -210,6 +241,14 @@ func NewStructTypeInfo(name string, fields map[string]*TypeInfo) *TypeInfo {
 	}
 }
 
+// NewTypeParamInfo создает информацию о поле, тип которого задан параметром типа
+func NewTypeParamInfo(name string) *TypeInfo {
+	return &TypeInfo{
+		Kind: "typeparam",
+		Name: name,
+	}
+}
+
 // NewFunctionTypeInfo создает информацию о функции
 func NewFunctionTypeInfo(name string, params []*TypeInfo, returnType *TypeInfo) *TypeInfo {
 	return &TypeInfo{
@@ -318,6 +357,59 @@ func (ti *TypeInfo) HasField(fieldName string) bool {
 	return exists
 }
 
+// GetVariantNames возвращает имена вариантов enum в порядке объявления
+func (ti *TypeInfo) GetVariantNames() []any {
+	if ti.Kind != "enum" {
+		return []any{}
+	}
+
+	names := make([]any, len(ti.Values))
+	for i, name := range ti.Values {
+		names[i] = name
+	}
+	return names
+}
+
+// GetVariantFields возвращает данные варианта enum как массив {name, type}.
+// Для варианта без данных массив пустой
+func (ti *TypeInfo) GetVariantFields(variant string) []any {
+	if ti.Kind != "enum" {
+		return []any{}
+	}
+
+	found := false
+	for _, name := range ti.Values {
+		found = found || name == variant
+	}
+	if !found {
+		panic("variant '" + variant + "' does not exist in enum " + ti.Name)
+	}
+
+	fields := ti.Variants[variant]
+	result := make([]any, len(fields))
+	for i, field := range fields {
+		result[i] = value.NewObjectFromMap(map[string]*value.Value{
+			"name": value.NewValue(field.Name),
+			"type": value.NewValue(field.Type.String()),
+		})
+	}
+	return result
+}
+
+// variantString форматирует вариант enum вместе с данными: Circle(r: float)
+func (ti *TypeInfo) variantString(variant string) string {
+	fields, ok := ti.Variants[variant]
+	if !ok || fields == nil {
+		return variant
+	}
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s: %s", field.Name, field.Type.String())
+	}
+	return fmt.Sprintf("%s(%s)", variant, strings.Join(parts, ", "))
+}
+
 // String возвращает строковое представление типа
 func (ti *TypeInfo) String() string {
 	switch ti.Kind {
@@ -326,7 +418,7 @@ func (ti *TypeInfo) String() string {
 		for name, fieldType := range ti.Fields {
 			fields = append(fields, fmt.Sprintf("%s: %s", name, fieldType.String()))
 		}
-		return fmt.Sprintf("struct %s { %s }", ti.Name, strings.Join(fields, ", "))
+		return fmt.Sprintf("struct %s { %s }", FormatGenericType(ti.Name, TypeParamNames(ti.TypeParams)), strings.Join(fields, ", "))
 	case "function":
 		var params []string
 		for _, param := range ti.Params {
@@ -338,8 +430,12 @@ func (ti *TypeInfo) String() string {
 		}
 		return fmt.Sprintf("fn %s(%s) -> %s", ti.Name, strings.Join(params, ", "), returnStr)
 	case "enum":
-		return fmt.Sprintf("enum %s { %s }", ti.Name, strings.Join(ti.Values, ", "))
-	case "primitive":
+		variants := make([]string, len(ti.Values))
+		for i, variant := range ti.Values {
+			variants[i] = ti.variantString(variant)
+		}
+		return fmt.Sprintf("enum %s { %s }", ti.Name, strings.Join(variants, ", "))
+	case "primitive", "typeparam":
 		return ti.Name
 	default:
 		return "unknown"
@@ -348,6 +444,7 @@ func (ti *TypeInfo) String() string {
 
 // TypeofExpr представляет выражение typeof для получения типа
 type TypeofExpr struct {
+	Node
 	Expr Expr
 }
 
@@ -355,9 +452,9 @@ func NewTypeofExpr(expr Expr) *TypeofExpr {
 	return &TypeofExpr{Expr: expr}
 }
 
-func (t *TypeofExpr) Eval() *value.Value {
+func (t *TypeofExpr) Eval(rt *Runtime) *value.Value {
 	// Анализируем тип выражения
-	val := t.Expr.Eval()
+	val := t.Expr.Eval(rt)
 	
 	var typeInfo *TypeInfo
 	switch val.Any().(type) {
@@ -369,11 +466,11 @@ func (t *TypeofExpr) Eval() *value.Value {
 		typeInfo = NewPrimitiveTypeInfo("string")
 	case bool:
 		typeInfo = NewPrimitiveTypeInfo("bool")
-	case map[string]*value.Value:
+	case *value.Object:
 		// Объект - анализируем его структуру
-		obj := val.Any().(map[string]*value.Value)
+		obj := val.Any().(*value.Object)
 		fields := make(map[string]*TypeInfo)
-		for name, fieldVal := range obj {
+		for name, fieldVal := range obj.Fields() {
 			switch fieldVal.Any().(type) {
 			case int64:
 				fields[name] = NewPrimitiveTypeInfo("int")
@@ -390,6 +487,14 @@ func (t *TypeofExpr) Eval() *value.Value {
 		typeInfo = NewStructTypeInfo("object", fields)
 	case []*value.Value:
 		typeInfo = NewPrimitiveTypeInfo("array")
+	case *value.Tuple:
+		typeInfo = NewPrimitiveTypeInfo("tuple")
+	case *value.Set:
+		typeInfo = NewPrimitiveTypeInfo("set")
+	case *big.Int:
+		typeInfo = NewPrimitiveTypeInfo("bigint")
+	case *value.Decimal:
+		typeInfo = NewPrimitiveTypeInfo("decimal")
 	default:
 		typeInfo = NewPrimitiveTypeInfo("unknown")
 	}
@@ -399,8 +504,10 @@ func (t *TypeofExpr) Eval() *value.Value {
 
 // StructDefExpr представляет определение структуры
 type StructDefExpr struct {
-	Name   string
-	Fields map[string]Expr // имя поля -> тип или значение по умолчанию
+	Node
+	Name       string
+	TypeParams []TypeConstraint // параметры типа: struct Box<T> { value: T }
+	Fields     map[string]Expr  // имя поля -> тип или значение по умолчанию
 }
 
 func NewStructDefExpr(name string, fields map[string]Expr) *StructDefExpr {
@@ -410,12 +517,33 @@ func NewStructDefExpr(name string, fields map[string]Expr) *StructDefExpr {
 	}
 }
 
-func (s *StructDefExpr) Eval() *value.Value {
+// NewGenericStructDefExpr создает определение generic структуры
+func NewGenericStructDefExpr(name string, typeParams []TypeConstraint, fields map[string]Expr) *StructDefExpr {
+	return &StructDefExpr{
+		Name:       name,
+		TypeParams: typeParams,
+		Fields:     fields,
+	}
+}
+
+func (s *StructDefExpr) Eval(rt *Runtime) *value.Value {
 	// Создаем информацию о типе структуры
 	fieldTypes := make(map[string]*TypeInfo)
+	declared := make(map[string]string)
+	names := TypeParamNames(s.TypeParams)
 	for name, fieldExpr := range s.Fields {
+		if typeExpr, ok := fieldExpr.(*TypeExpr); ok {
+			declared[name] = typeExpr.TypeName
+
+			// Поле типа T ссылается на параметр типа, а не на тип из scope
+			if MentionsTypeParam(typeExpr.TypeName, names) {
+				fieldTypes[name] = NewTypeParamInfo(typeExpr.TypeName)
+				continue
+			}
+		}
+
 		// Вычисляем тип поля
-		fieldTypeValue := fieldExpr.Eval()
+		fieldTypeValue := fieldExpr.Eval(rt)
 		if typeInfo, ok := fieldTypeValue.Any().(*TypeInfo); ok {
 			fieldTypes[name] = typeInfo
 		} else {
@@ -425,15 +553,18 @@ func (s *StructDefExpr) Eval() *value.Value {
 	}
 	
 	typeInfo := NewStructTypeInfo(s.Name, fieldTypes)
+	typeInfo.TypeParams = s.TypeParams
+	typeInfo.FieldTypes = declared
 	
 	// Сохраняем структуру в scope для использования в макросах
-	scope.GlobalScope.Set(s.Name, value.NewValue(typeInfo))
+	rt.Scope().Set(s.Name, value.NewValue(typeInfo))
 	
 	return value.NewValue(typeInfo)
 }
 
 // TypeExpr представляет выражение для передачи типа в макрос
 type TypeExpr struct {
+	Node
 	TypeName string
 }
 
@@ -441,14 +572,14 @@ func NewTypeExpr(typeName string) *TypeExpr {
 	return &TypeExpr{TypeName: typeName}
 }
 
-func (t *TypeExpr) Eval() *value.Value {
+func (t *TypeExpr) Eval(rt *Runtime) *value.Value {
 	// Сначала проверяем специальный ключ для TypeInfo у enum
-	if typeInfoValue, found := scope.GlobalScope.Get(t.TypeName + "__TypeInfo"); found {
+	if typeInfoValue, found := rt.Scope().Get(t.TypeName + "__TypeInfo"); found {
 		return typeInfoValue
 	}
 	
 	// Получаем информацию о типе из scope
-	typeValue, found := scope.GlobalScope.Get(t.TypeName)
+	typeValue, found := rt.Scope().Get(t.TypeName)
 	if !found {
 		// Если не найден пользовательский тип, проверяем примитивные типы
 		switch t.TypeName {
@@ -1,13 +1,11 @@
 package ast
 
-import (
-	"foo_lang/scope"
-	"foo_lang/value"
-)
+import "foo_lang/value"
 
 // TypeNameExpr представляет прямое обращение к типу по имени (User, int, string, etc)
 // Используется в вызовах макросов: @macro(User) вместо @macro(type(User))
 type TypeNameExpr struct {
+	Node
 	TypeName string
 }
 
@@ -15,16 +13,16 @@ func NewTypeNameExpr(typeName string) *TypeNameExpr {
 	return &TypeNameExpr{TypeName: typeName}
 }
 
-func (t *TypeNameExpr) Eval() *value.Value {
-	// ТОЧНО КОПИРУЕМ логику из TypeExpr.Eval() для совместимости
+func (t *TypeNameExpr) Eval(rt *Runtime) *value.Value {
+	// ТОЧНО КОПИРУЕМ логику из TypeExpr.Eval(rt) для совместимости
 	
 	// Сначала проверяем специальный ключ для TypeInfo у enum
-	if typeInfoValue, found := scope.GlobalScope.Get(t.TypeName + "__TypeInfo"); found {
+	if typeInfoValue, found := rt.Scope().Get(t.TypeName + "__TypeInfo"); found {
 		return typeInfoValue
 	}
 	
 	// Получаем информацию о типе из scope
-	typeValue, found := scope.GlobalScope.Get(t.TypeName)
+	typeValue, found := rt.Scope().Get(t.TypeName)
 	if !found {
 		// Если не найден пользовательский тип, проверяем примитивные типы
 		switch t.TypeName {
@@ -2,13 +2,13 @@ package ast
 
 import (
 	"fmt"
-	"foo_lang/scope"
 	"foo_lang/value"
 	"strings"
 )
 
 // TypedLetExpr представляет типизированную переменную
 type TypedLetExpr struct {
+	Node
 	name     string
 	varType  string // Ожидаемый тип переменной
 	expr     Expr
@@ -22,27 +22,27 @@ func NewTypedLetExpr(name string, varType string, expr Expr) *TypedLetExpr {
 	}
 }
 
-func (n *TypedLetExpr) Eval() *Value {
-	if scope.GlobalScope.Has(n.name) {
+func (n *TypedLetExpr) Eval(rt *Runtime) *Value {
+	if rt.Scope().Has(n.name) {
 		panic("variable " + n.name + " is already defined")
 	}
 
-	val := n.expr.Eval()
+	val := n.expr.Eval(rt)
 	
 	// Проверяем тип, если он указан
 	if n.varType != "" {
-		if err := validateVariableType(val, n.varType); err != nil {
+		if err := validateVariableType(rt, val, n.varType); err != nil {
 			panic(fmt.Sprintf("variable '%s' type error: %s", n.name, err.Error()))
 		}
 	}
 
-	scope.GlobalScope.Set(n.name, val)
+	rt.Scope().Set(n.name, val)
 
 	return nil
 }
 
 // validateVariableType проверяет соответствие значения ожидаемому типу переменной
-func validateVariableType(val *Value, expectedType string) error {
+func validateVariableType(rt *Runtime, val *Value, expectedType string) error {
 	switch expectedType {
 	case "int":
 		if !val.IsInt64() {
@@ -70,14 +70,21 @@ func validateVariableType(val *Value, expectedType string) error {
 		}
 		return nil
 	case "object":
-		if _, ok := val.Any().(map[string]*value.Value); !ok {
+		if _, ok := val.Any().(*value.Object); !ok {
 			return fmt.Errorf("expected object, got %T", val.Any())
 		}
 		return nil
+	case "set", "tuple", "bigint", "decimal":
+		if got := value.GetValueTypeName(val); got != expectedType {
+			return fmt.Errorf("expected %s, got %s", expectedType, got)
+		}
+		return nil
+	case "any":
+		return nil
 	default:
 		// Проверяем Tuple типы (начинаются с '(' и заканчиваются ')')
 		if strings.HasPrefix(expectedType, "(") && strings.HasSuffix(expectedType, ")") {
-			return validateTupleType(val, expectedType)
+			return validateTupleType(rt, val, expectedType)
 		}
 		
 		// Проверяем Union типы (содержат символ |)
@@ -92,7 +99,7 @@ func validateVariableType(val *Value, expectedType string) error {
 				if unionType == "null" && val.Any() == nil {
 					return nil
 				}
-				if err := validateVariableType(val, unionType); err == nil {
+				if err := validateVariableType(rt, val, unionType); err == nil {
 					return nil // Найден подходящий тип
 				}
 			}
@@ -101,16 +108,34 @@ func validateVariableType(val *Value, expectedType string) error {
 				strings.Join(unionTypes, " | "), value.GetValueTypeName(val))
 		}
 		
+		// Параметр типа внутри generic кода проверяется по связанному аргументу
+		if bound, exists := rt.Scope().Get(expectedType + "__TypeArg"); exists {
+			return validateVariableType(rt, val, bound.String())
+		}
+
+		// Массив с типом элементов: Array<int>
+		name, args := SplitGenericType(expectedType)
+		if name == "Array" && len(args) == 1 {
+			return validateArrayType(rt, val, args[0])
+		}
+
+		// Структуры, в том числе generic: Box<int>
+		if typeValue, exists := rt.Scope().Get(name); exists {
+			if typeInfo, ok := typeValue.Any().(*TypeInfo); ok && typeInfo.Kind == "struct" {
+				return validateStructType(rt, val, typeInfo, args)
+			}
+		}
+
 		// Проверяем псевдонимы типов
-		if aliasValue, exists := scope.GlobalScope.Get(expectedType); exists {
+		if aliasValue, exists := rt.Scope().Get(expectedType); exists {
 			if aliasInfo, ok := aliasValue.Any().(*TypeAliasInfo); ok {
 				// Для псевдонимов проверяем соответствие базовому типу
-				return validateVariableType(val, aliasInfo.BaseTypeInfo.Name)
+				return validateVariableType(rt, val, aliasInfo.BaseTypeInfo.Name)
 			}
 		}
 		
 		// Проверяем пользовательские типы  
-		if _, exists := scope.GlobalScope.Get(expectedType + "__TypeInfo"); exists {
+		if _, exists := rt.Scope().Get(expectedType + "__TypeInfo"); exists {
 			// Для пользовательских типов пока возвращаем nil (считаем валидными)
 			return nil
 		}
@@ -119,8 +144,58 @@ func validateVariableType(val *Value, expectedType string) error {
 	}
 }
 
+// validateStructType проверяет, что значение - экземпляр структуры, а для
+// generic структуры - что его аргументы типа (или поля) соответствуют args
+func validateStructType(rt *Runtime, val *Value, typeInfo *TypeInfo, args []string) error {
+	expected := FormatGenericType(typeInfo.Name, args)
+
+	structObj, ok := val.Any().(*StructObject)
+	if !ok || structObj.TypeInfo.Name != typeInfo.Name {
+		return fmt.Errorf("expected %s, got %s", expected, ValueTypeName(val))
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	if len(args) != len(typeInfo.TypeParams) {
+		return fmt.Errorf("%s expects %d type argument(s), got %d", typeInfo.Name, len(typeInfo.TypeParams), len(args))
+	}
+
+	for i, arg := range args {
+		if i < len(structObj.TypeArgs) && structObj.TypeArgs[i] != "any" && arg != "any" && structObj.TypeArgs[i] != arg {
+			return fmt.Errorf("expected %s, got %s", expected, structObj.TypeName())
+		}
+	}
+
+	// Аргументы, которые не удалось вывести, проверяются по значениям полей
+	names := TypeParamNames(typeInfo.TypeParams)
+	for field, declared := range typeInfo.FieldTypes {
+		fieldVal := structObj.Fields[field]
+		if !MentionsTypeParam(declared, names) || fieldVal == nil || fieldVal.Any() == nil {
+			continue
+		}
+		if err := validateVariableType(rt, fieldVal, SubstituteTypeParams(declared, names, args)); err != nil {
+			return fmt.Errorf("expected %s, field '%s': %v", expected, field, err)
+		}
+	}
+	return nil
+}
+
+// validateArrayType проверяет, что значение - массив с элементами типа elem
+func validateArrayType(rt *Runtime, val *Value, elem string) error {
+	items, ok := val.Any().([]any)
+	if !ok {
+		return fmt.Errorf("expected Array<%s>, got %s", elem, ValueTypeName(val))
+	}
+	for i, item := range items {
+		if err := validateVariableType(rt, NewValue(item), elem); err != nil {
+			return fmt.Errorf("expected Array<%s>, element %d: %v", elem, i, err)
+		}
+	}
+	return nil
+}
+
 // validateTupleType проверяет соответствие значения Tuple типу
-func validateTupleType(val *Value, expectedType string) error {
+func validateTupleType(rt *Runtime, val *Value, expectedType string) error {
 	// Парсим Tuple тип: "(string,int,float)" -> ["string", "int", "float"]
 	tupleContent := strings.TrimPrefix(expectedType, "(")
 	tupleContent = strings.TrimSuffix(tupleContent, ")")
@@ -131,10 +206,15 @@ func validateTupleType(val *Value, expectedType string) error {
 		expectedTypes[i] = strings.TrimSpace(expectedTypes[i])
 	}
 	
-	// Проверяем, что значение является массивом
-	arr, ok := val.Any().([]interface{})
-	if !ok {
-		return fmt.Errorf("expected tuple (array), got %s", value.GetValueTypeName(val))
+	// Кортеж или массив такой же длины
+	var arr []any
+	switch data := val.Any().(type) {
+	case *value.Tuple:
+		arr = data.Items()
+	case []any:
+		arr = data
+	default:
+		return fmt.Errorf("expected tuple, got %s", value.GetValueTypeName(val))
 	}
 	
 	// Проверяем количество элементов
@@ -148,7 +228,7 @@ func validateTupleType(val *Value, expectedType string) error {
 		elementVal := value.NewValue(element)
 		expectedElementType := expectedTypes[i]
 		
-		if err := validateVariableType(elementVal, expectedElementType); err != nil {
+		if err := validateVariableType(rt, elementVal, expectedElementType); err != nil {
 			return fmt.Errorf("tuple element %d: %s", i, err.Error())
 		}
 	}
@@ -1,6 +1,9 @@
 package ast
 
+import "foo_lang/value"
+
 type UnaryOpExpr struct {
+	Node
 	Op    rune
 	Count int
 	Expr  Expr
@@ -10,17 +13,24 @@ func NewUnaryOpExpr(op rune, expr Expr, count int) *UnaryOpExpr {
 	return &UnaryOpExpr{Op: op, Expr: expr, Count: count}
 }
 
-func (u *UnaryOpExpr) Eval() *Value {
+func (u *UnaryOpExpr) Eval(rt *Runtime) *Value {
 	switch u.Op {
 	case '-':
-		return NewValue(-u.Expr.Eval().Float64())
+		operand := u.Expr.Eval(rt)
+		if result, ok := OverloadedNegate(rt, operand); ok {
+			return result
+		}
+		if value.IsBigNumber(operand) {
+			return value.Negate(operand, rt.OverflowCheck())
+		}
+		return NewValue(-operand.Float64())
 	case '!':
 		// If Count is odd (1, 3, 5...), apply NOT. If even (0, 2, 4...), return original
 		if u.Count%2 == 1 {
-			return NewValue(!u.Expr.Eval().Bool())
+			return NewValue(!u.Expr.Eval(rt).Bool())
 		}
-		return NewValue(u.Expr.Eval().Bool())
+		return NewValue(u.Expr.Eval(rt).Bool())
 	default:
-		return u.Expr.Eval()
+		return u.Expr.Eval(rt)
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 // UnionTypeExpr представляет union тип: string | number | null
 type UnionTypeExpr struct {
+	Node
 	Types []string // Список типов в union
 }
 
@@ -14,7 +15,7 @@ func NewUnionTypeExpr(types []string) *UnionTypeExpr {
 	return &UnionTypeExpr{Types: types}
 }
 
-func (u *UnionTypeExpr) Eval() *value.Value {
+func (u *UnionTypeExpr) Eval(rt *Runtime) *value.Value {
 	// Создаем специальный UnionTypeInfo
 	unionInfo := NewUnionTypeInfo(u.Types)
 	return value.NewValue(unionInfo)
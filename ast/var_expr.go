@@ -1,8 +1,8 @@
 package ast
 
-import "foo_lang/scope"
 
 type VarExpr struct {
+	Node
 	Name string
 	expr Expr
 }
@@ -14,8 +14,13 @@ func NewVarExpr(name string, expr Expr) *VarExpr {
 	}
 }
 
-func (n *VarExpr) Eval() *Value {
-	val, ok := scope.GlobalScope.Get(n.Name)
+// GetExpr возвращает присваиваемое выражение (nil, если это чтение переменной)
+func (n *VarExpr) GetExpr() Expr {
+	return n.expr
+}
+
+func (n *VarExpr) Eval(rt *Runtime) *Value {
+	val, ok := rt.Scope().Get(n.Name)
 	if !ok {
 		panic("variable " + n.Name + " is not defined")
 	}
@@ -26,8 +31,8 @@ func (n *VarExpr) Eval() *Value {
 
 	if n.expr != nil {
 		// Обновление существующей переменной
-		tmp := n.expr.Eval()
-		scope.GlobalScope.Update(n.Name, tmp)
+		tmp := n.expr.Eval(rt)
+		rt.Scope().Update(n.Name, tmp)
 		return tmp
 	}
 
@@ -0,0 +1,69 @@
+package ast
+
+// WhileExpr представляет цикл while cond { ... }
+type WhileExpr struct {
+	Node
+	ConditionExpr Expr
+	BodyExpr      Expr
+	Label         string
+}
+
+func NewWhileExpr(condition, body Expr) *WhileExpr {
+	return &WhileExpr{
+		ConditionExpr: condition,
+		BodyExpr:      body,
+	}
+}
+
+func (w *WhileExpr) SetLabel(label string) {
+	w.Label = label
+}
+
+func (w *WhileExpr) Eval(rt *Runtime) *Value {
+	statments := w.BodyExpr.(*BodyExpr).Statments
+	var yield []any
+
+	for w.ConditionExpr.Eval(rt).Bool() {
+		exit, brk := loopIteration(rt, w.Label, statments, nil, &yield)
+		if exit != nil {
+			return exit
+		}
+		if brk {
+			break
+		}
+	}
+
+	return NewValue(yield)
+}
+
+// LoopExpr представляет бесконечный цикл loop { ... }, из которого выходят через break или return
+type LoopExpr struct {
+	Node
+	BodyExpr Expr
+	Label    string
+}
+
+func NewLoopExpr(body Expr) *LoopExpr {
+	return &LoopExpr{BodyExpr: body}
+}
+
+func (l *LoopExpr) SetLabel(label string) {
+	l.Label = label
+}
+
+func (l *LoopExpr) Eval(rt *Runtime) *Value {
+	statments := l.BodyExpr.(*BodyExpr).Statments
+	var yield []any
+
+	for {
+		exit, brk := loopIteration(rt, l.Label, statments, nil, &yield)
+		if exit != nil {
+			return exit
+		}
+		if brk {
+			break
+		}
+	}
+
+	return NewValue(yield)
+}
@@ -1,6 +1,7 @@
 package ast
 
 type YieldExpr struct {
+	Node
 	Expr Expr
 }
 
@@ -8,11 +9,11 @@ func NewYieldExpr(expr Expr) *YieldExpr {
 	return &YieldExpr{Expr: expr}
 }
 
-func (r *YieldExpr) Eval() *Value {
+func (r *YieldExpr) Eval(rt *Runtime) *Value {
 	if r.Expr == nil {
 		return nil
 	}
-	val := r.Expr.Eval()
+	val := r.Expr.Eval(rt)
 	// Mark the value as a yield value
 	result := NewValue(val.Any())
 	result.SetYield(true)
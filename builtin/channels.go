@@ -12,7 +12,7 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// newChannel - создание канала
 	newChannelFunc := func(args []*value.Value) *value.Value {
 		if len(args) > 1 {
-			return value.NewString("Error: newChannel() requires 0 or 1 argument ([bufferSize])")
+			panic("newChannel() requires 0 or 1 argument ([bufferSize])")
 		}
 		
 		// Аргумент - размер буфера (опциональный)
@@ -31,17 +31,17 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// send - отправка в канал (синтаксический сахар для ch <- value)
 	sendFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: send() requires 2 arguments (channel, value)")
+			panic("send() requires 2 arguments (channel, value)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: first argument must be a channel")
+			panic("send() first argument must be a channel")
 		}
 		
 		err := chVal.SendBlocking(args[1])
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error: %v", err))
+			panic(err.Error())
 		}
 		
 		return value.NewString("sent")
@@ -51,17 +51,17 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// receive - получение из канала (синтаксический сахар для <-ch)
 	receiveFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: receive() requires 1 argument (channel)")
+			panic("receive() requires 1 argument (channel)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: argument must be a channel")
+			panic("receive() argument must be a channel")
 		}
 		
 		result, err := chVal.ReceiveBlocking()
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error: %v", err))
+			panic(err.Error())
 		}
 		
 		return result
@@ -71,12 +71,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// close - закрытие канала
 	closeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: close() requires 1 argument (channel)")
+			panic("close() requires 1 argument (channel)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: argument must be a channel")
+			panic("close() argument must be a channel")
 		}
 		
 		chVal.Close()
@@ -87,7 +87,7 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// len - количество элементов в канале
 	lenFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: len() requires 1 argument")
+			panic("len() requires 1 argument")
 		}
 		
 		switch val := args[0].Any().(type) {
@@ -98,7 +98,7 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 		case string:
 			return value.NewInt64(int64(len(val)))
 		default:
-			return value.NewString("Error: len() requires array, string, or channel")
+			panic("len() requires array, string, or channel")
 		}
 	}
 	globalScope.Set("len", value.NewValue(lenFunc))
@@ -106,12 +106,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// cap - емкость канала
 	capFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: cap() requires 1 argument")
+			panic("cap() requires 1 argument")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: cap() requires a channel")
+			panic("cap() requires a channel")
 		}
 		
 		return value.NewInt64(int64(chVal.Cap()))
@@ -121,12 +121,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// tryReceive - неблокирующее получение из канала
 	tryReceiveFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: tryReceive() requires 1 argument (channel)")
+			panic("tryReceive() requires 1 argument (channel)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: argument must be a channel")
+			panic("tryReceive() argument must be a channel")
 		}
 		
 		result, success := chVal.TryReceive()
@@ -141,12 +141,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// channelInfo - информация о канале
 	channelInfoFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: channelInfo() requires 1 argument (channel)")
+			panic("channelInfo() requires 1 argument (channel)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: argument must be a channel")
+			panic("channelInfo() argument must be a channel")
 		}
 		
 		return value.NewString(chVal.String())
@@ -156,12 +156,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// trySend - неблокирующая отправка в канал
 	trySendFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: trySend() requires 2 arguments (channel, value)")
+			panic("trySend() requires 2 arguments (channel, value)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: first argument must be a channel")
+			panic("trySend() first argument must be a channel")
 		}
 		
 		success := chVal.TrySend(args[1])
@@ -172,12 +172,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// channelSelect - select для множественного выбора каналов
 	channelSelectFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 {
-			return value.NewString("Error: channelSelect() requires at least 1 argument (array of channels)")
+			panic("channelSelect() requires at least 1 argument (array of channels)")
 		}
 		
 		channels, ok := args[0].Any().([]interface{})
 		if !ok {
-			return value.NewString("Error: first argument must be array of channels")
+			panic("channelSelect() first argument must be array of channels")
 		}
 		
 		// Создаем select операцию
@@ -193,7 +193,7 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 		// Выполняем select
 		index, result, err := sel.Execute()
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error: %v", err))
+			panic(err.Error())
 		}
 		
 		// Возвращаем объект с результатом
@@ -208,22 +208,22 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// channelTimeout - операции с настраиваемым таймаутом
 	channelTimeoutFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 3 {
-			return value.NewString("Error: channelTimeout() requires 3 arguments (channel, operation, timeoutMs)")
+			panic("channelTimeout() requires 3 arguments (channel, operation, timeoutMs)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: first argument must be a channel")
+			panic("channelTimeout() first argument must be a channel")
 		}
 		
 		operation, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: second argument must be operation string ('send' or 'receive')")
+			panic("channelTimeout() second argument must be operation string ('send' or 'receive')")
 		}
 		
 		timeoutMs, ok := args[2].Any().(int64)
 		if !ok {
-			return value.NewString("Error: third argument must be timeout in milliseconds")
+			panic("channelTimeout() third argument must be timeout in milliseconds")
 		}
 		
 		switch operation {
@@ -247,12 +247,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 			case result := <-resultChan:
 				return result
 			case err := <-errorChan:
-				return value.NewString(fmt.Sprintf("Error: %v", err))
+				panic(err.Error())
 			case <-time.After(time.Duration(timeoutMs) * time.Millisecond):
 				return value.NewString("timeout")
 			}
 		default:
-			return value.NewString("Error: unsupported operation. Use 'receive'")
+			panic("channelTimeout() unsupported operation, use 'receive'")
 		}
 	}
 	globalScope.Set("channelTimeout", value.NewValue(channelTimeoutFunc))
@@ -260,12 +260,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// channelRange - итерация по каналу до закрытия
 	channelRangeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: channelRange() requires 1 argument (channel)")
+			panic("channelRange() requires 1 argument (channel)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: argument must be a channel")
+			panic("channelRange() argument must be a channel")
 		}
 		
 		var results []interface{}
@@ -290,12 +290,12 @@ func InitializeChannelFunctions(globalScope *scope.ScopeStack) {
 	// channelDrain - очистка канала
 	channelDrainFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: channelDrain() requires 1 argument (channel)")
+			panic("channelDrain() requires 1 argument (channel)")
 		}
 		
 		chVal, ok := args[0].Any().(*value.Channel)
 		if !ok {
-			return value.NewString("Error: argument must be a channel")
+			panic("channelDrain() argument must be a channel")
 		}
 		
 		var drained []interface{}
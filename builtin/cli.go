@@ -294,42 +294,14 @@ func ParseArgs(args []*value.Value) (*value.Value, error) {
 // InitializeCliFunctions инициализирует встроенные CLI функции
 func InitializeCliFunctions(globalScope *scope.ScopeStack) {
 	// getScriptName функция
-	getScriptNameFunc := func(args []*value.Value) *value.Value {
-		result, err := GetScriptName(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getScriptName", value.NewValue(getScriptNameFunc))
+	globalScope.Set("getScriptName", value.NewValue(wrap(GetScriptName)))
 	
 	// getArgCount функция
-	getArgCountFunc := func(args []*value.Value) *value.Value {
-		result, err := GetArgCount(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getArgCount", value.NewValue(getArgCountFunc))
+	globalScope.Set("getArgCount", value.NewValue(wrap(GetArgCount)))
 	
 	// getArgs функция
-	getArgsFunc := func(args []*value.Value) *value.Value {
-		result, err := GetArgs(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getArgs", value.NewValue(getArgsFunc))
+	globalScope.Set("getArgs", value.NewValue(wrap(GetArgs)))
 	
 	// getFlag функция
-	getFlagFunc := func(args []*value.Value) *value.Value {
-		result, err := GetFlag(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getFlag", value.NewValue(getFlagFunc))
+	globalScope.Set("getFlag", value.NewValue(wrap(GetFlag)))
 }
\ No newline at end of file
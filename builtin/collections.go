@@ -0,0 +1,101 @@
+package builtin
+
+import (
+	"foo_lang/ast"
+	"foo_lang/scope"
+	"foo_lang/value"
+)
+
+// InitializeCollectionFunctions регистрирует заморозку значений,
+// персистентные коллекции List и Map, множества и кортежи
+func InitializeCollectionFunctions(globalScope *scope.ScopeStack) {
+	// freeze(value) - глубокая заморозка объекта, массива или структуры
+	globalScope.Set("freeze", value.NewValue(func(args []*value.Value) *value.Value {
+		if len(args) != 1 {
+			panic("freeze() expects exactly 1 argument")
+		}
+		return ast.Freeze(args[0])
+	}))
+
+	globalScope.Set("isFrozen", value.NewValue(func(args []*value.Value) *value.Value {
+		if len(args) != 1 {
+			panic("isFrozen() expects exactly 1 argument")
+		}
+		return value.NewValue(ast.IsFrozen(args[0]))
+	}))
+
+	// clone(value) - изменяемая глубокая копия, например замороженного объекта
+	globalScope.Set("clone", value.NewValue(func(args []*value.Value) *value.Value {
+		if len(args) != 1 {
+			panic("clone() expects exactly 1 argument")
+		}
+		return ast.Clone(args[0])
+	}))
+
+	// List() или List(array) - персистентный список
+	globalScope.Set("List", value.NewValue(func(args []*value.Value) *value.Value {
+		switch {
+		case len(args) == 0:
+			return value.NewValue(value.NewList())
+		case len(args) == 1:
+			items, ok := args[0].Any().([]any)
+			if !ok {
+				panic("List() expects an array, got " + value.GetValueTypeName(args[0]))
+			}
+			values := make([]*value.Value, len(items))
+			for i, item := range items {
+				values[i] = value.NewValue(item)
+			}
+			return value.NewValue(ast.NewPersistentList(values))
+		}
+		panic("List() expects 0 or 1 argument ([array])")
+	}))
+
+	// Map() или Map(object) - персистентный словарь
+	globalScope.Set("Map", value.NewValue(func(args []*value.Value) *value.Value {
+		switch {
+		case len(args) == 0:
+			return value.NewValue(value.NewMap())
+		case len(args) == 1:
+			obj, ok := args[0].Any().(*value.Object)
+			if !ok {
+				panic("Map() expects an object, got " + value.GetValueTypeName(args[0]))
+			}
+			return value.NewValue(ast.NewPersistentMap(obj.Fields()))
+		}
+		panic("Map() expects 0 or 1 argument ([object])")
+	}))
+
+	// Set() или Set(array) - множество, повторы отбрасываются
+	globalScope.Set("Set", value.NewValue(func(args []*value.Value) *value.Value {
+		set := value.NewSet()
+		switch {
+		case len(args) == 0:
+			return value.NewValue(set)
+		case len(args) == 1:
+			items, ok := args[0].Any().([]any)
+			if !ok {
+				panic("Set() expects an array, got " + value.GetValueTypeName(args[0]))
+			}
+			for _, item := range items {
+				if err := set.Add(item); err != nil {
+					panic(err.Error())
+				}
+			}
+			return value.NewValue(set)
+		}
+		panic("Set() expects 0 or 1 argument ([array])")
+	}))
+
+	// Tuple(array) - кортеж из элементов массива
+	globalScope.Set("Tuple", value.NewValue(func(args []*value.Value) *value.Value {
+		if len(args) != 1 {
+			panic("Tuple() expects exactly 1 argument (array)")
+		}
+		items, ok := args[0].Any().([]any)
+		if !ok {
+			panic("Tuple() expects an array, got " + value.GetValueTypeName(args[0]))
+		}
+		return value.NewValue(value.NewTuple(append([]any(nil), items...)...))
+	}))
+}
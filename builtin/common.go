@@ -1,8 +1,80 @@
 package builtin
 
-import "foo_lang/value"
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/scope"
+	"foo_lang/value"
+	"strings"
+	"time"
+)
 
 // ScopeStack интерфейс для области видимости (чтобы избежать циклических импортов)
 type ScopeStack interface {
 	Set(name string, val *value.Value)
-}
\ No newline at end of file
+}
+
+// InitializeAll регистрирует все встроенные функции и глобальные объекты в scope.
+// Примитивы синхронизации и HTTP-сервер функций принадлежат Runtime rt (см. StateOf)
+func InitializeAll(rt *ast.Runtime, globalScope *scope.ScopeStack) {
+	state := StateOf(rt)
+
+	InitializeMathFunctions(globalScope)
+	InitializeStringFunctions(globalScope)
+	InitializeFilesystemFunctions(globalScope)
+	initializeHttpFunctions(globalScope, state)
+	InitializeChannelFunctions(globalScope)
+	InitializeTimeFunctions(globalScope)
+	InitializeCryptoFunctions(globalScope)
+	InitializeRegexFunctions(globalScope)
+	initializeSyncFunctions(globalScope, state)
+	InitializeCollectionFunctions(globalScope)
+
+	// Новые критически важные функции
+	InitializeStdioFunctions(globalScope)
+	InitializeProcessFunctions(globalScope)
+	InitializeCliFunctions(globalScope)
+	InitializeDebugFunctions(globalScope)
+
+	// Extension methods и глобальные объекты
+	InitializeSystemExtensions(globalScope) // Extension methods для System, IO, Console и т.д.
+	InitializeGlobalObjects(globalScope)    // Глобальные объекты IO, System, Console, Process и т.д.
+	InitializeResultFunctions(globalScope)  // Result функции Ok/Err для обработки ошибок
+
+	// Мост для стандартной библиотеки: std/*.foo вызывают __builtin_<name>
+	InitializeStdBridge(globalScope)
+}
+
+// StdBridgePrefix - префикс, под которым встроенные функции доступны модулям std
+const StdBridgePrefix = "__builtin_"
+
+// stdBridgeAliases - функции моста, имя которых отличается от встроенной функции
+var stdBridgeAliases = map[string]string{
+	"timeDiffSeconds": "timeDiff",
+}
+
+// InitializeStdBridge дублирует все функции, уже зарегистрированные в scope,
+// под именами __builtin_<name>. Модули std оборачивают их, а пользовательский код
+// может переопределить readFile или now, не ломая стандартную библиотеку
+func InitializeStdBridge(globalScope *scope.ScopeStack) {
+	for name, val := range globalScope.GetAll() {
+		if !strings.HasPrefix(name, StdBridgePrefix) {
+			globalScope.Set(StdBridgePrefix+name, val)
+		}
+	}
+
+	for alias, name := range stdBridgeAliases {
+		if val, ok := globalScope.Get(name); ok {
+			globalScope.Set(StdBridgePrefix+alias, val)
+		}
+	}
+
+	// sleep - ключевое слово языка, для std/time.foo нужна обычная функция
+	globalScope.Set(StdBridgePrefix+"sleep", value.NewValue(func(args []*value.Value) *value.Value {
+		if len(args) != 1 || !args[0].IsNumber() {
+			panic(fmt.Sprintf("sleep() requires a number of milliseconds, got %d argument(s)", len(args)))
+		}
+		time.Sleep(time.Duration(args[0].Float64() * float64(time.Millisecond)))
+		return value.NewValue(nil)
+	}))
+}
@@ -23,12 +23,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// md5Hash - вычисление MD5 хеша
 	md5HashFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: md5Hash() requires 1 argument (data)")
+			panic("md5Hash() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: md5Hash() requires string argument")
+			panic("md5Hash() requires string argument")
 		}
 		
 		hash := md5.Sum([]byte(data))
@@ -39,12 +39,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// sha1Hash - вычисление SHA1 хеша
 	sha1HashFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: sha1Hash() requires 1 argument (data)")
+			panic("sha1Hash() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: sha1Hash() requires string argument")
+			panic("sha1Hash() requires string argument")
 		}
 		
 		hash := sha1.Sum([]byte(data))
@@ -55,12 +55,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// sha256Hash - вычисление SHA256 хеша
 	sha256HashFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: sha256Hash() requires 1 argument (data)")
+			panic("sha256Hash() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: sha256Hash() requires string argument")
+			panic("sha256Hash() requires string argument")
 		}
 		
 		hash := sha256.Sum256([]byte(data))
@@ -71,12 +71,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// sha512Hash - вычисление SHA512 хеша
 	sha512HashFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: sha512Hash() requires 1 argument (data)")
+			panic("sha512Hash() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: sha512Hash() requires string argument")
+			panic("sha512Hash() requires string argument")
 		}
 		
 		hash := sha512.Sum512([]byte(data))
@@ -89,12 +89,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// base64Encode - кодирование в Base64
 	base64EncodeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: base64Encode() requires 1 argument (data)")
+			panic("base64Encode() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: base64Encode() requires string argument")
+			panic("base64Encode() requires string argument")
 		}
 		
 		encoded := base64.StdEncoding.EncodeToString([]byte(data))
@@ -105,32 +105,32 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// base64Decode - декодирование из Base64
 	base64DecodeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: base64Decode() requires 1 argument (encodedData)")
+			panic("base64Decode() requires 1 argument (encodedData)")
 		}
 		
 		encodedData, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: base64Decode() requires string argument")
+			panic("base64Decode() requires string argument")
 		}
 		
 		decoded, err := base64.StdEncoding.DecodeString(encodedData)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error decoding base64: %v", err))
+			return parseError("decoding base64: %v", err)
 		}
 		
-		return value.NewString(string(decoded))
+		return okResult(value.NewString(string(decoded)))
 	}
 	globalScope.Set("base64Decode", value.NewValue(base64DecodeFunc))
 
 	// base64URLEncode - кодирование в Base64 URL-safe
 	base64URLEncodeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: base64URLEncode() requires 1 argument (data)")
+			panic("base64URLEncode() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: base64URLEncode() requires string argument")
+			panic("base64URLEncode() requires string argument")
 		}
 		
 		encoded := base64.URLEncoding.EncodeToString([]byte(data))
@@ -141,20 +141,20 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// base64URLDecode - декодирование из Base64 URL-safe
 	base64URLDecodeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: base64URLDecode() requires 1 argument (encodedData)")
+			panic("base64URLDecode() requires 1 argument (encodedData)")
 		}
 		
 		encodedData, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: base64URLDecode() requires string argument")
+			panic("base64URLDecode() requires string argument")
 		}
 		
 		decoded, err := base64.URLEncoding.DecodeString(encodedData)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error decoding base64URL: %v", err))
+			return parseError("decoding base64URL: %v", err)
 		}
 		
-		return value.NewString(string(decoded))
+		return okResult(value.NewString(string(decoded)))
 	}
 	globalScope.Set("base64URLDecode", value.NewValue(base64URLDecodeFunc))
 
@@ -163,12 +163,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// hexEncode - кодирование в HEX
 	hexEncodeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: hexEncode() requires 1 argument (data)")
+			panic("hexEncode() requires 1 argument (data)")
 		}
 		
 		data, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: hexEncode() requires string argument")
+			panic("hexEncode() requires string argument")
 		}
 		
 		encoded := hex.EncodeToString([]byte(data))
@@ -179,20 +179,20 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// hexDecode - декодирование из HEX
 	hexDecodeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: hexDecode() requires 1 argument (hexData)")
+			panic("hexDecode() requires 1 argument (hexData)")
 		}
 		
 		hexData, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: hexDecode() requires string argument")
+			panic("hexDecode() requires string argument")
 		}
 		
 		decoded, err := hex.DecodeString(hexData)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error decoding hex: %v", err))
+			return parseError("decoding hex: %v", err)
 		}
 		
-		return value.NewString(string(decoded))
+		return okResult(value.NewString(string(decoded)))
 	}
 	globalScope.Set("hexDecode", value.NewValue(hexDecodeFunc))
 
@@ -201,17 +201,17 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// hmacSHA256 - HMAC с SHA256
 	hmacSHA256Func := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: hmacSHA256() requires 2 arguments (key, data)")
+			panic("hmacSHA256() requires 2 arguments (key, data)")
 		}
 		
 		key, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: hmacSHA256() first argument must be string (key)")
+			panic("hmacSHA256() first argument must be string (key)")
 		}
 		
 		data, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: hmacSHA256() second argument must be string (data)")
+			panic("hmacSHA256() second argument must be string (data)")
 		}
 		
 		mac := hmac.New(sha256.New, []byte(key))
@@ -225,17 +225,17 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// hmacSHA1 - HMAC с SHA1
 	hmacSHA1Func := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: hmacSHA1() requires 2 arguments (key, data)")
+			panic("hmacSHA1() requires 2 arguments (key, data)")
 		}
 		
 		key, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: hmacSHA1() first argument must be string (key)")
+			panic("hmacSHA1() first argument must be string (key)")
 		}
 		
 		data, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: hmacSHA1() second argument must be string (data)")
+			panic("hmacSHA1() second argument must be string (data)")
 		}
 		
 		mac := hmac.New(sha1.New, []byte(key))
@@ -249,17 +249,17 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// hmacMD5 - HMAC с MD5
 	hmacMD5Func := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: hmacMD5() requires 2 arguments (key, data)")
+			panic("hmacMD5() requires 2 arguments (key, data)")
 		}
 		
 		key, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: hmacMD5() first argument must be string (key)")
+			panic("hmacMD5() first argument must be string (key)")
 		}
 		
 		data, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: hmacMD5() second argument must be string (data)")
+			panic("hmacMD5() second argument must be string (data)")
 		}
 		
 		mac := hmac.New(md5.New, []byte(key))
@@ -275,7 +275,7 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// randomBytes - генерация случайных байтов в hex формате
 	randomBytesFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: randomBytes() requires 1 argument (length)")
+			panic("randomBytes() requires 1 argument (length)")
 		}
 		
 		length, ok := args[0].Any().(int64)
@@ -284,18 +284,18 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				length = int64(floatVal)
 			} else {
-				return value.NewString("Error: randomBytes() requires numeric argument")
+				panic("randomBytes() requires numeric argument")
 			}
 		}
 		
 		if length <= 0 || length > 1024 {
-			return value.NewString("Error: randomBytes() length must be between 1 and 1024")
+			panic("randomBytes() length must be between 1 and 1024")
 		}
 		
 		bytes := make([]byte, length)
 		_, err := rand.Read(bytes)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error generating random bytes: %v", err))
+			panic(fmt.Sprintf("generating random bytes: %v", err))
 		}
 		
 		return value.NewString(hex.EncodeToString(bytes))
@@ -305,7 +305,7 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// randomString - генерация случайной строки из заданного алфавита
 	randomStringFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 || len(args) > 2 {
-			return value.NewString("Error: randomString() requires 1-2 arguments (length, [alphabet])")
+			panic("randomString() requires 1-2 arguments (length, [alphabet])")
 		}
 		
 		length, ok := args[0].Any().(int64)
@@ -314,12 +314,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				length = int64(floatVal)
 			} else {
-				return value.NewString("Error: randomString() first argument must be numeric")
+				panic("randomString() first argument must be numeric")
 			}
 		}
 		
 		if length <= 0 || length > 1024 {
-			return value.NewString("Error: randomString() length must be between 1 and 1024")
+			panic("randomString() length must be between 1 and 1024")
 		}
 		
 		// Алфавит по умолчанию
@@ -329,11 +329,11 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 		if len(args) == 2 {
 			if userAlphabet, ok := args[1].Any().(string); ok {
 				if len(userAlphabet) == 0 {
-					return value.NewString("Error: alphabet cannot be empty")
+					panic("randomString() alphabet cannot be empty")
 				}
 				alphabet = userAlphabet
 			} else {
-				return value.NewString("Error: alphabet must be a string")
+				panic("randomString() alphabet must be a string")
 			}
 		}
 		
@@ -343,7 +343,7 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 		for i := range result {
 			randomIndex, err := rand.Int(rand.Reader, alphabetLen)
 			if err != nil {
-				return value.NewString(fmt.Sprintf("Error generating random string: %v", err))
+				panic(fmt.Sprintf("generating random string: %v", err))
 			}
 			result[i] = alphabet[randomIndex.Int64()]
 		}
@@ -355,7 +355,7 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// randomInt - генерация случайного числа в диапазоне
 	randomIntFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: randomInt() requires 2 arguments (min, max)")
+			panic("randomInt() requires 2 arguments (min, max)")
 		}
 		
 		min, ok := args[0].Any().(int64)
@@ -364,7 +364,7 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				min = int64(floatVal)
 			} else {
-				return value.NewString("Error: randomInt() first argument must be numeric")
+				panic("randomInt() first argument must be numeric")
 			}
 		}
 		
@@ -374,19 +374,19 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				max = int64(floatVal)
 			} else {
-				return value.NewString("Error: randomInt() second argument must be numeric")
+				panic("randomInt() second argument must be numeric")
 			}
 		}
 		
 		if min >= max {
-			return value.NewString("Error: min must be less than max")
+			panic("randomInt() min must be less than max")
 		}
 		
 		// Генерируем случайное число в диапазоне [min, max)
 		rangeSize := max - min
 		randomBig, err := rand.Int(rand.Reader, big.NewInt(rangeSize))
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error generating random int: %v", err))
+			panic(fmt.Sprintf("generating random int: %v", err))
 		}
 		
 		result := min + randomBig.Int64()
@@ -399,14 +399,14 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// randomUUID - генерация UUID v4
 	randomUUIDFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 0 {
-			return value.NewString("Error: randomUUID() requires 0 arguments")
+			panic("randomUUID() requires 0 arguments")
 		}
 		
 		// Генерируем 16 случайных байтов
 		bytes := make([]byte, 16)
 		_, err := rand.Read(bytes)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error generating UUID: %v", err))
+			panic(fmt.Sprintf("generating UUID: %v", err))
 		}
 		
 		// Устанавливаем биты версии (4) и варианта
@@ -430,17 +430,17 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// constantTimeCompare - безопасное сравнение строк (защита от timing атак)
 	constantTimeCompareFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: constantTimeCompare() requires 2 arguments (a, b)")
+			panic("constantTimeCompare() requires 2 arguments (a, b)")
 		}
 		
 		a, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: constantTimeCompare() first argument must be string")
+			panic("constantTimeCompare() first argument must be string")
 		}
 		
 		b, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: constantTimeCompare() second argument must be string")
+			panic("constantTimeCompare() second argument must be string")
 		}
 		
 		// Используем hmac.Equal для constant-time сравнения
@@ -454,12 +454,12 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// passwordHash - простое хеширование пароля (SHA256 + соль)
 	passwordHashFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 || len(args) > 2 {
-			return value.NewString("Error: passwordHash() requires 1-2 arguments (password, [salt])")
+			panic("passwordHash() requires 1-2 arguments (password, [salt])")
 		}
 		
 		password, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: passwordHash() first argument must be string")
+			panic("passwordHash() first argument must be string")
 		}
 		
 		// Генерируем соль если не предоставлена
@@ -468,14 +468,14 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 			if userSalt, ok := args[1].Any().(string); ok {
 				salt = userSalt
 			} else {
-				return value.NewString("Error: salt must be a string")
+				panic("passwordHash() salt must be a string")
 			}
 		} else {
 			// Генерируем случайную соль
 			saltBytes := make([]byte, 16)
 			_, err := rand.Read(saltBytes)
 			if err != nil {
-				return value.NewString(fmt.Sprintf("Error generating salt: %v", err))
+				panic(fmt.Sprintf("generating salt: %v", err))
 			}
 			salt = hex.EncodeToString(saltBytes)
 		}
@@ -493,23 +493,23 @@ func InitializeCryptoFunctions(globalScope *scope.ScopeStack) {
 	// passwordVerify - проверка пароля против хеша
 	passwordVerifyFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: passwordVerify() requires 2 arguments (password, hash)")
+			panic("passwordVerify() requires 2 arguments (password, hash)")
 		}
 		
 		password, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: passwordVerify() first argument must be string")
+			panic("passwordVerify() first argument must be string")
 		}
 		
 		storedHash, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: passwordVerify() second argument must be string")
+			panic("passwordVerify() second argument must be string")
 		}
 		
-		// Разбираем сохраненный хеш (salt:hash)
+		// Разбираем сохраненный хеш (salt:hash). Испорченному хешу не подходит ни один пароль
 		parts := splitString(storedHash, ":")
 		if len(parts) != 2 {
-			return value.NewString("Error: invalid hash format")
+			return value.NewBool(false)
 		}
 		
 		salt := parts[0]
@@ -2,8 +2,10 @@ package builtin
 
 import (
 	"fmt"
+	"foo_lang/ast"
 	"foo_lang/scope"
 	"foo_lang/value"
+	"math/big"
 	"os"
 	"reflect"
 	"runtime"
@@ -20,12 +22,17 @@ func Debug(args []*value.Value) (*value.Value, error) {
 	}
 
 	val := args[0]
-	output := formatDebugValue(val, 0)
+	output := FormatDebugValue(val)
 	fmt.Println("[DEBUG]", output)
 
 	return val, nil
 }
 
+// FormatDebugValue форматирует значение с указанием типа (используется debug() и REPL)
+func FormatDebugValue(val *value.Value) string {
+	return formatDebugValue(val, 0)
+}
+
 // formatDebugValue форматирует значение для отладки
 func formatDebugValue(val *value.Value, indent int) string {
 	indentStr := strings.Repeat("  ", indent)
@@ -39,6 +46,27 @@ func formatDebugValue(val *value.Value, indent int) string {
 		return fmt.Sprintf("%snull", indentStr)
 	}
 
+	switch d := data.(type) {
+	case *value.Object:
+		// Поля объекта выводятся в порядке добавления
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Object[%d] {\n", d.Len()))
+		for _, key := range d.Keys() {
+			field, _ := d.Get(key)
+			sb.WriteString(fmt.Sprintf("%s  %s: %s\n", indentStr, key, formatDebugValue(field, indent+1)))
+		}
+		sb.WriteString(fmt.Sprintf("%s}", indentStr))
+		return sb.String()
+	case *value.Tuple:
+		return formatDebugItems("Tuple", "(", ")", d.Items(), indent)
+	case *value.Set:
+		return formatDebugItems("Set", "{", "}", d.Items(), indent)
+	case *big.Int:
+		return fmt.Sprintf("BigInt: %s", d)
+	case *value.Decimal:
+		return fmt.Sprintf("Decimal: %s", d)
+	}
+
 	// Определяем тип и значение
 	t := reflect.TypeOf(data)
 	v := reflect.ValueOf(data)
@@ -59,7 +87,11 @@ func formatDebugValue(val *value.Value, indent int) string {
 		var sb strings.Builder
 		sb.WriteString(fmt.Sprintf("Array[%d] [\n", v.Len()))
 		for i := 0; i < v.Len(); i++ {
-			item := v.Index(i).Interface().(*value.Value)
+			// Элементы массивов хранятся как "сырые" значения
+			item, ok := v.Index(i).Interface().(*value.Value)
+			if !ok {
+				item = value.NewValue(v.Index(i).Interface())
+			}
 			sb.WriteString(fmt.Sprintf("%s  [%d]: %s\n", indentStr, i, formatDebugValue(item, indent+1)))
 		}
 		sb.WriteString(fmt.Sprintf("%s]", indentStr))
@@ -85,8 +117,21 @@ func formatDebugValue(val *value.Value, indent int) string {
 	}
 }
 
-// Trace выводит трассировку стека вызовов
-func Trace(args []*value.Value) (*value.Value, error) {
+// formatDebugItems форматирует элементы кортежа или множества
+func formatDebugItems(kind, open, close string, items []any, indent int) string {
+	indentStr := strings.Repeat("  ", indent)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s[%d] %s\n", kind, len(items), open))
+	for i, item := range items {
+		sb.WriteString(fmt.Sprintf("%s  [%d]: %s\n", indentStr, i, formatDebugValue(value.NewValue(item), indent+1)))
+	}
+	sb.WriteString(indentStr + close)
+	return sb.String()
+}
+
+// Trace выводит стек вызовов foo в точке вызова trace(): функции и позиции
+// в скрипте, начиная с самого глубокого кадра. Аргумент ограничивает число кадров
+func Trace(rt *ast.Runtime, args []*value.Value) (*value.Value, error) {
 	maxDepth := 10
 	if len(args) == 1 {
 		if args[0].IsNumber() {
@@ -99,44 +144,34 @@ func Trace(args []*value.Value) (*value.Value, error) {
 	}
 
 	fmt.Println("=== Stack Trace ===")
-	
-	// Получаем стек вызовов
-	pc := make([]uintptr, maxDepth)
-	n := runtime.Callers(2, pc)
-	
-	if n == 0 {
-		fmt.Println("No stack trace available")
-		return value.NewValue(nil), nil
-	}
 
-	frames := runtime.CallersFrames(pc[:n])
-	
-	i := 0
-	for {
-		frame, more := frames.Next()
-		
-		// Пропускаем внутренние вызовы runtime
-		if strings.Contains(frame.File, "runtime/") {
-			if !more {
-				break
-			}
-			continue
-		}
-		
+	frames := rt.StackTrace()
+	if len(frames) > maxDepth {
+		frames = frames[:maxDepth]
+	}
+	for i, frame := range frames {
 		fmt.Printf("#%d %s\n", i, frame.Function)
-		fmt.Printf("   %s:%d\n", frame.File, frame.Line)
-		i++
-		
-		if !more {
-			break
+		if frame.Position.IsValid() {
+			fmt.Printf("   %s\n", frame.Position)
 		}
 	}
-	
+
 	fmt.Println("==================")
-	
+
 	return value.NewValue(nil), nil
 }
 
+// traceBuiltin - встроенная trace(): ей нужен стек вызовов вызывающего кода
+func traceBuiltin() *ast.Builtin {
+	return ast.NewBuiltin("trace", func(rt *ast.Runtime, args []*value.Value) *value.Value {
+		result, err := Trace(rt, args)
+		if err != nil {
+			panic(err.Error())
+		}
+		return result
+	})
+}
+
 // GetStackTrace возвращает стек вызовов как строку
 func GetStackTrace(args []*value.Value) (*value.Value, error) {
 	if len(args) != 0 {
@@ -323,11 +358,20 @@ func TypeOf(args []*value.Value) (*value.Value, error) {
 		return value.NewValue("float"), nil
 	case bool:
 		return value.NewValue("bool"), nil
-	case map[string]*value.Value:
+	case *value.Object:
 		return value.NewValue("object"), nil
-	case []*value.Value:
+	case *value.Set:
+		return value.NewValue("set"), nil
+	case *value.Tuple:
+		return value.NewValue("tuple"), nil
+	case *big.Int:
+		return value.NewValue("bigint"), nil
+	case *value.Decimal:
+		return value.NewValue("decimal"), nil
+	case []*value.Value, []interface{}:
 		return value.NewValue("array"), nil
-	case func([]*value.Value) (*value.Value, error):
+	case func([]*value.Value) (*value.Value, error), func([]*value.Value) *value.Value,
+		interface{ Call([]*value.Value) *value.Value }:
 		return value.NewValue("function"), nil
 	default:
 		t := reflect.TypeOf(data)
@@ -361,6 +405,10 @@ func calculateSize(data interface{}) uintptr {
 		return 0
 	}
 
+	if obj, ok := data.(*value.Object); ok {
+		data = obj.Fields()
+	}
+
 	v := reflect.ValueOf(data)
 	size := reflect.TypeOf(data).Size()
 
@@ -417,19 +465,19 @@ func StartCPUProfile(args []*value.Value) (*value.Value, error) {
 	
 	f, err := os.Create(filename)
 	if err != nil {
-		return value.NewValue(false), nil
+		return ioError("creating profile file '%s': %v", filename, err), nil
 	}
 
 	err = pprof.StartCPUProfile(f)
 	if err != nil {
 		f.Close()
-		return value.NewValue(false), nil
+		return ioError("starting CPU profile: %v", err), nil
 	}
 
 	// Сохраняем файл для последующего закрытия
 	cpuProfileFile = f
 	
-	return value.NewValue(true), nil
+	return okResult(value.NewValue(nil)), nil
 }
 
 // StopCPUProfile останавливает профилирование CPU
@@ -453,52 +501,17 @@ var cpuProfileFile *os.File
 // InitializeDebugFunctions инициализирует встроенные отладочные функции
 func InitializeDebugFunctions(globalScope *scope.ScopeStack) {
 	// typeOf функция
-	typeOfFunc := func(args []*value.Value) *value.Value {
-		result, err := TypeOf(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("typeOf", value.NewValue(typeOfFunc))
+	globalScope.Set("typeOf", value.NewValue(wrap(TypeOf)))
 	
 	// sizeOf функция
-	sizeOfFunc := func(args []*value.Value) *value.Value {
-		result, err := SizeOf(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("sizeOf", value.NewValue(sizeOfFunc))
+	globalScope.Set("sizeOf", value.NewValue(wrap(SizeOf)))
 	
 	// memStats функция
-	memStatsFunc := func(args []*value.Value) *value.Value {
-		result, err := MemStats(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("memStats", value.NewValue(memStatsFunc))
+	globalScope.Set("memStats", value.NewValue(wrap(MemStats)))
 	
 	// debug функция
-	debugFunc := func(args []*value.Value) *value.Value {
-		result, err := Debug(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("debug", value.NewValue(debugFunc))
+	globalScope.Set("debug", value.NewValue(wrap(Debug)))
 	
 	// trace функция
-	traceFunc := func(args []*value.Value) *value.Value {
-		result, err := Trace(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("trace", value.NewValue(traceFunc))
+	globalScope.Set("trace", value.NewValue(traceBuiltin()))
 }
\ No newline at end of file
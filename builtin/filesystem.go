@@ -10,40 +10,40 @@ import (
 
 // Встроенные функции для работы с файловой системой
 
-// ReadFile читает содержимое файла и возвращает строку
+// ReadFile читает содержимое файла: Ok(string) или Err(IOError)
 func ReadFile(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("readFile() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("readFile() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	filename, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("readFile() requires a string argument (filename)")
+		panic("readFile() requires a string argument (filename)")
 	}
 	
 	// Читаем файл
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error reading file '%s': %v", filename, err))
+		return ioError("reading file '%s': %v", filename, err)
 	}
 	
-	return value.NewValue(string(data))
+	return okResult(value.NewValue(string(data)))
 }
 
-// WriteFile записывает содержимое в файл
+// WriteFile записывает содержимое в файл: Ok(null) или Err(IOError)
 func WriteFile(args []*value.Value) *value.Value {
 	if len(args) != 2 {
-		return value.NewValue(fmt.Sprintf("writeFile() requires exactly 2 arguments, got %d", len(args)))
+		panic(fmt.Sprintf("writeFile() requires exactly 2 arguments, got %d", len(args)))
 	}
 	
 	filename, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("writeFile() first argument must be a string (filename)")
+		panic("writeFile() first argument must be a string (filename)")
 	}
 	
 	content, ok := args[1].Any().(string)
 	if !ok {
-		return value.NewValue("writeFile() second argument must be a string (content)")
+		panic("writeFile() second argument must be a string (content)")
 	}
 	
 	// Создаем директории если нужно
@@ -51,28 +51,28 @@ func WriteFile(args []*value.Value) *value.Value {
 	if dir != "." {
 		err := os.MkdirAll(dir, 0755)
 		if err != nil {
-			return value.NewValue(fmt.Sprintf("Error creating directories for '%s': %v", filename, err))
+			return ioError("creating directories for '%s': %v", filename, err)
 		}
 	}
 	
 	// Записываем файл
 	err := os.WriteFile(filename, []byte(content), 0644)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error writing file '%s': %v", filename, err))
+		return ioError("writing file '%s': %v", filename, err)
 	}
 	
-	return value.NewValue("ok") // Успешная запись
+	return okResult(value.NewValue(nil))
 }
 
 // Exists проверяет существование файла или директории
 func Exists(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("exists() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("exists() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	path, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("exists() requires a string argument (path)")
+		panic("exists() requires a string argument (path)")
 	}
 	
 	_, err := os.Stat(path)
@@ -88,65 +88,65 @@ func Exists(args []*value.Value) *value.Value {
 	return value.NewValue(false)
 }
 
-// Mkdir создает директорию
+// Mkdir создает директорию: Ok(null) или Err(IOError)
 func Mkdir(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("mkdir() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("mkdir() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	dirPath, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("mkdir() requires a string argument (directory path)")
+		panic("mkdir() requires a string argument (directory path)")
 	}
 	
 	// Создаем директорию со всеми родительскими директориями
 	err := os.MkdirAll(dirPath, 0755)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error creating directory '%s': %v", dirPath, err))
+		return ioError("creating directory '%s': %v", dirPath, err)
 	}
 	
-	return value.NewValue("ok") // Успешное создание
+	return okResult(value.NewValue(nil))
 }
 
-// RemoveFile удаляет файл
+// RemoveFile удаляет файл: Ok(null) или Err(IOError)
 func RemoveFile(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("removeFile() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("removeFile() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	filename, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("removeFile() requires a string argument (filename)")
+		panic("removeFile() requires a string argument (filename)")
 	}
 	
 	err := os.Remove(filename)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error removing file '%s': %v", filename, err))
+		return ioError("removing file '%s': %v", filename, err)
 	}
 	
-	return value.NewValue("ok") // Успешное удаление
+	return okResult(value.NewValue(nil))
 }
 
-// CopyFile копирует файл
+// CopyFile копирует файл: Ok(null) или Err(IOError)
 func CopyFile(args []*value.Value) *value.Value {
 	if len(args) != 2 {
-		return value.NewValue(fmt.Sprintf("copyFile() requires exactly 2 arguments, got %d", len(args)))
+		panic(fmt.Sprintf("copyFile() requires exactly 2 arguments, got %d", len(args)))
 	}
 	
 	srcPath, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("copyFile() first argument must be a string (source path)")
+		panic("copyFile() first argument must be a string (source path)")
 	}
 	
 	dstPath, ok := args[1].Any().(string)
 	if !ok {
-		return value.NewValue("copyFile() second argument must be a string (destination path)")
+		panic("copyFile() second argument must be a string (destination path)")
 	}
 	
 	// Открываем источник
 	src, err := os.Open(srcPath)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error opening source file '%s': %v", srcPath, err))
+		return ioError("opening source file '%s': %v", srcPath, err)
 	}
 	defer src.Close()
 	
@@ -155,40 +155,40 @@ func CopyFile(args []*value.Value) *value.Value {
 	if dir != "." {
 		err := os.MkdirAll(dir, 0755)
 		if err != nil {
-			return value.NewValue(fmt.Sprintf("Error creating directories for '%s': %v", dstPath, err))
+			return ioError("creating directories for '%s': %v", dstPath, err)
 		}
 	}
 	
 	// Создаем файл назначения
 	dst, err := os.Create(dstPath)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error creating destination file '%s': %v", dstPath, err))
+		return ioError("creating destination file '%s': %v", dstPath, err)
 	}
 	defer dst.Close()
 	
 	// Копируем содержимое
 	_, err = io.Copy(dst, src)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error copying from '%s' to '%s': %v", srcPath, dstPath, err))
+		return ioError("copying from '%s' to '%s': %v", srcPath, dstPath, err)
 	}
 	
-	return value.NewValue("ok") // Успешное копирование
+	return okResult(value.NewValue(nil))
 }
 
-// ListDir возвращает список файлов в директории
+// ListDir возвращает список файлов в директории: Ok(array) или Err(IOError)
 func ListDir(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("listDir() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("listDir() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	dirPath, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("listDir() requires a string argument (directory path)")
+		panic("listDir() requires a string argument (directory path)")
 	}
 	
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error reading directory '%s': %v", dirPath, err))
+		return ioError("reading directory '%s': %v", dirPath, err)
 	}
 	
 	// Создаем массив имен файлов
@@ -197,18 +197,18 @@ func ListDir(args []*value.Value) *value.Value {
 		files = append(files, entry.Name())
 	}
 	
-	return value.NewValue(files)
+	return okResult(value.NewValue(files))
 }
 
 // IsFile проверяет, является ли путь файлом
 func IsFile(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("isFile() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("isFile() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	path, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("isFile() requires a string argument (path)")
+		panic("isFile() requires a string argument (path)")
 	}
 	
 	info, err := os.Stat(path)
@@ -222,12 +222,12 @@ func IsFile(args []*value.Value) *value.Value {
 // IsDir проверяет, является ли путь директорией
 func IsDir(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("isDir() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("isDir() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	path, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("isDir() requires a string argument (path)")
+		panic("isDir() requires a string argument (path)")
 	}
 	
 	info, err := os.Stat(path)
@@ -238,23 +238,23 @@ func IsDir(args []*value.Value) *value.Value {
 	return value.NewValue(info.IsDir()) // true если это директория
 }
 
-// GetFileSize возвращает размер файла в байтах
+// GetFileSize возвращает размер файла в байтах: Ok(int) или Err(IOError)
 func GetFileSize(args []*value.Value) *value.Value {
 	if len(args) != 1 {
-		return value.NewValue(fmt.Sprintf("getFileSize() requires exactly 1 argument, got %d", len(args)))
+		panic(fmt.Sprintf("getFileSize() requires exactly 1 argument, got %d", len(args)))
 	}
 	
 	filename, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("getFileSize() requires a string argument (filename)")
+		panic("getFileSize() requires a string argument (filename)")
 	}
 	
 	info, err := os.Stat(filename)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error getting file info for '%s': %v", filename, err))
+		return ioError("getting file info for '%s': %v", filename, err)
 	}
 	
-	return value.NewValue(info.Size()) // Размер в байтах
+	return okResult(value.NewValue(info.Size())) // Размер в байтах
 }
 
 // FilesystemFunction представляет функцию файловой системы (аналогично MathFunction)
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+	"foo_lang/ast"
 	"foo_lang/value"
 )
 
@@ -18,10 +20,6 @@ var httpClient = &http.Client{
 	Timeout: 30 * time.Second,
 }
 
-// HTTP сервер экземпляр
-var httpServer *http.Server
-var serverMux *http.ServeMux
-
 // HttpFunction представляет HTTP функцию
 type HttpFunction struct {
 	name string
@@ -36,6 +34,11 @@ func (hf *HttpFunction) Call(args []*value.Value) *value.Value {
 	return hf.fn(args)
 }
 
+// routeHandler - обработчик маршрута httpRoute: *HttpFunction или замыкание foo
+type routeHandler interface {
+	Call(args []*value.Value) *value.Value
+}
+
 func (hf *HttpFunction) String() string {
 	return "builtin function " + hf.name
 }
@@ -44,7 +47,13 @@ func (hf *HttpFunction) Name() string {
 	return hf.name
 }
 
+// InitializeHttpFunctions регистрирует HTTP функции с сервером ast.GlobalRuntime
 func InitializeHttpFunctions(scopeStack ScopeStack) {
+	initializeHttpFunctions(scopeStack, StateOf(ast.GlobalRuntime))
+}
+
+// initializeHttpFunctions регистрирует HTTP функции; сервер и маршруты хранятся в s
+func initializeHttpFunctions(scopeStack ScopeStack, s *State) {
 	// HTTP клиент функции
 	scopeStack.Set("httpGet", value.NewValue(&HttpFunction{
 		name: "httpGet",
@@ -69,22 +78,22 @@ func InitializeHttpFunctions(scopeStack ScopeStack) {
 	// HTTP сервер функции
 	scopeStack.Set("httpCreateServer", value.NewValue(&HttpFunction{
 		name: "httpCreateServer",
-		fn: HttpCreateServer,
+		fn: s.HttpCreateServer,
 	}))
 	
 	scopeStack.Set("httpRoute", value.NewValue(&HttpFunction{
 		name: "httpRoute",
-		fn: HttpRoute,
+		fn: s.HttpRoute,
 	}))
 	
 	scopeStack.Set("httpStartServer", value.NewValue(&HttpFunction{
 		name: "httpStartServer",
-		fn: HttpStartServer,
+		fn: s.HttpStartServer,
 	}))
 	
 	scopeStack.Set("httpStopServer", value.NewValue(&HttpFunction{
 		name: "httpStopServer",
-		fn: HttpStopServer,
+		fn: s.HttpStopServer,
 	}))
 	
 	// Утилиты
@@ -104,28 +113,28 @@ func InitializeHttpFunctions(scopeStack ScopeStack) {
 	}))
 }
 
-// HttpGet выполняет HTTP GET запрос
+// HttpGet выполняет HTTP GET запрос. Возвращает Ok(response) или Err(NetworkError)
 // Использование: httpGet(url, [headers])
 func HttpGet(args []*value.Value) *value.Value {
 	if len(args) < 1 {
-		return value.NewValue("Error: httpGet() requires at least 1 argument (url)")
+		panic("httpGet() requires at least 1 argument (url)")
 	}
 	
 	url, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: first argument must be a string (url)")
+		panic("first argument must be a string (url)")
 	}
 	
 	// Создаем запрос
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to create request: %v", err))
+		return networkError("failed to create request: %v", err)
 	}
 	
 	// Добавляем заголовки если переданы
 	if len(args) >= 2 {
-		if headers, ok := args[1].Any().(map[string]*value.Value); ok {
-			for key, val := range headers {
+		if headers, ok := args[1].Any().(*value.Object); ok {
+			for key, val := range headers.Fields() {
 				if headerValue, ok := val.Any().(string); ok {
 					req.Header.Set(key, headerValue)
 				}
@@ -136,14 +145,14 @@ func HttpGet(args []*value.Value) *value.Value {
 	// Выполняем запрос
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: request failed: %v", err))
+		return networkError("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 	
 	// Читаем тело ответа
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to read response: %v", err))
+		return networkError("failed to read response: %v", err)
 	}
 	
 	// Создаем объект ответа
@@ -154,19 +163,19 @@ func HttpGet(args []*value.Value) *value.Value {
 		"headers":    headersToMap(resp.Header),
 	}
 	
-	return value.NewValue(response)
+	return okResult(value.NewValue(response))
 }
 
-// HttpPost выполняет HTTP POST запрос
+// HttpPost выполняет HTTP POST запрос. Возвращает Ok(response) или Err(NetworkError)
 // Использование: httpPost(url, body, [headers])
 func HttpPost(args []*value.Value) *value.Value {
 	if len(args) < 2 {
-		return value.NewValue("Error: httpPost() requires at least 2 arguments (url, body)")
+		panic("httpPost() requires at least 2 arguments (url, body)")
 	}
 	
 	url, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: first argument must be a string (url)")
+		panic("first argument must be a string (url)")
 	}
 	
 	// Подготавливаем тело запроса
@@ -177,22 +186,22 @@ func HttpPost(args []*value.Value) *value.Value {
 	case string:
 		bodyReader = strings.NewReader(body)
 		contentType = "text/plain"
-	case map[string]*value.Value:
-		// JSON объект
-		jsonData, err := valueMapToJSON(body)
+	case *value.Object:
+		// JSON объект, ключи в порядке объекта
+		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return value.NewValue(fmt.Sprintf("Error: failed to encode JSON: %v", err))
+			return errResult(ast.ValueError, ast.E302_CANNOT_CONVERT, "failed to encode JSON: %v", err)
 		}
 		bodyReader = bytes.NewReader(jsonData)
 		contentType = "application/json"
 	default:
-		return value.NewValue("Error: body must be a string or object")
+		panic("body must be a string or object")
 	}
 	
 	// Создаем запрос
 	req, err := http.NewRequest("POST", url, bodyReader)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to create request: %v", err))
+		return networkError("failed to create request: %v", err)
 	}
 	
 	// Устанавливаем Content-Type
@@ -200,8 +209,8 @@ func HttpPost(args []*value.Value) *value.Value {
 	
 	// Добавляем заголовки если переданы
 	if len(args) >= 3 {
-		if headers, ok := args[2].Any().(map[string]*value.Value); ok {
-			for key, val := range headers {
+		if headers, ok := args[2].Any().(*value.Object); ok {
+			for key, val := range headers.Fields() {
 				if headerValue, ok := val.Any().(string); ok {
 					req.Header.Set(key, headerValue)
 				}
@@ -212,14 +221,14 @@ func HttpPost(args []*value.Value) *value.Value {
 	// Выполняем запрос
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: request failed: %v", err))
+		return networkError("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 	
 	// Читаем тело ответа
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to read response: %v", err))
+		return networkError("failed to read response: %v", err)
 	}
 	
 	// Создаем объект ответа
@@ -230,13 +239,13 @@ func HttpPost(args []*value.Value) *value.Value {
 		"headers":    headersToMap(resp.Header),
 	}
 	
-	return value.NewValue(response)
+	return okResult(value.NewValue(response))
 }
 
 // HttpPut выполняет HTTP PUT запрос
 func HttpPut(args []*value.Value) *value.Value {
 	if len(args) < 2 {
-		return value.NewValue("Error: httpPut() requires at least 2 arguments (url, body)")
+		panic("httpPut() requires at least 2 arguments (url, body)")
 	}
 	
 	return httpMethodWithBody("PUT", args)
@@ -245,24 +254,24 @@ func HttpPut(args []*value.Value) *value.Value {
 // HttpDelete выполняет HTTP DELETE запрос  
 func HttpDelete(args []*value.Value) *value.Value {
 	if len(args) < 1 {
-		return value.NewValue("Error: httpDelete() requires at least 1 argument (url)")
+		panic("httpDelete() requires at least 1 argument (url)")
 	}
 	
 	url, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: first argument must be a string (url)")
+		panic("first argument must be a string (url)")
 	}
 	
 	// Создаем запрос
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to create request: %v", err))
+		return networkError("failed to create request: %v", err)
 	}
 	
 	// Добавляем заголовки если переданы
 	if len(args) >= 2 {
-		if headers, ok := args[1].Any().(map[string]*value.Value); ok {
-			for key, val := range headers {
+		if headers, ok := args[1].Any().(*value.Object); ok {
+			for key, val := range headers.Fields() {
 				if headerValue, ok := val.Any().(string); ok {
 					req.Header.Set(key, headerValue)
 				}
@@ -273,14 +282,14 @@ func HttpDelete(args []*value.Value) *value.Value {
 	// Выполняем запрос
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: request failed: %v", err))
+		return networkError("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 	
 	// Читаем тело ответа
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to read response: %v", err))
+		return networkError("failed to read response: %v", err)
 	}
 	
 	// Создаем объект ответа
@@ -291,14 +300,14 @@ func HttpDelete(args []*value.Value) *value.Value {
 		"headers":    headersToMap(resp.Header),
 	}
 	
-	return value.NewValue(response)
+	return okResult(value.NewValue(response))
 }
 
 // HttpCreateServer создает новый HTTP сервер
-func HttpCreateServer(args []*value.Value) *value.Value {
+func (s *State) HttpCreateServer(args []*value.Value) *value.Value {
 	// Инициализируем сервер если еще не создан
-	if serverMux == nil {
-		serverMux = http.NewServeMux()
+	if s.serverMux == nil {
+		s.serverMux = http.NewServeMux()
 	}
 	
 	return value.NewValue("HTTP server created")
@@ -306,154 +315,203 @@ func HttpCreateServer(args []*value.Value) *value.Value {
 
 // HttpRoute добавляет маршрут к HTTP серверу
 // Использование: httpRoute(method, path, handler)
-func HttpRoute(args []*value.Value) *value.Value {
+func (s *State) HttpRoute(args []*value.Value) *value.Value {
 	if len(args) < 3 {
-		return value.NewValue("Error: httpRoute() requires 3 arguments (method, path, handler)")
+		panic("httpRoute() requires 3 arguments (method, path, handler)")
 	}
 	
 	method, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: first argument must be a string (method)")
+		panic("first argument must be a string (method)")
 	}
 	
 	path, ok := args[1].Any().(string)
 	if !ok {
-		return value.NewValue("Error: second argument must be a string (path)")
+		panic("second argument must be a string (path)")
 	}
 	
 	handler := args[2]
 	
-	if serverMux == nil {
-		serverMux = http.NewServeMux()
+	if s.serverMux == nil {
+		s.serverMux = http.NewServeMux()
 	}
 	
-	// Создаем обработчик
-	serverMux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
-		// Проверяем метод
-		if strings.ToUpper(r.Method) != strings.ToUpper(method) {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		
-		// Читаем тело запроса
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			http.Error(w, "Failed to read request body", http.StatusBadRequest)
-			return
+	s.routesMu.Lock()
+	methods, registered := s.routes[path]
+	if !registered {
+		methods = make(map[string]*value.Value)
+		s.routes[path] = methods
+	}
+	methods[strings.ToUpper(method)] = handler
+	s.routesMu.Unlock()
+	
+	if !registered {
+		s.serverMux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			s.serveRoute(path, w, r)
+		})
+	}
+	
+	return value.NewValue(fmt.Sprintf("Route %s %s registered", method, path))
+}
+
+// serveRoute вызывает обработчик маршрута path для метода запроса
+func (s *State) serveRoute(path string, w http.ResponseWriter, r *http.Request) {
+	s.routesMu.RLock()
+	handler := s.routes[path][strings.ToUpper(r.Method)]
+	s.routesMu.RUnlock()
+	
+	// Проверяем метод
+	if handler == nil {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	// Читаем тело запроса
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	
+	// Создаем объект запроса
+	request := map[string]*value.Value{
+		"method":  value.NewValue(r.Method),
+		"path":    value.NewValue(r.URL.Path),
+		"query":   queryToMap(r.URL.Query()),
+		"headers": headersToMap(r.Header),
+		"body":    value.NewValue(string(body)),
+	}
+	
+	// Вызываем обработчик: встроенная функция или функция foo (замыкание)
+	callable, ok := handler.Any().(routeHandler)
+	if !ok {
+		http.Error(w, "Handler is not a function", http.StatusInternalServerError)
+		return
+	}
+	
+	response := func() *value.Value {
+		s.requestMu.RLock()
+		defer s.requestMu.RUnlock()
+		return callable.Call([]*value.Value{value.NewValue(request)})
+	}()
+	
+	// Обрабатываем ответ
+	if responseObj, ok := response.Any().(*value.Object); ok {
+		// Устанавливаем заголовки (до статуса: после WriteHeader они игнорируются)
+		if headers, exists := responseObj.Get("headers"); exists {
+			if headerMap, ok := headers.Any().(*value.Object); ok {
+				for key, val := range headerMap.Fields() {
+					if headerValue, ok := val.Any().(string); ok {
+						w.Header().Set(key, headerValue)
+					}
+				}
+			}
 		}
 		
-		// Создаем объект запроса
-		request := map[string]*value.Value{
-			"method":  value.NewValue(r.Method),
-			"path":    value.NewValue(r.URL.Path),
-			"query":   queryToMap(r.URL.Query()),
-			"headers": headersToMap(r.Header),
-			"body":    value.NewValue(string(body)),
+		// Устанавливаем статус
+		if status, exists := responseObj.Get("status"); exists {
+			if statusCode, ok := status.Any().(int64); ok {
+				w.WriteHeader(int(statusCode))
+			}
 		}
 		
-		// Вызываем обработчик (это должна быть функция)
-		if callable, ok := handler.Any().(*HttpFunction); ok {
-			response := callable.Call([]*value.Value{value.NewValue(request)})
-			
-			// Обрабатываем ответ
-			if responseObj, ok := response.Any().(map[string]*value.Value); ok {
-				// Устанавливаем статус
-				if status, exists := responseObj["status"]; exists {
-					if statusCode, ok := status.Any().(int64); ok {
-						w.WriteHeader(int(statusCode))
-					}
-				}
-				
-				// Устанавливаем заголовки
-				if headers, exists := responseObj["headers"]; exists {
-					if headerMap, ok := headers.Any().(map[string]*value.Value); ok {
-						for key, val := range headerMap {
-							if headerValue, ok := val.Any().(string); ok {
-								w.Header().Set(key, headerValue)
-							}
-						}
-					}
-				}
-				
-				// Отправляем тело ответа
-				if body, exists := responseObj["body"]; exists {
-					if bodyStr, ok := body.Any().(string); ok {
-						w.Write([]byte(bodyStr))
-					}
-				}
-			} else {
-				// Простой текстовый ответ
-				if responseStr, ok := response.Any().(string); ok {
-					w.Write([]byte(responseStr))
-				}
+		// Отправляем тело ответа
+		if body, exists := responseObj.Get("body"); exists {
+			if bodyStr, ok := body.Any().(string); ok {
+				w.Write([]byte(bodyStr))
 			}
-		} else {
-			http.Error(w, "Handler is not a function", http.StatusInternalServerError)
 		}
-	})
-	
-	return value.NewValue(fmt.Sprintf("Route %s %s registered", method, path))
+	} else {
+		// Простой текстовый ответ
+		if responseStr, ok := response.Any().(string); ok {
+			w.Write([]byte(responseStr))
+		}
+	}
 }
 
-// HttpStartServer запускает HTTP сервер
+// HoldRequests ждет завершения выполняющихся обработчиков маршрутов и не дает
+// начаться новым до вызова resume. Перезагрузка модулей подменяет код под ним
+func (s *State) HoldRequests() (resume func()) {
+	s.requestMu.Lock()
+	return s.requestMu.Unlock
+}
+
+// RebindRoutes заменяет обработчики маршрутов: replace получает текущий
+// обработчик и возвращает новый (функцию из перезагруженного модуля) или тот же
+func (s *State) RebindRoutes(replace func(handler *value.Value) *value.Value) {
+	s.routesMu.Lock()
+	defer s.routesMu.Unlock()
+	
+	for _, methods := range s.routes {
+		for method, handler := range methods {
+			methods[method] = replace(handler)
+		}
+	}
+}
+
+// HttpStartServer запускает HTTP сервер. Возвращает Err(NetworkError), если порт занят
 // Использование: httpStartServer(port)
-func HttpStartServer(args []*value.Value) *value.Value {
+func (s *State) HttpStartServer(args []*value.Value) *value.Value {
 	if len(args) < 1 {
-		return value.NewValue("Error: httpStartServer() requires 1 argument (port)")
+		panic("httpStartServer() requires 1 argument (port)")
 	}
 	
 	port, ok := args[0].Any().(int64)
 	if !ok {
-		return value.NewValue("Error: port must be a number")
+		panic("port must be a number")
 	}
 	
-	if serverMux == nil {
-		return value.NewValue("Error: no server created, call httpCreateServer() first")
+	if s.serverMux == nil {
+		panic("no server created, call httpCreateServer() first")
 	}
 	
-	// Создаем сервер
-	httpServer = &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: serverMux,
+	// Порт занимаем синхронно, чтобы ошибка (порт занят) вернулась в скрипт как Err
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return networkError("failed to start server on port %d: %v", port, err)
 	}
 	
+	// Создаем сервер
+	s.httpServer = &http.Server{Handler: s.serverMux}
+	
 	// Запускаем сервер в горутине
+	server := s.httpServer
 	go func() {
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			fmt.Printf("Server error: %v\n", err)
 		}
 	}()
 	
-	return value.NewValue(fmt.Sprintf("HTTP server started on port %d", port))
+	return okResult(value.NewValue(fmt.Sprintf("HTTP server started on port %d", port)))
 }
 
 // HttpStopServer останавливает HTTP сервер
-func HttpStopServer(args []*value.Value) *value.Value {
-	if httpServer == nil {
-		return value.NewValue("Error: no server running")
+func (s *State) HttpStopServer(args []*value.Value) *value.Value {
+	if s.httpServer == nil {
+		panic("no server running")
 	}
 	
 	// Даем серверу 5 секунд на graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	
-	if err := httpServer.Shutdown(ctx); err != nil {
-		return value.NewValue(fmt.Sprintf("Error stopping server: %v", err))
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return networkError("failed to stop server: %v", err)
 	}
 	
-	httpServer = nil
-	return value.NewValue("HTTP server stopped")
+	s.httpServer = nil
+	return okResult(value.NewValue("HTTP server stopped"))
 }
 
 // HttpSetTimeout устанавливает таймаут для HTTP клиента
 func HttpSetTimeout(args []*value.Value) *value.Value {
 	if len(args) < 1 {
-		return value.NewValue("Error: httpSetTimeout() requires 1 argument (seconds)")
+		panic("httpSetTimeout() requires 1 argument (seconds)")
 	}
 	
 	seconds, ok := args[0].Any().(int64)
 	if !ok {
-		return value.NewValue("Error: timeout must be a number (seconds)")
+		panic("timeout must be a number (seconds)")
 	}
 	
 	httpClient.Timeout = time.Duration(seconds) * time.Second
@@ -463,35 +521,35 @@ func HttpSetTimeout(args []*value.Value) *value.Value {
 // UrlEncode кодирует строку для URL
 func UrlEncode(args []*value.Value) *value.Value {
 	if len(args) < 1 {
-		return value.NewValue("Error: urlEncode() requires 1 argument (string)")
+		panic("urlEncode() requires 1 argument (string)")
 	}
 	
 	str, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: argument must be a string")
+		panic("urlEncode() argument must be a string")
 	}
 	
 	encoded := url.QueryEscape(str)
 	return value.NewValue(encoded)
 }
 
-// UrlDecode декодирует URL строку
+// UrlDecode декодирует URL строку: Ok(string) или Err(ParseError)
 func UrlDecode(args []*value.Value) *value.Value {
 	if len(args) < 1 {
-		return value.NewValue("Error: urlDecode() requires 1 argument (string)")
+		panic("urlDecode() requires 1 argument (string)")
 	}
 	
 	str, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: argument must be a string")
+		panic("urlDecode() argument must be a string")
 	}
 	
 	decoded, err := url.QueryUnescape(str)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to decode URL: %v", err))
+		return parseError("failed to decode URL: %v", err)
 	}
 	
-	return value.NewValue(decoded)
+	return okResult(value.NewValue(decoded))
 }
 
 // Вспомогательные функции
@@ -500,7 +558,7 @@ func UrlDecode(args []*value.Value) *value.Value {
 func httpMethodWithBody(method string, args []*value.Value) *value.Value {
 	url, ok := args[0].Any().(string)
 	if !ok {
-		return value.NewValue("Error: first argument must be a string (url)")
+		panic("first argument must be a string (url)")
 	}
 	
 	// Подготавливаем тело запроса
@@ -511,22 +569,22 @@ func httpMethodWithBody(method string, args []*value.Value) *value.Value {
 	case string:
 		bodyReader = strings.NewReader(body)
 		contentType = "text/plain"
-	case map[string]*value.Value:
-		// JSON объект
-		jsonData, err := valueMapToJSON(body)
+	case *value.Object:
+		// JSON объект, ключи в порядке объекта
+		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return value.NewValue(fmt.Sprintf("Error: failed to encode JSON: %v", err))
+			return errResult(ast.ValueError, ast.E302_CANNOT_CONVERT, "failed to encode JSON: %v", err)
 		}
 		bodyReader = bytes.NewReader(jsonData)
 		contentType = "application/json"
 	default:
-		return value.NewValue("Error: body must be a string or object")
+		panic("body must be a string or object")
 	}
 	
 	// Создаем запрос
 	req, err := http.NewRequest(method, url, bodyReader)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to create request: %v", err))
+		return networkError("failed to create request: %v", err)
 	}
 	
 	// Устанавливаем Content-Type
@@ -534,8 +592,8 @@ func httpMethodWithBody(method string, args []*value.Value) *value.Value {
 	
 	// Добавляем заголовки если переданы
 	if len(args) >= 3 {
-		if headers, ok := args[2].Any().(map[string]*value.Value); ok {
-			for key, val := range headers {
+		if headers, ok := args[2].Any().(*value.Object); ok {
+			for key, val := range headers.Fields() {
 				if headerValue, ok := val.Any().(string); ok {
 					req.Header.Set(key, headerValue)
 				}
@@ -546,14 +604,14 @@ func httpMethodWithBody(method string, args []*value.Value) *value.Value {
 	// Выполняем запрос
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: request failed: %v", err))
+		return networkError("request failed: %v", err)
 	}
 	defer resp.Body.Close()
 	
 	// Читаем тело ответа
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to read response: %v", err))
+		return networkError("failed to read response: %v", err)
 	}
 	
 	// Создаем объект ответа
@@ -564,7 +622,7 @@ func httpMethodWithBody(method string, args []*value.Value) *value.Value {
 		"headers":    headersToMap(resp.Header),
 	}
 	
-	return value.NewValue(response)
+	return okResult(value.NewValue(response))
 }
 
 // headersToMap конвертирует HTTP заголовки в map
@@ -592,15 +650,3 @@ func queryToMap(query url.Values) *value.Value {
 	
 	return value.NewValue(result)
 }
-
-// valueMapToJSON конвертирует map[string]*value.Value в JSON
-func valueMapToJSON(valueMap map[string]*value.Value) ([]byte, error) {
-	// Конвертируем в обычный map для JSON marshalling
-	normalMap := make(map[string]interface{})
-	
-	for key, val := range valueMap {
-		normalMap[key] = val.Any()
-	}
-	
-	return json.Marshal(normalMap)
-}
\ No newline at end of file
@@ -1,7 +1,10 @@
 package builtin
 
 import (
+	"fmt"
 	"math"
+	"math/big"
+	"strings"
 	"foo_lang/value"
 )
 
@@ -211,10 +214,82 @@ func CreateMathFunctions() map[string]*value.Value {
 			return value.NewValue(math.Exp(x))
 		},
 	})
+
+	// bigint(x) - целое произвольной точности из int, строки или целого float/decimal
+	functions["bigint"] = value.NewValue(&MathFunction{
+		name: "bigint",
+		fn: func(args []*value.Value) *value.Value {
+			if len(args) != 1 {
+				panic("bigint() takes exactly 1 argument")
+			}
+			return value.NewValue(toBigInt(args[0]))
+		},
+	})
+
+	// decimal(x) - точное десятичное число из int, bigint, float или строки "19.99"
+	functions["decimal"] = value.NewValue(&MathFunction{
+		name: "decimal",
+		fn: func(args []*value.Value) *value.Value {
+			if len(args) != 1 {
+				panic("decimal() takes exactly 1 argument")
+			}
+			return value.NewValue(toDecimal(args[0]))
+		},
+	})
 	
 	return functions
 }
 
+func toBigInt(arg *value.Value) *big.Int {
+	switch x := arg.Any().(type) {
+	case int64:
+		return big.NewInt(x)
+	case *big.Int:
+		return x
+	case float64:
+		if x == math.Trunc(x) && !math.IsInf(x, 0) {
+			n, _ := big.NewFloat(x).Int(nil)
+			return n
+		}
+	case *value.Decimal:
+		if x.IsInteger() {
+			return x.BigInt()
+		}
+	case string:
+		if n, ok := new(big.Int).SetString(strings.ReplaceAll(strings.TrimSpace(x), "_", ""), 10); ok {
+			return n
+		}
+		panic(fmt.Sprintf("bigint() cannot parse %q", x))
+	default:
+		panic(fmt.Sprintf("bigint() expects a number or string, got %s", value.GetValueTypeName(arg)))
+	}
+	panic(fmt.Sprintf("bigint() expects an integer, got %s", arg.String()))
+}
+
+func toDecimal(arg *value.Value) *value.Decimal {
+	switch x := arg.Any().(type) {
+	case int64:
+		return value.NewDecimalFromInt(big.NewInt(x))
+	case *big.Int:
+		return value.NewDecimalFromInt(x)
+	case *value.Decimal:
+		return x
+	case float64:
+		d, err := value.NewDecimalFromFloat(x)
+		if err != nil {
+			panic("decimal() " + err.Error())
+		}
+		return d
+	case string:
+		d, err := value.ParseDecimal(x)
+		if err != nil {
+			panic("decimal() " + err.Error())
+		}
+		return d
+	}
+	panic(fmt.Sprintf("decimal() expects a number or string, got %s", value.GetValueTypeName(arg)))
+}
+
 // InitializeMathFunctions добавляет математические функции в глобальную область видимости
 func InitializeMathFunctions(scopeStack ScopeStack) {
 	functions := CreateMathFunctions()
@@ -73,7 +73,7 @@ func Exec(args []*value.Value) (*value.Value, error) {
 	return result, nil
 }
 
-// Spawn запускает процесс в фоне и возвращает PID
+// Spawn запускает процесс в фоне. Возвращает Ok(pid) или Err, если процесс не запустился
 func Spawn(args []*value.Value) (*value.Value, error) {
 	if len(args) < 1 {
 		return nil, fmt.Errorf("spawn expects at least 1 argument")
@@ -108,11 +108,11 @@ func Spawn(args []*value.Value) (*value.Value, error) {
 	// Запускаем процесс в фоне
 	err := cmd.Start()
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to spawn process: %v", err)), nil
+		return ioError("failed to spawn process: %v", err), nil
 	}
 
 	// Возвращаем PID процесса
-	return value.NewValue(int64(cmd.Process.Pid)), nil
+	return okResult(value.NewValue(int64(cmd.Process.Pid))), nil
 }
 
 // Kill завершает процесс по PID
@@ -213,7 +213,7 @@ func Exit(args []*value.Value) (*value.Value, error) {
 	return value.NewValue(nil), nil // никогда не выполнится
 }
 
-// GetWorkingDir получает текущую рабочую директорию
+// GetWorkingDir получает текущую рабочую директорию. Возвращает Ok(path) или Err
 func GetWorkingDir(args []*value.Value) (*value.Value, error) {
 	if len(args) != 0 {
 		return nil, fmt.Errorf("getWorkingDir expects 0 arguments")
@@ -221,13 +221,13 @@ func GetWorkingDir(args []*value.Value) (*value.Value, error) {
 
 	dir, err := os.Getwd()
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to get working directory: %v", err)), nil
+		return ioError("failed to get working directory: %v", err), nil
 	}
 	
-	return value.NewValue(dir), nil
+	return okResult(value.NewValue(dir)), nil
 }
 
-// ChangeDir меняет текущую рабочую директорию
+// ChangeDir меняет текущую рабочую директорию. Возвращает Ok(path) или Err
 func ChangeDir(args []*value.Value) (*value.Value, error) {
 	if len(args) != 1 {
 		return nil, fmt.Errorf("changeDir expects 1 argument")
@@ -238,9 +238,11 @@ func ChangeDir(args []*value.Value) (*value.Value, error) {
 	}
 
 	path := args[0].String()
-	err := os.Chdir(path)
-	
-	return value.NewValue(err == nil), nil
+	if err := os.Chdir(path); err != nil {
+		return ioError("failed to change directory to '%s': %v", path, err), nil
+	}
+
+	return okResult(value.NewValue(path)), nil
 }
 
 // GetPid возвращает PID текущего процесса
@@ -252,7 +254,7 @@ func GetPid(args []*value.Value) (*value.Value, error) {
 	return value.NewValue(int64(os.Getpid())), nil
 }
 
-// GetHostname возвращает имя хоста
+// GetHostname возвращает имя хоста: Ok(hostname) или Err
 func GetHostname(args []*value.Value) (*value.Value, error) {
 	if len(args) != 0 {
 		return nil, fmt.Errorf("getHostname expects 0 arguments")
@@ -260,10 +262,10 @@ func GetHostname(args []*value.Value) (*value.Value, error) {
 	
 	hostname, err := os.Hostname()
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: failed to get hostname: %v", err)), nil
+		return ioError("failed to get hostname: %v", err), nil
 	}
 	
-	return value.NewString(hostname), nil
+	return okResult(value.NewString(hostname)), nil
 }
 
 // GetOS возвращает информацию об операционной системе
@@ -287,62 +289,20 @@ func InitializeProcessFunctions(globalScope *scope.ScopeStack) {
 	// Адаптируем функции для совместимости со старым API
 	
 	// getOS функция
-	getOSFunc := func(args []*value.Value) *value.Value {
-		result, err := GetOS(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getOS", value.NewValue(getOSFunc))
+	globalScope.Set("getOS", value.NewValue(wrap(GetOS)))
 	
 	// getPid функция  
-	getPidFunc := func(args []*value.Value) *value.Value {
-		result, err := GetPid(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getPid", value.NewValue(getPidFunc))
+	globalScope.Set("getPid", value.NewValue(wrap(GetPid)))
 	
 	// getEnv функция
-	getEnvFunc := func(args []*value.Value) *value.Value {
-		result, err := GetEnv(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getEnv", value.NewValue(getEnvFunc))
+	globalScope.Set("getEnv", value.NewValue(wrap(GetEnv)))
 	
 	// exec функция
-	execFunc := func(args []*value.Value) *value.Value {
-		result, err := Exec(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("exec", value.NewValue(execFunc))
+	globalScope.Set("exec", value.NewValue(wrap(Exec)))
 	
 	// getWorkingDir функция
-	getWorkingDirFunc := func(args []*value.Value) *value.Value {
-		result, err := GetWorkingDir(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("getWorkingDir", value.NewValue(getWorkingDirFunc))
+	globalScope.Set("getWorkingDir", value.NewValue(wrap(GetWorkingDir)))
 	
 	// spawn функция
-	spawnFunc := func(args []*value.Value) *value.Value {
-		result, err := Spawn(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("spawn", value.NewValue(spawnFunc))
+	globalScope.Set("spawn", value.NewValue(wrap(Spawn)))
 }
\ No newline at end of file
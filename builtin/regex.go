@@ -1,7 +1,6 @@
 package builtin
 
 import (
-	"fmt"
 	"foo_lang/scope"
 	"foo_lang/value"
 	"regexp"
@@ -16,69 +15,69 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexMatch - проверяет соответствие строки регулярному выражению
 	regexMatchFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: regexMatch() requires 2 arguments (pattern, string)")
+			panic("regexMatch() requires 2 arguments (pattern, string)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexMatch() first argument must be string (pattern)")
+			panic("regexMatch() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexMatch() second argument must be string")
+			panic("regexMatch() second argument must be string")
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		matched := regex.MatchString(text)
-		return value.NewBool(matched)
+		return okResult(value.NewBool(matched))
 	}
 	globalScope.Set("regexMatch", value.NewValue(regexMatchFunc))
 
 	// regexFind - находит первое совпадение регулярного выражения
 	regexFindFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: regexFind() requires 2 arguments (pattern, string)")
+			panic("regexFind() requires 2 arguments (pattern, string)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexFind() first argument must be string (pattern)")
+			panic("regexFind() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexFind() second argument must be string")
+			panic("regexFind() second argument must be string")
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		result := regex.FindString(text)
-		return value.NewString(result)
+		return okResult(value.NewString(result))
 	}
 	globalScope.Set("regexFind", value.NewValue(regexFindFunc))
 
 	// regexFindAll - находит все совпадения регулярного выражения
 	regexFindAllFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 2 || len(args) > 3 {
-			return value.NewString("Error: regexFindAll() requires 2-3 arguments (pattern, string, [limit])")
+			panic("regexFindAll() requires 2-3 arguments (pattern, string, [limit])")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexFindAll() first argument must be string (pattern)")
+			panic("regexFindAll() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexFindAll() second argument must be string")
+			panic("regexFindAll() second argument must be string")
 		}
 
 		// Лимит совпадений (по умолчанию -1 = все)
@@ -89,13 +88,13 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 			} else if limitVal, ok := args[2].Any().(float64); ok {
 				limit = int(limitVal)
 			} else {
-				return value.NewString("Error: regexFindAll() third argument must be numeric (limit)")
+				panic("regexFindAll() third argument must be numeric (limit)")
 			}
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		matches := regex.FindAllString(text, limit)
@@ -106,7 +105,7 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 			arrayValues[i] = value.NewString(match)
 		}
 		
-		return value.NewArray(arrayValues)
+		return okResult(value.NewArray(arrayValues))
 	}
 	globalScope.Set("regexFindAll", value.NewValue(regexFindAllFunc))
 
@@ -115,27 +114,27 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexReplace - заменяет первое совпадение регулярного выражения
 	regexReplaceFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 3 {
-			return value.NewString("Error: regexReplace() requires 3 arguments (pattern, string, replacement)")
+			panic("regexReplace() requires 3 arguments (pattern, string, replacement)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexReplace() first argument must be string (pattern)")
+			panic("regexReplace() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexReplace() second argument must be string")
+			panic("regexReplace() second argument must be string")
 		}
 
 		replacement, ok := args[2].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexReplace() third argument must be string (replacement)")
+			panic("regexReplace() third argument must be string (replacement)")
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		// Заменяем только первое совпадение
@@ -146,42 +145,42 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 		// Но на самом деле нужно заменить только первое
 		firstMatch := regex.FindStringIndex(text)
 		if firstMatch == nil {
-			return value.NewString(text) // Нет совпадений
+			return okResult(value.NewString(text)) // Нет совпадений
 		}
 
 		result = text[:firstMatch[0]] + replacement + text[firstMatch[1]:]
-		return value.NewString(result)
+		return okResult(value.NewString(result))
 	}
 	globalScope.Set("regexReplace", value.NewValue(regexReplaceFunc))
 
 	// regexReplaceAll - заменяет все совпадения регулярного выражения
 	regexReplaceAllFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 3 {
-			return value.NewString("Error: regexReplaceAll() requires 3 arguments (pattern, string, replacement)")
+			panic("regexReplaceAll() requires 3 arguments (pattern, string, replacement)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexReplaceAll() first argument must be string (pattern)")
+			panic("regexReplaceAll() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexReplaceAll() second argument must be string")
+			panic("regexReplaceAll() second argument must be string")
 		}
 
 		replacement, ok := args[2].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexReplaceAll() third argument must be string (replacement)")
+			panic("regexReplaceAll() third argument must be string (replacement)")
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		result := regex.ReplaceAllString(text, replacement)
-		return value.NewString(result)
+		return okResult(value.NewString(result))
 	}
 	globalScope.Set("regexReplaceAll", value.NewValue(regexReplaceAllFunc))
 
@@ -190,17 +189,17 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexSplit - разделяет строку по регулярному выражению
 	regexSplitFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 2 || len(args) > 3 {
-			return value.NewString("Error: regexSplit() requires 2-3 arguments (pattern, string, [limit])")
+			panic("regexSplit() requires 2-3 arguments (pattern, string, [limit])")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexSplit() first argument must be string (pattern)")
+			panic("regexSplit() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexSplit() second argument must be string")
+			panic("regexSplit() second argument must be string")
 		}
 
 		// Лимит частей (по умолчанию -1 = все)
@@ -211,13 +210,13 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 			} else if limitVal, ok := args[2].Any().(float64); ok {
 				limit = int(limitVal)
 			} else {
-				return value.NewString("Error: regexSplit() third argument must be numeric (limit)")
+				panic("regexSplit() third argument must be numeric (limit)")
 			}
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		parts := regex.Split(text, limit)
@@ -228,7 +227,7 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 			arrayValues[i] = value.NewString(part)
 		}
 		
-		return value.NewArray(arrayValues)
+		return okResult(value.NewArray(arrayValues))
 	}
 	globalScope.Set("regexSplit", value.NewValue(regexSplitFunc))
 
@@ -237,28 +236,28 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexGroups - извлекает группы захвата из первого совпадения
 	regexGroupsFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: regexGroups() requires 2 arguments (pattern, string)")
+			panic("regexGroups() requires 2 arguments (pattern, string)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexGroups() first argument must be string (pattern)")
+			panic("regexGroups() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexGroups() second argument must be string")
+			panic("regexGroups() second argument must be string")
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		matches := regex.FindStringSubmatch(text)
 		if matches == nil {
 			// Нет совпадений - возвращаем пустой массив
-			return value.NewArray([]*value.Value{})
+			return okResult(value.NewArray([]*value.Value{}))
 		}
 
 		// Преобразуем группы в массив Value
@@ -267,7 +266,7 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 			arrayValues[i] = value.NewString(match)
 		}
 		
-		return value.NewArray(arrayValues)
+		return okResult(value.NewArray(arrayValues))
 	}
 	globalScope.Set("regexGroups", value.NewValue(regexGroupsFunc))
 
@@ -276,12 +275,12 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexValid - проверяет валидность регулярного выражения
 	regexValidFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: regexValid() requires 1 argument (pattern)")
+			panic("regexValid() requires 1 argument (pattern)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexValid() argument must be string (pattern)")
+			panic("regexValid() argument must be string (pattern)")
 		}
 
 		_, err := regexp.Compile(pattern)
@@ -292,12 +291,12 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexEscape - экранирует специальные символы в строке
 	regexEscapeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: regexEscape() requires 1 argument (string)")
+			panic("regexEscape() requires 1 argument (string)")
 		}
 
 		text, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexEscape() argument must be string")
+			panic("regexEscape() argument must be string")
 		}
 
 		escaped := regexp.QuoteMeta(text)
@@ -310,26 +309,26 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// regexCount - подсчитывает количество совпадений
 	regexCountFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: regexCount() requires 2 arguments (pattern, string)")
+			panic("regexCount() requires 2 arguments (pattern, string)")
 		}
 
 		pattern, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexCount() first argument must be string (pattern)")
+			panic("regexCount() first argument must be string (pattern)")
 		}
 
 		text, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: regexCount() second argument must be string")
+			panic("regexCount() second argument must be string")
 		}
 
 		regex, err := regexp.Compile(pattern)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error compiling regex pattern: %v", err))
+			return patternError(pattern, err)
 		}
 
 		matches := regex.FindAllString(text, -1)
-		return value.NewInt64(int64(len(matches)))
+		return okResult(value.NewInt64(int64(len(matches))))
 	}
 	globalScope.Set("regexCount", value.NewValue(regexCountFunc))
 
@@ -340,17 +339,17 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 	// stringSplit - простое разделение строки по подстроке
 	stringSplitFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 2 || len(args) > 3 {
-			return value.NewString("Error: stringSplit() requires 2-3 arguments (string, separator, [limit])")
+			panic("stringSplit() requires 2-3 arguments (string, separator, [limit])")
 		}
 
 		text, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: stringSplit() first argument must be string")
+			panic("stringSplit() first argument must be string")
 		}
 
 		separator, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: stringSplit() second argument must be string (separator)")
+			panic("stringSplit() second argument must be string (separator)")
 		}
 
 		limit := -1
@@ -360,7 +359,7 @@ func InitializeRegexFunctions(globalScope *scope.ScopeStack) {
 			} else if limitVal, ok := args[2].Any().(float64); ok {
 				limit = int(limitVal)
 			} else {
-				return value.NewString("Error: stringSplit() third argument must be numeric (limit)")
+				panic("stringSplit() third argument must be numeric (limit)")
 			}
 		}
 
@@ -0,0 +1,56 @@
+package builtin
+
+import "foo_lang/value"
+
+// Reattach выполняет fn - повторное выполнение модуля при перезагрузке.
+// Пока fn работает, newMutex("name") и другие конструкторы с именем уже
+// существующего примитива этого интерпретатора возвращают его, а не завершаются ошибкой
+func (s *State) Reattach(fn func()) {
+	s.reattaching.Store(true)
+	defer s.reattaching.Store(false)
+	fn()
+}
+
+// KeepOnReload сообщает, что при перезагрузке модуля переменная должна сохранить
+// старое значение old вместо нового new: оба значения - каналы или оба -
+// примитивы синхронизации одного вида. Так ожидающие горутины и новый код
+// работают с одним каналом и одним мьютексом
+func (s *State) KeepOnReload(old, new *value.Value) bool {
+	if _, ok := old.Any().(*value.Channel); ok {
+		_, ok = new.Any().(*value.Channel)
+		return ok
+	}
+
+	oldName, ok := old.Any().(string)
+	if !ok {
+		return false
+	}
+	newName, ok := new.Any().(string)
+	if !ok {
+		return false
+	}
+	kind := s.syncKind(oldName)
+	return kind != "" && kind == s.syncKind(newName)
+}
+
+// syncKind возвращает вид примитива синхронизации с именем name или ""
+func (s *State) syncKind(name string) string {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	switch {
+	case s.mutexes[name] != nil:
+		return "mutex"
+	case s.rwMutexes[name] != nil:
+		return "rwmutex"
+	case s.waitGroups[name] != nil:
+		return "waitgroup"
+	case s.semaphores[name] != nil:
+		return "semaphore"
+	case s.atomicInts[name] != nil:
+		return "atomic"
+	case s.barriers[name] != nil:
+		return "barrier"
+	}
+	return ""
+}
@@ -1,17 +1,67 @@
 package builtin
 
 import (
+	"fmt"
 	"foo_lang/ast"
 	"foo_lang/scope"
 	"foo_lang/value"
 )
 
+// Контракт встроенных функций:
+//   - ошибка программиста (неверное число или тип аргументов) - panic;
+//   - восстанавливаемая ошибка (ввод-вывод, сеть, разбор данных, компиляция regex) -
+//     Err(ErrorInfo), который скрипт проверяет через isErr() или поднимает оператором ?;
+//   - функция, которая может так завершиться, и при успехе возвращает Result: Ok(value).
+
+// okResult оборачивает успешный результат функции в Ok
+func okResult(v *value.Value) *value.Value {
+	return value.NewValue(ast.NewResultOk(v))
+}
+
+// errResult создает Err(ErrorInfo) для восстанавливаемой ошибки
+func errResult(errType, code, format string, args ...interface{}) *value.Value {
+	info := ast.NewErrorInfo(errType, fmt.Sprintf(format, args...), code)
+	return value.NewValue(ast.NewResultErr(value.NewValue(info)))
+}
+
+// ioError - ошибка файловой системы, процессов и стандартного ввода
+func ioError(format string, args ...interface{}) *value.Value {
+	return errResult(ast.IOError, ast.E501_IO_FAILURE, format, args...)
+}
+
+// networkError - ошибка HTTP запроса или сервера
+func networkError(format string, args ...interface{}) *value.Value {
+	return errResult(ast.NetworkError, ast.E502_NETWORK_FAILURE, format, args...)
+}
+
+// parseError - входные данные не разбираются (JSON, дата, число, base64 ...)
+func parseError(format string, args ...interface{}) *value.Value {
+	return errResult(ast.ParseError, ast.E503_PARSE_FAILURE, format, args...)
+}
+
+// patternError - регулярное выражение не компилируется
+func patternError(pattern string, err error) *value.Value {
+	return errResult(ast.ValueError, ast.E504_INVALID_PATTERN, "invalid regex pattern '%s': %v", pattern, err)
+}
+
+// wrap адаптирует функцию вида (value, error) ко встроенной функции foo.
+// Ее error - ошибка программиста (неверные аргументы) и поднимается паникой
+func wrap(fn func([]*value.Value) (*value.Value, error)) func([]*value.Value) *value.Value {
+	return func(args []*value.Value) *value.Value {
+		result, err := fn(args)
+		if err != nil {
+			panic(err.Error())
+		}
+		return result
+	}
+}
+
 // InitializeResultFunctions инициализирует Result функции Ok и Err
 func InitializeResultFunctions(globalScope *scope.ScopeStack) {
 	// Ok функция - создает Ok(value)
 	okFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewValue("Error: Ok() expects exactly 1 argument")
+			panic("Ok() expects exactly 1 argument")
 		}
 		
 		// Конвертируем value.Value в ast.Value 
@@ -26,7 +76,7 @@ func InitializeResultFunctions(globalScope *scope.ScopeStack) {
 	// Err функция - создает Err(error)
 	errFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewValue("Error: Err() expects exactly 1 argument")
+			panic("Err() expects exactly 1 argument")
 		}
 		
 		// Конвертируем value.Value в ast.Value
@@ -46,5 +96,6 @@ func InitializeResultMethods() {
 	// - isOk()
 	// - isErr() 
 	// - unwrap()
+	// - unwrapErr()
 	// - unwrapOr(default)
 }
\ No newline at end of file
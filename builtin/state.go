@@ -0,0 +1,60 @@
+package builtin
+
+import (
+	"foo_lang/ast"
+	"foo_lang/value"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// State - состояние встроенных функций одного интерпретатора: именованные
+// примитивы синхронизации, HTTP-сервер и таблица маршрутов. Хранится в
+// ast.Runtime (см. StateOf), поэтому newMutex("db") или httpRoute в одном
+// интерпретаторе не видны другому
+type State struct {
+	syncMu       sync.Mutex
+	mutexes      map[string]*sync.Mutex
+	rwMutexes    map[string]*sync.RWMutex
+	waitGroups   map[string]*sync.WaitGroup
+	semaphores   map[string]chan struct{}
+	atomicInts   map[string]*int64
+	conditions   map[string]*sync.Cond
+	barriers     map[string]*barrier
+	mutexCounter int
+
+	// Таблица маршрутов: путь -> метод -> обработчик. Путь регистрируется в
+	// serverMux один раз, повторный httpRoute заменяет обработчик
+	routesMu   sync.RWMutex
+	routes     map[string]map[string]*value.Value
+	serverMux  *http.ServeMux
+	httpServer *http.Server
+
+	// requestMu: обработчики маршрутов выполняются под RLock, подмена кода
+	// модулей при перезагрузке (HoldRequests) - под Lock
+	requestMu sync.RWMutex
+
+	// reattaching - модуль интерпретатора выполняется повторно при горячей перезагрузке (--watch)
+	reattaching atomic.Bool
+}
+
+func newState() *State {
+	return &State{
+		mutexes:    make(map[string]*sync.Mutex),
+		rwMutexes:  make(map[string]*sync.RWMutex),
+		waitGroups: make(map[string]*sync.WaitGroup),
+		semaphores: make(map[string]chan struct{}),
+		atomicInts: make(map[string]*int64),
+		conditions: make(map[string]*sync.Cond),
+		barriers:   make(map[string]*barrier),
+		routes:     make(map[string]map[string]*value.Value),
+	}
+}
+
+// stateKey - ключ State в ast.Runtime.Local
+type stateKey struct{}
+
+// StateOf возвращает состояние встроенных функций интерпретатора с Runtime rt
+func StateOf(rt *ast.Runtime) *State {
+	return rt.Local(stateKey{}, func() any { return newState() }).(*State)
+}
@@ -12,7 +12,7 @@ import (
 
 var stdinReader = bufio.NewReader(os.Stdin)
 
-// ReadLine читает строку из stdin
+// ReadLine читает строку из stdin. Возвращает Ok(line) или Err при ошибке чтения (EOF)
 func ReadLine(args []*value.Value) (*value.Value, error) {
 	if len(args) != 0 {
 		return nil, fmt.Errorf("readLine expects 0 arguments, got %d", len(args))
@@ -20,17 +20,17 @@ func ReadLine(args []*value.Value) (*value.Value, error) {
 
 	line, err := stdinReader.ReadString('\n')
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: reading line: %v", err)), nil
+		return ioError("reading line: %v", err), nil
 	}
 
 	// Удаляем символ новой строки
 	line = strings.TrimSuffix(line, "\n")
 	line = strings.TrimSuffix(line, "\r")
 
-	return value.NewValue(line), nil
+	return okResult(value.NewValue(line)), nil
 }
 
-// Input читает строку из stdin с опциональным prompt
+// Input читает строку из stdin с опциональным prompt. Возвращает Ok(line) или Err
 func Input(args []*value.Value) (*value.Value, error) {
 	prompt := ""
 	if len(args) == 1 {
@@ -49,17 +49,17 @@ func Input(args []*value.Value) (*value.Value, error) {
 
 	line, err := stdinReader.ReadString('\n')
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: reading input: %v", err)), nil
+		return ioError("reading input: %v", err), nil
 	}
 
 	// Удаляем символ новой строки
 	line = strings.TrimSuffix(line, "\n")
 	line = strings.TrimSuffix(line, "\r")
 
-	return value.NewValue(line), nil
+	return okResult(value.NewValue(line)), nil
 }
 
-// InputNumber читает число из stdin
+// InputNumber читает число из stdin. Возвращает Ok(number) или Err, если ввод не число
 func InputNumber(args []*value.Value) (*value.Value, error) {
 	prompt := ""
 	if len(args) == 1 {
@@ -78,7 +78,7 @@ func InputNumber(args []*value.Value) (*value.Value, error) {
 
 	line, err := stdinReader.ReadString('\n')
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: reading input: %v", err)), nil
+		return ioError("reading input: %v", err), nil
 	}
 
 	// Удаляем символ новой строки
@@ -88,17 +88,17 @@ func InputNumber(args []*value.Value) (*value.Value, error) {
 	if floatVal, err := strconv.ParseFloat(line, 64); err == nil {
 		// Проверяем, является ли это целым числом
 		if floatVal == float64(int64(floatVal)) {
-			return value.NewValue(int64(floatVal)), nil
+			return okResult(value.NewValue(int64(floatVal))), nil
 		}
-		return value.NewValue(floatVal), nil
+		return okResult(value.NewValue(floatVal)), nil
 	}
 
 	// Пробуем парсить как int
 	if intVal, err := strconv.ParseInt(line, 10, 64); err == nil {
-		return value.NewValue(intVal), nil
+		return okResult(value.NewValue(intVal)), nil
 	}
 
-	return value.NewValue(fmt.Sprintf("Error: invalid number: %s", line)), nil
+	return parseError("invalid number: %s", line), nil
 }
 
 // Printf форматированный вывод в stdout
@@ -137,7 +137,7 @@ func Printf(args []*value.Value) (*value.Value, error) {
 	return value.NewValue(nil), nil
 }
 
-// GetChar читает один символ из stdin
+// GetChar читает один символ из stdin. Возвращает Ok(char) или Err при ошибке чтения
 func GetChar(args []*value.Value) (*value.Value, error) {
 	if len(args) != 0 {
 		return nil, fmt.Errorf("getChar expects 0 arguments, got %d", len(args))
@@ -145,10 +145,10 @@ func GetChar(args []*value.Value) (*value.Value, error) {
 
 	char, err := stdinReader.ReadByte()
 	if err != nil {
-		return value.NewValue(fmt.Sprintf("Error: reading char: %v", err)), nil
+		return ioError("reading char: %v", err), nil
 	}
 
-	return value.NewString(string(char)), nil
+	return okResult(value.NewString(string(char))), nil
 }
 
 // PutChar выводит один символ в stdout
@@ -236,42 +236,14 @@ func Write(args []*value.Value) (*value.Value, error) {
 // InitializeStdioFunctions инициализирует встроенные STDIO функции
 func InitializeStdioFunctions(globalScope *scope.ScopeStack) {
 	// printf функция (адаптированная)
-	printfFunc := func(args []*value.Value) *value.Value {
-		result, err := Printf(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("printf", value.NewValue(printfFunc))
+	globalScope.Set("printf", value.NewValue(wrap(Printf)))
 	
 	// writeLn функция
-	writeLnFunc := func(args []*value.Value) *value.Value {
-		result, err := WriteLn(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("writeLn", value.NewValue(writeLnFunc))
+	globalScope.Set("writeLn", value.NewValue(wrap(WriteLn)))
 	
 	// input функция
-	inputFunc := func(args []*value.Value) *value.Value {
-		result, err := Input(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("input", value.NewValue(inputFunc))
+	globalScope.Set("input", value.NewValue(wrap(Input)))
 	
 	// readLine функция
-	readLineFunc := func(args []*value.Value) *value.Value {
-		result, err := ReadLine(args)
-		if err != nil {
-			return value.NewValue("Error: " + err.Error())
-		}
-		return result
-	}
-	globalScope.Set("readLine", value.NewValue(readLineFunc))
+	globalScope.Set("readLine", value.NewValue(wrap(ReadLine)))
 }
\ No newline at end of file
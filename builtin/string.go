@@ -1,8 +1,12 @@
 package builtin
 
 import (
+	"encoding/json"
 	"fmt"
 	"foo_lang/value"
+	"io"
+	"math/big"
+	"strings"
 )
 
 // StringFunction представляет строковую функцию
@@ -150,7 +154,7 @@ func GetBuiltinStringFunctions() map[string]*value.Value {
 		},
 	})
 
-	// jsonParse - парсинг JSON строк
+	// jsonParse - разбирает JSON в значения foo. Возвращает Ok(value) или Err(ParseError)
 	functions["jsonParse"] = value.NewValue(&StringFunction{
 		name: "jsonParse",
 		fn: func(args []*value.Value) *value.Value {
@@ -158,46 +162,23 @@ func GetBuiltinStringFunctions() map[string]*value.Value {
 				panic(fmt.Sprintf("jsonParse() expects 1 argument, got %d", len(args)))
 			}
 
-			jsonStr := args[0].String()
-			
-			// null
-			if jsonStr == "null" {
-				return value.NewValue(nil)
-			}
-			
-			// boolean
-			if jsonStr == "true" {
-				return value.NewValue(true)
+			text, ok := args[0].Any().(string)
+			if !ok {
+				panic("jsonParse() argument must be a string")
 			}
-			if jsonStr == "false" {
-				return value.NewValue(false)
-			}
-			
-			// Числа - простая проверка
-			if jsonStr == "0" { return value.NewValue(int64(0)) }
-			if jsonStr == "1" { return value.NewValue(int64(1)) }
-			if jsonStr == "2" { return value.NewValue(int64(2)) }
-			if jsonStr == "42" { return value.NewValue(int64(42)) }
-			if jsonStr == "123" { return value.NewValue(int64(123)) }
-			if jsonStr == "-1" { return value.NewValue(int64(-1)) }
-			if jsonStr == "3.14" { return value.NewValue(float64(3.14)) }
-			if jsonStr == "2.5" { return value.NewValue(float64(2.5)) }
-			
-			// Строки в кавычках
-			if len(jsonStr) >= 2 && jsonStr[0] == '"' && jsonStr[len(jsonStr)-1] == '"' {
-				// Убираем кавычки
-				return value.NewValue(jsonStr[1:len(jsonStr)-1])
-			}
-			
-			// Объекты и массивы (упрощенная версия)
-			if len(jsonStr) > 0 && jsonStr[0] == '{' {
-				return value.NewValue("PARSED_OBJECT:" + jsonStr)
+
+			decoder := json.NewDecoder(strings.NewReader(text))
+			decoder.UseNumber()
+
+			data, err := decodeJSON(decoder)
+			if err != nil {
+				return parseError("invalid JSON: %v", err)
 			}
-			if len(jsonStr) > 0 && jsonStr[0] == '[' {
-				return value.NewValue("PARSED_ARRAY:" + jsonStr)
+			if _, err := decoder.Token(); err != io.EOF {
+				return parseError("invalid JSON: unexpected data after value")
 			}
-			
-			return value.NewValue("UNKNOWN:" + jsonStr)
+
+			return okResult(value.NewValue(data))
 		},
 	})
 
@@ -240,6 +221,17 @@ func GetBuiltinStringFunctions() map[string]*value.Value {
 				return value.NewValue(fmt.Sprintf("\"%s\"", s))
 			}
 			
+			// Объекты (ключи в порядке объекта), массивы, кортежи, множества и
+			// большие числа (без потери точности)
+			switch val.Any().(type) {
+			case *value.Object, []any, *value.Tuple, *value.Set, *big.Int, *value.Decimal:
+				data, err := json.Marshal(val.Any())
+				if err != nil {
+					panic(fmt.Sprintf("jsonStringify() cannot encode value: %v", err))
+				}
+				return value.NewValue(string(data))
+			}
+
 			// По умолчанию - конвертируем в строку
 			return value.NewValue(fmt.Sprintf("\"%s\"", val.String()))
 		},
@@ -258,3 +250,50 @@ func InitializeStringFunctions(scopeStack interface {
 	}
 }
 
+// decodeJSON читает из decoder одно JSON-значение: объект становится объектом
+// foo с тем же порядком ключей, массив - массивом, целое число - int, остальные числа - float
+func decodeJSON(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '{' {
+			object := value.NewObject()
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				field, err := decodeJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+				object.Set(key.(string), value.NewValue(field))
+			}
+			_, err := decoder.Token() // }
+			return object, err
+		}
+
+		items := make([]any, 0)
+		for decoder.More() {
+			item, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := decoder.Token() // ]
+		return items, err
+	case json.Number:
+		if n, err := token.Int64(); err == nil {
+			return n, nil
+		}
+		return token.Float64()
+	default:
+		// string, bool и nil (null)
+		return token, nil
+	}
+}
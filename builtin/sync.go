@@ -2,6 +2,7 @@ package builtin
 
 import (
 	"fmt"
+	"foo_lang/ast"
 	"foo_lang/scope"
 	"foo_lang/value"
 	"sync"
@@ -9,19 +10,6 @@ import (
 	"time"
 )
 
-// Глобальные карты для хранения примитивов синхронизации
-var (
-	mutexes      = make(map[string]*sync.Mutex)
-	rwMutexes    = make(map[string]*sync.RWMutex)
-	waitGroups   = make(map[string]*sync.WaitGroup)
-	semaphores   = make(map[string]chan struct{})
-	atomicInts   = make(map[string]*int64)
-	conditions   = make(map[string]*sync.Cond)
-	barriers     = make(map[string]*barrier)
-	syncMapsMu   sync.Mutex
-	mutexCounter int
-)
-
 // barrier реализует барьер синхронизации
 type barrier struct {
 	n       int
@@ -58,8 +46,25 @@ func (b *barrier) wait() error {
 	return nil
 }
 
+// existing обрабатывает повторное создание примитива с именем name; вызывается
+// под syncMu. При перезагрузке модуля (Reattach) возвращает существующий
+// примитив, иначе это ошибка
+func (s *State) existing(kind, name string) *value.Value {
+	s.syncMu.Unlock()
+	if s.reattaching.Load() {
+		return value.NewString(name)
+	}
+	panic(fmt.Sprintf("%s '%s' already exists", kind, name))
+}
+
 // InitializeSyncFunctions инициализирует встроенные функции синхронизации
+// с примитивами ast.GlobalRuntime
 func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
+	initializeSyncFunctions(globalScope, StateOf(ast.GlobalRuntime))
+}
+
+// initializeSyncFunctions регистрирует функции синхронизации над примитивами s
+func initializeSyncFunctions(globalScope *scope.ScopeStack, s *State) {
 
 	// ============ МЬЮТЕКСЫ ============
 
@@ -70,27 +75,26 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if n, ok := args[0].Any().(string); ok {
 				name = n
 			} else {
-				return value.NewString("Error: newMutex() optional argument must be string (name)")
+				panic("newMutex() optional argument must be string (name)")
 			}
 		} else if len(args) > 1 {
-			return value.NewString("Error: newMutex() requires 0-1 arguments ([name])")
+			panic("newMutex() requires 0-1 arguments ([name])")
 		}
 		
 		// Генерируем имя если не предоставлено
 		if name == "" {
-			syncMapsMu.Lock()
-			mutexCounter++
-			name = fmt.Sprintf("mutex_%d", mutexCounter)
-			syncMapsMu.Unlock()
+			s.syncMu.Lock()
+			s.mutexCounter++
+			name = fmt.Sprintf("mutex_%d", s.mutexCounter)
+			s.syncMu.Unlock()
 		}
 		
-		syncMapsMu.Lock()
-		if _, exists := mutexes[name]; exists {
-			syncMapsMu.Unlock()
-			return value.NewString(fmt.Sprintf("Error: mutex '%s' already exists", name))
+		s.syncMu.Lock()
+		if _, exists := s.mutexes[name]; exists {
+			return s.existing("mutex", name)
 		}
-		mutexes[name] = &sync.Mutex{}
-		syncMapsMu.Unlock()
+		s.mutexes[name] = &sync.Mutex{}
+		s.syncMu.Unlock()
 		
 		return value.NewString(name)
 	}
@@ -99,20 +103,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// mutexLock - блокирует мьютекс
 	mutexLockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: mutexLock() requires 1 argument (name)")
+			panic("mutexLock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: mutexLock() argument must be string (name)")
+			panic("mutexLock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := mutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.mutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: mutex '%s' does not exist", name))
+			panic(fmt.Sprintf("mutex '%s' does not exist", name))
 		}
 		
 		mutex.Lock()
@@ -123,20 +127,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// mutexUnlock - разблокирует мьютекс
 	mutexUnlockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: mutexUnlock() requires 1 argument (name)")
+			panic("mutexUnlock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: mutexUnlock() argument must be string (name)")
+			panic("mutexUnlock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := mutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.mutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: mutex '%s' does not exist", name))
+			panic(fmt.Sprintf("mutex '%s' does not exist", name))
 		}
 		
 		mutex.Unlock()
@@ -147,20 +151,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// mutexTryLock - пытается заблокировать мьютекс без ожидания
 	mutexTryLockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: mutexTryLock() requires 1 argument (name)")
+			panic("mutexTryLock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: mutexTryLock() argument must be string (name)")
+			panic("mutexTryLock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := mutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.mutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: mutex '%s' does not exist", name))
+			panic(fmt.Sprintf("mutex '%s' does not exist", name))
 		}
 		
 		// Go мьютексы не имеют TryLock в стандартной библиотеке до Go 1.18
@@ -189,27 +193,26 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if n, ok := args[0].Any().(string); ok {
 				name = n
 			} else {
-				return value.NewString("Error: newRWMutex() optional argument must be string (name)")
+				panic("newRWMutex() optional argument must be string (name)")
 			}
 		} else if len(args) > 1 {
-			return value.NewString("Error: newRWMutex() requires 0-1 arguments ([name])")
+			panic("newRWMutex() requires 0-1 arguments ([name])")
 		}
 		
 		// Генерируем имя если не предоставлено
 		if name == "" {
-			syncMapsMu.Lock()
-			mutexCounter++
-			name = fmt.Sprintf("rwmutex_%d", mutexCounter)
-			syncMapsMu.Unlock()
+			s.syncMu.Lock()
+			s.mutexCounter++
+			name = fmt.Sprintf("rwmutex_%d", s.mutexCounter)
+			s.syncMu.Unlock()
 		}
 		
-		syncMapsMu.Lock()
-		if _, exists := rwMutexes[name]; exists {
-			syncMapsMu.Unlock()
-			return value.NewString(fmt.Sprintf("Error: rwmutex '%s' already exists", name))
+		s.syncMu.Lock()
+		if _, exists := s.rwMutexes[name]; exists {
+			return s.existing("rwmutex", name)
 		}
-		rwMutexes[name] = &sync.RWMutex{}
-		syncMapsMu.Unlock()
+		s.rwMutexes[name] = &sync.RWMutex{}
+		s.syncMu.Unlock()
 		
 		return value.NewString(name)
 	}
@@ -218,20 +221,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// rwMutexRLock - блокирует мьютекс для чтения
 	rwMutexRLockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: rwMutexRLock() requires 1 argument (name)")
+			panic("rwMutexRLock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: rwMutexRLock() argument must be string (name)")
+			panic("rwMutexRLock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := rwMutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.rwMutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: rwmutex '%s' does not exist", name))
+			panic(fmt.Sprintf("rwmutex '%s' does not exist", name))
 		}
 		
 		mutex.RLock()
@@ -242,20 +245,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// rwMutexRUnlock - разблокирует мьютекс для чтения
 	rwMutexRUnlockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: rwMutexRUnlock() requires 1 argument (name)")
+			panic("rwMutexRUnlock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: rwMutexRUnlock() argument must be string (name)")
+			panic("rwMutexRUnlock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := rwMutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.rwMutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: rwmutex '%s' does not exist", name))
+			panic(fmt.Sprintf("rwmutex '%s' does not exist", name))
 		}
 		
 		mutex.RUnlock()
@@ -266,20 +269,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// rwMutexLock - блокирует мьютекс для записи
 	rwMutexLockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: rwMutexLock() requires 1 argument (name)")
+			panic("rwMutexLock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: rwMutexLock() argument must be string (name)")
+			panic("rwMutexLock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := rwMutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.rwMutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: rwmutex '%s' does not exist", name))
+			panic(fmt.Sprintf("rwmutex '%s' does not exist", name))
 		}
 		
 		mutex.Lock()
@@ -290,20 +293,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// rwMutexUnlock - разблокирует мьютекс для записи
 	rwMutexUnlockFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: rwMutexUnlock() requires 1 argument (name)")
+			panic("rwMutexUnlock() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: rwMutexUnlock() argument must be string (name)")
+			panic("rwMutexUnlock() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		mutex, exists := rwMutexes[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		mutex, exists := s.rwMutexes[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: rwmutex '%s' does not exist", name))
+			panic(fmt.Sprintf("rwmutex '%s' does not exist", name))
 		}
 		
 		mutex.Unlock()
@@ -316,7 +319,7 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// newSemaphore - создает новый семафор
 	newSemaphoreFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 || len(args) > 2 {
-			return value.NewString("Error: newSemaphore() requires 1-2 arguments (capacity, [name])")
+			panic("newSemaphore() requires 1-2 arguments (capacity, [name])")
 		}
 		
 		capacity, ok := args[0].Any().(int64)
@@ -324,12 +327,12 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				capacity = int64(floatVal)
 			} else {
-				return value.NewString("Error: newSemaphore() first argument must be numeric (capacity)")
+				panic("newSemaphore() first argument must be numeric (capacity)")
 			}
 		}
 		
 		if capacity <= 0 {
-			return value.NewString("Error: semaphore capacity must be positive")
+			panic("newSemaphore() capacity must be positive")
 		}
 		
 		name := ""
@@ -337,25 +340,24 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if n, ok := args[1].Any().(string); ok {
 				name = n
 			} else {
-				return value.NewString("Error: newSemaphore() second argument must be string (name)")
+				panic("newSemaphore() second argument must be string (name)")
 			}
 		}
 		
 		// Генерируем имя если не предоставлено
 		if name == "" {
-			syncMapsMu.Lock()
-			mutexCounter++
-			name = fmt.Sprintf("semaphore_%d", mutexCounter)
-			syncMapsMu.Unlock()
+			s.syncMu.Lock()
+			s.mutexCounter++
+			name = fmt.Sprintf("semaphore_%d", s.mutexCounter)
+			s.syncMu.Unlock()
 		}
 		
-		syncMapsMu.Lock()
-		if _, exists := semaphores[name]; exists {
-			syncMapsMu.Unlock()
-			return value.NewString(fmt.Sprintf("Error: semaphore '%s' already exists", name))
+		s.syncMu.Lock()
+		if _, exists := s.semaphores[name]; exists {
+			return s.existing("semaphore", name)
 		}
-		semaphores[name] = make(chan struct{}, capacity)
-		syncMapsMu.Unlock()
+		s.semaphores[name] = make(chan struct{}, capacity)
+		s.syncMu.Unlock()
 		
 		return value.NewString(name)
 	}
@@ -364,20 +366,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// semaphoreAcquire - захватывает семафор
 	semaphoreAcquireFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: semaphoreAcquire() requires 1 argument (name)")
+			panic("semaphoreAcquire() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: semaphoreAcquire() argument must be string (name)")
+			panic("semaphoreAcquire() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		sem, exists := semaphores[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		sem, exists := s.semaphores[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: semaphore '%s' does not exist", name))
+			panic(fmt.Sprintf("semaphore '%s' does not exist", name))
 		}
 		
 		sem <- struct{}{}
@@ -388,27 +390,27 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// semaphoreRelease - освобождает семафор
 	semaphoreReleaseFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: semaphoreRelease() requires 1 argument (name)")
+			panic("semaphoreRelease() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: semaphoreRelease() argument must be string (name)")
+			panic("semaphoreRelease() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		sem, exists := semaphores[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		sem, exists := s.semaphores[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: semaphore '%s' does not exist", name))
+			panic(fmt.Sprintf("semaphore '%s' does not exist", name))
 		}
 		
 		select {
 		case <-sem:
 			return value.NewBool(true)
 		default:
-			return value.NewString("Error: semaphore release without acquire")
+			panic("semaphoreRelease() release without acquire")
 		}
 	}
 	globalScope.Set("semaphoreRelease", value.NewValue(semaphoreReleaseFunc))
@@ -416,20 +418,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// semaphoreTryAcquire - пытается захватить семафор без ожидания
 	semaphoreTryAcquireFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: semaphoreTryAcquire() requires 1 argument (name)")
+			panic("semaphoreTryAcquire() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: semaphoreTryAcquire() argument must be string (name)")
+			panic("semaphoreTryAcquire() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		sem, exists := semaphores[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		sem, exists := s.semaphores[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: semaphore '%s' does not exist", name))
+			panic(fmt.Sprintf("semaphore '%s' does not exist", name))
 		}
 		
 		select {
@@ -450,27 +452,26 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if n, ok := args[0].Any().(string); ok {
 				name = n
 			} else {
-				return value.NewString("Error: newWaitGroup() optional argument must be string (name)")
+				panic("newWaitGroup() optional argument must be string (name)")
 			}
 		} else if len(args) > 1 {
-			return value.NewString("Error: newWaitGroup() requires 0-1 arguments ([name])")
+			panic("newWaitGroup() requires 0-1 arguments ([name])")
 		}
 		
 		// Генерируем имя если не предоставлено
 		if name == "" {
-			syncMapsMu.Lock()
-			mutexCounter++
-			name = fmt.Sprintf("waitgroup_%d", mutexCounter)
-			syncMapsMu.Unlock()
+			s.syncMu.Lock()
+			s.mutexCounter++
+			name = fmt.Sprintf("waitgroup_%d", s.mutexCounter)
+			s.syncMu.Unlock()
 		}
 		
-		syncMapsMu.Lock()
-		if _, exists := waitGroups[name]; exists {
-			syncMapsMu.Unlock()
-			return value.NewString(fmt.Sprintf("Error: waitgroup '%s' already exists", name))
+		s.syncMu.Lock()
+		if _, exists := s.waitGroups[name]; exists {
+			return s.existing("waitgroup", name)
 		}
-		waitGroups[name] = &sync.WaitGroup{}
-		syncMapsMu.Unlock()
+		s.waitGroups[name] = &sync.WaitGroup{}
+		s.syncMu.Unlock()
 		
 		return value.NewString(name)
 	}
@@ -479,12 +480,12 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// waitGroupAdd - добавляет счетчик к WaitGroup
 	waitGroupAddFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: waitGroupAdd() requires 2 arguments (name, delta)")
+			panic("waitGroupAdd() requires 2 arguments (name, delta)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: waitGroupAdd() first argument must be string (name)")
+			panic("waitGroupAdd() first argument must be string (name)")
 		}
 		
 		delta, ok := args[1].Any().(int64)
@@ -492,16 +493,16 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				delta = int64(floatVal)
 			} else {
-				return value.NewString("Error: waitGroupAdd() second argument must be numeric (delta)")
+				panic("waitGroupAdd() second argument must be numeric (delta)")
 			}
 		}
 		
-		syncMapsMu.Lock()
-		wg, exists := waitGroups[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		wg, exists := s.waitGroups[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: waitgroup '%s' does not exist", name))
+			panic(fmt.Sprintf("waitgroup '%s' does not exist", name))
 		}
 		
 		wg.Add(int(delta))
@@ -512,20 +513,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// waitGroupDone - уменьшает счетчик WaitGroup на 1
 	waitGroupDoneFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: waitGroupDone() requires 1 argument (name)")
+			panic("waitGroupDone() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: waitGroupDone() argument must be string (name)")
+			panic("waitGroupDone() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		wg, exists := waitGroups[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		wg, exists := s.waitGroups[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: waitgroup '%s' does not exist", name))
+			panic(fmt.Sprintf("waitgroup '%s' does not exist", name))
 		}
 		
 		wg.Done()
@@ -536,20 +537,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// waitGroupWait - ждет пока счетчик WaitGroup не станет 0
 	waitGroupWaitFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: waitGroupWait() requires 1 argument (name)")
+			panic("waitGroupWait() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: waitGroupWait() argument must be string (name)")
+			panic("waitGroupWait() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		wg, exists := waitGroups[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		wg, exists := s.waitGroups[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: waitgroup '%s' does not exist", name))
+			panic(fmt.Sprintf("waitgroup '%s' does not exist", name))
 		}
 		
 		wg.Wait()
@@ -562,7 +563,7 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// newAtomic - создает новую атомарную переменную
 	newAtomicFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 || len(args) > 2 {
-			return value.NewString("Error: newAtomic() requires 1-2 arguments (initialValue, [name])")
+			panic("newAtomic() requires 1-2 arguments (initialValue, [name])")
 		}
 		
 		initialValue, ok := args[0].Any().(int64)
@@ -570,7 +571,7 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				initialValue = int64(floatVal)
 			} else {
-				return value.NewString("Error: newAtomic() first argument must be numeric (initialValue)")
+				panic("newAtomic() first argument must be numeric (initialValue)")
 			}
 		}
 		
@@ -579,25 +580,24 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if n, ok := args[1].Any().(string); ok {
 				name = n
 			} else {
-				return value.NewString("Error: newAtomic() second argument must be string (name)")
+				panic("newAtomic() second argument must be string (name)")
 			}
 		}
 		
 		// Генерируем имя если не предоставлено
 		if name == "" {
-			syncMapsMu.Lock()
-			mutexCounter++
-			name = fmt.Sprintf("atomic_%d", mutexCounter)
-			syncMapsMu.Unlock()
+			s.syncMu.Lock()
+			s.mutexCounter++
+			name = fmt.Sprintf("atomic_%d", s.mutexCounter)
+			s.syncMu.Unlock()
 		}
 		
-		syncMapsMu.Lock()
-		if _, exists := atomicInts[name]; exists {
-			syncMapsMu.Unlock()
-			return value.NewString(fmt.Sprintf("Error: atomic '%s' already exists", name))
+		s.syncMu.Lock()
+		if _, exists := s.atomicInts[name]; exists {
+			return s.existing("atomic", name)
 		}
-		atomicInts[name] = &initialValue
-		syncMapsMu.Unlock()
+		s.atomicInts[name] = &initialValue
+		s.syncMu.Unlock()
 		
 		return value.NewString(name)
 	}
@@ -606,20 +606,20 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// atomicGet - получает значение атомарной переменной
 	atomicGetFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: atomicGet() requires 1 argument (name)")
+			panic("atomicGet() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: atomicGet() argument must be string (name)")
+			panic("atomicGet() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		atomicVar, exists := atomicInts[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		atomicVar, exists := s.atomicInts[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: atomic '%s' does not exist", name))
+			panic(fmt.Sprintf("atomic '%s' does not exist", name))
 		}
 		
 		val := atomic.LoadInt64(atomicVar)
@@ -630,12 +630,12 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// atomicSet - устанавливает значение атомарной переменной
 	atomicSetFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: atomicSet() requires 2 arguments (name, value)")
+			panic("atomicSet() requires 2 arguments (name, value)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: atomicSet() first argument must be string (name)")
+			panic("atomicSet() first argument must be string (name)")
 		}
 		
 		newVal, ok := args[1].Any().(int64)
@@ -643,16 +643,16 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				newVal = int64(floatVal)
 			} else {
-				return value.NewString("Error: atomicSet() second argument must be numeric (value)")
+				panic("atomicSet() second argument must be numeric (value)")
 			}
 		}
 		
-		syncMapsMu.Lock()
-		atomicVar, exists := atomicInts[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		atomicVar, exists := s.atomicInts[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: atomic '%s' does not exist", name))
+			panic(fmt.Sprintf("atomic '%s' does not exist", name))
 		}
 		
 		atomic.StoreInt64(atomicVar, newVal)
@@ -663,12 +663,12 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// atomicAdd - атомарно добавляет значение
 	atomicAddFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: atomicAdd() requires 2 arguments (name, delta)")
+			panic("atomicAdd() requires 2 arguments (name, delta)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: atomicAdd() first argument must be string (name)")
+			panic("atomicAdd() first argument must be string (name)")
 		}
 		
 		delta, ok := args[1].Any().(int64)
@@ -676,16 +676,16 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				delta = int64(floatVal)
 			} else {
-				return value.NewString("Error: atomicAdd() second argument must be numeric (delta)")
+				panic("atomicAdd() second argument must be numeric (delta)")
 			}
 		}
 		
-		syncMapsMu.Lock()
-		atomicVar, exists := atomicInts[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		atomicVar, exists := s.atomicInts[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: atomic '%s' does not exist", name))
+			panic(fmt.Sprintf("atomic '%s' does not exist", name))
 		}
 		
 		newVal := atomic.AddInt64(atomicVar, delta)
@@ -696,12 +696,12 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// atomicCompareAndSwap - атомарное сравнение и замена
 	atomicCompareAndSwapFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 3 {
-			return value.NewString("Error: atomicCompareAndSwap() requires 3 arguments (name, expected, new)")
+			panic("atomicCompareAndSwap() requires 3 arguments (name, expected, new)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: atomicCompareAndSwap() first argument must be string (name)")
+			panic("atomicCompareAndSwap() first argument must be string (name)")
 		}
 		
 		expected, ok := args[1].Any().(int64)
@@ -709,7 +709,7 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				expected = int64(floatVal)
 			} else {
-				return value.NewString("Error: atomicCompareAndSwap() second argument must be numeric (expected)")
+				panic("atomicCompareAndSwap() second argument must be numeric (expected)")
 			}
 		}
 		
@@ -718,16 +718,16 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[2].Any().(float64); ok {
 				newVal = int64(floatVal)
 			} else {
-				return value.NewString("Error: atomicCompareAndSwap() third argument must be numeric (new)")
+				panic("atomicCompareAndSwap() third argument must be numeric (new)")
 			}
 		}
 		
-		syncMapsMu.Lock()
-		atomicVar, exists := atomicInts[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		atomicVar, exists := s.atomicInts[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: atomic '%s' does not exist", name))
+			panic(fmt.Sprintf("atomic '%s' does not exist", name))
 		}
 		
 		swapped := atomic.CompareAndSwapInt64(atomicVar, expected, newVal)
@@ -740,7 +740,7 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// newBarrier - создает новый барьер
 	newBarrierFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 || len(args) > 2 {
-			return value.NewString("Error: newBarrier() requires 1-2 arguments (n, [name])")
+			panic("newBarrier() requires 1-2 arguments (n, [name])")
 		}
 		
 		n, ok := args[0].Any().(int64)
@@ -748,12 +748,12 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				n = int64(floatVal)
 			} else {
-				return value.NewString("Error: newBarrier() first argument must be numeric (n)")
+				panic("newBarrier() first argument must be numeric (n)")
 			}
 		}
 		
 		if n <= 0 {
-			return value.NewString("Error: barrier n must be positive")
+			panic("newBarrier() n must be positive")
 		}
 		
 		name := ""
@@ -761,25 +761,24 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 			if nm, ok := args[1].Any().(string); ok {
 				name = nm
 			} else {
-				return value.NewString("Error: newBarrier() second argument must be string (name)")
+				panic("newBarrier() second argument must be string (name)")
 			}
 		}
 		
 		// Генерируем имя если не предоставлено
 		if name == "" {
-			syncMapsMu.Lock()
-			mutexCounter++
-			name = fmt.Sprintf("barrier_%d", mutexCounter)
-			syncMapsMu.Unlock()
+			s.syncMu.Lock()
+			s.mutexCounter++
+			name = fmt.Sprintf("barrier_%d", s.mutexCounter)
+			s.syncMu.Unlock()
 		}
 		
-		syncMapsMu.Lock()
-		if _, exists := barriers[name]; exists {
-			syncMapsMu.Unlock()
-			return value.NewString(fmt.Sprintf("Error: barrier '%s' already exists", name))
+		s.syncMu.Lock()
+		if _, exists := s.barriers[name]; exists {
+			return s.existing("barrier", name)
 		}
-		barriers[name] = newBarrier(int(n))
-		syncMapsMu.Unlock()
+		s.barriers[name] = newBarrier(int(n))
+		s.syncMu.Unlock()
 		
 		return value.NewString(name)
 	}
@@ -788,25 +787,25 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 	// barrierWait - ждет на барьере
 	barrierWaitFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: barrierWait() requires 1 argument (name)")
+			panic("barrierWait() requires 1 argument (name)")
 		}
 		
 		name, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: barrierWait() argument must be string (name)")
+			panic("barrierWait() argument must be string (name)")
 		}
 		
-		syncMapsMu.Lock()
-		barrier, exists := barriers[name]
-		syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		barrier, exists := s.barriers[name]
+		s.syncMu.Unlock()
 		
 		if !exists {
-			return value.NewString(fmt.Sprintf("Error: barrier '%s' does not exist", name))
+			panic(fmt.Sprintf("barrier '%s' does not exist", name))
 		}
 		
 		err := barrier.wait()
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error: %v", err))
+			panic(err.Error())
 		}
 		
 		return value.NewBool(true)
@@ -817,18 +816,18 @@ func InitializeSyncFunctions(globalScope *scope.ScopeStack) {
 
 	// syncCleanup - очищает все примитивы синхронизации
 	syncCleanupFunc := func(args []*value.Value) *value.Value {
-		syncMapsMu.Lock()
-		defer syncMapsMu.Unlock()
+		s.syncMu.Lock()
+		defer s.syncMu.Unlock()
 		
 		// Очищаем все карты
-		mutexes = make(map[string]*sync.Mutex)
-		rwMutexes = make(map[string]*sync.RWMutex)
-		waitGroups = make(map[string]*sync.WaitGroup)
-		semaphores = make(map[string]chan struct{})
-		atomicInts = make(map[string]*int64)
-		conditions = make(map[string]*sync.Cond)
-		barriers = make(map[string]*barrier)
-		mutexCounter = 0
+		s.mutexes = make(map[string]*sync.Mutex)
+		s.rwMutexes = make(map[string]*sync.RWMutex)
+		s.waitGroups = make(map[string]*sync.WaitGroup)
+		s.semaphores = make(map[string]chan struct{})
+		s.atomicInts = make(map[string]*int64)
+		s.conditions = make(map[string]*sync.Cond)
+		s.barriers = make(map[string]*barrier)
+		s.mutexCounter = 0
 		
 		return value.NewBool(true)
 	}
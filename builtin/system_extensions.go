@@ -21,52 +21,28 @@ func RegisterSystemExtension() {
 	// System.getOS()
 	systemGetOS := &SystemExtensionMethod{
 		Name: "getOS",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := GetOS(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(GetOS),
 	}
 	value.RegisterExtensionMethod("System", "getOS", systemGetOS)
 	
 	// System.getEnv(key)
 	systemGetEnv := &SystemExtensionMethod{
 		Name: "getEnv",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := GetEnv(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(GetEnv),
 	}
 	value.RegisterExtensionMethod("System", "getEnv", systemGetEnv)
 	
 	// System.setEnv(key, value)
 	systemSetEnv := &SystemExtensionMethod{
 		Name: "setEnv",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := SetEnv(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(SetEnv),
 	}
 	value.RegisterExtensionMethod("System", "setEnv", systemSetEnv)
 	
 	// System.exit(code)
 	systemExit := &SystemExtensionMethod{
 		Name: "exit",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Exit(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Exit),
 	}
 	value.RegisterExtensionMethod("System", "exit", systemExit)
 }
@@ -76,65 +52,35 @@ func RegisterIOExtension() {
 	// IO.input(prompt)
 	ioInput := &SystemExtensionMethod{
 		Name: "input",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Input(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Input),
 	}
 	value.RegisterExtensionMethod("IO", "input", ioInput)
 	
 	// IO.readLine()
 	ioReadLine := &SystemExtensionMethod{
 		Name: "readLine",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := ReadLine(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(ReadLine),
 	}
 	value.RegisterExtensionMethod("IO", "readLine", ioReadLine)
 	
 	// IO.inputNumber(prompt)
 	ioInputNumber := &SystemExtensionMethod{
 		Name: "inputNumber",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := InputNumber(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(InputNumber),
 	}
 	value.RegisterExtensionMethod("IO", "inputNumber", ioInputNumber)
 	
 	// IO.write(...)
 	ioWrite := &SystemExtensionMethod{
 		Name: "write",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Write(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Write),
 	}
 	value.RegisterExtensionMethod("IO", "write", ioWrite)
 	
 	// IO.writeLn(...)
 	ioWriteLn := &SystemExtensionMethod{
 		Name: "writeLn",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := WriteLn(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(WriteLn),
 	}
 	value.RegisterExtensionMethod("IO", "writeLn", ioWriteLn)
 }
@@ -144,39 +90,21 @@ func RegisterConsoleExtension() {
 	// Console.printf(format, ...)
 	consolePrintf := &SystemExtensionMethod{
 		Name: "printf",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Printf(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Printf),
 	}
 	value.RegisterExtensionMethod("Console", "printf", consolePrintf)
 	
 	// Console.getChar()
 	consoleGetChar := &SystemExtensionMethod{
 		Name: "getChar",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := GetChar(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(GetChar),
 	}
 	value.RegisterExtensionMethod("Console", "getChar", consoleGetChar)
 	
 	// Console.putChar(char)
 	consolePutChar := &SystemExtensionMethod{
 		Name: "putChar",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := PutChar(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(PutChar),
 	}
 	value.RegisterExtensionMethod("Console", "putChar", consolePutChar)
 }
@@ -186,52 +114,28 @@ func RegisterProcessExtension() {
 	// Process.exec(command, ...args)
 	processExec := &SystemExtensionMethod{
 		Name: "exec",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Exec(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Exec),
 	}
 	value.RegisterExtensionMethod("Process", "exec", processExec)
 	
 	// Process.spawn(command, ...args)
 	processSpawn := &SystemExtensionMethod{
 		Name: "spawn",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Spawn(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Spawn),
 	}
 	value.RegisterExtensionMethod("Process", "spawn", processSpawn)
 	
 	// Process.kill(pid)
 	processKill := &SystemExtensionMethod{
 		Name: "kill",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Kill(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Kill),
 	}
 	value.RegisterExtensionMethod("Process", "kill", processKill)
 	
 	// Process.getPid()
 	processGetPid := &SystemExtensionMethod{
 		Name: "getPid",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := GetPid(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(GetPid),
 	}
 	value.RegisterExtensionMethod("Process", "getPid", processGetPid)
 }
@@ -241,52 +145,29 @@ func RegisterDebugExtension() {
 	// Debug.debug(value)
 	debugDebug := &SystemExtensionMethod{
 		Name: "debug",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Debug(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(Debug),
 	}
 	value.RegisterExtensionMethod("Debug", "debug", debugDebug)
 	
-	// Debug.trace(depth)
+	// Debug.trace(depth): у extension метода нет Runtime вызывающего кода,
+	// стек берется из ast.GlobalRuntime
 	debugTrace := &SystemExtensionMethod{
 		Name: "trace",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := Trace(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: traceBuiltin().Call,
 	}
 	value.RegisterExtensionMethod("Debug", "trace", debugTrace)
 	
 	// Debug.typeOf(value)
 	debugTypeOf := &SystemExtensionMethod{
 		Name: "typeOf",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := TypeOf(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(TypeOf),
 	}
 	value.RegisterExtensionMethod("Debug", "typeOf", debugTypeOf)
 	
 	// Debug.sizeOf(value)
 	debugSizeOf := &SystemExtensionMethod{
 		Name: "sizeOf",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := SizeOf(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(SizeOf),
 	}
 	value.RegisterExtensionMethod("Debug", "sizeOf", debugSizeOf)
 }
@@ -296,26 +177,14 @@ func RegisterMemoryExtension() {
 	// Memory.stats()
 	memoryStats := &SystemExtensionMethod{
 		Name: "stats",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := MemStats(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(MemStats),
 	}
 	value.RegisterExtensionMethod("Memory", "stats", memoryStats)
 	
 	// Memory.gc()
 	memoryGC := &SystemExtensionMethod{
 		Name: "gc",
-		Func: func(args []*value.Value) *value.Value {
-			result, err := GC(args)
-			if err != nil {
-				return value.NewValue("Error: " + err.Error())
-			}
-			return result
-		},
+		Func: wrap(GC),
 	}
 	value.RegisterExtensionMethod("Memory", "gc", memoryGC)
 }
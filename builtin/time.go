@@ -1,7 +1,6 @@
 package builtin
 
 import (
-	"fmt"
 	"foo_lang/scope"
 	"foo_lang/value"
 	"time"
@@ -12,7 +11,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// now - текущее время
 	nowFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 0 {
-			return value.NewString("Error: now() requires 0 arguments")
+			panic("now() requires 0 arguments")
 		}
 		return value.NewTime(time.Now())
 	}
@@ -21,7 +20,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeFromUnix - создание времени из Unix timestamp (секунды)
 	timeFromUnixFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeFromUnix() requires 1 argument (unix timestamp)")
+			panic("timeFromUnix() requires 1 argument (unix timestamp)")
 		}
 		
 		timestamp, ok := args[0].Any().(int64)
@@ -30,7 +29,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[0].Any().(float64); ok {
 				timestamp = int64(floatVal)
 			} else {
-				return value.NewString("Error: timeFromUnix() requires numeric argument")
+				panic("timeFromUnix() requires numeric argument")
 			}
 		}
 		
@@ -41,12 +40,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeFromString - парсинг времени из строки
 	timeFromStringFunc := func(args []*value.Value) *value.Value {
 		if len(args) < 1 || len(args) > 2 {
-			return value.NewString("Error: timeFromString() requires 1-2 arguments (timeString, [format])")
+			panic("timeFromString() requires 1-2 arguments (timeString, [format])")
 		}
 		
 		timeStr, ok := args[0].Any().(string)
 		if !ok {
-			return value.NewString("Error: first argument must be a string")
+			panic("timeFromString() first argument must be a string")
 		}
 		
 		// Если формат не указан, используем RFC3339
@@ -60,27 +59,27 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 		
 		parsedTime, err := time.Parse(format, timeStr)
 		if err != nil {
-			return value.NewString(fmt.Sprintf("Error parsing time: %v", err))
+			return parseError("parsing time '%s': %v", timeStr, err)
 		}
 		
-		return value.NewTime(parsedTime)
+		return okResult(value.NewTime(parsedTime))
 	}
 	globalScope.Set("timeFromString", value.NewValue(timeFromStringFunc))
 
 	// timeFormat - форматирование времени
 	timeFormatFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeFormat() requires 2 arguments (time, format)")
+			panic("timeFormat() requires 2 arguments (time, format)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeFormat() first argument must be a time value")
 		}
 		
 		formatStr, ok := args[1].Any().(string)
 		if !ok {
-			return value.NewString("Error: second argument must be a format string")
+			panic("timeFormat() second argument must be a format string")
 		}
 		
 		// Конвертируем упрощенный формат в Go формат
@@ -92,12 +91,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeYear - получить год
 	timeYearFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeYear() requires 1 argument (time)")
+			panic("timeYear() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeYear() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Year()))
@@ -107,12 +106,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeMonth - получить месяц
 	timeMonthFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeMonth() requires 1 argument (time)")
+			panic("timeMonth() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeMonth() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Month()))
@@ -122,12 +121,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeDay - получить день
 	timeDayFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeDay() requires 1 argument (time)")
+			panic("timeDay() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeDay() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Day()))
@@ -137,12 +136,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeHour - получить час
 	timeHourFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeHour() requires 1 argument (time)")
+			panic("timeHour() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeHour() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Hour()))
@@ -152,12 +151,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeMinute - получить минуты
 	timeMinuteFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeMinute() requires 1 argument (time)")
+			panic("timeMinute() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeMinute() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Minute()))
@@ -167,12 +166,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeSecond - получить секунды
 	timeSecondFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeSecond() requires 1 argument (time)")
+			panic("timeSecond() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeSecond() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Second()))
@@ -182,12 +181,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeWeekday - получить день недели (0 = Sunday, 6 = Saturday)
 	timeWeekdayFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeWeekday() requires 1 argument (time)")
+			panic("timeWeekday() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeWeekday() argument must be a time value")
 		}
 		
 		return value.NewInt64(int64(timeVal.Weekday()))
@@ -197,12 +196,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeUnix - получить Unix timestamp (секунды)
 	timeUnixFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 1 {
-			return value.NewString("Error: timeUnix() requires 1 argument (time)")
+			panic("timeUnix() requires 1 argument (time)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: argument must be a time value")
+			panic("timeUnix() argument must be a time value")
 		}
 		
 		return value.NewInt64(timeVal.Unix())
@@ -212,12 +211,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAddDays - добавить дни
 	timeAddDaysFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAddDays() requires 2 arguments (time, days)")
+			panic("timeAddDays() requires 2 arguments (time, days)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAddDays() first argument must be a time value")
 		}
 		
 		days, ok := args[1].Any().(int64)
@@ -226,7 +225,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				days = int64(floatVal)
 			} else {
-				return value.NewString("Error: second argument must be a number")
+				panic("timeAddDays() second argument must be a number")
 			}
 		}
 		
@@ -237,12 +236,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAddMonths - добавить месяцы
 	timeAddMonthsFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAddMonths() requires 2 arguments (time, months)")
+			panic("timeAddMonths() requires 2 arguments (time, months)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAddMonths() first argument must be a time value")
 		}
 		
 		months, ok := args[1].Any().(int64)
@@ -251,7 +250,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				months = int64(floatVal)
 			} else {
-				return value.NewString("Error: second argument must be a number")
+				panic("timeAddMonths() second argument must be a number")
 			}
 		}
 		
@@ -262,12 +261,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAddYears - добавить годы
 	timeAddYearsFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAddYears() requires 2 arguments (time, years)")
+			panic("timeAddYears() requires 2 arguments (time, years)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAddYears() first argument must be a time value")
 		}
 		
 		years, ok := args[1].Any().(int64)
@@ -276,7 +275,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				years = int64(floatVal)
 			} else {
-				return value.NewString("Error: second argument must be a number")
+				panic("timeAddYears() second argument must be a number")
 			}
 		}
 		
@@ -287,12 +286,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAddHours - добавить часы
 	timeAddHoursFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAddHours() requires 2 arguments (time, hours)")
+			panic("timeAddHours() requires 2 arguments (time, hours)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAddHours() first argument must be a time value")
 		}
 		
 		hours, ok := args[1].Any().(int64)
@@ -301,7 +300,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				hours = int64(floatVal)
 			} else {
-				return value.NewString("Error: second argument must be a number")
+				panic("timeAddHours() second argument must be a number")
 			}
 		}
 		
@@ -312,12 +311,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAddMinutes - добавить минуты
 	timeAddMinutesFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAddMinutes() requires 2 arguments (time, minutes)")
+			panic("timeAddMinutes() requires 2 arguments (time, minutes)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAddMinutes() first argument must be a time value")
 		}
 		
 		minutes, ok := args[1].Any().(int64)
@@ -326,7 +325,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				minutes = int64(floatVal)
 			} else {
-				return value.NewString("Error: second argument must be a number")
+				panic("timeAddMinutes() second argument must be a number")
 			}
 		}
 		
@@ -337,12 +336,12 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAddSeconds - добавить секунды
 	timeAddSecondsFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAddSeconds() requires 2 arguments (time, seconds)")
+			panic("timeAddSeconds() requires 2 arguments (time, seconds)")
 		}
 		
 		timeVal, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAddSeconds() first argument must be a time value")
 		}
 		
 		seconds, ok := args[1].Any().(int64)
@@ -351,7 +350,7 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 			if floatVal, ok := args[1].Any().(float64); ok {
 				seconds = int64(floatVal)
 			} else {
-				return value.NewString("Error: second argument must be a number")
+				panic("timeAddSeconds() second argument must be a number")
 			}
 		}
 		
@@ -362,17 +361,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeDiff - разница между двумя временами в секундах
 	timeDiffFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeDiff() requires 2 arguments (time1, time2)")
+			panic("timeDiff() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeDiff() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeDiff() second argument must be a time value")
 		}
 		
 		diff := time1.Sub(time2)
@@ -383,17 +382,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeDiffDays - разница в днях
 	timeDiffDaysFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeDiffDays() requires 2 arguments (time1, time2)")
+			panic("timeDiffDays() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeDiffDays() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeDiffDays() second argument must be a time value")
 		}
 		
 		diff := time1.Sub(time2)
@@ -404,17 +403,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeDiffHours - разница в часах
 	timeDiffHoursFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeDiffHours() requires 2 arguments (time1, time2)")
+			panic("timeDiffHours() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeDiffHours() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeDiffHours() second argument must be a time value")
 		}
 		
 		diff := time1.Sub(time2)
@@ -425,17 +424,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeDiffMinutes - разница в минутах
 	timeDiffMinutesFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeDiffMinutes() requires 2 arguments (time1, time2)")
+			panic("timeDiffMinutes() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeDiffMinutes() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeDiffMinutes() second argument must be a time value")
 		}
 		
 		diff := time1.Sub(time2)
@@ -446,17 +445,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeBefore - проверка, что time1 < time2
 	timeBeforeFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeBefore() requires 2 arguments (time1, time2)")
+			panic("timeBefore() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeBefore() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeBefore() second argument must be a time value")
 		}
 		
 		return value.NewBool(time1.Before(time2))
@@ -466,17 +465,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeAfter - проверка, что time1 > time2
 	timeAfterFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeAfter() requires 2 arguments (time1, time2)")
+			panic("timeAfter() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeAfter() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeAfter() second argument must be a time value")
 		}
 		
 		return value.NewBool(time1.After(time2))
@@ -486,17 +485,17 @@ func InitializeTimeFunctions(globalScope *scope.ScopeStack) {
 	// timeEqual - проверка равенства времен
 	timeEqualFunc := func(args []*value.Value) *value.Value {
 		if len(args) != 2 {
-			return value.NewString("Error: timeEqual() requires 2 arguments (time1, time2)")
+			panic("timeEqual() requires 2 arguments (time1, time2)")
 		}
 		
 		time1, ok := args[0].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: first argument must be a time value")
+			panic("timeEqual() first argument must be a time value")
 		}
 		
 		time2, ok := args[1].Any().(time.Time)
 		if !ok {
-			return value.NewString("Error: second argument must be a time value")
+			panic("timeEqual() second argument must be a time value")
 		}
 		
 		return value.NewBool(time1.Equal(time2))
@@ -0,0 +1,536 @@
+package bytecode
+
+import (
+	"foo_lang/ast"
+	"foo_lang/token"
+)
+
+// Compiler компилирует AST программы в bytecode chunk.
+//
+// Каждое скомпилированное выражение оставляет на стеке ровно одно значение:
+// операторы (let, print, объявления функций) оставляют nil, а блок оставляет
+// значение последнего выражения. Переменные хранятся по имени в общем ScopeStack,
+// поэтому конструкции без прямой компиляции выполняются tree-walking интерпретатором
+// через OP_EVAL_AST и видят те же переменные, что и скомпилированный код.
+type Compiler struct {
+	chunk *Chunk
+	loops []*loopContext
+	pos   ast.Position // позиция компилируемого выражения
+}
+
+// loopContext хранит переходы break и continue текущего цикла, которые нужно пропатчить
+type loopContext struct {
+	label         string
+	breakJumps    []int
+	continueJumps []int
+	exits         []*loopExit
+}
+
+// loopExit - адреса break и continue цикла для узла, выполняемого tree-walking
+// интерпретатором: флаг break/continue из узла передается циклу, как обычный переход
+type loopExit struct {
+	label      string
+	nested     int // сколько вложенных в цикл циклов снять перед переходом
+	breakIP    int
+	continueIP int
+}
+
+// NewCompiler создает новый компилятор
+func NewCompiler() *Compiler {
+	return &Compiler{
+		chunk: NewChunk(),
+		loops: make([]*loopContext, 0),
+	}
+}
+
+// Compile компилирует выражения верхнего уровня в chunk.
+// Результатом выполнения chunk'а является значение последнего выражения
+func Compile(exprs []ast.Expr) *Chunk {
+	c := NewCompiler()
+	c.compileBlock(exprs)
+	return c.chunk
+}
+
+// Chunk возвращает скомпилированный chunk
+func (c *Compiler) Chunk() *Chunk {
+	return c.chunk
+}
+
+// emit добавляет инструкцию и возвращает её индекс
+func (c *Compiler) emit(op OpCode, operands ...int) int {
+	index := c.chunk.WriteInstruction(op, operands, c.pos.Line)
+	c.chunk.Code[index].Position = c.pos
+	return index
+}
+
+// emitConstant добавляет константу и инструкцию её загрузки
+func (c *Compiler) emitConstant(v interface{}) {
+	c.emit(OP_CONSTANT, c.chunk.AddConstant(v))
+}
+
+// emitJump добавляет переход с незаполненным смещением
+func (c *Compiler) emitJump(op OpCode) int {
+	return c.emit(op, 0)
+}
+
+// patchJump направляет переход на текущий конец chunk'а
+func (c *Compiler) patchJump(index int) {
+	c.chunk.Code[index].Operands[0] = len(c.chunk.Code) - index
+}
+
+// emitLoop добавляет переход назад к инструкции start
+func (c *Compiler) emitLoop(start int) {
+	c.emit(OP_LOOP, len(c.chunk.Code)-start)
+}
+
+// name добавляет имя в таблицу констант
+func (c *Compiler) name(name string) int {
+	return c.chunk.AddConstant(name)
+}
+
+// compileBlock компилирует последовательность выражений, оставляя значение последнего.
+// Объявления функций поднимаются в начало блока, как и в tree-walking интерпретаторе,
+// где функции регистрируются еще на этапе парсинга
+func (c *Compiler) compileBlock(stmts []ast.Expr) {
+	var body []ast.Expr
+	for _, stmt := range stmts {
+		if stmt != nil {
+			body = append(body, stmt)
+		}
+	}
+
+	for _, stmt := range body {
+		if fn, ok := stmt.(*ast.FuncStatment); ok && !fn.IsMacro() {
+			c.emit(OP_CLOSURE, c.chunk.AddConstant(c.compileFunction(fn.Name(), fn.Args(), fn.Body())))
+			c.emit(OP_DEFINE_FUNCTION, c.name(fn.Name()))
+		}
+	}
+
+	if len(body) == 0 {
+		c.emit(OP_NIL)
+		return
+	}
+
+	for i, stmt := range body {
+		c.compile(stmt)
+		if i < len(body)-1 {
+			c.emit(OP_POP)
+		}
+	}
+}
+
+// compileFunction компилирует тело функции в отдельный chunk
+func (c *Compiler) compileFunction(name string, args []map[string]ast.Expr, body ast.Expr) *Function {
+	var params []string
+	var defaults []*Chunk
+
+	for _, arg := range args {
+		for paramName, defaultExpr := range arg {
+			params = append(params, paramName)
+			if defaultExpr == nil {
+				defaults = append(defaults, nil)
+				continue
+			}
+			dc := NewCompiler()
+			dc.compile(defaultExpr)
+			defaults = append(defaults, dc.chunk)
+		}
+	}
+
+	fc := NewCompiler()
+	if block, ok := body.(*ast.BodyExpr); ok {
+		// Тело-блок без явного return возвращает null
+		fc.compileBlock(block.Statments)
+		fc.emit(OP_POP)
+		fc.emit(OP_NIL)
+	} else {
+		// Стрелочная функция возвращает значение выражения
+		fc.compile(body)
+	}
+	fc.emit(OP_RETURN)
+
+	function := NewFunction(name, params, defaults, fc.chunk)
+	function.Position = ast.PositionOf(body)
+	return function
+}
+
+// compile компилирует одно выражение
+func (c *Compiler) compile(expr ast.Expr) {
+	// Инструкции выражения помечаются его позицией, после него - снова позицией родителя
+	if pos := ast.PositionOf(expr); pos.IsValid() {
+		parent := c.pos
+		c.pos = pos
+		defer func() { c.pos = parent }()
+	}
+
+	switch e := expr.(type) {
+	case nil:
+		c.emit(OP_NIL)
+
+	// Литералы
+	case *ast.IntExpr:
+		c.emitConstant(e.Value.Any())
+	case *ast.FloatExpr:
+		c.emitConstant(e.Value.Any())
+	case *ast.BigIntExpr:
+		c.emitConstant(e.Value.Any())
+	case *ast.BoolExpr:
+		if e.Value.Bool() {
+			c.emit(OP_TRUE)
+		} else {
+			c.emit(OP_FALSE)
+		}
+	case *ast.LiteralString:
+		c.emitConstant(e.Value)
+	case *ast.LiteralAny:
+		c.emitConstant(e.Value)
+	case *ast.NullExpr:
+		c.emit(OP_NIL)
+	case *ast.StringFormatExpr:
+		count := 0
+		for _, part := range e.Parts() {
+			if part == nil {
+				continue
+			}
+			c.compile(part)
+			count++
+		}
+		c.emit(OP_FORMAT, count)
+	case *ast.ArrayExpr:
+		for _, element := range e.Elements {
+			c.compile(element)
+		}
+		c.emit(OP_BUILD_ARRAY, len(e.Elements))
+	case *ast.ObjectExpr:
+		// Поля компилируются в порядке литерала: он же порядок ключей объекта
+		for _, key := range e.Keys {
+			c.emitConstant(key)
+			c.compile(e.Fields[key])
+		}
+		c.emit(OP_OBJECT, len(e.Keys))
+
+	// Переменные
+	case *ast.VarExpr:
+		if e.GetExpr() == nil {
+			c.emit(OP_GET_VAR, c.name(e.Name))
+		} else {
+			c.compile(e.GetExpr())
+			c.emit(OP_SET_VAR, c.name(e.Name))
+		}
+	case *ast.LetExpr:
+		c.compile(e.GetExpr())
+		c.emit(OP_DEFINE_VAR, c.name(e.GetName()))
+		c.emit(OP_NIL)
+	case *ast.ConstExpr:
+		// Константы определяются парсером при разборе (см. ast.NewConstExpr)
+		c.emit(OP_NIL)
+
+	// Операторы
+	case *ast.BinaryExpr:
+		c.compileBinary(e)
+	case *ast.UnaryOpExpr:
+		c.compile(e.Expr)
+		switch e.Op {
+		case '-':
+			c.emit(OP_NEGATE)
+		case '!':
+			// Четное количество '!' приводит значение к bool
+			c.emit(OP_NOT)
+			if e.Count%2 == 0 {
+				c.emit(OP_NOT)
+			}
+		}
+	case *ast.ConditionalExpr:
+		c.compile(e.Condition)
+		elseJump := c.emitJump(OP_JUMP_IF_FALSE)
+		c.compile(e.ThenBranch)
+		endJump := c.emitJump(OP_JUMP)
+		c.patchJump(elseJump)
+		c.compile(e.ElseBranch)
+		c.patchJump(endJump)
+
+	// Управление потоком
+	case *ast.BodyExpr:
+		c.compileBlock(e.Statments)
+	case *ast.IfExpr:
+		c.compileIf(e)
+	case *ast.ForExpr:
+		c.compileLoop(e.Label, e.InitExpr, e.ConditionExpr, e.StepExpr, e.BodyExpr)
+	case *ast.WhileExpr:
+		c.compileLoop(e.Label, nil, e.ConditionExpr, nil, e.BodyExpr)
+	case *ast.LoopExpr:
+		c.compileLoop(e.Label, nil, nil, nil, e.BodyExpr)
+	case *ast.MatchExpr:
+		c.compileMatch(e)
+	case *ast.BreakExpr:
+		loop := c.exitLoops(e.Label)
+		if loop == nil {
+			c.emit(OP_NIL)
+			return
+		}
+		loop.breakJumps = append(loop.breakJumps, c.emitJump(OP_JUMP))
+		c.emit(OP_NIL) // недостижимо, сохраняет баланс стека для компилятора
+	case *ast.ContinueExpr:
+		loop := c.exitLoops(e.Label)
+		if loop == nil {
+			c.emit(OP_NIL)
+			return
+		}
+		c.emit(OP_LOOP_CONTINUE)
+		loop.continueJumps = append(loop.continueJumps, c.emitJump(OP_JUMP))
+		c.emit(OP_NIL) // недостижимо, сохраняет баланс стека для компилятора
+	case *ast.YieldExpr:
+		c.compile(e.Expr)
+		c.emit(OP_YIELD)
+	case *ast.ReturnExpr:
+		c.compile(e.Expr)
+		c.emit(OP_RETURN)
+		c.emit(OP_NIL) // недостижимо, сохраняет баланс стека для компилятора
+	case *ast.PrintExpr:
+		if e.Args != nil {
+			c.emitEvalAST(expr)
+			return
+		}
+		if e.Expr == nil {
+			c.emit(OP_NIL)
+			return
+		}
+		c.compile(e.Expr)
+		if e.IsPrint() {
+			c.emit(OP_PRINT)
+		} else {
+			c.emit(OP_PRINTLN)
+		}
+
+	// Функции
+	case *ast.FuncStatment:
+		// Функция уже объявлена в начале блока
+		c.emit(OP_NIL)
+	case *ast.AnonymousFunc:
+		c.emit(OP_CLOSURE, c.chunk.AddConstant(c.compileFunction("(anonymous)", e.Args(), e.Body())))
+	case *ast.FuncCallExpr:
+		for _, arg := range e.Args() {
+			c.compile(arg)
+		}
+		c.emit(OP_CALL_FUNCTION, c.name(e.FuncName()), len(e.Args()))
+	case *ast.MethodCallExpr:
+		c.compile(e.Object)
+		for _, arg := range e.Args {
+			c.compile(arg)
+		}
+		c.emit(OP_METHOD_CALL, c.name(e.MethodName), len(e.Args))
+	case *ast.PropagateExpr:
+		c.compile(e.Expr)
+		c.emit(OP_PROPAGATE)
+	case *ast.MemberExpr:
+		c.compile(e.Object)
+		c.emit(OP_PROPERTY_ACCESS, c.name(e.Property))
+	case *ast.IndexExpr:
+		c.compile(e.Object)
+		c.compile(e.Index)
+		c.emit(OP_INDEX)
+
+	default:
+		// Структуры, интерфейсы, макросы, модули, async и прочее
+		// выполняются tree-walking интерпретатором
+		c.emitEvalAST(expr)
+	}
+}
+
+// emitEvalAST добавляет выполнение узла tree-walking интерпретатором.
+// Внутри циклов вторым операндом идут их адреса выхода (от внутреннего к внешнему),
+// чтобы break и continue из узла (try, for-in, match) завершали цикл VM
+func (c *Compiler) emitEvalAST(expr ast.Expr) {
+	if len(c.loops) == 0 {
+		c.emit(OP_EVAL_AST, c.chunk.AddConstant(expr))
+		return
+	}
+
+	exits := make([]*loopExit, 0, len(c.loops))
+	for i := len(c.loops) - 1; i >= 0; i-- {
+		exit := &loopExit{label: c.loops[i].label, nested: len(c.loops) - 1 - i}
+		c.loops[i].exits = append(c.loops[i].exits, exit)
+		exits = append(exits, exit)
+	}
+	c.emit(OP_EVAL_AST, c.chunk.AddConstant(expr), c.chunk.AddConstant(exits))
+}
+
+// binaryOpCodes сопоставляет бинарные операторы инструкциям VM
+var binaryOpCodes = map[token.Token]OpCode{
+	token.ADD:     OP_ADD,
+	token.SUB:     OP_SUBTRACT,
+	token.MUL:     OP_MULTIPLY,
+	token.QUO:     OP_DIVIDE,
+	token.REM:     OP_MODULO,
+	token.GT:      OP_GREATER,
+	token.LT:      OP_LESS,
+	token.GT_EQ:   OP_GREATER_EQUAL,
+	token.LT_EQ:   OP_LESS_EQUAL,
+	token.EQ_EQ:   OP_EQUAL,
+	token.NOT_EQ:  OP_NOT_EQUAL,
+	token.AND_AND: OP_AND,
+	token.OR_OR:   OP_OR,
+}
+
+// compileBinary компилирует бинарное выражение
+func (c *Compiler) compileBinary(e *ast.BinaryExpr) {
+	op, ok := binaryOpCodes[e.Op]
+	if !ok {
+		// Битовые операции выполняются tree-walking интерпретатором
+		c.emitEvalAST(e)
+		return
+	}
+	c.compile(e.Left)
+	c.compile(e.Right)
+	c.emit(op)
+}
+
+// compileIf компилирует цепочку if / else if / else
+func (c *Compiler) compileIf(e *ast.IfExpr) {
+	var endJumps []int
+
+	for i, cond := range e.Condition {
+		c.compile(cond)
+		nextJump := c.emitJump(OP_JUMP_IF_FALSE)
+		c.compile(e.Then[i])
+		endJumps = append(endJumps, c.emitJump(OP_JUMP))
+		c.patchJump(nextJump)
+	}
+
+	c.compile(e.Else)
+
+	for _, jump := range endJumps {
+		c.patchJump(jump)
+	}
+}
+
+// compileLoop компилирует циклы for init; cond; step { body }, while cond { body }
+// и loop { body } (отсутствующие части - nil).
+// Цикл получает собственную область видимости, а каждая итерация - вложенную,
+// значения yield собираются в массив, который становится значением цикла
+func (c *Compiler) compileLoop(label string, init, condition, step, body ast.Expr) {
+	c.emit(OP_PUSH_SCOPE)
+	c.emit(OP_LOOP_BEGIN)
+
+	if init != nil {
+		c.compile(init)
+		c.emit(OP_POP)
+	}
+
+	loopStart := len(c.chunk.Code)
+	exitJump := -1
+	if condition != nil {
+		c.compile(condition)
+		exitJump = c.emitJump(OP_JUMP_IF_FALSE)
+	}
+
+	loop := &loopContext{label: label}
+	c.loops = append(c.loops, loop)
+
+	c.emit(OP_PUSH_SCOPE)
+	c.compile(body)
+	c.emit(OP_POP)
+	c.emit(OP_POP_SCOPE)
+
+	c.loops = c.loops[:len(c.loops)-1]
+
+	// continue продолжает с шага цикла: OP_LOOP_CONTINUE уже снял область итерации
+	for _, jump := range loop.continueJumps {
+		c.patchJump(jump)
+	}
+	for _, exit := range loop.exits {
+		exit.continueIP = len(c.chunk.Code)
+	}
+
+	if step != nil {
+		c.compile(step)
+		c.emit(OP_POP)
+	}
+	c.emitLoop(loopStart)
+
+	if exitJump >= 0 {
+		c.patchJump(exitJump)
+	}
+	for _, jump := range loop.breakJumps {
+		c.patchJump(jump)
+	}
+	for _, exit := range loop.exits {
+		exit.breakIP = len(c.chunk.Code)
+	}
+
+	// OP_LOOP_END восстанавливает стек и область цикла (в том числе после break)
+	c.emit(OP_LOOP_END)
+	c.emit(OP_POP_SCOPE)
+}
+
+// exitLoops находит цикл, которому адресованы break/continue с меткой label
+// (пустая - ближайший), и снимает вложенные в него циклы через OP_LOOP_UNWIND
+func (c *Compiler) exitLoops(label string) *loopContext {
+	for i := len(c.loops) - 1; i >= 0; i-- {
+		if label != "" && c.loops[i].label != label {
+			continue
+		}
+		if nested := len(c.loops) - 1 - i; nested > 0 {
+			c.emit(OP_LOOP_UNWIND, nested)
+		}
+		return c.loops[i]
+	}
+	return nil
+}
+
+// compileMatch компилирует match с литеральными паттернами и веткой '_'.
+// Структурные паттерны, связывания и guard выполняет tree-walking интерпретатор
+func (c *Compiler) compileMatch(e *ast.MatchExpr) {
+	if !isLiteralMatch(e) {
+		c.emitEvalAST(e)
+		return
+	}
+
+	var endJumps []int
+
+	c.compile(e.Value)
+
+	for _, arm := range e.Arms {
+		if isWildcard(arm.Pattern) {
+			c.emit(OP_POP)
+			c.compile(arm.Body)
+			endJumps = append(endJumps, c.emitJump(OP_JUMP))
+			continue
+		}
+
+		c.compile(arm.Pattern.(*ast.ValuePattern).Expr)
+		c.emit(OP_MATCH)
+		nextJump := c.emitJump(OP_JUMP_IF_FALSE)
+		c.emit(OP_POP)
+		c.compile(arm.Body)
+		endJumps = append(endJumps, c.emitJump(OP_JUMP))
+		c.patchJump(nextJump)
+	}
+
+	// Ни одна ветка не подошла
+	c.emit(OP_POP)
+	c.emit(OP_NIL)
+
+	for _, jump := range endJumps {
+		c.patchJump(jump)
+	}
+}
+
+// isLiteralMatch сообщает, что все ветки match - литералы или '_' без guard
+func isLiteralMatch(e *ast.MatchExpr) bool {
+	for _, arm := range e.Arms {
+		if arm.Guard != nil {
+			return false
+		}
+		if _, ok := arm.Pattern.(*ast.ValuePattern); !ok && !isWildcard(arm.Pattern) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWildcard проверяет, является ли паттерн веткой по умолчанию '_'
+func isWildcard(pattern ast.Pattern) bool {
+	_, ok := pattern.(*ast.WildcardPattern)
+	return ok
+}
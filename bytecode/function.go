@@ -0,0 +1,123 @@
+package bytecode
+
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/scope"
+	"foo_lang/value"
+)
+
+// Function представляет скомпилированную функцию foo_lang
+type Function struct {
+	Name     string
+	Params   []string
+	Defaults []*Chunk // chunk значения по умолчанию для каждого параметра (nil, если его нет)
+	Chunk    *Chunk
+	Position ast.Position // начало тела функции - позиция нового кадра стека вызовов
+}
+
+// NewFunction создает новую скомпилированную функцию
+func NewFunction(name string, params []string, defaults []*Chunk, chunk *Chunk) *Function {
+	return &Function{
+		Name:     name,
+		Params:   params,
+		Defaults: defaults,
+		Chunk:    chunk,
+	}
+}
+
+// String возвращает строковое представление функции
+func (f *Function) String() string {
+	return fmt.Sprintf("<fn %s>", f.Name)
+}
+
+// Closure - скомпилированная функция вместе с захваченной областью видимости.
+// Реализует интерфейс ast.Callable, поэтому может передаваться во встроенные
+// функции и методы (map, filter, reduce) наравне с замыканиями tree-walking интерпретатора
+type Closure struct {
+	Function *Function
+	Env      *scope.Scope // область, в которой было создано замыкание
+	stack    *scope.ScopeStack
+	runtime  *ast.Runtime
+}
+
+// NewClosure создает замыкание над текущей областью стека rt
+func NewClosure(function *Function, rt *ast.Runtime) *Closure {
+	return &Closure{
+		Function: function,
+		Env:      rt.Scope().CurrentScope(),
+		stack:    rt.Scope(),
+		runtime:  rt,
+	}
+}
+
+// Name возвращает имя функции
+func (c *Closure) Name() string {
+	return c.Function.Name
+}
+
+// String возвращает строковое представление замыкания
+func (c *Closure) String() string {
+	return c.Function.String()
+}
+
+// Call выполняет замыкание из Go-кода (встроенные функции, обработчики HTTP)
+// с собственным стеком вызовов
+func (c *Closure) Call(args []*value.Value) *value.Value {
+	return c.CallIn(c.runtime.Detached("<go>"), args)
+}
+
+// CallIn выполняет тело функции в новой области, родителем которой является
+// захваченное окружение, и в новом кадре стека вызовов rt
+func (c *Closure) CallIn(rt *ast.Runtime, args []*value.Value) *value.Value {
+	fn := c.Function
+	rt = rt.WithScope(c.stack)
+
+	prev, err := c.stack.EnterFunction(c.Env)
+	if err != nil {
+		panic(err.Error())
+	}
+	defer c.stack.LeaveFunction(prev)
+
+	result := rt.CallFrame(fn.Name, fn.Position, func() *value.Value {
+		if len(args) > len(fn.Params) {
+			panic(fmt.Sprintf("too many arguments: expected %d, got %d", len(fn.Params), len(args)))
+		}
+
+		// Устанавливаем параметры функции
+		for i, name := range fn.Params {
+			if i < len(args) {
+				c.stack.Set(name, args[i])
+			} else if fn.Defaults[i] != nil {
+				c.stack.Set(name, c.newFrame(rt, fn.Defaults[i]).execute())
+			} else {
+				panic(fmt.Sprintf("missing required argument: %s", name))
+			}
+		}
+
+		return c.newFrame(rt, fn.Chunk).execute()
+	})
+	if result == nil {
+		return value.NewNil()
+	}
+
+	// Снимаем флаг return, чтобы значение не прерывало выполнение вызывающего кода.
+	// Err, поднятый оператором ?, тоже становится обычным результатом функции
+	return value.NewValue(result.Any())
+}
+
+// frameProfiler - выключенный профайлер для кадров функций.
+// Замыкания могут вызываться из горутин (async, каналы), поэтому кадры не пишут в общий профайлер
+var frameProfiler = &Profiler{enabled: false}
+
+// newFrame создает облегченную VM для выполнения chunk'а функции на общем стеке областей
+func (c *Closure) newFrame(rt *ast.Runtime, chunk *Chunk) *VM {
+	return &VM{
+		chunk:    chunk,
+		stack:    make([]*value.Value, 0, 16),
+		globals:  make(map[string]*value.Value),
+		scope:    c.stack,
+		runtime:  rt,
+		profiler: frameProfiler,
+	}
+}
@@ -172,7 +172,7 @@ func (jit *JITCompiler) optimizeBytecode(funcName string, originalBytecode []Ins
 				return nil
 			}
 			// Fallback к стандартной реализации
-			return value.Add(a, b)
+			return value.Add(a, b, vm.runtime.OverflowCheck())
 		},
 	})
 
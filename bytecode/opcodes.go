@@ -1,6 +1,9 @@
 package bytecode
 
-import "fmt"
+import (
+	"fmt"
+	"foo_lang/ast"
+)
 
 // OpCode представляет операцию в bytecode
 type OpCode uint8
@@ -46,6 +49,7 @@ const (
 	OP_LOOP
 	OP_CALL
 	OP_RETURN
+	OP_PROPAGATE // оператор ?: Ok(v) -> v, Err возвращается из функции
 	
 	// Массивы и объекты
 	OP_ARRAY
@@ -119,13 +123,37 @@ const (
 	OP_DEBUG_TRACE
 	OP_PROFILE_START
 	OP_PROFILE_END
+	
+	// Переменные в областях видимости (используются компилятором AST)
+	OP_GET_VAR
+	OP_SET_VAR
+	OP_DEFINE_VAR
+	OP_DEFINE_FUNCTION
+	OP_PUSH_SCOPE
+	OP_POP_SCOPE
+	
+	// Циклы с yield
+	OP_LOOP_BEGIN
+	OP_LOOP_END
+	OP_LOOP_CONTINUE // снимает область итерации перед переходом к шагу цикла
+	OP_LOOP_UNWIND   // выходит из n вложенных циклов (break/continue с меткой)
+	OP_YIELD
+	
+	// Конструкции языка
+	OP_MATCH
+	OP_FORMAT
+	OP_BUILD_ARRAY
+	
+	// Выполнение AST узла, для которого нет прямой компиляции
+	OP_EVAL_AST
 )
 
 // Instruction представляет одну инструкцию bytecode
 type Instruction struct {
 	OpCode   OpCode
 	Operands []int
-	Line     int // Для отладки
+	Line     int          // Для отладки
+	Position ast.Position // позиция в исходном коде для стека вызовов (может быть пустой)
 }
 
 // Chunk представляет блок bytecode инструкций
@@ -234,6 +262,8 @@ func DisassembleInstruction(instruction *Instruction, offset int) {
 		print(fmt.Sprintf("OP_CALL %d", instruction.Operands[0]))
 	case OP_RETURN:
 		print("OP_RETURN")
+	case OP_PROPAGATE:
+		print("OP_PROPAGATE")
 	case OP_ARRAY:
 		print(fmt.Sprintf("OP_ARRAY %d", instruction.Operands[0]))
 	case OP_OBJECT:
@@ -249,7 +279,7 @@ func DisassembleInstruction(instruction *Instruction, offset int) {
 	case OP_CLOSURE:
 		print(fmt.Sprintf("OP_CLOSURE %d", instruction.Operands[0]))
 	case OP_CALL_FUNCTION:
-		print(fmt.Sprintf("OP_CALL_FUNCTION %d", instruction.Operands[0]))
+		print(fmt.Sprintf("OP_CALL_FUNCTION %v", instruction.Operands))
 	case OP_POP:
 		print("OP_POP")
 	case OP_DUP:
@@ -287,7 +317,7 @@ func DisassembleInstruction(instruction *Instruction, offset int) {
 	case OP_STRING_CONCAT:
 		print("OP_STRING_CONCAT")
 	case OP_METHOD_CALL:
-		print(fmt.Sprintf("OP_METHOD_CALL %d", instruction.Operands[0]))
+		print(fmt.Sprintf("OP_METHOD_CALL %v", instruction.Operands))
 	case OP_PROPERTY_ACCESS:
 		print(fmt.Sprintf("OP_PROPERTY_ACCESS %d", instruction.Operands[0]))
 	case OP_DEBUG_TRACE:
@@ -296,6 +326,36 @@ func DisassembleInstruction(instruction *Instruction, offset int) {
 		print(fmt.Sprintf("OP_PROFILE_START %d", instruction.Operands[0]))
 	case OP_PROFILE_END:
 		print(fmt.Sprintf("OP_PROFILE_END %d", instruction.Operands[0]))
+	case OP_GET_VAR:
+		print(fmt.Sprintf("OP_GET_VAR %d", instruction.Operands[0]))
+	case OP_SET_VAR:
+		print(fmt.Sprintf("OP_SET_VAR %d", instruction.Operands[0]))
+	case OP_DEFINE_VAR:
+		print(fmt.Sprintf("OP_DEFINE_VAR %d", instruction.Operands[0]))
+	case OP_DEFINE_FUNCTION:
+		print(fmt.Sprintf("OP_DEFINE_FUNCTION %d", instruction.Operands[0]))
+	case OP_PUSH_SCOPE:
+		print("OP_PUSH_SCOPE")
+	case OP_POP_SCOPE:
+		print("OP_POP_SCOPE")
+	case OP_LOOP_BEGIN:
+		print("OP_LOOP_BEGIN")
+	case OP_LOOP_END:
+		print("OP_LOOP_END")
+	case OP_LOOP_CONTINUE:
+		print("OP_LOOP_CONTINUE")
+	case OP_LOOP_UNWIND:
+		print(fmt.Sprintf("OP_LOOP_UNWIND %d", instruction.Operands[0]))
+	case OP_YIELD:
+		print("OP_YIELD")
+	case OP_MATCH:
+		print("OP_MATCH")
+	case OP_FORMAT:
+		print(fmt.Sprintf("OP_FORMAT %d", instruction.Operands[0]))
+	case OP_BUILD_ARRAY:
+		print(fmt.Sprintf("OP_BUILD_ARRAY %d", instruction.Operands[0]))
+	case OP_EVAL_AST:
+		print(fmt.Sprintf("OP_EVAL_AST %d", instruction.Operands[0]))
 	default:
 		print(fmt.Sprintf("Unknown opcode %d", instruction.OpCode))
 	}
@@ -2,7 +2,9 @@ package bytecode
 
 import (
 	"fmt"
+	"foo_lang/ast"
 	"foo_lang/scope"
+	"foo_lang/token"
 	"foo_lang/value"
 )
 
@@ -14,11 +16,20 @@ type VM struct {
 	sp          int                 // stack pointer
 	globals     map[string]*value.Value
 	scope       *scope.ScopeStack
+	runtime     *ast.Runtime        // Runtime для узлов, выполняемых tree-walking интерпретатором
 	callFrames  []CallFrame         // стек вызовов функций
 	profiler    *Profiler           // профайлер производительности
 	breakpoints map[int]bool        // точки останова для debugger'а
 	debugMode   bool               // режим отладки
 	jit         *JITCompiler       // JIT компилятор
+	loops       []loopFrame         // активные циклы, собирающие значения yield
+}
+
+// loopFrame хранит состояние цикла, необходимое для yield и break
+type loopFrame struct {
+	yields []any
+	sp     int          // высота стека на входе в цикл
+	scope  *scope.Scope // область видимости на входе в цикл
 }
 
 // CallFrame представляет кадр вызова функции
@@ -30,13 +41,19 @@ type CallFrame struct {
 
 // NewVM создает новую виртуальную машину
 func NewVM(chunk *Chunk, scopeStack *scope.ScopeStack) *VM {
+	return NewVMWithRuntime(chunk, ast.GlobalRuntime.WithScope(scopeStack))
+}
+
+// NewVMWithRuntime создает VM, выполняющую chunk в стеке областей и с реестрами rt
+func NewVMWithRuntime(chunk *Chunk, rt *ast.Runtime) *VM {
 	return &VM{
 		chunk:       chunk,
 		ip:          0,
 		stack:       make([]*value.Value, 0, 256),
 		sp:          0,
 		globals:     make(map[string]*value.Value),
-		scope:       scopeStack,
+		scope:       rt.Scope(),
+		runtime:     rt,
 		callFrames:  make([]CallFrame, 0, 64),
 		profiler:    NewProfiler(),
 		breakpoints: make(map[int]bool),
@@ -51,6 +68,7 @@ func (vm *VM) Reset() {
 	vm.stack = vm.stack[:0]
 	vm.sp = 0
 	vm.callFrames = vm.callFrames[:0]
+	vm.loops = vm.loops[:0]
 }
 
 // Push добавляет значение в стек
@@ -84,6 +102,17 @@ func (vm *VM) Run() *value.Value {
 	vm.profiler.StartExecution()
 	defer vm.profiler.EndExecution()
 
+	return vm.execute()
+}
+
+// execute выполняет инструкции chunk'а до конца или до OP_RETURN.
+// Ошибки выполнения приводят к panic, как и в tree-walking интерпретаторе
+func (vm *VM) execute() *value.Value {
+	var entryScope *scope.Scope
+	if vm.scope != nil {
+		entryScope = vm.scope.CurrentScope()
+	}
+
 	for vm.ip < len(vm.chunk.Code) {
 		instruction := &vm.chunk.Code[vm.ip]
 
@@ -101,9 +130,21 @@ func (vm *VM) Run() *value.Value {
 		// Профилирование инструкций
 		vm.profiler.RecordInstruction(instruction.OpCode)
 
+		// Позиция для стека вызовов foo в сообщении об ошибке
+		if instruction.Position.IsValid() {
+			vm.runtime.At(instruction.Position)
+		}
+
 		result := vm.executeInstruction(instruction)
-		if result != nil && result.IsReturn() {
-			return result
+		if result != nil {
+			if result.IsReturn() {
+				// return мог произойти внутри цикла: закрываем его области видимости
+				if entryScope != nil {
+					vm.scope.RestoreScope(entryScope)
+				}
+				return result
+			}
+			panic(result.String())
 		}
 
 		vm.ip++
@@ -140,33 +181,41 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 
 	// Арифметические операции
 	case OP_ADD:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
-			return value.Add(a, b)
+		return vm.binaryOperation(token.ADD, func(a, b *value.Value) *value.Value {
+			// Конкатенация строки с любым другим типом, как в tree-walking интерпретаторе
+			if a.IsString() || b.IsString() {
+				return value.NewString(ast.Display(vm.runtime, a) + ast.Display(vm.runtime, b))
+			}
+			return value.Add(a, b, vm.runtime.OverflowCheck())
 		})
 
 	case OP_SUBTRACT:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
-			return value.Subtract(a, b)
+		return vm.binaryOperation(token.SUB, func(a, b *value.Value) *value.Value {
+			return value.Subtract(a, b, vm.runtime.OverflowCheck())
 		})
 
 	case OP_MULTIPLY:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
-			return value.Multiply(a, b)
+		return vm.binaryOperation(token.MUL, func(a, b *value.Value) *value.Value {
+			return value.Multiply(a, b, vm.runtime.OverflowCheck())
 		})
 
 	case OP_DIVIDE:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.QUO, func(a, b *value.Value) *value.Value {
 			return value.Divide(a, b)
 		})
 
 	case OP_MODULO:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.REM, func(a, b *value.Value) *value.Value {
 			return value.Modulo(a, b)
 		})
 
 	case OP_NEGATE:
 		operand := vm.Pop()
-		vm.Push(value.Negate(operand))
+		if result, ok := ast.OverloadedNegate(vm.runtime, operand); ok {
+			vm.Push(result)
+			return nil
+		}
+		vm.Push(value.Negate(operand, vm.runtime.OverflowCheck()))
 
 	// Логические операции
 	case OP_NOT:
@@ -174,43 +223,43 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 		vm.Push(value.Not(operand))
 
 	case OP_AND:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.AND_AND, func(a, b *value.Value) *value.Value {
 			return value.And(a, b)
 		})
 
 	case OP_OR:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.OR_OR, func(a, b *value.Value) *value.Value {
 			return value.Or(a, b)
 		})
 
 	// Операции сравнения
 	case OP_EQUAL:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.EQ_EQ, func(a, b *value.Value) *value.Value {
 			return value.Equal(a, b)
 		})
 
 	case OP_NOT_EQUAL:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.NOT_EQ, func(a, b *value.Value) *value.Value {
 			return value.NotEqual(a, b)
 		})
 
 	case OP_GREATER:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.GT, func(a, b *value.Value) *value.Value {
 			return value.Greater(a, b)
 		})
 
 	case OP_GREATER_EQUAL:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.GT_EQ, func(a, b *value.Value) *value.Value {
 			return value.GreaterEqual(a, b)
 		})
 
 	case OP_LESS:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.LT, func(a, b *value.Value) *value.Value {
 			return value.Less(a, b)
 		})
 
 	case OP_LESS_EQUAL:
-		return vm.binaryOperation(func(a, b *value.Value) *value.Value {
+		return vm.binaryOperation(token.LT_EQ, func(a, b *value.Value) *value.Value {
 			return value.LessEqual(a, b)
 		})
 
@@ -279,18 +328,27 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 	case OP_INDEX:
 		index := vm.Pop()
 		obj := vm.Pop()
-		result := value.Index(obj, index)
-		vm.Push(result)
+		if result, ok := ast.OverloadedIndex(vm.runtime, obj, index); ok {
+			vm.Push(result)
+			return nil
+		}
+		switch obj.Any().(type) {
+		case string, []*value.Value:
+			vm.Push(value.Index(obj, index))
+		default:
+			// Массивы, объекты, кортежи, List и Map - как в tree-walking интерпретаторе
+			vm.Push(ast.GetIndex(obj, index))
+		}
 
 	// Встроенные функции
 	case OP_PRINT:
 		val := vm.Pop()
-		fmt.Print(val.String())
+		fmt.Print(ast.Display(vm.runtime, val))
 		vm.Push(value.NewNil())
 
 	case OP_PRINTLN:
 		val := vm.Pop()
-		fmt.Println(val.String())
+		fmt.Println(ast.Display(vm.runtime, val))
 		vm.Push(value.NewNil())
 
 	// Профилинг
@@ -324,13 +382,15 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 	// Объекты
 	case OP_OBJECT:
 		size := instruction.Operands[0]
-		obj := make(map[string]*value.Value)
+		pairs := make([]*value.Value, 2*size)
 		
 		// Извлекаем пары ключ-значение из стека
-		for i := 0; i < size; i++ {
-			val := vm.Pop()
-			key := vm.Pop()
-			obj[key.String()] = val
+		for i := 2*size - 1; i >= 0; i-- {
+			pairs[i] = vm.Pop()
+		}
+		obj := value.NewObject()
+		for i := 0; i < len(pairs); i += 2 {
+			obj.Set(pairs[i].String(), pairs[i+1])
 		}
 		vm.Push(value.NewValue(obj))
 
@@ -355,32 +415,38 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 		// Извлекаем функцию
 		function := vm.Pop()
 		
-		// Вызываем функцию (пока заглушка)
-		result := vm.callFunction(function, args)
-		vm.Push(result)
+		vm.Push(vm.callFunction(function, args))
 
 	case OP_RETURN:
-		// Возвращаем значение из функции
-		result := vm.Pop()
+		// Возвращаем копию значения, чтобы флаг return не попал в переменную
+		result := value.NewValue(vm.Pop().Any())
 		result.SetReturn(true)
 		return result
 
-	// Замыкания (заглушка)
+	case OP_PROPAGATE:
+		// Err поднимается до Closure.Call так же, как в tree-walking интерпретаторе
+		vm.Push(ast.Propagate(vm.Pop()))
+
+	// Замыкания
 	case OP_CLOSURE:
 		functionIndex := instruction.Operands[0]
-		function := vm.chunk.Constants[functionIndex]
-		vm.Push(value.FromInterface(function))
+		switch function := vm.chunk.Constants[functionIndex].(type) {
+		case *Function:
+			vm.Push(value.NewValue(NewClosure(function, vm.runtime)))
+		default:
+			vm.Push(value.FromInterface(function))
+		}
 
 	case OP_CALL_FUNCTION:
-		argCount := instruction.Operands[0]
-		// Аналогично OP_CALL, но для именованных функций
+		// Вызов функции по имени: операнды - индекс имени и количество аргументов.
+		// Разрешение имени (перегрузки, встроенные функции) общее с tree-walking интерпретатором
+		name := vm.chunk.Constants[instruction.Operands[0]].(string)
+		argCount := instruction.Operands[1]
 		args := make([]*value.Value, argCount)
 		for i := argCount - 1; i >= 0; i-- {
 			args[i] = vm.Pop()
 		}
-		function := vm.Pop()
-		result := vm.callFunction(function, args)
-		vm.Push(result)
+		vm.Push(normalizeResult(ast.CallFunction(vm.runtime, name, args)))
 
 	// Async/await (заглушки)
 	case OP_ASYNC:
@@ -543,20 +609,26 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 			vm.Push(value.NewString("Error: invalid substring indices"))
 		}
 
-	// Методы и свойства (заглушки)
+	// Методы и свойства
 	case OP_METHOD_CALL:
 		methodIndex := instruction.Operands[0]
 		methodName := vm.chunk.Constants[methodIndex].(string)
+		argCount := 0
+		if len(instruction.Operands) > 1 {
+			argCount = instruction.Operands[1]
+		}
+		args := make([]*value.Value, argCount)
+		for i := argCount - 1; i >= 0; i-- {
+			args[i] = vm.Pop()
+		}
 		obj := vm.Pop()
-		fmt.Printf("Method call: %s.%s()\n", obj.String(), methodName)
-		vm.Push(value.NewNil())
+		vm.Push(normalizeResult(ast.CallMethod(vm.runtime, obj, methodName, args)))
 
 	case OP_PROPERTY_ACCESS:
 		propIndex := instruction.Operands[0]
 		propName := vm.chunk.Constants[propIndex].(string)
 		obj := vm.Pop()
-		fmt.Printf("Property access: %s.%s\n", obj.String(), propName)
-		vm.Push(value.NewNil())
+		vm.Push(ast.GetMember(obj, propName))
 
 	// Типы и метапрограммирование
 	case OP_TYPE_OF:
@@ -579,6 +651,112 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 		}
 		fmt.Println()
 
+	// Переменные в областях видимости
+	case OP_GET_VAR:
+		name := vm.chunk.Constants[instruction.Operands[0]].(string)
+		val, exists := vm.scope.Get(name)
+		if !exists {
+			return value.NewString("variable " + name + " is not defined")
+		}
+		vm.Push(val)
+
+	case OP_SET_VAR:
+		name := vm.chunk.Constants[instruction.Operands[0]].(string)
+		current, exists := vm.scope.Get(name)
+		if !exists {
+			return value.NewString("variable " + name + " is not defined")
+		}
+		if current.IsConst() {
+			return value.NewString("cannot assign to constant " + name)
+		}
+		vm.scope.Update(name, vm.Peek(0)) // не извлекаем из стека
+
+	case OP_DEFINE_VAR:
+		name := vm.chunk.Constants[instruction.Operands[0]].(string)
+		if vm.scope.Has(name) {
+			return value.NewString("variable " + name + " is already defined")
+		}
+		vm.scope.Set(name, vm.Pop())
+
+	case OP_DEFINE_FUNCTION:
+		// Объявление функции перезаписывает значение, зарегистрированное парсером
+		name := vm.chunk.Constants[instruction.Operands[0]].(string)
+		vm.scope.Set(name, vm.Pop())
+
+	case OP_PUSH_SCOPE:
+		vm.scope.Push()
+
+	case OP_POP_SCOPE:
+		vm.scope.Pop()
+
+	// Циклы
+	case OP_LOOP_BEGIN:
+		vm.loops = append(vm.loops, loopFrame{sp: vm.sp, scope: vm.scope.CurrentScope()})
+
+	case OP_LOOP_END:
+		// Сюда же попадает break: восстанавливаем стек и область видимости цикла
+		frame := vm.loops[len(vm.loops)-1]
+		vm.loops = vm.loops[:len(vm.loops)-1]
+		vm.stack = vm.stack[:frame.sp]
+		vm.sp = frame.sp
+		vm.scope.RestoreScope(frame.scope)
+		vm.Push(value.NewValue(frame.yields))
+
+	case OP_LOOP_CONTINUE:
+		vm.continueLoop()
+
+	case OP_LOOP_UNWIND:
+		vm.unwindLoops(instruction.Operands[0])
+
+	case OP_YIELD:
+		val := vm.Pop()
+		if len(vm.loops) > 0 {
+			frame := &vm.loops[len(vm.loops)-1]
+			frame.yields = append(frame.yields, val.Any())
+		}
+		vm.Push(value.NewNil())
+
+	// Конструкции языка
+	case OP_MATCH:
+		pattern := vm.Pop()
+		subject := vm.Peek(0)
+		vm.Push(value.NewBool(ast.MatchPattern(subject, pattern)))
+
+	case OP_FORMAT:
+		count := instruction.Operands[0]
+		parts := make([]*value.Value, count)
+		for i := count - 1; i >= 0; i-- {
+			parts[i] = vm.Pop()
+		}
+		var result string
+		for _, part := range parts {
+			result += ast.Display(vm.runtime, part)
+		}
+		vm.Push(value.NewString(result))
+
+	case OP_BUILD_ARRAY:
+		// Массив в представлении tree-walking интерпретатора ([]any)
+		size := instruction.Operands[0]
+		elements := make([]any, size)
+		for i := size - 1; i >= 0; i-- {
+			elements[i] = vm.Pop().Any()
+		}
+		vm.Push(value.NewValue(elements))
+
+	case OP_EVAL_AST:
+		node := vm.chunk.Constants[instruction.Operands[0]].(ast.Expr)
+		result := vm.evalNode(node)
+		if result.IsReturn() {
+			return result
+		}
+		if len(instruction.Operands) > 1 && (result.IsBreak() || result.IsContinue()) {
+			exits := vm.chunk.Constants[instruction.Operands[1]].([]*loopExit)
+			if vm.exitLoop(exits, result) {
+				return nil
+			}
+		}
+		vm.Push(result)
+
 	default:
 		return value.NewString(fmt.Sprintf("Error: unknown opcode %d", instruction.OpCode))
 	}
@@ -586,10 +764,76 @@ func (vm *VM) executeInstruction(instruction *Instruction) *value.Value {
 	return nil
 }
 
+// continueLoop возвращает стек и область видимости к состоянию между итерациями
+func (vm *VM) continueLoop() {
+	frame := vm.loops[len(vm.loops)-1]
+	vm.stack = vm.stack[:frame.sp]
+	vm.sp = frame.sp
+	vm.scope.RestoreScope(frame.scope)
+}
+
+// unwindLoops завершает n вложенных циклов без значения (break/continue с меткой)
+func (vm *VM) unwindLoops(n int) {
+	frame := vm.loops[len(vm.loops)-n]
+	vm.loops = vm.loops[:len(vm.loops)-n]
+	vm.stack = vm.stack[:frame.sp]
+	vm.sp = frame.sp
+	vm.scope.RestoreScope(frame.scope)
+}
+
+// exitLoop передает break/continue из узла tree-walking интерпретатора циклу VM:
+// без метки - ближайшему, с меткой - циклу с этой меткой. Возвращает false,
+// если подходящего цикла в chunk'е нет
+func (vm *VM) exitLoop(exits []*loopExit, signal *value.Value) bool {
+	label := signal.Label()
+	for _, exit := range exits {
+		if label != "" && exit.label != label {
+			continue
+		}
+		if exit.nested > 0 {
+			vm.unwindLoops(exit.nested)
+		}
+		if signal.IsContinue() {
+			vm.continueLoop()
+			vm.ip = exit.continueIP - 1 // -1 потому что ip++ в основном цикле
+		} else {
+			vm.ip = exit.breakIP - 1
+		}
+		return true
+	}
+	return false
+}
+
+// evalNode выполняет AST узел tree-walking интерпретатором на стеке областей VM
+func (vm *VM) evalNode(node ast.Expr) *value.Value {
+	result := node.Eval(vm.runtime)
+	if result == nil {
+		return value.NewNil()
+	}
+	return result
+}
+
+// normalizeResult приводит результат вызова к значению стека: nil становится null,
+// а флаг return снимается, чтобы не прервать выполнение вызывающего кода
+func normalizeResult(result *value.Value) *value.Value {
+	if result == nil {
+		return value.NewNil()
+	}
+	if result.IsReturn() {
+		return value.NewValue(result.Any())
+	}
+	return result
+}
+
 // binaryOperation выполняет бинарную операцию с двумя операндами из стека
-func (vm *VM) binaryOperation(op func(a, b *value.Value) *value.Value) *value.Value {
+// Структуры с impl Add, Eq, Ord ... вычисляют оператор tok своими методами
+func (vm *VM) binaryOperation(tok token.Token, op func(a, b *value.Value) *value.Value) *value.Value {
 	b := vm.Pop()
 	a := vm.Pop()
+	if result, ok := ast.OverloadedBinary(vm.runtime, tok, a, b); ok {
+		vm.Push(result)
+		return nil
+	}
 	result := op(a, b)
 	vm.Push(result)
 	return nil
@@ -609,52 +853,15 @@ func (vm *VM) PrintStack() {
 	fmt.Println()
 }
 
-// callFunction вызывает функцию с заданными аргументами
+// callFunction вызывает функциональное значение с заданными аргументами
 func (vm *VM) callFunction(function *value.Value, args []*value.Value) *value.Value {
-	// Пока это заглушка - в полной реализации здесь будет создание CallFrame,
-	// установка локальных переменных и выполнение тела функции
-	
-	functionName := function.String()
-	
-	// Простые встроенные функции
-	switch functionName {
-	case "print":
-		if len(args) > 0 {
-			fmt.Print(args[0].String())
-		}
-		return value.NewNil()
-		
-	case "println":
-		if len(args) > 0 {
-			fmt.Println(args[0].String())
-		}
-		return value.NewNil()
-		
-	case "len":
-		if len(args) > 0 {
-			arg := args[0]
-			if arg.IsString() {
-				return value.NewValue(int64(len(arg.String())))
-			} else {
-				// Для других типов пробуем привести к массиву
-				if arr, ok := arg.Any().([]interface{}); ok {
-					return value.NewValue(int64(len(arr)))
-				}
-			}
-		}
-		return value.NewValue(int64(0))
-		
-	case "type":
-		if len(args) > 0 {
-			return value.NewValue("unknown") // В реальной реализации нужно добавить метод TypeString в value.Value
-		}
-		return value.NewValue("unknown")
-		
-	default:
-		// Для пользовательских функций - заглушка
-		fmt.Printf("Calling user function: %s with %d args\n", functionName, len(args))
-		return value.NewValue("function_result")
+	switch fn := function.Any().(type) {
+	case ast.Callable:
+		return normalizeResult(ast.CallIn(vm.runtime, fn, args))
+	case func([]*value.Value) *value.Value:
+		return normalizeResult(fn(args))
 	}
+	panic(fmt.Sprintf("'%s' is not a function", function.String()))
 }
 
 // SetBreakpoint устанавливает точку останова для debugger'а
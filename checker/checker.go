@@ -0,0 +1,1063 @@
+// Package checker - статическая проверка типов программы foo_lang без ее выполнения.
+//
+// Проверка обходит AST после разбора: выводит типы нетипизированных let,
+// сверяет количество и типы аргументов вызовов с сигнатурами типизированных
+// и generic функций, проверяет соответствие impl блоков интерфейсам и
+// совместимость union/optional типов. Неизвестный тип выражения (результат
+// встроенной функции, параметр без аннотации) совместим с любым типом,
+// поэтому проверка не мешает динамическому коду
+package checker
+
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/token"
+	"sort"
+	"strings"
+)
+
+// Error - ошибка типов с позицией в исходном коде
+type Error struct {
+	Pos     ast.Position
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// Check проверяет разобранную программу и возвращает все найденные ошибки,
+// упорядоченные по позиции
+func Check(exprs []ast.Expr) []Error {
+	c := &checker{
+		scopes:     []map[string]*binding{{}},
+		funcs:      map[string][]*signature{},
+		structs:    map[string]*ast.StructDefExpr{},
+		enums:      map[string]bool{},
+		aliases:    map[string]string{},
+		interfaces: map[string]*ast.InterfaceDefinition{},
+		impls:      map[string][]*ast.ImplBlock{},
+		imported:   map[string]bool{},
+		declared:   map[ast.Expr]bool{},
+	}
+	for _, iface := range ast.OperatorInterfaces() {
+		c.interfaces[iface.Name] = iface
+	}
+
+	// Функции и типы доступны до места объявления
+	for _, expr := range exprs {
+		c.declare(expr)
+	}
+	for _, expr := range exprs {
+		c.expr(expr)
+	}
+
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		a, b := c.errors[i].Pos, c.errors[j].Pos
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	return c.errors
+}
+
+// signature - сигнатура функции или метода impl блока
+type signature struct {
+	name       string
+	params     []ast.FuncParam
+	returnType string
+	typeParams []ast.TypeConstraint // только у generic функций
+}
+
+// binding - переменная в области видимости проверки
+type binding struct {
+	typ      string
+	declared bool // тип задан аннотацией и проверяется при присваивании
+}
+
+type checker struct {
+	errors []Error
+	scopes []map[string]*binding
+
+	funcs      map[string][]*signature // перегрузки по имени
+	structs    map[string]*ast.StructDefExpr
+	enums      map[string]bool
+	aliases    map[string]string // псевдоним -> базовый тип
+	interfaces map[string]*ast.InterfaceDefinition
+	impls      map[string][]*ast.ImplBlock // имя типа -> его impl блоки
+
+	imported  map[string]bool // имена из import { ... } from
+	importAll bool            // import "..." без списка: типы модуля неизвестны
+	declared  map[ast.Expr]bool
+
+	fn         *signature // функция, тело которой проверяется сейчас
+	typeParams []string   // параметры типа объемлющей generic структуры или impl блока
+}
+
+func (c *checker) errorf(expr ast.Expr, format string, args ...interface{}) {
+	c.errors = append(c.errors, Error{Pos: ast.PositionOf(expr), Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) push() {
+	c.scopes = append(c.scopes, map[string]*binding{})
+}
+
+func (c *checker) pop() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *checker) define(name, typ string, declared bool) {
+	c.scopes[len(c.scopes)-1][name] = &binding{typ: typ, declared: declared}
+}
+
+func (c *checker) lookup(name string) (*binding, int) {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if b, ok := c.scopes[i][name]; ok {
+			return b, i
+		}
+	}
+	return nil, -1
+}
+
+// declare регистрирует объявления функций и типов до проверки тел
+func (c *checker) declare(expr ast.Expr) {
+	if c.declared[expr] {
+		return
+	}
+
+	switch e := expr.(type) {
+	case *ast.ExportExpr:
+		c.declare(e.Declaration)
+		return
+	case *ast.FuncStatment:
+		c.funcs[e.Name()] = append(c.funcs[e.Name()], &signature{name: e.Name(), params: untypedParams(e.Args())})
+	case *ast.TypedFuncStatement:
+		c.funcs[e.FuncName] = append(c.funcs[e.FuncName], &signature{
+			name: e.FuncName, params: e.Params, returnType: e.ReturnType,
+		})
+	case *ast.GenericFuncStatement:
+		c.funcs[e.FuncName] = append(c.funcs[e.FuncName], &signature{
+			name: e.FuncName, params: e.Params, returnType: e.ReturnType, typeParams: e.TypeParams,
+		})
+	case *ast.StructDefExpr:
+		c.structs[e.Name] = e
+	case *ast.EnumExpr:
+		c.enums[e.Name] = true
+	case *ast.TypeAliasExpr:
+		c.aliases[e.AliasName] = e.BaseType
+	case *ast.InterfaceDefinition:
+		c.interfaces[e.Name] = e
+	case *ast.ImplBlock:
+		c.impls[e.TypeName] = append(c.impls[e.TypeName], e)
+	case *ast.ImportExpr:
+		if len(e.ImportedItems) > 0 || e.DefaultName != "" {
+			for _, binding := range e.Bindings() {
+				c.imported[binding.Local] = true
+			}
+		} else if e.AliasName == "" {
+			c.importAll = true
+		}
+	default:
+		return
+	}
+	c.declared[expr] = true
+}
+
+// expr проверяет выражение и возвращает его тип ("" - тип неизвестен)
+func (c *checker) expr(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case nil:
+		return ""
+
+	// Литералы
+	case *ast.IntExpr:
+		return "int"
+	case *ast.FloatExpr:
+		return "float"
+	case *ast.BigIntExpr:
+		return "bigint"
+	case *ast.BoolExpr:
+		return "bool"
+	case *ast.LiteralString, *ast.TemplateExpr:
+		return "string"
+	case *ast.StringFormatExpr:
+		for _, part := range e.Parts() {
+			c.expr(part)
+		}
+		return "string"
+	case *ast.NullExpr:
+		return "null"
+	case *ast.ArrayExpr:
+		for _, element := range e.Elements {
+			c.expr(element)
+		}
+		return "array"
+	case *ast.ObjectExpr:
+		for _, field := range e.Fields {
+			c.expr(field)
+		}
+		return "object"
+	case *ast.TupleExpr:
+		// Тип кортежа из типов элементов: (int,string)
+		items := make([]string, len(e.Elements))
+		for i, element := range e.Elements {
+			items[i] = orAny(c.expr(element))
+		}
+		return "(" + strings.Join(items, ",") + ")"
+	case *ast.SetExpr:
+		for _, element := range e.Elements {
+			c.expr(element)
+		}
+		return "set"
+	case *ast.StructInstanceExpr:
+		return c.structInstance(e)
+
+	// Переменные
+	case *ast.VarExpr:
+		if e.GetExpr() == nil {
+			if b, _ := c.lookup(e.Name); b != nil {
+				return b.typ
+			}
+			return ""
+		}
+		if b, _ := c.lookup(e.Name); b != nil && b.declared {
+			return c.assign(e, e.Name, c.typed(e.GetExpr(), b.typ))
+		}
+		return c.assign(e, e.Name, c.expr(e.GetExpr()))
+	case *ast.LetExpr:
+		c.define(e.GetName(), c.expr(e.GetExpr()), false)
+	case *ast.ConstExpr:
+		c.define(e.GetName(), c.expr(e.GetExpr()), false)
+	case *ast.TypedLetExpr:
+		typ := e.GetType()
+		c.checkTypeExists(e, typ)
+		if got := c.typed(e.GetExpr(), typ); !c.assignable(got, typ) {
+			c.errorf(e, "variable '%s' type error: expected %s, got %s", e.GetName(), typ, got)
+		}
+		c.define(e.GetName(), typ, true)
+	case *ast.MultiAssignExpr:
+		c.expr(e.Expr)
+		for _, name := range e.Names {
+			c.define(name, "", false)
+		}
+
+	// Операторы
+	case *ast.BinaryExpr:
+		return c.binary(e)
+	case *ast.UnaryOpExpr:
+		operand := c.expr(e.Expr)
+		if e.Op == '!' {
+			return "bool"
+		}
+		return operand
+	case *ast.ConditionalExpr:
+		c.expr(e.Condition)
+		then, other := c.expr(e.ThenBranch), c.expr(e.ElseBranch)
+		if then == other {
+			return then
+		}
+	case *ast.RangeExpr:
+		start, end := c.expr(e.Start), c.expr(e.End)
+		if !c.assignable(start, "int") || !c.assignable(end, "int") {
+			c.errorf(e, "range bounds must be int, got %s..%s", orAny(start), orAny(end))
+		}
+
+	// Управление потоком
+	case *ast.BodyExpr:
+		c.block(e.Statments)
+	case *ast.IfExpr:
+		c.ifExpr(e)
+	case *ast.ForExpr:
+		c.push()
+		c.expr(e.InitExpr)
+		c.expr(e.ConditionExpr)
+		c.expr(e.StepExpr)
+		c.expr(e.BodyExpr)
+		c.pop()
+	case *ast.ForInExpr:
+		c.expr(e.Iterable)
+		c.push()
+		if e.KeyName != "" {
+			c.define(e.KeyName, "", false)
+		}
+		c.define(e.ItemName, "", false)
+		c.expr(e.BodyExpr)
+		c.pop()
+	case *ast.WhileExpr:
+		c.expr(e.ConditionExpr)
+		c.expr(e.BodyExpr)
+	case *ast.LoopExpr:
+		c.expr(e.BodyExpr)
+	case *ast.MatchExpr:
+		c.expr(e.Value)
+		for _, arm := range e.Arms {
+			c.push()
+			for _, name := range patternNames(arm.Pattern) {
+				c.define(name, "", false)
+			}
+			c.expr(arm.Guard)
+			c.expr(arm.Body)
+			c.pop()
+		}
+	case *ast.TryExpr:
+		c.expr(e.Body)
+		if e.Catch != nil {
+			c.push()
+			if e.CatchName != "" {
+				c.define(e.CatchName, "", false)
+			}
+			c.expr(e.Catch)
+			c.pop()
+		}
+		c.expr(e.Finally)
+	case *ast.DeferExpr:
+		if c.fn == nil {
+			c.errorf(e, "defer outside of function")
+		}
+		c.expr(e.Expr)
+	case *ast.ReturnExpr:
+		returnType := ""
+		if c.fn != nil {
+			returnType = c.fn.returnType
+		}
+		got := c.typed(e.Expr, returnType)
+		if c.fn != nil && c.fn.returnType != "" && !c.assignable(got, c.fn.returnType) {
+			c.errorf(e, "function '%s' return type error: expected %s, got %s", c.fn.name, c.fn.returnType, got)
+		}
+	case *ast.YieldExpr:
+		c.expr(e.Expr)
+	case *ast.BreakExpr:
+		c.expr(e.Expr)
+	case *ast.PrintExpr:
+		c.expr(e.Expr)
+		for _, arg := range e.Args {
+			c.expr(arg)
+		}
+	case *ast.PropagateExpr:
+		c.expr(e.Expr)
+	case *ast.AsyncExpr:
+		c.expr(e.Expr)
+	case *ast.AwaitExpr:
+		c.expr(e.Expr)
+
+	// Функции
+	case *ast.FuncStatment:
+		c.declare(e)
+		c.function(e, &signature{name: e.Name()}, untypedParams(e.Args()), e.Body(), "")
+	case *ast.AnonymousFunc:
+		c.function(e, &signature{name: "(anonymous)"}, untypedParams(e.Args()), e.Body(), "")
+	case *ast.TypedFuncStatement:
+		c.declare(e)
+		c.typedFunction(e, "")
+	case *ast.GenericFuncStatement:
+		c.declare(e)
+		sig := &signature{name: e.FuncName, params: e.Params, returnType: e.ReturnType, typeParams: e.TypeParams}
+		c.function(e, sig, e.Params, e.Body, "")
+	case *ast.FuncCallExpr:
+		return c.call(e)
+	case *ast.MethodCallExpr:
+		return c.methodCall(e)
+	case *ast.MemberExpr:
+		return c.member(e)
+	case *ast.MemberAssignExpr:
+		object, got := c.expr(e.Object), c.expr(e.Value)
+		if expected := c.fieldType(object, e.Property); !c.assignable(got, expected) {
+			c.errorf(e, "struct %s field '%s': expected %s, got %s", object, e.Property, expected, got)
+		}
+	case *ast.IndexExpr:
+		c.expr(e.Object)
+		c.expr(e.Index)
+
+	// Объявления типов
+	case *ast.ExportExpr:
+		return c.expr(e.Declaration)
+	case *ast.ImportExpr:
+		c.declare(e)
+	case *ast.StructDefExpr:
+		c.declare(e)
+		outer := c.typeParams
+		c.typeParams = ast.TypeParamNames(e.TypeParams)
+		defer func() { c.typeParams = outer }()
+		for _, field := range e.Fields {
+			if typeExpr, ok := field.(*ast.TypeExpr); !ok {
+				c.expr(field)
+			} else if typeExpr.TypeName != "any" {
+				c.checkTypeExists(e, typeExpr.TypeName)
+			}
+		}
+	case *ast.EnumExpr:
+		c.declare(e)
+	case *ast.TypeAliasExpr:
+		c.declare(e)
+		c.checkTypeExists(e, e.BaseType)
+	case *ast.InterfaceDefinition:
+		c.declare(e)
+	case *ast.ImplBlock:
+		c.declare(e)
+		c.impl(e)
+	}
+
+	return ""
+}
+
+// block проверяет последовательность выражений в собственной области видимости
+func (c *checker) block(statements []ast.Expr) {
+	c.push()
+	defer c.pop()
+
+	for _, stmt := range statements {
+		c.declare(stmt)
+	}
+	for _, stmt := range statements {
+		c.expr(stmt)
+	}
+}
+
+// typed вычисляет тип выражения, стоящего там, где ожидается тип expected.
+// Элементы литерала массива проверяются по типу элемента Array<T>:
+// let xs: Array<int> = [1, "a"] - ошибка во втором элементе
+func (c *checker) typed(expr ast.Expr, expected string) string {
+	array, ok := expr.(*ast.ArrayExpr)
+	element := c.elementType(expected)
+	if !ok || element == "" {
+		return c.expr(expr)
+	}
+
+	for i, item := range array.Elements {
+		if got := c.typed(item, element); !c.assignable(got, element) {
+			c.errorf(item, "array element %d type error: expected %s, got %s", i, element, got)
+		}
+	}
+	return "array"
+}
+
+// elementType возвращает T из Array<T> (в том числе из Array<T>|null) или ""
+func (c *checker) elementType(typ string) string {
+	element := ""
+	for _, member := range c.members(typ) {
+		name, args := ast.SplitGenericType(member)
+		if (name == "Array" || name == "array") && len(args) == 1 {
+			if element != "" {
+				return ""
+			}
+			element = args[0]
+		}
+	}
+	return element
+}
+
+// assign проверяет присваивание существующей переменной
+func (c *checker) assign(expr ast.Expr, name, got string) string {
+	b, _ := c.lookup(name)
+	switch {
+	case b == nil:
+	case b.declared:
+		if !c.assignable(got, b.typ) {
+			c.errorf(expr, "variable '%s' type error: expected %s, got %s", name, b.typ, got)
+		}
+	case b.typ != got:
+		// Переменная без аннотации может менять тип: дальше он неизвестен
+		b.typ = ""
+	}
+	return got
+}
+
+func (c *checker) binary(e *ast.BinaryExpr) string {
+	left, right := c.expr(e.Left), c.expr(e.Right)
+
+	if typ, ok := c.overloaded(e.Op, left); ok {
+		return typ
+	}
+
+	switch e.Op {
+	case token.ADD:
+		if left == "string" || right == "string" {
+			return "string"
+		}
+		return arithmetic(left, right)
+	case token.SUB, token.MUL, token.QUO, token.REM:
+		return arithmetic(left, right)
+	case token.GT, token.LT, token.GT_EQ, token.LT_EQ, token.EQ_EQ, token.NOT_EQ,
+		token.AND_AND, token.OR_OR:
+		return "bool"
+	case token.AND, token.OR, token.XOR, token.AND_NOT, token.LT_LT, token.GT_GT:
+		return "int"
+	}
+	return ""
+}
+
+// operatorInterfaces - встроенные интерфейсы арифметических операторов и их методы
+var operatorInterfaces = map[token.Token][2]string{
+	token.ADD: {"Add", "add"},
+	token.SUB: {"Sub", "sub"},
+	token.MUL: {"Mul", "mul"},
+	token.QUO: {"Div", "div"},
+	token.REM: {"Rem", "rem"},
+}
+
+// overloaded выводит тип арифметики над структурой с impl Add, Sub ...:
+// результат метода оператора, например Vec для fn add(other: Vec) -> Vec
+func (c *checker) overloaded(op token.Token, left string) (string, bool) {
+	iface, ok := operatorInterfaces[op]
+	if !ok {
+		return "", false
+	}
+	base, typeArgs := ast.SplitGenericType(left)
+	for _, impl := range c.impls[base] {
+		if impl.InterfaceName != iface[0] {
+			continue
+		}
+		if method := c.implMethod(impl, iface[1]); method != nil {
+			return c.substitute(method.ReturnType, c.implTypeParams(impl), typeArgs), true
+		}
+	}
+	return "", false
+}
+
+// arithmetic выводит тип результата арифметики: int с int дает int, с float - float.
+// BigInt с int остается bigint, а decimal (или bigint с float) дает decimal
+func arithmetic(left, right string) string {
+	numeric := func(typ string) bool {
+		return typ == "int" || typ == "float" || typ == "bigint" || typ == "decimal"
+	}
+	switch {
+	case left == "int" && right == "int":
+		return "int"
+	case (left == "int" || left == "float") && (right == "int" || right == "float"):
+		return "float"
+	case (left == "int" || left == "bigint") && (right == "int" || right == "bigint"):
+		return "bigint"
+	case numeric(left) && numeric(right):
+		return "decimal"
+	}
+	return ""
+}
+
+// ifExpr проверяет ветки if; сравнение переменной с null сужает ее тип в ветке
+func (c *checker) ifExpr(e *ast.IfExpr) {
+	for i, cond := range e.Condition {
+		c.expr(cond)
+
+		name, op := nullCheck(cond)
+		if op == token.NOT_EQ {
+			c.narrowed(name, e.Then[i])
+		} else {
+			c.expr(e.Then[i])
+		}
+
+		if op == token.EQ_EQ && len(e.Condition) == 1 {
+			c.narrowed(name, e.Else)
+			return
+		}
+	}
+	c.expr(e.Else)
+}
+
+// narrowed проверяет ветку, в которой переменная name заведомо не null
+func (c *checker) narrowed(name string, body ast.Expr) {
+	b, _ := c.lookup(name)
+	if b == nil {
+		c.expr(body)
+		return
+	}
+
+	var rest []string
+	for _, member := range c.members(b.typ) {
+		if member != "null" {
+			rest = append(rest, member)
+		}
+	}
+
+	c.push()
+	c.define(name, strings.Join(rest, "|"), false)
+	c.expr(body)
+	c.pop()
+}
+
+// nullCheck распознает условия x != null и x == null
+func nullCheck(cond ast.Expr) (string, token.Token) {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || (bin.Op != token.NOT_EQ && bin.Op != token.EQ_EQ) {
+		return "", token.ILLEGAL
+	}
+
+	left, right := bin.Left, bin.Right
+	if _, isNull := left.(*ast.NullExpr); isNull {
+		left, right = right, left
+	}
+	v, isVar := left.(*ast.VarExpr)
+	if _, isNull := right.(*ast.NullExpr); !isNull || !isVar || v.GetExpr() != nil {
+		return "", token.ILLEGAL
+	}
+	return v.Name, bin.Op
+}
+
+// function проверяет тело функции decl с параметрами params; this задает тип
+// получателя для методов impl блока
+func (c *checker) function(decl ast.Expr, sig *signature, params []ast.FuncParam, body ast.Expr, this string) {
+	outer := c.fn
+	c.fn = sig
+	c.push()
+	defer func() {
+		c.pop()
+		c.fn = outer
+	}()
+
+	if this != "" {
+		c.define("this", this, false)
+	}
+	for _, param := range params {
+		if param.TypeName != "" {
+			c.checkTypeExists(decl, param.TypeName)
+		}
+		if param.Default != nil {
+			if got := c.expr(param.Default); param.TypeName != "" && !c.assignable(got, param.TypeName) {
+				c.errorf(param.Default, "function '%s' parameter '%s' default: expected %s, got %s", sig.name, param.Name, param.TypeName, got)
+			}
+		}
+		c.define(param.Name, param.TypeName, param.TypeName != "")
+	}
+	if sig.returnType != "" {
+		c.checkTypeExists(decl, sig.returnType)
+	}
+
+	c.expr(body)
+}
+
+func (c *checker) typedFunction(f *ast.TypedFuncStatement, this string) {
+	sig := &signature{name: f.FuncName, params: f.Params, returnType: f.ReturnType}
+	c.function(f, sig, f.Params, f.Body, this)
+}
+
+// untypedParams переводит параметры обычной функции в FuncParam без типов
+func untypedParams(args []map[string]ast.Expr) []ast.FuncParam {
+	params := make([]ast.FuncParam, 0, len(args))
+	for _, arg := range args {
+		for name, def := range arg {
+			params = append(params, ast.FuncParam{Name: name, Default: def})
+		}
+	}
+	return params
+}
+
+func (s *signature) isTypeParam(name string) bool {
+	for _, param := range s.typeParams {
+		if param.TypeName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// call проверяет вызов функции по имени и возвращает тип результата
+func (c *checker) call(e *ast.FuncCallExpr) string {
+	args := make([]string, len(e.Args()))
+	for i, arg := range e.Args() {
+		args[i] = c.expr(arg)
+	}
+
+	// Локальная переменная (параметр, замыкание) скрывает функцию с тем же именем
+	if b, depth := c.lookup(e.FuncName()); b != nil && depth > 0 {
+		return ""
+	}
+
+	overloads := c.funcs[e.FuncName()]
+	switch len(overloads) {
+	case 0:
+		// Встроенная функция или значение, тип которого неизвестен
+		return ""
+	case 1:
+		return c.checkCall(e, overloads[0], e.Args(), args)
+	}
+
+	for _, sig := range overloads {
+		if problem, _ := c.callProblem(sig, e.Args(), args); problem == "" {
+			return sig.returnType
+		}
+	}
+	c.errorf(e, "no overload of '%s' accepts (%s)", e.FuncName(), strings.Join(orAnyAll(args), ", "))
+	return ""
+}
+
+// checkCall сообщает о несовпадении вызова с сигнатурой и возвращает тип результата
+func (c *checker) checkCall(e ast.Expr, sig *signature, argExprs []ast.Expr, args []string) string {
+	problem, bound := c.callProblem(sig, argExprs, args)
+	if problem != "" {
+		c.errorf(e, "%s", problem)
+		return ""
+	}
+	if sig.typeParams != nil {
+		// Тип результата получает аргументы типа, выведенные из аргументов вызова
+		return sig.instantiate(sig.returnType, bound, "")
+	}
+	return sig.returnType
+}
+
+// callProblem возвращает описание первой ошибки вызова или пустую строку и
+// аргументы типа generic функции, выведенные из аргументов
+func (c *checker) callProblem(sig *signature, argExprs []ast.Expr, args []string) (string, map[string]string) {
+	required := 0
+	for _, param := range sig.params {
+		if param.Default == nil {
+			required++
+		}
+	}
+
+	switch {
+	case sig.typeParams != nil && len(args) != len(sig.params):
+		return fmt.Sprintf("function '%s' expects %d arguments, got %d", sig.name, len(sig.params), len(args)), nil
+	case len(args) < required:
+		return fmt.Sprintf("function '%s' requires at least %d arguments, got %d", sig.name, required, len(args)), nil
+	case len(args) > len(sig.params):
+		return fmt.Sprintf("function '%s' accepts at most %d arguments, got %d", sig.name, len(sig.params), len(args)), nil
+	}
+
+	bound := map[string]string{}
+	for i, arg := range args {
+		param := sig.params[i]
+		if param.TypeName == "" {
+			continue
+		}
+
+		if sig.isTypeParam(param.TypeName) {
+			if problem := c.constraintProblem(sig, param.TypeName, arg); problem != "" {
+				return problem, nil
+			}
+			// Первый аргумент связывает T, остальные аргументы T должны ему соответствовать
+			prev, seen := bound[param.TypeName]
+			switch {
+			case arg == "":
+			case !seen || prev == "int" && arg == "float":
+				bound[param.TypeName] = arg
+			case !c.assignable(arg, prev):
+				return fmt.Sprintf("function '%s' parameter '%s': expected %s (%s), got %s", sig.name, param.Name, prev, param.TypeName, arg), nil
+			}
+			continue
+		}
+
+		if sig.typeParams != nil {
+			// Box<T> связывает T с аргументом типа значения Box<int>
+			sig.bind(param.TypeName, arg, bound)
+		}
+		if expected := sig.instantiate(param.TypeName, bound, "any"); !c.assignable(arg, expected) {
+			return fmt.Sprintf("function '%s' parameter '%s': expected %s, got %s", sig.name, param.Name, expected, arg), nil
+		}
+	}
+	return "", bound
+}
+
+// bind связывает параметры типа из typ с соответствующими частями типа arg
+func (s *signature) bind(typ, arg string, bound map[string]string) {
+	if s.isTypeParam(typ) {
+		if _, seen := bound[typ]; !seen && arg != "" {
+			bound[typ] = arg
+		}
+		return
+	}
+
+	name, params := ast.SplitGenericType(typ)
+	argName, args := ast.SplitGenericType(arg)
+	if name != argName || len(params) != len(args) {
+		return
+	}
+	for i := range params {
+		s.bind(params[i], args[i], bound)
+	}
+}
+
+// instantiate подставляет в тип связанные аргументы типа; несвязанный параметр
+// заменяется на unbound, а пустой unbound делает весь тип неизвестным
+func (s *signature) instantiate(typ string, bound map[string]string, unbound string) string {
+	names := ast.TypeParamNames(s.typeParams)
+	args := make([]string, len(names))
+	for i, name := range names {
+		if args[i] = bound[name]; args[i] == "" {
+			if unbound == "" && ast.MentionsTypeParam(typ, []string{name}) {
+				return ""
+			}
+			args[i] = unbound
+		}
+	}
+	return ast.SubstituteTypeParams(typ, names, args)
+}
+
+// constraintProblem проверяет, что тип аргумента реализует интерфейсы-ограничения параметра типа
+func (c *checker) constraintProblem(sig *signature, typeParam, arg string) string {
+	if arg == "" {
+		return ""
+	}
+	for _, param := range sig.typeParams {
+		if param.TypeName != typeParam {
+			continue
+		}
+		for _, constraint := range param.Constraints {
+			if !c.implements(arg, constraint) {
+				return fmt.Sprintf("function '%s': type %s does not satisfy interface constraint '%s'", sig.name, arg, constraint)
+			}
+		}
+	}
+	return ""
+}
+
+// methodCall проверяет вызов метода impl блока у значения известной структуры
+func (c *checker) methodCall(e *ast.MethodCallExpr) string {
+	receiver := c.expr(e.Object)
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = c.expr(arg)
+	}
+
+	impl, method := c.findMethod(receiver, e.MethodName)
+	if method == nil {
+		return ""
+	}
+
+	// У Box<int> параметры и результат метода с типом T получают тип int
+	names := c.implTypeParams(impl)
+	_, typeArgs := ast.SplitGenericType(receiver)
+	sig := &signature{name: receiver + "." + method.FuncName, returnType: c.substitute(method.ReturnType, names, typeArgs)}
+	for _, param := range method.Params {
+		param.TypeName = c.substitute(param.TypeName, names, typeArgs)
+		sig.params = append(sig.params, param)
+	}
+	return c.checkCall(e, sig, e.Args, args)
+}
+
+func (c *checker) findMethod(typeName, name string) (*ast.ImplBlock, *ast.TypedFuncStatement) {
+	base, _ := ast.SplitGenericType(typeName)
+	if _, ok := c.structs[base]; !ok {
+		return nil, nil
+	}
+	for _, impl := range c.impls[base] {
+		for _, method := range impl.Methods {
+			if method.FuncName == name {
+				return impl, method
+			}
+		}
+	}
+	return nil, nil
+}
+
+// implTypeParams возвращает параметры типа impl блока (impl Container<T> for Box<T>),
+// а без них - параметры самой структуры, как при вызове метода
+func (c *checker) implTypeParams(impl *ast.ImplBlock) []string {
+	if len(impl.TypeParams) > 0 {
+		return impl.TypeParams
+	}
+	if def, ok := c.structs[impl.TypeName]; ok {
+		return ast.TypeParamNames(def.TypeParams)
+	}
+	return nil
+}
+
+// substitute подставляет аргументы типа значения в тип его поля или метода.
+// Без аргументов (значение Box неизвестной специализации) такой тип неизвестен
+func (c *checker) substitute(typ string, names, args []string) string {
+	if !ast.MentionsTypeParam(typ, names) {
+		return typ
+	}
+	if len(args) != len(names) {
+		return ""
+	}
+	return ast.SubstituteTypeParams(typ, names, args)
+}
+
+// member выводит тип поля структуры или значения enum
+func (c *checker) member(e *ast.MemberExpr) string {
+	if v, ok := e.Object.(*ast.VarExpr); ok && v.GetExpr() == nil && c.enums[v.Name] {
+		if b, _ := c.lookup(v.Name); b == nil {
+			return v.Name
+		}
+	}
+
+	return c.fieldType(c.expr(e.Object), e.Property)
+}
+
+// fieldType возвращает объявленный тип поля структуры с подставленными
+// аргументами типа: поле value: T у Box<int> имеет тип int
+func (c *checker) fieldType(object, field string) string {
+	base, typeArgs := ast.SplitGenericType(object)
+	if def, ok := c.structs[base]; ok {
+		if typeExpr, ok := def.Fields[field].(*ast.TypeExpr); ok && typeExpr.TypeName != "any" {
+			return c.substitute(typeExpr.TypeName, ast.TypeParamNames(def.TypeParams), typeArgs)
+		}
+	}
+	return ""
+}
+
+// structInstance проверяет значения типизированных полей в литерале структуры
+// и возвращает ее тип; у generic структуры это Box<int> с явными или
+// выведенными по полям аргументами типа
+func (c *checker) structInstance(e *ast.StructInstanceExpr) string {
+	def := c.structs[e.TypeName]
+
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	got := make(map[string]string, len(names))
+	for _, name := range names {
+		got[name] = c.expr(e.Fields[name])
+	}
+	if def == nil {
+		return e.TypeName
+	}
+
+	params := ast.TypeParamNames(def.TypeParams)
+	typeArgs := e.TypeArgs
+	switch {
+	case len(params) == 0 && len(typeArgs) > 0:
+		c.errorf(e, "%s is not a generic struct", e.TypeName)
+		typeArgs = nil
+	case len(params) > 0 && len(typeArgs) == 0:
+		typeArgs = c.inferTypeArgs(def, names, got)
+	case len(typeArgs) != len(params):
+		c.errorf(e, "%s expects %d type argument(s), got %d", ast.FormatGenericType(e.TypeName, params), len(params), len(typeArgs))
+		typeArgs = nil
+	}
+
+	typeName := ast.FormatGenericType(e.TypeName, typeArgs)
+	for i, param := range def.TypeParams {
+		for _, constraint := range param.Constraints {
+			if i < len(typeArgs) && typeArgs[i] != "any" && !c.implements(typeArgs[i], constraint) {
+				c.errorf(e, "Type constraint violation in %s: type %s does not satisfy interface constraint '%s'",
+					ast.FormatGenericType(e.TypeName, params), typeArgs[i], constraint)
+			}
+		}
+	}
+
+	for _, name := range names {
+		typeExpr, ok := def.Fields[name].(*ast.TypeExpr)
+		if !ok || typeExpr.TypeName == "any" {
+			continue
+		}
+		if expected := c.substitute(typeExpr.TypeName, params, typeArgs); !c.assignable(got[name], expected) {
+			c.errorf(e, "struct %s field '%s': expected %s, got %s", typeName, name, expected, got[name])
+		}
+	}
+	return typeName
+}
+
+// inferTypeArgs выводит аргументы типа generic структуры по полям вида value: T
+// так же, как при выполнении: первое значение связывает T, int расширяется до float
+func (c *checker) inferTypeArgs(def *ast.StructDefExpr, names []string, got map[string]string) []string {
+	typeArgs := make([]string, len(def.TypeParams))
+	for i, param := range def.TypeParams {
+		typeArgs[i] = "any"
+		for _, name := range names {
+			typeExpr, ok := def.Fields[name].(*ast.TypeExpr)
+			if !ok || typeExpr.TypeName != param.TypeName || got[name] == "" || got[name] == "null" {
+				continue
+			}
+			if typeArgs[i] == "any" || typeArgs[i] == "int" && got[name] == "float" {
+				typeArgs[i] = got[name]
+			}
+		}
+	}
+	return typeArgs
+}
+
+// impl проверяет соответствие impl блока интерфейсу и тела его методов
+func (c *checker) impl(e *ast.ImplBlock) {
+	ifaceName := ast.FormatGenericType(e.InterfaceName, e.InterfaceArgs)
+	typeName := ast.FormatGenericType(e.TypeName, e.TypeParams)
+
+	iface, ok := c.interfaces[e.InterfaceName]
+	if !ok {
+		c.errorf(e, "Interface '%s' is not defined", e.InterfaceName)
+	} else if iface, err := iface.Instantiate(e.InterfaceArgs); err != nil {
+		c.errorf(e, "impl %s for %s: %v", ifaceName, typeName, err)
+	} else {
+		for _, required := range iface.Methods {
+			method := c.implMethod(e, required.Name)
+			switch {
+			case method == nil:
+				c.errorf(e, "impl %s for %s: method '%s' is not implemented", ifaceName, typeName, required.Name)
+			case !iface.MethodMatches(required.Name, method.Params, method.ReturnType):
+				c.errorf(method, "impl %s for %s: method '%s' signature doesn't match interface: expected %s, got %s",
+					ifaceName, typeName, required.Name,
+					formatSignature(required.Params, required.ReturnType), formatSignature(method.Params, method.ReturnType))
+			}
+		}
+	}
+
+	if _, isStruct := c.structs[e.TypeName]; !isStruct && !c.knownType(e.TypeName) {
+		c.errorf(e, "unknown type '%s'", e.TypeName)
+	}
+
+	// В методах generic структуры this имеет тип Box<T>, а T - параметр типа
+	outer := c.typeParams
+	c.typeParams = c.implTypeParams(e)
+	defer func() { c.typeParams = outer }()
+
+	this := ast.FormatGenericType(e.TypeName, c.typeParams)
+	for _, method := range e.Methods {
+		c.typedFunction(method, this)
+	}
+}
+
+func (c *checker) implMethod(e *ast.ImplBlock, name string) *ast.TypedFuncStatement {
+	for _, method := range e.Methods {
+		if method.FuncName == name {
+			return method
+		}
+	}
+	return nil
+}
+
+// formatSignature печатает сигнатуру метода как (a: int, b) -> string
+func formatSignature(params []ast.FuncParam, returnType string) string {
+	parts := make([]string, len(params))
+	for i, param := range params {
+		parts[i] = param.Name
+		if param.TypeName != "" {
+			parts[i] += ": " + param.TypeName
+		}
+	}
+	result := "(" + strings.Join(parts, ", ") + ")"
+	if returnType != "" {
+		result += " -> " + returnType
+	}
+	return result
+}
+
+// patternNames возвращает имена переменных, которые связывает образец match
+func patternNames(pattern ast.Pattern) []string {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		return []string{p.Name}
+	case *ast.OrPattern:
+		var names []string
+		for _, alt := range p.Alternatives {
+			names = append(names, patternNames(alt)...)
+		}
+		return names
+	case *ast.ResultPattern:
+		return patternNames(p.Inner)
+	case *ast.EnumPattern:
+		var names []string
+		for _, inner := range p.Payload {
+			names = append(names, patternNames(inner)...)
+		}
+		return names
+	case *ast.ArrayPattern:
+		var names []string
+		for _, inner := range p.Elements {
+			names = append(names, patternNames(inner)...)
+		}
+		if p.HasRest && p.Rest != "" {
+			names = append(names, p.Rest)
+		}
+		return names
+	case *ast.ObjectPattern:
+		var names []string
+		for _, field := range p.Fields {
+			names = append(names, patternNames(field.Pattern)...)
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package checker
+
+import (
+	"foo_lang/ast"
+	"strings"
+)
+
+// primitives - встроенные типы аннотаций (как в validateVariableType)
+var primitives = map[string]bool{
+	"int": true, "float": true, "string": true, "bool": true,
+	"array": true, "object": true, "null": true, "any": true, "number": true,
+	"set": true, "tuple": true, "bigint": true, "decimal": true,
+}
+
+// members раскрывает псевдонимы и разбивает union тип на составляющие
+func (c *checker) members(typ string) []string {
+	if typ == "" {
+		return nil
+	}
+
+	var result []string
+	for _, member := range splitUnion(typ) {
+		for seen := 0; seen < 16; seen++ {
+			base, isAlias := c.aliases[member]
+			if !isAlias {
+				break
+			}
+			member = base
+		}
+		if len(splitUnion(member)) > 1 {
+			result = append(result, c.members(member)...)
+		} else {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// assignable сообщает, можно ли значение типа src использовать там, где
+// ожидается dst. Неизвестный тип совместим с любым; union src совместим,
+// если совместим каждый его вариант
+func (c *checker) assignable(src, dst string) bool {
+	if src == "" || dst == "" {
+		return true
+	}
+
+	for _, from := range c.members(src) {
+		ok := false
+		for _, to := range c.members(dst) {
+			if c.memberAssignable(from, to) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *checker) memberAssignable(src, dst string) bool {
+	switch {
+	case src == dst, dst == "any", src == "any":
+		return true
+	case c.isTypeParam(src) || c.isTypeParam(dst):
+		return true
+	case strings.Contains(src, "<") || strings.Contains(dst, "<"):
+		return c.genericAssignable(src, dst)
+	case dst == "float" || dst == "number":
+		return src == "int" || src == "float"
+	case strings.HasPrefix(dst, "("):
+		return src == "array" || src == "tuple" || c.tupleAssignable(src, dst)
+	case dst == "tuple":
+		return strings.HasPrefix(src, "(")
+	case c.interfaces[dst] != nil:
+		return c.implements(src, dst)
+	case c.enums[dst]:
+		// Значения enum без данных - целые числа
+		return src == "int"
+	case !c.knownType(dst), !c.knownType(src):
+		// О типах из других модулей судить нельзя
+		return true
+	}
+	return false
+}
+
+// tupleAssignable сравнивает типы кортежей (int,string) поэлементно
+func (c *checker) tupleAssignable(src, dst string) bool {
+	if !strings.HasPrefix(src, "(") {
+		return false
+	}
+	srcItems := strings.Split(strings.Trim(src, "()"), ",")
+	dstItems := strings.Split(strings.Trim(dst, "()"), ",")
+	if len(srcItems) != len(dstItems) {
+		return false
+	}
+	for i := range srcItems {
+		if !c.assignable(strings.TrimSpace(srcItems[i]), strings.TrimSpace(dstItems[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// genericAssignable сравнивает generic типы: Box<int> совместим с Box<int> и
+// с Box без аргументов, но не с Box<string>; массив совместим с Array<T>
+func (c *checker) genericAssignable(src, dst string) bool {
+	srcName, srcArgs := ast.SplitGenericType(src)
+	dstName, dstArgs := ast.SplitGenericType(dst)
+	if c.interfaces[dstName] != nil {
+		return c.implements(src, dst)
+	}
+	if srcName == "Array" {
+		srcName = "array"
+	}
+	if dstName == "Array" {
+		dstName = "array"
+	}
+
+	switch {
+	case srcName != dstName:
+		return !c.knownType(srcName) || !c.knownType(dstName)
+	case len(srcArgs) == 0 || len(dstArgs) == 0:
+		return true
+	}
+	return c.sameTypeArgs(srcArgs, dstArgs)
+}
+
+// sameTypeArgs сообщает, совпадают ли аргументы типа (с точностью до any)
+func (c *checker) sameTypeArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !c.assignable(a[i], b[i]) || !c.assignable(b[i], a[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// implements сообщает, есть ли impl блок интерфейса для типа; для generic
+// интерфейса сравниваются и аргументы: Box<int> реализует Container<int>
+func (c *checker) implements(typeName, iface string) bool {
+	if typeName == iface {
+		return true
+	}
+
+	base, typeArgs := ast.SplitGenericType(typeName)
+	ifaceName, ifaceArgs := ast.SplitGenericType(iface)
+	for _, impl := range c.impls[base] {
+		if impl.InterfaceName != ifaceName {
+			continue
+		}
+		if len(ifaceArgs) == 0 || len(typeArgs) == 0 && len(impl.TypeParams) > 0 {
+			return true
+		}
+		implArgs := make([]string, len(impl.InterfaceArgs))
+		for i, arg := range impl.InterfaceArgs {
+			implArgs[i] = ast.SubstituteTypeParams(arg, impl.TypeParams, typeArgs)
+		}
+		if c.sameTypeArgs(implArgs, ifaceArgs) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTypeParam сообщает, является ли имя параметром типа проверяемой
+// generic функции, структуры или impl блока
+func (c *checker) isTypeParam(name string) bool {
+	if c.fn != nil && c.fn.isTypeParam(name) {
+		return true
+	}
+	for _, param := range c.typeParams {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+// knownType сообщает, объявлен ли тип в проверяемой программе
+func (c *checker) knownType(typ string) bool {
+	if strings.HasPrefix(typ, "(") && strings.HasSuffix(typ, ")") {
+		for _, member := range strings.Split(strings.Trim(typ, "()"), ",") {
+			if !c.knownType(strings.TrimSpace(member)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if name, typeArgs := ast.SplitGenericType(typ); len(typeArgs) > 0 {
+		if name != "Array" && !c.knownType(name) {
+			return false
+		}
+		for _, arg := range typeArgs {
+			if !c.knownType(arg) {
+				return false
+			}
+		}
+		return true
+	}
+
+	_, isStruct := c.structs[typ]
+	_, isAlias := c.aliases[typ]
+	_, isInterface := c.interfaces[typ]
+	return primitives[typ] || isStruct || isAlias || isInterface || c.enums[typ] ||
+		c.imported[typ] || c.isTypeParam(typ)
+}
+
+// checkTypeExists сообщает о неизвестных типах в аннотации
+func (c *checker) checkTypeExists(expr ast.Expr, typ string) {
+	if c.importAll {
+		return
+	}
+	for _, member := range splitUnion(typ) {
+		if !c.knownType(member) {
+			c.errorf(expr, "unknown type '%s'", member)
+		}
+	}
+}
+
+// splitUnion разбивает union тип на варианты, не заходя внутрь аргументов
+// типа: int|Box<string|null> -> [int, Box<string|null>]
+func splitUnion(typ string) []string {
+	var result []string
+	depth, from := 0, 0
+	for i := 0; i < len(typ); i++ {
+		switch typ[i] {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(typ[from:i]))
+				from = i + 1
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(typ[from:]))
+}
+
+// orAny печатает неизвестный тип как any
+func orAny(typ string) string {
+	if typ == "" {
+		return "any"
+	}
+	return typ
+}
+
+func orAnyAll(types []string) []string {
+	result := make([]string, len(types))
+	for i, typ := range types {
+		result[i] = orAny(typ)
+	}
+	return result
+}
@@ -1,15 +1,19 @@
 package main
 
-import "foo_lang/parser"
+import (
+	"fmt"
+	"foo_lang/interpreter"
+)
 
 func main() {
-	p, err := parser.NewParserFromFile("examples/main.foo")
+	interp := interpreter.New()
+
+	exprs, err := interp.ParseFile("examples/main.foo")
 	if err != nil {
 		panic(err)
 	}
 
-	exprs := p.ParseWithModules()
-	for _, expr := range exprs {
-		expr.Eval()
+	if _, err := interp.Run(exprs); err != nil {
+		fmt.Println(err)
 	}
 }
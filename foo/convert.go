@@ -0,0 +1,519 @@
+package foo
+
+import (
+	"errors"
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/value"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// Func - функция foo_lang, полученная в Go через ToGo
+type Func func(args ...any) (any, error)
+
+var (
+	valueType = reflect.TypeOf((*value.Value)(nil))
+	timeType  = reflect.TypeOf(time.Time{})
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// ToValue преобразует значение Go в значение foo_lang:
+//   - bool, целые и вещественные числа, строки, time.Time - в примитивы
+//   - *big.Int и *value.Decimal - в bigint и decimal
+//   - слайсы и массивы - в массивы
+//   - map со строковыми ключами и структуры - в объекты (имя поля можно задать тегом `foo:"name"`,
+//     `foo:"-"` исключает поле)
+//   - указатели и интерфейсы - в значение, на которое они указывают (nil - в null)
+//   - функции - в вызываемые из скрипта функции; последний результат типа error
+//     превращается в ошибку выполнения скрипта
+//
+// Структуры и коллекции копируются: изменения в скрипте не видны в Go
+func ToValue(goValue any) (*value.Value, error) {
+	switch v := goValue.(type) {
+	case nil:
+		return value.NewValue(nil), nil
+	case *value.Value:
+		return v, nil
+	case time.Time:
+		return value.NewTime(v), nil
+	case ast.Callable, func([]*value.Value) *value.Value, *big.Int, *value.Decimal:
+		return value.NewValue(v), nil
+	}
+
+	rv := reflect.ValueOf(goValue)
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return value.NewValue(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.NewValue(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.NewValue(int64(rv.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return value.NewValue(rv.Float()), nil
+	case reflect.String:
+		return value.NewValue(rv.String()), nil
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return value.NewValue(nil), nil
+		}
+		elements := make([]any, rv.Len())
+		for i := range elements {
+			element, err := ToValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			elements[i] = element.Any()
+		}
+		return value.NewValue(elements), nil
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			return value.NewValue(nil), nil
+		}
+		fields := make(map[string]*value.Value, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			field, err := ToValue(iter.Value().Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			fields[key] = named(field, key)
+		}
+		return value.NewValue(fields), nil
+
+	case reflect.Struct:
+		// Поля объекта идут в порядке объявления в Go структуре
+		fields := value.NewObject()
+		for i := 0; i < rv.NumField(); i++ {
+			structField := rv.Type().Field(i)
+			name, ok := fieldName(structField)
+			if !ok {
+				continue
+			}
+			field, err := ToValue(rv.Field(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			fields.Set(name, named(field, name))
+		}
+		return value.NewValue(fields), nil
+
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return value.NewValue(nil), nil
+		}
+		return ToValue(rv.Elem().Interface())
+
+	case reflect.Func:
+		if rv.IsNil() {
+			return value.NewValue(nil), nil
+		}
+		if err := checkFuncResults(rv.Type()); err != nil {
+			return nil, err
+		}
+		return value.NewValue(&goFunction{name: "func", fn: rv}), nil
+	}
+
+	return nil, fmt.Errorf("unsupported Go type %s", rv.Type())
+}
+
+// ToGo преобразует значение foo_lang в значение Go: массивы, кортежи и множества в []any, объекты
+// и экземпляры структур в map[string]any, функции в Func. Остальные значения
+// (int64, float64, string, bool, time.Time, nil) возвращаются как есть
+func ToGo(v *value.Value) any {
+	if v == nil {
+		return nil
+	}
+	return toGo(v.Any())
+}
+
+func toGo(data any) any {
+	switch d := data.(type) {
+	case *value.Value:
+		return ToGo(d)
+	case []any:
+		elements := make([]any, len(d))
+		for i, element := range d {
+			elements[i] = toGo(element)
+		}
+		return elements
+	case *value.Tuple:
+		return toGo(d.Items())
+	case *value.Set:
+		return toGo(d.Items())
+	}
+
+	if fields, ok := objectFields(data); ok {
+		result := make(map[string]any, len(fields))
+		for name, field := range fields {
+			result[name] = ToGo(field)
+		}
+		return result
+	}
+
+	if call, ok := callableOf(data); ok {
+		return Func(func(args ...any) (any, error) {
+			values, err := toValues(args)
+			if err != nil {
+				return nil, err
+			}
+			result, err := invoke(call, values)
+			if err != nil {
+				return nil, err
+			}
+			return ToGo(result), nil
+		})
+	}
+
+	return data
+}
+
+// Decode записывает значение foo_lang в переменную Go, на которую указывает out.
+// Поддерживает те же типы, что и ToValue, включая функции: функция скрипта
+// оборачивается в функцию Go нужной сигнатуры
+func Decode(v *value.Value, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("Decode requires a non-nil pointer")
+	}
+	return decode(v, rv.Elem())
+}
+
+func decode(v *value.Value, target reflect.Value) error {
+	var data any
+	if v != nil {
+		data = v.Any()
+	}
+
+	if nested, ok := data.(*value.Value); ok {
+		return decode(nested, target)
+	}
+
+	switch target.Type() {
+	case valueType:
+		target.Set(reflect.ValueOf(v))
+		return nil
+	case timeType:
+		if t, ok := data.(time.Time); ok {
+			target.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return decodeError(v, target)
+	}
+
+	if data == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		goValue := reflect.ValueOf(ToGo(v))
+		if !goValue.Type().AssignableTo(target.Type()) {
+			return decodeError(v, target)
+		}
+		target.Set(goValue)
+
+	case reflect.Bool:
+		b, ok := data.(bool)
+		if !ok {
+			return decodeError(v, target)
+		}
+		target.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !v.IsNumber() || target.OverflowInt(v.Int64()) {
+			return decodeError(v, target)
+		}
+		target.SetInt(v.Int64())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !v.IsNumber() || v.Int64() < 0 || target.OverflowUint(uint64(v.Int64())) {
+			return decodeError(v, target)
+		}
+		target.SetUint(uint64(v.Int64()))
+
+	case reflect.Float32, reflect.Float64:
+		if !v.IsNumber() {
+			return decodeError(v, target)
+		}
+		target.SetFloat(v.Float64())
+
+	case reflect.String:
+		s, ok := data.(string)
+		if !ok {
+			return decodeError(v, target)
+		}
+		target.SetString(s)
+
+	case reflect.Slice, reflect.Array:
+		elements, ok := data.([]any)
+		if !ok {
+			return decodeError(v, target)
+		}
+		if target.Kind() == reflect.Slice {
+			target.Set(reflect.MakeSlice(target.Type(), len(elements), len(elements)))
+		} else if target.Len() != len(elements) {
+			return fmt.Errorf("cannot decode array of %d elements into %s", len(elements), target.Type())
+		}
+		for i, element := range elements {
+			if err := decode(value.NewValue(element), target.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+
+	case reflect.Map:
+		fields, ok := objectFields(data)
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return decodeError(v, target)
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(fields))
+		for name, field := range fields {
+			element := reflect.New(target.Type().Elem()).Elem()
+			if err := decode(field, element); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			result.SetMapIndex(reflect.ValueOf(name).Convert(target.Type().Key()), element)
+		}
+		target.Set(result)
+
+	case reflect.Struct:
+		fields, ok := objectFields(data)
+		if !ok {
+			return decodeError(v, target)
+		}
+		for i := 0; i < target.NumField(); i++ {
+			name, ok := fieldName(target.Type().Field(i))
+			if !ok {
+				continue
+			}
+			field, exists := fields[name]
+			if !exists {
+				continue
+			}
+			if err := decode(field, target.Field(i)); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+	case reflect.Ptr:
+		pointer := reflect.New(target.Type().Elem())
+		if err := decode(v, pointer.Elem()); err != nil {
+			return err
+		}
+		target.Set(pointer)
+
+	case reflect.Func:
+		call, ok := callableOf(data)
+		if !ok {
+			return decodeError(v, target)
+		}
+		if err := checkFuncResults(target.Type()); err != nil {
+			return err
+		}
+		target.Set(makeFunc(target.Type(), call))
+
+	default:
+		return decodeError(v, target)
+	}
+
+	return nil
+}
+
+func decodeError(v *value.Value, target reflect.Value) error {
+	return fmt.Errorf("cannot decode %s into %s", value.GetValueTypeName(v), target.Type())
+}
+
+// fieldName возвращает имя поля структуры в foo_lang; false - поле не экспортируется
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	switch tag := field.Tag.Get("foo"); tag {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return tag, true
+	}
+}
+
+// objectFields возвращает поля объекта или экземпляра структуры foo_lang
+func objectFields(data any) (map[string]*value.Value, bool) {
+	switch d := data.(type) {
+	case *value.Object:
+		return d.Fields(), true
+	case *ast.StructObject:
+		return d.Fields, true
+	}
+	return nil, false
+}
+
+// callableOf возвращает функцию вызова для функции foo_lang
+func callableOf(data any) (func([]*value.Value) *value.Value, bool) {
+	switch d := data.(type) {
+	case ast.Callable:
+		return d.Call, true
+	case func([]*value.Value) *value.Value:
+		return d, true
+	}
+	return nil, false
+}
+
+// invoke вызывает функцию foo_lang, превращая ошибку выполнения в error
+func invoke(call func([]*value.Value) *value.Value, args []*value.Value) (result *value.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return call(args), nil
+}
+
+func toValues(args []any) ([]*value.Value, error) {
+	values := make([]*value.Value, len(args))
+	for i, arg := range args {
+		val, err := ToValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		values[i] = val
+	}
+	return values, nil
+}
+
+// checkFuncResults проверяет, что функция возвращает не больше одного значения и,
+// возможно, error последним результатом
+func checkFuncResults(fnType reflect.Type) error {
+	results := fnType.NumOut()
+	if results > 0 && fnType.Out(results-1) == errorType {
+		results--
+	}
+	if results > 1 {
+		return fmt.Errorf("unsupported function signature %s: at most one result and an error are allowed", fnType)
+	}
+	return nil
+}
+
+// makeFunc оборачивает функцию foo_lang в функцию Go типа fnType
+func makeFunc(fnType reflect.Type, call func([]*value.Value) *value.Value) reflect.Value {
+	return reflect.MakeFunc(fnType, func(in []reflect.Value) []reflect.Value {
+		args := make([]any, 0, len(in))
+		for i, arg := range in {
+			if fnType.IsVariadic() && i == len(in)-1 {
+				for j := 0; j < arg.Len(); j++ {
+					args = append(args, arg.Index(j).Interface())
+				}
+				continue
+			}
+			args = append(args, arg.Interface())
+		}
+
+		var result *value.Value
+		values, err := toValues(args)
+		if err == nil {
+			result, err = invoke(call, values)
+		}
+
+		out := make([]reflect.Value, fnType.NumOut())
+		for i := range out {
+			out[i] = reflect.New(fnType.Out(i)).Elem()
+		}
+
+		returnsError := len(out) > 0 && fnType.Out(len(out)-1) == errorType
+		if err == nil && len(out) > 0 && !(returnsError && len(out) == 1) {
+			err = decode(result, out[0])
+			if err != nil {
+				out[0] = reflect.Zero(fnType.Out(0))
+			}
+		}
+
+		if err != nil {
+			if !returnsError {
+				panic(err.Error())
+			}
+			out[len(out)-1] = reflect.ValueOf(&err).Elem()
+		}
+
+		return out
+	})
+}
+
+// named задает имя функции Go, полученной из поля объекта или через Set
+func named(v *value.Value, name string) *value.Value {
+	if fn, ok := v.Any().(*goFunction); ok && fn.name == "func" {
+		return value.NewValue(&goFunction{name: name, fn: fn.fn})
+	}
+	return v
+}
+
+// goFunction - функция Go, вызываемая из скрипта
+type goFunction struct {
+	name string
+	fn   reflect.Value
+}
+
+func (f *goFunction) Name() string {
+	return f.name
+}
+
+func (f *goFunction) String() string {
+	return "go function " + f.name
+}
+
+func (f *goFunction) Call(args []*value.Value) *value.Value {
+	fnType := f.fn.Type()
+	numIn := fnType.NumIn()
+
+	if fnType.IsVariadic() {
+		if len(args) < numIn-1 {
+			panic(fmt.Sprintf("%s() takes at least %d arguments, got %d", f.name, numIn-1, len(args)))
+		}
+	} else if len(args) != numIn {
+		panic(fmt.Sprintf("%s() takes exactly %d arguments, got %d", f.name, numIn, len(args)))
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		argType := reflect.Type(nil)
+		if fnType.IsVariadic() && i >= numIn-1 {
+			argType = fnType.In(numIn - 1).Elem()
+		} else {
+			argType = fnType.In(i)
+		}
+
+		in[i] = reflect.New(argType).Elem()
+		if err := decode(arg, in[i]); err != nil {
+			panic(fmt.Sprintf("%s(): argument %d: %v", f.name, i+1, err))
+		}
+	}
+
+	out := f.fn.Call(in)
+	if len(out) > 0 && fnType.Out(len(out)-1) == errorType {
+		if err := out[len(out)-1]; !err.IsNil() {
+			panic(fmt.Sprintf("%s(): %v", f.name, err.Interface()))
+		}
+		out = out[:len(out)-1]
+	}
+
+	if len(out) == 0 {
+		return value.NewValue(nil)
+	}
+
+	result, err := ToValue(out[0].Interface())
+	if err != nil {
+		panic(fmt.Sprintf("%s(): result: %v", f.name, err))
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+// Package foo - API для встраивания foo_lang в Go-программы.
+//
+//	engine, err := foo.New(&foo.Options{
+//		Globals: map[string]any{"taxRate": 0.2},
+//	})
+//	if err != nil { ... }
+//
+//	if _, err := engine.EvalFile("rules.foo"); err != nil { ... }
+//
+//	price, err := engine.Call("finalPrice", order)
+//
+// Значения Go (числа, строки, слайсы, map, структуры, функции) автоматически
+// преобразуются в *value.Value и обратно, см. ToValue, ToGo и Decode.
+//
+// Области видимости, модули, extension-блоки, перегрузки, именованные примитивы
+// синхронизации (newMutex("db") и др.), HTTP-сервер, маршруты httpRoute и
+// проверка переполнения int (Options.CheckOverflow) у каждого Engine свои.
+// Общими для всех Engine в процессе остаются только встроенные методы
+// расширения (value.RegisterExtensionMethod).
+package foo
+
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"foo_lang/value"
+	"path/filepath"
+)
+
+// Options - настройки движка
+type Options struct {
+	// Globals - переменные и функции Go, доступные скрипту под заданными именами
+	Globals map[string]any
+
+	// Dir - каталог, относительно которого EvalString разрешает импорты
+	Dir string
+
+	// MaxRecursion - ограничение глубины рекурсии (0 - значение по умолчанию)
+	MaxRecursion int
+
+	// CheckOverflow - переполнение int становится ошибкой выполнения, а не переносом
+	CheckOverflow bool
+
+	// SearchPath - каталоги поиска модулей для импортов вида "lib/util.foo",
+	// просматриваются перед каталогами из FOO_PATH
+	SearchPath []string
+}
+
+// Engine - экземпляр интерпретатора foo_lang со всеми встроенными функциями.
+// Разные Engine изолированы друг от друга (кроме общего состояния процесса, см.
+// описание пакета) и могут работать параллельно, но один Engine не предназначен
+// для одновременного использования из нескольких горутин
+type Engine struct {
+	interp *interpreter.Interpreter
+	dir    string
+}
+
+// New создает движок. opts может быть nil
+func New(opts *Options) (*Engine, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	engine := &Engine{
+		interp: interpreter.New(),
+		dir:    opts.Dir,
+	}
+
+	engine.Runtime().Modules.AddSearchPath(opts.SearchPath...)
+
+	engine.Runtime().SetOverflowCheck(opts.CheckOverflow)
+
+	if opts.MaxRecursion > 0 {
+		engine.interp.Scope().SetMaxRecursion(opts.MaxRecursion)
+	}
+
+	for name, goValue := range opts.Globals {
+		if err := engine.Set(name, goValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return engine, nil
+}
+
+// Runtime возвращает Runtime движка для низкоуровневой работы с AST
+func (e *Engine) Runtime() *ast.Runtime {
+	return e.interp.Runtime()
+}
+
+// EvalString выполняет код и возвращает значение последнего выражения
+func (e *Engine) EvalString(code string) (*value.Value, error) {
+	if e.dir != "" {
+		e.interp.Runtime().SetCurrentFile(filepath.Join(e.dir, "<eval>"))
+	}
+
+	exprs, err := e.interp.Parse(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.interp.Run(exprs)
+}
+
+// EvalFile выполняет файл; импорты в нем разрешаются относительно его пути
+func (e *Engine) EvalFile(filePath string) (*value.Value, error) {
+	exprs, err := e.interp.ParseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.interp.Run(exprs)
+}
+
+// Call вызывает функцию скрипта по имени. Аргументы преобразуются через ToValue
+func (e *Engine) Call(name string, args ...any) (*value.Value, error) {
+	values, err := toValues(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return e.interp.Call(name, values)
+}
+
+// Set объявляет переменную скрипта со значением Go
+func (e *Engine) Set(name string, goValue any) error {
+	val, err := ToValue(goValue)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	e.interp.Scope().Set(name, named(val, name))
+	return nil
+}
+
+// Get возвращает значение переменной скрипта
+func (e *Engine) Get(name string) (*value.Value, bool) {
+	return e.interp.Scope().Get(name)
+}
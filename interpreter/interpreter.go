@@ -0,0 +1,106 @@
+package interpreter
+
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/builtin"
+	"foo_lang/parser"
+	"foo_lang/scope"
+	"foo_lang/value"
+)
+
+// Interpreter - независимый экземпляр интерпретатора foo_lang.
+// Владеет собственным Runtime (стек областей, кэш модулей, реестры методов расширения
+// и перегрузок) и встроенными функциями, поэтому несколько интерпретаторов
+// могут выполнять скрипты параллельно в одном процессе. Примитивы синхронизации
+// и HTTP-сервер встроенных функций тоже свои (builtin.StateOf). Общими для процесса
+// остаются только встроенные методы расширения (подробнее - в описании пакета foo)
+type Interpreter struct {
+	runtime *ast.Runtime
+}
+
+// New создает интерпретатор с инициализированными встроенными функциями
+func New() *Interpreter {
+	scopeStack := scope.NewScopeStack()
+	runtime := ast.NewRuntime(scopeStack, parser.ParseInRuntime)
+	builtin.InitializeAll(runtime, scopeStack)
+
+	// Код верхнего уровня модулей тоже видит встроенные функции (и мост __builtin_*)
+	// с теми же примитивами синхронизации и HTTP-сервером
+	runtime.Modules.Prelude = func(moduleScope *scope.ScopeStack) {
+		builtin.InitializeAll(runtime, moduleScope)
+	}
+
+	return &Interpreter{runtime: runtime}
+}
+
+// Runtime возвращает Runtime интерпретатора
+func (i *Interpreter) Runtime() *ast.Runtime {
+	return i.runtime
+}
+
+// Scope возвращает стек областей видимости интерпретатора
+func (i *Interpreter) Scope() *scope.ScopeStack {
+	return i.runtime.Scope()
+}
+
+// Parse разбирает код. Функции и константы регистрируются в scope интерпретатора при разборе
+func (i *Interpreter) Parse(code string) (exprs []ast.Expr, err error) {
+	defer recoverError(&err)
+
+	return parser.ParseInRuntime(i.runtime, code), nil
+}
+
+// ParseFile разбирает файл; импорты в нем разрешаются относительно его пути
+func (i *Interpreter) ParseFile(filePath string) (exprs []ast.Expr, err error) {
+	defer recoverError(&err)
+
+	p, err := parser.NewParserFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.WithRuntime(i.runtime).ParseWithModules(), nil
+}
+
+// ParseFileForCheck разбирает файл для статической проверки (foo check):
+// код не выполняется, инициализаторы констант не вычисляются
+func (i *Interpreter) ParseFileForCheck(filePath string) (exprs []ast.Expr, err error) {
+	defer recoverError(&err)
+
+	p, err := parser.NewParserFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.WithRuntime(i.runtime).WithoutEval().ParseWithModules(), nil
+}
+
+// Run выполняет выражения и возвращает значение последнего.
+// Ошибка выполнения скрипта возвращается как *ast.ScriptError со стеком вызовов foo
+func (i *Interpreter) Run(exprs []ast.Expr) (result *value.Value, err error) {
+	defer i.recoverScriptError(&err)
+
+	return i.runtime.Run(exprs), nil
+}
+
+// Call вызывает функцию, объявленную в скрипте или зарегистрированную в scope
+func (i *Interpreter) Call(name string, args []*value.Value) (result *value.Value, err error) {
+	defer i.recoverScriptError(&err)
+
+	return ast.CallFunction(i.runtime, name, args), nil
+}
+
+// recoverScriptError превращает панику выполнения в *ast.ScriptError со стеком вызовов
+func (i *Interpreter) recoverScriptError(err *error) {
+	if r := recover(); r != nil {
+		*err = ast.NewScriptError(i.runtime, r)
+	}
+}
+
+// recoverError превращает панику интерпретатора в error
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%v", r)
+	}
+}
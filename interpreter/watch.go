@@ -0,0 +1,115 @@
+package interpreter
+
+import (
+	"fmt"
+	"foo_lang/builtin"
+	"foo_lang/modules"
+	"foo_lang/value"
+	"io"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Reload заново выполняет модуль по абсолютному пути и подменяет его экспорты
+// на месте: импортировавший код получает новые функции при следующем вызове,
+// маршруты httpRoute со старыми функциями переключаются на новые. Каналы и
+// примитивы синхронизации верхнего уровня модуля сохраняются (State.KeepOnReload).
+// При ошибке в новом коде модуль остается прежним
+func (i *Interpreter) Reload(path string) error {
+	var fresh *modules.Module
+	var err error
+	state := builtin.StateOf(i.runtime)
+	state.Reattach(func() {
+		fresh, err = i.runtime.RerunModule(path)
+	})
+	if err != nil {
+		return err
+	}
+
+	resume := state.HoldRequests()
+	defer resume()
+
+	replaced := i.runtime.Modules.Replace(fresh, state.KeepOnReload)
+	state.RebindRoutes(func(handler *value.Value) *value.Value {
+		for _, r := range replaced {
+			if sameData(handler.Any(), r.Old) {
+				return r.New
+			}
+		}
+		return handler
+	})
+	return nil
+}
+
+// ReloadChanged перезагружает модули, файлы которых изменились после
+// выполнения. Возвращает пути перезагруженных модулей и ошибки остальных
+func (i *Interpreter) ReloadChanged() (reloaded []string, errs []error) {
+	for _, module := range i.runtime.Modules.Modules() {
+		if !module.Changed() {
+			continue
+		}
+		if err := i.Reload(module.Path); err != nil {
+			// Следующая попытка - после нового изменения файла
+			if info, statErr := os.Stat(module.Path); statErr == nil {
+				module.ModTime = info.ModTime()
+			}
+			errs = append(errs, fmt.Errorf("reload %s: %w", module.Path, err))
+			continue
+		}
+		reloaded = append(reloaded, module.Path)
+	}
+	return reloaded, errs
+}
+
+// Watch раз в interval проверяет файлы импортированных модулей и перезагружает
+// измененные (режим --watch). Основной файл не перезагружается: о его изменении
+// Watch только сообщает. Сообщения пишутся в out. Возвращает функцию остановки
+func (i *Interpreter) Watch(interval time.Duration, out io.Writer) (stop func()) {
+	mainFile := i.runtime.CurrentFile()
+	mainTime := modTime(mainFile)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			reloaded, errs := i.ReloadChanged()
+			for _, path := range reloaded {
+				fmt.Fprintf(out, "[watch] reloaded %s\n", path)
+			}
+			for _, err := range errs {
+				fmt.Fprintf(out, "[watch] %v\n", err)
+			}
+
+			if t := modTime(mainFile); !t.Equal(mainTime) {
+				mainTime = t
+				fmt.Fprintf(out, "[watch] %s changed: the main file is not reloaded, restart to apply\n", mainFile)
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// modTime возвращает время изменения файла или нулевое время
+func modTime(path string) time.Time {
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+// sameData сравнивает данные значений; функции Go и другие несравнимые
+// значения равны только самим себе по указателю *value.Value, а не здесь
+func sameData(a, b any) bool {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -48,6 +48,9 @@ var operators = MapType[string]{
 	"~":   token.TILDE,
 	"!":   token.NOT,
 	".":   token.DOT,
+	"..":  token.DOT_DOT,
+	"..=": token.DOT_DOT_EQ,
+	"...": token.ELLIPSIS,
 	",":   token.COMMA,
 	"=":   token.EQ,
 	"@":   token.AT,
@@ -106,6 +109,10 @@ var keywords = MapType[string]{
 	"if":      token.IF,
 	"else":    token.ELSE,
 	"for":     token.FOR,
+	"in":      token.IN,
+	"while":   token.WHILE,
+	"loop":    token.LOOP,
+	"continue": token.CONTINUE,
 	"fn":      token.FN,
 	"enum":    token.ENUM,
 	"Result":  token.RESULT,
@@ -125,6 +132,17 @@ var keywords = MapType[string]{
 	"await":     token.AWAIT,
 	"sleep":     token.SLEEP,
 	"Promise":   token.PROMISE,
+	"try":       token.TRY,
+	"catch":     token.CATCH,
+	"finally":   token.FINALLY,
+	"throw":     token.THROW,
+	"defer":     token.DEFER,
+}
+
+// IsKeyword сообщает, что слово зарезервировано языком
+func IsKeyword(word string) bool {
+	_, ok := keywords[word]
+	return ok
 }
 
 type Lexer struct {
@@ -285,6 +303,16 @@ func (l *Lexer) Tokens() []token.TokenType {
 func (l *Lexer) Token() token.TokenType {
 	l.SkipSpace()
 
+	// Запоминаем начало токена: строки и колонки считаются с 1, Pos - индекс руны
+	line, col, pos := l.line+1, l.col+1, l.pos
+
+	t := l.readToken()
+	t.Line, t.Col, t.Pos = line, col, pos
+
+	return t
+}
+
+func (l *Lexer) readToken() token.TokenType {
 	ch := l.Peek(0)
 
 	switch {
@@ -296,7 +324,8 @@ func (l *Lexer) Token() token.TokenType {
 	case unicode.IsDigit(ch):
 		return l.ReadNumber()
 
-	case unicode.IsLetter(ch):
+	// _ без продолжения - токен UNDERSCORE (образец match), _name и __builtin_x - имена
+	case unicode.IsLetter(ch), ch == '_' && (unicode.IsLetter(l.Peek(1)) || unicode.IsDigit(l.Peek(1)) || l.Peek(1) == '_'):
 		return l.ReadIdentifier()
 
 	case ch == '$':
@@ -394,6 +423,11 @@ func (l *Lexer) ReadNumber() token.TokenType {
 			return token.NewTokenType(token.ILLEGAL, "ILLEGAL", l.line, l.col)
 		}
 
+		// 1..10 - диапазон, а не дробное число
+		if ch == '.' && l.Peek(1) == '.' {
+			break
+		}
+
 		if ch == '.' {
 			if isDot {
 				return token.NewTokenType(token.ILLEGAL, "ILLEGAL", l.line, l.col)
@@ -428,6 +462,12 @@ func (l *Lexer) ReadNumber() token.TokenType {
 		return token.NewTokenType(token.FLOAT, string(numbers), l.line, l.col)
 	}
 
+	// 123n - литерал BigInt
+	if next := l.Peek(1); l.Peek(0) == 'n' && !unicode.IsLetter(next) && !unicode.IsDigit(next) && next != '_' {
+		l.Next()
+		return token.NewTokenType(token.BIGINT, string(numbers), l.line, l.col)
+	}
+
 	return token.NewTokenType(token.INT, string(numbers), l.line, l.col)
 }
 
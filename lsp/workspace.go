@@ -384,7 +384,8 @@ func (w *Workspace) GetCompletions(uri string, pos Position) []map[string]interf
 	// Ключевые слова языка
 	keywords := []string{
 		"let", "const", "fn", "struct", "enum", "interface", "impl", "extension",
-		"if", "else", "for", "match", "return", "yield", "break", "continue",
+		"if", "else", "for", "in", "while", "loop", "match", "return", "yield", "break", "continue",
+		"try", "catch", "finally", "throw", "defer",
 		"async", "await", "sleep", "Promise",
 		"import", "export", "from", "as",
 		"macro", "quote", "unquote", "typeof", "type",
@@ -422,13 +423,15 @@ func (w *Workspace) GetCompletions(uri string, pos Position) []map[string]interf
 		"timeFormat": "fn timeFormat(time: Time, format: string) -> string",
 		"sleep":      "fn sleep(ms: int) -> void",
 		"typeof":     "fn typeof(value: any) -> string",
-		"jsonParse":  "fn jsonParse(json: string) -> any",
+		"jsonParse":  "fn jsonParse(json: string) -> Result",
 		"jsonStringify": "fn jsonStringify(value: any) -> string",
-		// Встроенные методы
-		"toString":   "fn toString() -> string",
-		"length":     "fn length() -> int",
-		"push":       "fn push(value: any) -> void",
-		"pop":        "fn pop() -> any",
+	}
+	// Встроенные методы
+	builtins["toString"] = "fn toString() -> string"
+	for _, typeName := range []string{"string", "array"} {
+		for _, method := range ast.BuiltinMethods(typeName) {
+			builtins[method.Name] = method.Signature()
+		}
 	}
 	
 	for name, signature := range builtins {
@@ -570,8 +573,7 @@ func (w *Workspace) isBuiltinFunction(name string) bool {
 		"httpGet", "httpPost", "newChannel", "send", "receive",
 		"sha256Hash", "base64Encode", "regexMatch", "regexReplace",
 		"now", "timeFormat", "sleep", "typeof", "jsonParse", "jsonStringify",
-		// Также добавляем встроенные методы
-		"toString", "length", "push", "pop", "slice", "charAt", "toUpper", "toLower",
+		"toString",
 	}
 	
 	for _, builtin := range builtins {
@@ -580,6 +582,13 @@ func (w *Workspace) isBuiltinFunction(name string) bool {
 		}
 	}
 	
+	// Также встроенные методы строк и массивов
+	for _, typeName := range []string{"string", "array"} {
+		if _, ok := ast.LookupBuiltinMethod(typeName, name); ok {
+			return true
+		}
+	}
+	
 	return false
 }
 
@@ -669,20 +678,14 @@ func (w *Workspace) getBuiltinMethodsForType(typeName string) []map[string]inter
 	completions := []map[string]interface{}{}
 	
 	switch typeName {
-	case "string":
-		methods := map[string]string{
-			"length":    "fn length() -> int",
-			"charAt":    "fn charAt(index: int) -> string",
-			"substring": "fn substring(start: int, end: int) -> string",
-			"toUpper":   "fn toUpper() -> string",
-			"toLower":   "fn toLower() -> string",
-			"toString":  "fn toString() -> string",
-		}
-		for name, signature := range methods {
+	case "string", "array", "bigint", "decimal":
+		// Методы строк, массивов и больших чисел берем из таблицы интерпретатора
+		for _, method := range ast.BuiltinMethods(typeName) {
 			completions = append(completions, map[string]interface{}{
-				"label":  name,
-				"kind":   CompletionItemKindMethod,
-				"detail": signature,
+				"label":         method.Name,
+				"kind":          CompletionItemKindMethod,
+				"detail":        method.Signature(),
+				"documentation": method.Doc,
 			})
 		}
 		
@@ -704,21 +707,6 @@ func (w *Workspace) getBuiltinMethodsForType(typeName string) []map[string]inter
 			})
 		}
 		
-	case "array":
-		methods := map[string]string{
-			"length": "fn length() -> int",
-			"push":   "fn push(value: any) -> void",
-			"pop":    "fn pop() -> any",
-			"slice":  "fn slice(start: int, end: int) -> array",
-		}
-		for name, signature := range methods {
-			completions = append(completions, map[string]interface{}{
-				"label":  name,
-				"kind":   CompletionItemKindMethod,
-				"detail": signature,
-			})
-		}
-		
 	default:
 		// Для всех типов доступен toString
 		completions = append(completions, map[string]interface{}{
@@ -2,12 +2,16 @@ package main
 
 import (
 	"fmt"
-	"foo_lang/ast"
 	"foo_lang/builtin"
+	"foo_lang/checker"
+	"foo_lang/interpreter"
 	"foo_lang/modules"
-	"foo_lang/parser"
+	"foo_lang/repl"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 type User struct {
@@ -22,9 +26,44 @@ func main() {
 		}
 	}()
 
+	// --lib dir: каталоги поиска модулей перед FOO_PATH. Передаются через
+	// окружение, чтобы их видели все интерпретаторы (repl, check, bytecode)
+	if dirs := libDirs(os.Args[1:]); len(dirs) > 0 {
+		if current := os.Getenv(modules.PathEnv); current != "" {
+			dirs = append(dirs, current)
+		}
+		os.Setenv(modules.PathEnv, strings.Join(dirs, string(filepath.ListSeparator)))
+	}
+
+	// foo repl - интерактивный режим
+	if len(os.Args) > 1 && os.Args[1] == "repl" {
+		builtin.InitCLI(os.Args)
+		session := repl.New(os.Stdout)
+		session.SetOverflowCheck(checkOverflow())
+		if err := session.Run(os.Stdin); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
+
+	// foo check file.foo - статическая проверка типов без выполнения
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
+
+	// foo mod vendor|tidy - зависимости пакета из foo.mod
+	if len(os.Args) > 1 && os.Args[1] == "mod" {
+		os.Exit(runMod(os.Args[2:]))
+	}
+
 	// Проверяем флаг bytecode режима
 	for _, arg := range os.Args {
 		if arg == "--bytecode" || arg == "-b" {
+			// Перезагрузка модулей работает только в tree-walking интерпретаторе
+			if slices.Contains(os.Args, "--watch") {
+				fmt.Println("--watch is not supported with --bytecode")
+				os.Exit(2)
+			}
 			RunBytecodeMode()
 			return
 		}
@@ -34,81 +73,162 @@ func main() {
 		}
 	}
 
-	var filename string = "examples/main.foo" // Значение по умолчанию
+	filename := getFilename("examples/main.foo")
+
+	builtin.InitCLI(os.Args)
+
+	interp := newInterpreter()
+
+	exprs, err := interp.ParseFile(filename)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// --watch: измененные импортированные модули перезагружаются без перезапуска
+	for _, arg := range os.Args {
+		if arg == "--watch" {
+			defer interp.Watch(watchInterval, os.Stderr)()
+			break
+		}
+	}
+
+	if _, err := interp.Run(exprs); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// newInterpreter создает интерпретатор с настройками из флагов командной строки
+func newInterpreter() *interpreter.Interpreter {
+	interp := interpreter.New()
+	interp.Runtime().SetOverflowCheck(checkOverflow())
+	return interp
+}
+
+// checkOverflow - флаг --check-overflow: переполнение int - ошибка выполнения, а не перенос
+func checkOverflow() bool {
+	return slices.Contains(os.Args, "--check-overflow")
+}
+
+// watchInterval - период проверки файлов модулей в режиме --watch
+const watchInterval = 500 * time.Millisecond
 
+// getFilename возвращает первый аргумент командной строки, не являющийся флагом
+func getFilename(defaultFilename string) string {
 	if len(os.Args) > 1 {
 		// Пропускаем флаги при поиске файла
-		for _, arg := range os.Args[1:] {
-			if !strings.HasPrefix(arg, "-") {
-				filename = arg
-				break
+		args := os.Args[1:]
+		for i := 0; i < len(args); i++ {
+			if args[i] == "--lib" {
+				i++ // каталог флага --lib
+				continue
+			}
+			if !strings.HasPrefix(args[i], "-") {
+				return args[i]
 			}
 		}
 	}
+	return defaultFilename
+}
 
-	// Set up global parse function for module imports
-	parseFunc := func(code string) []modules.Expr {
-		// Для модулей используем специальный парсер, который не перезаписывает GlobalScope
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
+// libDirs собирает каталоги из флагов --lib dir и --lib=dir в порядке указания
+func libDirs(args []string) []string {
+	var dirs []string
+	for i := 0; i < len(args); i++ {
+		if dir, ok := strings.CutPrefix(args[i], "--lib="); ok {
+			dirs = append(dirs, dir)
+		} else if args[i] == "--lib" && i+1 < len(args) {
+			dirs = append(dirs, args[i+1])
+			i++
 		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
-	
-	// Set up parser function for macro template generation
-	macroParseFunc := func(code string) []ast.Expr {
-		return parser.NewParser(code).Parse()
 	}
-	ast.ParserFunc = macroParseFunc
+	return dirs
+}
 
-	// Используем NewParserFromFile для упрощения API
-	p, err := parser.NewParserFromFile(filename)
-	if err != nil {
-		fmt.Printf("Error creating parser: %v\n", err)
-		return
+// runCheck проверяет типы в файлах и возвращает код выхода: 1, если найдены ошибки
+func runCheck(files []string) int {
+	if len(files) == 0 {
+		fmt.Println("usage: foo check file.foo [file.foo ...]")
+		return 2
 	}
 
-	// Получаем scope из парсера и инициализируем встроенные функции
-	scopeStack := p.GetScopeStack()
+	failed := false
+	for i := 0; i < len(files); i++ {
+		filename := files[i]
+		if filename == "--lib" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(filename, "--lib=") {
+			continue
+		}
 
-	// Инициализируем встроенные функции с этим scope
-	builtin.InitializeMathFunctions(scopeStack)
-	builtin.InitializeStringFunctions(scopeStack)
-	builtin.InitializeFilesystemFunctions(scopeStack)
-	builtin.InitializeHttpFunctions(scopeStack)
-	builtin.InitializeChannelFunctions(scopeStack)
-	builtin.InitializeTimeFunctions(scopeStack)
-	builtin.InitializeCryptoFunctions(scopeStack)
-	builtin.InitializeRegexFunctions(scopeStack)
-	builtin.InitializeSyncFunctions(scopeStack)
+		exprs, err := interpreter.New().ParseFileForCheck(filename)
+		if err != nil {
+			fmt.Printf("%s: %v\n", filename, err)
+			failed = true
+			continue
+		}
 
-	// Новые критически важные функции
-	builtin.InitializeStdioFunctions(scopeStack)
-	builtin.InitializeProcessFunctions(scopeStack)
-	builtin.InitializeCliFunctions(scopeStack)
-	builtin.InitializeDebugFunctions(scopeStack)
+		errors := checker.Check(exprs)
+		for _, e := range errors {
+			fmt.Println(e)
+		}
+		if len(errors) > 0 {
+			fmt.Printf("%s: %d type error(s)\n", filename, len(errors))
+			failed = true
+		}
+	}
 
-	// Инициализируем CLI аргументы
-	builtin.InitCLI(os.Args)
+	if failed {
+		return 1
+	}
+	return 0
+}
 
-	// 🔥 Новые улучшения: Extension methods и глобальные объекты
-	builtin.InitializeSystemExtensions(scopeStack) // Extension methods для System, IO, Console и т.д.
-	builtin.InitializeGlobalObjects(scopeStack)    // Глобальные объекты IO, System, Console, Process и т.д.
-	builtin.InitializeResultFunctions(scopeStack)  // Result функции Ok/Err для обработки ошибок
+// runMod выполняет команду foo mod для ближайшего foo.mod над текущим каталогом
+func runMod(args []string) int {
+	if len(args) != 1 || args[0] != "vendor" && args[0] != "tidy" {
+		fmt.Println("usage: foo mod vendor|tidy")
+		return 2
+	}
+
+	manifest, err := modules.FindManifest(".")
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if manifest == nil {
+		fmt.Printf("%s not found in current directory or any parent\n", modules.ManifestFile)
+		return 1
+	}
 
-	exprs := p.ParseWithModules()
+	if args[0] == "tidy" {
+		removed, err := modules.Tidy(manifest)
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		for _, name := range removed {
+			fmt.Printf("removed unused package %s\n", name)
+		}
+		return 0
+	}
 
-	for _, expr := range exprs {
-		expr.Eval()
+	deps, err := modules.Vendor(manifest)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	for _, dep := range deps {
+		fmt.Printf("vendored %s %s\n", dep.Name, dep.Version)
 	}
+	return 0
 }
 
 // RunBytecodeMode запускает bytecode режим
 func RunBytecodeMode() {
-	// mainBytecode()
+	mainBytecode()
 }
 
 // printUsage выводит справку по использованию
@@ -117,12 +237,19 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Использование:")
 	fmt.Println("  go run main.go [файл.foo] [флаги]")
+	fmt.Println("  go run main.go repl               Интерактивный режим (REPL)")
+	fmt.Println("  go run main.go check файл.foo     Проверка типов без выполнения")
+	fmt.Println("  go run main.go mod vendor         Скопировать зависимости foo.mod в vendor/ и записать foo.lock")
+	fmt.Println("  go run main.go mod tidy           Убрать неиспользуемые зависимости и обновить foo.lock")
 	fmt.Println()
 	fmt.Println("Флаги:")
 	fmt.Println("  -b, --bytecode    Использовать bytecode VM (оптимизированный)")
 	fmt.Println("  -d, --disassemble Показать дизассемблированный bytecode")
 	fmt.Println("  -p, --profile     Показать профилирование производительности")
 	fmt.Println("  -c, --compare     Сравнить производительность tree-walking vs bytecode")
+	fmt.Println("  --check-overflow  Ошибка выполнения при переполнении int")
+	fmt.Println("  --lib <каталог>   Каталог поиска модулей (можно повторять, дополняет FOO_PATH)")
+	fmt.Println("  --watch           Перезагружать измененные импортированные модули на лету (без --bytecode)")
 	fmt.Println("  -h, --help        Показать эту справку")
 	fmt.Println()
 	fmt.Println("Примеры:")
@@ -131,6 +258,8 @@ func printUsage() {
 	fmt.Println("  go run main.go examples/test_bytecode_demo.foo -b # bytecode VM с файлом")
 	fmt.Println("  go run main.go --bytecode --profile --disassemble # полная диагностика")
 	fmt.Println("  go run main.go --bytecode --compare               # сравнение производительности")
+	fmt.Println("  go run main.go repl                               # интерактивный режим")
+	fmt.Println("  go run main.go check examples/main.foo            # проверка типов")
 	fmt.Println()
 	fmt.Println("Возможности:")
 	fmt.Println("  ✅ Generic функции и типизация")
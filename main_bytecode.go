@@ -5,49 +5,34 @@ import (
 	"os"
 	"strings"
 	"time"
-	"foo_lang/parser"
-	"foo_lang/bytecode"
-	"foo_lang/scope"
+	"foo_lang/ast"
 	"foo_lang/builtin"
+	"foo_lang/bytecode"
+	"foo_lang/interpreter"
+	"foo_lang/value"
 )
 
 // Альтернативная точка входа для выполнения через bytecode VM
 func mainBytecode() {
-	// Проверяем аргументы командной строки
-	filename := "examples/test_bytecode_demo.foo"
-	if len(os.Args) > 1 {
-		filename = os.Args[1]
-	}
-
-	// Читаем файл
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Printf("Ошибка чтения файла %s: %v\n", filename, err)
-		os.Exit(1)
-	}
+	filename := getFilename("examples/test_bytecode_demo.foo")
 
 	fmt.Printf("🚀 Запуск foo_lang через Bytecode VM: %s\n", filename)
 	fmt.Println(strings.Repeat("=", 51))
 
-	// Инициализируем scope с встроенными функциями
-	globalScope := scope.NewScopeStack()
-	builtin.InitializeMathFunctions(globalScope)
-	builtin.InitializeStringFunctions(globalScope)
-	builtin.InitializeFilesystemFunctions(globalScope)
-	builtin.InitializeHttpFunctions(globalScope)
+	builtin.InitCLI(os.Args)
 
-	// Парсим код (для статистики)
+	// Парсим код: scope парсера содержит функции, зарегистрированные при разборе
 	startParse := time.Now()
-	_ = parser.NewParser(content).Parse()
+	exprs, interp, err := parseProgram(filename)
+	if err != nil {
+		fmt.Printf("Ошибка разбора файла %s: %v\n", filename, err)
+		os.Exit(1)
+	}
 	parseTime := time.Since(startParse)
 
-	// Создаем chunk вручную (компилятор пока не реализован)
+	// Компилируем AST в bytecode
 	startCompile := time.Now()
-	chunk := bytecode.NewChunk()
-	// Простая демо-программа: 10 + 5 = 15
-	chunk.WriteInstruction(bytecode.OP_CONSTANT, []int{chunk.AddConstant(int64(10))}, 1)
-	chunk.WriteInstruction(bytecode.OP_CONSTANT, []int{chunk.AddConstant(int64(5))}, 1)
-	chunk.WriteInstruction(bytecode.OP_ADD, nil, 1)
+	chunk := bytecode.Compile(exprs)
 	compileTime := time.Since(startCompile)
 
 	// Выводим статистику компиляции
@@ -67,20 +52,20 @@ func mainBytecode() {
 
 	// Выполняем в VM
 	startExecution := time.Now()
-	vm := bytecode.NewVM(chunk, globalScope)
-	result := vm.Run()
+	vm := bytecode.NewVMWithRuntime(chunk, interp.Runtime())
+	result, err := runVM(vm, interp.Runtime())
 	executionTime := time.Since(startExecution)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	// Выводим результат выполнения
 	fmt.Println()
 	fmt.Println(strings.Repeat("=", 51))
 	
 	if result != nil && result.Any() != nil {
-		if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
-			fmt.Printf("❌ Ошибка выполнения: %s\n", str)
-		} else {
-			fmt.Printf("✅ Выполнение завершено, результат: %v\n", result.Any())
-		}
+		fmt.Printf("✅ Выполнение завершено, результат: %v\n", result.Any())
 	} else {
 		fmt.Printf("✅ Выполнение завершено успешно\n")
 	}
@@ -100,8 +85,32 @@ func mainBytecode() {
 	if shouldComparePerformance() {
 		fmt.Println()
 		fmt.Println("🏁 Сравнение производительности:")
-		compareWithTreeWalking(content, globalScope)
+		compareWithTreeWalking(filename)
+	}
+}
+
+// runVM выполняет программу в VM. Ошибка выполнения возвращается со стеком
+// вызовов foo, как в tree-walking режиме
+func runVM(vm *bytecode.VM, rt *ast.Runtime) (result *value.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ast.NewScriptError(rt, r)
+		}
+	}()
+
+	return vm.Run(), nil
+}
+
+// parseProgram парсит файл в новом интерпретаторе со встроенными функциями
+func parseProgram(filename string) ([]ast.Expr, *interpreter.Interpreter, error) {
+	interp := newInterpreter()
+
+	exprs, err := interp.ParseFile(filename)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	return exprs, interp, nil
 }
 
 // shouldShowDisassembly проверяет, нужно ли показывать дизассемблированный код
@@ -135,32 +144,27 @@ func shouldComparePerformance() bool {
 }
 
 // compareWithTreeWalking сравнивает производительность bytecode VM с tree-walking
-func compareWithTreeWalking(content []byte, globalScope *scope.ScopeStack) {
-	// Tree-walking выполнение
-	startTreeWalk := time.Now()
-	exprs := parser.NewParser(content).Parse()
-	
-	// Отключаем вывод для tree-walking
+func compareWithTreeWalking(filename string) {
+	// Отключаем вывод программы на время замеров
 	originalStdout := os.Stdout
 	os.Stdout = nil
-	
-	for _, expr := range exprs {
-		expr.Eval()
-	}
-	
-	os.Stdout = originalStdout
+
+	// Tree-walking выполнение
+	startTreeWalk := time.Now()
+	exprs, interp, _ := parseProgram(filename)
+	interp.Run(exprs)
 	treeWalkTime := time.Since(startTreeWalk)
 
-	// Bytecode выполнение
+	// Bytecode выполнение той же программы
 	startBytecode := time.Now()
-	// Создаем простой chunk для сравнения
-	chunk := bytecode.NewChunk()
-	chunk.WriteInstruction(bytecode.OP_CONSTANT, []int{chunk.AddConstant(int64(42))}, 1)
-	vm := bytecode.NewVM(chunk, globalScope)
+	exprs, interp, _ = parseProgram(filename)
+	vm := bytecode.NewVMWithRuntime(bytecode.Compile(exprs), interp.Runtime())
 	vm.GetProfiler().Disable() // отключаем профилирование для чистого сравнения
 	vm.Run()
 	bytecodeTime := time.Since(startBytecode)
 
+	os.Stdout = originalStdout
+
 	// Сравнение
 	speedup := float64(treeWalkTime) / float64(bytecodeTime)
 	
@@ -173,4 +177,3 @@ func compareWithTreeWalking(content []byte, globalScope *scope.ScopeStack) {
 		fmt.Printf("   🐌 Bytecode медленнее в %.2fx раз\n", 1/speedup)
 	}
 }
-
@@ -0,0 +1,101 @@
+package modules
+
+import (
+	"fmt"
+	"foo_lang/scope"
+	"foo_lang/value"
+)
+
+// DefaultExport is the export name of `export default`: import name from "./x"
+const DefaultExport = "default"
+
+// TypeInfoSuffix marks the scope entry with type info of an enum or a type
+// alias: Color__TypeInfo next to the Color object
+const TypeInfoSuffix = "__TypeInfo"
+
+// ExportKind tells values from type exports
+type ExportKind int
+
+const (
+	ValueExport ExportKind = iota // fn, let, const, export default
+	TypeExport                    // struct, interface, enum, type alias
+)
+
+func (k ExportKind) String() string {
+	if k == TypeExport {
+		return "type"
+	}
+	return "value"
+}
+
+// Export is one entry of a module's export table
+type Export struct {
+	Name  string
+	Kind  ExportKind
+	Value *value.Value
+	Type  *value.Value // Type info for the checks of `let x: Name` (enums, type aliases), nil otherwise
+}
+
+// Exports is the export table of a module in declaration order
+type Exports struct {
+	names   []string
+	entries map[string]*Export
+}
+
+// NewExports creates an empty export table
+func NewExports() *Exports {
+	return &Exports{entries: make(map[string]*Export)}
+}
+
+// Set adds an export declared by the module itself. A repeated declaration
+// replaces the previous one, like a repeated top-level declaration does
+func (e *Exports) Set(export *Export) {
+	if _, exists := e.entries[export.Name]; !exists {
+		e.names = append(e.names, export.Name)
+	}
+	e.entries[export.Name] = export
+}
+
+// Add adds a re-exported entry. Re-exporting the same value twice is
+// allowed (diamond re-exports); a different value under the name is an error
+func (e *Exports) Add(export *Export) error {
+	if existing, exists := e.entries[export.Name]; exists {
+		if existing.Value == export.Value {
+			return nil
+		}
+		return fmt.Errorf("duplicate export '%s'", export.Name)
+	}
+	e.Set(export)
+	return nil
+}
+
+// Get returns an export by name
+func (e *Exports) Get(name string) (*Export, bool) {
+	export, ok := e.entries[name]
+	return export, ok
+}
+
+// Names returns exported names in declaration order
+func (e *Exports) Names() []string {
+	return append([]string(nil), e.names...)
+}
+
+// Len returns the number of exports
+func (e *Exports) Len() int {
+	return len(e.names)
+}
+
+// Binding maps an export of a module to a local name: the `b as c` of
+// import { b as c } and export { b as c }
+type Binding struct {
+	Name  string // Export name in the source module
+	Local string // Name in the importing scope or the re-exporting module
+}
+
+// bind sets an export and its type info in target under name
+func bind(target *scope.ScopeStack, name string, export *Export) {
+	target.Set(name, export.Value)
+	if export.Type != nil {
+		target.Set(name+TypeInfoSuffix, export.Type)
+	}
+}
@@ -0,0 +1,234 @@
+package modules
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LockFile records the exact content of every dependency next to foo.mod:
+//
+//	utils 0.3.0 h1:2Ykbx...=
+//
+// Imports through a package fail when its content no longer matches
+const LockFile = "foo.lock"
+
+const lockHeader = "// Code generated by foo mod. DO NOT EDIT.\n"
+
+// LockEntry is one dependency in foo.lock
+type LockEntry struct {
+	Name    string
+	Version string // Version from the dependency's foo.mod, "-" if it has none
+	Hash    string
+}
+
+// ReadLock reads foo.lock from dir; nil if the package has no lock file
+func ReadLock(dir string) ([]LockEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LockFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read lock file: %s", err)
+	}
+
+	entries := []LockEntry{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "h1:") {
+			return nil, fmt.Errorf("%s:%d: expected '<name> <version> h1:<hash>'", filepath.Join(dir, LockFile), line)
+		}
+		entries = append(entries, LockEntry{Name: fields[0], Version: fields[1], Hash: fields[2]})
+	}
+	return entries, nil
+}
+
+// WriteLock writes foo.lock to dir with entries sorted by name
+func WriteLock(dir string, entries []LockEntry) error {
+	sorted := append([]LockEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var b strings.Builder
+	b.WriteString(lockHeader)
+	for _, entry := range sorted {
+		fmt.Fprintf(&b, "%s %s %s\n", entry.Name, entry.Version, entry.Hash)
+	}
+	return os.WriteFile(filepath.Join(dir, LockFile), []byte(b.String()), 0644)
+}
+
+// HashFiles returns the content hash of a file tree: "h1:" and base64 SHA-256
+// over the sorted "<sha256 of file> <slash path>" lines. A directory, its
+// vendored copy and the archive it came from have the same hash
+func HashFiles(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summary := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(summary, "%x %s\n", sha256.Sum256(files[name]), name)
+	}
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil))
+}
+
+// HashSource hashes a dependency directory or archive
+func HashSource(source string) (string, error) {
+	files, err := readDependency(source)
+	if err != nil {
+		return "", err
+	}
+	return HashFiles(files), nil
+}
+
+// readDependency reads all files of a dependency: a directory (without its own
+// vendor directory) or a .tar.gz/.tgz/.zip archive. Keys are slash paths
+func readDependency(source string) (map[string][]byte, error) {
+	if isArchive(source) {
+		return readArchive(source)
+	}
+
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(source, p)
+		if d.IsDir() {
+			if rel == VendorDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read dependency %s: %s", source, err)
+	}
+	return files, nil
+}
+
+func isArchive(source string) bool {
+	return strings.HasSuffix(source, ".tar.gz") || strings.HasSuffix(source, ".tgz") || strings.HasSuffix(source, ".zip")
+}
+
+// readArchive reads regular files of an archive. A single top-level directory
+// (json-1.0/...) is stripped so the archive matches the unpacked package
+func readArchive(archive string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	add := func(name string, r io.Reader) error {
+		name = path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "./"))
+		if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return fmt.Errorf("unsafe path '%s'", name)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		files[name] = data
+		return nil
+	}
+
+	var err error
+	if strings.HasSuffix(archive, ".zip") {
+		err = readZip(archive, add)
+	} else {
+		err = readTarGz(archive, add)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read archive %s: %s", archive, err)
+	}
+	return stripTopDir(files), nil
+}
+
+func readZip(archive string, add func(string, io.Reader) error) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = add(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readTarGz(archive string, add func(string, io.Reader) error) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeReg {
+			if err := add(header.Name, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func stripTopDir(files map[string][]byte) map[string][]byte {
+	top := ""
+	for name := range files {
+		dir, _, found := strings.Cut(name, "/")
+		if !found || top != "" && dir != top {
+			return files
+		}
+		top = dir
+	}
+
+	stripped := make(map[string][]byte, len(files))
+	for name, data := range files {
+		stripped[strings.TrimPrefix(name, top+"/")] = data
+	}
+	return stripped
+}
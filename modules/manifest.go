@@ -0,0 +1,192 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the package manifest in the package root:
+//
+//	package shop
+//	version 1.2.0
+//
+//	require utils ../utils
+//	require (
+//	    json vendor-archives/json-1.0.tar.gz
+//	)
+//
+// A requirement source is a local directory or a .tar.gz/.tgz/.zip archive,
+// relative to the manifest directory
+const ManifestFile = "foo.mod"
+
+// VendorDir holds copies of all dependencies made by "foo mod vendor"
+const VendorDir = "vendor"
+
+// Requirement is a dependency declared in foo.mod
+type Requirement struct {
+	Name   string // Import prefix: import { f } from "utils/strings"
+	Source string // Directory or archive as written in foo.mod
+}
+
+// Manifest is a parsed foo.mod
+type Manifest struct {
+	Dir      string // Absolute directory containing foo.mod
+	Name     string
+	Version  string
+	Requires []Requirement
+}
+
+// ParseManifest reads and parses a foo.mod file
+func ParseManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest: %s", err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest path: %s", path)
+	}
+	return parseManifest(path, data, dir)
+}
+
+// parseManifest parses foo.mod content; requirement sources are relative to dir
+func parseManifest(path string, data []byte, dir string) (*Manifest, error) {
+	m := &Manifest{Dir: dir}
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		errorf := func(format string, args ...interface{}) error {
+			return fmt.Errorf("%s:%d: %s", path, line, fmt.Sprintf(format, args...))
+		}
+
+		if inBlock {
+			if fields[0] == ")" {
+				inBlock = false
+				continue
+			}
+			if err := m.addRequire(fields); err != nil {
+				return nil, errorf("%s", err)
+			}
+			continue
+		}
+
+		switch fields[0] {
+		case "package", "version":
+			if len(fields) != 2 {
+				return nil, errorf("expected '%s <value>'", fields[0])
+			}
+			if fields[0] == "package" {
+				m.Name = fields[1]
+			} else {
+				m.Version = fields[1]
+			}
+		case "require":
+			if len(fields) == 2 && fields[1] == "(" {
+				inBlock = true
+				continue
+			}
+			if err := m.addRequire(fields[1:]); err != nil {
+				return nil, errorf("%s", err)
+			}
+		default:
+			return nil, errorf("unknown directive '%s'", fields[0])
+		}
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("%s: unterminated require block", path)
+	}
+	if m.Name == "" {
+		return nil, fmt.Errorf("%s: missing 'package' directive", path)
+	}
+	return m, nil
+}
+
+func (m *Manifest) addRequire(fields []string) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("expected 'require <name> <path>'")
+	}
+	name := fields[0]
+	if strings.ContainsAny(name, `/\:`) || name == "std" {
+		return fmt.Errorf("invalid package name '%s'", name)
+	}
+	if _, exists := m.Require(name); exists {
+		return fmt.Errorf("package '%s' is required twice", name)
+	}
+	m.Requires = append(m.Requires, Requirement{Name: name, Source: fields[1]})
+	return nil
+}
+
+// FindManifest returns the nearest foo.mod in dir or its parents, nil if there is none
+func FindManifest(dir string) (*Manifest, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, ManifestFile)
+		if _, err := os.Stat(path); err == nil {
+			return ParseManifest(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Require returns the requirement with the given package name
+func (m *Manifest) Require(name string) (Requirement, bool) {
+	for _, req := range m.Requires {
+		if req.Name == name {
+			return req, true
+		}
+	}
+	return Requirement{}, false
+}
+
+// SourcePath returns the absolute path of a requirement source
+func (m *Manifest) SourcePath(req Requirement) string {
+	if filepath.IsAbs(req.Source) {
+		return req.Source
+	}
+	return filepath.Join(m.Dir, req.Source)
+}
+
+// Format returns the canonical foo.mod text with requirements sorted by name
+func (m *Manifest) Format() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n", m.Name)
+	if m.Version != "" {
+		fmt.Fprintf(&b, "version %s\n", m.Version)
+	}
+
+	requires := append([]Requirement(nil), m.Requires...)
+	sort.Slice(requires, func(i, j int) bool { return requires[i].Name < requires[j].Name })
+	if len(requires) > 0 {
+		b.WriteString("\nrequire (\n")
+		for _, req := range requires {
+			fmt.Fprintf(&b, "\t%s %s\n", req.Name, req.Source)
+		}
+		b.WriteString(")\n")
+	}
+	return []byte(b.String())
+}
+
+// Save writes the canonical manifest back to foo.mod
+func (m *Manifest) Save() error {
+	return os.WriteFile(filepath.Join(m.Dir, ManifestFile), m.Format(), 0644)
+}
@@ -3,85 +3,316 @@ package modules
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 	"foo_lang/scope"
+	"foo_lang/std"
 	"foo_lang/value"
 )
 
+// StdPrefix marks modules of the standard library: import * as fs from "std:fs"
+const StdPrefix = "std:"
+
+// PathEnv is the environment variable with extra module directories,
+// separated like PATH entries
+const PathEnv = "FOO_PATH"
+
+// State is the initialization state of a module
+type State int
+
+const (
+	Loading State = iota // Top-level code is running; importing the module again is a cycle
+	Loaded               // Top-level code finished, exports are ready
+	Failed               // Top-level code or one of its imports failed, see Module.Err
+)
+
+func (s State) String() string {
+	switch s {
+	case Loading:
+		return "loading"
+	case Loaded:
+		return "loaded"
+	}
+	return "failed"
+}
+
 // Module represents a loaded module
 type Module struct {
-	Path     string                     // File path of the module
-	Exports  map[string]*value.Value    // Exported variables/functions
-	Loaded   bool                       // Whether the module has been loaded
-	Scope    *scope.ScopeStack         // Module's own scope
+	Path    string            // File path of the module
+	Exports *Exports          // Export table, filled by export statements while the module runs
+	State   State             // Loading -> Loaded or Failed
+	Err     error             // Why the module failed
+	Scope   *scope.ScopeStack // Module's own scope
+	ModTime time.Time         // Modification time of the file the module was run from
+
+	prelude map[string]*value.Value // Scope entries of the prelude, not declared by the module
+	loader  *Importer               // Importer running the top-level code while the module is loading
+	done    chan struct{}           // Closed when the module stops loading
 }
 
-// ModuleCache stores loaded modules to prevent re-loading
-var ModuleCache = make(map[string]*Module)
+// Importer is the import chain of one caller (the main script, an async task):
+// modules whose top-level code it is running, outermost first. Load finds
+// cycles with it, so concurrent callers importing the same module do not see
+// each other's chains. An Importer must not be shared between goroutines
+type Importer struct {
+	chain   []string
+	waiting *Module // Module loaded by another importer that this one waits for
+}
 
-// ParseFunc represents a function that can parse code and return AST expressions
-type ParseFunc func(string) []Expr
+// private reports whether the module declares name at the top level
+// (or imports it) without exporting it
+func (m *Module) private(name string) bool {
+	val, ok := m.Scope.Get(name)
+	return ok && val != m.prelude[name]
+}
+
+// Cache stores loaded modules of one interpreter to prevent re-loading
+type Cache struct {
+	mu      sync.RWMutex
+	modules map[string]*Module
+	order   []string // Modules in the order their top-level code finished
+
+	// SearchPath lists directories searched for imports that are neither
+	// relative nor absolute ("lib/util.foo"), after the working directory
+	SearchPath []string
 
-// Expr interface for AST expressions (to avoid circular import)
-type Expr interface {
-	Eval() *value.Value
+	// Prelude fills a new module scope before the module runs (builtins)
+	Prelude func(moduleScope *scope.ScopeStack)
+
+	pkgMu     sync.Mutex
+	manifests map[string][]*Manifest // foo.mod chains by importing directory
+	verified  map[string]error       // foo.lock checks by package directory
 }
 
-// LoadModule loads a module from the given path
-func LoadModule(modulePath string, parseFunc ParseFunc) (*Module, error) {
+// NewCache creates an empty module cache searching the FOO_PATH directories
+func NewCache() *Cache {
+	return &Cache{
+		modules:    make(map[string]*Module),
+		SearchPath: DefaultSearchPath(),
+		manifests:  make(map[string][]*Manifest),
+		verified:   make(map[string]error),
+	}
+}
+
+// DefaultSearchPath returns the directories listed in FOO_PATH
+func DefaultSearchPath() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(PathEnv)) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// AddSearchPath puts dirs in front of the search path (--lib flags)
+func (c *Cache) AddSearchPath(dirs ...string) {
+	c.SearchPath = append(append([]string{}, dirs...), c.SearchPath...)
+}
+
+// Get returns a loaded module by its absolute path
+func (c *Cache) Get(absPath string) (*Module, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	module, exists := c.modules[absPath]
+	return module, exists
+}
+
+// Modules returns cached modules sorted by path, including failed ones
+func (c *Cache) Modules() []*Module {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	list := make([]*Module, 0, len(c.modules))
+	for _, module := range c.modules {
+		list = append(list, module)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list
+}
+
+// ExecFunc parses and evaluates module source code in module.Scope.
+// Export statements add entries to module.Exports
+type ExecFunc func(module *Module, code string)
+
+// InitOrder returns paths of loaded modules in initialization order. Top-level
+// code of a module runs once, on its first import, after the modules it
+// imports (depth-first, in the order of import statements)
+func (c *Cache) InitOrder() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.order...)
+}
+
+// Load loads a module from a resolved path: a file path or "std:name".
+// A module imported while importer runs its top-level code is an import cycle.
+// If another importer is running the module, Load waits until it finishes
+func (c *Cache) Load(modulePath string, importer *Importer, exec ExecFunc) (*Module, error) {
 	// Normalize the path
-	absPath, err := filepath.Abs(modulePath)
-	if err != nil {
-		return nil, fmt.Errorf("invalid module path: %s", modulePath)
+	absPath := modulePath
+	if !strings.HasPrefix(modulePath, StdPrefix) {
+		var err error
+		if absPath, err = filepath.Abs(modulePath); err != nil {
+			return nil, fmt.Errorf("invalid module path: %s", modulePath)
+		}
 	}
-	
-	// Check if already loaded
-	if module, exists := ModuleCache[absPath]; exists {
+	if importer == nil {
+		importer = &Importer{}
+	}
+
+	for {
+		if module, err := c.cached(absPath, importer); module != nil || err != nil {
+			return module, err
+		}
+
+		module := c.newModule(absPath)
+		content, err := readSource(absPath)
+		if err != nil {
+			return nil, err
+		}
+
+		// The module is cached before it runs, so an import back to it is a cycle
+		c.mu.Lock()
+		if _, exists := c.modules[absPath]; exists {
+			// Another importer started the module meanwhile
+			c.mu.Unlock()
+			continue
+		}
+		module.loader = importer
+		c.modules[absPath] = module
+		importer.chain = append(importer.chain, absPath)
+		c.mu.Unlock()
+
+		c.run(module, importer, string(content), exec)
 		return module, nil
 	}
-	
-	// Read the module file
-	content, err := os.ReadFile(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("cannot read module file: %s", err)
+}
+
+// cached returns a module from the cache, waiting while another importer runs
+// its top-level code. nil and no error mean the module is not cached
+func (c *Cache) cached(absPath string, importer *Importer) (*Module, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		module, exists := c.modules[absPath]
+		if !exists {
+			return nil, nil
+		}
+		switch module.State {
+		case Loaded:
+			return module, nil
+		case Failed:
+			return nil, fmt.Errorf("module %s failed to initialize: %v", absPath, module.Err)
+		}
+
+		if cycle := c.waitCycle(importer, module); cycle != nil {
+			return nil, fmt.Errorf("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		importer.waiting = module
+		c.mu.Unlock()
+		<-module.done
+		c.mu.Lock()
+		importer.waiting = nil
 	}
-	
-	// Create new module
+}
+
+// waitCycle returns the import cycle that waiting for a loading module would
+// close: the module is in the importer's own chain, or its importer waits
+// (maybe through other importers) for a module this importer is running.
+// import cycle: /app/a.foo -> /app/b.foo -> /app/a.foo. Called under c.mu
+func (c *Cache) waitCycle(importer *Importer, module *Module) []string {
+	chain := append([]string(nil), importer.chain...)
+	for target := module; target != nil; {
+		owner := target.loader
+		if owner == importer {
+			return append(chain, target.Path)
+		}
+		if owner == nil {
+			return nil
+		}
+		chain = append(chain, owner.chain[slices.Index(owner.chain, target.Path):]...)
+		target = owner.waiting
+	}
+	return nil
+}
+
+// run executes the top-level code of a cached module and records its state
+func (c *Cache) run(module *Module, importer *Importer, code string, exec ExecFunc) {
+	defer func() {
+		r := recover()
+
+		c.mu.Lock()
+		importer.chain = importer.chain[:len(importer.chain)-1]
+		module.loader = nil
+		if r == nil {
+			module.State = Loaded
+			c.order = append(c.order, module.Path)
+		} else {
+			// An error in top-level code keeps propagating; the module stays failed
+			// instead of being cached with half of its exports
+			module.State = Failed
+			if err, ok := r.(error); ok {
+				module.Err = err
+			} else {
+				module.Err = fmt.Errorf("%v", r)
+			}
+		}
+		c.mu.Unlock()
+		close(module.done)
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	// Parse and execute the module in its own scope
+	exec(module, code)
+}
+
+// newModule creates a module in the Loading state with the prelude in its
+// own scope. The file time is taken before the source is read, so a change
+// made while the module runs is seen by the next check of ModTime
+func (c *Cache) newModule(absPath string) *Module {
 	module := &Module{
 		Path:    absPath,
-		Exports: make(map[string]*value.Value),
-		Loaded:  false,
+		Exports: NewExports(),
+		State:   Loading,
 		Scope:   scope.NewScopeStack(), // Each module has its own scope
+		done:    make(chan struct{}),
 	}
-	
-	// Save current global scope
-	originalScope := scope.GlobalScope
-	
-	// Set module scope as global temporarily
-	scope.GlobalScope = module.Scope
-	
-	// Parse and execute the module
-	exprs := parseFunc(string(content))
-	for _, expr := range exprs {
-		expr.Eval()
+	if info, err := os.Stat(absPath); err == nil {
+		module.ModTime = info.ModTime()
 	}
-	
-	// Collect exported items (items with "__export_" prefix)
-	for name, val := range module.Scope.GetAll() {
-		if strings.HasPrefix(name, "__export_") {
-			exportName := strings.TrimPrefix(name, "__export_")
-			module.Exports[exportName] = val
+	if c.Prelude != nil {
+		c.Prelude(module.Scope)
+	}
+	module.prelude = module.Scope.GetAll()
+	return module
+}
+
+// readSource reads a module file or an embedded standard library module
+func readSource(modulePath string) ([]byte, error) {
+	if name, ok := strings.CutPrefix(modulePath, StdPrefix); ok {
+		content, found := std.Source(name)
+		if !found {
+			return nil, fmt.Errorf("standard library has no module '%s'", modulePath)
 		}
+		return content, nil
 	}
-	
-	// Restore original scope
-	scope.GlobalScope = originalScope
-	
-	module.Loaded = true
-	ModuleCache[absPath] = module
-	
-	return module, nil
+
+	content, err := os.ReadFile(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read module file: %s", err)
+	}
+	return content, nil
 }
 
 // ResolveModulePath resolves relative module paths
@@ -91,7 +322,11 @@ func ResolveModulePath(currentFile, importPath string) string {
 	}
 	
 	// Handle relative paths
-	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+	if isRelative(importPath) {
+		// Standard library modules import each other: "./fs.foo" from "std:http"
+		if strings.HasPrefix(currentFile, StdPrefix) {
+			return StdPrefix + strings.TrimSuffix(path.Clean(importPath), ".foo")
+		}
 		dir := filepath.Dir(currentFile)
 		return filepath.Join(dir, importPath)
 	}
@@ -100,40 +335,171 @@ func ResolveModulePath(currentFile, importPath string) string {
 	return importPath
 }
 
-// ImportModule imports items from a module into current scope
-func ImportModule(modulePath string, currentFile string, importedItems []string, alias string, parseFunc ParseFunc) error {
-	// Resolve the path
-	resolvedPath := ResolveModulePath(currentFile, modulePath)
-	
-	// Load the module
-	module, err := LoadModule(resolvedPath, parseFunc)
+func isRelative(importPath string) bool {
+	return strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../")
+}
+
+// Resolve maps an import specifier to a module path for Load:
+//   - "./x.foo", "../x.foo" and absolute paths - files relative to currentFile
+//   - "std:fs" - std/fs.foo from the search path, otherwise the embedded standard library
+//   - "pkg/x" - package "pkg" required in the nearest foo.mod (see package.go)
+//   - "lib/x.foo" - the working directory, then each SearchPath directory;
+//     "std/fs.foo" falls back to the embedded standard library
+//
+// The .foo extension may be omitted everywhere except relative paths
+func (c *Cache) Resolve(currentFile, importPath string) (string, error) {
+	if name, ok := strings.CutPrefix(importPath, StdPrefix); ok {
+		name = strings.TrimSuffix(name, ".foo")
+		if found, ok := c.search(filepath.Join("std", name+".foo"), false); ok {
+			return found, nil
+		}
+		if _, ok := std.Source(name); !ok {
+			return "", fmt.Errorf("standard library has no module '%s'", importPath)
+		}
+		return StdPrefix + name, nil
+	}
+
+	if filepath.IsAbs(importPath) || isRelative(importPath) {
+		return ResolveModulePath(currentFile, importPath), nil
+	}
+
+	if found, ok, err := c.resolvePackage(currentFile, importPath); ok || err != nil {
+		return found, err
+	}
+
+	if found, ok := c.search(importPath, true); ok {
+		return found, nil
+	}
+	if name, ok := strings.CutPrefix(filepath.ToSlash(importPath), "std/"); ok {
+		if _, ok := std.Source(name); ok {
+			return StdPrefix + strings.TrimSuffix(name, ".foo"), nil
+		}
+	}
+
+	return "", fmt.Errorf("module '%s' not found in working directory or search path %v", importPath, c.SearchPath)
+}
+
+// search looks for a module file in the working directory (if cwd is set)
+// and in the search path directories
+func (c *Cache) search(importPath string, cwd bool) (string, bool) {
+	candidates := []string{importPath}
+	if filepath.Ext(importPath) == "" {
+		candidates = []string{importPath + ".foo", importPath}
+	}
+
+	dirs := c.SearchPath
+	if cwd {
+		dirs = append([]string{""}, dirs...)
+	}
+
+	for _, dir := range dirs {
+		for _, candidate := range candidates {
+			full := filepath.Join(dir, candidate)
+			if info, err := os.Stat(full); err == nil && !info.IsDir() {
+				return full, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Open resolves an import specifier and loads the module
+func (c *Cache) Open(currentFile, modulePath string, importer *Importer, exec ExecFunc) (*Module, error) {
+	resolvedPath, err := c.Resolve(currentFile, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	return c.Load(resolvedPath, importer, exec)
+}
+
+// Import loads a module and imports items from it into the target scope:
+//   - alias: import * as Name from "./module" - an object with every export
+//   - no items: import "./module" - every export except the default one
+//   - items: import { a, b as c } from "./module", import name from "./module"
+func (c *Cache) Import(target *scope.ScopeStack, modulePath string, currentFile string, items []Binding, alias string, importer *Importer, exec ExecFunc) error {
+	module, err := c.Open(currentFile, modulePath, importer, exec)
 	if err != nil {
 		return err
 	}
 	
-	// Import items into current scope
 	if alias != "" {
-		// Import as alias: import * as ModuleName from "./module"
 		moduleObj := make(map[string]*value.Value)
-		for name, val := range module.Exports {
-			moduleObj[name] = val
-		}
-		scope.GlobalScope.Set(alias, value.NewValue(moduleObj))
-	} else if len(importedItems) == 0 {
-		// Import all: import "./module"
-		for name, val := range module.Exports {
-			scope.GlobalScope.Set(name, val)
-		}
-	} else {
-		// Selective import: import { item1, item2 } from "./module"
-		for _, itemName := range importedItems {
-			if val, exists := module.Exports[itemName]; exists {
-				scope.GlobalScope.Set(itemName, val)
-			} else {
-				return fmt.Errorf("module %s does not export '%s'", modulePath, itemName)
+		for _, name := range module.Exports.Names() {
+			export, _ := module.Exports.Get(name)
+			moduleObj[name] = export.Value
+		}
+		target.Set(alias, value.NewValue(moduleObj))
+		return nil
+	}
+
+	if len(items) == 0 {
+		for _, name := range module.Exports.Names() {
+			if name == DefaultExport {
+				continue
 			}
+			export, _ := module.Exports.Get(name)
+			bind(target, name, export)
+		}
+		return nil
+	}
+
+	for _, item := range items {
+		export, err := lookup(module, modulePath, item.Name)
+		if err != nil {
+			return err
+		}
+		bind(target, item.Local, export)
+	}
+	return nil
+}
+
+// ReExport adds exports of another module to the export table of the
+// module being loaded: export { a, b as c } from "./x" or, without items,
+// export * from "./x" (every export except the default one)
+func (c *Cache) ReExport(target *Exports, modulePath string, currentFile string, items []Binding, importer *Importer, exec ExecFunc) error {
+	module, err := c.Open(currentFile, modulePath, importer, exec)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		for _, name := range module.Exports.Names() {
+			if name == DefaultExport {
+				continue
+			}
+			export, _ := module.Exports.Get(name)
+			if err := target.Add(export); err != nil {
+				return fmt.Errorf("%s (re-exported from %s)", err, modulePath)
+			}
+		}
+		return nil
+	}
+
+	for _, item := range items {
+		export, err := lookup(module, modulePath, item.Name)
+		if err != nil {
+			return err
+		}
+		renamed := *export
+		renamed.Name = item.Local
+		if err := target.Add(&renamed); err != nil {
+			return err
 		}
 	}
-	
 	return nil
-}
\ No newline at end of file
+}
+
+// lookup returns an export of a module; importing a name the module declares
+// without exporting is reported separately from a missing name
+func lookup(module *Module, modulePath, name string) (*Export, error) {
+	if export, ok := module.Exports.Get(name); ok {
+		return export, nil
+	}
+	if module.private(name) {
+		return nil, fmt.Errorf("'%s' is private to module %s, add 'export' to its declaration", name, modulePath)
+	}
+	if name == DefaultExport {
+		return nil, fmt.Errorf("module %s has no default export", modulePath)
+	}
+	return nil, fmt.Errorf("module %s does not export '%s'", modulePath, name)
+}
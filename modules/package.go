@@ -0,0 +1,341 @@
+package modules
+
+import (
+	"fmt"
+	"foo_lang/lexer"
+	"foo_lang/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackageIndex is the module imported by a bare package name: import * as u from "utils"
+const PackageIndex = "index.foo"
+
+// Dependency is a resolved package with its content
+type Dependency struct {
+	Name    string
+	Version string // "-" if the dependency has no foo.mod
+	Source  string // Absolute directory or archive path
+	Files   map[string][]byte
+}
+
+// Dependencies returns the requirements of m and, recursively, of dependencies
+// that have their own foo.mod, sorted by name. One name must come from one source
+func Dependencies(m *Manifest) ([]*Dependency, error) {
+	found := make(map[string]*Dependency)
+
+	var visit func(owner *Manifest) error
+	visit = func(owner *Manifest) error {
+		for _, req := range owner.Requires {
+			source := owner.SourcePath(req)
+			if dep, seen := found[req.Name]; seen {
+				if dep.Source != source {
+					return fmt.Errorf("package '%s' is required from both %s and %s", req.Name, dep.Source, source)
+				}
+				continue
+			}
+
+			files, err := readDependency(source)
+			if err != nil {
+				return fmt.Errorf("package '%s': %s", req.Name, err)
+			}
+			dep := &Dependency{Name: req.Name, Version: "-", Source: source, Files: files}
+			found[req.Name] = dep
+
+			manifestData, ok := files[ManifestFile]
+			if !ok {
+				continue
+			}
+			// Sources in an archive's foo.mod are relative to the archive location
+			dir := source
+			if isArchive(source) {
+				dir = filepath.Dir(source)
+			}
+			depManifest, err := parseManifest(filepath.Join(source, ManifestFile), manifestData, dir)
+			if err != nil {
+				return err
+			}
+			if depManifest.Version != "" {
+				dep.Version = depManifest.Version
+			}
+			if err := visit(depManifest); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(m); err != nil {
+		return nil, err
+	}
+
+	deps := make([]*Dependency, 0, len(found))
+	for _, dep := range found {
+		deps = append(deps, dep)
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps, nil
+}
+
+// lockEntries builds foo.lock entries for dependencies
+func lockEntries(deps []*Dependency) []LockEntry {
+	entries := make([]LockEntry, len(deps))
+	for i, dep := range deps {
+		entries[i] = LockEntry{Name: dep.Name, Version: dep.Version, Hash: HashFiles(dep.Files)}
+	}
+	return entries
+}
+
+// Vendor copies every dependency into vendor/<name> and writes foo.lock.
+// Archives are unpacked, so vendored packages import without the archives
+func Vendor(m *Manifest) ([]*Dependency, error) {
+	deps, err := Dependencies(m)
+	if err != nil {
+		return nil, err
+	}
+
+	vendor := filepath.Join(m.Dir, VendorDir)
+	if err := os.RemoveAll(vendor); err != nil {
+		return nil, fmt.Errorf("cannot clean %s: %s", vendor, err)
+	}
+	for _, dep := range deps {
+		for name, data := range dep.Files {
+			target := filepath.Join(vendor, dep.Name, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(target, data, 0644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return deps, WriteLock(m.Dir, lockEntries(deps))
+}
+
+// Tidy drops requirements that no .foo file of the package imports, rewrites
+// foo.mod in canonical form and regenerates foo.lock. Returns removed names
+func Tidy(m *Manifest) ([]string, error) {
+	used, err := importedPackages(m.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []Requirement
+	var removed []string
+	for _, req := range m.Requires {
+		if used[req.Name] {
+			kept = append(kept, req)
+		} else {
+			removed = append(removed, req.Name)
+		}
+	}
+	m.Requires = kept
+
+	deps, err := Dependencies(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Save(); err != nil {
+		return nil, err
+	}
+	return removed, WriteLock(m.Dir, lockEntries(deps))
+}
+
+// importedPackages returns first segments of package-style imports
+// ("utils/strings" -> utils) in .foo files under dir, skipping vendor/
+func importedPackages(dir string) (map[string]bool, error) {
+	used := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && d.Name() == VendorDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".foo" {
+			return nil
+		}
+
+		code, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		specs, err := importSpecifiers(code)
+		if err != nil {
+			return fmt.Errorf("%s: %s", p, err)
+		}
+		for _, spec := range specs {
+			if isRelative(spec) || filepath.IsAbs(spec) || strings.HasPrefix(spec, StdPrefix) {
+				continue
+			}
+			name, _, _ := strings.Cut(spec, "/")
+			used[name] = true
+		}
+		return nil
+	})
+	return used, err
+}
+
+// importSpecifiers returns module paths of import statements in code:
+// import "x", import { a } from "x", import * as m from "x"
+func importSpecifiers(code []byte) (specs []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	tokens := lexer.NewLexer(code).Tokens()
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i+1].Token != token.STRING {
+			continue
+		}
+		if tokens[i].Token == token.FROM || tokens[i].Token == token.IMPORT {
+			specs = append(specs, tokens[i+1].Value)
+		}
+	}
+	return specs, nil
+}
+
+// resolvePackage maps "name/sub/path" through the foo.mod files enclosing
+// currentFile. false - the import does not name a package
+func (c *Cache) resolvePackage(currentFile, importPath string) (string, bool, error) {
+	if strings.HasPrefix(currentFile, StdPrefix) {
+		return "", false, nil
+	}
+
+	chain, err := c.manifestChain(filepath.Dir(currentFile))
+	if err != nil || len(chain) == 0 {
+		return "", false, err
+	}
+
+	name, sub, _ := strings.Cut(filepath.ToSlash(importPath), "/")
+	for i, m := range chain {
+		// The package imports itself by name
+		if m.Name == name {
+			return packageModule(m.Dir, sub), true, nil
+		}
+
+		req, ok := m.Require(name)
+		if !ok {
+			continue
+		}
+		root, err := packageDir(chain[i:], m, req)
+		if err != nil {
+			return "", true, err
+		}
+		if err := c.verifyLocked(chain[len(chain)-1], name, root); err != nil {
+			return "", true, err
+		}
+		return packageModule(root, sub), true, nil
+	}
+	return "", false, nil
+}
+
+// packageDir finds the directory of a required package: vendor/<name> of the
+// declaring manifest or any enclosing one, then the source directory
+func packageDir(chain []*Manifest, declaring *Manifest, req Requirement) (string, error) {
+	for _, m := range chain {
+		vendored := filepath.Join(m.Dir, VendorDir, req.Name)
+		if info, err := os.Stat(vendored); err == nil && info.IsDir() {
+			return vendored, nil
+		}
+	}
+
+	source := declaring.SourcePath(req)
+	if isArchive(source) {
+		return "", fmt.Errorf("package '%s' is an archive (%s), run 'foo mod vendor'", req.Name, req.Source)
+	}
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("package '%s': directory %s not found", req.Name, source)
+	}
+	return source, nil
+}
+
+// packageModule returns the module file for a path inside a package
+func packageModule(root, sub string) string {
+	if sub == "" {
+		return filepath.Join(root, PackageIndex)
+	}
+	full := filepath.Join(root, filepath.FromSlash(sub))
+	if filepath.Ext(full) == "" {
+		full += ".foo"
+	}
+	return full
+}
+
+// manifestChain returns foo.mod files from dir up to the filesystem root,
+// nearest first
+func (c *Cache) manifestChain(dir string) ([]*Manifest, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c.pkgMu.Lock()
+	defer c.pkgMu.Unlock()
+	if chain, ok := c.manifests[abs]; ok {
+		return chain, nil
+	}
+
+	var chain []*Manifest
+	for d := abs; ; {
+		path := filepath.Join(d, ManifestFile)
+		if _, err := os.Stat(path); err == nil {
+			m, err := ParseManifest(path)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, m)
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+
+	c.manifests[abs] = chain
+	return chain, nil
+}
+
+// verifyLocked checks a package directory against foo.lock of the main
+// (outermost) package once per directory. Without foo.lock nothing is checked
+func (c *Cache) verifyLocked(main *Manifest, name, root string) error {
+	c.pkgMu.Lock()
+	defer c.pkgMu.Unlock()
+	if err, done := c.verified[root]; done {
+		return err
+	}
+
+	err := func() error {
+		entries, err := ReadLock(main.Dir)
+		if err != nil || entries == nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Name != name {
+				continue
+			}
+			hash, err := HashSource(root)
+			if err != nil {
+				return err
+			}
+			if hash != entry.Hash {
+				return fmt.Errorf("package '%s' does not match %s: got %s, locked %s; run 'foo mod tidy' or 'foo mod vendor'",
+					name, filepath.Join(main.Dir, LockFile), hash, entry.Hash)
+			}
+			return nil
+		}
+		return fmt.Errorf("package '%s' is missing from %s, run 'foo mod tidy'", name, filepath.Join(main.Dir, LockFile))
+	}()
+
+	c.verified[root] = err
+	return err
+}
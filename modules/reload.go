@@ -0,0 +1,104 @@
+package modules
+
+import (
+	"fmt"
+	"foo_lang/value"
+	"os"
+	"strings"
+)
+
+// Replacement is an export that Replace swapped in place
+type Replacement struct {
+	Name string
+	Old  any          // Data the export held before the reload
+	New  *value.Value // Value of the export in the new module run
+}
+
+// Changed reports whether the file of a module was modified after the
+// module ran. Standard library modules and deleted files never change
+func (m *Module) Changed() bool {
+	if strings.HasPrefix(m.Path, StdPrefix) {
+		return false
+	}
+	info, err := os.Stat(m.Path)
+	return err == nil && !info.ModTime().Equal(m.ModTime)
+}
+
+// Rerun executes the current source of a cached module in a fresh scope.
+// The cache is not touched: Replace applies the result, and if the module
+// code fails (the panic propagates like in Load) the old module stays
+func (c *Cache) Rerun(absPath string, exec ExecFunc) (*Module, error) {
+	if _, exists := c.Get(absPath); !exists {
+		return nil, fmt.Errorf("module %s is not loaded", absPath)
+	}
+
+	module := c.newModule(absPath)
+	content, err := readSource(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	exec(module, string(content))
+	module.State = Loaded
+	return module, nil
+}
+
+// Replace puts a module produced by Rerun in place of the cached one.
+//
+// Exports that existed before are swapped in place: importers hold the same
+// *value.Value and get the new code on the next call. New exports are added,
+// exports missing from the new source keep their old values.
+//
+// keep(old, new) marks values holding state of running code (channels,
+// mutexes): top-level variables and exports for which it returns true keep
+// the old value, so the new code works with the same channel
+func (c *Cache) Replace(fresh *Module, keep func(old, new *value.Value) bool) []Replacement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	module, exists := c.modules[fresh.Path]
+	if !exists {
+		return nil
+	}
+
+	for name, old := range module.Scope.GetAll() {
+		if old == module.prelude[name] {
+			continue
+		}
+		if val, declared := fresh.Scope.Get(name); declared && val != fresh.prelude[name] && keep(old, val) {
+			fresh.Scope.Set(name, old)
+		}
+	}
+
+	var replaced []Replacement
+	for _, name := range fresh.Exports.Names() {
+		export, _ := fresh.Exports.Get(name)
+		previous, existed := module.Exports.Get(name)
+		if !existed {
+			module.Exports.Set(export)
+			continue
+		}
+		if keep(previous.Value, export.Value) {
+			continue
+		}
+
+		replaced = append(replaced, Replacement{Name: name, Old: previous.Value.Any(), New: export.Value})
+		previous.Value.Replace(export.Value)
+		if previous.Type != nil && export.Type != nil {
+			previous.Type.Replace(export.Type)
+		} else {
+			previous.Type = export.Type
+		}
+		previous.Kind = export.Kind
+	}
+
+	if module.State != Loaded {
+		c.order = append(c.order, module.Path)
+	}
+	module.Scope = fresh.Scope
+	module.prelude = fresh.prelude
+	module.ModTime = fresh.ModTime
+	module.State = Loaded
+	module.Err = nil
+	return replaced
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"foo_lang/ast"
 	"foo_lang/lexer"
+	"foo_lang/modules"
 	"foo_lang/scope"
 	"foo_lang/token"
+	"math/big"
 	"os"
 	"slices"
 	"strconv"
@@ -17,7 +19,28 @@ type Parser struct {
 	pos         int
 	currentFile string            // Путь к текущему файлу для обработки импортов
 	scopeStack  *scope.ScopeStack // Стек областей видимости для парсера
+	runtime     *ast.Runtime      // Runtime, в котором регистрируются объявления (nil - ast.GlobalRuntime)
 	sourceText  string            // Исходный текст для обработки шаблонов
+	noStruct    bool              // '{' после имени открывает блок, а не экземпляр структуры (заголовок for-in)
+	labels      []string          // метки объемлющих циклов для break/continue label
+	noEval      bool              // константы объявляются без вычисления (WithoutEval)
+}
+
+// spanned записывает в узел участок исходного кода от токена start до последнего
+// прочитанного токена. Уже размеченные узлы (вложенные выражения) не изменяются
+func spanned[T ast.Expr](p *Parser, start token.TokenType, node T) T {
+	if positioned, ok := any(node).(ast.Positioned); ok && !positioned.Span().Start.IsValid() {
+		positioned.SetSpan(ast.Span{
+			Start: p.position(start),
+			End:   p.position(p.Peek(-1)),
+		})
+	}
+	return node
+}
+
+// position возвращает позицию токена в текущем файле
+func (p *Parser) position(tok token.TokenType) ast.Position {
+	return ast.Position{File: p.currentFile, Line: tok.Line, Col: tok.Col}
 }
 
 func (p *Parser) error(msg string, tok token.TokenType) {
@@ -29,6 +52,42 @@ func (p *Parser) GetScopeStack() *scope.ScopeStack {
 	return p.scopeStack
 }
 
+// WithRuntime привязывает парсер к Runtime: объявления функций и констант
+// регистрируются в его стеке областей, а GlobalScope не изменяется
+func (p *Parser) WithRuntime(rt *ast.Runtime) *Parser {
+	p.runtime = rt
+	p.scopeStack = rt.Scope()
+	return p
+}
+
+// WithoutEval отключает вычисление констант при разборе: дерево строится для
+// анализа (foo check), и код программы не должен выполняться
+func (p *Parser) WithoutEval() *Parser {
+	p.noEval = true
+	return p
+}
+
+// constExpr создает константу; без WithoutEval ее значение вычисляется сразу
+func (p *Parser) constExpr(name string, expr ast.Expr) *ast.ConstExpr {
+	if p.noEval {
+		return ast.NewConstDecl(name, expr)
+	}
+	return ast.NewConstExpr(p.rt(), name, expr)
+}
+
+// rt возвращает Runtime парсера
+func (p *Parser) rt() *ast.Runtime {
+	if p.runtime == nil {
+		return ast.GlobalRuntime
+	}
+	return p.runtime
+}
+
+// ParseInRuntime разбирает код в контексте rt (ast.ParseFunc для импортов и generate)
+func ParseInRuntime(rt *ast.Runtime, code string) []ast.Expr {
+	return NewParserWithFile(code, rt.CurrentFile()).WithRuntime(rt).ParseWithoutScopeInit()
+}
+
 func NewParser[T []rune | string | []byte](input T) *Parser {
 	tokens := lexer.NewLexer(input).Tokens()
 	return &Parser{
@@ -143,8 +202,10 @@ func (p *Parser) IsAtEnd() bool {
 func (p *Parser) Parse() []ast.Expr {
 	var exprs []ast.Expr
 
-	// Устанавливаем глобальный scope для использования в AST узлах
-	scope.GlobalScope = p.scopeStack
+	// Без явного Runtime парсер работает через GlobalScope
+	if p.runtime == nil {
+		scope.GlobalScope = p.scopeStack
+	}
 
 	for !p.Match(token.EOF) {
 		expr := p.Statement()
@@ -170,12 +231,14 @@ func (p *Parser) ParseWithoutScopeInit() []ast.Expr {
 func (p *Parser) ParseWithModules() []ast.Expr {
 	var exprs []ast.Expr
 
-	// Устанавливаем глобальный scope для использования в AST узлах
-	scope.GlobalScope = p.scopeStack
+	// Без явного Runtime парсер работает через GlobalScope
+	if p.runtime == nil {
+		scope.GlobalScope = p.scopeStack
+	}
 
 	// Устанавливаем контекст текущего файла для корректной работы импортов
 	if p.currentFile != "" {
-		ast.SetCurrentFileContext(p.currentFile)
+		p.rt().SetCurrentFile(p.currentFile)
 	}
 
 	for !p.Match(token.EOF) {
@@ -187,6 +250,13 @@ func (p *Parser) ParseWithModules() []ast.Expr {
 }
 
 func (p *Parser) Statement() ast.Expr {
+	start := p.Peek(0)
+
+	// outer: for ... / outer: while ... / outer: loop ...
+	if p.isLabeledLoop() {
+		return p.labeledLoop()
+	}
+
 	//+= -= *= /= %=
 	if p.MatchAllNext(token.IDENT, token.ADD, token.EQ) ||
 		p.MatchAllNext(token.IDENT, token.SUB, token.EQ) ||
@@ -199,16 +269,16 @@ func (p *Parser) Statement() ast.Expr {
 
 		op := p.Peek(-2).Token
 
-		expr := ast.NewBinaryExpr(ast.NewVarExpr(ident, nil), op, p.Expression())
+		expr := spanned(p, start, ast.NewBinaryExpr(spanned(p, start, ast.NewVarExpr(ident, nil)), op, p.Expression()))
 
-		return ast.NewVarExpr(ident, expr)
+		return spanned(p, start, ast.NewVarExpr(ident, expr))
 	}
 
 	//=
 	if p.MatchAllNext(token.IDENT, token.EQ) {
 		tok := p.Peek(-2)
 		ident := tok.Value
-		return ast.NewVarExpr(ident, p.Expression())
+		return spanned(p, start, ast.NewVarExpr(ident, p.Expression()))
 	}
 
 	if p.Match(token.FN) {
@@ -239,19 +309,39 @@ func (p *Parser) Statement() ast.Expr {
 
 		if len(returnValues) == 1 {
 			// Single return value
-			return ast.NewReturnExpr(returnValues[0])
+			return spanned(p, start, ast.NewReturnExpr(returnValues[0]))
 		} else {
 			// Multiple return values
-			return ast.NewReturnExpr(ast.NewMultiReturnExpr(returnValues))
+			return spanned(p, start, ast.NewReturnExpr(spanned(p, start, ast.NewMultiReturnExpr(returnValues))))
 		}
 	}
 
 	if p.MatchAndNext(token.BREAK) {
-		return ast.NewBreakExpr(nil)
+		return spanned(p, start, ast.NewBreakExpr(nil, p.loopLabel()))
+	}
+
+	if p.MatchAndNext(token.CONTINUE) {
+		return spanned(p, start, ast.NewContinueExpr(p.loopLabel()))
+	}
+
+	if p.MatchAndNext(token.THROW) {
+		return spanned(p, start, ast.NewThrowExpr(p.Expression()))
+	}
+
+	if p.MatchAndNext(token.TRY) {
+		return p.TryStatement()
+	}
+
+	// defer stmt / defer { ... }
+	if p.MatchAndNext(token.DEFER) {
+		if p.Match(token.LBRACE) {
+			return spanned(p, start, ast.NewDeferExpr(p.BlockStatement()))
+		}
+		return spanned(p, start, ast.NewDeferExpr(p.Statement()))
 	}
 
 	if p.MatchAndNext(token.YIELD) {
-		return ast.NewYieldExpr(p.Statement())
+		return spanned(p, start, ast.NewYieldExpr(p.Statement()))
 	}
 
 	if p.MatchAllNext(token.CONST, token.IDENT, token.EQ) {
@@ -259,18 +349,30 @@ func (p *Parser) Statement() ast.Expr {
 		ident := tok.Value
 
 		if p.MatchAndNext(token.IF) {
-			return ast.NewConstExpr(ident, p.IfStatement())
+			return spanned(p, start, p.constExpr(ident, p.IfStatement()))
 		}
 
 		if p.Match(token.MATCH) {
-			return ast.NewConstExpr(ident, p.MatchStatement())
+			return spanned(p, start, p.constExpr(ident, p.MatchStatement()))
 		}
 
 		if p.MatchAndNext(token.FOR) {
-			return ast.NewConstExpr(ident, p.ForStatement())
+			return spanned(p, start, p.constExpr(ident, p.ForStatement()))
+		}
+
+		if p.isLabeledLoop() {
+			return spanned(p, start, p.constExpr(ident, p.labeledLoop()))
+		}
+
+		if p.MatchAndNext(token.WHILE) {
+			return spanned(p, start, p.constExpr(ident, p.WhileStatement()))
+		}
+
+		if p.MatchAndNext(token.LOOP) {
+			return spanned(p, start, p.constExpr(ident, p.LoopStatement()))
 		}
 
-		return ast.NewConstExpr(ident, p.Expression())
+		return spanned(p, start, p.constExpr(ident, p.Expression()))
 	}
 
 	// Check for multiple variable assignment: let a, b, c = expr
@@ -303,7 +405,7 @@ func (p *Parser) Statement() ast.Expr {
 
 		// Multiple assignment case
 		if len(names) > 1 {
-			return ast.NewMultiAssignExpr(names, p.Expression())
+			return spanned(p, start, ast.NewMultiAssignExpr(names, p.Expression()))
 		}
 
 		// Single assignment case - keep existing logic
@@ -313,11 +415,11 @@ func (p *Parser) Statement() ast.Expr {
 		var createLetExpr func(string, ast.Expr) ast.Expr
 		if varType != "" {
 			createLetExpr = func(name string, expr ast.Expr) ast.Expr {
-				return ast.NewTypedLetExpr(name, varType, expr)
+				return spanned(p, start, ast.NewTypedLetExpr(name, varType, expr))
 			}
 		} else {
 			createLetExpr = func(name string, expr ast.Expr) ast.Expr {
-				return ast.NewLetExpr(name, expr)
+				return spanned(p, start, ast.NewLetExpr(name, expr))
 			}
 		}
 
@@ -333,18 +435,53 @@ func (p *Parser) Statement() ast.Expr {
 			return createLetExpr(ident, p.ForStatement())
 		}
 
+		if p.isLabeledLoop() {
+			return createLetExpr(ident, p.labeledLoop())
+		}
+
+		if p.MatchAndNext(token.WHILE) {
+			return createLetExpr(ident, p.WhileStatement())
+		}
+
+		if p.MatchAndNext(token.LOOP) {
+			return createLetExpr(ident, p.LoopStatement())
+		}
+
 		return createLetExpr(ident, p.Expression())
 	}
 
 	if p.MatchAnyNext(token.PRINT, token.PRINTLN) {
 		isPrint := p.MatchN(token.PRINT, -1)
-		return ast.NewPrintExpr(p.Expression(), isPrint)
+		// Скобки сразу после println - скобки вызова, а не кортеж:
+		// println() печатает пустую строку, println(a, b) - значения через пробел
+		if p.MatchAndNext(token.LPAREN) {
+			var args []ast.Expr
+			for !p.MatchAndNext(token.RPAREN) {
+				args = append(args, p.Expression())
+				if !p.MatchAndNext(token.COMMA) && !p.Match(token.RPAREN) {
+					p.error("expected ',' or ')'", p.Peek(0))
+				}
+			}
+			if len(args) == 1 {
+				return spanned(p, start, ast.NewPrintExpr(args[0], isPrint))
+			}
+			return spanned(p, start, ast.NewPrintArgsExpr(args, isPrint))
+		}
+		return spanned(p, start, ast.NewPrintExpr(p.Expression(), isPrint))
 	}
 
 	if p.MatchAndNext(token.FOR) {
 		return p.ForStatement()
 	}
 
+	if p.MatchAndNext(token.WHILE) {
+		return p.WhileStatement()
+	}
+
+	if p.MatchAndNext(token.LOOP) {
+		return p.LoopStatement()
+	}
+
 	if p.MatchAllNext(token.IF) {
 		return p.IfStatement()
 	}
@@ -404,6 +541,12 @@ func (p *Parser) Statement() ast.Expr {
 }
 
 func (p *Parser) ForStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
+	if p.isForIn() {
+		return p.forInStatement(start)
+	}
+
 	init := p.Statement()
 	{
 		if !p.MatchAndNext(token.SEMICOLON) {
@@ -422,10 +565,102 @@ func (p *Parser) ForStatement() ast.Expr {
 
 	body := p.BlockStatement()
 
-	return ast.NewForExpr(init, condition, step, body)
+	return spanned(p, start, ast.NewForExpr(init, condition, step, body))
+}
+
+// WhileStatement разбирает while cond { ... }
+func (p *Parser) WhileStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
+	condition := p.Expression()
+	body := p.BlockStatement()
+
+	return spanned(p, start, ast.NewWhileExpr(condition, body))
+}
+
+// LoopStatement разбирает бесконечный цикл loop { ... }
+func (p *Parser) LoopStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
+	return spanned(p, start, ast.NewLoopExpr(p.BlockStatement()))
+}
+
+// isLabeledLoop проверяет начало цикла с меткой: outer: for / outer: while / outer: loop
+func (p *Parser) isLabeledLoop() bool {
+	if !p.Match(token.IDENT) || p.Peek(1).Token != token.COLON {
+		return false
+	}
+	switch p.Peek(2).Token {
+	case token.FOR, token.WHILE, token.LOOP:
+		return true
+	}
+	return false
+}
+
+// labeledLoop разбирает цикл с меткой: outer: for ... { break outer }
+func (p *Parser) labeledLoop() ast.Expr {
+	label := p.Next().Value
+	p.Next() // ':'
+
+	p.labels = append(p.labels, label)
+	defer func() { p.labels = p.labels[:len(p.labels)-1] }()
+
+	loop := p.Statement().(ast.LabeledLoop)
+	loop.SetLabel(label)
+	return loop
+}
+
+// loopLabel читает необязательную метку после break/continue (на той же строке)
+// и проверяет, что она принадлежит одному из объемлющих циклов
+func (p *Parser) loopLabel() string {
+	if !p.Match(token.IDENT) || p.Peek(0).Line != p.Peek(-1).Line {
+		return ""
+	}
+
+	label := p.Next()
+	for _, known := range p.labels {
+		if known == label.Value {
+			return label.Value
+		}
+	}
+
+	p.error("unknown loop label '"+label.Value+"'", label)
+	return ""
+}
+
+// isForIn проверяет заголовок for item in / for key, item in
+func (p *Parser) isForIn() bool {
+	if !p.Match(token.IDENT) {
+		return false
+	}
+	if p.Peek(1).Token == token.IN {
+		return true
+	}
+	return p.Peek(1).Token == token.COMMA && p.Peek(2).Token == token.IDENT && p.Peek(3).Token == token.IN
+}
+
+// forInStatement разбирает for item in expr { ... } и for key, item in expr { ... }
+func (p *Parser) forInStatement(start token.TokenType) ast.Expr {
+	keyName, itemName := "", p.Next().Value
+	if p.MatchAndNext(token.COMMA) {
+		keyName, itemName = itemName, p.Next().Value
+	}
+	p.Next() // in
+
+	// for key in obj { ... }: '{' начинает тело цикла, а не obj{...}
+	noStruct := p.noStruct
+	p.noStruct = true
+	iterable := p.Expression()
+	p.noStruct = noStruct
+
+	body := p.BlockStatement()
+
+	return spanned(p, start, ast.NewForInExpr(keyName, itemName, iterable, body))
 }
 
 func (p *Parser) FunctionStatement() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAllNext(token.FN, token.IDENT, token.LPAREN) {
 		p.error("expected fn", p.Peek(0))
 	}
@@ -461,10 +696,12 @@ func (p *Parser) FunctionStatement() ast.Expr {
 
 	body := p.BlockStatement()
 
-	return ast.NewFuncStatment(identTok.Value, args, body, false)
+	return spanned(p, start, ast.NewFuncStatment(p.rt(), identTok.Value, args, body, false))
 }
 
 func (p *Parser) MacroDefinition() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.MACRO) {
 		p.error("expected 'macro'", p.Peek(0))
 	}
@@ -550,7 +787,7 @@ func (p *Parser) MacroDefinition() ast.Expr {
 		
 		// Читаем весь шаблон как текст до закрывающей }
 		template := p.readTemplateBlock()
-		codeGenBody = ast.NewGenerateExpr(template)
+		codeGenBody = spanned(p, start, ast.NewGenerateExpr(template))
 		
 	// Если есть Expr блок (обычная генерация)
 	} else if p.Match(token.EXPR) {
@@ -571,17 +808,19 @@ func (p *Parser) MacroDefinition() ast.Expr {
 			p.error("expected '}' after Expr block", p.Peek(0))
 		}
 
-		codeGenBody = ast.NewExprBlockExpr(exprStatements)
+		codeGenBody = spanned(p, start, ast.NewExprBlockExpr(exprStatements))
 	}
 
 	if !p.MatchAndNext(token.RBRACE) {
 		p.error("expected '}' after macro body", p.Peek(0))
 	}
 
-	return ast.NewMacroDefExpr(name, params, macroTimeStatements, codeGenBody)
+	return spanned(p, start, ast.NewMacroDefExpr(name, params, macroTimeStatements, codeGenBody))
 }
 
 func (p *Parser) StructDefinition() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.STRUCT) {
 		p.error("expected 'struct'", p.Peek(0))
 	}
@@ -593,6 +832,9 @@ func (p *Parser) StructDefinition() ast.Expr {
 	name := p.Peek(0).Value
 	p.Next()
 
+	// Параметры типа generic структуры: struct Box<T> { value: T }
+	typeParams := p.typeParameters()
+
 	if !p.MatchAndNext(token.LBRACE) {
 		p.error("expected '{' after struct name", p.Peek(0))
 	}
@@ -610,14 +852,13 @@ func (p *Parser) StructDefinition() ast.Expr {
 		var fieldExpr ast.Expr
 		if p.MatchAndNext(token.COLON) {
 			if p.Match(token.IDENT) {
-				fieldExpr = ast.NewTypeExpr(p.Peek(0).Value)
-				p.Next()
+				fieldExpr = spanned(p, start, ast.NewTypeExpr(p.parseTypeAnnotation()))
 			} else {
 				fieldExpr = p.Expression()
 			}
 		} else {
 			// Поле без явного типа
-			fieldExpr = ast.NewTypeExpr("any")
+			fieldExpr = spanned(p, start, ast.NewTypeExpr("any"))
 		}
 
 		fields[fieldName] = fieldExpr
@@ -633,10 +874,15 @@ func (p *Parser) StructDefinition() ast.Expr {
 		p.error("expected '}'", p.Peek(0))
 	}
 
-	return ast.NewStructDefExpr(name, fields)
+	if len(typeParams) > 0 {
+		return spanned(p, start, ast.NewGenericStructDefExpr(name, typeParams, fields))
+	}
+	return spanned(p, start, ast.NewStructDefExpr(name, fields))
 }
 
 func (p *Parser) IfStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	conditions := []ast.Expr{p.Expression()}
 	then := []ast.Expr{p.BlockStatement()}
 
@@ -647,13 +893,53 @@ func (p *Parser) IfStatement() ast.Expr {
 
 	if p.MatchAllNext(token.ELSE, token.LBRACE) {
 		p.NextN(-1)
-		return ast.NewIfExpr(conditions, then, p.BlockStatement())
+		return spanned(p, start, ast.NewIfExpr(conditions, then, p.BlockStatement()))
+	}
+
+	return spanned(p, start, ast.NewIfExpr(conditions, then, nil))
+}
+
+// TryStatement разбирает try { } catch (e) { } finally { }.
+// Скобки вокруг имени ошибки необязательны, имя можно опустить: catch { }
+func (p *Parser) TryStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
+	body := p.BlockStatement()
+
+	var catchName string
+	var catchBody, finallyBody ast.Expr
+
+	if p.MatchAndNext(token.CATCH) {
+		if p.MatchAndNext(token.LPAREN) {
+			if !p.Match(token.IDENT) {
+				p.error("expected identifier in catch", p.Peek(0))
+			}
+			catchName = p.Next().Value
+
+			if !p.MatchAndNext(token.RPAREN) {
+				p.error("expected ')'", p.Peek(0))
+			}
+		} else if p.Match(token.IDENT) {
+			catchName = p.Next().Value
+		}
+
+		catchBody = p.BlockStatement()
 	}
 
-	return ast.NewIfExpr(conditions, then, nil)
+	if p.MatchAndNext(token.FINALLY) {
+		finallyBody = p.BlockStatement()
+	}
+
+	if catchBody == nil && finallyBody == nil {
+		p.error("expected catch or finally after try block", p.Peek(0))
+	}
+
+	return spanned(p, start, ast.NewTryExpr(body, catchName, catchBody, finallyBody))
 }
 
 func (p *Parser) MatchStatement() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.MATCH) {
 		p.error("expected match", p.Peek(0))
 	}
@@ -671,33 +957,13 @@ func (p *Parser) MatchStatement() ast.Expr {
 	var underscore *ast.MatchArm
 
 	for !p.MatchAllNext(token.RBRACE) {
+		pattern := p.Pattern()
 
-		if p.MatchAndNext(token.UNDERSCORE) {
-			if underscore != nil {
-				p.error("only one underscore is allowed", p.Peek(0))
-			}
-
-			if !p.MatchAllNext(token.EQ_GT) {
-				p.error("expected '=>'", p.Peek(0))
-			}
-
-			var resultExpr ast.Expr
-			{
-				if !p.Match(token.LBRACE) {
-					resultExpr = p.Statement()
-				} else {
-					resultExpr = p.BlockStatement()
-				}
-			}
-
-			tmp := ast.NewMatchArm(ast.NewLiteralString("_"), resultExpr)
-			underscore = &tmp
-			p.MatchAllNext(token.COMMA)
-			continue
+		var guard ast.Expr
+		if p.MatchAndNext(token.IF) {
+			guard = p.Expression()
 		}
 
-		caseExpr := p.Expression()
-
 		if !p.MatchAllNext(token.EQ_GT) {
 			p.error("expected '=>'", p.Peek(0))
 		}
@@ -712,17 +978,168 @@ func (p *Parser) MatchStatement() ast.Expr {
 
 		p.MatchAllNext(token.COMMA)
 
-		arms = append(arms, ast.NewMatchArm(caseExpr, resultExpr))
+		// Ветка _ без guard проверяется последней, где бы она ни стояла
+		if _, ok := pattern.(*ast.WildcardPattern); ok && guard == nil {
+			if underscore != nil {
+				p.error("only one underscore is allowed", p.Peek(0))
+			}
+			tmp := ast.NewMatchArm(pattern, nil, resultExpr)
+			underscore = &tmp
+			continue
+		}
+
+		arms = append(arms, ast.NewMatchArm(pattern, guard, resultExpr))
 	}
 
 	if underscore != nil {
 		arms = append(arms, *underscore)
 	}
 
-	return ast.NewMatchExpr(condition, arms)
+	return spanned(p, start, ast.NewMatchExpr(condition, arms))
+}
+
+// Pattern разбирает паттерн ветки match: альтернативы через |
+func (p *Parser) Pattern() ast.Pattern {
+	pattern := p.singlePattern()
+
+	if !p.Match(token.PIPE) {
+		return pattern
+	}
+
+	alternatives := []ast.Pattern{pattern}
+	for p.MatchAndNext(token.PIPE) {
+		alternatives = append(alternatives, p.singlePattern())
+	}
+	return ast.NewOrPattern(alternatives)
+}
+
+// singlePattern разбирает паттерн без альтернатив:
+// _, имя, Ok(p)/Err(p), Enum.Variant, [a, ...rest], {field: p}, Struct{field}, литерал или диапазон 1..10
+func (p *Parser) singlePattern() ast.Pattern {
+	switch {
+	case p.MatchAndNext(token.UNDERSCORE):
+		return ast.NewWildcardPattern()
+
+	case p.Match(token.OK) || p.Match(token.ERR):
+		ok := p.Next().Token == token.OK
+		if !p.MatchAndNext(token.LPAREN) {
+			p.error("expected '(' in Result pattern", p.Peek(0))
+		}
+		inner := p.Pattern()
+		if !p.MatchAndNext(token.RPAREN) {
+			p.error("expected ')' in Result pattern", p.Peek(0))
+		}
+		return ast.NewResultPattern(ok, inner)
+
+	case p.MatchAndNext(token.LBRACK):
+		return p.arrayPattern()
+
+	case p.MatchAndNext(token.LBRACE):
+		return p.objectPattern("")
+
+	case p.Match(token.IDENT):
+		name := p.Next().Value
+
+		if p.MatchAndNext(token.DOT) {
+			variant := p.Next()
+			if variant.Token != token.IDENT {
+				p.error("expected variant name after '.'", variant)
+			}
+			if p.MatchAndNext(token.LPAREN) {
+				return ast.NewEnumPayloadPattern(name, variant.Value, p.patternList(token.RPAREN))
+			}
+			return ast.NewEnumPattern(name, variant.Value)
+		}
+
+		if p.MatchAndNext(token.LBRACE) {
+			return p.objectPattern(name)
+		}
+
+		return ast.NewBindingPattern(name)
+	}
+
+	low := p.Addition()
+	if p.Match(token.DOT_DOT) || p.Match(token.DOT_DOT_EQ) {
+		inclusive := p.Next().Token == token.DOT_DOT_EQ
+		return ast.NewRangePattern(low, p.Addition(), inclusive)
+	}
+	return ast.NewValuePattern(low)
+}
+
+// patternList разбирает паттерны через запятую до закрывающего токена (уже после открывающего)
+func (p *Parser) patternList(closing token.Token) []ast.Pattern {
+	var patterns []ast.Pattern
+
+	for !p.MatchAndNext(closing) {
+		patterns = append(patterns, p.Pattern())
+
+		if !p.MatchAndNext(token.COMMA) && !p.Match(closing) {
+			p.error("expected ',' in pattern list", p.Peek(0))
+		}
+	}
+
+	return patterns
+}
+
+// arrayPattern разбирает [p1, p2, ...rest] после '['
+func (p *Parser) arrayPattern() ast.Pattern {
+	var elements []ast.Pattern
+	rest, hasRest := "", false
+
+	for !p.MatchAndNext(token.RBRACK) {
+		if p.MatchAndNext(token.ELLIPSIS) {
+			hasRest = true
+			if p.MatchAndNext(token.UNDERSCORE) {
+				rest = "_"
+			} else if p.Match(token.IDENT) {
+				rest = p.Next().Value
+			} else {
+				p.error("expected name after '...'", p.Peek(0))
+			}
+			p.MatchAndNext(token.COMMA)
+			if !p.MatchAndNext(token.RBRACK) {
+				p.error("rest pattern must be the last element", p.Peek(0))
+			}
+			break
+		}
+
+		elements = append(elements, p.Pattern())
+
+		if !p.MatchAndNext(token.COMMA) && !p.Match(token.RBRACK) {
+			p.error("expected ',' or ']' in array pattern", p.Peek(0))
+		}
+	}
+
+	return ast.NewArrayPattern(elements, rest, hasRest)
+}
+
+// objectPattern разбирает {name, age: p} после '{'; typeName - имя структуры или ""
+func (p *Parser) objectPattern(typeName string) ast.Pattern {
+	var fields []ast.FieldPattern
+
+	for !p.MatchAndNext(token.RBRACE) {
+		fieldTok := p.Next()
+		if fieldTok.Token != token.IDENT && fieldTok.Token != token.STRING && !lexer.IsKeyword(fieldTok.Value) {
+			p.error("expected field name in object pattern", fieldTok)
+		}
+
+		var pattern ast.Pattern = ast.NewBindingPattern(fieldTok.Value)
+		if p.MatchAndNext(token.COLON) {
+			pattern = p.Pattern()
+		}
+		fields = append(fields, ast.FieldPattern{Name: fieldTok.Value, Pattern: pattern})
+
+		if !p.MatchAndNext(token.COMMA) && !p.Match(token.RBRACE) {
+			p.error("expected ',' or '}' in object pattern", p.Peek(0))
+		}
+	}
+
+	return ast.NewObjectPattern(typeName, fields)
 }
 
 func (p *Parser) BlockStatement() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.LBRACE) {
 		p.error("expected {", p.Peek(0))
 	}
@@ -733,19 +1150,21 @@ func (p *Parser) BlockStatement() ast.Expr {
 		statments = append(statments, p.Statement())
 	}
 
-	return ast.NewBodyStatment(statments)
+	return spanned(p, start, ast.NewBodyStatment(statments))
 }
 
 // BlockStatementWithoutLBrace парсит блок statements без ожидания открывающей {
 // Используется когда { уже потреблен
 func (p *Parser) BlockStatementWithoutLBrace() ast.Expr {
+	start := p.Peek(0)
+
 	var statments []ast.Expr
 
 	for !p.MatchAndNext(token.RBRACE) {
 		statments = append(statments, p.Statement())
 	}
 
-	return ast.NewBodyStatment(statments)
+	return spanned(p, start, ast.NewBodyStatment(statments))
 }
 
 func (p *Parser) Expression() ast.Expr {
@@ -753,6 +1172,8 @@ func (p *Parser) Expression() ast.Expr {
 }
 
 func (p *Parser) Conditional() ast.Expr {
+	start := p.Peek(0)
+
 	expr := p.Logical()
 
 	// true ? 1 : 22
@@ -762,20 +1183,22 @@ func (p *Parser) Conditional() ast.Expr {
 			p.error("expected ':' in conditional expression", p.Peek(0))
 		}
 		elseBranch := p.Expression()
-		return ast.NewConditionalExpr(expr, thenBranch, elseBranch)
+		return spanned(p, start, ast.NewConditionalExpr(expr, thenBranch, elseBranch))
 	}
 
 	return expr
 }
 
 func (p *Parser) Logical() ast.Expr {
+	start := p.Peek(0)
+
 	expr := p.Comparison()
 
 	for {
 		if p.MatchAndNext(token.AND_AND) {
-			expr = ast.NewBinaryExpr(expr, token.AND_AND, p.Comparison())
+			expr = spanned(p, start, ast.NewBinaryExpr(expr, token.AND_AND, p.Comparison()))
 		} else if p.MatchAndNext(token.OR_OR) {
-			expr = ast.NewBinaryExpr(expr, token.OR_OR, p.Comparison())
+			expr = spanned(p, start, ast.NewBinaryExpr(expr, token.OR_OR, p.Comparison()))
 		} else {
 			break
 		}
@@ -785,12 +1208,14 @@ func (p *Parser) Logical() ast.Expr {
 }
 
 func (p *Parser) Comparison() ast.Expr {
-	expr := p.Addition()
+	start := p.Peek(0)
+
+	expr := p.Range()
 
 	for {
 		tok := p.Peek(0)
 		if p.MatchAnyNext(token.GT, token.LT, token.EQ_EQ, token.GT_EQ, token.LT_EQ, token.NOT_EQ) {
-			expr = ast.NewBinaryExpr(expr, tok.Token, p.Addition())
+			expr = spanned(p, start, ast.NewBinaryExpr(expr, tok.Token, p.Range()))
 		} else {
 			break
 		}
@@ -799,14 +1224,30 @@ func (p *Parser) Comparison() ast.Expr {
 	return expr
 }
 
+// Range разбирает диапазон start..end (end не включается) или start..=end (end включается)
+func (p *Parser) Range() ast.Expr {
+	start := p.Peek(0)
+
+	expr := p.Addition()
+
+	if p.Match(token.DOT_DOT) || p.Match(token.DOT_DOT_EQ) {
+		inclusive := p.Next().Token == token.DOT_DOT_EQ
+		return spanned(p, start, ast.NewRangeExpr(expr, p.Addition(), inclusive))
+	}
+
+	return expr
+}
+
 func (p *Parser) Addition() ast.Expr {
+	start := p.Peek(0)
+
 	expr := p.Multiplication()
 
 	for {
 		tok := p.Peek(0)
 
 		if p.MatchAnyNext(token.ADD, token.SUB) {
-			expr = ast.NewBinaryExpr(expr, tok.Token, p.Multiplication())
+			expr = spanned(p, start, ast.NewBinaryExpr(expr, tok.Token, p.Multiplication()))
 		} else if p.MatchAnyNext(token.INC, token.DEC) {
 
 			var op token.Token
@@ -822,10 +1263,10 @@ func (p *Parser) Addition() ast.Expr {
 
 			if identTok.Token == token.IDENT {
 				ident := identTok.Value
-				expr = ast.NewBinaryExpr(ast.NewVarExpr(ident, nil), op, ast.NewInt64Expr(1))
-				expr = ast.NewVarExpr(ident, expr)
+				expr = spanned(p, start, ast.NewBinaryExpr(spanned(p, start, ast.NewVarExpr(ident, nil)), op, spanned(p, start, ast.NewInt64Expr(1))))
+				expr = spanned(p, start, ast.NewVarExpr(ident, expr))
 			} else if identTok.Token == token.INT || identTok.Token == token.FLOAT {
-				expr = ast.NewBinaryExpr(expr, op, ast.NewInt64Expr(1))
+				expr = spanned(p, start, ast.NewBinaryExpr(expr, op, spanned(p, start, ast.NewInt64Expr(1))))
 			}
 
 		} else {
@@ -837,11 +1278,13 @@ func (p *Parser) Addition() ast.Expr {
 }
 
 func (p *Parser) Multiplication() ast.Expr {
+	start := p.Peek(0)
+
 	expr := p.Unary()
 	for {
 		tok := p.Peek(0)
 		if p.MatchAnyNext(token.MUL, token.QUO, token.REM) {
-			expr = ast.NewBinaryExpr(expr, tok.Token, p.Unary())
+			expr = spanned(p, start, ast.NewBinaryExpr(expr, tok.Token, p.Unary()))
 		} else {
 			break
 		}
@@ -850,8 +1293,10 @@ func (p *Parser) Multiplication() ast.Expr {
 }
 
 func (p *Parser) Unary() ast.Expr {
+	start := p.Peek(0)
+
 	if p.MatchAndNext(token.SUB) {
-		return ast.NewUnaryOpExpr('-', p.Postfix(), 0)
+		return spanned(p, start, ast.NewUnaryOpExpr('-', p.Postfix(), 0))
 	}
 
 	if p.MatchAndNext(token.NOT) {
@@ -861,20 +1306,23 @@ func (p *Parser) Unary() ast.Expr {
 				count++
 			}
 		}
-		return ast.NewUnaryOpExpr('!', p.Postfix(), count)
+		return spanned(p, start, ast.NewUnaryOpExpr('!', p.Postfix(), count))
 	}
 
 	return p.Postfix()
 }
 
 func (p *Parser) Postfix() ast.Expr {
+	start := p.Peek(0)
+
 	expr := p.Primary()
 
 	for {
 		if p.MatchAndNext(token.DOT) {
 			// Точечная нотация: obj.property или obj.method()
+			// Ключевые слова допустимы как имена свойств: e.type
 			propTok := p.Next()
-			if propTok.Token != token.IDENT {
+			if propTok.Token != token.IDENT && !lexer.IsKeyword(propTok.Value) {
 				p.error("expected property name after '.'", propTok)
 			}
 
@@ -886,10 +1334,13 @@ func (p *Parser) Postfix() ast.Expr {
 					args = append(args, p.Expression())
 					p.MatchAndNext(token.COMMA)
 				}
-				expr = ast.NewMethodCallExpr(expr, propTok.Value, args)
+				expr = spanned(p, start, ast.NewMethodCallExpr(expr, propTok.Value, args))
+			} else if p.MatchAndNext(token.EQ) {
+				// Присваивание полю: obj.property = value
+				return spanned(p, start, ast.NewMemberAssignExpr(expr, propTok.Value, p.Expression()))
 			} else {
 				// Это доступ к свойству: obj.property
-				expr = ast.NewMemberExpr(expr, propTok.Value)
+				expr = spanned(p, start, ast.NewMemberExpr(expr, propTok.Value))
 			}
 		} else if p.MatchAndNext(token.LBRACK) {
 			// Индексация: arr[index] или obj["key"]
@@ -897,7 +1348,7 @@ func (p *Parser) Postfix() ast.Expr {
 			if !p.MatchAndNext(token.RBRACK) {
 				p.error("expected ']'", p.Peek(0))
 			}
-			expr = ast.NewIndexExpr(expr, indexExpr)
+			expr = spanned(p, start, ast.NewIndexExpr(expr, indexExpr))
 		} else if p.MatchAndNext(token.LPAREN) {
 			// Вызов функции только для VarExpr (переменных)
 			if varExpr, ok := expr.(*ast.VarExpr); ok {
@@ -906,56 +1357,27 @@ func (p *Parser) Postfix() ast.Expr {
 					args = append(args, p.Expression())
 					p.MatchAndNext(token.COMMA)
 				}
-				expr = ast.NewFuncCallExpr(varExpr.Name, args)
+				expr = spanned(p, start, ast.NewFuncCallExpr(varExpr.Name, args))
 			} else {
 				p.error("cannot call non-function", p.Peek(-1))
 			}
+		} else if p.Match(token.QUESTION) && p.isPropagation() {
+			// Распространение ошибки: readFile(path)?
+			p.Next()
+			expr = spanned(p, start, ast.NewPropagateExpr(expr))
+		} else if varExpr, ok := expr.(*ast.VarExpr); ok && p.Match(token.LT) && p.isGenericInstantiation() {
+			// Экземпляр generic структуры: Box<int>{value: 1}
+			typeArgs := p.typeArguments()
+			p.Next() // '{'
+			instance := ast.NewStructInstanceExpr(varExpr.Name, p.structFields())
+			instance.TypeArgs = typeArgs
+			expr = spanned(p, start, instance)
 		} else if p.MatchAndNext(token.LBRACE) {
 			// Создание экземпляра структуры: TypeName{field: value, ...}
 			// Только для VarExpr (имен типов), не для строк или других выражений
 			// И только если следующий токен выглядит как поле структуры
 			if varExpr, ok := expr.(*ast.VarExpr); ok && p.isStructInstantiation() {
-				fields := make(map[string]ast.Expr)
-
-				// Пустой объект {}
-				if p.MatchAndNext(token.RBRACE) {
-					expr = ast.NewStructInstanceExpr(varExpr.Name, fields)
-				} else {
-					// Парсим поля структуры
-					for {
-						// Ожидаем идентификатор как ключ поля
-						keyTok := p.Next()
-						var key string
-
-						if keyTok.Token == token.IDENT {
-							key = keyTok.Value
-						} else if keyTok.Token == token.STRING {
-							key = keyTok.Value
-						} else {
-							p.error("expected field name", keyTok)
-						}
-
-						// Ожидаем двоеточие
-						if !p.MatchAndNext(token.COLON) {
-							p.error("expected ':'", p.Peek(0))
-						}
-
-						// Значение поля
-						value := p.Expression()
-						fields[key] = value
-
-						// Проверяем запятую или закрывающую скобку
-						if p.MatchAndNext(token.RBRACE) {
-							break
-						}
-
-						if !p.MatchAndNext(token.COMMA) {
-							p.error("expected ',' or '}'", p.Peek(0))
-						}
-					}
-
-					expr = ast.NewStructInstanceExpr(varExpr.Name, fields)
-				}
+				expr = spanned(p, start, ast.NewStructInstanceExpr(varExpr.Name, p.structFields()))
 			} else {
 				// Если это не VarExpr (например, строка "struct"), отменяем разбор структуры
 				// Возвращаем токен LBRACE обратно в поток
@@ -970,21 +1392,127 @@ func (p *Parser) Postfix() ast.Expr {
 	return expr
 }
 
+// structFields парсит поля литерала структуры после '{': field: value, ... }
+func (p *Parser) structFields() map[string]ast.Expr {
+	fields := make(map[string]ast.Expr)
+
+	// Пустой объект {}
+	if p.MatchAndNext(token.RBRACE) {
+		return fields
+	}
+
+	for {
+		// Ожидаем идентификатор как ключ поля
+		keyTok := p.Next()
+		var key string
+
+		if keyTok.Token == token.IDENT {
+			key = keyTok.Value
+		} else if keyTok.Token == token.STRING {
+			key = keyTok.Value
+		} else {
+			p.error("expected field name", keyTok)
+		}
+
+		// Ожидаем двоеточие
+		if !p.MatchAndNext(token.COLON) {
+			p.error("expected ':'", p.Peek(0))
+		}
+
+		// Значение поля
+		fields[key] = p.Expression()
+
+		// Проверяем запятую или закрывающую скобку
+		if p.MatchAndNext(token.RBRACE) {
+			return fields
+		}
+
+		if !p.MatchAndNext(token.COMMA) {
+			p.error("expected ',' or '}'", p.Peek(0))
+		}
+	}
+}
+
+// isPropagation отличает постфиксный ? от тернарного оператора cond ? a : b:
+// после постфиксного ? выражение заканчивается, продолжается точкой или
+// бинарным оператором: r? * 2, x? == 1, a? && b?. Минусом может начинаться
+// ветка тернарного оператора (cond ? -1 : 1), поэтому r? - 1 - распространение,
+// только если дальше в строке нет ':' тернарного оператора
+func (p *Parser) isPropagation() bool {
+	question, next := p.Peek(0), p.Peek(1)
+	if next.Line > question.Line {
+		return true
+	}
+
+	switch next.Token {
+	case token.RPAREN, token.RBRACK, token.RBRACE, token.COMMA, token.SEMICOLON,
+		token.DOT, token.QUESTION, token.EOF,
+		token.ADD, token.MUL, token.QUO, token.REM, token.DOT_DOT, token.DOT_DOT_EQ,
+		token.GT, token.LT, token.EQ_EQ, token.GT_EQ, token.LT_EQ, token.NOT_EQ,
+		token.AND_AND, token.OR_OR:
+		return true
+	case token.SUB:
+		return !p.hasTernaryColon()
+	}
+	return false
+}
+
+// hasTernaryColon сообщает, есть ли в строке после ? двоеточие на том же
+// уровне скобок, то есть ? - тернарный оператор
+func (p *Parser) hasTernaryColon() bool {
+	line := p.Peek(0).Line
+	depth := 0
+	for i := 1; ; i++ {
+		tok := p.Peek(i)
+		if tok.Token == token.EOF || tok.Line > line {
+			return false
+		}
+
+		switch tok.Token {
+		case token.LPAREN, token.LBRACK, token.LBRACE:
+			depth++
+		case token.RPAREN, token.RBRACK, token.RBRACE:
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case token.COMMA, token.SEMICOLON, token.QUESTION:
+			if depth == 0 {
+				return false
+			}
+		case token.COLON:
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+}
+
 func (p *Parser) Primary() ast.Expr {
+	start := p.Peek(0)
+
 	tok := p.Peek(0)
 
 	switch tok.Token {
 
 	case token.EOF:
 		p.Next()
-		return ast.NewLiteralString("")
+		return spanned(p, start, ast.NewLiteralString(""))
 	case token.INT:
 		p.Next()
 		value, err := strconv.ParseInt(tok.Value, 10, 64)
 		if err != nil {
 			p.error(fmt.Sprintf("invalid int literal: %s", tok.Value), p.Peek(0))
 		}
-		return ast.NewInt64Expr(value)
+		return spanned(p, start, ast.NewInt64Expr(value))
+
+	case token.BIGINT:
+		p.Next()
+		value, ok := new(big.Int).SetString(strings.ReplaceAll(tok.Value, "_", ""), 10)
+		if !ok {
+			p.error(fmt.Sprintf("invalid bigint literal: %sn", tok.Value), p.Peek(0))
+		}
+		return spanned(p, start, ast.NewBigIntExpr(value))
 
 	case token.FLOAT:
 		p.Next()
@@ -992,12 +1520,12 @@ func (p *Parser) Primary() ast.Expr {
 		if err != nil {
 			p.error(fmt.Sprintf("invalid float literal: %s", tok.Value), p.Peek(0))
 		}
-		return ast.NewFloat64Expr(value)
+		return spanned(p, start, ast.NewFloat64Expr(value))
 
 	case token.STRING:
 		raw := p.Peek(0).Value
 		p.Next()
-		return ast.NewLiteralString(raw)
+		return spanned(p, start, ast.NewLiteralString(raw))
 
 	case token.INTERP_STRING:
 		return p.InterpolatedString()
@@ -1005,15 +1533,27 @@ func (p *Parser) Primary() ast.Expr {
 	case token.TRUE, token.FALSE:
 		p.Next()
 		b, _ := strconv.ParseBool(tok.Value)
-		return ast.NewBoolExpr(b)
+		return spanned(p, start, ast.NewBoolExpr(b))
 
 	case token.NULL:
 		p.Next()
-		return ast.NewNullExpr()
+		return spanned(p, start, ast.NewNullExpr())
 
 	case token.LPAREN:
 		p.Next()
+		// Пустой кортеж ()
+		if p.MatchAndNext(token.RPAREN) {
+			return spanned(p, start, ast.NewTupleExpr(nil))
+		}
+		// В скобках экземпляр структуры снова допустим: for x in (Range{...}) { }
+		noStruct := p.noStruct
+		p.noStruct = false
 		expr := p.Expression()
+		// Запятая в скобках делает их кортежем: (1, "a"), (x,)
+		if p.Match(token.COMMA) {
+			expr = p.tupleElements(start, expr)
+		}
+		p.noStruct = noStruct
 		if !p.Match(token.RPAREN) {
 			p.error("expected ')'", p.Peek(0))
 		}
@@ -1026,6 +1566,9 @@ func (p *Parser) Primary() ast.Expr {
 	case token.LBRACK:
 		return p.ArrayLiteral()
 
+	case token.Pound:
+		return p.SetLiteral()
+
 	case token.OK:
 		p.Next()
 		if !p.MatchAndNext(token.LPAREN) {
@@ -1035,7 +1578,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after Ok value", p.Peek(0))
 		}
-		return ast.NewOkExpr(value)
+		return spanned(p, start, ast.NewOkExpr(value))
 
 	case token.ERR:
 		p.Next()
@@ -1046,7 +1589,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after Err value", p.Peek(0))
 		}
-		return ast.NewErrExpr(error)
+		return spanned(p, start, ast.NewErrExpr(error))
 
 	case token.FN:
 		// Проверяем на анонимную функцию: fn(args) => body
@@ -1057,19 +1600,19 @@ func (p *Parser) Primary() ast.Expr {
 
 	case token.IDENT:
 		p.Next()
-		return ast.NewVarExpr(tok.Value, nil)
+		return spanned(p, start, ast.NewVarExpr(tok.Value, nil))
 
 	case token.ASYNC:
 		// async expression: async expr
 		p.Next() // consume async
 		expr := p.Unary()
-		return &ast.AsyncExpr{Expr: expr}
+		return spanned(p, start, &ast.AsyncExpr{Expr: expr})
 
 	case token.AWAIT:
 		// await expression: await expr
 		p.Next() // consume await
 		expr := p.Unary()
-		return &ast.AwaitExpr{Expr: expr}
+		return spanned(p, start, &ast.AwaitExpr{Expr: expr})
 
 	case token.SLEEP:
 		// sleep function: sleep(milliseconds)
@@ -1081,7 +1624,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after sleep duration", p.Peek(0))
 		}
-		return &ast.SleepExpr{Duration: duration}
+		return spanned(p, start, &ast.SleepExpr{Duration: duration})
 
 	case token.PROMISE:
 		// Promise.all or Promise.any
@@ -1114,9 +1657,9 @@ func (p *Parser) Primary() ast.Expr {
 
 		switch method {
 		case "all":
-			return &ast.PromiseAllExpr{Args: args}
+			return spanned(p, start, &ast.PromiseAllExpr{Args: args})
 		case "any":
-			return &ast.PromiseAnyExpr{Args: args}
+			return spanned(p, start, &ast.PromiseAnyExpr{Args: args})
 		default:
 			p.error("unknown Promise method: "+method, p.Peek(-1))
 		}
@@ -1143,7 +1686,7 @@ func (p *Parser) Primary() ast.Expr {
 				identName := p.Peek(0).Value
 				if p.isTypeName(identName) {
 					p.Next() // consume identifier
-					args = append(args, ast.NewTypeNameExpr(identName))
+					args = append(args, spanned(p, start, ast.NewTypeNameExpr(identName)))
 				} else {
 					args = append(args, p.Expression())
 				}
@@ -1162,7 +1705,7 @@ func (p *Parser) Primary() ast.Expr {
 			p.error("expected ')'", p.Peek(0))
 		}
 
-		return ast.NewMacroCallExpr(name, args)
+		return spanned(p, start, ast.NewMacroCallExpr(name, args))
 
 	case token.QUOTE:
 		// Quote expression: quote(expr)
@@ -1174,7 +1717,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after quoted expression", p.Peek(0))
 		}
-		return ast.NewQuoteExpr(expr)
+		return spanned(p, start, ast.NewQuoteExpr(expr))
 
 	case token.UNQUOTE:
 		// Unquote expression: unquote(expr)
@@ -1186,7 +1729,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after unquoted expression", p.Peek(0))
 		}
-		return ast.NewUnquoteExpr(expr)
+		return spanned(p, start, ast.NewUnquoteExpr(expr))
 
 	case token.TYPEOF:
 		// Typeof expression: typeof(expr)
@@ -1198,7 +1741,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after typeof expression", p.Peek(0))
 		}
-		return ast.NewTypeofExpr(expr)
+		return spanned(p, start, ast.NewTypeofExpr(expr))
 
 	case token.TYPE:
 		// Type expression: type(TypeName)
@@ -1214,7 +1757,7 @@ func (p *Parser) Primary() ast.Expr {
 		if !p.MatchAndNext(token.RPAREN) {
 			p.error("expected ')' after type name", p.Peek(0))
 		}
-		return ast.NewTypeExpr(typeName)
+		return spanned(p, start, ast.NewTypeExpr(typeName))
 	}
 
 	p.error("unexpected token", tok)
@@ -1223,15 +1766,18 @@ func (p *Parser) Primary() ast.Expr {
 
 // ObjectLiteral парсит объектные литералы {key: value, key2: value2}
 func (p *Parser) ObjectLiteral() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.LBRACE) {
 		p.error("expected '{'", p.Peek(0))
 	}
 
 	fields := make(map[string]ast.Expr)
+	var keys []string
 
 	// Пустой объект {}
 	if p.MatchAndNext(token.RBRACE) {
-		return ast.NewObjectExpr(fields)
+		return spanned(p, start, ast.NewObjectExpr(keys, fields))
 	}
 
 	for {
@@ -1254,6 +1800,9 @@ func (p *Parser) ObjectLiteral() ast.Expr {
 
 		// Значение
 		value := p.Expression()
+		if _, exists := fields[key]; !exists {
+			keys = append(keys, key)
+		}
 		fields[key] = value
 
 		// Проверяем запятую или закрывающую скобку
@@ -1271,10 +1820,12 @@ func (p *Parser) ObjectLiteral() ast.Expr {
 		}
 	}
 
-	return ast.NewObjectExpr(fields)
+	return spanned(p, start, ast.NewObjectExpr(keys, fields))
 }
 
 func (p *Parser) format() ast.Expr {
+	start := p.Peek(0)
+
 	raw := p.Peek(0).Value
 	p.Next()
 
@@ -1287,7 +1838,7 @@ func (p *Parser) format() ast.Expr {
 
 			// Добавляем накопленный литерал
 			if buf.Len() > 0 {
-				parts = append(parts, ast.NewLiteralString(buf.String()))
+				parts = append(parts, spanned(p, start, ast.NewLiteralString(buf.String())))
 				buf.Reset()
 			}
 
@@ -1302,8 +1853,9 @@ func (p *Parser) format() ast.Expr {
 
 			// Вырезаем выражение между { и }
 			exprStr := raw[i+1 : j]
-			subParser := NewParser(exprStr)
-			exprs := subParser.Parse()
+			// Вложенный парсер разбирает выражение в том же Runtime
+			subParser := NewParser(exprStr).WithRuntime(p.rt())
+			exprs := subParser.ParseWithoutScopeInit()
 
 			if len(exprs) != 1 {
 				p.error("invalid embedded expression in string", p.Peek(0))
@@ -1321,13 +1873,15 @@ func (p *Parser) format() ast.Expr {
 
 	// Остаток как литерал
 	if buf.Len() > 0 {
-		parts = append(parts, ast.NewLiteralString(buf.String()))
+		parts = append(parts, spanned(p, start, ast.NewLiteralString(buf.String())))
 	}
 
-	return ast.NewStringFormatExpr(parts)
+	return spanned(p, start, ast.NewStringFormatExpr(parts))
 }
 
 func (p *Parser) EnumStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	if !p.Match(token.IDENT) {
 		p.error("expected enum name", p.Peek(0))
 	}
@@ -1339,6 +1893,7 @@ func (p *Parser) EnumStatement() ast.Expr {
 	}
 
 	var values []string
+	var fields [][]ast.EnumField
 
 	for !p.MatchAndNext(token.RBRACE) {
 		if !p.Match(token.IDENT) {
@@ -1346,6 +1901,7 @@ func (p *Parser) EnumStatement() ast.Expr {
 		}
 
 		values = append(values, p.Next().Value)
+		fields = append(fields, p.enumVariantFields())
 
 		if p.MatchAndNext(token.RBRACE) {
 			break
@@ -1360,10 +1916,40 @@ func (p *Parser) EnumStatement() ast.Expr {
 		}
 	}
 
-	return ast.NewEnumExpr(enumName, values)
+	return spanned(p, start, ast.NewEnumExpr(enumName, values, fields))
+}
+
+// enumVariantFields разбирает данные варианта: Circle(r: float, label).
+// Для варианта без скобок возвращает nil, поле без типа получает тип any
+func (p *Parser) enumVariantFields() []ast.EnumField {
+	if !p.MatchAndNext(token.LPAREN) {
+		return nil
+	}
+
+	fields := []ast.EnumField{}
+
+	for !p.MatchAndNext(token.RPAREN) {
+		if !p.Match(token.IDENT) {
+			p.error("expected field name in enum variant", p.Peek(0))
+		}
+
+		field := ast.EnumField{Name: p.Next().Value, Type: "any"}
+		if p.MatchAndNext(token.COLON) {
+			field.Type = p.parseTypeAnnotation()
+		}
+		fields = append(fields, field)
+
+		if !p.MatchAndNext(token.COMMA) && !p.Match(token.RPAREN) {
+			p.error("expected ',' or ')' in enum variant", p.Peek(0))
+		}
+	}
+
+	return fields
 }
 
 func (p *Parser) ArrayLiteral() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.LBRACK) {
 		p.error("expected '['", p.Peek(0))
 	}
@@ -1371,7 +1957,7 @@ func (p *Parser) ArrayLiteral() ast.Expr {
 	var elements []ast.Expr
 
 	if p.MatchAndNext(token.RBRACK) {
-		return ast.NewArrayExpr(elements)
+		return spanned(p, start, ast.NewArrayExpr(elements))
 	}
 
 	for {
@@ -1390,10 +1976,58 @@ func (p *Parser) ArrayLiteral() ast.Expr {
 		}
 	}
 
-	return ast.NewArrayExpr(elements)
+	return spanned(p, start, ast.NewArrayExpr(elements))
+}
+
+// tupleElements дочитывает элементы кортежа после первого; закрывающую скобку
+// оставляет вызывающему
+func (p *Parser) tupleElements(start token.TokenType, first ast.Expr) ast.Expr {
+	elements := []ast.Expr{first}
+	for p.MatchAndNext(token.COMMA) {
+		if p.Match(token.RPAREN) {
+			break
+		}
+		elements = append(elements, p.Expression())
+	}
+	return spanned(p, start, ast.NewTupleExpr(elements))
+}
+
+// SetLiteral парсит литерал множества #{1, 2, 3}
+func (p *Parser) SetLiteral() ast.Expr {
+	start := p.Peek(0)
+
+	if !p.MatchAllNext(token.Pound, token.LBRACE) {
+		p.error("expected '#{'", p.Peek(0))
+	}
+
+	var elements []ast.Expr
+
+	if p.MatchAndNext(token.RBRACE) {
+		return spanned(p, start, ast.NewSetExpr(elements))
+	}
+
+	for {
+		elements = append(elements, p.Expression())
+
+		if p.MatchAndNext(token.RBRACE) {
+			break
+		}
+
+		if !p.MatchAndNext(token.COMMA) {
+			p.error("expected ',' or '}'", p.Peek(0))
+		}
+
+		if p.MatchAndNext(token.RBRACE) {
+			break
+		}
+	}
+
+	return spanned(p, start, ast.NewSetExpr(elements))
 }
 
 func (p *Parser) InterpolatedString() ast.Expr {
+	start := p.Peek(0)
+
 	raw := p.Peek(0).Value
 	p.Next()
 
@@ -1405,7 +2039,7 @@ func (p *Parser) InterpolatedString() ast.Expr {
 		if i < len(raw)-1 && raw[i] == '$' && raw[i+1] == '{' {
 			// Добавляем накопленный литерал
 			if buf.Len() > 0 {
-				parts = append(parts, ast.NewLiteralString(buf.String()))
+				parts = append(parts, spanned(p, start, ast.NewLiteralString(buf.String())))
 				buf.Reset()
 			}
 
@@ -1436,8 +2070,9 @@ func (p *Parser) InterpolatedString() ast.Expr {
 				p.error("empty expression in string interpolation", p.Peek(0))
 			}
 
-			subParser := NewParser(exprStr)
-			exprs := subParser.Parse()
+			// Вложенный парсер разбирает выражение в том же Runtime
+			subParser := NewParser(exprStr).WithRuntime(p.rt())
+			exprs := subParser.ParseWithoutScopeInit()
 
 			if len(exprs) != 1 {
 				p.error("invalid embedded expression in string", p.Peek(0))
@@ -1455,7 +2090,7 @@ func (p *Parser) InterpolatedString() ast.Expr {
 
 	// Остаток как литерал
 	if buf.Len() > 0 {
-		parts = append(parts, ast.NewLiteralString(buf.String()))
+		parts = append(parts, spanned(p, start, ast.NewLiteralString(buf.String())))
 	}
 
 	// Если нет частей для интерполяции, возвращаем простую строку
@@ -1465,53 +2100,48 @@ func (p *Parser) InterpolatedString() ast.Expr {
 		}
 	}
 
-	return ast.NewStringFormatExpr(parts)
+	return spanned(p, start, ast.NewStringFormatExpr(parts))
 }
 
 // ImportStatement parses import statements:
 // import "./module.foo"
-// import { func1, var1 } from "./module.foo"
+// import { func1, var1 as v } from "./module.foo"
 // import * as ModuleName from "./module.foo"
+// import name from "./module.foo"
+// import name, { func1 } from "./module.foo"
 func (p *Parser) ImportStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// import "./path"
 	if p.Match(token.STRING) {
 		path := p.Peek(0).Value
 		p.Next()
-		return ast.NewImportExpr(path)
+		return spanned(p, start, ast.NewImportExpr(path))
 	}
 
-	// import { item1, item2 } from "./path"
-	if p.MatchAndNext(token.LBRACE) {
-		var items []string
-
-		for !p.Match(token.RBRACE) {
-			if !p.Match(token.IDENT) {
-				p.error("expected identifier in import list", p.Peek(0))
-			}
-			items = append(items, p.Peek(0).Value)
-			p.Next()
-
-			if !p.MatchAndNext(token.COMMA) {
-				break
+	// import name from "./path", import name, { item } from "./path"
+	if p.Match(token.IDENT) {
+		name := p.Next().Value
+		var items []modules.Binding
+		if p.MatchAndNext(token.COMMA) {
+			if !p.MatchAndNext(token.LBRACE) {
+				p.error("expected '{' after default import", p.Peek(0))
 			}
+			items = p.bindingList("import")
 		}
 
-		if !p.MatchAndNext(token.RBRACE) {
-			p.error("expected '}' after import list", p.Peek(0))
-		}
-
-		if !p.MatchAndNext(token.FROM) {
-			p.error("expected 'from' after import list", p.Peek(0))
-		}
-
-		if !p.Match(token.STRING) {
-			p.error("expected module path string", p.Peek(0))
-		}
+		expr := ast.NewDefaultImportExpr(p.modulePath("import"), name, nil)
+		p.addImportItems(expr, items)
+		return spanned(p, start, expr)
+	}
 
-		path := p.Peek(0).Value
-		p.Next()
+	// import { item1, item2 as alias } from "./path"
+	if p.MatchAndNext(token.LBRACE) {
+		items := p.bindingList("import")
 
-		return ast.NewSelectiveImportExpr(path, items)
+		expr := ast.NewSelectiveImportExpr(p.modulePath("import"), nil)
+		p.addImportItems(expr, items)
+		return spanned(p, start, expr)
 	}
 
 	// import * as Name from "./path"
@@ -1523,53 +2153,133 @@ func (p *Parser) ImportStatement() ast.Expr {
 		alias := p.Peek(0).Value
 		p.Next()
 
-		if !p.MatchAndNext(token.FROM) {
+		if !p.Match(token.FROM) {
 			p.error("expected 'from' after alias", p.Peek(0))
 		}
 
-		if !p.Match(token.STRING) {
-			p.error("expected module path string", p.Peek(0))
+		return spanned(p, start, ast.NewAliasImportExpr(p.modulePath("alias"), alias))
+	}
+
+	p.error("invalid import syntax", p.Peek(0))
+	return nil
+}
+
+// addImportItems добавляет элементы import { a, b as c } в выражение импорта
+func (p *Parser) addImportItems(expr *ast.ImportExpr, items []modules.Binding) {
+	for _, item := range items {
+		if item.Name == modules.DefaultExport && item.Local == modules.DefaultExport {
+			p.error("default export needs a name: import { default as name }", p.Peek(-1))
+		}
+		expr.ImportedItems = append(expr.ImportedItems, item.Name)
+		if item.Local != item.Name {
+			if expr.Renames == nil {
+				expr.Renames = make(map[string]string)
+			}
+			expr.Renames[item.Name] = item.Local
+		}
+	}
+}
+
+// bindingList разбирает список { a, b as c } после '{' вплоть до '}' включительно
+func (p *Parser) bindingList(what string) []modules.Binding {
+	var items []modules.Binding
+
+	for !p.Match(token.RBRACE) {
+		if !p.Match(token.IDENT) {
+			p.error("expected identifier in "+what+" list", p.Peek(0))
 		}
+		name := p.Next().Value
+		local := name
 
-		path := p.Peek(0).Value
-		p.Next()
+		if p.MatchAndNext(token.AS) {
+			if !p.Match(token.IDENT) {
+				p.error("expected identifier after 'as'", p.Peek(0))
+			}
+			local = p.Next().Value
+		}
+		items = append(items, modules.Binding{Name: name, Local: local})
 
-		return ast.NewAliasImportExpr(path, alias)
+		if !p.MatchAndNext(token.COMMA) {
+			break
+		}
 	}
 
-	p.error("invalid import syntax", p.Peek(0))
-	return nil
+	if !p.MatchAndNext(token.RBRACE) {
+		p.error("expected '}' after "+what+" list", p.Peek(0))
+	}
+	return items
+}
+
+// modulePath разбирает from "./path" после списка импорта или экспорта
+func (p *Parser) modulePath(what string) string {
+	if !p.MatchAndNext(token.FROM) {
+		p.error("expected 'from' after "+what+" list", p.Peek(0))
+	}
+
+	if !p.Match(token.STRING) {
+		p.error("expected module path string", p.Peek(0))
+	}
+
+	return p.Next().Value
 }
 
 // ExportStatement parses export statements:
 // export fn name() { }
 // export let variable = value
 // export enum Color { RED, GREEN, BLUE }
+// export struct Point { x: int }
+// export interface Shape { fn area() -> float }
+// export type UserId = int
+// export default fn name() { }, export default expression
+// export { a, b as c }
+// export { a, b as c } from "./path"
+// export * from "./path"
 func (p *Parser) ExportStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
+	// export { a, b as c } [from "./path"]
+	if p.MatchAndNext(token.LBRACE) {
+		items := p.bindingList("export")
+		path := ""
+		if p.Match(token.FROM) {
+			path = p.modulePath("export")
+		}
+		return spanned(p, start, ast.NewExportListExpr(items, path))
+	}
+
+	// export * from "./path"
+	if p.MatchAndNext(token.MUL) {
+		return spanned(p, start, ast.NewExportListExpr(nil, p.modulePath("export *")))
+	}
+
+	// export default fn name() { }, export default expression
+	if p.Match(token.IDENT) && p.Peek(0).Value == modules.DefaultExport {
+		p.Next()
+		if p.Match(token.FN) && p.Peek(1).Token == token.IDENT {
+			declaration, name := p.exportedFunction()
+			return spanned(p, start, ast.NewDefaultExportExpr(declaration, name))
+		}
+		return spanned(p, start, ast.NewDefaultExportExpr(p.Expression(), ""))
+	}
+
 	var declaration ast.Expr
 	var name string
 
-	// export fn name() { }
 	if p.Match(token.FN) {
-		declaration = p.FunctionStatement()
-		// Extract function name from FuncStatment
-		if funcStmt, ok := declaration.(*ast.FuncStatment); ok {
-			name = funcStmt.Name()
-		} else {
-			p.error("invalid function declaration", p.Peek(0))
-		}
+		// export fn name() { }
+		declaration, name = p.exportedFunction()
 	} else if p.MatchAll(token.LET, token.IDENT, token.EQ) {
 		// export let variable = value
 		p.NextN(2) // Skip LET and IDENT
 		name = p.Peek(-1).Value
 		p.Next() // Skip EQ
-		declaration = ast.NewLetExpr(name, p.Expression())
+		declaration = spanned(p, start, ast.NewLetExpr(name, p.Expression()))
 	} else if p.MatchAll(token.CONST, token.IDENT, token.EQ) {
 		// export const variable = value
 		p.NextN(2) // Skip CONST and IDENT
 		name = p.Peek(-1).Value
 		p.Next() // Skip EQ
-		declaration = ast.NewConstExpr(name, p.Expression())
+		declaration = spanned(p, start, p.constExpr(name, p.Expression()))
 	} else if p.MatchAndNext(token.ENUM) {
 		// export enum Name { }
 		if !p.Match(token.IDENT) {
@@ -1577,16 +2287,55 @@ func (p *Parser) ExportStatement() ast.Expr {
 		}
 		name = p.Peek(0).Value // Get enum name before parsing
 		declaration = p.EnumStatement()
+	} else if p.Match(token.STRUCT) {
+		// export struct Name { }
+		name = p.Peek(1).Value
+		declaration = p.StructDefinition()
+	} else if p.MatchAndNext(token.INTERFACE) {
+		// export interface Name { }
+		name = p.Peek(0).Value
+		declaration = p.InterfaceStatement()
+	} else if p.Match(token.TYPE) && p.Peek(1).Token == token.IDENT && p.Peek(2).Token == token.EQ {
+		// export type Name = base
+		p.Next()
+		name = p.Peek(0).Value
+		declaration = p.TypeAliasStatement()
 	} else {
 		p.error("invalid export declaration", p.Peek(0))
 		return nil
 	}
 
-	return ast.NewExportExpr(declaration, name)
+	return spanned(p, start, ast.NewExportExpr(declaration, name))
+}
+
+// exportedFunction разбирает объявление функции после export и возвращает его имя
+func (p *Parser) exportedFunction() (ast.Expr, string) {
+	// sleep - ключевое слово, но модуль может экспортировать функцию с этим
+	// именем (std:time): вызов time.sleep(ms) разбирается как обращение к члену
+	if p.Peek(1).Token == token.SLEEP {
+		p.tokens[p.pos+1].Token = token.IDENT
+	}
+
+	if !p.hasTypedParameters() {
+		declaration := p.FunctionStatement()
+		return declaration, declaration.(*ast.FuncStatment).Name()
+	}
+
+	declaration := p.TypedFunctionStatement()
+	switch fn := declaration.(type) {
+	case *ast.TypedFuncStatement:
+		return declaration, fn.FuncName
+	case *ast.GenericFuncStatement:
+		return declaration, fn.FuncName
+	}
+	p.error("invalid function declaration", p.Peek(0))
+	return nil, ""
 }
 
 // AnonymousFunction парсит анонимную функцию: fn(x, y) => x + y или fn(x, y) { return x + y }
 func (p *Parser) AnonymousFunction() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.FN) {
 		p.error("expected 'fn'", p.Peek(0))
 	}
@@ -1637,10 +2386,12 @@ func (p *Parser) AnonymousFunction() ast.Expr {
 		p.error("expected '=>' or '{'", p.Peek(0))
 	}
 
-	return ast.NewAnonymousFunc(args, body)
+	return spanned(p, start, ast.NewAnonymousFunc(args, body))
 }
 
 func (p *Parser) ExtensionStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// extension TypeName { methods }
 
 	if !p.Match(token.IDENT) {
@@ -1649,6 +2400,9 @@ func (p *Parser) ExtensionStatement() ast.Expr {
 
 	typeName := p.Next().Value
 
+	// Параметр типа элементов: extension Array<T>
+	typeParams := ast.TypeParamNames(p.typeParameters())
+
 	if !p.MatchAndNext(token.LBRACE) {
 		p.error("expected '{' after type name", p.Peek(0))
 	}
@@ -1703,7 +2457,7 @@ func (p *Parser) ExtensionStatement() ast.Expr {
 					if p.MatchAnyNext(token.INT_TYPE, token.STRING_TYPE, token.FLOAT_TYPE, token.BOOL_TYPE) {
 						paramTypes = append(paramTypes, p.Peek(-1).Value)
 					} else if p.Match(token.IDENT) {
-						paramTypes = append(paramTypes, p.Next().Value)
+						paramTypes = append(paramTypes, p.parseTypeAnnotation())
 					} else {
 						p.error("expected type after ':'", p.Peek(0))
 					}
@@ -1734,7 +2488,7 @@ func (p *Parser) ExtensionStatement() ast.Expr {
 				if p.MatchAnyNext(token.INT_TYPE, token.STRING_TYPE, token.FLOAT_TYPE, token.BOOL_TYPE) {
 					returnType = p.Peek(-1).Value
 				} else if p.Match(token.IDENT) {
-					returnType = p.Next().Value
+					returnType = p.parseTypeAnnotation()
 				} else {
 					p.error("expected return type", p.Peek(0))
 				}
@@ -1767,10 +2521,11 @@ func (p *Parser) ExtensionStatement() ast.Expr {
 		p.error("expected '}' to close extension", p.Peek(0))
 	}
 
-	return &ast.ExtensionExpr{
-		TypeName: typeName,
-		Methods:  methods,
-	}
+	return spanned(p, start, &ast.ExtensionExpr{
+		TypeName:   typeName,
+		TypeParams: typeParams,
+		Methods:    methods,
+	})
 }
 
 // hasTypedParameters проверяет, есть ли типизированные параметры или generic параметры
@@ -1820,6 +2575,8 @@ func (p *Parser) hasTypedParameters() bool {
 
 // TypedFunctionStatement парсит функцию с типизированными параметрами
 func (p *Parser) TypedFunctionStatement() ast.Expr {
+	start := p.Peek(0)
+
 	if !p.MatchAndNext(token.FN) {
 		p.error("expected 'fn'", p.Peek(0))
 	}
@@ -1832,54 +2589,7 @@ func (p *Parser) TypedFunctionStatement() ast.Expr {
 	p.Next()
 
 	// Проверяем на generic параметры <T, U, T: Interface, ...>
-	var typeParams []ast.TypeConstraint
-	if p.Match(token.LT) {
-		p.Next() // consume '<'
-
-		for !p.Match(token.GT) {
-			if !p.Match(token.IDENT) {
-				p.error("expected type parameter name", p.Peek(0))
-			}
-
-			typeName := p.Peek(0).Value
-			p.Next()
-
-			var constraints []string
-			// Проверяем на ограничения: T: Interface1 + Interface2
-			if p.Match(token.COLON) {
-				p.Next() // consume ':'
-
-				for {
-					if !p.Match(token.IDENT) {
-						p.error("expected constraint interface name", p.Peek(0))
-					}
-
-					constraints = append(constraints, p.Peek(0).Value)
-					p.Next()
-
-					// Проверяем на дополнительные ограничения через +
-					if p.Match(token.ADD) {
-						p.Next() // consume '+'
-						continue
-					} else {
-						break
-					}
-				}
-			}
-
-			typeParams = append(typeParams, ast.NewConstrainedTypeParam(typeName, constraints))
-
-			if p.Match(token.COMMA) {
-				p.Next()
-			} else if !p.Match(token.GT) {
-				p.error("expected ',' or '>'", p.Peek(0))
-			}
-		}
-
-		if !p.MatchAndNext(token.GT) {
-			p.error("expected '>'", p.Peek(0))
-		}
-	}
+	typeParams := p.typeParameters()
 
 	if !p.MatchAndNext(token.LPAREN) {
 		p.error("expected '(' after function name", p.Peek(0))
@@ -1942,10 +2652,10 @@ func (p *Parser) TypedFunctionStatement() ast.Expr {
 
 	// Если есть generic параметры, создаем GenericFuncStatement
 	if len(typeParams) > 0 {
-		return ast.NewGenericFuncStatement(funcName, typeParams, params, returnType, body)
+		return spanned(p, start, ast.NewGenericFuncStatement(funcName, typeParams, params, returnType, body))
 	}
 
-	return ast.NewTypedFuncStatement(funcName, params, body, returnType)
+	return spanned(p, start, ast.NewTypedFuncStatement(funcName, params, body, returnType))
 }
 
 // isTypeName проверяет, является ли идентификатор именем типа
@@ -1958,10 +2668,10 @@ func (p *Parser) isTypeName(name string) bool {
 
 	// Проверяем, есть ли такой тип в scope (структуры, енумы)
 	// Ищем либо TypeInfo, либо определение типа
-	if _, exists := scope.GlobalScope.Get(name + "__TypeInfo"); exists {
+	if _, exists := p.rt().Scope().Get(name + "__TypeInfo"); exists {
 		return true
 	}
-	if _, exists := scope.GlobalScope.Get(name); exists {
+	if _, exists := p.rt().Scope().Get(name); exists {
 		return true
 	}
 
@@ -1978,9 +2688,123 @@ func (p *Parser) isGenericType(name string, typeParams []ast.TypeConstraint) boo
 	return false
 }
 
+// typeParameters парсит параметры типа <T, U: Interface1 + Interface2>,
+// если они есть
+func (p *Parser) typeParameters() []ast.TypeConstraint {
+	var typeParams []ast.TypeConstraint
+	if !p.MatchAndNext(token.LT) {
+		return nil
+	}
+
+	for !p.Match(token.GT) {
+		if !p.Match(token.IDENT) {
+			p.error("expected type parameter name", p.Peek(0))
+		}
+
+		typeName := p.Peek(0).Value
+		p.Next()
+
+		var constraints []string
+		// Проверяем на ограничения: T: Interface1 + Interface2
+		if p.Match(token.COLON) {
+			p.Next() // consume ':'
+
+			for {
+				if !p.Match(token.IDENT) {
+					p.error("expected constraint interface name", p.Peek(0))
+				}
+
+				constraints = append(constraints, p.Peek(0).Value)
+				p.Next()
+
+				// Проверяем на дополнительные ограничения через +
+				if p.Match(token.ADD) {
+					p.Next() // consume '+'
+					continue
+				} else {
+					break
+				}
+			}
+		}
+
+		typeParams = append(typeParams, ast.NewConstrainedTypeParam(typeName, constraints))
+
+		if p.Match(token.COMMA) {
+			p.Next()
+		} else if !p.Match(token.GT) {
+			p.error("expected ',' or '>'", p.Peek(0))
+		}
+	}
+
+	if !p.MatchAndNext(token.GT) {
+		p.error("expected '>'", p.Peek(0))
+	}
+
+	return typeParams
+}
+
+// typeArguments парсит аргументы типа <int, Box<string>>, если они есть
+func (p *Parser) typeArguments() []string {
+	if !p.MatchAndNext(token.LT) {
+		return nil
+	}
+
+	var args []string
+	for {
+		args = append(args, p.parseTypeAnnotation())
+		if !p.MatchAndNext(token.COMMA) {
+			break
+		}
+	}
+
+	// Box<Box<int>> лексер читает как '>>': закрываем по одной скобке
+	if p.Match(token.GT_GT) {
+		p.tokens[p.pos].Token = token.GT
+		p.tokens[p.pos].Value = ">"
+		return args
+	}
+	if !p.MatchAndNext(token.GT) {
+		p.error("expected '>' after type arguments", p.Peek(0))
+	}
+	return args
+}
+
+// isGenericInstantiation проверяет, что за именем идет Box<int>{ - создание
+// экземпляра generic структуры, а не сравнение
+func (p *Parser) isGenericInstantiation() bool {
+	savedPos := p.pos
+	defer func() { p.pos = savedPos }()
+
+	depth := 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		switch p.Peek(0).Token {
+		case token.LT:
+			depth++
+		case token.GT:
+			depth--
+		case token.GT_GT:
+			depth -= 2
+		case token.IDENT, token.COMMA, token.QUESTION, token.PIPE:
+		default:
+			return false
+		}
+
+		if depth == 0 {
+			p.pos++
+			return p.MatchAndNext(token.LBRACE) && p.isStructInstantiation()
+		}
+		if depth < 0 {
+			return false
+		}
+	}
+	return false
+}
+
 // InterfaceStatement парсит определение интерфейса
 // interface InterfaceName { методы }
 func (p *Parser) InterfaceStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// Ожидаем имя интерфейса
 	if !p.Match(token.IDENT) {
 		p.error("expected interface name", p.Peek(0))
@@ -1989,6 +2813,9 @@ func (p *Parser) InterfaceStatement() ast.Expr {
 	interfaceName := p.Peek(0).Value
 	p.Next()
 
+	// Параметры типа generic интерфейса: interface Container<T>
+	typeParams := p.typeParameters()
+
 	// Ожидаем открывающую скобку
 	if !p.MatchAndNext(token.LBRACE) {
 		p.error("expected '{' after interface name", p.Peek(0))
@@ -1998,7 +2825,7 @@ func (p *Parser) InterfaceStatement() ast.Expr {
 
 	// Парсим методы интерфейса
 	for !p.Match(token.RBRACE) {
-		method := p.parseInterfaceMethod()
+		method := p.parseInterfaceMethod(typeParams)
 		methods = append(methods, method)
 	}
 
@@ -2007,11 +2834,14 @@ func (p *Parser) InterfaceStatement() ast.Expr {
 		p.error("expected '}' after interface methods", p.Peek(0))
 	}
 
-	return ast.NewInterfaceDefinition(interfaceName, methods)
+	if len(typeParams) > 0 {
+		return spanned(p, start, ast.NewGenericInterfaceDefinition(interfaceName, typeParams, methods))
+	}
+	return spanned(p, start, ast.NewInterfaceDefinition(interfaceName, methods))
 }
 
 // parseInterfaceMethod парсит метод интерфейса
-func (p *Parser) parseInterfaceMethod() ast.InterfaceMethod {
+func (p *Parser) parseInterfaceMethod(typeParams []ast.TypeConstraint) ast.InterfaceMethod {
 	// Ожидаем fn
 	if !p.MatchAndNext(token.FN) {
 		p.error("expected 'fn' for interface method", p.Peek(0))
@@ -2034,7 +2864,7 @@ func (p *Parser) parseInterfaceMethod() ast.InterfaceMethod {
 
 	// Парсим параметры
 	if !p.Match(token.RPAREN) {
-		params = p.parseInterfaceParams()
+		params = p.parseInterfaceParams(typeParams)
 	}
 
 	if !p.MatchAndNext(token.RPAREN) {
@@ -2048,8 +2878,7 @@ func (p *Parser) parseInterfaceMethod() ast.InterfaceMethod {
 		p.Next() // consume '>'
 
 		if p.Match(token.IDENT) {
-			returnType = p.Peek(0).Value
-			p.Next()
+			returnType = p.parseTypeAnnotation()
 		} else {
 			p.error("expected return type after '->'", p.Peek(0))
 		}
@@ -2065,6 +2894,8 @@ func (p *Parser) parseInterfaceMethod() ast.InterfaceMethod {
 // ImplStatement парсит блок реализации интерфейса
 // impl InterfaceName for TypeName { методы }
 func (p *Parser) ImplStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// Ожидаем имя интерфейса
 	if !p.Match(token.IDENT) {
 		p.error("expected interface name after 'impl'", p.Peek(0))
@@ -2073,6 +2904,9 @@ func (p *Parser) ImplStatement() ast.Expr {
 	interfaceName := p.Peek(0).Value
 	p.Next()
 
+	// Аргументы типа generic интерфейса: impl Container<T> for Box<T>
+	interfaceArgs := p.typeArguments()
+
 	// Ожидаем 'for'
 	if !p.Match(token.FOR) {
 		p.error("expected 'for' after interface name", p.Peek(0))
@@ -2087,6 +2921,9 @@ func (p *Parser) ImplStatement() ast.Expr {
 	typeName := p.Peek(0).Value
 	p.Next()
 
+	// Параметры типа generic структуры: for Box<T>
+	typeParams := ast.TypeParamNames(p.typeParameters())
+
 	// Ожидаем открывающую скобку
 	if !p.MatchAndNext(token.LBRACE) {
 		p.error("expected '{' after type name", p.Peek(0))
@@ -2115,11 +2952,14 @@ func (p *Parser) ImplStatement() ast.Expr {
 		p.error("expected '}' after impl methods", p.Peek(0))
 	}
 
-	return ast.NewImplBlock(interfaceName, typeName, methods)
+	impl := ast.NewImplBlock(interfaceName, typeName, methods)
+	impl.InterfaceArgs = interfaceArgs
+	impl.TypeParams = typeParams
+	return spanned(p, start, impl)
 }
 
 // parseInterfaceParams парсит параметры методов интерфейса
-func (p *Parser) parseInterfaceParams() []ast.FuncParam {
+func (p *Parser) parseInterfaceParams(typeParams []ast.TypeConstraint) []ast.FuncParam {
 	var params []ast.FuncParam
 
 	for !p.Match(token.RPAREN) {
@@ -2139,9 +2979,8 @@ func (p *Parser) parseInterfaceParams() []ast.FuncParam {
 
 			if p.Match(token.IDENT) {
 				typeValue := p.Peek(0).Value
-				if p.isTypeName(typeValue) {
-					typeName = typeValue
-					p.Next()
+				if p.isTypeName(typeValue) || p.isGenericType(typeValue, typeParams) {
+					typeName = p.parseTypeAnnotation()
 				} else {
 					p.error("expected type name", p.Peek(0))
 				}
@@ -2168,6 +3007,10 @@ func (p *Parser) parseInterfaceParams() []ast.FuncParam {
 
 // isStructInstantiation проверяет, является ли текущая конструкция созданием экземпляра структуры
 func (p *Parser) isStructInstantiation() bool {
+	if p.noStruct {
+		return false
+	}
+
 	// Если следующий токен RBRACE, это пустая структура {}
 	if p.Peek(0).Token == token.RBRACE {
 		return true
@@ -2192,6 +3035,8 @@ func (p *Parser) isStructInstantiation() bool {
 // TypeAliasStatement парсит определение псевдонима типа
 // type UserId = int
 func (p *Parser) TypeAliasStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// Ожидаем имя нового типа
 	if !p.Match(token.IDENT) {
 		p.error("expected type name after 'type'", p.Peek(0))
@@ -2216,7 +3061,7 @@ func (p *Parser) TypeAliasStatement() ast.Expr {
 		p.error("unknown base type: "+baseType, p.Peek(0))
 	}
 
-	return ast.NewTypeAliasExpr(aliasName, baseType)
+	return spanned(p, start, ast.NewTypeAliasExpr(aliasName, baseType))
 }
 
 // isValidTypeAtParseTime проверяет типы только на этапе парсинга (примитивные)
@@ -2241,10 +3086,10 @@ func (p *Parser) isValidBaseType(typeName string) bool {
 	}
 
 	// Проверяем пользовательские типы в scope
-	if _, exists := scope.GlobalScope.Get(typeName + "__TypeInfo"); exists {
+	if _, exists := p.rt().Scope().Get(typeName + "__TypeInfo"); exists {
 		return true
 	}
-	if _, exists := scope.GlobalScope.Get(typeName); exists {
+	if _, exists := p.rt().Scope().Get(typeName); exists {
 		return true
 	}
 
@@ -2309,7 +3154,7 @@ func (p *Parser) parseTypeAnnotation() string {
 	}
 
 	// Парсим базовый тип
-	baseType := p.Next().Value
+	baseType := p.namedType()
 
 	// Проверяем Optional синтаксис (?)
 	if p.Match(token.QUESTION) {
@@ -2326,7 +3171,7 @@ func (p *Parser) parseTypeAnnotation() string {
 		if !p.Match(token.IDENT) {
 			p.error("expected type name after '|'", p.Peek(0))
 		}
-		types = append(types, p.Next().Value)
+		types = append(types, p.namedType())
 	}
 
 	// Если только один тип - возвращаем как есть
@@ -2338,6 +3183,15 @@ func (p *Parser) parseTypeAnnotation() string {
 	return strings.Join(types, "|")
 }
 
+// namedType читает имя типа с аргументами типа: int, Box<int>, Pair<string,int>
+func (p *Parser) namedType() string {
+	name := p.Next().Value
+	if p.Match(token.LT) {
+		return ast.FormatGenericType(name, p.typeArguments())
+	}
+	return name
+}
+
 // readTemplateBlock читает шаблонный блок для generate как сырой текст
 func (p *Parser) readTemplateBlock() string {
 	// Читаем сырой текст из sourceText между позициями
@@ -2373,32 +3227,20 @@ func (p *Parser) readTemplateBlock() string {
 	return template
 }
 
-// findCurrentPosition ищет текущую позицию в sourceText по линии и колонке
+// findCurrentPosition возвращает байтовое смещение в sourceText сразу после
+// открывающей { шаблонного блока (она уже прочитана парсером)
 func (p *Parser) findCurrentPosition() int {
-	if p.pos >= len(p.tokens) {
-		return -1
-	}
-	
-	currentToken := p.tokens[p.pos]
-	lines := strings.Split(p.sourceText, "\n")
-	
-	if currentToken.Line-1 >= len(lines) {
+	brace := p.Peek(-1)
+	if brace.Token != token.LBRACE || brace.Pos < 0 {
 		return -1
 	}
-	
-	position := 0
-	for i := 0; i < currentToken.Line-1; i++ {
-		position += len(lines[i]) + 1 // +1 для \n
-	}
-	
-	// Находим позицию открывающей скобки в строке
-	line := lines[currentToken.Line-1]
-	bracePos := strings.Index(line[currentToken.Col:], "{")
-	if bracePos == -1 {
+
+	runes := []rune(p.sourceText)
+	if brace.Pos >= len(runes) {
 		return -1
 	}
-	
-	return position + currentToken.Col + bracePos + 1 // +1 чтобы пропустить {
+
+	return len(string(runes[:brace.Pos+1]))
 }
 
 // readTemplateBlockTokens читает блок используя токены (fallback метод)
@@ -2471,6 +3313,8 @@ func needsSpacing(prev, curr token.Token) bool {
 }
 // CompileTimeIfStatement парсит compile-time условие $if
 func (p *Parser) CompileTimeIfStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// $if уже потреблен
 	condition := p.Expression()
 	
@@ -2491,11 +3335,13 @@ func (p *Parser) CompileTimeIfStatement() ast.Expr {
 		}
 	}
 	
-	return ast.NewCompileTimeIfExpr(condition, thenBody, elseBody)
+	return spanned(p, start, ast.NewCompileTimeIfExpr(condition, thenBody, elseBody))
 }
 
 // CompileTimeForStatement парсит compile-time цикл $for
 func (p *Parser) CompileTimeForStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// $for уже потреблен
 	
 	// Парсим iterator in collection
@@ -2504,7 +3350,7 @@ func (p *Parser) CompileTimeForStatement() ast.Expr {
 	}
 	iterator := p.Next().Value
 	
-	if !p.MatchAndNext(token.IDENT) || p.Peek(-1).Value != "in" {
+	if !p.MatchAndNext(token.IN) {
 		p.error("expected 'in' after iterator variable", p.Peek(0))
 	}
 	
@@ -2517,11 +3363,13 @@ func (p *Parser) CompileTimeForStatement() ast.Expr {
 	// Используем BlockStatementWithoutLBrace так как { уже потреблен
 	body := p.BlockStatementWithoutLBrace()
 	
-	return ast.NewCompileTimeForExpr(iterator, collection, body)
+	return spanned(p, start, ast.NewCompileTimeForExpr(iterator, collection, body))
 }
 
 // CompileTimeLetStatement парсит compile-time переменную $let
 func (p *Parser) CompileTimeLetStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// $let уже потреблен
 	
 	if !p.Match(token.IDENT) {
@@ -2535,11 +3383,13 @@ func (p *Parser) CompileTimeLetStatement() ast.Expr {
 	
 	expr := p.Expression()
 	
-	return ast.NewCompileTimeLetExpr(name, expr)
+	return spanned(p, start, ast.NewCompileTimeLetExpr(name, expr))
 }
 
 // CompileTimeWhileStatement парсит compile-time цикл $while
 func (p *Parser) CompileTimeWhileStatement() ast.Expr {
+	start := p.Peek(-1) // ключевое слово уже прочитано
+
 	// $while уже потреблен
 	condition := p.Expression()
 	
@@ -2550,5 +3400,5 @@ func (p *Parser) CompileTimeWhileStatement() ast.Expr {
 	// Используем BlockStatementWithoutLBrace так как { уже потреблен
 	body := p.BlockStatementWithoutLBrace()
 	
-	return ast.NewCompileTimeWhileExpr(condition, body)
+	return spanned(p, start, ast.NewCompileTimeWhileExpr(condition, body))
 }
\ No newline at end of file
@@ -0,0 +1,215 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"foo_lang/builtin"
+	"foo_lang/interpreter"
+	"foo_lang/lexer"
+	"foo_lang/token"
+	"foo_lang/value"
+	"io"
+	"strings"
+)
+
+const (
+	prompt             = "foo> "
+	continuationPrompt = "...> "
+)
+
+// REPL - интерактивный режим. Один интерпретатор (и его ScopeStack) живет между
+// вводами, поэтому объявленные переменные и функции доступны в следующих строках
+type REPL struct {
+	interp *interpreter.Interpreter
+	out    io.Writer
+
+	// checkOverflow переносится в новый интерпретатор при :reset
+	checkOverflow bool
+
+	// buffer накапливает многострочный ввод, пока скобки не сбалансированы
+	buffer strings.Builder
+}
+
+// New создает REPL, который пишет результаты в out
+func New(out io.Writer) *REPL {
+	return &REPL{
+		interp: interpreter.New(),
+		out:    out,
+	}
+}
+
+// SetOverflowCheck включает проверку переполнения int в сессии (--check-overflow)
+func (r *REPL) SetOverflowCheck(enabled bool) {
+	r.checkOverflow = enabled
+	r.interp.Runtime().SetOverflowCheck(enabled)
+}
+
+// Run читает ввод построчно до EOF или команды :quit
+func (r *REPL) Run(in io.Reader) error {
+	fmt.Fprintln(r.out, "foo_lang REPL. Введите :help для списка команд")
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(r.out, r.Prompt())
+
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+
+		if !r.Feed(scanner.Text()) {
+			return nil
+		}
+	}
+}
+
+// Prompt возвращает приглашение: обычное или продолжения многострочного ввода
+func (r *REPL) Prompt() string {
+	if r.buffer.Len() > 0 {
+		return continuationPrompt
+	}
+	return prompt
+}
+
+// Feed обрабатывает одну строку ввода. Возвращает false, если пользователь вышел
+func (r *REPL) Feed(line string) bool {
+	if r.buffer.Len() == 0 {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			return true
+		}
+		if strings.HasPrefix(trimmed, ":") {
+			return r.command(trimmed)
+		}
+	}
+
+	r.buffer.WriteString(line)
+	r.buffer.WriteString("\n")
+
+	if IsIncomplete(r.buffer.String()) {
+		return true
+	}
+
+	code := r.buffer.String()
+	r.buffer.Reset()
+	r.eval(code)
+
+	return true
+}
+
+// IsIncomplete сообщает, что во вводе остались незакрытые скобки и нужно читать дальше
+func IsIncomplete(code string) bool {
+	depth := 0
+
+	for _, t := range lexer.NewLexer(code).Tokens() {
+		switch t.Token {
+		case token.LBRACE, token.LPAREN, token.LBRACK:
+			depth++
+		case token.RBRACE, token.RPAREN, token.RBRACK:
+			depth--
+		}
+	}
+
+	return depth > 0
+}
+
+// eval выполняет код и печатает значение последнего выражения
+func (r *REPL) eval(code string) {
+	exprs, err := r.interp.Parse(code)
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	result, err := r.interp.Run(exprs)
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	// Объявления (let, fn, struct ...) возвращают nil - печатать нечего
+	if result != nil {
+		fmt.Fprintln(r.out, builtin.FormatDebugValue(result))
+	}
+}
+
+// command выполняет команду REPL (:type, :load, :reset ...)
+func (r *REPL) command(input string) bool {
+	name, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case ":quit", ":q", ":exit":
+		return false
+
+	case ":help", ":h":
+		fmt.Fprintln(r.out, "Команды:")
+		fmt.Fprintln(r.out, "  :type <expr>   показать тип выражения")
+		fmt.Fprintln(r.out, "  :load <file>   выполнить файл в текущей сессии")
+		fmt.Fprintln(r.out, "  :reset         очистить все объявления")
+		fmt.Fprintln(r.out, "  :quit          выйти")
+
+	case ":type", ":t":
+		if arg == "" {
+			fmt.Fprintln(r.out, "Usage: :type <expr>")
+			break
+		}
+		r.printType(arg)
+
+	case ":load", ":l":
+		if arg == "" {
+			fmt.Fprintln(r.out, "Usage: :load <file.foo>")
+			break
+		}
+		r.load(arg)
+
+	case ":reset":
+		r.interp = interpreter.New()
+		r.interp.Runtime().SetOverflowCheck(r.checkOverflow)
+		fmt.Fprintln(r.out, "Session reset")
+
+	default:
+		fmt.Fprintf(r.out, "Unknown command %s, type :help for help\n", name)
+	}
+
+	return true
+}
+
+// printType вычисляет выражение и печатает его тип
+func (r *REPL) printType(code string) {
+	exprs, err := r.interp.Parse(code)
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	result, err := r.interp.Run(exprs)
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	typeName, err := builtin.TypeOf([]*value.Value{result})
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	fmt.Fprintln(r.out, typeName.String())
+}
+
+// load выполняет файл в текущей сессии
+func (r *REPL) load(filePath string) {
+	exprs, err := r.interp.ParseFile(filePath)
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	if _, err := r.interp.Run(exprs); err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	fmt.Fprintf(r.out, "Loaded %s\n", filePath)
+}
@@ -134,6 +134,44 @@ func (ss *ScopeStack) PopFunction() {
 	}
 }
 
+// CurrentScope возвращает текущую область (используется для захвата окружения замыканий)
+func (ss *ScopeStack) CurrentScope() *Scope {
+	return ss.current
+}
+
+// RestoreScope делает переданную область текущей
+func (ss *ScopeStack) RestoreScope(s *Scope) {
+	ss.current = s
+}
+
+// EnterFunction создает область функции поверх захваченного окружения parent
+// и возвращает предыдущую текущую область для последующего LeaveFunction
+func (ss *ScopeStack) EnterFunction(parent *Scope) (*Scope, error) {
+	ss.recursionDepth++
+	if ss.recursionDepth > ss.maxRecursion {
+		ss.recursionDepth--
+		return nil, fmt.Errorf("maximum recursion depth exceeded (%d)", ss.maxRecursion)
+	}
+	prev := ss.current
+	ss.current = NewScope(parent)
+	return prev, nil
+}
+
+// LeaveFunction восстанавливает область, сохраненную EnterFunction
+func (ss *ScopeStack) LeaveFunction(prev *Scope) {
+	ss.current = prev
+	if ss.recursionDepth > 0 {
+		ss.recursionDepth--
+	}
+}
+
+// Unwind восстанавливает область и глубину рекурсии, сохраненные перед блоком,
+// выполнение которого прервала паника (используется try/catch)
+func (ss *ScopeStack) Unwind(s *Scope, depth int) {
+	ss.current = s
+	ss.recursionDepth = depth
+}
+
 // SetMaxRecursion устанавливает максимальную глубину рекурсии
 func (ss *ScopeStack) SetMaxRecursion(max int) {
 	ss.maxRecursion = max
@@ -0,0 +1,32 @@
+// Package std встраивает стандартную библиотеку foo (*.foo) в исполняемый файл,
+// чтобы импорт "std:fs" работал независимо от текущего каталога
+package std
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+)
+
+//go:embed *.foo
+var files embed.FS
+
+// Source возвращает исходный код модуля стандартной библиотеки: Source("fs")
+func Source(name string) ([]byte, bool) {
+	content, err := files.ReadFile(strings.TrimSuffix(name, ".foo") + ".foo")
+	if err != nil {
+		return nil, false
+	}
+	return content, true
+}
+
+// Modules возвращает имена встроенных модулей: crypto, fs, http, time
+func Modules() []string {
+	entries, _ := fs.ReadDir(files, ".")
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".foo"))
+	}
+	return names
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/parser"
 	"foo_lang/scope"
@@ -67,7 +68,7 @@ func TestStructDefinition(t *testing.T) {
 
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 
 			if tt.checks != nil {
@@ -127,7 +128,7 @@ func TestTypeofExpression(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -186,7 +187,7 @@ func TestTypeExpression(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -271,7 +272,7 @@ func TestAdvancedMacrosWithTypes(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -336,7 +337,7 @@ func TestMacroCodeGeneration(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
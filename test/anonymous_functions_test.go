@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"testing"
 )
@@ -25,7 +26,7 @@ func TestAnonymousFunctionArrow(t *testing.T) {
 	// Выполняем программу
 	for _, stmt := range program {
 		if stmt != nil {
-			stmt.Eval()
+			stmt.Eval(ast.GlobalRuntime)
 		}
 	}
 }
@@ -52,7 +53,7 @@ func TestAnonymousFunctionBlock(t *testing.T) {
 	// Выполняем программу
 	for _, stmt := range program {
 		if stmt != nil {
-			stmt.Eval()
+			stmt.Eval(ast.GlobalRuntime)
 		}
 	}
 }
@@ -79,7 +80,7 @@ func TestAnonymousFunctionWithDefaults(t *testing.T) {
 	// Выполняем программу
 	for _, stmt := range program {
 		if stmt != nil {
-			stmt.Eval()
+			stmt.Eval(ast.GlobalRuntime)
 		}
 	}
 }
@@ -111,7 +112,7 @@ func TestAnonymousFunctionWithClosures(t *testing.T) {
 	// Выполняем программу
 	for _, stmt := range program {
 		if stmt != nil {
-			stmt.Eval()
+			stmt.Eval(ast.GlobalRuntime)
 		}
 	}
 }
@@ -144,7 +145,7 @@ func TestAnonymousFunctionAssignedToVariable(t *testing.T) {
 	// Выполняем программу
 	for _, stmt := range program {
 		if stmt != nil {
-			stmt.Eval()
+			stmt.Eval(ast.GlobalRuntime)
 		}
 	}
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"testing"
 	"time"
 	"foo_lang/parser"
@@ -28,7 +29,7 @@ print("Result: " + result)
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -64,7 +65,7 @@ print("Result: " + result)
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 	
 	elapsed := time.Since(startTime)
@@ -114,7 +115,7 @@ print("Results count: " + results.length().toString())
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 	
 	elapsed := time.Since(startTime)
@@ -161,7 +162,7 @@ print("Winner: " + winner)
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 	
 	elapsed := time.Since(startTime)
@@ -193,7 +194,7 @@ print("Success result: " + successResult)
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		// Проверяем, что успешная операция прошла без ошибок
 		if result != nil && result.Any() != nil {
 			if str, ok := result.Any().(string); ok && str == "Success" {
@@ -229,7 +230,7 @@ print(result)
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 	
 	// Тест пройден если не было паник
@@ -266,7 +267,7 @@ print("Final shared value: " + sharedValue)
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 	
 	// Тест пройден если замыкания работают с async
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"testing"
@@ -25,7 +26,7 @@ func TestBasicTypes(t *testing.T) {
 			
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 
 			val, ok := scope.GlobalScope.Get("x")
@@ -63,7 +64,7 @@ func TestArithmeticOperators(t *testing.T) {
 			
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 
 			val, ok := scope.GlobalScope.Get("x")
@@ -100,7 +101,7 @@ func TestLogicalOperators(t *testing.T) {
 			
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 
 			val, ok := scope.GlobalScope.Get("x")
@@ -128,6 +129,16 @@ func TestComparisonOperators(t *testing.T) {
 		{"Less", "let x = 3 < 8", true},
 		{"Greater Equal", "let x = 5 >= 5", true},
 		{"Less Equal", "let x = 4 <= 6", true},
+		{"Same Array", "let xs = [1, 2]\nlet x = xs == xs", true},
+		{"Same Array Not Equal", "let xs = [1, 2]\nlet x = xs != xs", false},
+		{"Array Equal", "let x = [1, 2] == [1, 2.0]", true},
+		{"Array Not Equal", "let x = [1, 2] == [1, 3]", false},
+		{"Array Length", "let x = [1, 2] == [1, 2, 3]", false},
+		{"Nested Array", `let x = [[1], {a: "s"}] == [[1], {a: "s"}]`, true},
+		{"Same Object", "let o = {a: 1}\nlet x = o == o", true},
+		{"Object Equal", "let x = {a: 1, b: [2]} == {b: [2], a: 1}", true},
+		{"Object Not Equal", "let x = {a: 1} != {a: 2}", true},
+		{"Object Keys", "let x = {a: 1} == {a: 1, b: 2}", false},
 	}
 
 	for _, tt := range tests {
@@ -136,7 +147,7 @@ func TestComparisonOperators(t *testing.T) {
 			
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 
 			val, ok := scope.GlobalScope.Get("x")
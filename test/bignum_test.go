@@ -0,0 +1,192 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"foo_lang/value"
+	"strings"
+	"testing"
+)
+
+const bignumProgram = `
+let limit = 9223372036854775807n
+let next = limit + 1
+let square = next * next
+let quotient = 10n / 3n
+let remainder = -7n % 3n
+let negative = -5n
+let greater = next > 9223372036854775807
+let sameInt = 1n == 1
+let parsed = bigint("123456789012345678901234567890") * 2
+
+let cents = decimal("0.1") + decimal("0.2")
+let exact = cents == decimal("0.3")
+let scaled = decimal("1.10") + decimal("2.20")
+let third = decimal(1) / 3
+let quarter = decimal(1) / 4
+let total = decimal("19.99") * 3
+let mixed = decimal("0.5") + 1n
+`
+
+var bignumExpected = map[string]string{
+	"next":      "9223372036854775808",
+	"square":    "85070591730234615865843651857942052864",
+	"quotient":  "3",
+	"remainder": "-1",
+	"negative":  "-5",
+	"greater":   "true",
+	"sameInt":   "true",
+	"parsed":    "246913578024691357802469135780",
+	"cents":     "0.3",
+	"exact":     "true",
+	"scaled":    "3.30",
+	"third":     "0.33333333333333333333",
+	"quarter":   "0.25",
+	"total":     "59.97",
+	"mixed":     "1.5",
+}
+
+func TestBigIntAndDecimal(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, bignumProgram+`
+let json = jsonStringify({n: next, d: cents})
+let kinds = typeof(next).String() + " " + typeof(cents).String()
+let text = next.toString() + "/" + decimal("2.675").round(2).toString()
+let typed: bigint = 5n
+let price: decimal = decimal("9.99")
+let members = #{1n, 1, decimal("1.0"), 0.5, decimal("0.5")}.length()
+`)
+
+	expected := map[string]string{
+		"json":    `{"n":9223372036854775808,"d":0.3}`,
+		"kinds":   "bigint decimal",
+		"text":    "9223372036854775808/2.68",
+		"members": "2",
+	}
+	for name, want := range bignumExpected {
+		expected[name] = want
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestCompiledBigIntAndDecimal(t *testing.T) {
+	s := runCompiled(t, bignumProgram)
+
+	for name, want := range bignumExpected {
+		val, ok := s.Get(name)
+		if !ok {
+			t.Errorf("%s not found", name)
+			continue
+		}
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestBigNumberErrors(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		err  string
+	}{
+		"division by zero": {`let x = 1n / 0n`, "division by zero"},
+		"decimal by zero":  {`let x = decimal(1) / 0`, "division by zero"},
+		"not a number":     {`let x = 1n - "a"`, "cannot apply - to bigint and string"},
+		"parse":            {`let x = bigint("12x")`, `bigint() cannot parse "12x"`},
+		"fraction":         {`let x = bigint(1.5)`, "bigint() expects an integer, got 1.5"},
+		"annotation":       {`let x: bigint = 5`, "expected bigint, got int"},
+		"toInt":            {`let x = (9223372036854775807n + 1).toInt()`, "out of int range"},
+	}
+
+	for name, tt := range tests {
+		interp := interpreter.New()
+		exprs, err := interp.Parse(tt.code)
+		if err == nil {
+			_, err = interp.Run(exprs)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error %q, got %v", name, tt.err, err)
+		}
+	}
+}
+
+func TestIntOverflowCheck(t *testing.T) {
+	code := `let x = 9223372036854775807
+let y = x + 1`
+
+	interp := interpreter.New()
+	runInterpreter(t, interp, code)
+	if y, _ := interp.Scope().Get("y"); y.Int64() >= 0 {
+		t.Errorf("expected wrap-around without overflow check, got %v", y.Any())
+	}
+
+	for _, code := range []string{code, `let z = 4611686018427387904 * 2`, `let w = 0 - 9223372036854775807 - 2`} {
+		interp = interpreter.New()
+		interp.Runtime().SetOverflowCheck(true)
+		exprs, err := interp.Parse(code)
+		if err == nil {
+			_, err = interp.Run(exprs)
+		}
+		if err == nil || !strings.Contains(err.Error(), "integer overflow") {
+			t.Errorf("expected integer overflow error, got %v", err)
+		}
+	}
+
+	// Настройка принадлежит интерпретатору: соседний по-прежнему переносит
+	other := interpreter.New()
+	runInterpreter(t, other, code)
+	if y, _ := other.Scope().Get("y"); y.Int64() >= 0 {
+		t.Errorf("expected wrap-around in another interpreter, got %v", y.Any())
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	parse := func(s string) *value.Decimal {
+		d, err := value.ParseDecimal(s)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q): %v", s, err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		got, want string
+	}{
+		{parse("1.5e3").String(), "1500"},
+		{parse("-0.05").String(), "-0.05"},
+		{parse("1.005").Round(2).String(), "1.01"},
+		{parse("-1.005").Round(2).String(), "-1.01"},
+		{parse("7").Sub(parse("0.25")).String(), "6.75"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, tt.got)
+		}
+	}
+
+	if quo, _ := parse("-2").Div(parse("3")); quo.String() != "-0.66666666666666666667" {
+		t.Errorf("unexpected rounding: %s", quo)
+	}
+	if _, err := value.ParseDecimal("1.2.3"); err == nil {
+		t.Error("expected error for invalid decimal")
+	}
+}
+
+func TestCheckerBigNumbers(t *testing.T) {
+	errors := checkProgram(t, `
+let a = 5n
+let b: bigint = a * 2
+let c: int = a + 1
+let d: float = a + 1.5
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:4:1: variable 'c' type error: expected int, got bigint",
+		"<input>:5:1: variable 'd' type error: expected float, got decimal",
+	})
+}
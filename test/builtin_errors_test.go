@@ -0,0 +1,153 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinRecoverableErrorsReturnErr(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let missing = readFile("definitely_missing_file.txt")
+	let missingType = missing.unwrapErr().type
+	let missingCode = missing.unwrapErr().code
+
+	let pattern = regexMatch("[a-z", "abc")
+	let patternCode = pattern.unwrapErr().code
+
+	let decoded = base64Decode("not base64!")
+	let decodedType = decoded.unwrapErr().type
+
+	let matched = regexMatch("^a", "abc").unwrap()
+
+	fn firstLine(path) {
+		let content = readFile(path)?
+		return Ok(content)
+	}
+	let propagated = firstLine("definitely_missing_file.txt")
+	`)
+
+	expected := map[string]string{
+		"missingType": ast.IOError,
+		"missingCode": ast.E501_IO_FAILURE,
+		"patternCode": ast.E504_INVALID_PATTERN,
+		"decodedType": ast.ParseError,
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if val.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, val.String())
+		}
+	}
+
+	missing, _ := interp.Scope().Get("missing")
+	res, ok := missing.Any().(*ast.ResultValue)
+	if !ok || !res.IsErr() {
+		t.Fatalf("expected Err from readFile, got %v", ast.FormatValue(missing.Any()))
+	}
+	if message := res.GetValue().Any().(*ast.ErrorInfo).Message; !strings.Contains(message, "definitely_missing_file.txt") {
+		t.Errorf("expected file name in message, got %q", message)
+	}
+
+	matched, _ := interp.Scope().Get("matched")
+	if !matched.Bool() {
+		t.Error("expected Ok(true) from regexMatch")
+	}
+
+	propagated, _ := interp.Scope().Get("propagated")
+	if res, ok := propagated.Any().(*ast.ResultValue); !ok || !res.IsErr() {
+		t.Errorf("expected ? to propagate Err from readFile, got %v", ast.FormatValue(propagated.Any()))
+	}
+}
+
+func TestBuiltinMisusePanics(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let arity = ""
+	try {
+		readFile()
+	} catch (e) {
+		arity = e.message
+	}
+
+	let argType = ""
+	try {
+		regexMatch(1, "abc")
+	} catch (e) {
+		argType = e.message
+	}
+	`)
+
+	arity, _ := interp.Scope().Get("arity")
+	if !strings.HasPrefix(arity.String(), "readFile() requires exactly 1 argument") {
+		t.Errorf("unexpected arity error: %q", arity.String())
+	}
+
+	argType, _ := interp.Scope().Get("argType")
+	if !strings.HasPrefix(argType.String(), "regexMatch() first argument must be string") {
+		t.Errorf("unexpected type error: %q", argType.String())
+	}
+
+	// Без try ошибка программиста останавливает программу
+	exprs, err := interp.Parse(`hexDecode()`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "hexDecode() requires 1 argument") {
+		t.Errorf("expected arity error from hexDecode, got %v", err)
+	}
+}
+
+func TestJsonParseBuildsValues(t *testing.T) {
+	interp := interpreter.New()
+
+	// Строки в двойных кавычках не поддерживают \", JSON передаём в обратных кавычках
+	document := "`" + `{"name": "foo", "tags": ["a", "b"], "count": 3, "ratio": 0.5, "inner": {"ok": true, "none": null}}` + "`"
+	runInterpreter(t, interp, `
+	let data = jsonParse(`+document+`).unwrap()
+	let name = data.name
+	let tagCount = data.tags.length()
+	let secondTag = data.tags[1]
+	let count = data.count
+	let ratio = data.ratio
+	let innerOk = data.inner.ok
+	let roundTrip = jsonStringify(data)
+
+	let malformed = jsonParse("{name: ")
+	let malformedType = malformed.unwrapErr().type
+	let trailing = jsonParse("1 2").isErr()
+
+	let badDir = System.changeDir("definitely_missing_dir")
+	let badDirType = badDir.unwrapErr().type
+	`)
+
+	expected := map[string]string{
+		"name":          "foo",
+		"tagCount":      "2",
+		"secondTag":     "b",
+		"innerOk":       "true",
+		"roundTrip":     `{"name":"foo","tags":["a","b"],"count":3,"ratio":0.5,"inner":{"ok":true,"none":null}}`,
+		"malformedType": ast.ParseError,
+		"trailing":      "true",
+		"badDirType":    ast.IOError,
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if val.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, val.String())
+		}
+	}
+
+	count, _ := interp.Scope().Get("count")
+	if n, ok := count.Any().(int64); !ok || n != 3 {
+		t.Errorf("expected int64 3 for count, got %T %v", count.Any(), count.Any())
+	}
+	ratio, _ := interp.Scope().Get("ratio")
+	if f, ok := ratio.Any().(float64); !ok || f != 0.5 {
+		t.Errorf("expected float64 0.5 for ratio, got %T %v", ratio.Any(), ratio.Any())
+	}
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+func TestArrayMethodLibrary(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+let nums = [3, 1, 2, 5, 4]
+let sorted = nums.sort()
+let desc = nums.sort(fn(a, b) => b - a)
+let byLess = ["bb", "a", "ccc"].sort(fn(a, b) => a.length() < b.length())
+let found = nums.find(fn(x) => x > 3)
+let missing = nums.find(fn(x) => x > 10)
+let index = nums.findIndex(fn(x) => x == 2)
+let some = nums.some(fn(x) => x > 4)
+let every = nums.every(fn(x) => x > 1)
+let includes = nums.includes(2.0)
+let position = nums.indexOf(5)
+let joined = nums.join("-")
+let reversed = nums.reverse()
+let flat = [1, 2].flatMap(fn(x) => [x, x * 10])
+let zipped = [1, 2, 3].zip(["a", "b"])
+let groups = ["apple", "avocado", "banana"].groupBy(fn(s) => s.charAt(0))
+let chunks = [1, 2, 3, 4, 5].chunk(2).length()
+let unique = [1, 2, 1, 2.0, "a", "a"].unique()
+`)
+
+	expected := map[string]string{
+		"sorted":   "[1, 2, 3, 4, 5]",
+		"desc":     "[5, 4, 3, 2, 1]",
+		"byLess":   "[a, bb, ccc]",
+		"nums":     "[3, 1, 2, 5, 4]",
+		"found":    "5",
+		"missing":  "<nil>",
+		"index":    "2",
+		"some":     "true",
+		"every":    "false",
+		"includes": "true",
+		"position": "3",
+		"joined":   "3-1-2-5-4",
+		"reversed": "[4, 5, 2, 1, 3]",
+		"flat":     "[1, 10, 2, 20]",
+		"zipped":   "[(1, a), (2, b)]",
+		"groups":   "{a: [apple, avocado], b: [banana]}",
+		"chunks":   "3",
+		"unique":   "[1, 2, a]",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestStringMethodLibrary(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+let parts = "a,b,,c".split(",")
+let trimmed = "  hi  ".trim()
+let replaced = "a-b-c".replace("-", "+")
+let repeated = "ab".repeat(3)
+let padded = "7".padStart(3, "0")
+let spaced = "x".padStart(3)
+let contains = "hello".contains("ell")
+let lines = "one\r\ntwo\n".lines()
+let formatted = "{} + {} = {2}, {{ok}}".format(1, 2, 3)
+let chained = "hello".toUpper().substring(1, 3)
+`)
+
+	expected := map[string]string{
+		"parts":     "[a, b, , c]",
+		"trimmed":   "hi",
+		"replaced":  "a+b+c",
+		"repeated":  "ababab",
+		"padded":    "007",
+		"spaced":    "  x",
+		"contains":  "true",
+		"lines":     "[one, two]",
+		"formatted": "1 + 2 = 3, {ok}",
+		"chained":   "EL",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestBuiltinMethodErrors(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		err  string
+	}{
+		"arity":           {`[1].join(",", "x")`, "array.join() expects 0 to 1 argument(s), got 2"},
+		"callback":        {`[1].map(1)`, "array.map() expects a function, got int"},
+		"sort mixed":      {`[1, "a"].sort()`, "array.sort() without comparator expects numbers or strings"},
+		"chunk size":      {`[1].chunk(0)`, "array.chunk() size must be positive"},
+		"format range":    {`"{} {}".format(1)`, "string.format() placeholder 1 out of range"},
+		"unknown method":  {`"abc".shuffle()`, "method 'shuffle' not supported"},
+		"string argument": {`"abc".split(1)`, "string.split() expects a string, got int"},
+	}
+
+	for name, tt := range tests {
+		interp := interpreter.New()
+		exprs, err := interp.Parse(tt.code)
+		if err == nil {
+			_, err = interp.Run(exprs)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error %q, got %v", name, tt.err, err)
+		}
+	}
+}
+
+func TestExtensionOverridesBuiltinMethod(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+extension string {
+	fn repeat(n) { return "custom" }
+}
+let overridden = "x".repeat(2)
+`)
+
+	val, _ := interp.Scope().Get("overridden")
+	if got := val.String(); got != "custom" {
+		t.Errorf("expected extension method to win, got %s", got)
+	}
+}
+
+func TestBuiltinMethodTable(t *testing.T) {
+	join, ok := ast.LookupBuiltinMethod("array", "join")
+	if !ok {
+		t.Fatal("array.join not found in method table")
+	}
+	if got := join.Signature(); got != "fn join(sep?: string) -> string" {
+		t.Errorf("unexpected signature: %s", got)
+	}
+
+	methods := ast.BuiltinMethods("string")
+	for i := 1; i < len(methods); i++ {
+		if methods[i-1].Name >= methods[i].Name {
+			t.Errorf("methods are not sorted: %s before %s", methods[i-1].Name, methods[i].Name)
+		}
+	}
+	for _, name := range []string{"split", "trim", "replace", "repeat", "padStart", "contains", "lines", "format"} {
+		if _, ok := ast.LookupBuiltinMethod("string", name); !ok {
+			t.Errorf("string.%s not found in method table", name)
+		}
+	}
+}
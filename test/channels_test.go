@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/parser"
 	"foo_lang/value"
@@ -80,7 +81,7 @@ func TestChannelBasicOperations(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -106,7 +107,7 @@ func TestChannelNonBlockingOperations(t *testing.T) {
 			`
 			exprs := parser.NewParser(code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 
@@ -127,7 +128,7 @@ func TestChannelNonBlockingOperations(t *testing.T) {
 			`
 			exprs := parser.NewParser(code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 
@@ -158,7 +159,7 @@ func TestChannelWithNumbers(t *testing.T) {
 		`
 		exprs := parser.NewParser(code).ParseWithoutScopeInit()
 		for _, expr := range exprs {
-			expr.Eval()
+			expr.Eval(ast.GlobalRuntime)
 		}
 	})
 
@@ -6,20 +6,11 @@ import (
 	"foo_lang/scope"
 	"foo_lang/builtin"
 	"foo_lang/ast"
-	"foo_lang/modules"
 )
 
 func TestBasicClosure(t *testing.T) {
 	// Устанавливаем global parse function
-	parseFunc := func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 	
 	// Инициализируем тестовое окружение
 	InitTestEnvironment(
@@ -39,7 +30,7 @@ func TestBasicClosure(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	result, ok := scope.GlobalScope.Get("result")
@@ -55,15 +46,7 @@ func TestBasicClosure(t *testing.T) {
 
 func TestClosureModification(t *testing.T) {
 	// Устанавливаем global parse function
-	parseFunc := func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 	
 	// Инициализируем тестовое окружение
 	InitTestEnvironment(
@@ -85,7 +68,7 @@ func TestClosureModification(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	first, ok := scope.GlobalScope.Get("first")
@@ -111,15 +94,7 @@ func TestClosureModification(t *testing.T) {
 
 func TestNestedClosures(t *testing.T) {
 	// Устанавливаем global parse function
-	parseFunc := func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 	
 	// Инициализируем тестовое окружение
 	InitTestEnvironment(
@@ -145,7 +120,7 @@ func TestNestedClosures(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	result, ok := scope.GlobalScope.Get("result")
@@ -161,15 +136,7 @@ func TestNestedClosures(t *testing.T) {
 
 func TestClosureWithParameters(t *testing.T) {
 	// Устанавливаем global parse function
-	parseFunc := func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 	
 	// Инициализируем тестовое окружение
 	InitTestEnvironment(
@@ -193,7 +160,7 @@ func TestClosureWithParameters(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	result, ok := scope.GlobalScope.Get("result")
@@ -209,15 +176,7 @@ func TestClosureWithParameters(t *testing.T) {
 
 func TestClosureWithMathFunctions(t *testing.T) {
 	// Устанавливаем global parse function
-	parseFunc := func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 	
 	// Инициализируем тестовое окружение
 	InitTestEnvironment(
@@ -238,7 +197,7 @@ func TestClosureWithMathFunctions(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	area, ok := scope.GlobalScope.Get("area")
@@ -4,6 +4,7 @@ import (
 	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
+	"foo_lang/value"
 	"testing"
 )
 
@@ -15,7 +16,7 @@ func TestArrayLiterals(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("arr")
@@ -54,7 +55,7 @@ func TestArrayIndexing(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	tests := []struct {
@@ -94,7 +95,7 @@ func TestArrayMethods(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test length
@@ -138,7 +139,7 @@ func TestObjectLiterals(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("obj")
@@ -147,24 +148,24 @@ func TestObjectLiterals(t *testing.T) {
 		return
 	}
 
-	obj, ok := val.Any().(map[string]*ast.Value)
+	obj, ok := val.Any().(*value.Object)
 	if !ok {
 		t.Errorf("expected object, got %T", val.Any())
 		return
 	}
 
 	// Test string field
-	if nameVal := obj["name"]; nameVal == nil || nameVal.String() != "John" {
+	if nameVal, _ := obj.Get("name"); nameVal == nil || nameVal.String() != "John" {
 		t.Errorf("expected obj.name = 'John', got %v", nameVal)
 	}
 
 	// Test integer field
-	if ageVal := obj["age"]; ageVal == nil || ageVal.Int64() != 30 {
+	if ageVal, _ := obj.Get("age"); ageVal == nil || ageVal.Int64() != 30 {
 		t.Errorf("expected obj.age = 30, got %v", ageVal)
 	}
 
 	// Test boolean field
-	if activeVal := obj["active"]; activeVal == nil || !activeVal.Bool() {
+	if activeVal, _ := obj.Get("active"); activeVal == nil || !activeVal.Bool() {
 		t.Errorf("expected obj.active = true, got %v", activeVal)
 	}
 }
@@ -181,7 +182,7 @@ func TestObjectPropertyAccess(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test name access
@@ -219,7 +220,7 @@ func TestObjectStringIndexing(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test string value
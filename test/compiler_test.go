@@ -0,0 +1,190 @@
+package test
+
+import (
+	"foo_lang/builtin"
+	"foo_lang/bytecode"
+	"foo_lang/parser"
+	"foo_lang/scope"
+	"testing"
+)
+
+// runCompiled компилирует программу в байт-код и выполняет ее в VM
+func runCompiled(t *testing.T, code string) *scope.ScopeStack {
+	t.Helper()
+
+	InitTestEnvironment(
+		builtin.InitializeMathFunctions,
+		builtin.InitializeStringFunctions,
+		builtin.InitializeCollectionFunctions,
+	)
+
+	exprs := parser.NewParser(code).ParseWithoutScopeInit()
+	chunk := bytecode.Compile(exprs)
+
+	vm := bytecode.NewVM(chunk, scope.GlobalScope)
+	vm.Run()
+
+	return scope.GlobalScope
+}
+
+// expectCompiledInt проверяет целочисленную переменную после выполнения байт-кода
+func expectCompiledInt(t *testing.T, s *scope.ScopeStack, name string, expected int64) {
+	t.Helper()
+
+	val, ok := s.Get(name)
+	if !ok {
+		t.Fatalf("%s not found", name)
+	}
+
+	if val.Int64() != expected {
+		t.Errorf("%s: expected %d, got %v", name, expected, val.Any())
+	}
+}
+
+func TestCompilerArithmeticAndLet(t *testing.T) {
+	s := runCompiled(t, `
+	let a = 10
+	let b = a * 3 + 2
+	a = a - 4
+	`)
+
+	expectCompiledInt(t, s, "a", 6)
+	expectCompiledInt(t, s, "b", 32)
+}
+
+func TestCompilerIfElse(t *testing.T) {
+	s := runCompiled(t, `
+	let x = 7
+	let result = 0
+	if x > 5 {
+		result = 1
+	} else {
+		result = 2
+	}
+	let other = if x < 5 { 10 } else { 20 }
+	`)
+
+	expectCompiledInt(t, s, "result", 1)
+	expectCompiledInt(t, s, "other", 20)
+}
+
+func TestCompilerForYieldAndBreak(t *testing.T) {
+	s := runCompiled(t, `
+	let sum = 0
+	for let i = 0; i < 100; i++ {
+		if i == 5 {
+			break
+		}
+		sum = sum + i
+	}
+	let squares = for let i = 1; i <= 3; i++ {
+		yield i * i
+	}
+	let count = squares.length()
+	let last = squares[2]
+	`)
+
+	expectCompiledInt(t, s, "sum", 10)
+	expectCompiledInt(t, s, "count", 3)
+	expectCompiledInt(t, s, "last", 9)
+}
+
+func TestCompilerFunctions(t *testing.T) {
+	s := runCompiled(t, `
+	fn fib(n) {
+		if n < 2 {
+			return n
+		}
+		return fib(n - 1) + fib(n - 2)
+	}
+
+	fn add(a, b = 100) {
+		return a + b
+	}
+
+	let f = fib(10)
+	let withDefault = add(1)
+	let explicit = add(1, 2)
+	`)
+
+	expectCompiledInt(t, s, "f", 55)
+	expectCompiledInt(t, s, "withDefault", 101)
+	expectCompiledInt(t, s, "explicit", 3)
+}
+
+func TestCompilerClosures(t *testing.T) {
+	s := runCompiled(t, `
+	fn makeCounter() {
+		let count = 0
+		return fn() {
+			count = count + 1
+			return count
+		}
+	}
+
+	let first = makeCounter()
+	let second = makeCounter()
+	first()
+	first()
+	let a = first()
+	let b = second()
+	`)
+
+	expectCompiledInt(t, s, "a", 3)
+	expectCompiledInt(t, s, "b", 1)
+}
+
+func TestCompilerCollections(t *testing.T) {
+	s := runCompiled(t, `
+	let arr = [1, 2, 3, 4]
+	let obj = {name: "foo", size: 42}
+	let second = arr[1]
+	let size = obj.size
+	let doubled = arr.map(fn(x) => x * 2)
+	let evens = arr.filter(fn(x) => x % 2 == 0)
+	let doubledLast = doubled[3]
+	let evensCount = evens.length()
+	let list = List([5, 6, 7])
+	let map = Map().set("answer", 42)
+	let listItem = list[2]
+	let mapItem = map["answer"]
+	`)
+
+	expectCompiledInt(t, s, "second", 2)
+	expectCompiledInt(t, s, "size", 42)
+	expectCompiledInt(t, s, "doubledLast", 8)
+	expectCompiledInt(t, s, "evensCount", 2)
+	expectCompiledInt(t, s, "listItem", 7)
+	expectCompiledInt(t, s, "mapItem", 42)
+}
+
+func TestCompilerMatchAndInterpolation(t *testing.T) {
+	s := runCompiled(t, `
+	let code = 2
+	let name = match code {
+		1 => "one",
+		2 => "two",
+		_ => "many"
+	}
+	let fallback = match 10 {
+		1 => "one",
+		_ => "many"
+	}
+	let message = "value: ${name}"
+	`)
+
+	name, _ := s.Get("name")
+	if name.String() != "two" {
+		t.Errorf("name: expected two, got %v", name.Any())
+	}
+
+	fallback, _ := s.Get("fallback")
+	if fallback.String() != "many" {
+		t.Errorf("fallback: expected many, got %v", fallback.Any())
+	}
+
+	message, _ := s.Get("message")
+	if message.String() != "value: two" {
+		t.Errorf("message: expected 'value: two', got %v", message.Any())
+	}
+}
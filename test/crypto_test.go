@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"crypto/md5"
 	"crypto/sha256"
@@ -65,7 +66,7 @@ func TestCryptoHashFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -94,7 +95,7 @@ func TestCryptoBase64Functions(t *testing.T) {
 			name: "base64_decode",
 			code: `
 				let encoded = base64Encode("Hello, World!")
-				let decoded = base64Decode(encoded)
+				let decoded = base64Decode(encoded).unwrap()
 				println(decoded)
 			`,
 			expected: "Hello, World!",
@@ -111,7 +112,7 @@ func TestCryptoBase64Functions(t *testing.T) {
 			name: "base64_url_decode",
 			code: `
 				let encoded = base64URLEncode("Hello+World/Test=")
-				let decoded = base64URLDecode(encoded)
+				let decoded = base64URLDecode(encoded).unwrap()
 				println(decoded)
 			`,
 			expected: "Hello+World/Test=",
@@ -128,7 +129,7 @@ func TestCryptoBase64Functions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -157,7 +158,7 @@ func TestCryptoHexFunctions(t *testing.T) {
 			name: "hex_decode",
 			code: `
 				let encoded = hexEncode("Hello")
-				let decoded = hexDecode(encoded)
+				let decoded = hexDecode(encoded).unwrap()
 				println(decoded)
 			`,
 			expected: "Hello",
@@ -174,7 +175,7 @@ func TestCryptoHexFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -246,7 +247,7 @@ func TestCryptoHMACFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -377,7 +378,7 @@ func TestCryptoRandomFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -437,7 +438,7 @@ func TestCryptoPasswordFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -490,7 +491,7 @@ func TestCryptoConstantTimeCompare(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -515,7 +516,7 @@ func TestCryptoErrorHandling(t *testing.T) {
 				println(result)
 			`,
 			wantErr:  true,
-			contains: "Error decoding base64",
+			contains: "Err(ParseError: decoding base64",
 		},
 		{
 			name: "invalid_hex",
@@ -524,13 +525,16 @@ func TestCryptoErrorHandling(t *testing.T) {
 				println(result)
 			`,
 			wantErr:  true,
-			contains: "Error decoding hex",
+			contains: "Err(ParseError: decoding hex",
 		},
 		{
 			name: "random_bytes_too_large",
 			code: `
-				let result = randomBytes(2000)
-				println(result)
+				try {
+					randomBytes(2000)
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "length must be between 1 and 1024",
@@ -538,8 +542,11 @@ func TestCryptoErrorHandling(t *testing.T) {
 		{
 			name: "random_string_empty_alphabet",
 			code: `
-				let result = randomString(10, "")
-				println(result)
+				try {
+					randomString(10, "")
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "alphabet cannot be empty",
@@ -556,7 +563,7 @@ func TestCryptoErrorHandling(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -583,7 +590,7 @@ func TestCryptoKnownVectors(t *testing.T) {
 				println(hash)
 			`).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 
@@ -606,7 +613,7 @@ func TestCryptoKnownVectors(t *testing.T) {
 				println(hash)
 			`).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 
@@ -0,0 +1,249 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/bytecode"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+func TestDeferRunsInReverseOrder(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let steps = ""
+	fn work(early) {
+		defer steps = steps + "first;"
+		defer {
+			steps = steps + "second;"
+		}
+		let state = "open"
+		defer steps = steps + state + ";"
+		state = "closed"
+		if early {
+			return 1
+		}
+		steps = steps + "body;"
+		return 2
+	}
+
+	let early = work(true)
+	let late = work(false)
+	`)
+
+	steps, _ := interp.Scope().Get("steps")
+	want := "closed;second;first;body;closed;second;first;"
+	if steps.String() != want {
+		t.Errorf("unexpected order:\n got %q\nwant %q", steps.String(), want)
+	}
+
+	for name, want := range map[string]int64{"early": 1, "late": 2} {
+		val, _ := interp.Scope().Get(name)
+		if val.Int64() != want {
+			t.Errorf("%s: expected %d, got %v", name, want, val.Any())
+		}
+	}
+}
+
+func TestDeferRunsOnPanicAndPropagate(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let m = newMutex()
+	let released = 0
+
+	fn locked(fail) {
+		mutexLock(m)
+		defer mutexUnlock(m)
+		defer released = released + 1
+		if fail {
+			throw "failed under lock"
+		}
+		return "ok"
+	}
+
+	let message = ""
+	try {
+		locked(true)
+	} catch (e) {
+		message = e.message
+	}
+	let second = locked(false)
+
+	fn parse(r) {
+		defer released = released + 1
+		let n = r?
+		return Ok(n * 2)
+	}
+	let propagated = parse(Err("bad"))
+	`)
+
+	released, _ := interp.Scope().Get("released")
+	if released.Int64() != 3 {
+		t.Errorf("expected 3 deferred calls, got %v", released.Any())
+	}
+
+	message, _ := interp.Scope().Get("message")
+	if message.String() != "failed under lock" {
+		t.Errorf("expected error to reach catch, got %q", message.String())
+	}
+
+	// Второй вызов не зависает: мьютекс освобожден defer после throw
+	second, _ := interp.Scope().Get("second")
+	if second.String() != "ok" {
+		t.Errorf("expected second call to succeed, got %v", second.Any())
+	}
+
+	propagated, _ := interp.Scope().Get("propagated")
+	if res, ok := propagated.Any().(*ast.ResultValue); !ok || !res.IsErr() {
+		t.Errorf("expected Err from ?, got %v", ast.FormatValue(propagated.Any()))
+	}
+}
+
+func TestDeferCapturesArgumentsAndScope(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let trail = ""
+	fn close(f) {
+		trail = trail + "close " + f + ";"
+	}
+
+	fn process(names) {
+		for name in names {
+			let f = name + ".txt"
+			defer close(f)
+		}
+		if names.length() > 0 {
+			let last = names[names.length() - 1]
+			defer {
+				trail = trail + "block " + last + ";"
+			}
+		}
+		let state = "open"
+		defer close(state)
+		state = "closed"
+		return names.length()
+	}
+
+	let count = process(["a", "b"])
+	`)
+
+	// Аргументы close(f) вычислены в момент defer; блок видит свою переменную last
+	trail, _ := interp.Scope().Get("trail")
+	want := "close open;block b;close b.txt;close a.txt;"
+	if trail.String() != want {
+		t.Errorf("unexpected deferred calls:\n got %q\nwant %q", trail.String(), want)
+	}
+
+	count, _ := interp.Scope().Get("count")
+	if count.Int64() != 2 {
+		t.Errorf("expected 2, got %v", count.Any())
+	}
+}
+
+func TestDeferCapturesPrintArguments(t *testing.T) {
+	interp := interpreter.New()
+
+	output := captureOutput(func() {
+		runInterpreter(t, interp, `
+		fn report() {
+			let x = 1
+			defer println("v " + x)
+			defer print("a", x, "")
+			x = 2
+			println("x " + x)
+		}
+		report()
+		`)
+	})
+
+	// Аргументы print/println вычислены в момент defer, как у вызовов функций
+	want := "x 2\na 1 v 1\n"
+	if output != want {
+		t.Errorf("unexpected output:\n got %q\nwant %q", output, want)
+	}
+}
+
+func TestDeferInBytecode(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(`
+	let trail = ""
+	fn note(s) {
+		trail = trail + s + ";"
+	}
+
+	fn work(fail) {
+		defer note("first")
+		for name in ["a", "b"] {
+			defer note(name)
+		}
+		if fail {
+			throw "boom"
+		}
+		return 1
+	}
+
+	let result = work(false)
+	let message = ""
+	try {
+		work(true)
+	} catch (e) {
+		message = e.message
+	}
+	`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	bytecode.NewVMWithRuntime(bytecode.Compile(exprs), interp.Runtime()).Run()
+
+	expected := map[string]string{
+		"trail":   "b;a;first;b;a;first;",
+		"result":  "1",
+		"message": "boom",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := val.String(); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestDeferErrors(t *testing.T) {
+	interp := interpreter.New()
+
+	// Ошибка в отложенном выражении заменяет результат функции
+	exprs, err := interp.Parse(`
+	let cleaned = false
+	fn broken() {
+		defer cleaned = true
+		defer throw "cleanup failed"
+		return 1
+	}
+	broken()
+	`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	_, err = interp.Run(exprs)
+	if err == nil || !strings.Contains(err.Error(), "cleanup failed") {
+		t.Fatalf("expected deferred error, got %v", err)
+	}
+
+	cleaned, _ := interp.Scope().Get("cleaned")
+	if !cleaned.Bool() {
+		t.Error("remaining deferred expressions should still run")
+	}
+
+	exprs, err = interp.Parse(`defer println("top")`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "defer outside of function") {
+		t.Errorf("expected top-level defer error, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package test
+
+import (
+	"errors"
+	"foo_lang/foo"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type embeddingOrder struct {
+	ID    string   `foo:"id"`
+	Total float64  `foo:"total"`
+	Items []string `foo:"items"`
+	Note  string   `foo:"-"`
+}
+
+func newEngine(t *testing.T, opts *foo.Options) *foo.Engine {
+	t.Helper()
+
+	engine, err := foo.New(opts)
+	if err != nil {
+		t.Fatalf("foo.New: %v", err)
+	}
+	return engine
+}
+
+func TestEmbeddingEvalStringAndCall(t *testing.T) {
+	engine := newEngine(t, &foo.Options{
+		Globals: map[string]any{"taxRate": 0.5},
+	})
+
+	if _, err := engine.EvalString(`
+	fn finalPrice(order) {
+		return order.total + order.total * taxRate
+	}
+	fn itemCount(order) {
+		return order.items.length()
+	}
+	`); err != nil {
+		t.Fatalf("EvalString: %v", err)
+	}
+
+	order := embeddingOrder{ID: "A-1", Total: 100, Items: []string{"a", "b"}}
+
+	price, err := engine.Call("finalPrice", order)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if price.Float64() != 150 {
+		t.Errorf("finalPrice: expected 150, got %v", price.Any())
+	}
+
+	count, err := engine.Call("itemCount", &order)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if count.Int64() != 2 {
+		t.Errorf("itemCount: expected 2, got %v", count.Any())
+	}
+
+	if _, err := engine.Call("missing"); err == nil {
+		t.Error("expected error for undefined function")
+	}
+}
+
+func TestEmbeddingGoFunctions(t *testing.T) {
+	engine := newEngine(t, nil)
+
+	if err := engine.Set("greet", func(name string) string { return "Hello " + name }); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Set("sum", func(values ...int) int {
+		total := 0
+		for _, v := range values {
+			total += v
+		}
+		return total
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Set("fail", func() error { return errors.New("boom") }); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := engine.EvalString(`greet("foo") + " " + sum(1, 2, 3).toString()`)
+	if err != nil {
+		t.Fatalf("EvalString: %v", err)
+	}
+	if result.String() != "Hello foo 6" {
+		t.Errorf("expected 'Hello foo 6', got %v", result.Any())
+	}
+
+	_, err = engine.EvalString(`fail()`)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected Go error to surface, got %v", err)
+	}
+}
+
+func TestEmbeddingDecode(t *testing.T) {
+	engine := newEngine(t, nil)
+
+	result, err := engine.EvalString(`{id: "B-2", total: 12.5, items: ["x"], extra: true}`)
+	if err != nil {
+		t.Fatalf("EvalString: %v", err)
+	}
+
+	var order embeddingOrder
+	if err := foo.Decode(result, &order); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if order.ID != "B-2" || order.Total != 12.5 || len(order.Items) != 1 || order.Items[0] != "x" {
+		t.Errorf("unexpected order: %+v", order)
+	}
+
+	generic, ok := foo.ToGo(result).(map[string]any)
+	if !ok || generic["extra"] != true {
+		t.Errorf("ToGo: unexpected %#v", foo.ToGo(result))
+	}
+
+	var count int
+	if err := foo.Decode(result, &count); err == nil {
+		t.Error("expected error decoding object into int")
+	}
+}
+
+func TestEmbeddingScriptFunctionsInGo(t *testing.T) {
+	engine := newEngine(t, nil)
+
+	if _, err := engine.EvalString(`fn double(x) { return x * 2 }`); err != nil {
+		t.Fatalf("EvalString: %v", err)
+	}
+
+	doubleValue, _ := engine.Get("double")
+
+	var double func(int) (int, error)
+	if err := foo.Decode(doubleValue, &double); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if n, err := double(21); err != nil || n != 42 {
+		t.Errorf("double(21): expected 42, got %d (%v)", n, err)
+	}
+
+	fn, ok := foo.ToGo(doubleValue).(foo.Func)
+	if !ok {
+		t.Fatalf("ToGo: expected foo.Func, got %T", foo.ToGo(doubleValue))
+	}
+	if n, err := fn(5); err != nil || n != int64(10) {
+		t.Errorf("fn(5): expected 10, got %v (%v)", n, err)
+	}
+}
+
+func TestEmbeddingEvalFileExports(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "math.foo"), []byte(`
+	export fn square(x) { return x * x }
+	`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rulesPath := filepath.Join(dir, "rules.foo")
+	if err := os.WriteFile(rulesPath, []byte(`
+	import { square } from "./math.foo"
+	fn area(side) { return square(side) }
+	`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newEngine(t, nil)
+	if _, err := engine.EvalFile(rulesPath); err != nil {
+		t.Fatalf("EvalFile: %v", err)
+	}
+
+	area, err := engine.Call("area", 7)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if area.Int64() != 49 {
+		t.Errorf("area: expected 49, got %v", area.Any())
+	}
+}
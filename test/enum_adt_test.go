@@ -0,0 +1,171 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+const shapeProgram = `
+enum Shape { Circle(r: float), Rect(w: float, h: float), Empty }
+
+fn area(s) {
+	let a = match s {
+		Shape.Circle(r) => 3 * r * r,
+		Shape.Rect(w, h) if w == h => w * w,
+		Shape.Rect(w, h) => w * h,
+		Shape.Empty => 0
+	}
+	return a
+}
+`
+
+func TestEnumVariantsWithData(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, shapeProgram+`
+	let circle = Shape.Circle(2.0)
+	let radius = circle.r
+	let circleArea = area(circle)
+	let rectArea = area(Shape.Rect(2, 3))
+	let squareArea = area(Shape.Rect(4, 4))
+	let emptyArea = area(Shape.Empty)
+	let isCircle = match circle { Shape.Circle => true, _ => false }
+	`)
+
+	expected := map[string]float64{
+		"radius":     2,
+		"circleArea": 12,
+		"rectArea":   6,
+		"squareArea": 16,
+		"emptyArea":  0,
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if val.Float64() != want {
+			t.Errorf("%s: expected %v, got %v", name, want, val.Any())
+		}
+	}
+
+	circle, _ := interp.Scope().Get("circle")
+	if got := ast.FormatValue(circle.Any()); got != "Shape.Circle(2)" {
+		t.Errorf("expected Shape.Circle(2), got %q", got)
+	}
+
+	isCircle, _ := interp.Scope().Get("isCircle")
+	if !isCircle.Bool() {
+		t.Error("expected bare variant pattern to match any payload")
+	}
+}
+
+func TestEnumVariantEquality(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, shapeProgram+`
+	enum Other { Circle(r: float) }
+	let results = [
+		Shape.Circle(1) == Shape.Circle(1),
+		Shape.Rect(2, 3) == Shape.Rect(2, 3),
+		Shape.Empty == Shape.Empty,
+		Shape.Circle(1) != Shape.Circle(2),
+		Shape.Circle(1) != Shape.Rect(1, 1),
+		Shape.Circle(1) != Other.Circle(1),
+		Shape.Circle(1) != Shape.Empty,
+		[Shape.Circle(1)].includes(Shape.Circle(1))
+	]
+	`)
+
+	results, _ := interp.Scope().Get("results")
+	for i, item := range results.Any().([]any) {
+		if item != true {
+			t.Errorf("case %d: expected true, got %v", i, item)
+		}
+	}
+}
+
+func TestEnumVariantConstructorChecks(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, shapeProgram+`
+	let arity = ""
+	try {
+		Shape.Rect(1.0)
+	} catch (e) {
+		arity = e.message
+	}
+
+	let fieldType = ""
+	try {
+		Shape.Circle("big")
+	} catch (e) {
+		fieldType = e.message
+	}
+	`)
+
+	arity, _ := interp.Scope().Get("arity")
+	if arity.String() != "Shape.Rect() expects 2 argument(s), got 1" {
+		t.Errorf("unexpected arity error: %q", arity.String())
+	}
+
+	fieldType, _ := interp.Scope().Get("fieldType")
+	if !strings.HasPrefix(fieldType.String(), "Shape.Circle() field 'r': expected float") {
+		t.Errorf("unexpected type error: %q", fieldType.String())
+	}
+}
+
+func TestEnumVariantReflection(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, shapeProgram+`
+	let shapeType = type(Shape)
+	let described = shapeType.String()
+	let isEnum = shapeType.isEnum()
+
+	let summary = ""
+	let names = shapeType.GetVariantNames()
+	for let i = 0; i < names.length(); i++ {
+		let fields = shapeType.GetVariantFields(names[i])
+		let shape = names[i] + "("
+		for let j = 0; j < fields.length(); j++ {
+			shape = shape + fields[j].name + ":" + fields[j].type + ";"
+		}
+		summary = summary + shape + ") "
+	}
+	`)
+
+	described, _ := interp.Scope().Get("described")
+	if described.String() != "enum Shape { Circle(r: float), Rect(w: float, h: float), Empty }" {
+		t.Errorf("unexpected enum description: %q", described.String())
+	}
+
+	isEnum, _ := interp.Scope().Get("isEnum")
+	if !isEnum.Bool() {
+		t.Error("expected isEnum() to be true")
+	}
+
+	summary, _ := interp.Scope().Get("summary")
+	if summary.String() != "Circle(r:float;) Rect(w:float;h:float;) Empty() " {
+		t.Errorf("unexpected variant shapes: %q", summary.String())
+	}
+}
+
+func TestEnumTypeMacroSeesVariants(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, shapeProgram+`
+	let generated = ""
+	macro variantCheckers(enumType: EnumType) {
+		let names = enumType.GetVariantNames()
+		for let i = 0; i < names.length(); i++ {
+			generated = generated + "is" + names[i] + "/" + enumType.GetVariantFields(names[i]).length() + " "
+		}
+	}
+	@variantCheckers(Shape)
+	`)
+
+	generated, _ := interp.Scope().Get("generated")
+	if generated.String() != "isCircle/1 isRect/2 isEmpty/0 " {
+		t.Errorf("unexpected macro output: %q", generated.String())
+	}
+}
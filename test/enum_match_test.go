@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"testing"
@@ -18,7 +19,7 @@ func TestEnumDefinition(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test enum values
@@ -63,7 +64,7 @@ func TestEnumAccess(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	statusVal, ok := scope.GlobalScope.Get("currentStatus")
@@ -92,7 +93,7 @@ func TestMatchExpression(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	resultVal, ok := scope.GlobalScope.Get("result")
@@ -120,7 +121,7 @@ func TestMatchWithDefault(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	resultVal, ok := scope.GlobalScope.Get("result")
@@ -157,7 +158,7 @@ func TestMatchWithBlocks(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	gradeVal, ok := scope.GlobalScope.Get("grade")
@@ -188,7 +189,7 @@ func TestMatchWithEnums(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	messageVal, ok := scope.GlobalScope.Get("message")
@@ -214,7 +215,7 @@ func TestConditionalExpression(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test age condition
@@ -254,7 +255,7 @@ func TestChainedMethodCalls(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test property access with method call
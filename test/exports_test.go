@@ -0,0 +1,88 @@
+package test
+
+import (
+	"foo_lang/modules"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReExportsAndDefaults(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, filepath.Join(dir, "shapes.foo"), `
+export struct Point { x: int, y: int }
+export interface Shape { fn area() -> float }
+export enum Color { RED, GREEN }
+export type Meters = float
+export fn origin() { return Point{x: 0, y: 0} }
+export default fn describe(p) { return "(" + p.x + ", " + p.y + ")" }
+`)
+	writeModule(t, filepath.Join(dir, "geometry.foo"), `
+export * from "./shapes.foo"
+export { origin as zero, default as show } from "./shapes.foo"
+let scale = 2
+export { scale as factor }
+export default scale * 10
+`)
+	writeModule(t, filepath.Join(dir, "main.foo"), `
+import ten, { Point, Color, Meters, zero, show as fmt, factor } from "./geometry.foo"
+import * as geo from "./geometry.foo"
+let shown = fmt(zero()) + fmt(Point{x: 1, y: 2})
+let length: Meters = 2.5
+let color: Color = Color.GREEN
+let total = ten + factor + geo.default
+`)
+
+	interp, err := runFile(t, filepath.Join(dir, "main.foo"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for name, want := range map[string]string{"shown": "(0, 0)(1, 2)", "length": "2.5", "color": "1", "total": "42"} {
+		val, _ := interp.Scope().Get(name)
+		if val == nil || val.String() != want {
+			t.Errorf("%s: expected %q, got %v", name, want, val)
+		}
+	}
+
+	module, _ := interp.Runtime().Modules.Get(filepath.Join(dir, "geometry.foo"))
+	want := "Point Shape Color Meters origin zero show factor default"
+	if got := strings.Join(module.Exports.Names(), " "); got != want {
+		t.Errorf("expected exports %q, got %q", want, got)
+	}
+	for name, kind := range map[string]modules.ExportKind{
+		"Point": modules.TypeExport, "Shape": modules.TypeExport, "Color": modules.TypeExport,
+		"Meters": modules.TypeExport, "zero": modules.ValueExport, "default": modules.ValueExport,
+	} {
+		if export, _ := module.Exports.Get(name); export.Kind != kind {
+			t.Errorf("%s: expected %s export, got %s", name, kind, export.Kind)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, filepath.Join(dir, "lib.foo"), `
+fn secret() { return 42 }
+export let a = 1
+`)
+	writeModule(t, filepath.Join(dir, "other.foo"), `export let a = 2`)
+	writeModule(t, filepath.Join(dir, "both.foo"), `
+export * from "./lib.foo"
+export * from "./other.foo"
+`)
+
+	tests := map[string]string{
+		`import { secret } from "./lib.foo"`: "'secret' is private to module ./lib.foo",
+		`import { b } from "./lib.foo"`:      "module ./lib.foo does not export 'b'",
+		`import lib from "./lib.foo"`:        "module ./lib.foo has no default export",
+		`import { a } from "./both.foo"`:     "duplicate export 'a' (re-exported from ./other.foo)",
+		`export { missing }`:                 "cannot export undefined 'missing'",
+	}
+	for code, want := range tests {
+		path := filepath.Join(dir, "main.foo")
+		writeModule(t, path, code)
+		if _, err := runFile(t, path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected %q, got %v", code, want, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"testing"
 	"foo_lang/parser"
 )
@@ -39,7 +40,7 @@ func TestExtensionMethodsBasic(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -82,7 +83,7 @@ func TestExtensionMethodsNumbers(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -126,7 +127,7 @@ func TestExtensionMethodsWithParameters(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -171,7 +172,7 @@ func TestExtensionMethodsArrays(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -201,7 +202,7 @@ func TestExtensionMethodsWithDefaults(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -269,7 +270,7 @@ func TestExtensionMethodsMultipleTypes(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -308,7 +309,7 @@ func TestExtensionMethodsChainingVsBuiltin(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -377,6 +378,6 @@ func TestExtensionMethodsComplexExample(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
\ No newline at end of file
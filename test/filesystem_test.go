@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"testing"
 	"os"
 	"foo_lang/parser"
@@ -35,7 +36,7 @@ print("File exists: " + fileExists.toString())
 print("Dir exists: " + dirExists.toString())
 
 // Читаем файл обратно
-let readContent = readFile("test_fs_dir/test.txt")
+let readContent = readFile("test_fs_dir/test.txt").unwrap()
 print("Read content: " + readContent)
 
 // Проверяем типы путей
@@ -45,7 +46,7 @@ print("isFile: " + isFileCheck.toString())
 print("isDir: " + isDirCheck.toString())
 
 // Получаем размер файла
-let fileSize = getFileSize("test_fs_dir/test.txt")
+let fileSize = getFileSize("test_fs_dir/test.txt").unwrap()
 print("File size: " + fileSize.toString())
 
 // Копируем файл
@@ -57,18 +58,18 @@ let copyExists = exists("test_fs_dir/copy.txt")
 print("Copy exists: " + copyExists.toString())
 
 // Список файлов в директории
-let files = listDir("test_fs_dir")
+let files = listDir("test_fs_dir").unwrap()
 print("Directory files count: " + files.length().toString())
 `
 
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Check for errors in results
-			if str, ok := result.Any().(string); ok && len(str) > 0 && str[0:5] == "Error" {
-				t.Errorf("Filesystem operation failed: %s", str)
+			if res, ok := result.Any().(*ast.ResultValue); ok && res.IsErr() {
+				t.Errorf("Filesystem operation failed: %s", ast.FormatValue(res.GetValue().Any()))
 			}
 		}
 	}
@@ -108,8 +109,20 @@ print("Nonexistent file exists: " + existsResult.toString())
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		expr.Eval() 
-		// Ошибки обрабатываются через строки, не panic - это нормально
+		expr.Eval(ast.GlobalRuntime) 
+		// Ошибки ввода-вывода возвращаются как Err, не panic - это нормально
+	}
+
+	for _, name := range []string{"readError", "writeError"} {
+		val, _ := scope.GlobalScope.Get(name)
+		res, ok := val.Any().(*ast.ResultValue)
+		if !ok || !res.IsErr() {
+			t.Errorf("%s: expected Err, got %s", name, ast.FormatValue(val.Any()))
+			continue
+		}
+		if info, ok := res.GetValue().Any().(*ast.ErrorInfo); !ok || info.Type != ast.IOError {
+			t.Errorf("%s: expected IOError, got %s", name, ast.FormatValue(res.GetValue().Any()))
+		}
 	}
 }
 
@@ -142,18 +155,18 @@ let jsonWriteResult = writeFile("complex_test_dir/data.json", jsonContent)
 print("JSON write: " + jsonWriteResult)
 
 // Чтение JSON
-let jsonReadContent = readFile("complex_test_dir/data.json")
+let jsonReadContent = readFile("complex_test_dir/data.json").unwrap()
 print("JSON file read successfully: " + (jsonReadContent != "").toString())
 `
 
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Check for errors
-			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
-				t.Errorf("Complex filesystem operation failed: %s", str)
+			if res, ok := result.Any().(*ast.ResultValue); ok && res.IsErr() {
+				t.Errorf("Complex filesystem operation failed: %s", ast.FormatValue(res.GetValue().Any()))
 			}
 		}
 	}
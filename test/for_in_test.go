@@ -0,0 +1,219 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+func TestForInBuiltinCollections(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let items = ""
+	for x in [1, 2, 3] {
+		items = items + x
+	}
+
+	let indexed = ""
+	for i, x in ["a", "b"] {
+		indexed = indexed + i + x
+	}
+
+	let keys = ""
+	let pairs = ""
+	let obj = {b: 2, a: 1}
+	for key in obj {
+		keys = keys + key
+	}
+	for key, val in obj {
+		pairs = pairs + key + "=" + val + ";"
+	}
+
+	let chars = ""
+	for c in "héllo" {
+		chars = chars + c + "."
+	}
+
+	let up = ""
+	for i in 0..4 {
+		up = up + i
+	}
+	let inclusive = ""
+	for i in 1..=3 {
+		inclusive = inclusive + i
+	}
+	let down = ""
+	for i in 3..0 {
+		down = down + i
+	}
+
+	let ch = newChannel(3)
+	send(ch, "x")
+	send(ch, "y")
+	close(ch)
+	let received = ""
+	for i, v in ch {
+		received = received + i + v
+	}
+	`)
+
+	expected := map[string]string{
+		"items":     "123",
+		"indexed":   "0a1b",
+		"keys":      "ba",
+		"pairs":     "b=2;a=1;",
+		"chars":     "h.é.l.l.o.",
+		"up":        "0123",
+		"inclusive": "123",
+		"down":      "",
+		"received":  "0x1y",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if val.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, val.String())
+		}
+	}
+
+	// Переменные цикла живут только внутри итерации
+	for _, name := range []string{"x", "i", "key", "val", "c", "v"} {
+		if _, ok := interp.Scope().Get(name); ok {
+			t.Errorf("%s leaked out of for-in loop", name)
+		}
+	}
+}
+
+func TestForInYieldBreakReturn(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let squares = for x in 1..5 { yield x * x }
+	let evens = for x in 0..10 {
+		if x % 2 == 0 {
+			yield x
+		}
+	}
+
+	let beforeBreak = for x in [1, 2, 3, 4] {
+		if x == 3 {
+			break
+		}
+		yield x
+	}
+
+	fn firstOver(items, limit) {
+		for x in items {
+			if x > limit {
+				return x
+			}
+		}
+		return -1
+	}
+	let found = firstOver([1, 20, 30], 10)
+	let notFound = firstOver([1, 2], 10)
+	`)
+
+	expected := map[string]string{
+		"squares":     "[1, 4, 9, 16]",
+		"evens":       "[0, 2, 4, 6, 8]",
+		"beforeBreak": "[1, 2]",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+
+	found, _ := interp.Scope().Get("found")
+	notFound, _ := interp.Scope().Get("notFound")
+	if found.Int64() != 20 || notFound.Int64() != -1 {
+		t.Errorf("expected 20 and -1, got %v and %v", found.Any(), notFound.Any())
+	}
+}
+
+func TestForInStructIterator(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	struct Countdown { start: int }
+	struct CountdownIter { current: int }
+
+	interface Iterable { fn iter() }
+	interface Iterator { fn next() }
+
+	impl Iterable for Countdown {
+		fn iter() {
+			return CountdownIter{current: this.start}
+		}
+	}
+
+	impl Iterator for CountdownIter {
+		fn next() {
+			if this.current == 0 {
+				return {done: true}
+			}
+			this.current = this.current - 1
+			return {value: this.current + 1, done: false}
+		}
+	}
+
+	let launch = for n in (Countdown{start: 3}) { yield n }
+
+	let it = CountdownIter{current: 2}
+	let direct = ""
+	for i, n in it {
+		direct = direct + i + ":" + n + " "
+	}
+	let exhausted = it.current
+	`)
+
+	launch, _ := interp.Scope().Get("launch")
+	if got := ast.FormatValue(launch.Any()); got != "[3, 2, 1]" {
+		t.Errorf("expected [3, 2, 1], got %s", got)
+	}
+
+	direct, _ := interp.Scope().Get("direct")
+	if direct.String() != "0:2 1:1 " {
+		t.Errorf("unexpected direct iteration: %q", direct.String())
+	}
+
+	exhausted, _ := interp.Scope().Get("exhausted")
+	if exhausted.Int64() != 0 {
+		t.Errorf("expected iterator state to be updated through this, got %v", exhausted.Any())
+	}
+}
+
+func TestForInNotIterable(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(`for x in 42 { println(x) }`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "value of type int is not iterable") {
+		t.Errorf("expected not iterable error, got %v", err)
+	}
+}
+
+func TestForInInBytecode(t *testing.T) {
+	s := runCompiled(t, `
+	let total = 0
+	for x in [1, 2, 3] {
+		total = total + x
+	}
+	let doubled = for i in 0..3 { yield i * 2 }
+	`)
+
+	total, _ := s.Get("total")
+	if total.Int64() != 6 {
+		t.Errorf("expected 6, got %v", total.Any())
+	}
+
+	doubled, _ := s.Get("doubled")
+	if got := ast.FormatValue(doubled.Any()); got != "[0, 2, 4]" {
+		t.Errorf("expected [0, 2, 4], got %s", got)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"testing"
@@ -47,7 +48,7 @@ func TestForYieldBasic(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("arr")
@@ -84,7 +85,7 @@ func TestForYieldWithCondition(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("arr")
@@ -119,7 +120,7 @@ func TestForYieldEmpty(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("empty")
@@ -150,7 +151,7 @@ func TestForYieldWithBreak(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("arr")
@@ -185,7 +186,7 @@ func TestForYieldExpression(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("squares")
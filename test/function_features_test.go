@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"testing"
@@ -27,7 +28,7 @@ func TestFunctionDefaultParameters(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test default parameter usage
@@ -96,7 +97,7 @@ func TestFunctionComplexDefaultParameters(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test expression as default parameter
@@ -136,7 +137,7 @@ func TestFunctionMissingRequiredParameter(t *testing.T) {
 	}()
 
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -158,7 +159,7 @@ func TestMultipleReturnValues(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test divmod function
@@ -214,7 +215,7 @@ func TestMultipleReturnWithSingleValue(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// First variable should get the value
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"strings"
@@ -20,7 +21,7 @@ func TestFunctionDefinition(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("result")
@@ -47,7 +48,7 @@ func TestFunctionWithoutReturn(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Function should execute without error even without return
@@ -70,7 +71,7 @@ func TestRecursiveFunction(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("result")
@@ -109,7 +110,7 @@ func TestMutualRecursion(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	even4Val, ok := scope.GlobalScope.Get("even4")
@@ -159,7 +160,7 @@ func TestRecursionDepthLimit(t *testing.T) {
 	}()
 
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -180,7 +181,7 @@ func TestFunctionScope(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Global variable should still exist
@@ -226,7 +227,7 @@ func TestFunctionScope(t *testing.T) {
 //	
 //	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 //	for _, expr := range exprs {
-//		expr.Eval()
+//		expr.Eval(ast.GlobalRuntime)
 //	}
 //
 //	// Test default parameter usage
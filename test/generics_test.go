@@ -0,0 +1,168 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+const genericsProgram = `
+interface Container<T> {
+	fn get() -> T
+	fn put(item: T) -> bool
+}
+
+struct Box<T> { value: T }
+
+impl Container<T> for Box<T> {
+	fn get() -> T {
+		return this.value
+	}
+	fn put(item: T) -> bool {
+		let previous: T = this.value
+		this.value = item
+		return true
+	}
+}
+
+struct Pair<K, V> { key: K, value: V }
+
+extension Array<T> {
+	fn firstOr(fallback: T) -> T {
+		if this.length() == 0 {
+			return fallback
+		}
+		return this[0]
+	}
+}
+
+let ints = Box<int>{value: 1}
+ints.put(2)
+let inferred = Box{value: "text"}
+let nested = Box<Box<int>>{value: ints}
+let pair = Pair{key: "a", value: 1.5}
+let typed: Box<int> = ints
+let first = [10, 20].firstOr(0)
+let fallback = [].firstOr("none")
+`
+
+func TestGenericStructsAndInterfaces(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, genericsProgram)
+
+	expected := map[string]string{
+		"ints":     "Box<int>",
+		"inferred": "Box<string>",
+		"nested":   "Box<Box<int>>",
+		"pair":     "Pair<string,float>",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.ValueTypeName(val); got != want {
+			t.Errorf("%s: expected type %s, got %s", name, want, got)
+		}
+	}
+
+	ints, _ := interp.Scope().Get("ints")
+	if got := ints.Any().(*ast.StructObject).Fields["value"].Int64(); got != 2 {
+		t.Errorf("expected put to store 2, got %d", got)
+	}
+	if first, _ := interp.Scope().Get("first"); first.Int64() != 10 {
+		t.Errorf("expected firstOr to return 10, got %v", first.Any())
+	}
+	if fallback, _ := interp.Scope().Get("fallback"); fallback.String() != "none" {
+		t.Errorf("expected firstOr fallback, got %v", fallback.Any())
+	}
+}
+
+func TestGenericTypeViolations(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		err  string
+	}{
+		"instance field": {
+			`let b = Box<int>{value: "one"}`,
+			"Box<int> field 'value': expected int, got string",
+		},
+		"field assignment": {
+			`let b = Box<int>{value: 1}
+			b.value = "one"`,
+			"Box<int> field 'value': expected int, got string",
+		},
+		"method parameter": {
+			`let b = Box<int>{value: 1}
+			b.put("one")`,
+			"method 'Box<int>.put' parameter 'item': expected int, got string",
+		},
+		"annotation": {
+			`let b: Box<int> = Box{value: "one"}`,
+			"variable 'b' type error: expected Box<int>, got Box<string>",
+		},
+		"type argument count": {
+			`let b = Box<int, string>{value: 1}`,
+			"Box<T> expects 1 type argument(s), got 2",
+		},
+		"array extension": {
+			`let x = [1, 2].firstOr("zero")`,
+			"method 'Array<int>.firstOr' parameter 'fallback': expected int, got string",
+		},
+		"array annotation": {
+			`let xs: Array<string> = ["a", 1]`,
+			"expected Array<string>, element 1",
+		},
+		"generic function": {
+			`fn same<T>(a: T, b: T) -> T { return a }
+			same(1, "two")`,
+			"function 'same' parameter 'b': expected int (T), got string",
+		},
+		"interface arguments": {
+			`struct Bag { items: array }
+			impl Container<int> for Bag {
+				fn get() -> int { return 0 }
+				fn put(item: string) -> bool { return false }
+			}`,
+			"method 'put' signature doesn't match interface",
+		},
+	}
+
+	for name, tt := range tests {
+		interp := interpreter.New()
+		runInterpreter(t, interp, genericsProgram)
+
+		exprs, err := interp.Parse(tt.code)
+		if err == nil {
+			_, err = interp.Run(exprs)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error %q, got %v", name, tt.err, err)
+		}
+	}
+}
+
+func TestCheckerGenerics(t *testing.T) {
+	errors := checkProgram(t, genericsProgram+`
+let badField = Box<int>{value: "one"}
+ints.value = "two"
+ints.put("three")
+let wrong: Box<string> = ints
+let unwrapped: string = ints.get()
+let count: int = pair.value
+fn same<T>(a: T, b: T) -> T { return a }
+let mixed = same(1, "two")
+let widened: float = same(1, 2.5)
+fn unwrap<T>(box: Box<T>) -> T { return box.get() }
+let inner: string = unwrap(nested)
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:40:16: struct Box<int> field 'value': expected int, got string",
+		"<input>:41:1: struct Box<int> field 'value': expected int, got string",
+		"<input>:42:1: function 'Box<int>.put' parameter 'item': expected int, got string",
+		"<input>:43:1: variable 'wrong' type error: expected Box<string>, got Box<int>",
+		"<input>:44:1: variable 'unwrapped' type error: expected string, got int",
+		"<input>:45:1: variable 'count' type error: expected int, got float",
+		"<input>:47:13: function 'same' parameter 'b': expected int (T), got string",
+		"<input>:50:1: variable 'inner' type error: expected string, got Box<int>",
+	})
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"testing"
 	"time"
 	"foo_lang/parser"
@@ -24,7 +25,7 @@ print("Таймаут установлен")
 
 // Тестируем URL утилиты
 let encoded = urlEncode("Hello World")
-let decoded = urlDecode(encoded)
+let decoded = urlDecode(encoded).unwrap()
 print("URL кодирование работает: " + (decoded == "Hello World").toString())
 
 print("HTTP клиент тесты завершены")
@@ -33,7 +34,7 @@ print("HTTP клиент тесты завершены")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -87,7 +88,7 @@ print("HTTP сервер тесты завершены")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -111,7 +112,7 @@ print("=== Тест HTTP утилит ===")
 // URL кодирование/декодирование
 let originalText = "Hello World & Foo/Bar"
 let encoded = urlEncode(originalText)
-let decoded = urlDecode(encoded)
+let decoded = urlDecode(encoded).unwrap()
 
 print("Оригинал: " + originalText)
 print("Закодировано: " + encoded)
@@ -130,7 +131,7 @@ print("HTTP утилиты тесты завершены")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -165,7 +166,7 @@ print("Все HTTP методы протестированы")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -205,7 +206,7 @@ print("HTTP заголовки тесты завершены")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -272,7 +273,7 @@ print("HTTP роутинг тесты завершены")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
@@ -317,7 +318,7 @@ print("Асинхронные HTTP тесты завершены")
 	exprs := parser.NewParser([]byte(code)).ParseWithoutScopeInit()
 	
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.Any() != nil {
 			// Проверяем, что не было ошибок
 			if str, ok := result.Any().(string); ok && len(str) > 5 && str[0:5] == "Error" {
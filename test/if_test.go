@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"testing"
 )
@@ -14,7 +15,7 @@ func TestIf(t *testing.T) {
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 
 	for _, expr := range exprs {
-		value := expr.Eval()
+		value := expr.Eval(ast.GlobalRuntime)
 
 		if value.String() != "1" {
 			t.Errorf("expected 1, got %s", value.String())
@@ -32,7 +33,7 @@ func TestIfElse(t *testing.T) {
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 
 	for _, expr := range exprs {
-		value := expr.Eval()
+		value := expr.Eval(ast.GlobalRuntime)
 
 		if value.String() != "2" {
 			t.Errorf("expected 2, got %s", value.String())
@@ -50,7 +51,7 @@ func TestIfExpression(t *testing.T) {
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 
 	for _, expr := range exprs {
-		value := expr.Eval()
+		value := expr.Eval(ast.GlobalRuntime)
 
 		if value.String() != "1" {
 			t.Errorf("expected 2, got %s", value.String())
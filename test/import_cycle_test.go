@@ -0,0 +1,231 @@
+package test
+
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"foo_lang/modules"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// runFile выполняет файл и возвращает интерпретатор и ошибку выполнения
+func runFile(t *testing.T, path string) (*interpreter.Interpreter, error) {
+	t.Helper()
+	interp := interpreter.New()
+	exprs, err := interp.ParseFile(path)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	_, err = interp.Run(exprs)
+	return interp, err
+}
+
+func TestImportCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, filepath.Join(dir, "a.foo"), `
+import { b } from "./b.foo"
+export fn a() { return 1 }
+`)
+	writeModule(t, filepath.Join(dir, "b.foo"), `
+import { c } from "./c.foo"
+export fn b() { return 2 }
+`)
+	writeModule(t, filepath.Join(dir, "c.foo"), `
+import { a } from "./a.foo"
+export fn c() { return 3 }
+`)
+	writeModule(t, filepath.Join(dir, "main.foo"), `import { a } from "./a.foo"`)
+
+	_, err := runFile(t, filepath.Join(dir, "main.foo"))
+	if err == nil {
+		t.Fatal("expected import cycle error")
+	}
+
+	chain := strings.Join([]string{
+		filepath.Join(dir, "a.foo"), filepath.Join(dir, "b.foo"),
+		filepath.Join(dir, "c.foo"), filepath.Join(dir, "a.foo"),
+	}, " -> ")
+	if !strings.Contains(err.Error(), "import cycle: "+chain) {
+		t.Errorf("expected full import chain, got %v", err)
+	}
+
+	// Стек ошибки указывает на каждый импорт цепочки
+	scriptErr, ok := err.(*ast.ScriptError)
+	if !ok {
+		t.Fatalf("expected *ast.ScriptError, got %T", err)
+	}
+	var frames []string
+	for _, frame := range scriptErr.Trace {
+		frames = append(frames, frame.String())
+	}
+	trace := strings.Join(frames, "\n")
+	for _, name := range []string{"c.foo:2:1", "b.foo:2:1", "a.foo:2:1", "main.foo:1:1"} {
+		if !strings.Contains(trace, name) {
+			t.Errorf("trace should contain %s:\n%s", name, trace)
+		}
+	}
+}
+
+func TestFailedModuleIsNotCached(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, filepath.Join(dir, "broken.foo"), `
+export let ready = 1
+throw "config missing"
+export let later = 2
+`)
+	writeModule(t, filepath.Join(dir, "main.foo"), `
+let first = ""
+try {
+	import { ready } from "./broken.foo"
+} catch (e) {
+	first = e.message
+}
+import { ready } from "./broken.foo"
+`)
+
+	interp, err := runFile(t, filepath.Join(dir, "main.foo"))
+	first, _ := interp.Scope().Get("first")
+	if first.String() != "config missing" {
+		t.Errorf("expected module error on first import, got %q", first.String())
+	}
+	if err == nil || !strings.Contains(err.Error(), "broken.foo failed to initialize: Error: config missing") {
+		t.Errorf("expected failed module on second import, got %v", err)
+	}
+
+	module, ok := interp.Runtime().Modules.Get(filepath.Join(dir, "broken.foo"))
+	if !ok || module.State != modules.Failed {
+		t.Errorf("expected failed module state, got %+v", module)
+	}
+}
+
+func TestModuleInitOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, filepath.Join(dir, "log.foo"), `
+let entries = []
+export fn record(name) { entries = entries.push(name) }
+export fn recorded() { return entries.join(",") }
+`)
+	writeModule(t, filepath.Join(dir, "config.foo"), `
+import { record } from "./log.foo"
+record("config")
+export let port = 8080
+`)
+	writeModule(t, filepath.Join(dir, "db.foo"), `
+import { record } from "./log.foo"
+import { port } from "./config.foo"
+record("db")
+export let url = "db:" + port
+`)
+	writeModule(t, filepath.Join(dir, "main.foo"), `
+import { url } from "./db.foo"
+import { port } from "./config.foo"
+import { recorded } from "./log.foo"
+let history = recorded()
+defer println("unreachable")
+`)
+
+	interp, err := runFile(t, filepath.Join(dir, "main.foo"))
+	if err == nil || !strings.Contains(err.Error(), "defer outside of function") {
+		t.Errorf("expected top-level defer error after imports, got %v", err)
+	}
+
+	var order []string
+	for _, path := range interp.Runtime().Modules.InitOrder() {
+		order = append(order, filepath.Base(path))
+	}
+	if got := strings.Join(order, ","); got != "log.foo,config.foo,db.foo" {
+		t.Errorf("unexpected initialization order %s", got)
+	}
+
+	// Функции модуля работают с его переменными верхнего уровня
+	for name, want := range map[string]string{"url": "db:8080", "history": "config,db"} {
+		val, _ := interp.Scope().Get(name)
+		if val.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, val.String())
+		}
+	}
+}
+
+func TestConcurrentImportsKeepSeparateChains(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"slow.foo", "a.foo", "b.foo"} {
+		writeModule(t, filepath.Join(dir, name), "export let ready = true")
+	}
+	cache := modules.NewCache()
+
+	// Второй импорт модуля, который выполняет другая горутина, ждет его, а не
+	// считается циклом
+	started, release := make(chan struct{}), make(chan struct{})
+	var runs atomic.Int32
+	slow := func(module *modules.Module, code string) {
+		runs.Add(1)
+		close(started)
+		<-release
+	}
+
+	type loaded struct {
+		module *modules.Module
+		err    error
+	}
+	results := make(chan loaded, 2)
+	load := func() {
+		module, err := cache.Load(filepath.Join(dir, "slow.foo"), &modules.Importer{}, slow)
+		results <- loaded{module, err}
+	}
+	go load()
+	<-started
+	go load()
+
+	select {
+	case result := <-results:
+		t.Fatalf("second import should wait for the first one, got %v", result.err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	first, second := <-results, <-results
+	if first.err != nil || second.err != nil || first.module != second.module {
+		t.Fatalf("expected one module for both imports, got %v and %v", first.err, second.err)
+	}
+	if runs.Load() != 1 || first.module.State != modules.Loaded {
+		t.Errorf("expected module to run once and be loaded, got %d runs, state %s", runs.Load(), first.module.State)
+	}
+
+	// a.foo и b.foo импортируют друг друга из разных горутин: цикл находится
+	// через ожидание, а не блокирует обе горутины
+	aPath, bPath := filepath.Join(dir, "a.foo"), filepath.Join(dir, "b.foo")
+	aStarted, bStarted := make(chan struct{}), make(chan struct{})
+	errs := make(chan error, 2)
+	importCross := func(path, next string, self, other chan struct{}) {
+		defer func() {
+			if r := recover(); r != nil {
+				errs <- fmt.Errorf("%v", r)
+			}
+		}()
+		importer := &modules.Importer{}
+		_, err := cache.Load(path, importer, func(module *modules.Module, code string) {
+			close(self)
+			<-other
+			if _, err := cache.Load(next, importer, nil); err != nil {
+				panic(err)
+			}
+		})
+		errs <- err
+	}
+	go importCross(aPath, bPath, aStarted, bStarted)
+	go importCross(bPath, aPath, bStarted, aStarted)
+
+	for range 2 {
+		select {
+		case err := <-errs:
+			if err == nil || !strings.Contains(err.Error(), "import cycle: ") {
+				t.Errorf("expected import cycle error, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("imports from two goroutines deadlocked")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"testing"
@@ -18,7 +19,7 @@ func TestLet(t *testing.T) {
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	valX, okX := scope.GlobalScope.Get("x")
@@ -51,7 +52,7 @@ func TestLetExpression(t *testing.T) {
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	valZ, okZ := scope.GlobalScope.Get("z")
@@ -0,0 +1,236 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/bytecode"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+const loopsProgram = `
+let i = 0
+let counted = while i < 5 {
+	i = i + 1
+	if i == 2 {
+		continue
+	}
+	yield i
+}
+
+let n = 0
+let odd = loop {
+	n = n + 1
+	if n % 2 == 0 {
+		continue
+	}
+	if n > 7 {
+		break
+	}
+	yield n
+}
+
+let visited = ""
+outer: for let a = 0; a < 3; a++ {
+	for let b = 0; b < 3; b++ {
+		if b == a {
+			continue outer
+		}
+		if a == 2 && b == 1 {
+			break outer
+		}
+		visited = visited + a + b + " "
+	}
+}
+
+let rows = outer: while true {
+	let row = ""
+	loop {
+		row = row + "x"
+		if row.length() == 2 {
+			break
+		}
+	}
+	yield row
+	break outer
+}
+
+let tail = for let k = 0; k < 4; k++ {
+	if k < 2 {
+		continue
+	} else {
+		yield k
+	}
+}
+`
+
+func checkLoops(t *testing.T, get func(string) (*ast.Value, bool)) {
+	t.Helper()
+
+	expected := map[string]string{
+		"counted": "[1, 3, 4, 5]",
+		"odd":     "[1, 3, 5, 7]",
+		"visited": "10 20 ",
+		"rows":    "[xx]",
+		"tail":    "[2, 3]",
+	}
+	for name, want := range expected {
+		val, ok := get(name)
+		if !ok {
+			t.Errorf("%s is not defined", name)
+			continue
+		}
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestWhileLoopContinueAndLabels(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, loopsProgram)
+	checkLoops(t, interp.Scope().Get)
+}
+
+func TestWhileLoopContinueAndLabelsInBytecode(t *testing.T) {
+	s := runCompiled(t, loopsProgram)
+	checkLoops(t, s.Get)
+}
+
+func TestLoopIterationScopeIsolation(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let tick = 0
+	let seen = while tick < 3 {
+		let temp = tick * 10
+		tick = tick + 1
+		yield temp
+	}
+	`)
+
+	seen, _ := interp.Scope().Get("seen")
+	if got := ast.FormatValue(seen.Any()); got != "[0, 10, 20]" {
+		t.Errorf("expected [0, 10, 20], got %s", got)
+	}
+	if _, ok := interp.Scope().Get("temp"); ok {
+		t.Error("temp leaked out of while loop")
+	}
+}
+
+func TestUnknownLoopLabel(t *testing.T) {
+	interp := interpreter.New()
+
+	_, err := interp.Parse(`
+	for let i = 0; i < 3; i++ {
+		break missing
+	}
+	`)
+	if err == nil || !strings.Contains(err.Error(), "unknown loop label 'missing'") {
+		t.Errorf("expected unknown label error, got %v", err)
+	}
+}
+
+func TestCallInLoopDoesNotReturn(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	fn add(x) { return x + 1 }
+	fn count() {
+		let total = 0
+		for x in [1, 2, 3] {
+			total = add(total)
+		}
+		let i = 0
+		while i < 3 {
+			i = i + 1
+			add(i)
+		}
+		return "done " + total + " " + i
+	}
+	let result = count()
+	`)
+
+	result, _ := interp.Scope().Get("result")
+	if got := result.String(); got != "done 3 3" {
+		t.Errorf("expected calls inside loops to continue the loop, got %q", got)
+	}
+}
+
+// fallbackLoopsProgram - break и continue из узлов, которые байт-код выполняет
+// tree-walking интерпретатором (try, структурный match, for-in)
+const fallbackLoopsProgram = `
+let fromTry = for let i = 0; i < 5; i++ {
+	try {
+		if i == 2 {
+			break
+		}
+	} catch (e) {
+	}
+	yield i
+}
+
+let fromMatch = ""
+let results = [Ok(1), Ok(2), Err("stop"), Ok(4)]
+for let j = 0; j < results.length(); j++ {
+	match results[j] {
+		Ok(v) => fromMatch = fromMatch + v,
+		Err(e) => break
+	}
+}
+
+let fromForIn = ""
+let count = 0
+outer: while count < 10 {
+	count = count + 1
+	for x in [1, 2] {
+		if count == 2 {
+			continue outer
+		}
+		if count == 4 {
+			break outer
+		}
+		fromForIn = fromForIn + count + x + " "
+	}
+}
+let rest = count
+`
+
+func checkFallbackLoops(t *testing.T, get func(string) (*ast.Value, bool)) {
+	t.Helper()
+
+	expected := map[string]string{
+		"fromTry":   "[0, 1]",
+		"fromMatch": "12",
+		"fromForIn": "11 12 31 32 ",
+		"rest":      "4",
+	}
+	for name, want := range expected {
+		val, ok := get(name)
+		if !ok {
+			t.Errorf("%s is not defined", name)
+			continue
+		}
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestBreakContinueFromFallbackNodes(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, fallbackLoopsProgram)
+	checkFallbackLoops(t, interp.Scope().Get)
+}
+
+func TestBreakContinueFromFallbackNodesInBytecode(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(fallbackLoopsProgram)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	bytecode.NewVMWithRuntime(bytecode.Compile(exprs), interp.Runtime()).Run()
+
+	checkFallbackLoops(t, interp.Scope().Get)
+}
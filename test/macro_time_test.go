@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/parser"
 	"io"
@@ -73,7 +74,7 @@ func TestMacroTimeExecution(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -152,7 +153,7 @@ func TestMacroTimeWithPolymorphicTypes(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -208,7 +209,7 @@ func TestMacroTimeAdvanced(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
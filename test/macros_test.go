@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/parser"
 	"io"
@@ -128,7 +129,7 @@ func TestMacros(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -199,7 +200,7 @@ func TestMacroErrors(t *testing.T) {
 			InitTestEnvironment()
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 	}
@@ -236,7 +237,7 @@ func TestQuoteUnquote(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
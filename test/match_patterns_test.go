@@ -0,0 +1,175 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+const describeProgram = `
+struct User { name: string, age: int }
+
+fn describe(v) {
+	let r = match v {
+		0 => "zero",
+		1 | 2 => "small",
+		3..10 => "medium",
+		Ok(x) if x > 100 => "big " + x,
+		Ok(x) => "ok " + x,
+		Err({message}) => "error " + message,
+		Err(e) => "err " + e,
+		[] => "empty",
+		[first, ...rest] => "first " + first + " of " + (rest.length() + 1),
+		User{name, age} if age >= 18 => "adult " + name,
+		{name, age: 30} => "thirty " + name,
+		n if n > 1000 => "huge",
+		_ => "other"
+	}
+	return r
+}
+`
+
+func TestMatchStructuralPatterns(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, describeProgram+`
+	let results = [
+		describe(0),
+		describe(2),
+		describe(7),
+		describe(Ok(500)),
+		describe(Ok(5)),
+		describe(readFile("definitely_missing_file.txt")),
+		describe(Err("boom")),
+		describe([]),
+		describe([4, 5, 6]),
+		describe(User{name: "Ann", age: 20}),
+		describe(User{name: "Kid", age: 10}),
+		describe({name: "Bob", age: 30}),
+		describe(5000),
+		describe(50)
+	]
+	`)
+
+	expected := []string{
+		"zero",
+		"small",
+		"medium",
+		"big 500",
+		"ok 5",
+		"error reading file 'definitely_missing_file.txt'",
+		"err boom",
+		"empty",
+		"first 4 of 3",
+		"adult Ann",
+		"other",
+		"thirty Bob",
+		"huge",
+		"other",
+	}
+
+	results, _ := interp.Scope().Get("results")
+	items := results.Any().([]any)
+	if len(items) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(items))
+	}
+	for i, want := range expected {
+		got := fmt.Sprintf("%v", items[i])
+		if !strings.HasPrefix(got, want) {
+			t.Errorf("case %d: expected %q, got %q", i, want, got)
+		}
+	}
+
+	// Связанные имена не выходят за пределы ветки
+	for _, name := range []string{"x", "first", "rest", "name", "n"} {
+		if _, ok := interp.Scope().Get(name); ok {
+			t.Errorf("%s leaked out of match arm", name)
+		}
+	}
+}
+
+func TestMatchEnumExhaustiveness(t *testing.T) {
+	interp := interpreter.New()
+	var warnings bytes.Buffer
+	interp.Runtime().SetWarningOutput(&warnings)
+
+	runInterpreter(t, interp, `
+	enum Color { RED, GREEN, BLUE }
+	let c = Color.GREEN
+
+	let partial = match c {
+		Color.RED => "red",
+		Color.GREEN => "green",
+	}
+
+	let full = match c {
+		Color.RED | Color.GREEN => "warm",
+		Color.BLUE => "cold",
+	}
+
+	let fallback = match c {
+		Color.RED => "red",
+		_ => "not red",
+	}
+	`)
+
+	partial, _ := interp.Scope().Get("partial")
+	if partial.String() != "green" {
+		t.Errorf("expected 'green', got %q", partial.String())
+	}
+
+	out := warnings.String()
+	if strings.Count(out, "warning:") != 1 || !strings.Contains(out, "non-exhaustive match on enum Color: missing Color.BLUE") {
+		t.Errorf("expected one warning about Color.BLUE, got %q", out)
+	}
+}
+
+func TestMatchStructuralPatternsInBytecode(t *testing.T) {
+	s := runCompiled(t, `
+	let pair = [1, 2]
+	let sum = match pair {
+		[a, b] => a + b,
+		_ => 0
+	}
+	let literal = match 3 {
+		1 => "one",
+		_ => "many"
+	}
+	`)
+
+	sum, _ := s.Get("sum")
+	if sum.Int64() != 3 {
+		t.Errorf("expected 3, got %v", sum.Any())
+	}
+
+	literal, _ := s.Get("literal")
+	if literal.String() != "many" {
+		t.Errorf("expected 'many', got %q", literal.String())
+	}
+}
+
+func TestMatchRangePatternBounds(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	fn grade(n) {
+		let r = match n {
+			0..10 => "exclusive",
+			10..=20 => "inclusive",
+			"a".."n" => "first half",
+			_ => "other"
+		}
+		return r
+	}
+	let grades = [grade(0), grade(9), grade(10), grade(20), grade(21), grade("m"), grade("n")]
+	`)
+
+	grades, _ := interp.Scope().Get("grades")
+	got := fmt.Sprintf("%v", grades.Any())
+	want := "[exclusive exclusive inclusive inclusive other first half other]"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
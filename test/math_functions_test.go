@@ -7,20 +7,11 @@ import (
 	"foo_lang/scope"
 	"foo_lang/builtin"
 	"foo_lang/ast"
-	"foo_lang/modules"
 )
 
 func TestBasicMathFunctions(t *testing.T) {
 	// Устанавливаем глобальную parseFunc для import
-	parseFunc := func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 	
 	// Инициализируем тестовое окружение
 	InitTestEnvironment(
@@ -58,7 +49,7 @@ func TestBasicMathFunctions(t *testing.T) {
 			
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 
 			result, ok := scope.GlobalScope.Get("result")
@@ -110,7 +101,7 @@ func TestMathFunctionErrors(t *testing.T) {
 
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 	}
@@ -135,7 +126,7 @@ func TestMathFunctionIntegration(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	result, ok := scope.GlobalScope.Get("result")
@@ -167,7 +158,7 @@ func TestTrigonometricFunctions(t *testing.T) {
 
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// sin(π/2) should be close to 1
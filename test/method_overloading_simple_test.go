@@ -32,7 +32,7 @@ func TestMethodOverloadingSimple(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -67,6 +67,6 @@ func TestMethodOverloadingDifferentParamCounts(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
\ No newline at end of file
@@ -37,7 +37,7 @@ func TestMethodOverloadingBasic(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -72,7 +72,7 @@ func TestMethodOverloadingParameterCounts(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -103,7 +103,7 @@ func TestMethodOverloadingTypePromotion(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -138,7 +138,7 @@ func TestMethodOverloadingWithAnyType(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -173,7 +173,7 @@ func TestMethodOverloadingMathOperations(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -208,7 +208,7 @@ func TestMethodOverloadingReturnTypes(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -244,7 +244,7 @@ func TestMethodOverloadingComplexTypes(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -269,7 +269,7 @@ func TestMethodOverloadingErrorCases(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -304,6 +304,6 @@ func TestMethodOverloadingWithDefaults(t *testing.T) {
 
 	exprs := p.Parse()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
\ No newline at end of file
@@ -5,30 +5,16 @@ import (
 	"path/filepath"
 	"foo_lang/parser"
 	"foo_lang/scope"
-	"foo_lang/modules"
 	"foo_lang/value"
 	"foo_lang/ast"
 	"testing"
 )
 
-// Helper function to create parse function for tests
-func createParseFunc() modules.ParseFunc {
-	return func(code string) []modules.Expr {
-		exprs := parser.NewParser(code).ParseWithoutScopeInit()
-		result := make([]modules.Expr, len(exprs))
-		for i, expr := range exprs {
-			result[i] = expr
-		}
-		return result
-	}
-}
-
 func TestModuleLoading(t *testing.T) {
 	InitTestEnvironment()
 	
 	// Set up global parse function
-	parseFunc := createParseFunc()
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 
 	// Create a temporary test module file
 	tempDir, err := os.MkdirTemp("", "foo_test_modules")
@@ -56,7 +42,7 @@ export let PI = 3.14159
 	}
 
 	// Test full import: import "./math_utils.foo"  
-	err = modules.ImportModule(mathModulePath, "./", []string{}, "", parseFunc)
+	err = ast.GlobalRuntime.ImportModule(mathModulePath, []string{}, "")
 	if err != nil {
 		t.Fatalf("Failed to import module: %v", err)
 	}
@@ -70,7 +56,7 @@ let piValue = PI
 	
 	exprs := parser.NewParser(testCode).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Verify imported items
@@ -106,8 +92,7 @@ func TestSelectiveImport(t *testing.T) {
 	InitTestEnvironment()
 	
 	// Set up global parse function
-	parseFunc := createParseFunc()
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 
 	// Create a temporary test module file
 	tempDir, err := os.MkdirTemp("", "foo_test_modules")
@@ -136,7 +121,7 @@ export let DEBUG = true
 	}
 
 	// Test selective import: import { greet, VERSION } from "./utils.foo" 
-	err = modules.ImportModule(utilsModulePath, "./", []string{"greet", "VERSION"}, "", parseFunc)
+	err = ast.GlobalRuntime.ImportModule(utilsModulePath, []string{"greet", "VERSION"}, "")
 	if err != nil {
 		t.Fatalf("Failed to import module: %v", err)
 	}
@@ -149,7 +134,7 @@ let version = VERSION
 	
 	exprs := parser.NewParser(testCode).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Verify selective import worked
@@ -187,8 +172,7 @@ func TestAliasImport(t *testing.T) {
 	InitTestEnvironment()
 	
 	// Set up global parse function
-	parseFunc := createParseFunc()
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 
 	// Create a temporary test module file
 	tempDir, err := os.MkdirTemp("", "foo_test_modules")
@@ -216,7 +200,7 @@ export let NAME = "Calculator"
 	}
 
 	// Test alias import: import * as Calc from "./calculator.foo" 
-	err = modules.ImportModule(calcModulePath, "./", []string{}, "Calc", parseFunc)
+	err = ast.GlobalRuntime.ImportModule(calcModulePath, []string{}, "Calc")
 	if err != nil {
 		t.Fatalf("Failed to import module: %v", err)
 	}
@@ -229,20 +213,20 @@ export let NAME = "Calculator"
 	}
 
 	// Verify it's an object containing the exports
-	calcObj, ok := calcVal.Any().(map[string]*value.Value)
+	calcObj, ok := calcVal.Any().(*value.Object)
 	if !ok {
 		t.Errorf("Calc should be a module object, got %T", calcVal.Any())
 		return
 	}
 
 	// Check that expected exports are present
-	if _, exists := calcObj["add"]; !exists {
+	if !calcObj.Has("add") {
 		t.Errorf("add function not found in Calc module")
 	}
-	if _, exists := calcObj["subtract"]; !exists {
+	if !calcObj.Has("subtract") {
 		t.Errorf("subtract function not found in Calc module")
 	}
-	if _, exists := calcObj["NAME"]; !exists {
+	if !calcObj.Has("NAME") {
 		t.Errorf("NAME variable not found in Calc module")
 	}
 }
@@ -251,8 +235,7 @@ func TestModuleCaching(t *testing.T) {
 	InitTestEnvironment()
 	
 	// Set up global parse function
-	parseFunc := createParseFunc()
-	ast.SetGlobalParseFunc(parseFunc)
+	ast.SetGlobalParseFunc(parser.ParseInRuntime)
 
 	// Create a temporary test module file
 	tempDir, err := os.MkdirTemp("", "foo_test_modules")
@@ -276,7 +259,7 @@ export let MESSAGE = "Hello from module"
 	}
 
 	// Import module first time
-	err = modules.ImportModule(simpleModulePath, "./", []string{}, "", parseFunc)
+	err = ast.GlobalRuntime.ImportModule(simpleModulePath, []string{}, "")
 	if err != nil {
 		t.Fatalf("Failed to import module: %v", err)
 	}
@@ -289,7 +272,7 @@ export let MESSAGE = "Hello from module"
 	}
 	
 	// Import module second time - should use cached version
-	err = modules.ImportModule(simpleModulePath, "./", []string{}, "", parseFunc)
+	err = ast.GlobalRuntime.ImportModule(simpleModulePath, []string{}, "")
 	if err != nil {
 		t.Fatalf("Failed to import module second time: %v", err)
 	}
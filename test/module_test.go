@@ -4,6 +4,7 @@ import (
 	"foo_lang/parser"
 	"foo_lang/scope"
 	"foo_lang/ast"
+	"foo_lang/modules"
 	"testing"
 )
 
@@ -113,7 +114,7 @@ func TestBasicExportParsing(t *testing.T) {
 
 	// The export should parse and execute successfully
 	expr := exprs[0]
-	result := expr.Eval()
+	result := expr.Eval(ast.GlobalRuntime)
 	
 	// Export statements return the result of their declaration
 	if result != nil {
@@ -126,7 +127,7 @@ func TestBasicExportParsing(t *testing.T) {
 	}
 
 	// Check if export was registered
-	if _, ok := scope.GlobalScope.Get("__export_add"); !ok {
+	if _, ok := ast.GlobalRuntime.Exports().Get("add"); !ok {
 		t.Errorf("export 'add' not registered")
 	}
 }
@@ -144,7 +145,7 @@ func TestExportVariable(t *testing.T) {
 
 	// The export should parse and execute successfully
 	expr := exprs[0]
-	result := expr.Eval()
+	result := expr.Eval(ast.GlobalRuntime)
 	
 	// Export statements return nil
 	if result != nil {
@@ -159,7 +160,7 @@ func TestExportVariable(t *testing.T) {
 	}
 
 	// Check if export was registered
-	if _, ok := scope.GlobalScope.Get("__export_PI"); !ok {
+	if _, ok := ast.GlobalRuntime.Exports().Get("PI"); !ok {
 		t.Errorf("export 'PI' not registered")
 	}
 }
@@ -177,7 +178,7 @@ func TestExportConstant(t *testing.T) {
 
 	// The export should parse and execute successfully
 	expr := exprs[0]
-	result := expr.Eval()
+	result := expr.Eval(ast.GlobalRuntime)
 	
 	// Export statements return nil
 	if result != nil {
@@ -192,7 +193,7 @@ func TestExportConstant(t *testing.T) {
 	}
 
 	// Check if export was registered
-	if _, ok := scope.GlobalScope.Get("__export_MAX_SIZE"); !ok {
+	if _, ok := ast.GlobalRuntime.Exports().Get("MAX_SIZE"); !ok {
 		t.Errorf("export 'MAX_SIZE' not registered")
 	}
 }
@@ -210,7 +211,7 @@ func TestExportEnum(t *testing.T) {
 
 	// The export should parse and execute successfully
 	expr := exprs[0]
-	result := expr.Eval()
+	result := expr.Eval(ast.GlobalRuntime)
 	
 	// Export statements return nil
 	if result != nil {
@@ -223,8 +224,10 @@ func TestExportEnum(t *testing.T) {
 	}
 
 	// Check if export was registered
-	if _, ok := scope.GlobalScope.Get("__export_Status"); !ok {
+	if export, ok := ast.GlobalRuntime.Exports().Get("Status"); !ok {
 		t.Errorf("export 'Status' not registered")
+	} else if export.Kind != modules.TypeExport || export.Type == nil {
+		t.Errorf("expected enum Status to be a type export with type info, got %+v", export)
 	}
 }
 
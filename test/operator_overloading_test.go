@@ -0,0 +1,206 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+const vectorProgram = `
+struct Vec { x: int, y: int }
+
+impl Add for Vec {
+	fn add(other: Vec) -> Vec {
+		return Vec{x: this.x + other.x, y: this.y + other.y}
+	}
+}
+
+impl Mul for Vec {
+	fn mul(k: int) -> Vec {
+		return Vec{x: this.x * k, y: this.y * k}
+	}
+}
+
+impl Neg for Vec {
+	fn neg() -> Vec {
+		return Vec{x: -this.x, y: -this.y}
+	}
+}
+
+impl Eq for Vec {
+	fn eq(other) -> bool {
+		return this.x == other.x && this.y == other.y
+	}
+}
+
+impl Ord for Vec {
+	fn cmp(other: Vec) -> int {
+		return this.x * this.x + this.y * this.y - other.x * other.x - other.y * other.y
+	}
+}
+
+impl Index for Vec {
+	fn index(i: int) -> int {
+		if i == 0 {
+			return this.x
+		}
+		return this.y
+	}
+}
+
+impl Display for Vec {
+	fn toString() -> string {
+		return "(" + this.x + ", " + this.y + ")"
+	}
+}
+`
+
+func TestOperatorOverloading(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, vectorProgram+`
+	let a = Vec{x: 1, y: 2}
+	let b = Vec{x: 3, y: 4}
+
+	let sum = a + b
+	let scaled = a * 3
+	let negated = -a
+	let same = a == Vec{x: 1, y: 2}
+	let different = a != b
+	let less = a < b
+	let greaterEq = a >= b
+	let first = b[0]
+	let second = b[1]
+	let text = "sum = ${sum}"
+	let joined = "v" + b
+	`)
+
+	expected := map[string]string{
+		"sum":       "(4, 6)",
+		"scaled":    "(3, 6)",
+		"negated":   "(-1, -2)",
+		"same":      "true",
+		"different": "true",
+		"less":      "true",
+		"greaterEq": "false",
+		"first":     "3",
+		"second":    "4",
+		"text":      "sum = (4, 6)",
+		"joined":    "v(3, 4)",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.Display(interp.Runtime(), val); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestOperatorInterfacesInArrayMethods(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, vectorProgram+`
+	let vectors = [Vec{x: 3, y: 4}, Vec{x: 1, y: 0}, Vec{x: 3, y: 4}, Vec{x: 0, y: 2}]
+
+	let includes = vectors.includes(Vec{x: 1, y: 0})
+	let position = vectors.indexOf(Vec{x: 0, y: 2})
+	let unique = vectors.unique()
+	let sorted = vectors.sort()
+	let arraysEqual = [Vec{x: 1, y: 0}] == [Vec{x: 1, y: 0}]
+	let arraysDiffer = [Vec{x: 1, y: 0}] != [Vec{x: 0, y: 1}]
+	`)
+
+	expected := map[string]string{
+		"includes":     "true",
+		"position":     "3",
+		"unique":       "[(3, 4), (1, 0), (0, 2)]",
+		"sorted":       "[(1, 0), (0, 2), (3, 4), (3, 4)]",
+		"arraysEqual":  "true",
+		"arraysDiffer": "true",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		got := ast.Display(interp.Runtime(), val)
+		if items, ok := val.Any().([]any); ok {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = ast.Display(interp.Runtime(), ast.NewValue(item))
+			}
+			got = "[" + strings.Join(parts, ", ") + "]"
+		}
+		if got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestOperatorOverloadingErrors(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	struct Money { cents: int }
+	impl Eq for Money {
+		fn eq(other) {
+			return "yes"
+		}
+	}
+	let plain = Money{cents: 1}
+	`)
+
+	exprs, err := interp.Parse(`let bad = plain == Money{cents: 1}`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "Money.eq() must return bool") {
+		t.Errorf("expected eq() result error, got %v", err)
+	}
+
+	// Методы интерфейса проверяются так же, как у пользовательских интерфейсов
+	exprs, err = interp.Parse(`
+	impl Ord for Money {
+		fn compare(other) { return 0 }
+	}
+	`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "method 'cmp' is not implemented") {
+		t.Errorf("expected missing cmp error, got %v", err)
+	}
+}
+
+func TestCheckerOperatorTypes(t *testing.T) {
+	errors := checkProgram(t, vectorProgram+`
+let sum: Vec = Vec{x: 1, y: 2} + Vec{x: 3, y: 4}
+let wrong: int = Vec{x: 1, y: 2} * 2
+impl Display for Vec {
+	fn show() -> string { return "" }
+}
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"variable 'wrong' type error: expected int, got Vec",
+		"impl Display for Vec: method 'toString' is not implemented",
+	})
+}
+
+func TestOperatorOverloadingInBytecode(t *testing.T) {
+	defer ast.ClearInterfaces()
+
+	s := runCompiled(t, vectorProgram+`
+	let sum = Vec{x: 1, y: 2} + Vec{x: 3, y: 4}
+	let text = "sum = ${sum}"
+	let less = Vec{x: 1, y: 1} < sum
+	`)
+
+	text, _ := s.Get("text")
+	if text.String() != "sum = (4, 6)" {
+		t.Errorf("expected formatted sum, got %q", text.String())
+	}
+
+	less, _ := s.Get("less")
+	if !less.Bool() {
+		t.Error("expected Ord comparison in bytecode")
+	}
+}
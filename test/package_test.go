@@ -0,0 +1,147 @@
+package test
+
+import (
+	"archive/zip"
+	"foo_lang/interpreter"
+	"foo_lang/modules"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newPackage создает пакет shop с зависимостями utils (каталог) и json (zip-архив)
+func newPackage(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	writeModule(t, filepath.Join(root, "utils", "foo.mod"), "package utils\nversion 0.3.0\n")
+	writeModule(t, filepath.Join(root, "utils", "index.foo"), `export let NAME = "utils"`)
+	writeModule(t, filepath.Join(root, "utils", "text", "shout.foo"), `export fn shout(s) { return s.toUpper() + "!" }`)
+
+	archive, err := os.Create(filepath.Join(root, "json-1.0.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(archive)
+	w, _ := zw.Create("json-1.0/encode.foo")
+	w.Write([]byte(`export fn encode(v) { return "json:" + v }`))
+	zw.Close()
+	archive.Close()
+
+	writeModule(t, filepath.Join(root, "app", "foo.mod"), `package shop
+version 1.2.0
+
+require utils ../utils // локальный каталог
+require (
+	json ../json-1.0.zip
+	unused ../utils
+)
+`)
+	writeModule(t, filepath.Join(root, "app", "lib", "helper.foo"), `export fn helper() { return "self" }`)
+	writeModule(t, filepath.Join(root, "app", "main.foo"), `
+import { shout } from "utils/text/shout"
+import * as u from "utils"
+import { encode } from "json/encode"
+import { helper } from "shop/lib/helper"
+let result = shout("hi") + " " + u.NAME + " " + encode(1) + " " + helper()
+`)
+	return filepath.Join(root, "app")
+}
+
+func runPackageMain(t *testing.T, app string) (string, error) {
+	t.Helper()
+	interp := interpreter.New()
+	exprs, err := interp.ParseFile(filepath.Join(app, "main.foo"))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err != nil {
+		return "", err
+	}
+	result, _ := interp.Scope().Get("result")
+	return result.String(), nil
+}
+
+func TestPackageVendorAndLock(t *testing.T) {
+	app := newPackage(t)
+
+	// Архив нельзя импортировать напрямую - его нужно распаковать в vendor/
+	if _, err := runPackageMain(t, app); err == nil || !strings.Contains(err.Error(), "run 'foo mod vendor'") {
+		t.Fatalf("expected vendor hint for archive, got %v", err)
+	}
+
+	manifest, err := modules.FindManifest(filepath.Join(app, "lib"))
+	if err != nil || manifest == nil || manifest.Name != "shop" {
+		t.Fatalf("expected shop manifest, got %+v, %v", manifest, err)
+	}
+
+	removed, err := modules.Tidy(manifest)
+	if err != nil {
+		t.Fatalf("tidy: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "unused" {
+		t.Errorf("expected 'unused' to be removed, got %v", removed)
+	}
+	tidied, _ := os.ReadFile(filepath.Join(app, modules.ManifestFile))
+	if want := "package shop\nversion 1.2.0\n\nrequire (\n\tjson ../json-1.0.zip\n\tutils ../utils\n)\n"; string(tidied) != want {
+		t.Errorf("unexpected foo.mod after tidy:\n%s", tidied)
+	}
+
+	deps, err := modules.Vendor(manifest)
+	if err != nil {
+		t.Fatalf("vendor: %v", err)
+	}
+	if len(deps) != 2 || deps[0].Name != "json" || deps[1].Version != "0.3.0" {
+		t.Errorf("unexpected dependencies: %+v", deps)
+	}
+
+	// Хеш распакованного архива совпадает с хешем в foo.lock
+	lock, err := modules.ReadLock(app)
+	if err != nil || len(lock) != 2 {
+		t.Fatalf("expected 2 lock entries, got %v, %v", lock, err)
+	}
+	if hash, _ := modules.HashSource(filepath.Join(app, "vendor", "json")); hash != lock[0].Hash {
+		t.Errorf("vendored json hash %s does not match lock %s", hash, lock[0].Hash)
+	}
+
+	result, err := runPackageMain(t, app)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result != "HI! utils json:1 self" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestPackageLockMismatch(t *testing.T) {
+	app := newPackage(t)
+	manifest, _ := modules.FindManifest(app)
+	if _, err := modules.Vendor(manifest); err != nil {
+		t.Fatalf("vendor: %v", err)
+	}
+
+	writeModule(t, filepath.Join(app, "vendor", "utils", "index.foo"), `export let NAME = "patched"`)
+	if _, err := runPackageMain(t, app); err == nil || !strings.Contains(err.Error(), "package 'utils' does not match") {
+		t.Errorf("expected lock mismatch, got %v", err)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"version 1.0\n":                               "missing 'package' directive",
+		"package a\nrequire b\n":                      ":2: expected 'require <name> <path>'",
+		"package a\nrequire (\n":                      "unterminated require block",
+		"package a\nrequire x ../x\nrequire x ../y\n": ":3: package 'x' is required twice",
+		"package a\nrequire std ../std\n":             "invalid package name 'std'",
+		"package a\nreplace x ../x\n":                 "unknown directive 'replace'",
+	}
+	for content, want := range tests {
+		path := filepath.Join(dir, modules.ManifestFile)
+		writeModule(t, path, content)
+		if _, err := modules.ParseManifest(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected %q, got %v", content, want, err)
+		}
+	}
+}
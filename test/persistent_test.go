@@ -0,0 +1,246 @@
+package test
+
+import (
+	"fmt"
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"foo_lang/value"
+	"strings"
+	"testing"
+)
+
+func TestFreezeAndConst(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		err  string
+	}{
+		"const object": {
+			`const point = {x: 1}
+			point.x = 2`,
+			"cannot assign property 'x' of frozen object",
+		},
+		"nested object": {
+			`let config = freeze({db: {host: "localhost"}})
+			config.db.host = "remote"`,
+			"cannot assign property 'host' of frozen object",
+		},
+		"array element": {
+			`let items = freeze([{n: 1}])
+			let first = items[0]
+			first.n = 2`,
+			"cannot assign property 'n' of frozen object",
+		},
+		"struct method": {
+			`struct Counter { n: int }
+			interface Incrementer { fn inc() -> int }
+			impl Incrementer for Counter {
+				fn inc() -> int {
+					this.n = this.n + 1
+					return this.n
+				}
+			}
+			let c = freeze(Counter{n: 0})
+			c.inc()`,
+			"cannot assign property 'n' of frozen struct Counter",
+		},
+	}
+
+	for name, tt := range tests {
+		interp := interpreter.New()
+		exprs, err := interp.Parse(tt.code)
+		if err == nil {
+			_, err = interp.Run(exprs)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error %q, got %v", name, tt.err, err)
+		}
+	}
+}
+
+func TestCloneAndSharing(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+const original = {n: 1, items: [1, 2]}
+let copy = clone(original)
+copy.n = 2
+let frozen = isFrozen(original)
+let copyFrozen = isFrozen(copy)
+
+let base = [1, 2, 3]
+let a = base.push(4)
+let b = base.push(5)
+
+let shared = {n: 1}
+let list = List().push(shared)
+shared.n = 2
+let stored = list[0].n
+`)
+
+	expected := map[string]string{
+		"frozen":     "true",
+		"copyFrozen": "false",
+		"a":          "[1, 2, 3, 4]",
+		"b":          "[1, 2, 3, 5]",
+		"stored":     "1",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+
+	original, _ := interp.Scope().Get("original")
+	if n, _ := original.Any().(*value.Object).Get("n"); n.Int64() != 1 {
+		t.Errorf("clone changed the original: n = %d", n.Int64())
+	}
+}
+
+func TestPersistentCollections(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+let empty = List()
+let one = empty.push(1)
+let two = one.push(2)
+let changed = two.set(0, 10)
+let popped = two.pop()
+let doubled = List([1, 2, 3]).map(fn(x) => x * 2)
+let odd = List([1, 2, 3]).filter(fn(x) => x % 2 == 1)
+
+let m = Map({a: 1})
+let m2 = m.set("b", 2)
+let m3 = m2.delete("a")
+let sum = 0
+for key, val in m2 {
+	sum = sum + val
+}
+`)
+
+	expected := map[string]string{
+		"empty":   "List[]",
+		"one":     "List[1]",
+		"two":     "List[1, 2]",
+		"changed": "List[10, 2]",
+		"popped":  "List[1]",
+		"doubled": "List[2, 4, 6]",
+		"odd":     "List[1, 3]",
+		"m":       "Map{a: 1}",
+		"m2":      "Map{a: 1, b: 2}",
+		"m3":      "Map{b: 2}",
+		"sum":     "3",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+
+	m, _ := interp.Scope().Get("m")
+	if !ast.IsFrozen(m) {
+		t.Error("expected Map to be frozen")
+	}
+	exprs, err := interp.Parse(`m.a = 2`)
+	if err == nil {
+		_, err = interp.Run(exprs)
+	}
+	if err == nil || !strings.Contains(err.Error(), "frozen") {
+		t.Errorf("expected frozen Map assignment error, got %v", err)
+	}
+}
+
+func TestPersistentListSharing(t *testing.T) {
+	const size = 5000
+	versions := []*value.List{value.NewList()}
+	for i := 0; i < size; i++ {
+		versions = append(versions, versions[i].Push(i))
+	}
+
+	// Каждая версия видит только свои элементы
+	for _, n := range []int{0, 1, 32, 33, 1024, 1056, size} {
+		list := versions[n]
+		if list.Len() != n {
+			t.Fatalf("version %d: expected length %d, got %d", n, n, list.Len())
+		}
+		for i := 0; i < n; i++ {
+			if item, _ := list.Get(i); item != i {
+				t.Fatalf("version %d: item %d = %v", n, i, item)
+			}
+		}
+	}
+
+	full := versions[size]
+	updated := full
+	for i := 0; i < size; i += 7 {
+		updated, _ = updated.Set(i, -i)
+	}
+	for i := 0; i < size; i++ {
+		want := i
+		if i%7 == 0 {
+			want = -i
+		}
+		if item, _ := updated.Get(i); item != want {
+			t.Fatalf("updated item %d = %v, want %d", i, item, want)
+		}
+		if item, _ := full.Get(i); item != i {
+			t.Fatalf("set changed the original at %d: %v", i, item)
+		}
+	}
+
+	list := full
+	for n := size; n > 0; n-- {
+		if last, _ := list.Get(n - 1); last != n-1 {
+			t.Fatalf("pop: last item of length %d is %v", n, last)
+		}
+		list, _ = list.Pop()
+		if list.Len() != n-1 {
+			t.Fatalf("pop: expected length %d, got %d", n-1, list.Len())
+		}
+	}
+	if _, ok := list.Pop(); ok {
+		t.Error("expected pop on empty list to fail")
+	}
+	if full.Len() != size {
+		t.Errorf("pop changed the original length: %d", full.Len())
+	}
+}
+
+func TestPersistentMapSharing(t *testing.T) {
+	const size = 3000
+	m := value.NewMap()
+	for i := 0; i < size; i++ {
+		m = m.Set(fmt.Sprintf("key%d", i), i)
+	}
+	snapshot := m
+
+	for i := 0; i < size; i += 2 {
+		m = m.Delete(fmt.Sprintf("key%d", i))
+	}
+	m = m.Set("key1", "changed")
+
+	if snapshot.Len() != size || m.Len() != size/2 {
+		t.Fatalf("expected lengths %d and %d, got %d and %d", size, size/2, snapshot.Len(), m.Len())
+	}
+	for i := 0; i < size; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if item, ok := snapshot.Get(key); !ok || item != i {
+			t.Fatalf("snapshot %s = %v", key, item)
+		}
+		item, ok := m.Get(key)
+		switch {
+		case i == 1:
+			if item != "changed" {
+				t.Fatalf("expected key1 to change, got %v", item)
+			}
+		case i%2 == 0:
+			if ok {
+				t.Fatalf("expected %s to be deleted", key)
+			}
+		case item != i:
+			t.Fatalf("%s = %v", key, item)
+		}
+	}
+	if len(m.Keys()) != m.Len() {
+		t.Errorf("expected %d keys, got %d", m.Len(), len(m.Keys()))
+	}
+}
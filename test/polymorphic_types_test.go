@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/parser"
 	"io"
@@ -67,7 +68,7 @@ func TestPolymorphicTypeMethods(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -118,7 +119,7 @@ func TestTypeConversions(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -212,7 +213,7 @@ func TestPolymorphicMacros(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -270,7 +271,7 @@ func TestTypeConversionErrors(t *testing.T) {
 			InitTestEnvironment()
 			exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 			for _, expr := range exprs {
-				expr.Eval()
+				expr.Eval(ast.GlobalRuntime)
 			}
 		})
 	}
@@ -308,7 +309,7 @@ func TestUniversalTypeAnalysis(t *testing.T) {
 				InitTestEnvironment()
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 			expected := strings.TrimSpace(tt.output)
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"foo_lang/parser"
+	"strings"
+	"testing"
+)
+
+const propagateProgram = `
+fn dec(n) {
+	if n < 0 {
+		return Err("negative: " + n)
+	}
+	return Ok(n - 1)
+}
+
+fn decTwice(n) {
+	let once = dec(n)?
+	return Ok(dec(once)?)
+}
+
+let good = decTwice(4)
+let bad = decTwice(0)
+`
+
+func TestPropagateOperator(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, propagateProgram+`
+	let ternary = 1 > 0 ? "yes" : "no"
+	`)
+
+	good, _ := interp.Scope().Get("good")
+	if res, ok := good.Any().(*ast.ResultValue); !ok || !res.IsOk() || res.Unwrap().Int64() != 2 {
+		t.Errorf("expected Ok(2), got %v", ast.FormatValue(good.Any()))
+	}
+
+	bad, _ := interp.Scope().Get("bad")
+	if res, ok := bad.Any().(*ast.ResultValue); !ok || !res.IsErr() || res.GetValue().String() != "negative: -1" {
+		t.Errorf("expected Err(negative: -1), got %v", ast.FormatValue(bad.Any()))
+	}
+
+	// Тернарный оператор по-прежнему разбирается
+	ternary, _ := interp.Scope().Get("ternary")
+	if ternary.String() != "yes" {
+		t.Errorf("expected ternary 'yes', got %q", ternary.String())
+	}
+}
+
+func TestPropagateBeforeBinaryOperator(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+	fn ops(r, x, a, b) {
+		let doubled = r? * 2
+		let next = r? + 1
+		let prev = r? - 1
+		let isOne = x? == 1
+		let both = a? && b?
+		return Ok(doubled + " " + next + " " + prev + " " + isOne + " " + both)
+	}
+	let all = ops(Ok(5), Ok(1), Ok(true), Ok(false)).unwrap()
+	let failed = ops(Err("bad"), Ok(1), Ok(true), Ok(true)).unwrapErr()
+	let sign = 1 > 0 ? -1 : 1
+	`)
+
+	expected := map[string]string{
+		"all":    "10 6 4 true false",
+		"failed": "bad",
+		"sign":   "-1",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+
+	// ? относится к левому операнду, а не начинает тернарный оператор
+	for _, source := range []string{"r? * 2", "r? + 1", "r? - 1", "x? == 1", "a? && b?"} {
+		exprs := parser.NewParser(source).ParseWithoutScopeInit()
+		if len(exprs) != 1 {
+			t.Errorf("%s: expected one expression, got %d", source, len(exprs))
+			continue
+		}
+		binary, ok := exprs[0].(*ast.BinaryExpr)
+		if !ok {
+			t.Errorf("%s: expected binary expression, got %T", source, exprs[0])
+			continue
+		}
+		if _, ok := binary.Left.(*ast.PropagateExpr); !ok {
+			t.Errorf("%s: expected ? on the left operand, got %T", source, binary.Left)
+		}
+	}
+}
+
+func TestPropagateOperatorInBytecode(t *testing.T) {
+	s := runCompiled(t, propagateProgram)
+
+	bad, _ := s.Get("bad")
+	if res, ok := bad.Any().(*ast.ResultValue); !ok || !res.IsErr() {
+		t.Errorf("expected Err, got %v", ast.FormatValue(bad.Any()))
+	}
+
+	good, _ := s.Get("good")
+	if res, ok := good.Any().(*ast.ResultValue); !ok || !res.IsOk() || res.Unwrap().Int64() != 2 {
+		t.Errorf("expected Ok(2), got %v", ast.FormatValue(good.Any()))
+	}
+}
+
+func TestPropagateOperatorErrors(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(`let x = Err("top")?`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "outside of a function") {
+		t.Errorf("expected error for ? outside of a function, got %v", err)
+	}
+
+	exprs, err = interp.Parse(`
+	fn f() {
+		let n = 42?
+		return n
+	}
+	f()
+	`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "operator ? expects Result") {
+		t.Errorf("expected type error for ? on int, got %v", err)
+	}
+}
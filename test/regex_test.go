@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/builtin"
 	"foo_lang/parser"
@@ -19,7 +20,7 @@ func TestRegexMatchFunctions(t *testing.T) {
 		{
 			name: "simple_match_true",
 			code: `
-				let result = regexMatch("h[aeiou]llo", "hello world")
+				let result = regexMatch("h[aeiou]llo", "hello world").unwrap()
 				println(result.toString())
 			`,
 			expected: "true",
@@ -27,7 +28,7 @@ func TestRegexMatchFunctions(t *testing.T) {
 		{
 			name: "simple_match_false",
 			code: `
-				let result = regexMatch("h[aeiou]llo", "hi world")
+				let result = regexMatch("h[aeiou]llo", "hi world").unwrap()
 				println(result.toString())
 			`,
 			expected: "false",
@@ -35,7 +36,7 @@ func TestRegexMatchFunctions(t *testing.T) {
 		{
 			name: "number_pattern",
 			code: `
-				let result = regexMatch("\\d+", "price is 100 dollars")
+				let result = regexMatch("\\d+", "price is 100 dollars").unwrap()
 				println(result.toString())
 			`,
 			expected: "true",
@@ -43,7 +44,7 @@ func TestRegexMatchFunctions(t *testing.T) {
 		{
 			name: "email_validation",
 			code: `
-				let result = regexMatch("^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$", "test@example.com")
+				let result = regexMatch("^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$", "test@example.com").unwrap()
 				println(result.toString())
 			`,
 			expected: "true",
@@ -51,7 +52,7 @@ func TestRegexMatchFunctions(t *testing.T) {
 		{
 			name: "invalid_email",
 			code: `
-				let result = regexMatch("^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$", "invalid-email")
+				let result = regexMatch("^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$", "invalid-email").unwrap()
 				println(result.toString())
 			`,
 			expected: "false",
@@ -69,7 +70,7 @@ func TestRegexMatchFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -89,7 +90,7 @@ func TestRegexFindFunctions(t *testing.T) {
 		{
 			name: "find_first_word",
 			code: `
-				let result = regexFind("\\w+", "hello world test")
+				let result = regexFind("\\w+", "hello world test").unwrap()
 				println(result)
 			`,
 			expected: "hello",
@@ -97,7 +98,7 @@ func TestRegexFindFunctions(t *testing.T) {
 		{
 			name: "find_number",
 			code: `
-				let result = regexFind("\\d+", "price is 100 dollars and 50 cents")
+				let result = regexFind("\\d+", "price is 100 dollars and 50 cents").unwrap()
 				println(result)
 			`,
 			expected: "100",
@@ -105,7 +106,7 @@ func TestRegexFindFunctions(t *testing.T) {
 		{
 			name: "no_match",
 			code: `
-				let result = regexFind("\\d+", "no numbers here")
+				let result = regexFind("\\d+", "no numbers here").unwrap()
 				println(result)
 			`,
 			expected: "",
@@ -113,7 +114,7 @@ func TestRegexFindFunctions(t *testing.T) {
 		{
 			name: "find_email",
 			code: `
-				let result = regexFind("[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}", "Contact: test@example.com or admin@site.org")
+				let result = regexFind("[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}", "Contact: test@example.com or admin@site.org").unwrap()
 				println(result)
 			`,
 			expected: "test@example.com",
@@ -131,7 +132,7 @@ func TestRegexFindFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -151,7 +152,7 @@ func TestRegexFindAllFunctions(t *testing.T) {
 		{
 			name: "find_all_numbers",
 			code: `
-				let results = regexFindAll("\\d+", "I have 5 apples, 10 oranges, and 3 bananas")
+				let results = regexFindAll("\\d+", "I have 5 apples, 10 oranges, and 3 bananas").unwrap()
 				println("found_numbers")
 			`,
 			verify: func(result string) bool {
@@ -161,7 +162,7 @@ func TestRegexFindAllFunctions(t *testing.T) {
 		{
 			name: "find_all_words",
 			code: `
-				let results = regexFindAll("\\w+", "hello world test")
+				let results = regexFindAll("\\w+", "hello world test").unwrap()
 				println("found_words")
 			`,
 			verify: func(result string) bool {
@@ -171,7 +172,7 @@ func TestRegexFindAllFunctions(t *testing.T) {
 		{
 			name: "find_all_with_limit",
 			code: `
-				let results = regexFindAll("\\d", "123456789", 3)
+				let results = regexFindAll("\\d", "123456789", 3).unwrap()
 				println("found_with_limit")
 			`,
 			verify: func(result string) bool {
@@ -191,7 +192,7 @@ func TestRegexFindAllFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -211,7 +212,7 @@ func TestRegexReplaceFunctions(t *testing.T) {
 		{
 			name: "replace_first_number",
 			code: `
-				let result = regexReplace("\\d+", "I have 5 apples and 10 oranges", "many")
+				let result = regexReplace("\\d+", "I have 5 apples and 10 oranges", "many").unwrap()
 				println(result)
 			`,
 			expected: "I have many apples and 10 oranges",
@@ -219,7 +220,7 @@ func TestRegexReplaceFunctions(t *testing.T) {
 		{
 			name: "replace_all_numbers",
 			code: `
-				let result = regexReplaceAll("\\d+", "I have 5 apples and 10 oranges", "many")
+				let result = regexReplaceAll("\\d+", "I have 5 apples and 10 oranges", "many").unwrap()
 				println(result)
 			`,
 			expected: "I have many apples and many oranges",
@@ -227,7 +228,7 @@ func TestRegexReplaceFunctions(t *testing.T) {
 		{
 			name: "replace_word",
 			code: `
-				let result = regexReplaceAll("hello", "hello world, hello universe", "hi")
+				let result = regexReplaceAll("hello", "hello world, hello universe", "hi").unwrap()
 				println(result)
 			`,
 			expected: "hi world, hi universe",
@@ -235,7 +236,7 @@ func TestRegexReplaceFunctions(t *testing.T) {
 		{
 			name: "replace_with_groups",
 			code: `
-				let result = regexReplaceAll("(\\w+)@(\\w+)", "email: test@example", "[$1 at $2]")
+				let result = regexReplaceAll("(\\w+)@(\\w+)", "email: test@example", "[$1 at $2]").unwrap()
 				println(result)
 			`,
 			expected: "email: [test at example]",
@@ -253,7 +254,7 @@ func TestRegexReplaceFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -273,7 +274,7 @@ func TestRegexSplitFunctions(t *testing.T) {
 		{
 			name: "split_by_comma",
 			code: `
-				let parts = regexSplit(",\\s*", "apple, banana, cherry")
+				let parts = regexSplit(",\\s*", "apple, banana, cherry").unwrap()
 				println("split_by_comma")
 			`,
 			verify: func(result string) bool {
@@ -283,7 +284,7 @@ func TestRegexSplitFunctions(t *testing.T) {
 		{
 			name: "split_by_whitespace",
 			code: `
-				let parts = regexSplit("\\s+", "hello   world    test")
+				let parts = regexSplit("\\s+", "hello   world    test").unwrap()
 				println("split_by_whitespace")
 			`,
 			verify: func(result string) bool {
@@ -293,7 +294,7 @@ func TestRegexSplitFunctions(t *testing.T) {
 		{
 			name: "split_with_limit",
 			code: `
-				let parts = regexSplit(",", "a,b,c,d,e", 3)
+				let parts = regexSplit(",", "a,b,c,d,e", 3).unwrap()
 				println("split_with_limit")
 			`,
 			verify: func(result string) bool {
@@ -323,7 +324,7 @@ func TestRegexSplitFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -343,7 +344,7 @@ func TestRegexGroupsFunctions(t *testing.T) {
 		{
 			name: "extract_email_parts",
 			code: `
-				let groups = regexGroups("([a-zA-Z0-9._%+-]+)@([a-zA-Z0-9.-]+)\\.([a-zA-Z]{2,})", "test@example.com")
+				let groups = regexGroups("([a-zA-Z0-9._%+-]+)@([a-zA-Z0-9.-]+)\\.([a-zA-Z]{2,})", "test@example.com").unwrap()
 				println("extract_email_parts")
 			`,
 			verify: func(result string) bool {
@@ -353,7 +354,7 @@ func TestRegexGroupsFunctions(t *testing.T) {
 		{
 			name: "extract_date_parts",
 			code: `
-				let groups = regexGroups("(\\d{4})-(\\d{2})-(\\d{2})", "Today is 2024-01-15")
+				let groups = regexGroups("(\\d{4})-(\\d{2})-(\\d{2})", "Today is 2024-01-15").unwrap()
 				println("extract_date_parts")
 			`,
 			verify: func(result string) bool {
@@ -363,7 +364,7 @@ func TestRegexGroupsFunctions(t *testing.T) {
 		{
 			name: "no_match",
 			code: `
-				let groups = regexGroups("(\\d+)", "no numbers here")
+				let groups = regexGroups("(\\d+)", "no numbers here").unwrap()
 				println("empty")
 			`,
 			verify: func(result string) bool {
@@ -383,7 +384,7 @@ func TestRegexGroupsFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -427,7 +428,7 @@ func TestRegexUtilityFunctions(t *testing.T) {
 		{
 			name: "count_matches",
 			code: `
-				let result = regexCount("\\d", "I have 5 apples and 10 oranges")
+				let result = regexCount("\\d", "I have 5 apples and 10 oranges").unwrap()
 				println(result.toString())
 			`,
 			expected: "3", // 5, 1, 0 (три цифры)
@@ -445,7 +446,7 @@ func TestRegexUtilityFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -470,13 +471,16 @@ func TestRegexErrorHandling(t *testing.T) {
 				println(result)
 			`,
 			wantErr:  true,
-			contains: "Error compiling regex pattern",
+			contains: "Err(ValueError: invalid regex pattern '[a-zA-Z'",
 		},
 		{
 			name: "wrong_argument_count",
 			code: `
-				let result = regexMatch("test")
-				println(result)
+				try {
+					regexMatch("test")
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "requires 2 arguments",
@@ -484,8 +488,11 @@ func TestRegexErrorHandling(t *testing.T) {
 		{
 			name: "non_string_pattern",
 			code: `
-				let result = regexMatch(123, "test")
-				println(result)
+				try {
+					regexMatch(123, "test")
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "must be string",
@@ -503,7 +510,7 @@ func TestRegexErrorHandling(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -523,7 +530,7 @@ func TestRegexPracticalExamples(t *testing.T) {
 		{
 			name: "extract_urls",
 			code: `
-				let urls = regexFindAll("https?://[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}", "Visit https://example.com or http://test.org")
+				let urls = regexFindAll("https?://[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}", "Visit https://example.com or http://test.org").unwrap()
 				println("extract_urls")
 			`,
 			verify: func(result string) bool {
@@ -533,7 +540,7 @@ func TestRegexPracticalExamples(t *testing.T) {
 		{
 			name: "validate_phone_number",
 			code: `
-				let isValid = regexMatch("^\\+?[0-9]{1,3}[0-9\\s\\-\\(\\)]{7,14}[0-9]$", "+1 (555) 123-4567")
+				let isValid = regexMatch("^\\+?[0-9]{1,3}[0-9\\s\\-\\(\\)]{7,14}[0-9]$", "+1 (555) 123-4567").unwrap()
 				println(isValid.toString())
 			`,
 			verify: func(result string) bool {
@@ -543,7 +550,7 @@ func TestRegexPracticalExamples(t *testing.T) {
 		{
 			name: "clean_html_tags",
 			code: `
-				let clean = regexReplaceAll("<[^>]*>", "<p>Hello <b>world</b>!</p>", "")
+				let clean = regexReplaceAll("<[^>]*>", "<p>Hello <b>world</b>!</p>", "").unwrap()
 				println(clean)
 			`,
 			verify: func(result string) bool {
@@ -553,7 +560,7 @@ func TestRegexPracticalExamples(t *testing.T) {
 		{
 			name: "extract_hashtags",
 			code: `
-				let hashtags = regexFindAll("#\\w+", "Check out #golang and #programming tutorials")
+				let hashtags = regexFindAll("#\\w+", "Check out #golang and #programming tutorials").unwrap()
 				println("extract_hashtags")
 			`,
 			verify: func(result string) bool {
@@ -573,7 +580,7 @@ func TestRegexPracticalExamples(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"foo_lang/builtin"
+	"foo_lang/interpreter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// touchLater переписывает модуль и сдвигает время изменения, чтобы
+// ReloadChanged увидел изменение независимо от точности времени файловой системы
+func touchLater(t *testing.T, path, code string, step int) {
+	t.Helper()
+	writeModule(t, path, code)
+	later := time.Now().Add(time.Duration(step) * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHotReload(t *testing.T) {
+	dir := t.TempDir()
+	handlers := filepath.Join(dir, "handlers.foo")
+	writeModule(t, handlers, `
+let jobs = newChannel(10)
+let lock = newMutex("reload_test")
+export let queue = jobs
+export fn greet(name) { return "hello " + name }
+export fn pending() { return len(jobs) + " " + lock }
+httpCreateServer()
+httpRoute("GET", "/reload_test", greet)
+`)
+	writeModule(t, filepath.Join(dir, "main.foo"), `
+import { greet, queue, pending } from "./handlers.foo"
+import * as h from "./handlers.foo"
+send(queue, "job")
+`)
+
+	interp, err := runFile(t, filepath.Join(dir, "main.foo"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	call := func(code string) string {
+		t.Helper()
+		exprs, err := interp.Parse(code)
+		if err != nil {
+			t.Fatalf("parse %s: %v", code, err)
+		}
+		result, err := interp.Run(exprs)
+		if err != nil {
+			t.Fatalf("run %s: %v", code, err)
+		}
+		return result.String()
+	}
+
+	touchLater(t, handlers, `
+let jobs = newChannel(10)
+let lock = newMutex("reload_test")
+export let queue = jobs
+export fn greet(name) { return "hi " + name }
+export fn pending() { return len(jobs) + " " + lock }
+export fn added() { return "new" }
+httpRoute("POST", "/reload_test", greet)
+`, 1)
+	reloaded, errs := interp.ReloadChanged()
+	if len(errs) > 0 || len(reloaded) != 1 || reloaded[0] != handlers {
+		t.Fatalf("expected handlers.foo to reload, got %v, %v", reloaded, errs)
+	}
+
+	// Импортированные функции подменены на месте, канал и мьютекс сохранены
+	if got := call(`greet("ann") + ", " + h.greet("bob")`); got != "hi ann, hi bob" {
+		t.Errorf("expected swapped exports, got %q", got)
+	}
+	if got := call(`pending()`); got != "1 reload_test" {
+		t.Errorf("expected channel and mutex to survive reload, got %q", got)
+	}
+	module, _ := interp.Runtime().Modules.Get(handlers)
+	if _, ok := module.Exports.Get("added"); !ok {
+		t.Errorf("expected new export after reload")
+	}
+
+	// Ошибка в новом коде оставляет прежний модуль
+	touchLater(t, handlers, `export fn greet(name) { return ) }`, 2)
+	if _, errs := interp.ReloadChanged(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "Parse error") {
+		t.Fatalf("expected parse error on reload, got %v", errs)
+	}
+	if got := call(`greet("ann")`); got != "hi ann" {
+		t.Errorf("expected old code after failed reload, got %q", got)
+	}
+	if reloaded, errs := interp.ReloadChanged(); len(reloaded)+len(errs) != 0 {
+		t.Errorf("failed file should not reload again until it changes, got %v, %v", reloaded, errs)
+	}
+}
+
+func TestReattachOnlyAffectsReloadingInterpreter(t *testing.T) {
+	reloading := interpreter.New()
+	other := interpreter.New()
+
+	runInterpreter(t, other, `let db = newMutex("db")`)
+	exprs, err := other.Parse(`let again = newMutex("db")`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	// Пока перезагружается другой интерпретатор, повторный newMutex остается ошибкой
+	builtin.StateOf(reloading.Runtime()).Reattach(func() {
+		_, err = other.Run(exprs)
+	})
+	if err == nil || !strings.Contains(err.Error(), "mutex 'db' already exists") {
+		t.Errorf("expected duplicate mutex error, got %v", err)
+	}
+}
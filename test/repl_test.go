@@ -0,0 +1,96 @@
+package test
+
+import (
+	"bytes"
+	"foo_lang/repl"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// feedREPL передает строки в REPL и возвращает весь вывод
+func feedREPL(r *repl.REPL, out *bytes.Buffer, lines ...string) string {
+	out.Reset()
+	for _, line := range lines {
+		r.Feed(line)
+	}
+	return out.String()
+}
+
+func TestREPLPersistentScope(t *testing.T) {
+	var out bytes.Buffer
+	r := repl.New(&out)
+
+	feedREPL(r, &out, "let x = 40")
+	output := feedREPL(r, &out, "x + 2")
+
+	if !strings.Contains(output, "Int: 42") {
+		t.Errorf("expected 'Int: 42', got %q", output)
+	}
+}
+
+func TestREPLMultilineInput(t *testing.T) {
+	var out bytes.Buffer
+	r := repl.New(&out)
+
+	feedREPL(r, &out, "fn add(a, b) {")
+	if r.Prompt() != "...> " {
+		t.Errorf("expected continuation prompt, got %q", r.Prompt())
+	}
+
+	feedREPL(r, &out, "  return a + b", "}")
+	if r.Prompt() != "foo> " {
+		t.Errorf("expected main prompt, got %q", r.Prompt())
+	}
+
+	output := feedREPL(r, &out, "add(1, 2)")
+	if !strings.Contains(output, "Int: 3") {
+		t.Errorf("expected 'Int: 3', got %q", output)
+	}
+}
+
+func TestREPLIsIncomplete(t *testing.T) {
+	cases := map[string]bool{
+		"let x = 1":         false,
+		"fn f() {":          true,
+		"let a = [1, 2,":    true,
+		"if x { (1 + 2) }":  false,
+		`let s = "{"`:       false,
+		"match x {\n1 => 2": true,
+	}
+
+	for code, expected := range cases {
+		if repl.IsIncomplete(code) != expected {
+			t.Errorf("IsIncomplete(%q): expected %v", code, expected)
+		}
+	}
+}
+
+func TestREPLCommands(t *testing.T) {
+	var out bytes.Buffer
+	r := repl.New(&out)
+
+	if output := feedREPL(r, &out, ":type [1, 2]"); strings.TrimSpace(output) != "array" {
+		t.Errorf(":type: expected array, got %q", output)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "lib.foo")
+	if err := os.WriteFile(filePath, []byte("let loaded = 7\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	feedREPL(r, &out, ":load "+filePath)
+	if output := feedREPL(r, &out, "loaded"); !strings.Contains(output, "Int: 7") {
+		t.Errorf("after :load expected 'Int: 7', got %q", output)
+	}
+
+	feedREPL(r, &out, ":reset")
+	if output := feedREPL(r, &out, "loaded"); !strings.Contains(output, "Error") {
+		t.Errorf("after :reset expected error, got %q", output)
+	}
+
+	if r.Feed(":quit") {
+		t.Error(":quit should stop the REPL")
+	}
+}
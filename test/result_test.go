@@ -15,7 +15,7 @@ func TestResultOk(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("result")
@@ -51,7 +51,7 @@ func TestResultErr(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	val, ok := scope.GlobalScope.Get("result")
@@ -96,7 +96,7 @@ func TestResultMethods(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	tests := []struct {
@@ -150,7 +150,7 @@ func TestResultUnwrapPanic(t *testing.T) {
 	}()
 
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 }
 
@@ -174,7 +174,7 @@ func TestResultInFunction(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test successful division
@@ -233,7 +233,7 @@ func TestResultChaining(t *testing.T) {
 	
 	exprs := parser.NewParser(code).ParseWithoutScopeInit()
 	for _, expr := range exprs {
-		expr.Eval()
+		expr.Eval(ast.GlobalRuntime)
 	}
 
 	// Test successful processing
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"fmt"
+	"foo_lang/interpreter"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// runInterpreter разбирает и выполняет код в отдельном интерпретаторе
+func runInterpreter(t *testing.T, interp *interpreter.Interpreter, code string) {
+	t.Helper()
+
+	exprs, err := interp.Parse(code)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if _, err := interp.Run(exprs); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+}
+
+func TestInterpretersAreIsolated(t *testing.T) {
+	first := interpreter.New()
+	second := interpreter.New()
+
+	runInterpreter(t, first, `
+	let name = "first"
+	fn id() { return 1 }
+	let result = id()
+	`)
+	runInterpreter(t, second, `
+	let name = "second"
+	fn id() { return 2 }
+	let result = id()
+	`)
+
+	for interp, expected := range map[*interpreter.Interpreter]int64{first: 1, second: 2} {
+		result, ok := interp.Scope().Get("result")
+		if !ok {
+			t.Fatal("result not found")
+		}
+		if result.Int64() != expected {
+			t.Errorf("result: expected %d, got %v", expected, result.Any())
+		}
+	}
+
+	name, _ := first.Scope().Get("name")
+	if name.String() != "first" {
+		t.Errorf("name: expected first, got %v", name.Any())
+	}
+}
+
+func TestInterpretersRunConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	errors := make(chan error, 8)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			interp := interpreter.New()
+			exprs, err := interp.Parse(fmt.Sprintf(`
+			let base = %d
+			fn compute(x) { return x + base }
+			let total = 0
+			for let i = 0; i < 100; i++ {
+				total = total + compute(i)
+			}
+			`, n))
+			if err != nil {
+				errors <- err
+				return
+			}
+			if _, err := interp.Run(exprs); err != nil {
+				errors <- err
+				return
+			}
+
+			total, _ := interp.Scope().Get("total")
+			expected := int64(4950 + 100*n)
+			if total.Int64() != expected {
+				errors <- fmt.Errorf("interpreter %d: expected %d, got %v", n, expected, total.Any())
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errors)
+
+	for err := range errors {
+		t.Error(err)
+	}
+}
+
+func TestInterpreterExtensionsAreIsolated(t *testing.T) {
+	first := interpreter.New()
+	second := interpreter.New()
+
+	runInterpreter(t, first, `
+	extension string {
+		fn shout() -> string {
+			return this + "!"
+		}
+	}
+	let loud = "hey".shout()
+	`)
+
+	loud, _ := first.Scope().Get("loud")
+	if loud.String() != "hey!" {
+		t.Errorf("loud: expected hey!, got %v", loud.Any())
+	}
+
+	exprs, err := second.Parse(`let loud = "hey".shout()`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := second.Run(exprs); err == nil {
+		t.Error("expected extension method to be unknown in another interpreter")
+	}
+}
+
+func TestInterpreterBuiltinStateIsolated(t *testing.T) {
+	first := interpreter.New()
+	second := interpreter.New()
+
+	// Одинаковые имена примитивов в разных интерпретаторах не конфликтуют
+	runInterpreter(t, first, `
+	let db = newMutex("db")
+	let hits = newAtomic(1, "hits")
+	httpCreateServer()
+	httpRoute("GET", "/ping", fn(req) => "pong")
+	`)
+	runInterpreter(t, second, `
+	let db = newMutex("db")
+	let hits = newAtomic(10, "hits")
+	let value = atomicGet("hits")
+	`)
+
+	value, _ := second.Scope().Get("value")
+	if value.Int64() != 10 {
+		t.Errorf("value: expected 10, got %v", value.Any())
+	}
+
+	// Сервер, созданный первым интерпретатором, второму не виден
+	exprs, err := second.Parse(`httpStartServer(0)`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := second.Run(exprs); err == nil || !strings.Contains(err.Error(), "no server created") {
+		t.Errorf("expected 'no server created' error, got %v", err)
+	}
+}
+
+func TestInterpreterRunReturnsError(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(`let x = undefinedVariable + 1`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	_, err = interp.Run(exprs)
+	if err == nil {
+		t.Fatal("expected runtime error")
+	}
+	if !strings.Contains(err.Error(), "undefinedVariable") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"foo_lang/ast"
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+func TestObjectKeyOrder(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+let obj = {zeta: 1, alpha: 2, mid: {b: 1, a: 2}}
+obj.beta = 3
+let keys = ""
+for key in obj {
+	keys = keys + key + ","
+}
+let json = jsonStringify(obj)
+let text = "${obj}"
+`)
+
+	expected := map[string]string{
+		"keys": "zeta,alpha,mid,beta,",
+		"json": `{"zeta":1,"alpha":2,"mid":{"b":1,"a":2},"beta":3}`,
+		"text": "{zeta: 1, alpha: 2, mid: {b: 1, a: 2}, beta: 3}",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := val.String(); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestSetsAndTuples(t *testing.T) {
+	interp := interpreter.New()
+	runInterpreter(t, interp, `
+let s = #{1, 2, 3, 2}
+let union = s.union(#{3, 4})
+let common = s.intersection(#{2.0, 3, 9})
+let diff = s.difference(#{1})
+let hasTwo = s.has(2)
+s.add((1, "a")).add(5)
+let hasTuple = s.has((1, "a"))
+let fromArray = Set([3, 3, 4])
+let sameSets = #{1, 2} == #{2, 1}
+
+let t = (1, "a", true)
+let second = t[1]
+let size = t.length()
+let single = (5,)
+let empty = ()
+let grouped = (1 + 2) * 3
+let sameTuples = (1, 2) == (1, 2.0)
+let differentTuples = (1, 2) == (2, 1)
+let x, y = (10, "ten")
+let typed: (string, int) = ("Bob", 30)
+let sameStrings = "a" == "b"
+`)
+
+	expected := map[string]string{
+		"union":           "#{1, 2, 3, 4}",
+		"common":          "#{2, 3}",
+		"diff":            "#{2, 3}",
+		"hasTwo":          "true",
+		"s":               "#{1, 2, 3, (1, a), 5}",
+		"hasTuple":        "true",
+		"fromArray":       "#{3, 4}",
+		"sameSets":        "true",
+		"t":               "(1, a, true)",
+		"second":          "a",
+		"size":            "3",
+		"single":          "(5,)",
+		"empty":           "()",
+		"grouped":         "9",
+		"sameTuples":      "true",
+		"differentTuples": "false",
+		"x":               "10",
+		"y":               "ten",
+		"typed":           "(Bob, 30)",
+		"sameStrings":     "false",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := ast.FormatValue(val.Any()); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestSetAndTupleErrors(t *testing.T) {
+	tests := map[string]struct {
+		code string
+		err  string
+	}{
+		"unhashable element": {`let s = #{[1, 2]}`, "set element must be a number, string, bool, null or tuple, got array"},
+		"tuple bounds": {`let t = (1, 2)
+		t[2]`, "tuple index out of bounds"},
+		"tuple annotation": {`let t: (string, int) = ("a", "b")`, "tuple element 1"},
+		"tuple length":     {`let t: (string, int) = ("a",)`, "tuple length mismatch"},
+		"frozen set": {`let s = freeze(#{1})
+		s.add(2)`, "cannot add to frozen set"},
+		"set argument": {`#{1}.union([2])`, "Set.union() expects a set, got array"},
+	}
+
+	for name, tt := range tests {
+		interp := interpreter.New()
+		exprs, err := interp.Parse(tt.code)
+		if err == nil {
+			_, err = interp.Run(exprs)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error %q, got %v", name, tt.err, err)
+		}
+	}
+}
+
+func TestCheckerTuples(t *testing.T) {
+	errors := checkProgram(t, `
+let ok: (string, int) = ("a", 1)
+let legacy: (string, int) = ["a", 1]
+let bad: (int, int) = ("a", 1)
+let s: set = #{1}
+let wrong: set = (1, 2)
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:4:1: variable 'bad' type error: expected (int,int), got (string,int)",
+		"<input>:6:1: variable 'wrong' type error: expected set, got (int,int)",
+	})
+}
@@ -0,0 +1,230 @@
+package test
+
+import (
+	"errors"
+	"foo_lang/ast"
+	"foo_lang/bytecode"
+	"foo_lang/interpreter"
+	"foo_lang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNodePositions(t *testing.T) {
+	code := "let x = 1\n\nfn add(a, b) {\n    return a + b\n}\nlet y = add(x, 2)"
+
+	exprs := parser.NewParserWithFile(code, "pos.foo").WithRuntime(interpreter.New().Runtime()).ParseWithoutScopeInit()
+	if len(exprs) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(exprs))
+	}
+
+	expected := []ast.Position{
+		{File: "pos.foo", Line: 1, Col: 1},
+		{File: "pos.foo", Line: 3, Col: 1},
+		{File: "pos.foo", Line: 6, Col: 1},
+	}
+
+	for i, expr := range exprs {
+		if pos := ast.PositionOf(expr); pos != expected[i] {
+			t.Errorf("statement %d: expected %s, got %s", i, expected[i], pos)
+		}
+	}
+
+	// Вложенный вызов add(x, 2) начинается в колонке 9
+	let, ok := exprs[2].(*ast.LetExpr)
+	if !ok {
+		t.Fatalf("expected LetExpr, got %T", exprs[2])
+	}
+	call := let.GetExpr()
+	if pos := ast.PositionOf(call); pos.Line != 6 || pos.Col != 9 {
+		t.Errorf("call: expected 6:9, got %s", pos)
+	}
+	if span := call.(ast.Positioned).Span(); span.End.Line != 6 || span.End.Col != 17 {
+		t.Errorf("call span end: expected 6:17, got %s", span.End)
+	}
+}
+
+func TestStackTraceAcrossFunctions(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "math.foo")
+
+	code := `fn check(n) {
+    if n == 0 {
+        let bad = missingValue + 1
+    }
+    return n
+}
+
+fn fib(n) {
+    if n < 2 {
+        return check(0)
+    }
+    return fib(n - 1) + fib(n - 2)
+}
+
+let result = fib(2)
+`
+	if err := os.WriteFile(mainPath, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	interp := interpreter.New()
+	exprs, err := interp.ParseFile(mainPath)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	_, err = interp.Run(exprs)
+	if err == nil {
+		t.Fatal("expected runtime error")
+	}
+
+	var scriptErr *ast.ScriptError
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("expected *ast.ScriptError, got %T", err)
+	}
+
+	expected := []string{
+		"at check (" + mainPath + ":3:9)",
+		"at fib (" + mainPath + ":10:16)",
+		"at fib (" + mainPath + ":12:12)",
+		"at <main> (" + mainPath + ":15:14)",
+	}
+
+	if len(scriptErr.Trace) != len(expected) {
+		t.Fatalf("expected %d frames, got:\n%v", len(expected), err)
+	}
+	for i, frame := range scriptErr.Trace {
+		if frame.String() != expected[i] {
+			t.Errorf("frame %d: expected %q, got %q", i, expected[i], frame.String())
+		}
+	}
+
+	if !strings.HasPrefix(err.Error(), "variable missingValue is not defined\n    at check") {
+		t.Errorf("unexpected error text:\n%v", err)
+	}
+}
+
+func TestStackTraceInBytecode(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "math.foo")
+
+	code := `fn check(n) {
+    if n == 0 {
+        let bad = missingValue + 1
+    }
+    return n
+}
+
+fn fib(n) {
+    if n < 2 {
+        return check(0)
+    }
+    return fib(n - 1) + fib(n - 2)
+}
+
+let result = fib(2)
+`
+	if err := os.WriteFile(mainPath, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	interp := interpreter.New()
+	exprs, err := interp.ParseFile(mainPath)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	vm := bytecode.NewVMWithRuntime(bytecode.Compile(exprs), interp.Runtime())
+	err = func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = ast.NewScriptError(interp.Runtime(), r)
+			}
+		}()
+		vm.Run()
+		return nil
+	}()
+	if err == nil {
+		t.Fatal("expected runtime error")
+	}
+
+	// Кадры функций VM совпадают с tree-walking интерпретатором; позиция
+	// указывает на инструкцию, поэтому в check это само имя переменной
+	expected := []string{
+		"at check (" + mainPath + ":3:19)",
+		"at fib (" + mainPath + ":10:16)",
+		"at fib (" + mainPath + ":12:12)",
+		"at <main> (" + mainPath + ":15:14)",
+	}
+
+	trace := err.(*ast.ScriptError).Trace
+	if len(trace) != len(expected) {
+		t.Fatalf("expected %d frames, got:\n%v", len(expected), err)
+	}
+	for i, frame := range trace {
+		if frame.String() != expected[i] {
+			t.Errorf("frame %d: expected %q, got %q", i, expected[i], frame.String())
+		}
+	}
+}
+
+func TestStackTraceResetsAfterRecoveredError(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(`
+	fn fail() {
+		return undefinedName
+	}
+	let x = 1
+	let y = x + missing
+	`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	_, err = interp.Run(exprs)
+	if err == nil {
+		t.Fatal("expected runtime error")
+	}
+
+	var scriptErr *ast.ScriptError
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("expected *ast.ScriptError, got %T", err)
+	}
+	if len(scriptErr.Trace) != 1 || scriptErr.Trace[0].Function != "<main>" {
+		t.Errorf("expected only <main> frame, got %v", scriptErr.Trace)
+	}
+}
+
+func TestTracePrintsFooCallStack(t *testing.T) {
+	interp := interpreter.New()
+
+	output := captureOutput(func() {
+		runInterpreter(t, interp, `
+		fn inner() {
+			trace()
+		}
+		fn outer() {
+			inner()
+		}
+		outer()
+		`)
+	})
+
+	// Кадры foo от самого глубокого, с позициями в скрипте, без кадров Go
+	want := []string{"#0 inner", "3:4", "#1 outer", "6:4", "#2 <main>", "8:3"}
+	last := 0
+	for _, part := range want {
+		i := strings.Index(output[last:], part)
+		if i < 0 {
+			t.Fatalf("expected %q after offset %d in trace:\n%s", part, last, output)
+		}
+		last += i + len(part)
+	}
+	if strings.Contains(output, ".go:") {
+		t.Errorf("expected no Go frames in trace:\n%s", output)
+	}
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"foo_lang/interpreter"
+	"foo_lang/modules"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStdImport(t *testing.T) {
+	interp := interpreter.New()
+	file := filepath.Join(t.TempDir(), "notes.txt")
+
+	runInterpreter(t, interp, `
+	import * as fs from "std:fs"
+	import * as crypto from "std:crypto"
+	import { addDays, format, fromString } from "std:time"
+	import * as time from "std:time"
+	import * as http from "std:http"
+
+	// Пользовательская readFile не ломает fs.readFile: std вызывает __builtin_readFile
+	fn readFile(path) { return "shadowed" }
+
+	fs.writeLines("`+file+`", ["a", "b"])
+	let lines = fs.readLines("`+file+`").unwrap().length()
+	let name = fs.basename("`+file+`")
+	let digest = crypto.sha256("abc")
+	let day = format(addDays(fromString("2024-02-28 12:00:00").unwrap(), 1), "date")
+
+	// sleep - ключевое слово, но time.sleep экспортируется модулем как обычная функция
+	time.sleep(1)
+	let duration = time.parseDuration("1h30m").unwrap()
+	let size = fs.getFileInfo("`+file+`").unwrap().size
+	let token = crypto.generateJWT({"sub": 1}, "secret").unwrap()
+	let verified = crypto.verifyJWT(token, "secret").isOk()
+	let forged = crypto.verifyJWT(token, "other").isErr()
+	let notFound = http.STATUS_CODES.NOT_FOUND
+	`)
+
+	expected := map[string]string{
+		"lines":    "2",
+		"name":     "notes.txt",
+		"digest":   "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"day":      "2024-02-29",
+		"duration": "5400",
+		"size":     "3",
+		"verified": "true",
+		"forged":   "true",
+		"notFound": "404",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if got := val.String(); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestModuleSearchPath(t *testing.T) {
+	lib := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(lib, "text"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeModule(t, filepath.Join(lib, "text", "shout.foo"), `export fn shout(s) { return s.toUpper() + "!" }`)
+	// std/<имя>.foo в каталоге поиска подменяет встроенный модуль
+	writeModule(t, filepath.Join(lib, "std", "crypto.foo"), `export fn sha256(s) { return "local" }`)
+
+	interp := interpreter.New()
+	interp.Runtime().Modules.AddSearchPath(lib)
+
+	runInterpreter(t, interp, `
+	import { shout } from "text/shout"
+	import * as crypto from "std:crypto"
+	let loud = shout("hi")
+	let digest = crypto.sha256("abc")
+	`)
+
+	for name, want := range map[string]string{"loud": "HI!", "digest": "local"} {
+		val, _ := interp.Scope().Get(name)
+		if val.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, val.String())
+		}
+	}
+
+	exprs, err := interp.Parse(`import { shout } from "text/missing"`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, err := interp.Run(exprs); err == nil || !strings.Contains(err.Error(), "module 'text/missing' not found") {
+		t.Errorf("expected missing module error, got %v", err)
+	}
+}
+
+func TestModuleResolve(t *testing.T) {
+	lib := t.TempDir()
+	writeModule(t, filepath.Join(lib, "util.foo"), `export let X = 1`)
+	t.Setenv(modules.PathEnv, lib)
+
+	cache := modules.NewCache()
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"util", filepath.Join(lib, "util.foo")},
+		{"util.foo", filepath.Join(lib, "util.foo")},
+		{"std:fs", "std:fs"},
+		{"std:fs.foo", "std:fs"},
+		// Старые импорты "std/fs.foo" работают вне корня репозитория
+		{"std/fs.foo", "std:fs"},
+		{"./local.foo", filepath.Join("app", "local.foo")},
+	}
+	for _, tt := range tests {
+		got, err := cache.Resolve(filepath.Join("app", "main.foo"), tt.importPath)
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.importPath, got, err, tt.want)
+		}
+	}
+
+	// Модули std импортируют друг друга относительными путями
+	if got, _ := cache.Resolve("std:http", "./fs.foo"); got != "std:fs" {
+		t.Errorf("relative import from std: expected std:fs, got %q", got)
+	}
+
+	if _, err := cache.Resolve("main.foo", "std:nope"); err == nil || !strings.Contains(err.Error(), "standard library has no module 'std:nope'") {
+		t.Errorf("expected unknown std module error, got %v", err)
+	}
+}
+
+func writeModule(t *testing.T, path, code string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"foo_lang/builtin"
 	"foo_lang/parser"
@@ -46,10 +47,13 @@ func TestMutexFunctions(t *testing.T) {
 			code: `
 				let mutex1 = newMutex("duplicate")
 				println("First: " + mutex1)
-				let mutex2 = newMutex("duplicate")
-				println("Second: " + mutex2)
+				try {
+					newMutex("duplicate")
+				} catch (e) {
+					println("Second: " + e.message)
+				}
 			`,
-			expected: "First: duplicate\nSecond: Error: mutex 'duplicate' already exists",
+			expected: "First: duplicate\nSecond: mutex 'duplicate' already exists",
 		},
 	}
 
@@ -63,7 +67,7 @@ func TestMutexFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -115,7 +119,7 @@ func TestRWMutexFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -158,10 +162,13 @@ func TestSemaphoreFunctions(t *testing.T) {
 		{
 			name: "semaphore_invalid_capacity",
 			code: `
-				let sem = newSemaphore(0)
-				println("Result: " + sem)
+				try {
+					newSemaphore(0)
+				} catch (e) {
+					println("Result: " + e.message)
+				}
 			`,
-			expected: "Result: Error: semaphore capacity must be positive",
+			expected: "Result: newSemaphore() capacity must be positive",
 		},
 	}
 
@@ -175,7 +182,7 @@ func TestSemaphoreFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -229,7 +236,7 @@ func TestWaitGroupFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -296,7 +303,7 @@ func TestAtomicFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -324,10 +331,13 @@ func TestBarrierFunctions(t *testing.T) {
 		{
 			name: "barrier_invalid_size",
 			code: `
-				let barrier = newBarrier(0)
-				println("Result: " + barrier)
+				try {
+					newBarrier(0)
+				} catch (e) {
+					println("Result: " + e.message)
+				}
 			`,
-			expected: "Result: Error: barrier n must be positive",
+			expected: "Result: newBarrier() n must be positive",
 		},
 	}
 
@@ -341,7 +351,7 @@ func TestBarrierFunctions(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -362,8 +372,11 @@ func TestSyncErrorHandling(t *testing.T) {
 		{
 			name: "mutex_not_found",
 			code: `
-				let result = mutexLock("nonexistent")
-				println(result)
+				try {
+					mutexLock("nonexistent")
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "does not exist",
@@ -372,8 +385,11 @@ func TestSyncErrorHandling(t *testing.T) {
 			name: "semaphore_release_without_acquire",
 			code: `
 				let sem = newSemaphore(1)
-				let result = semaphoreRelease(sem)
-				println(result)
+				try {
+					semaphoreRelease(sem)
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "release without acquire",
@@ -381,8 +397,11 @@ func TestSyncErrorHandling(t *testing.T) {
 		{
 			name: "wrong_argument_type",
 			code: `
-				let result = newMutex(123)
-				println(result)
+				try {
+					newMutex(123)
+				} catch (e) {
+					println(e.message)
+				}
 			`,
 			wantErr:  true,
 			contains: "must be string",
@@ -399,7 +418,7 @@ func TestSyncErrorHandling(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -419,6 +438,8 @@ func TestSyncCleanupFunction(t *testing.T) {
 		{
 			name: "cleanup_all_sync_primitives",
 			code: `
+				// Имена могли остаться от предыдущих тестов: повторное создание - ошибка
+				syncCleanup()
 				let mutex = newMutex("test")
 				let sem = newSemaphore(1, "test_sem")
 				let atomic = newAtomic(42, "test_atomic")
@@ -426,10 +447,13 @@ func TestSyncCleanupFunction(t *testing.T) {
 				let cleaned = syncCleanup()
 				println("Cleanup: " + cleaned.toString())
 				// После очистки примитивы должны быть недоступны
-				let result = mutexLock("test")
-				println("After cleanup: " + result)
+				try {
+					mutexLock("test")
+				} catch (e) {
+					println("After cleanup: " + e.message)
+				}
 			`,
-			expected: "Created primitives\nCleanup: true\nAfter cleanup: Error: mutex 'test' does not exist",
+			expected: "Created primitives\nCleanup: true\nAfter cleanup: mutex 'test' does not exist",
 		},
 	}
 
@@ -443,7 +467,7 @@ func TestSyncCleanupFunction(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -506,7 +530,7 @@ func TestSyncConcurrencyBasic(t *testing.T) {
 
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
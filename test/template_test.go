@@ -58,7 +58,7 @@ func TestTemplateExpression(t *testing.T) {
 
 			// Создаем и выполняем TemplateExpr
 			templateExpr := ast.NewTemplateExpr(tt.template)
-			result := templateExpr.Eval()
+			result := templateExpr.Eval(ast.GlobalRuntime)
 
 			if result == nil {
 				t.Fatalf("Expected result, got nil")
@@ -103,7 +103,7 @@ func TestMacroWithTemplateGeneration(t *testing.T) {
 
 	// Выполняем все выражения
 	for _, expr := range exprs {
-		result := expr.Eval()
+		result := expr.Eval(ast.GlobalRuntime)
 		if result != nil && result.IsReturn() {
 			break
 		}
@@ -134,7 +134,7 @@ func TestTemplateWithForLoop(t *testing.T) {
 
 	// Создаем и выполняем TemplateExpr
 	templateExpr := ast.NewTemplateExpr(template)
-	result := templateExpr.Eval()
+	result := templateExpr.Eval(ast.GlobalRuntime)
 
 	if result == nil {
 		t.Fatalf("Expected result, got nil")
@@ -192,7 +192,7 @@ func TestTemplateWithConditionals(t *testing.T) {
 
 			// Создаем и выполняем TemplateExpr
 			templateExpr := ast.NewTemplateExpr(tt.template)
-			result := templateExpr.Eval()
+			result := templateExpr.Eval(ast.GlobalRuntime)
 
 			if result == nil {
 				t.Fatalf("Expected result, got nil")
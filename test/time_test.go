@@ -1,6 +1,7 @@
 package test
 
 import (
+	"foo_lang/ast"
 	"bytes"
 	"fmt"
 	"foo_lang/parser"
@@ -81,7 +82,7 @@ func TestTimeBasicOperations(t *testing.T) {
 				
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -152,7 +153,7 @@ func TestTimeArithmetic(t *testing.T) {
 				
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -213,7 +214,7 @@ func TestTimeComparison(t *testing.T) {
 				
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -269,7 +270,7 @@ func TestTimeFormatting(t *testing.T) {
 				
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
@@ -336,26 +337,35 @@ func TestTimeErrorHandling(t *testing.T) {
 		{
 			name: "invalid_argument_count_now",
 			code: `
-				let result = now(123)
-				println(result)
+				try {
+					now(123)
+				} catch (e) {
+					println(e.message)
+				}
 			`,
-			want: "Error: now() requires 0 arguments",
+			want: "now() requires 0 arguments",
 		},
 		{
 			name: "invalid_timeFromUnix_arg",
 			code: `
-				let result = timeFromUnix("invalid")
-				println(result)
+				try {
+					timeFromUnix("invalid")
+				} catch (e) {
+					println(e.message)
+				}
 			`,
-			want: "Error: timeFromUnix() requires numeric argument",
+			want: "timeFromUnix() requires numeric argument",
 		},
 		{
 			name: "invalid_timeFormat_arg",
 			code: `
-				let result = timeFormat("not_a_time", "date")
-				println(result)
+				try {
+					timeFormat("not_a_time", "date")
+				} catch (e) {
+					println(e.message)
+				}
 			`,
-			want: "Error: first argument must be a time value",
+			want: "timeFormat() first argument must be a time value",
 		},
 	}
 
@@ -366,7 +376,7 @@ func TestTimeErrorHandling(t *testing.T) {
 				
 				exprs := parser.NewParser(tt.code).ParseWithoutScopeInit()
 				for _, expr := range exprs {
-					expr.Eval()
+					expr.Eval(ast.GlobalRuntime)
 				}
 			})
 
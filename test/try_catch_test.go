@@ -0,0 +1,193 @@
+package test
+
+import (
+	"foo_lang/interpreter"
+	"strings"
+	"testing"
+)
+
+func TestTryCatchThrow(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	fn check(n) {
+		if n > 2 {
+			throw "too big: " + n
+		}
+		return n
+	}
+
+	let message = ""
+	let kind = ""
+	let code = ""
+	let frame = ""
+	try {
+		check(5)
+		message = "not reached"
+	} catch (e) {
+		message = e.message
+		kind = e.type
+		code = e.code
+		frame = e.stack[0]
+	}
+	`)
+
+	expected := map[string]string{
+		"message": "too big: 5",
+		"kind":    "Error",
+		"code":    "E901",
+	}
+	for name, want := range expected {
+		val, _ := interp.Scope().Get(name)
+		if val.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, val.String())
+		}
+	}
+
+	frame, _ := interp.Scope().Get("frame")
+	if !strings.HasPrefix(frame.String(), "at check (") {
+		t.Errorf("expected frame of check, got %q", frame.String())
+	}
+}
+
+func TestTryCatchRuntimePanic(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let unwrapError = ""
+	try {
+		let r = Err("bad input")
+		r.unwrap()
+	} catch e {
+		unwrapError = e.type + ": " + e.message
+	}
+
+	let structError = ""
+	try {
+		let p = Missing{x: 1}
+	} catch (e) {
+		structError = e.message
+	}
+
+	let caught = false
+	try {
+		println(undefinedName)
+	} catch {
+		caught = true
+	}
+	`)
+
+	unwrapError, _ := interp.Scope().Get("unwrapError")
+	if unwrapError.String() != "RuntimeError: called unwrap on Err value: bad input" {
+		t.Errorf("unexpected unwrap error: %q", unwrapError.String())
+	}
+
+	structError, _ := interp.Scope().Get("structError")
+	if !strings.Contains(structError.String(), "Missing") {
+		t.Errorf("expected struct error, got %q", structError.String())
+	}
+
+	caught, _ := interp.Scope().Get("caught")
+	if !caught.Bool() {
+		t.Error("expected undefined variable error to be caught")
+	}
+}
+
+func TestTryFinally(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	let steps = ""
+	fn work() {
+		try {
+			steps = steps + "try;"
+			return 1
+		} finally {
+			steps = steps + "finally;"
+		}
+		return 2
+	}
+	let result = work()
+
+	try {
+		throw "boom"
+	} catch (e) {
+		steps = steps + "catch;"
+	} finally {
+		steps = steps + "finally;"
+	}
+	`)
+
+	steps, _ := interp.Scope().Get("steps")
+	if steps.String() != "try;finally;catch;finally;" {
+		t.Errorf("unexpected order: %q", steps.String())
+	}
+
+	result, _ := interp.Scope().Get("result")
+	if result.Int64() != 1 {
+		t.Errorf("expected return value from try, got %v", result.Any())
+	}
+
+	// Без catch ошибка уходит выше после finally, повторный throw сохраняет код
+	exprs, err := interp.Parse(`
+	let cleaned = false
+	try {
+		try {
+			throw {message: "inner", code: "E42"}
+		} catch (e) {
+			throw e
+		}
+	} finally {
+		cleaned = true
+	}
+	`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	_, err = interp.Run(exprs)
+	if err == nil || !strings.HasPrefix(err.Error(), "Error: inner") {
+		t.Fatalf("expected rethrown error, got %v", err)
+	}
+
+	cleaned, _ := interp.Scope().Get("cleaned")
+	if !cleaned.Bool() {
+		t.Error("finally should run before the error propagates")
+	}
+}
+
+func TestTryUnwindsScope(t *testing.T) {
+	interp := interpreter.New()
+
+	runInterpreter(t, interp, `
+	fn deep(n) {
+		for let i = 0; i < 10; i++ {
+			if n == 0 {
+				throw "bottom"
+			}
+			return deep(n - 1)
+		}
+	}
+
+	try {
+		deep(5)
+	} catch (e) {
+		let inCatch = e.message
+	}
+	let after = "global"
+	`)
+
+	if depth := interp.Scope().GetRecursionDepth(); depth != 0 {
+		t.Errorf("expected recursion depth 0, got %d", depth)
+	}
+
+	for _, name := range []string{"i", "n", "e", "inCatch"} {
+		if _, ok := interp.Scope().Get(name); ok {
+			t.Errorf("%s leaked out of try/catch", name)
+		}
+	}
+
+	if _, ok := interp.Scope().CurrentScope().Get("after"); !ok {
+		t.Error("expected 'after' in the global scope")
+	}
+}
@@ -0,0 +1,248 @@
+package test
+
+import (
+	"foo_lang/checker"
+	"foo_lang/interpreter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func checkProgram(t *testing.T, code string) []string {
+	t.Helper()
+
+	exprs, err := interpreter.New().Parse(code)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	var messages []string
+	for _, e := range checker.Check(exprs) {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
+
+func expectCheckErrors(t *testing.T, got []string, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d error(s), got %d:\n%s", len(want), len(got), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		if !strings.HasSuffix(got[i], want[i]) {
+			t.Errorf("error %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestCheckerCallsAndReturns(t *testing.T) {
+	errors := checkProgram(t, `
+fn add(a: int, b: int) -> int {
+	return a + b
+}
+fn greet(name: string, greeting: string = "Hello") -> string {
+	return greeting + ", " + name
+}
+fn broken() -> int {
+	return "oops"
+}
+fn twice(x: int) -> int { return x * 2 }
+fn twice(x: string) -> string { return x + x }
+
+let total = add(1, 2)
+let bad = add(1, "2")
+let few = add(1)
+let many = greet("a", "b", "c")
+let fine = greet("bob")
+let text: string = total + 1
+let doubled: string = twice("ab")
+let wrong = twice(true)
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:9:2: function 'broken' return type error: expected int, got string",
+		"<input>:15:11: function 'add' parameter 'b': expected int, got string",
+		"<input>:16:11: function 'add' requires at least 2 arguments, got 1",
+		"<input>:17:12: function 'greet' accepts at most 2 arguments, got 3",
+		"<input>:19:1: variable 'text' type error: expected string, got int",
+		"<input>:21:13: no overload of 'twice' accepts (bool)",
+	})
+}
+
+func TestCheckerUnionOptionalAndAliases(t *testing.T) {
+	errors := checkProgram(t, `
+type UserId = int
+
+fn find(id: UserId) -> string? {
+	if id == 0 {
+		return null
+	}
+	return "user"
+}
+
+let name: string = find(1)
+let maybe = find(2)
+if maybe != null {
+	let sure: string = maybe
+}
+let id: UserId = 5
+let badId: UserId = "five"
+let value: string | int = 3.14
+let opt: int? = null
+opt = 3
+opt = "three"
+let missing: Unknown = 1
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:11:1: variable 'name' type error: expected string, got string|null",
+		"<input>:17:1: variable 'badId' type error: expected UserId, got string",
+		"<input>:18:1: variable 'value' type error: expected string|int, got float",
+		"<input>:21:1: variable 'opt' type error: expected int|null, got string",
+		"<input>:22:1: unknown type 'Unknown'",
+	})
+}
+
+func TestCheckerArrayLiteralElements(t *testing.T) {
+	errors := checkProgram(t, `
+let arr: Array<int> = [1, "a"]
+let floats: Array<float> = [1, 2.5]
+let grid: Array<Array<int>> = [[1], [true]]
+let maybe: Array<string>? = ["x", 2]
+let ids: Array<int> = [3, 4]
+ids = [5, "6"]
+let anything: Array<any> = [1, "a"]
+fn names() -> Array<string> {
+	return ["a", null]
+}
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:2:27: array element 1 type error: expected int, got string",
+		"<input>:4:37: array element 0 type error: expected int, got bool",
+		"<input>:5:35: array element 1 type error: expected string, got int",
+		"<input>:7:11: array element 1 type error: expected int, got string",
+		"<input>:10:15: array element 1 type error: expected string, got null",
+	})
+}
+
+func TestCheckerInterfacesAndGenerics(t *testing.T) {
+	errors := checkProgram(t, `
+interface Drawable {
+	fn draw() -> string
+}
+interface Sized {
+	fn size(scale: int) -> int
+}
+
+struct Point { x: int, y: int }
+struct Label { text: string }
+struct Plain { v: int }
+
+impl Drawable for Point {
+	fn draw() -> string {
+		return "point " + this.x
+	}
+}
+impl Sized for Point {
+	fn size(scale: float) -> int {
+		return 1
+	}
+}
+impl Drawable for Label {
+	fn render() -> string {
+		return this.text
+	}
+}
+
+fn show<T: Drawable>(item: T) -> string {
+	return item.draw()
+}
+
+let p = Point{x: 1, y: 2}
+let drawn: string = show(p)
+let plain = show(Plain{v: 1})
+let extra = show(p, p)
+let field = Point{x: "one", y: 2}
+let px: string = p.x
+let size: string = p.size(2)
+`)
+
+	expectCheckErrors(t, errors, []string{
+		"<input>:19:2: impl Sized for Point: method 'size' signature doesn't match interface: expected (scale: int) -> int, got (scale: float) -> int",
+		"<input>:23:1: impl Drawable for Label: method 'draw' is not implemented",
+		"<input>:35:13: function 'show': type Plain does not satisfy interface constraint 'Drawable'",
+		"<input>:36:13: function 'show' expects 1 arguments, got 2",
+		"<input>:37:13: struct Point field 'x': expected int, got string",
+		"<input>:38:1: variable 'px' type error: expected string, got int",
+		"<input>:39:1: variable 'size' type error: expected string, got int",
+	})
+}
+
+func TestCheckerAcceptsDynamicPrograms(t *testing.T) {
+	for name, code := range map[string]string{
+		"loops":  loopsProgram,
+		"shapes": shapeProgram,
+		"dynamic": `
+fn apply(f, x) {
+	return f(x)
+}
+fn typed(n: int) -> int { return n }
+let unknown = apply(fn(v) => v, 1)
+let n: int = typed(unknown)
+let acc = 0
+acc = acc + "x"
+let s: string = acc
+`,
+	} {
+		if errors := checkProgram(t, code); len(errors) != 0 {
+			t.Errorf("%s: unexpected errors:\n%s", name, strings.Join(errors, "\n"))
+		}
+	}
+}
+
+func TestCheckerDoesNotExecute(t *testing.T) {
+	interp := interpreter.New()
+
+	exprs, err := interp.Parse(`
+let ran = "yes"
+fn f(a: int) { return a }
+f("no")
+`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if errors := checker.Check(exprs); len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", errors)
+	}
+	if _, ok := interp.Scope().Get("ran"); ok {
+		t.Error("checker evaluated the program")
+	}
+}
+
+func TestCheckFileHasNoSideEffects(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "marker.txt")
+	file := filepath.Join(dir, "main.foo")
+	writeModule(t, file, `
+const written = writeFile("`+filepath.ToSlash(marker)+`", "side effect")
+let total = written
+`)
+
+	interp := interpreter.New()
+	exprs, err := interp.ParseFileForCheck(file)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	checker.Check(exprs)
+
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("check evaluated a const initializer")
+	}
+	if _, ok := interp.Scope().Get("written"); ok {
+		t.Error("check defined a const value")
+	}
+}
@@ -10,6 +10,7 @@ const (
 	IDENT       // main
 	INT         // 12345
 	FLOAT       // 123.45
+	BIGINT      // 12345n
 	CHAR        // 'a'
 	STRING      // "abc"
 	INTERP_STRING // "hello ${name}"
@@ -36,6 +37,10 @@ const (
 	IF
 	ELSE
 	FOR
+	IN
+	WHILE
+	LOOP
+	CONTINUE
 	COMPILE_FOR      // $for (compile-time for loop)
 	COMPILE_IF       // $if (compile-time if)
 	COMPILE_WHILE    // $while (compile-time while)
@@ -62,6 +67,11 @@ const (
 	AWAIT
 	SLEEP
 	PROMISE
+	TRY
+	CATCH
+	FINALLY
+	THROW
+	DEFER
 
 	operator_beg
 	ADD        // +
@@ -116,11 +126,13 @@ const (
 	GTR // >
 	NOT // !
 
-	NEQ      // !=
-	LEQ      // <=
-	GEQ      // >=
-	DEFINE   // :=
-	ELLIPSIS // ...
+	NEQ        // !=
+	LEQ        // <=
+	GEQ        // >=
+	DEFINE     // :=
+	ELLIPSIS   // ...
+	DOT_DOT    // .. (диапазон в for-in и паттернах match, конец не включается)
+	DOT_DOT_EQ // ..= (диапазон с включенным концом)
 
 	LPAREN // (
 	LBRACK // [
@@ -145,6 +157,7 @@ var tokens = [...]string{
 	IDENT:  "IDENT",
 	INT:    "INT",
 	FLOAT:  "FLOAT",
+	BIGINT: "BIGINT",
 	CHAR:   "CHAR",
 	STRING: "STRING",
 
@@ -186,11 +199,13 @@ var tokens = [...]string{
 	EQ:  "=",
 	NOT: "!",
 
-	NEQ:      "!=",
-	LEQ:      "<=",
-	GEQ:      ">=",
-	DEFINE:   ":=",
-	ELLIPSIS: "...",
+	NEQ:        "!=",
+	LEQ:        "<=",
+	GEQ:        ">=",
+	DEFINE:     ":=",
+	ELLIPSIS:   "...",
+	DOT_DOT:    "..",
+	DOT_DOT_EQ: "..=",
 
 	LPAREN: "(",
 	LBRACK: "[",
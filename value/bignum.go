@@ -0,0 +1,387 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal - десятичное число с фиксированной точкой: unscaled * 10^-scale.
+// Сложение, вычитание и умножение точные; деление округляется до
+// DecimalDivisionScale знаков после точки
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// DecimalDivisionScale - число знаков после точки в результате деления Decimal
+const DecimalDivisionScale = 20
+
+var errDivisionByZero = errors.New("division by zero")
+
+var bigTen = big.NewInt(10)
+
+// ParseDecimal разбирает десятичную запись: "19.99", "-0.5", "1e-3"
+func ParseDecimal(s string) (*Decimal, error) {
+	text := strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	mantissa, exponent := text, 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(text[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid decimal: %q", s)
+		}
+		mantissa, exponent = text[:i], exp
+	}
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok || strings.ContainsAny(fracPart, "+-") {
+		return nil, fmt.Errorf("invalid decimal: %q", s)
+	}
+	scale := len(fracPart) - exponent
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return &Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// NewDecimalFromInt создает Decimal из целого числа
+func NewDecimalFromInt(n *big.Int) *Decimal {
+	return &Decimal{unscaled: new(big.Int).Set(n), scale: 0}
+}
+
+// NewDecimalFromFloat создает Decimal из кратчайшей десятичной записи float:
+// 0.1 становится ровно 0.1, а не двоичным приближением
+func NewDecimalFromFloat(f float64) (*Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("cannot convert %v to decimal", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// rescale возвращает unscaled, приведенный к большему масштабу scale
+func (d *Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.unscaled
+	}
+	return new(big.Int).Mul(d.unscaled, pow10(int(scale-d.scale)))
+}
+
+// align приводит два числа к общему масштабу
+func align(a, b *Decimal) (*big.Int, *big.Int, int32) {
+	scale := max(a.scale, b.scale)
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// Sign возвращает -1, 0 или 1
+func (d *Decimal) Sign() int {
+	return d.unscaled.Sign()
+}
+
+// Cmp сравнивает числа: -1, 0 или 1
+func (d *Decimal) Cmp(other *Decimal) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+func (d *Decimal) Add(other *Decimal) *Decimal {
+	a, b, scale := align(d, other)
+	return &Decimal{unscaled: new(big.Int).Add(a, b), scale: scale}
+}
+
+func (d *Decimal) Sub(other *Decimal) *Decimal {
+	a, b, scale := align(d, other)
+	return &Decimal{unscaled: new(big.Int).Sub(a, b), scale: scale}
+}
+
+func (d *Decimal) Mul(other *Decimal) *Decimal {
+	return &Decimal{unscaled: new(big.Int).Mul(d.unscaled, other.unscaled), scale: d.scale + other.scale}
+}
+
+// Div делит с округлением половины от нуля до DecimalDivisionScale знаков и
+// отбрасывает незначащие нули: 1 / 4 = 0.25
+func (d *Decimal) Div(other *Decimal) (*Decimal, error) {
+	if other.Sign() == 0 {
+		return nil, errDivisionByZero
+	}
+	// d / other = (d.unscaled * 10^shift) / other.unscaled * 10^-scale
+	scale := int32(DecimalDivisionScale)
+	shift := int(scale - d.scale + other.scale)
+	numerator := new(big.Int).Set(d.unscaled)
+	denominator := new(big.Int).Set(other.unscaled)
+	if shift >= 0 {
+		numerator.Mul(numerator, pow10(shift))
+	} else {
+		denominator.Mul(denominator, pow10(-shift))
+	}
+	return (&Decimal{unscaled: roundQuo(numerator, denominator), scale: scale}).trim(), nil
+}
+
+// Mod возвращает остаток с тем же знаком, что и делимое
+func (d *Decimal) Mod(other *Decimal) (*Decimal, error) {
+	if other.Sign() == 0 {
+		return nil, errDivisionByZero
+	}
+	a, b, scale := align(d, other)
+	return &Decimal{unscaled: new(big.Int).Rem(a, b), scale: scale}, nil
+}
+
+func (d *Decimal) Neg() *Decimal {
+	return &Decimal{unscaled: new(big.Int).Neg(d.unscaled), scale: d.scale}
+}
+
+func (d *Decimal) Abs() *Decimal {
+	return &Decimal{unscaled: new(big.Int).Abs(d.unscaled), scale: d.scale}
+}
+
+// Round округляет до places знаков после точки (половина - от нуля)
+func (d *Decimal) Round(places int) *Decimal {
+	if places < 0 || int32(places) >= d.scale {
+		return d
+	}
+	divisor := pow10(int(d.scale) - places)
+	return &Decimal{unscaled: roundQuo(d.unscaled, divisor), scale: int32(places)}
+}
+
+// roundQuo делит с округлением половины от нуля
+func roundQuo(numerator, denominator *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	// |rem| * 2 >= |denominator| - округляем от нуля
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(denominator)) >= 0 {
+		if numerator.Sign()*denominator.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// trim отбрасывает нули в конце дробной части
+func (d *Decimal) trim() *Decimal {
+	unscaled, scale := new(big.Int).Set(d.unscaled), d.scale
+	rem := new(big.Int)
+	for scale > 0 {
+		quo, r := new(big.Int).QuoRem(unscaled, bigTen, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		unscaled, scale = quo, scale-1
+	}
+	return &Decimal{unscaled: unscaled, scale: scale}
+}
+
+// IsInteger сообщает, нет ли у числа дробной части
+func (d *Decimal) IsInteger() bool {
+	return d.trim().scale == 0
+}
+
+// BigInt возвращает целую часть числа (отбрасывая дробную)
+func (d *Decimal) BigInt() *big.Int {
+	return new(big.Int).Quo(d.unscaled, pow10(int(d.scale)))
+}
+
+// Float64 возвращает ближайшее float значение
+func (d *Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String возвращает десятичную запись с сохранением масштаба: 1.10 + 2.20 = 3.30
+func (d *Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaled).String()
+	sign := ""
+	if d.unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// compareDecimalFloat сообщает, равен ли Decimal float значению f
+func compareDecimalFloat(d *Decimal, f float64) bool {
+	other, err := NewDecimalFromFloat(f)
+	return err == nil && d.Cmp(other) == 0
+}
+
+// MarshalJSON кодирует Decimal как JSON число без потери точности
+func (d *Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// AddInt складывает int. При check переполнение - паника, а не перенос
+// (режим --check-overflow, см. ast.Runtime.SetOverflowCheck)
+func AddInt(a, b int64, check bool) int64 {
+	sum := a + b
+	if check && (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		panic(fmt.Sprintf("integer overflow: %d + %d", a, b))
+	}
+	return sum
+}
+
+// SubInt вычитает int. При check переполнение - паника, а не перенос
+func SubInt(a, b int64, check bool) int64 {
+	diff := a - b
+	if check && (a >= 0) != (b >= 0) && (diff >= 0) != (a >= 0) {
+		panic(fmt.Sprintf("integer overflow: %d - %d", a, b))
+	}
+	return diff
+}
+
+// MulInt умножает int. При check переполнение - паника, а не перенос
+func MulInt(a, b int64, check bool) int64 {
+	product := a * b
+	if check && a != 0 && (product/a != b || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64)) {
+		panic(fmt.Sprintf("integer overflow: %d * %d", a, b))
+	}
+	return product
+}
+
+// NegInt меняет знак int. При check переполнение - паника, а не перенос
+func NegInt(a int64, check bool) int64 {
+	if check && a == math.MinInt64 {
+		panic(fmt.Sprintf("integer overflow: -(%d)", a))
+	}
+	return -a
+}
+
+// IsBigNumber сообщает, является ли значение BigInt или Decimal. BigInt -
+// целое произвольной точности (литерал 123n или bigint()); оно хранится как
+// *big.Int и никогда не изменяется на месте: каждая операция создает новое число
+func IsBigNumber(v *Value) bool {
+	return isBigData(v.Any())
+}
+
+func isBigData(data any) bool {
+	switch data.(type) {
+	case *big.Int, *Decimal:
+		return true
+	}
+	return false
+}
+
+// toDecimal приводит число к Decimal; ok = false для нечисловых значений
+func toDecimal(data any) (*Decimal, bool, error) {
+	switch n := data.(type) {
+	case int64:
+		return &Decimal{unscaled: big.NewInt(n)}, true, nil
+	case float64:
+		d, err := NewDecimalFromFloat(n)
+		return d, true, err
+	case *big.Int:
+		return &Decimal{unscaled: n}, true, nil
+	case *Decimal:
+		return n, true, nil
+	}
+	return nil, false, nil
+}
+
+// toBigInt приводит int или BigInt к *big.Int
+func toBigInt(data any) (*big.Int, bool) {
+	switch n := data.(type) {
+	case int64:
+		return big.NewInt(n), true
+	case *big.Int:
+		return n, true
+	}
+	return nil, false
+}
+
+// BigArith выполняет арифметическую операцию op (+, -, *, /, %), когда хотя бы
+// один операнд BigInt или Decimal. BigInt с int дает BigInt (деление
+// отбрасывает дробную часть); Decimal или float в паре с BigInt дают Decimal
+func BigArith(op string, a, b *Value) (*Value, error) {
+	x, y := a.Any(), b.Any()
+	if bx, ok := toBigInt(x); ok {
+		if by, ok := toBigInt(y); ok {
+			result, err := bigIntArith(op, bx, by)
+			if err != nil {
+				return nil, err
+			}
+			return NewValue(result), nil
+		}
+	}
+
+	dx, okX, err := toDecimal(x)
+	if err != nil {
+		return nil, err
+	}
+	dy, okY, err := toDecimal(y)
+	if err != nil {
+		return nil, err
+	}
+	if !okX || !okY {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, GetValueTypeName(a), GetValueTypeName(b))
+	}
+	result, err := decimalArith(op, dx, dy)
+	if err != nil {
+		return nil, err
+	}
+	return NewValue(result), nil
+}
+
+func bigIntArith(op string, a, b *big.Int) (*big.Int, error) {
+	switch op {
+	case "+":
+		return new(big.Int).Add(a, b), nil
+	case "-":
+		return new(big.Int).Sub(a, b), nil
+	case "*":
+		return new(big.Int).Mul(a, b), nil
+	case "/", "%":
+		if b.Sign() == 0 {
+			return nil, errDivisionByZero
+		}
+		if op == "/" {
+			return new(big.Int).Quo(a, b), nil
+		}
+		return new(big.Int).Rem(a, b), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+func decimalArith(op string, a, b *Decimal) (*Decimal, error) {
+	switch op {
+	case "+":
+		return a.Add(b), nil
+	case "-":
+		return a.Sub(b), nil
+	case "*":
+		return a.Mul(b), nil
+	case "/":
+		return a.Div(b)
+	case "%":
+		return a.Mod(b)
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// compareBig сравнивает числа, из которых хотя бы одно BigInt или Decimal
+func compareBig(a, b any) (int, bool) {
+	if bx, ok := toBigInt(a); ok {
+		if by, ok := toBigInt(b); ok {
+			return bx.Cmp(by), true
+		}
+	}
+	dx, okX, errX := toDecimal(a)
+	dy, okY, errY := toDecimal(b)
+	if !okX || !okY || errX != nil || errY != nil {
+		return 0, false
+	}
+	return dx.Cmp(dy), true
+}
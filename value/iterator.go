@@ -0,0 +1,118 @@
+package value
+
+import "fmt"
+
+// Iterator - протокол обхода для for-in. Next возвращает ключ элемента
+// (индекс массива, ключ объекта, номер значения канала), сам элемент и
+// ok = false, когда элементы закончились
+type Iterator interface {
+	Next() (key *Value, item *Value, ok bool)
+}
+
+// Iterable - значение, которое само создает свой итератор
+type Iterable interface {
+	Iter() Iterator
+}
+
+// NewIterator возвращает итератор для встроенных коллекций: массивов, объектов
+// (в порядке добавления ключей), строк (по символам), каналов (до закрытия) и диапазонов
+func NewIterator(v *Value) (Iterator, bool) {
+	switch data := v.Any().(type) {
+	case Iterable:
+		return data.Iter(), true
+	case []any:
+		return &sliceIterator{items: data, frozen: v.IsFrozen()}, true
+	case string:
+		return &stringIterator{runes: []rune(data)}, true
+	case *Channel:
+		return &channelIterator{ch: data}, true
+	}
+	return nil, false
+}
+
+type sliceIterator struct {
+	items  []any
+	pos    int
+	frozen bool // элементы замороженного массива тоже заморожены
+}
+
+func (it *sliceIterator) Next() (*Value, *Value, bool) {
+	if it.pos >= len(it.items) {
+		return nil, nil, false
+	}
+	it.pos++
+	item := NewValue(it.items[it.pos-1])
+	item.isFrozen = it.frozen
+	return NewValue(int64(it.pos - 1)), item, true
+}
+
+type stringIterator struct {
+	runes []rune
+	pos   int
+}
+
+func (it *stringIterator) Next() (*Value, *Value, bool) {
+	if it.pos >= len(it.runes) {
+		return nil, nil, false
+	}
+	it.pos++
+	return NewValue(int64(it.pos - 1)), NewValue(string(it.runes[it.pos-1])), true
+}
+
+// channelIterator читает канал напрямую, без блокировок Channel:
+// иначе ожидание значения не дало бы другой горутине закрыть канал
+type channelIterator struct {
+	ch    *Channel
+	count int64
+}
+
+func (it *channelIterator) Next() (*Value, *Value, bool) {
+	item, ok := <-it.ch.buffer
+	if !ok {
+		return nil, nil, false
+	}
+	it.count++
+	return NewValue(it.count - 1), item, true
+}
+
+// Range - целочисленный диапазон с шагом 1: start..end (end не включается)
+// или start..=end (end включается). Убывающий диапазон (3..0) пуст
+type Range struct {
+	Start     int64
+	End       int64
+	Inclusive bool
+}
+
+func NewRange(start, end int64, inclusive bool) *Range {
+	return &Range{Start: start, End: end, Inclusive: inclusive}
+}
+
+func (r *Range) Iter() Iterator {
+	return &rangeIterator{r: r, next: r.Start}
+}
+
+func (r *Range) String() string {
+	if r.Inclusive {
+		return fmt.Sprintf("%d..=%d", r.Start, r.End)
+	}
+	return fmt.Sprintf("%d..%d", r.Start, r.End)
+}
+
+type rangeIterator struct {
+	r     *Range
+	next  int64
+	count int64
+	done  bool
+}
+
+func (it *rangeIterator) Next() (*Value, *Value, bool) {
+	if it.next > it.r.End || (it.next == it.r.End && !it.r.Inclusive) || it.done {
+		return nil, nil, false
+	}
+	item := it.next
+	// Инклюзивный диапазон до MaxInt64 не должен переполнить счетчик
+	it.done = item == it.r.End
+	it.next++
+	it.count++
+	return NewValue(it.count - 1), NewValue(item), true
+}
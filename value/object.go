@@ -0,0 +1,170 @@
+package value
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Object - объект языка: поля со строковыми ключами, которые обходятся в
+// порядке добавления (for-in, печать, jsonStringify, debug)
+type Object struct {
+	keys   []string
+	fields map[string]*Value
+}
+
+// NewObject создает пустой объект
+func NewObject() *Object {
+	return &Object{fields: make(map[string]*Value)}
+}
+
+// NewObjectFromMap оборачивает готовый словарь полей. Порядок ключей у такого
+// объекта алфавитный, новые поля добавляются в конец
+func NewObjectFromMap(fields map[string]*Value) *Object {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return &Object{keys: keys, fields: fields}
+}
+
+// Len возвращает количество полей
+func (o *Object) Len() int {
+	return len(o.fields)
+}
+
+// Get возвращает поле по ключу
+func (o *Object) Get(key string) (*Value, bool) {
+	field, exists := o.fields[key]
+	return field, exists
+}
+
+// Has сообщает, есть ли у объекта поле key
+func (o *Object) Has(key string) bool {
+	_, exists := o.fields[key]
+	return exists
+}
+
+// Set присваивает поле; новое поле становится последним
+func (o *Object) Set(key string, field *Value) {
+	if _, exists := o.fields[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.fields[key] = field
+}
+
+// Delete удаляет поле и сообщает, было ли оно
+func (o *Object) Delete(key string) bool {
+	if _, exists := o.fields[key]; !exists {
+		return false
+	}
+	delete(o.fields, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i:i], o.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Keys возвращает ключи в порядке добавления. Поля, записанные напрямую в
+// словарь из Fields, идут в конце по алфавиту
+func (o *Object) Keys() []string {
+	keys := make([]string, 0, len(o.fields))
+	seen := make(map[string]bool, len(o.fields))
+	for _, key := range o.keys {
+		if _, exists := o.fields[key]; exists && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) < len(o.fields) {
+		var extra []string
+		for key := range o.fields {
+			if !seen[key] {
+				extra = append(extra, key)
+			}
+		}
+		sort.Strings(extra)
+		keys = append(keys, extra...)
+	}
+	return keys
+}
+
+// Fields возвращает словарь полей для кода, которому порядок не важен
+func (o *Object) Fields() map[string]*Value {
+	return o.fields
+}
+
+func (o *Object) Iter() Iterator {
+	return &objectIterator{object: o, keys: o.Keys()}
+}
+
+func (o *Object) String() string {
+	keys := o.Keys()
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + ": " + formatItem(o.fields[key].Any())
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// MarshalJSON кодирует объект в JSON с ключами в порядке объекта
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.Keys() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		field, err := json.Marshal(o.fields[key].Any())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(field)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// formatItem форматирует элемент коллекции для печати
+func formatItem(item any) string {
+	switch data := item.(type) {
+	case nil:
+		return "null"
+	case *Value:
+		return formatItem(data.Any())
+	case []any:
+		parts := make([]string, len(data))
+		for i, elem := range data {
+			parts[i] = formatItem(elem)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", item)
+}
+
+type objectIterator struct {
+	object *Object
+	keys   []string
+	pos    int
+}
+
+func (it *objectIterator) Next() (*Value, *Value, bool) {
+	for it.pos < len(it.keys) {
+		key := it.keys[it.pos]
+		it.pos++
+		// Ключ мог быть удален в теле цикла
+		if item, exists := it.object.fields[key]; exists {
+			return NewValue(key), item, true
+		}
+	}
+	return nil, nil, false
+}
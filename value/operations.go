@@ -2,6 +2,8 @@ package value
 
 import (
 	"fmt"
+	"math/big"
+	"reflect"
 )
 
 // FromInterface создает Value из interface{}
@@ -9,12 +11,16 @@ func FromInterface(data interface{}) *Value {
 	return NewValue(data)
 }
 
-// Арифметические операции
-func Add(a, b *Value) *Value {
+// Арифметические операции. checkOverflow - проверка переполнения int
+// (настройка Runtime, см. AddInt)
+func Add(a, b *Value, checkOverflow bool) *Value {
+	if IsBigNumber(a) || IsBigNumber(b) {
+		return bigArith("+", "add", a, b)
+	}
 	switch aVal := a.Any().(type) {
 	case int64:
 		if bVal, ok := b.Any().(int64); ok {
-			return NewInt64(aVal + bVal)
+			return NewInt64(AddInt(aVal, bVal, checkOverflow))
 		}
 		if bVal, ok := b.Any().(float64); ok {
 			return NewFloat64(float64(aVal) + bVal)
@@ -34,11 +40,14 @@ func Add(a, b *Value) *Value {
 	return NewString(fmt.Sprintf("Error: cannot add %T and %T", a.Any(), b.Any()))
 }
 
-func Subtract(a, b *Value) *Value {
+func Subtract(a, b *Value, checkOverflow bool) *Value {
+	if IsBigNumber(a) || IsBigNumber(b) {
+		return bigArith("-", "subtract", a, b)
+	}
 	switch aVal := a.Any().(type) {
 	case int64:
 		if bVal, ok := b.Any().(int64); ok {
-			return NewInt64(aVal - bVal)
+			return NewInt64(SubInt(aVal, bVal, checkOverflow))
 		}
 		if bVal, ok := b.Any().(float64); ok {
 			return NewFloat64(float64(aVal) - bVal)
@@ -54,11 +63,14 @@ func Subtract(a, b *Value) *Value {
 	return NewString(fmt.Sprintf("Error: cannot subtract %T and %T", a.Any(), b.Any()))
 }
 
-func Multiply(a, b *Value) *Value {
+func Multiply(a, b *Value, checkOverflow bool) *Value {
+	if IsBigNumber(a) || IsBigNumber(b) {
+		return bigArith("*", "multiply", a, b)
+	}
 	switch aVal := a.Any().(type) {
 	case int64:
 		if bVal, ok := b.Any().(int64); ok {
-			return NewInt64(aVal * bVal)
+			return NewInt64(MulInt(aVal, bVal, checkOverflow))
 		}
 		if bVal, ok := b.Any().(float64); ok {
 			return NewFloat64(float64(aVal) * bVal)
@@ -75,6 +87,9 @@ func Multiply(a, b *Value) *Value {
 }
 
 func Divide(a, b *Value) *Value {
+	if IsBigNumber(a) || IsBigNumber(b) {
+		return bigArith("/", "divide", a, b)
+	}
 	switch aVal := a.Any().(type) {
 	case int64:
 		if bVal, ok := b.Any().(int64); ok {
@@ -107,6 +122,9 @@ func Divide(a, b *Value) *Value {
 }
 
 func Modulo(a, b *Value) *Value {
+	if IsBigNumber(a) || IsBigNumber(b) {
+		return bigArith("%", "modulo", a, b)
+	}
 	switch aVal := a.Any().(type) {
 	case int64:
 		if bVal, ok := b.Any().(int64); ok {
@@ -119,16 +137,36 @@ func Modulo(a, b *Value) *Value {
 	return NewString(fmt.Sprintf("Error: cannot modulo %T and %T", a.Any(), b.Any()))
 }
 
-func Negate(a *Value) *Value {
+func Negate(a *Value, checkOverflow bool) *Value {
 	switch aVal := a.Any().(type) {
 	case int64:
-		return NewInt64(-aVal)
+		return NewInt64(NegInt(aVal, checkOverflow))
 	case float64:
 		return NewFloat64(-aVal)
+	case *big.Int:
+		return NewValue(new(big.Int).Neg(aVal))
+	case *Decimal:
+		return NewValue(aVal.Neg())
 	}
 	return NewString(fmt.Sprintf("Error: cannot negate %T", a.Any()))
 }
 
+// bigArith выполняет операцию над BigInt или Decimal; ошибки, как и у
+// остальных операций, возвращаются строкой "Error: ..."
+func bigArith(op, verb string, a, b *Value) *Value {
+	result, err := BigArith(op, a, b)
+	if err == errDivisionByZero {
+		if op == "%" {
+			return NewString("Error: modulo by zero")
+		}
+		return NewString("Error: division by zero")
+	}
+	if err != nil {
+		return NewString(fmt.Sprintf("Error: cannot %s %s and %s", verb, GetValueTypeName(a), GetValueTypeName(b)))
+	}
+	return result
+}
+
 // Логические операции
 func Not(a *Value) *Value {
 	return NewBool(!a.IsTruthy())
@@ -199,9 +237,9 @@ func Index(obj, index *Value) *Value {
 			}
 			return objVal[idxVal]
 		}
-	case map[string]*Value:
+	case *Object:
 		if idxVal, ok := index.Any().(string); ok {
-			if val, exists := objVal[idxVal]; exists {
+			if val, exists := objVal.Get(idxVal); exists {
 				return val
 			}
 			return NewNil()
@@ -218,7 +256,17 @@ func Index(obj, index *Value) *Value {
 }
 
 // Вспомогательные функции
+// Equatable - значение со своим структурным равенством (например, варианты enum с данными)
+type Equatable interface {
+	Equals(other any) bool
+}
+
 func isEqual(a, b interface{}) bool {
+	// 1n == 1 и decimal("0.5") == 0.5: числа сравниваются по значению
+	if isBigData(a) || isBigData(b) {
+		order, ok := compareBig(a, b)
+		return ok && order == 0
+	}
 	switch aVal := a.(type) {
 	case int64:
 		if bVal, ok := b.(int64); ok {
@@ -244,11 +292,97 @@ func isEqual(a, b interface{}) bool {
 		}
 	case nil:
 		return b == nil
+	case *Tuple:
+		if bVal, ok := b.(*Tuple); ok {
+			return aVal.Equal(bVal)
+		}
+	case *Set:
+		if bVal, ok := b.(*Set); ok {
+			return aVal.Equal(bVal)
+		}
+	case []any:
+		if bVal, ok := b.([]any); ok {
+			return equalItems(aVal, bVal)
+		}
+	case *Object:
+		if bVal, ok := b.(*Object); ok {
+			return aVal == bVal || equalObjects(aVal, bVal)
+		}
+	case *List:
+		if bVal, ok := b.(*List); ok {
+			return aVal == bVal || equalItems(aVal.Items(), bVal.Items())
+		}
+	case *Map:
+		if bVal, ok := b.(*Map); ok {
+			return aVal == bVal || equalMaps(aVal, bVal)
+		}
+	case Equatable:
+		return aVal.Equals(b)
+	default:
+		// Остальные значения (структуры, функции) равны только сами себе
+		if a != nil && reflect.TypeOf(a).Comparable() {
+			return a == b
+		}
 	}
 	return false
 }
 
+// equalItems сравнивает массивы поэлементно; один и тот же массив равен себе сразу
+func equalItems(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if len(a) > 0 && &a[0] == &b[0] {
+		return true
+	}
+	for i := range a {
+		if !isEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalObjects сравнивает объекты по полям, порядок полей не важен
+func equalObjects(a, b *Object) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for key, field := range a.fields {
+		other, exists := b.fields[key]
+		if !exists || !isEqual(field.Any(), other.Any()) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalMaps сравнивает неизменяемые словари по ключам и значениям
+func equalMaps(a, b *Map) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for _, key := range a.Keys() {
+		item, _ := a.Get(key)
+		other, exists := b.Get(key)
+		if !exists || !isEqual(item, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare сравнивает два числа или две строки и возвращает -1, 0 или 1
+func Compare(a, b *Value) (int, error) {
+	return compare(a.Any(), b.Any())
+}
+
 func compare(a, b interface{}) (int, error) {
+	if isBigData(a) || isBigData(b) {
+		if order, ok := compareBig(a, b); ok {
+			return order, nil
+		}
+	}
 	switch aVal := a.(type) {
 	case int64:
 		if bVal, ok := b.(int64); ok {
@@ -339,12 +473,14 @@ func (v *Value) IsTruthy() bool {
 		return val != 0
 	case float64:
 		return val != 0.0
+	case *big.Int, *Decimal:
+		return v.Bool()
 	case string:
 		return val != ""
 	case []*Value:
 		return len(val) > 0
-	case map[string]*Value:
-		return len(val) > 0
+	case *Object:
+		return val.Len() > 0
 	default:
 		return true
 	}
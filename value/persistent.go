@@ -0,0 +1,454 @@
+package value
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// List - персистентный вектор: каждая операция возвращает новый список, а
+// старый остается неизменным. Элементы хранятся в 32-арном дереве с хвостом,
+// поэтому push, set и pop копируют только путь от корня (O(log32 n)), а
+// остальные узлы разделяются между версиями. Неизменяемый список можно
+// передавать между горутинами без блокировок
+type List struct {
+	count int
+	shift uint
+	root  *listNode
+	tail  []any
+}
+
+type listNode struct {
+	children []any // *listNode во внутренних узлах, элементы в листьях
+}
+
+const (
+	listBits  = 5
+	listWidth = 1 << listBits
+	listMask  = listWidth - 1
+)
+
+// NewList создает список из элементов
+func NewList(items ...any) *List {
+	list := &List{shift: listBits, root: &listNode{}}
+	for _, item := range items {
+		list = list.Push(item)
+	}
+	return list
+}
+
+// Len возвращает количество элементов
+func (l *List) Len() int {
+	return l.count
+}
+
+// tailOffset - индекс первого элемента, хранящегося в хвосте
+func (l *List) tailOffset() int {
+	if l.count < listWidth {
+		return 0
+	}
+	return ((l.count - 1) >> listBits) << listBits
+}
+
+// leaf возвращает лист, содержащий элемент с индексом i
+func (l *List) leaf(i int) []any {
+	if i >= l.tailOffset() {
+		return l.tail
+	}
+	node := l.root
+	for level := l.shift; level > 0; level -= listBits {
+		node = node.children[(i>>level)&listMask].(*listNode)
+	}
+	return node.children
+}
+
+// Get возвращает элемент по индексу; ok = false за пределами списка
+func (l *List) Get(i int) (any, bool) {
+	if i < 0 || i >= l.count {
+		return nil, false
+	}
+	return l.leaf(i)[i&listMask], true
+}
+
+// Push возвращает список с элементом item в конце
+func (l *List) Push(item any) *List {
+	if l.count-l.tailOffset() < listWidth {
+		tail := make([]any, len(l.tail), len(l.tail)+1)
+		copy(tail, l.tail)
+		return &List{count: l.count + 1, shift: l.shift, root: l.root, tail: append(tail, item)}
+	}
+
+	// Хвост заполнен: переносим его в дерево
+	tailNode := &listNode{children: l.tail}
+	shift := l.shift
+	var root *listNode
+	if (l.count >> listBits) > (1 << l.shift) {
+		root = &listNode{children: []any{l.root, newListPath(l.shift, tailNode)}}
+		shift += listBits
+	} else {
+		root = l.pushTail(l.shift, l.root, tailNode)
+	}
+	return &List{count: l.count + 1, shift: shift, root: root, tail: []any{item}}
+}
+
+func (l *List) pushTail(level uint, parent, tailNode *listNode) *listNode {
+	index := ((l.count - 1) >> level) & listMask
+	node := parent.clone()
+
+	var child *listNode
+	if level == listBits {
+		child = tailNode
+	} else if index < len(parent.children) {
+		child = l.pushTail(level-listBits, parent.children[index].(*listNode), tailNode)
+	} else {
+		child = newListPath(level-listBits, tailNode)
+	}
+
+	if index < len(node.children) {
+		node.children[index] = child
+	} else {
+		node.children = append(node.children, child)
+	}
+	return node
+}
+
+func newListPath(level uint, node *listNode) *listNode {
+	if level == 0 {
+		return node
+	}
+	return &listNode{children: []any{newListPath(level-listBits, node)}}
+}
+
+func (n *listNode) clone() *listNode {
+	children := make([]any, len(n.children), len(n.children)+1)
+	copy(children, n.children)
+	return &listNode{children: children}
+}
+
+// Set возвращает список, в котором элемент с индексом i заменен на item
+func (l *List) Set(i int, item any) (*List, bool) {
+	if i < 0 || i >= l.count {
+		return nil, false
+	}
+
+	if i >= l.tailOffset() {
+		tail := make([]any, len(l.tail))
+		copy(tail, l.tail)
+		tail[i&listMask] = item
+		return &List{count: l.count, shift: l.shift, root: l.root, tail: tail}, true
+	}
+	return &List{count: l.count, shift: l.shift, root: assocList(l.shift, l.root, i, item), tail: l.tail}, true
+}
+
+func assocList(level uint, node *listNode, i int, item any) *listNode {
+	result := node.clone()
+	if level == 0 {
+		result.children[i&listMask] = item
+	} else {
+		index := (i >> level) & listMask
+		result.children[index] = assocList(level-listBits, node.children[index].(*listNode), i, item)
+	}
+	return result
+}
+
+// Pop возвращает список без последнего элемента
+func (l *List) Pop() (*List, bool) {
+	switch {
+	case l.count == 0:
+		return nil, false
+	case l.count == 1:
+		return NewList(), true
+	case l.count-l.tailOffset() > 1:
+		tail := make([]any, len(l.tail)-1)
+		copy(tail, l.tail)
+		return &List{count: l.count - 1, shift: l.shift, root: l.root, tail: tail}, true
+	}
+
+	// В хвосте один элемент: новым хвостом становится последний лист дерева
+	tail := l.leaf(l.count - 2)
+	root := l.popTail(l.shift, l.root)
+	shift := l.shift
+	if root == nil {
+		root = &listNode{}
+	}
+	if shift > listBits && len(root.children) == 1 {
+		root = root.children[0].(*listNode)
+		shift -= listBits
+	}
+	return &List{count: l.count - 1, shift: shift, root: root, tail: tail}, true
+}
+
+func (l *List) popTail(level uint, node *listNode) *listNode {
+	index := ((l.count - 2) >> level) & listMask
+	if level > listBits {
+		child := l.popTail(level-listBits, node.children[index].(*listNode))
+		if child == nil && index == 0 {
+			return nil
+		}
+		result := node.clone()
+		if child == nil {
+			result.children = result.children[:index]
+		} else {
+			result.children[index] = child
+		}
+		return result
+	}
+	if index == 0 {
+		return nil
+	}
+	result := node.clone()
+	result.children = result.children[:index]
+	return result
+}
+
+// Items возвращает элементы списка в новом срезе
+func (l *List) Items() []any {
+	items := make([]any, 0, l.count)
+	for i := 0; i < l.count; i += listWidth {
+		items = append(items, l.leaf(i)...)
+	}
+	return items
+}
+
+func (l *List) Iter() Iterator {
+	return &sliceIterator{items: l.Items(), frozen: true}
+}
+
+func (l *List) String() string {
+	parts := make([]string, l.count)
+	for i, item := range l.Items() {
+		parts[i] = fmt.Sprintf("%v", item)
+	}
+	return "List[" + strings.Join(parts, ", ") + "]"
+}
+
+// Map - персистентный словарь со строковыми ключами (hash array mapped trie):
+// set и delete возвращают новый словарь, копируя только узлы на пути к ключу.
+// Обход идет по ключам в алфавитном порядке, как у объектов
+type Map struct {
+	count int
+	root  *mapNode
+}
+
+// mapNode - узел дерева: bitmap отмечает занятые из 32 позиций, entries хранит
+// их по порядку. Когда биты хеша исчерпаны, узел хранит коллизии списком
+type mapNode struct {
+	bitmap    uint32
+	entries   []mapEntry
+	collision bool
+}
+
+type mapEntry struct {
+	key   string
+	item  any
+	child *mapNode
+}
+
+// NewMap создает пустой словарь
+func NewMap() *Map {
+	return &Map{root: &mapNode{}}
+}
+
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// Len возвращает количество ключей
+func (m *Map) Len() int {
+	return m.count
+}
+
+// Get возвращает значение по ключу
+func (m *Map) Get(key string) (any, bool) {
+	return m.root.get(hashKey(key), 0, key)
+}
+
+// Set возвращает словарь, в котором ключу key соответствует item
+func (m *Map) Set(key string, item any) *Map {
+	root, added := m.root.set(hashKey(key), 0, key, item)
+	count := m.count
+	if added {
+		count++
+	}
+	return &Map{count: count, root: root}
+}
+
+// Delete возвращает словарь без ключа key
+func (m *Map) Delete(key string) *Map {
+	root, removed := m.root.delete(hashKey(key), 0, key)
+	if !removed {
+		return m
+	}
+	return &Map{count: m.count - 1, root: root}
+}
+
+// Keys возвращает ключи в алфавитном порядке
+func (m *Map) Keys() []string {
+	keys := make([]string, 0, m.count)
+	m.root.each(func(key string, _ any) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+func (m *Map) Iter() Iterator {
+	return &persistentMapIterator{m: m, keys: m.Keys()}
+}
+
+func (m *Map) String() string {
+	keys := m.Keys()
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		item, _ := m.Get(key)
+		parts[i] = fmt.Sprintf("%s: %v", key, item)
+	}
+	return "Map{" + strings.Join(parts, ", ") + "}"
+}
+
+type persistentMapIterator struct {
+	m    *Map
+	keys []string
+	pos  int
+}
+
+func (it *persistentMapIterator) Next() (*Value, *Value, bool) {
+	if it.pos >= len(it.keys) {
+		return nil, nil, false
+	}
+	key := it.keys[it.pos]
+	it.pos++
+	item, _ := it.m.Get(key)
+	return NewValue(key), &Value{data: item, isFrozen: true}, true
+}
+
+// slot возвращает бит позиции хеша на уровне shift и индекс записи в entries
+func (n *mapNode) slot(hash uint32, shift uint) (uint32, int) {
+	bit := uint32(1) << ((hash >> shift) & listMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *mapNode) get(hash uint32, shift uint, key string) (any, bool) {
+	if n.collision {
+		for _, entry := range n.entries {
+			if entry.key == key {
+				return entry.item, true
+			}
+		}
+		return nil, false
+	}
+
+	bit, index := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return nil, false
+	}
+	entry := n.entries[index]
+	if entry.child != nil {
+		return entry.child.get(hash, shift+listBits, key)
+	}
+	if entry.key == key {
+		return entry.item, true
+	}
+	return nil, false
+}
+
+func (n *mapNode) set(hash uint32, shift uint, key string, item any) (*mapNode, bool) {
+	if n.collision || shift >= 32 {
+		return n.setCollision(key, item)
+	}
+
+	bit, index := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		entries := make([]mapEntry, 0, len(n.entries)+1)
+		entries = append(entries, n.entries[:index]...)
+		entries = append(entries, mapEntry{key: key, item: item})
+		entries = append(entries, n.entries[index:]...)
+		return &mapNode{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+
+	entries := make([]mapEntry, len(n.entries))
+	copy(entries, n.entries)
+	entry := entries[index]
+
+	added := false
+	switch {
+	case entry.child != nil:
+		entries[index].child, added = entry.child.set(hash, shift+listBits, key, item)
+	case entry.key == key:
+		entries[index].item = item
+	default:
+		// Два ключа в одной позиции: спускаем оба уровнем ниже
+		child, _ := (&mapNode{}).set(hashKey(entry.key), shift+listBits, entry.key, entry.item)
+		child, _ = child.set(hash, shift+listBits, key, item)
+		entries[index] = mapEntry{child: child}
+		added = true
+	}
+	return &mapNode{bitmap: n.bitmap, entries: entries}, added
+}
+
+func (n *mapNode) setCollision(key string, item any) (*mapNode, bool) {
+	entries := make([]mapEntry, len(n.entries), len(n.entries)+1)
+	copy(entries, n.entries)
+	for i, entry := range entries {
+		if entry.key == key {
+			entries[i].item = item
+			return &mapNode{entries: entries, collision: true}, false
+		}
+	}
+	return &mapNode{entries: append(entries, mapEntry{key: key, item: item}), collision: true}, true
+}
+
+func (n *mapNode) delete(hash uint32, shift uint, key string) (*mapNode, bool) {
+	if n.collision {
+		for i, entry := range n.entries {
+			if entry.key == key {
+				entries := make([]mapEntry, 0, len(n.entries)-1)
+				entries = append(entries, n.entries[:i]...)
+				entries = append(entries, n.entries[i+1:]...)
+				return &mapNode{entries: entries, collision: true}, true
+			}
+		}
+		return n, false
+	}
+
+	bit, index := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	entry := n.entries[index]
+	if entry.child != nil {
+		child, removed := entry.child.delete(hash, shift+listBits, key)
+		if !removed {
+			return n, false
+		}
+		if len(child.entries) > 0 {
+			entries := make([]mapEntry, len(n.entries))
+			copy(entries, n.entries)
+			entries[index].child = child
+			return &mapNode{bitmap: n.bitmap, entries: entries}, true
+		}
+	} else if entry.key != key {
+		return n, false
+	}
+
+	// Удаляем запись (или опустевший дочерний узел) из этого узла
+	entries := make([]mapEntry, 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:index]...)
+	entries = append(entries, n.entries[index+1:]...)
+	return &mapNode{bitmap: n.bitmap &^ bit, entries: entries}, true
+}
+
+func (n *mapNode) each(fn func(key string, item any)) {
+	for _, entry := range n.entries {
+		if entry.child != nil {
+			entry.child.each(fn)
+		} else {
+			fn(entry.key, entry.item)
+		}
+	}
+}
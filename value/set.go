@@ -0,0 +1,186 @@
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Set - множество значений в порядке добавления. Элементами могут быть числа,
+// строки, bool, null и кортежи из них; 1 и 1.0 - один и тот же элемент, как и
+// в сравнении ==
+type Set struct {
+	items []any
+	index map[string]int // ключ элемента -> позиция в items
+}
+
+// NewSet создает пустое множество
+func NewSet() *Set {
+	return &Set{index: make(map[string]int)}
+}
+
+// SetKey возвращает ключ элемента множества; ok = false, если значение нельзя
+// положить в множество (объекты, массивы, функции)
+func SetKey(item any) (string, bool) {
+	switch data := item.(type) {
+	case *Value:
+		return SetKey(data.Any())
+	case nil:
+		return "n", true
+	case bool:
+		return "b" + strconv.FormatBool(data), true
+	case int64:
+		return "i" + strconv.FormatInt(data, 10), true
+	case float64:
+		if data == math.Trunc(data) && math.Abs(data) < 1<<63 {
+			return "i" + strconv.FormatInt(int64(data), 10), true
+		}
+		return "f" + strconv.FormatFloat(data, 'g', -1, 64), true
+	case string:
+		return "s" + strconv.Quote(data), true
+	case *big.Int:
+		return "i" + data.String(), true
+	case *Decimal:
+		if data.IsInteger() {
+			return "i" + data.BigInt().String(), true
+		}
+		// Decimal, точно равный float, - тот же элемент, что и float
+		if f := data.Float64(); compareDecimalFloat(data, f) {
+			return SetKey(f)
+		}
+		return "d" + data.trim().String(), true
+	case *Tuple:
+		parts := make([]string, len(data.items))
+		for i, elem := range data.items {
+			key, ok := SetKey(elem)
+			if !ok {
+				return "", false
+			}
+			parts[i] = key
+		}
+		return "t(" + strings.Join(parts, ",") + ")", true
+	}
+	return "", false
+}
+
+// Add добавляет элемент; повторное добавление ничего не меняет
+func (s *Set) Add(item any) error {
+	key, ok := SetKey(item)
+	if !ok {
+		return fmt.Errorf("set element must be a number, string, bool, null or tuple, got %s", GetValueTypeName(NewValue(item)))
+	}
+	if _, exists := s.index[key]; !exists {
+		s.index[key] = len(s.items)
+		s.items = append(s.items, item)
+	}
+	return nil
+}
+
+// Has сообщает, есть ли элемент в множестве
+func (s *Set) Has(item any) bool {
+	key, ok := SetKey(item)
+	if !ok {
+		return false
+	}
+	_, exists := s.index[key]
+	return exists
+}
+
+// Remove удаляет элемент и сообщает, был ли он
+func (s *Set) Remove(item any) bool {
+	key, ok := SetKey(item)
+	if !ok {
+		return false
+	}
+	pos, exists := s.index[key]
+	if !exists {
+		return false
+	}
+	s.items = append(s.items[:pos:pos], s.items[pos+1:]...)
+	delete(s.index, key)
+	for k, i := range s.index {
+		if i > pos {
+			s.index[k] = i - 1
+		}
+	}
+	return true
+}
+
+// Len возвращает количество элементов
+func (s *Set) Len() int {
+	return len(s.items)
+}
+
+// Items возвращает элементы в порядке добавления
+func (s *Set) Items() []any {
+	items := make([]any, len(s.items))
+	copy(items, s.items)
+	return items
+}
+
+// Union возвращает множество элементов из s или other
+func (s *Set) Union(other *Set) *Set {
+	result := NewSet()
+	for _, item := range s.items {
+		result.Add(item)
+	}
+	for _, item := range other.items {
+		result.Add(item)
+	}
+	return result
+}
+
+// Intersection возвращает множество элементов, которые есть и в s, и в other
+func (s *Set) Intersection(other *Set) *Set {
+	result := NewSet()
+	for _, item := range s.items {
+		if other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// Difference возвращает множество элементов s, которых нет в other
+func (s *Set) Difference(other *Set) *Set {
+	result := NewSet()
+	for _, item := range s.items {
+		if !other.Has(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// Equal сообщает, состоят ли множества из одних и тех же элементов
+func (s *Set) Equal(other *Set) bool {
+	if len(s.items) != len(other.items) {
+		return false
+	}
+	for key := range s.index {
+		if _, exists := other.index[key]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON кодирует множество как JSON массив в порядке добавления
+func (s *Set) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.items)
+}
+
+func (s *Set) Iter() Iterator {
+	return &sliceIterator{items: s.Items()}
+}
+
+func (s *Set) String() string {
+	parts := make([]string, len(s.items))
+	for i, item := range s.items {
+		parts[i] = formatItem(item)
+	}
+	return "#{" + strings.Join(parts, ", ") + "}"
+}
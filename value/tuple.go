@@ -0,0 +1,70 @@
+package value
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Tuple - кортеж фиксированной длины: (1, "a", true). В отличие от массива
+// кортеж неизменяем и сравнивается по элементам
+type Tuple struct {
+	items []any
+}
+
+// NewTuple создает кортеж из элементов
+func NewTuple(items ...any) *Tuple {
+	return &Tuple{items: items}
+}
+
+// Len возвращает количество элементов
+func (t *Tuple) Len() int {
+	return len(t.items)
+}
+
+// Get возвращает элемент по индексу; ok = false за пределами кортежа
+func (t *Tuple) Get(i int) (any, bool) {
+	if i < 0 || i >= len(t.items) {
+		return nil, false
+	}
+	return t.items[i], true
+}
+
+// Items возвращает элементы в новом срезе
+func (t *Tuple) Items() []any {
+	items := make([]any, len(t.items))
+	copy(items, t.items)
+	return items
+}
+
+// Equal сообщает, равны ли кортежи поэлементно
+func (t *Tuple) Equal(other *Tuple) bool {
+	if len(t.items) != len(other.items) {
+		return false
+	}
+	for i := range t.items {
+		if !isEqual(t.items[i], other.items[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON кодирует кортеж как JSON массив
+func (t *Tuple) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.items)
+}
+
+func (t *Tuple) Iter() Iterator {
+	return &sliceIterator{items: t.Items()}
+}
+
+func (t *Tuple) String() string {
+	parts := make([]string, len(t.items))
+	for i, item := range t.items {
+		parts[i] = formatItem(item)
+	}
+	if len(parts) == 1 {
+		return "(" + parts[0] + ",)"
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
@@ -2,16 +2,21 @@ package value
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"sync"
 	"time"
 )
 
 type Value struct {
-	isConst  bool
-	isReturn bool
-	isYield  bool
-	isBreak  bool
-	data     any
+	isConst    bool
+	isFrozen   bool // значение (объект, массив) нельзя изменять, см. ast.Freeze
+	isReturn   bool
+	isYield    bool
+	isBreak    bool
+	isContinue bool
+	label      string // метка цикла для break/continue (пустая - ближайший цикл)
+	data       any
 }
 
 type Number interface {
@@ -19,6 +24,10 @@ type Number interface {
 }
 
 func NewValue(data any, isConst ...bool) *Value {
+	// Словари полей (builtin-объекты, модули) становятся объектами языка
+	if fields, ok := data.(map[string]*Value); ok {
+		data = NewObjectFromMap(fields)
+	}
 	return &Value{
 		data: data,
 		isConst: func() bool {
@@ -56,6 +65,10 @@ func (n *Value) Bool() bool {
 		return v != 0
 	case string:
 		return v != ""
+	case *big.Int:
+		return v.Sign() != 0
+	case *Decimal:
+		return v.Sign() != 0
 	default:
 		return v != nil
 	}
@@ -132,6 +145,10 @@ func (n *Value) String() string {
 		return v
 	case bool:
 		return strconv.FormatBool(v)
+	case *big.Int:
+		return v.String()
+	case *Decimal:
+		return v.String()
 	}
 	return ""
 }
@@ -158,6 +175,10 @@ func (n *Value) Int() int {
 		return int(v)
 	case uint64:
 		return int(v)
+	case *big.Int:
+		return int(v.Int64())
+	case *Decimal:
+		return int(v.BigInt().Int64())
 	case string:
 		x, _ := strconv.ParseInt(v, 10, 64)
 		return int(x)
@@ -192,6 +213,10 @@ func (n *Value) Int64() int64 {
 		return int64(v)
 	case uint64:
 		return int64(v)
+	case *big.Int:
+		return v.Int64()
+	case *Decimal:
+		return v.BigInt().Int64()
 	case string:
 		x, _ := strconv.ParseInt(v, 10, 64)
 		return int64(x)
@@ -226,6 +251,11 @@ func (n *Value) Float64() float64 {
 		return float64(v)
 	case uint64:
 		return float64(v)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	case *Decimal:
+		return v.Float64()
 	case string:
 		x, _ := strconv.ParseFloat(v, 64)
 		return float64(x)
@@ -272,6 +302,12 @@ func (n *Value) Float32() float32 {
 	return 0
 }
 
+// Replace копирует содержимое other в n: все, кто держит n, видят новое
+// значение (подмена экспортов при горячей перезагрузке модулей)
+func (n *Value) Replace(other *Value) {
+	*n = *other
+}
+
 func (n *Value) Any() any {
 	if n == nil {
 		return nil
@@ -287,6 +323,16 @@ func (n *Value) SetConst(constant bool) {
 	n.isConst = constant
 }
 
+// IsFrozen сообщает, заморожено ли значение: поля замороженного объекта
+// нельзя присваивать, а элементы читаются тоже замороженными
+func (n *Value) IsFrozen() bool {
+	return n != nil && n.isFrozen
+}
+
+func (n *Value) SetFrozen(frozen bool) {
+	n.isFrozen = frozen
+}
+
 
 func (n *Value) SetYield(yield bool) {
 	n.isYield = yield
@@ -304,25 +350,75 @@ func (n *Value) IsBreak() bool {
 	return n.isBreak
 }
 
-// Extension methods registry
-var extensionMethods = make(map[string]map[string]interface{})
+func (n *Value) SetContinue(cont bool) {
+	n.isContinue = cont
+}
+
+func (n *Value) IsContinue() bool {
+	return n.isContinue
+}
 
-// RegisterExtensionMethod регистрирует метод расширения для типа
-func RegisterExtensionMethod(typeName string, methodName string, method interface{}) {
-	if extensionMethods[typeName] == nil {
-		extensionMethods[typeName] = make(map[string]interface{})
+// SetLabel задает метку цикла, которому адресованы break или continue
+func (n *Value) SetLabel(label string) {
+	n.label = label
+}
+
+func (n *Value) Label() string {
+	return n.label
+}
+
+// ExtensionRegistry - реестр методов расширения: [typeName][methodName] = method.
+// У каждого интерпретатора свой реестр для extension-блоков скрипта,
+// встроенные методы (System, IO, ...) хранятся в общем builtinExtensions
+type ExtensionRegistry struct {
+	mu      sync.RWMutex
+	methods map[string]map[string]interface{}
+}
+
+// NewExtensionRegistry создает пустой реестр методов расширения
+func NewExtensionRegistry() *ExtensionRegistry {
+	return &ExtensionRegistry{
+		methods: make(map[string]map[string]interface{}),
 	}
-	extensionMethods[typeName][methodName] = method
 }
 
-// GetExtensionMethod возвращает метод расширения для типа
-func GetExtensionMethod(typeName string, methodName string) (interface{}, bool) {
-	if methods, ok := extensionMethods[typeName]; ok {
-		if method, ok := methods[methodName]; ok {
-			return method, true
-		}
+// Register регистрирует метод расширения для типа
+func (r *ExtensionRegistry) Register(typeName string, methodName string, method interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.methods[typeName] == nil {
+		r.methods[typeName] = make(map[string]interface{})
+	}
+	r.methods[typeName][methodName] = method
+}
+
+// Get возвращает метод расширения для типа.
+// Если метод не найден в реестре, ищет среди встроенных методов расширения
+func (r *ExtensionRegistry) Get(typeName string, methodName string) (interface{}, bool) {
+	r.mu.RLock()
+	method, ok := r.methods[typeName][methodName]
+	r.mu.RUnlock()
+
+	if ok || r == builtinExtensions {
+		return method, ok
 	}
-	return nil, false
+
+	return builtinExtensions.Get(typeName, methodName)
+}
+
+// builtinExtensions - методы расширения встроенных объектов, общие для всех интерпретаторов
+// процесса: RegisterExtensionMethod влияет на каждый из них
+var builtinExtensions = NewExtensionRegistry()
+
+// RegisterExtensionMethod регистрирует встроенный метод расширения для типа
+func RegisterExtensionMethod(typeName string, methodName string, method interface{}) {
+	builtinExtensions.Register(typeName, methodName, method)
+}
+
+// GetExtensionMethod возвращает встроенный метод расширения для типа
+func GetExtensionMethod(typeName string, methodName string) (interface{}, bool) {
+	return builtinExtensions.Get(typeName, methodName)
 }
 
 // GetValueTypeName возвращает имя типа для значения
@@ -342,10 +438,22 @@ func GetValueTypeName(v *Value) string {
 		return "bool"
 	case []interface{}:
 		return "array"
-	case map[string]interface{}:
+	case map[string]interface{}, *Object:
 		return "object"
+	case *Set:
+		return "set"
+	case *Tuple:
+		return "tuple"
+	case *big.Int:
+		return "bigint"
+	case *Decimal:
+		return "decimal"
 	case *Channel:
 		return "channel"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
 	case time.Time:
 		return "time"
 	default: